@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
@@ -9,10 +10,25 @@ import (
 type (
 	// Config -.
 	Config struct {
-		App  `yaml:"app"`
-		HTTP `yaml:"http"`
-		Log  `yaml:"logger"`
-		PG   `yaml:"postgres"`
+		App                `yaml:"app"`
+		HTTP               `yaml:"http"`
+		Log                `yaml:"logger"`
+		PG                 `yaml:"postgres"`
+		Allowance          `yaml:"allowance"`
+		Rewards            `yaml:"rewards"`
+		CoinRequests       `yaml:"coin_requests"`
+		ScheduledTransfers `yaml:"scheduled_transfers"`
+		TransferLimits     `yaml:"transfer_limits"`
+		TransferApprovals  `yaml:"transfer_approvals"`
+		Fraud              `yaml:"fraud"`
+		CoinExpiry         `yaml:"coin_expiry"`
+		Marketplace        `yaml:"marketplace"`
+		Auctions           `yaml:"auctions"`
+		Raffles            `yaml:"raffles"`
+		Wishlists          `yaml:"wishlists"`
+		Preorders          `yaml:"preorders"`
+		Recommendations    `yaml:"recommendations"`
+		Localization       `yaml:"localization"`
 	}
 
 	// App -.
@@ -36,6 +52,103 @@ type (
 		PoolMax int    `env-required:"true" yaml:"pool_max" env:"PG_POOL_MAX"`
 		URL     string `env-required:"true"                 env:"PG_URL"`
 	}
+
+	// Allowance -.
+	Allowance struct {
+		Amount   int64         `env-required:"true" yaml:"amount"   env:"ALLOWANCE_AMOUNT"`
+		Interval time.Duration `env-required:"true" yaml:"interval" env:"ALLOWANCE_INTERVAL"`
+	}
+
+	// Rewards -.
+	Rewards struct {
+		MaxAward      int64         `env-required:"true" yaml:"max_award"      env:"REWARDS_MAX_AWARD"`
+		ResetInterval time.Duration `env-required:"true" yaml:"reset_interval" env:"REWARDS_RESET_INTERVAL"`
+	}
+
+	// CoinRequests -.
+	CoinRequests struct {
+		TTL            time.Duration `env-required:"true" yaml:"ttl"             env:"COIN_REQUESTS_TTL"`
+		ExpireInterval time.Duration `env-required:"true" yaml:"expire_interval" env:"COIN_REQUESTS_EXPIRE_INTERVAL"`
+	}
+
+	// ScheduledTransfers -.
+	ScheduledTransfers struct {
+		Interval time.Duration `env-required:"true" yaml:"interval" env:"SCHEDULED_TRANSFERS_INTERVAL"`
+	}
+
+	// TransferLimits -. Zero disables a limit.
+	TransferLimits struct {
+		PerTransfer  int64 `yaml:"per_transfer" env:"TRANSFER_LIMITS_PER_TRANSFER"`
+		Daily        int64 `yaml:"daily" env:"TRANSFER_LIMITS_DAILY"`
+		PerRecipient int64 `yaml:"per_recipient" env:"TRANSFER_LIMITS_PER_RECIPIENT"`
+	}
+
+	// TransferApprovals -. Zero threshold disables approvals.
+	TransferApprovals struct {
+		Threshold      int64         `yaml:"threshold" env:"TRANSFER_APPROVALS_THRESHOLD"`
+		TTL            time.Duration `env-required:"true" yaml:"ttl" env:"TRANSFER_APPROVALS_TTL"`
+		ExpireInterval time.Duration `env-required:"true" yaml:"expire_interval" env:"TRANSFER_APPROVALS_EXPIRE_INTERVAL"`
+	}
+
+	// Fraud -. A zero threshold disables the corresponding rule.
+	Fraud struct {
+		Lookback          time.Duration `env-required:"true" yaml:"lookback" env:"FRAUD_LOOKBACK"`
+		VelocityWindow    time.Duration `yaml:"velocity_window" env:"FRAUD_VELOCITY_WINDOW"`
+		VelocityFlagAt    int           `yaml:"velocity_flag_at" env:"FRAUD_VELOCITY_FLAG_AT"`
+		VelocityHoldAt    int           `yaml:"velocity_hold_at" env:"FRAUD_VELOCITY_HOLD_AT"`
+		RingWindow        time.Duration `yaml:"ring_window" env:"FRAUD_RING_WINDOW"`
+		MaxRingSize       int           `yaml:"max_ring_size" env:"FRAUD_MAX_RING_SIZE"`
+		NewAccountAge     time.Duration `yaml:"new_account_age" env:"FRAUD_NEW_ACCOUNT_AGE"`
+		DrainPercent      int64         `yaml:"drain_percent" env:"FRAUD_DRAIN_PERCENT"`
+		UnusualFactor     int64         `yaml:"unusual_factor" env:"FRAUD_UNUSUAL_FACTOR"`
+		UnusualMinSamples int           `yaml:"unusual_min_samples" env:"FRAUD_UNUSUAL_MIN_SAMPLES"`
+	}
+
+	// CoinExpiry -. Zero ttl means granted coins never expire.
+	CoinExpiry struct {
+		TTL           time.Duration `yaml:"ttl" env:"COIN_EXPIRY_TTL"`
+		SweepInterval time.Duration `env-required:"true" yaml:"sweep_interval" env:"COIN_EXPIRY_SWEEP_INTERVAL"`
+	}
+
+	// Marketplace -. An empty fee account burns the fees; zero listing ttl
+	// keeps listings up until sold or cancelled.
+	Marketplace struct {
+		FeePercent     int64         `yaml:"fee_percent" env:"MARKETPLACE_FEE_PERCENT"`
+		FeeAccount     string        `yaml:"fee_account" env:"MARKETPLACE_FEE_ACCOUNT"`
+		ListingTTL     time.Duration `yaml:"listing_ttl" env:"MARKETPLACE_LISTING_TTL"`
+		ExpireInterval time.Duration `env-required:"true" yaml:"expire_interval" env:"MARKETPLACE_EXPIRE_INTERVAL"`
+	}
+
+	// Auctions -.
+	Auctions struct {
+		CloseInterval time.Duration `env-required:"true" yaml:"close_interval" env:"AUCTIONS_CLOSE_INTERVAL"`
+	}
+
+	// Raffles -.
+	Raffles struct {
+		DrawInterval time.Duration `env-required:"true" yaml:"draw_interval" env:"RAFFLES_DRAW_INTERVAL"`
+	}
+
+	// Wishlists -.
+	Wishlists struct {
+		CheckInterval time.Duration `env-required:"true" yaml:"check_interval" env:"WISHLISTS_CHECK_INTERVAL"`
+	}
+
+	// Preorders -.
+	Preorders struct {
+		ReleaseInterval time.Duration `env-required:"true" yaml:"release_interval" env:"PREORDERS_RELEASE_INTERVAL"`
+	}
+
+	// Recommendations -.
+	Recommendations struct {
+		RefreshInterval time.Duration `env-required:"true" yaml:"refresh_interval" env:"RECOMMENDATIONS_REFRESH_INTERVAL"`
+	}
+
+	// Localization -.
+	Localization struct {
+		CatalogLocale string   `env-required:"true" yaml:"catalog_locale" env:"LOCALIZATION_CATALOG_LOCALE"`
+		Locales       []string `env-required:"true" yaml:"locales" env:"LOCALIZATION_LOCALES" env-separator:","`
+	}
 )
 
 // NewConfig returns app config.
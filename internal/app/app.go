@@ -16,6 +16,7 @@ import (
 	"syscall"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
 )
 
 // Run creates objects via constructors.
@@ -29,12 +30,22 @@ func Run(cfg *config.Config) {
 	}
 	defer pg.Close()
 
+	db, err := sqlx.Connect("postgres", cfg.PG.URL)
+	if err != nil {
+		l.Fatal(fmt.Errorf("app - Run - sqlx.Connect: %w", err))
+	}
+	db.SetMaxOpenConns(cfg.PG.PoolMax)
+	defer db.Close()
+
 	// Use case
 	translationUseCase := usecase.New(
 		repository.New(pg),
 		webapi.New(),
 	)
 
+	// Background jobs
+	jobs := runJobs(cfg, l, db)
+
 	// HTTP Server
 	handler := gin.New()
 	v1.NewRouter(handler, l, translationUseCase)
@@ -58,4 +69,7 @@ func Run(cfg *config.Config) {
 		l.Error(fmt.Errorf("app - Run - httpServer.Shutdown: %w", err))
 	}
 
+	for _, s := range jobs {
+		s.Shutdown()
+	}
 }
@@ -0,0 +1,232 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/smthjapanese/avito-merch/config"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/repository"
+	"github.com/smthjapanese/avito-merch/internal/repository/allowance_repository"
+	"github.com/smthjapanese/avito-merch/internal/repository/auction_repository"
+	"github.com/smthjapanese/avito-merch/internal/repository/coin_lot_repository"
+	"github.com/smthjapanese/avito-merch/internal/repository/coin_request_repository"
+	"github.com/smthjapanese/avito-merch/internal/repository/fraud_flag_repository"
+	"github.com/smthjapanese/avito-merch/internal/repository/inventory_repository"
+	"github.com/smthjapanese/avito-merch/internal/repository/market_repository"
+	"github.com/smthjapanese/avito-merch/internal/repository/merch_repository"
+	"github.com/smthjapanese/avito-merch/internal/repository/notification_repository"
+	"github.com/smthjapanese/avito-merch/internal/repository/order_repository"
+	"github.com/smthjapanese/avito-merch/internal/repository/pending_transfer_repository"
+	"github.com/smthjapanese/avito-merch/internal/repository/preorder_repository"
+	"github.com/smthjapanese/avito-merch/internal/repository/promo_repository"
+	"github.com/smthjapanese/avito-merch/internal/repository/raffle_repository"
+	"github.com/smthjapanese/avito-merch/internal/repository/recommendation_repository"
+	"github.com/smthjapanese/avito-merch/internal/repository/reward_repository"
+	"github.com/smthjapanese/avito-merch/internal/repository/scheduled_transfer_repository"
+	"github.com/smthjapanese/avito-merch/internal/repository/team_repository"
+	"github.com/smthjapanese/avito-merch/internal/repository/transaction_repository"
+	"github.com/smthjapanese/avito-merch/internal/repository/transfer_limit_repository"
+	"github.com/smthjapanese/avito-merch/internal/repository/user_repository"
+	"github.com/smthjapanese/avito-merch/internal/repository/wishlist_repository"
+	"github.com/smthjapanese/avito-merch/internal/usecase/auction_usecase"
+	"github.com/smthjapanese/avito-merch/internal/usecase/coin_expiry_usecase"
+	"github.com/smthjapanese/avito-merch/internal/usecase/coin_request_usecase"
+	"github.com/smthjapanese/avito-merch/internal/usecase/fraud_usecase"
+	"github.com/smthjapanese/avito-merch/internal/usecase/grant_usecase"
+	"github.com/smthjapanese/avito-merch/internal/usecase/market_usecase"
+	"github.com/smthjapanese/avito-merch/internal/usecase/preorder_usecase"
+	"github.com/smthjapanese/avito-merch/internal/usecase/raffle_usecase"
+	"github.com/smthjapanese/avito-merch/internal/usecase/recommendation_usecase"
+	"github.com/smthjapanese/avito-merch/internal/usecase/reward_usecase"
+	"github.com/smthjapanese/avito-merch/internal/usecase/scheduled_transfer_usecase"
+	"github.com/smthjapanese/avito-merch/internal/usecase/transaction_usecase"
+	"github.com/smthjapanese/avito-merch/internal/usecase/wishlist_usecase"
+	"github.com/smthjapanese/avito-merch/pkg/logger"
+	"github.com/smthjapanese/avito-merch/pkg/scheduler"
+)
+
+// runJobs starts a scheduler for every background job. The caller shuts
+// them down.
+func runJobs(cfg *config.Config, l *logger.Logger, db *sqlx.DB) []*scheduler.Scheduler {
+	// Repository
+	dbTx := repository.NewTransactor(db)
+	userRepo := user_repository.NewUserRepository(db)
+	teamRepo := team_repository.NewTeamRepository(db)
+	txRepo := transaction_repository.NewTransactionRepository(db)
+	lotRepo := coin_lot_repository.NewCoinLotRepository(db)
+	merchRepo := merch_repository.NewMerchRepository(db)
+	invRepo := inventory_repository.NewInventoryRepository(db)
+	orderRepo := order_repository.NewOrderRepository(db)
+	notifier := notification_repository.NewNotificationRepository(db)
+
+	// Use case
+	fraudUC := fraud_usecase.NewFraudUC(
+		fraud_flag_repository.NewFraudFlagRepository(db),
+		txRepo,
+		userRepo,
+		dbTx,
+		fraud_usecase.Config{
+			Lookback:          cfg.Fraud.Lookback,
+			VelocityWindow:    cfg.Fraud.VelocityWindow,
+			VelocityFlagAt:    cfg.Fraud.VelocityFlagAt,
+			VelocityHoldAt:    cfg.Fraud.VelocityHoldAt,
+			RingWindow:        cfg.Fraud.RingWindow,
+			MaxRingSize:       cfg.Fraud.MaxRingSize,
+			NewAccountAge:     cfg.Fraud.NewAccountAge,
+			DrainPercent:      cfg.Fraud.DrainPercent,
+			UnusualFactor:     cfg.Fraud.UnusualFactor,
+			UnusualMinSamples: cfg.Fraud.UnusualMinSamples,
+		},
+	)
+	transactionUC := transaction_usecase.NewTransactionUC(
+		userRepo,
+		txRepo,
+		merchRepo,
+		lotRepo,
+		transfer_limit_repository.NewTransferLimitRepository(db),
+		pending_transfer_repository.NewPendingTransferRepository(db),
+		dbTx,
+		notifier,
+		fraudUC,
+		entity.TransferLimits{
+			PerTransfer:  cfg.TransferLimits.PerTransfer,
+			Daily:        cfg.TransferLimits.Daily,
+			PerRecipient: cfg.TransferLimits.PerRecipient,
+		},
+		entity.ApprovalPolicy{
+			Threshold: cfg.TransferApprovals.Threshold,
+			TTL:       cfg.TransferApprovals.TTL,
+		},
+	)
+	grantUC := grant_usecase.NewGrantUC(
+		userRepo,
+		teamRepo,
+		txRepo,
+		lotRepo,
+		allowance_repository.NewAllowanceRepository(db),
+		dbTx,
+		cfg.Allowance.Amount,
+		cfg.CoinExpiry.TTL,
+	)
+	rewardUC := reward_usecase.NewRewardUC(
+		userRepo,
+		reward_repository.NewRewardRepository(db),
+		txRepo,
+		lotRepo,
+		dbTx,
+		cfg.Rewards.MaxAward,
+	)
+	coinRequestUC := coin_request_usecase.NewCoinRequestUC(
+		coin_request_repository.NewCoinRequestRepository(db),
+		userRepo,
+		transactionUC,
+		dbTx,
+		cfg.CoinRequests.TTL,
+	)
+	scheduledTransferUC := scheduled_transfer_usecase.NewScheduledTransferUC(
+		scheduled_transfer_repository.NewScheduledTransferRepository(db),
+		userRepo,
+		transactionUC,
+		dbTx,
+	)
+	coinExpiryUC := coin_expiry_usecase.NewCoinExpiryUC(lotRepo, userRepo, txRepo, dbTx)
+	marketUC := market_usecase.NewMarketUC(
+		market_repository.NewMarketRepository(db),
+		invRepo,
+		merchRepo,
+		userRepo,
+		txRepo,
+		lotRepo,
+		orderRepo,
+		transactionUC,
+		dbTx,
+		entity.MarketPolicy{
+			FeePercent: cfg.Marketplace.FeePercent,
+			FeeAccount: cfg.Marketplace.FeeAccount,
+			TTL:        cfg.Marketplace.ListingTTL,
+		},
+	)
+	auctionUC := auction_usecase.NewAuctionUC(
+		auction_repository.NewAuctionRepository(db),
+		userRepo,
+		merchRepo,
+		invRepo,
+		txRepo,
+		lotRepo,
+		orderRepo,
+		notifier,
+		dbTx,
+	)
+	raffleUC := raffle_usecase.NewRaffleUC(
+		raffle_repository.NewRaffleRepository(db),
+		userRepo,
+		merchRepo,
+		invRepo,
+		txRepo,
+		lotRepo,
+		orderRepo,
+		notifier,
+		dbTx,
+	)
+	preorderUC := preorder_usecase.NewPreorderUC(
+		preorder_repository.NewPreorderRepository(db),
+		userRepo,
+		merchRepo,
+		invRepo,
+		txRepo,
+		lotRepo,
+		orderRepo,
+		notifier,
+		dbTx,
+	)
+	wishlistUC := wishlist_usecase.NewWishlistUC(
+		wishlist_repository.NewWishlistRepository(db),
+		userRepo,
+		merchRepo,
+		promo_repository.NewPromoRepository(db),
+		notifier,
+		dbTx,
+	)
+	recommendationUC := recommendation_usecase.NewRecommendationUC(
+		recommendation_repository.NewRecommendationRepository(db),
+		userRepo,
+		merchRepo,
+		cfg.Recommendations.RefreshInterval,
+	)
+
+	// Scheduler
+	run := func(name string, job scheduler.Job, interval time.Duration) *scheduler.Scheduler {
+		return scheduler.New(job,
+			scheduler.Interval(interval),
+			scheduler.OnError(func(err error) {
+				l.Error(fmt.Errorf("app - Run - %s: %w", name, err))
+			}),
+		)
+	}
+
+	return []*scheduler.Scheduler{
+		run("PayAllowance", job(grantUC.PayAllowance), cfg.Allowance.Interval),
+		run("ResetBudgets", job(rewardUC.ResetBudgets), cfg.Rewards.ResetInterval),
+		run("ExpireRequests", job(coinRequestUC.ExpireRequests), cfg.CoinRequests.ExpireInterval),
+		run("RunDue", job(scheduledTransferUC.RunDue), cfg.ScheduledTransfers.Interval),
+		run("ExpirePendingTransfers", job(transactionUC.ExpirePendingTransfers), cfg.TransferApprovals.ExpireInterval),
+		run("Sweep", job(coinExpiryUC.Sweep), cfg.CoinExpiry.SweepInterval),
+		run("ExpireListings", job(marketUC.ExpireListings), cfg.Marketplace.ExpireInterval),
+		run("CloseDue", job(auctionUC.CloseDue), cfg.Auctions.CloseInterval),
+		run("DrawDue", job(raffleUC.DrawDue), cfg.Raffles.DrawInterval),
+		run("ReleaseDue", job(preorderUC.ReleaseDue), cfg.Preorders.ReleaseInterval),
+		run("CheckWishlists", job(wishlistUC.CheckWishlists), cfg.Wishlists.CheckInterval),
+		run("Refresh", recommendationUC.Refresh, cfg.Recommendations.RefreshInterval),
+	}
+}
+
+// job runs fn for the current time and drops the count it reports.
+func job[T any](fn func(ctx context.Context, now time.Time) (T, error)) scheduler.Job {
+	return func(ctx context.Context) error {
+		_, err := fn(ctx, time.Now())
+		return err
+	}
+}
@@ -0,0 +1,11 @@
+package entity
+
+import "time"
+
+type AllowancePayout struct {
+	ID        int64     `json:"id" db:"id"`
+	UserID    int64     `json:"user_id" db:"user_id"`
+	Period    string    `json:"period" db:"period"`
+	Amount    int64     `json:"amount" db:"amount"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
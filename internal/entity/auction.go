@@ -0,0 +1,85 @@
+package entity
+
+import "time"
+
+type AuctionStatus string
+
+const (
+	AuctionStatusOpen      AuctionStatus = "open"
+	AuctionStatusClosed    AuctionStatus = "closed"
+	AuctionStatusCancelled AuctionStatus = "cancelled"
+)
+
+// Auction sells one unit of a limited-edition item to the highest bidder.
+// The leading bid is held on the bidder's account until they are outbid or
+// the auction closes, when it is captured and the item goes to them.
+type Auction struct {
+	ID           int64         `json:"id" db:"id"`
+	ItemID       int64         `json:"item_id" db:"item_id"`
+	StartPrice   int64         `json:"start_price" db:"start_price"`
+	MinIncrement int64         `json:"min_increment" db:"min_increment"`
+	StartsAt     time.Time     `json:"starts_at" db:"starts_at"`
+	EndsAt       time.Time     `json:"ends_at" db:"ends_at"`
+	Status       AuctionStatus `json:"status" db:"status"`
+	HighBidderID *int64        `json:"high_bidder_id,omitempty" db:"high_bidder_id"`
+	HighBid      int64         `json:"high_bid" db:"high_bid"`
+	CreatedBy    int64         `json:"created_by" db:"created_by"`
+	ClosedAt     *time.Time    `json:"closed_at,omitempty" db:"closed_at"`
+	CreatedAt    time.Time     `json:"created_at" db:"created_at"`
+
+	// Filled in when listing.
+	ItemName string `json:"item_name,omitempty" db:"item_name"`
+}
+
+func (a *Auction) Validate() error {
+	if a.StartPrice <= 0 || a.MinIncrement <= 0 {
+		return ErrInvalidPrice
+	}
+	if !a.EndsAt.After(a.StartsAt) {
+		return ErrInvalidAuctionWindow
+	}
+	return nil
+}
+
+// IsOpen tells whether the auction takes bids at now.
+func (a *Auction) IsOpen(now time.Time) bool {
+	return a.Status == AuctionStatusOpen && !now.Before(a.StartsAt) && now.Before(a.EndsAt)
+}
+
+// IsDue tells whether the auction has ended and is waiting to be closed.
+func (a *Auction) IsDue(now time.Time) bool {
+	return a.Status == AuctionStatusOpen && !now.Before(a.EndsAt)
+}
+
+// MinimumBid is the least the next bid has to be: the start price while
+// nobody has bid, and the leading bid plus the increment afterwards.
+func (a *Auction) MinimumBid() int64 {
+	if a.HighBidderID == nil {
+		return a.StartPrice
+	}
+	return a.HighBid + a.MinIncrement
+}
+
+// Bid makes amount the leading bid. The caller moves the holds.
+func (a *Auction) Bid(userID, amount int64, now time.Time) error {
+	if !a.IsOpen(now) {
+		return ErrAuctionNotOpen
+	}
+	if amount < a.MinimumBid() {
+		return ErrBidTooLow
+	}
+	a.HighBidderID = &userID
+	a.HighBid = amount
+	return nil
+}
+
+type AuctionBid struct {
+	ID        int64     `json:"id" db:"id"`
+	AuctionID int64     `json:"auction_id" db:"auction_id"`
+	UserID    int64     `json:"user_id" db:"user_id"`
+	Amount    int64     `json:"amount" db:"amount"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+
+	// Filled in when listing.
+	Username string `json:"username,omitempty" db:"username"`
+}
@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAuctionBid(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	leader := int64(2)
+
+	open := Auction{StartPrice: 100, MinIncrement: 10, StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour), Status: AuctionStatusOpen}
+	leading := open
+	leading.HighBidderID = &leader
+	leading.HighBid = 150
+
+	tests := []struct {
+		name    string
+		auction Auction
+		amount  int64
+		at      time.Time
+		want    error
+	}{
+		{"first bid at the start price", open, 100, now, nil},
+		{"first bid below the start price", open, 99, now, ErrBidTooLow},
+		{"outbids by the increment", leading, 160, now, nil},
+		{"outbids by less than the increment", leading, 155, now, ErrBidTooLow},
+		{"before the start", open, 100, now.Add(-2 * time.Hour), ErrAuctionNotOpen},
+		{"at the end", open, 100, now.Add(time.Hour), ErrAuctionNotOpen},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			auction := tc.auction
+
+			err := auction.Bid(7, tc.amount, tc.at)
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("Bid = %v, want %v", err, tc.want)
+			}
+			if err == nil && (*auction.HighBidderID != 7 || auction.HighBid != tc.amount) {
+				t.Errorf("leading bid = %d by %d, want %d by 7", auction.HighBid, *auction.HighBidderID, tc.amount)
+			}
+		})
+	}
+}
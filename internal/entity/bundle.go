@@ -0,0 +1,109 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+// Bundle is a set of items sold together as one product at its own price,
+// e.g. a welcome pack of a t-shirt, a cup and a pen.
+type Bundle struct {
+	ID          int64        `json:"id" db:"id"`
+	Name        string       `json:"name" db:"name"`
+	Price       int64        `json:"price" db:"price"`
+	Description string       `json:"description,omitempty" db:"description"`
+	CreatedBy   *int64       `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt   time.Time    `json:"created_at" db:"created_at"`
+	Items       []BundleItem `json:"items" db:"-"`
+}
+
+// BundleItem is one component of a bundle. Without a VariantID the buyer
+// picks the variant of items that come in variants.
+type BundleItem struct {
+	BundleID  int64  `json:"-" db:"bundle_id"`
+	ItemID    int64  `json:"item_id" db:"item_id"`
+	VariantID *int64 `json:"variant_id,omitempty" db:"variant_id"`
+	Quantity  int64  `json:"quantity" db:"quantity"`
+
+	// Filled in when listing.
+	ItemName string `json:"item_name,omitempty" db:"item_name"`
+}
+
+func (b *Bundle) Validate() error {
+	if strings.TrimSpace(b.Name) == "" {
+		return ErrBundleNameRequired
+	}
+	if b.Price <= 0 {
+		return ErrInvalidPrice
+	}
+	if len(b.Items) == 0 {
+		return ErrEmptyBundle
+	}
+
+	type key struct {
+		itemID    int64
+		variantID int64
+	}
+	seen := make(map[key]bool, len(b.Items))
+	var units int64
+	for _, item := range b.Items {
+		if item.Quantity <= 0 {
+			return ErrInvalidQuantity
+		}
+		units += item.Quantity
+		k := key{itemID: item.ItemID}
+		if item.VariantID != nil {
+			k.variantID = *item.VariantID
+		}
+		if seen[k] {
+			return ErrDuplicateBundleItem
+		}
+		seen[k] = true
+	}
+
+	// Every unit is sold for at least a coin, see SplitPrice.
+	if b.Price < units {
+		return ErrBundlePriceTooLow
+	}
+	return nil
+}
+
+// SplitPrice spreads price over units in proportion to their list prices,
+// so that the shares add up to price exactly. Every unit gets a coin first,
+// so none is handed out for free however cheap it is next to the others, and
+// only the rest is split by list price. The coins left over by rounding down
+// go to the first units, one each. Units are split evenly when none of them
+// has a list price.
+func SplitPrice(price int64, listPrices []int64) []int64 {
+	shares := make([]int64, len(listPrices))
+	if len(listPrices) == 0 {
+		return shares
+	}
+
+	var total int64
+	for _, p := range listPrices {
+		total += p
+	}
+
+	var floor int64
+	if price >= int64(len(listPrices)) {
+		floor = 1
+	}
+	rest := price - floor*int64(len(listPrices))
+
+	var allotted int64
+	for i, p := range listPrices {
+		if total > 0 {
+			shares[i] = floor + rest*p/total
+		} else {
+			shares[i] = floor + rest/int64(len(listPrices))
+		}
+		allotted += shares[i]
+	}
+
+	for i := 0; allotted < price; i = (i + 1) % len(shares) {
+		shares[i]++
+		allotted++
+	}
+	return shares
+}
@@ -0,0 +1,65 @@
+package entity
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestBundleValidate(t *testing.T) {
+	variant := int64(4)
+	other := int64(5)
+
+	tests := []struct {
+		name   string
+		bundle Bundle
+		want   error
+	}{
+		{"valid", Bundle{Name: "welcome pack", Price: 100, Items: []BundleItem{{ItemID: 1, Quantity: 1}, {ItemID: 2, Quantity: 3}}}, nil},
+		{"no name", Bundle{Name: " ", Price: 100, Items: []BundleItem{{ItemID: 1, Quantity: 1}}}, ErrBundleNameRequired},
+		{"free", Bundle{Name: "pack", Items: []BundleItem{{ItemID: 1, Quantity: 1}}}, ErrInvalidPrice},
+		{"empty", Bundle{Name: "pack", Price: 100}, ErrEmptyBundle},
+		{"zero quantity", Bundle{Name: "pack", Price: 100, Items: []BundleItem{{ItemID: 1}}}, ErrInvalidQuantity},
+		{"cheaper than its units", Bundle{Name: "pack", Price: 3, Items: []BundleItem{{ItemID: 1, Quantity: 1}, {ItemID: 2, Quantity: 3}}}, ErrBundlePriceTooLow},
+		{"a coin per unit", Bundle{Name: "pack", Price: 4, Items: []BundleItem{{ItemID: 1, Quantity: 1}, {ItemID: 2, Quantity: 3}}}, nil},
+		{"same item twice", Bundle{Name: "pack", Price: 100, Items: []BundleItem{{ItemID: 1, Quantity: 1}, {ItemID: 1, Quantity: 2}}}, ErrDuplicateBundleItem},
+		{
+			"two variants of one item",
+			Bundle{Name: "pack", Price: 100, Items: []BundleItem{{ItemID: 1, VariantID: &variant, Quantity: 1}, {ItemID: 1, VariantID: &other, Quantity: 1}}},
+			nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.bundle.Validate(); !errors.Is(err, tc.want) {
+				t.Errorf("Validate = %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestSplitPrice(t *testing.T) {
+	tests := []struct {
+		name       string
+		price      int64
+		listPrices []int64
+		want       []int64
+	}{
+		{"proportional", 50, []int64{60, 30, 10}, []int64{30, 15, 5}},
+		{"rounding leftovers go first", 10, []int64{1, 1, 1}, []int64{4, 3, 3}},
+		{"no list prices", 7, []int64{0, 0}, []int64{4, 3}},
+		{"cheap units still cost a coin", 100, []int64{10000, 1}, []int64{99, 1}},
+		{"one coin per unit", 3, []int64{500, 20, 1}, []int64{1, 1, 1}},
+		{"nothing to split", 7, nil, []int64{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := SplitPrice(tc.price, tc.listPrices)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("SplitPrice = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package entity
+
+import "time"
+
+// CoinLot is a batch of coins a user received at once. A user's balance is
+// the sum of the remaining coins of their lots; spending takes coins from the
+// oldest lots first, and lots with an expiry date are swept once it passes.
+//
+// Coins passed on to another user keep the grant and expiry dates of the lot
+// they were taken from, so transfers cannot be used to dodge an expiry.
+type CoinLot struct {
+	ID        int64      `json:"id" db:"id"`
+	UserID    int64      `json:"user_id" db:"user_id"`
+	Amount    int64      `json:"amount" db:"amount"`
+	Remaining int64      `json:"remaining" db:"remaining"`
+	GrantedAt time.Time  `json:"granted_at" db:"granted_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// NewCoinLot returns a lot of amount coins granted at now. A zero ttl means
+// the coins never expire.
+func NewCoinLot(userID, amount int64, now time.Time, ttl time.Duration) CoinLot {
+	lot := CoinLot{
+		UserID:    userID,
+		Amount:    amount,
+		Remaining: amount,
+		GrantedAt: now,
+	}
+	if ttl > 0 {
+		expiresAt := now.Add(ttl)
+		lot.ExpiresAt = &expiresAt
+	}
+	return lot
+}
+
+func (l CoinLot) IsExpired(now time.Time) bool {
+	return l.ExpiresAt != nil && !now.Before(*l.ExpiresAt)
+}
+
+// ConsumeLots takes amount coins from lots in the order given, which is
+// expected to be oldest first, and updates their remaining counts in place.
+// It returns the portions taken, each carrying the ID and dates of the lot it
+// came from, and how much could not be covered by the lots at all.
+func ConsumeLots(lots []CoinLot, amount int64) (taken []CoinLot, short int64) {
+	for i := range lots {
+		if amount == 0 {
+			break
+		}
+
+		lot := &lots[i]
+		if lot.Remaining <= 0 {
+			continue
+		}
+
+		n := min(lot.Remaining, amount)
+		lot.Remaining -= n
+		amount -= n
+
+		taken = append(taken, CoinLot{
+			ID:        lot.ID,
+			UserID:    lot.UserID,
+			Amount:    n,
+			Remaining: n,
+			GrantedAt: lot.GrantedAt,
+			ExpiresAt: lot.ExpiresAt,
+		})
+	}
+
+	return taken, amount
+}
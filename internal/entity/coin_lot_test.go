@@ -0,0 +1,59 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConsumeLots(t *testing.T) {
+	granted := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	expires := granted.AddDate(1, 0, 0)
+
+	lots := []CoinLot{
+		{ID: 1, UserID: 7, Amount: 100, Remaining: 0, GrantedAt: granted},
+		{ID: 2, UserID: 7, Amount: 100, Remaining: 60, GrantedAt: granted, ExpiresAt: &expires},
+		{ID: 3, UserID: 7, Amount: 50, Remaining: 50, GrantedAt: granted.AddDate(0, 1, 0)},
+	}
+
+	taken, short := ConsumeLots(lots, 80)
+	if short != 0 {
+		t.Fatalf("short = %d, want 0", short)
+	}
+	if len(taken) != 2 {
+		t.Fatalf("took from %d lots, want 2", len(taken))
+	}
+	if taken[0].ID != 2 || taken[0].Amount != 60 || taken[0].ExpiresAt != &expires {
+		t.Errorf("first portion = %+v, want 60 coins of lot 2 with its expiry", taken[0])
+	}
+	if taken[1].ID != 3 || taken[1].Amount != 20 {
+		t.Errorf("second portion = %+v, want 20 coins of lot 3", taken[1])
+	}
+	if lots[1].Remaining != 0 || lots[2].Remaining != 30 {
+		t.Errorf("remaining = %d, %d, want 0, 30", lots[1].Remaining, lots[2].Remaining)
+	}
+
+	taken, short = ConsumeLots(lots, 50)
+	if short != 20 {
+		t.Errorf("short = %d, want 20", short)
+	}
+	if len(taken) != 1 || taken[0].Amount != 30 {
+		t.Errorf("taken = %+v, want the last 30 coins of lot 3", taken)
+	}
+}
+
+func TestNewCoinLot(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	lot := NewCoinLot(1, 100, now, 0)
+	if lot.ExpiresAt != nil || lot.IsExpired(now.AddDate(10, 0, 0)) {
+		t.Errorf("lot without ttl expires at %v", lot.ExpiresAt)
+	}
+
+	lot = NewCoinLot(1, 100, now, time.Hour)
+	if lot.IsExpired(now) {
+		t.Error("lot expired as soon as it was granted")
+	}
+	if !lot.IsExpired(now.Add(time.Hour)) {
+		t.Error("lot not expired once its ttl passed")
+	}
+}
@@ -0,0 +1,35 @@
+package entity
+
+import "time"
+
+type CoinRequestStatus string
+
+const (
+	CoinRequestStatusPending   CoinRequestStatus = "pending"
+	CoinRequestStatusAccepted  CoinRequestStatus = "accepted"
+	CoinRequestStatusDeclined  CoinRequestStatus = "declined"
+	CoinRequestStatusExpired   CoinRequestStatus = "expired"
+	CoinRequestStatusCancelled CoinRequestStatus = "cancelled"
+)
+
+// CoinRequest is one user asking another for coins. Only pending requests can
+// change state; every other status is final.
+type CoinRequest struct {
+	ID          int64             `json:"id" db:"id"`
+	RequesterID int64             `json:"requester_id" db:"requester_id"`
+	PayerID     int64             `json:"payer_id" db:"payer_id"`
+	Amount      int64             `json:"amount" db:"amount"`
+	Reason      string            `json:"reason" db:"reason"`
+	Status      CoinRequestStatus `json:"status" db:"status"`
+	ExpiresAt   time.Time         `json:"expires_at" db:"expires_at"`
+	CreatedAt   time.Time         `json:"created_at" db:"created_at"`
+	ResolvedAt  *time.Time        `json:"resolved_at,omitempty" db:"resolved_at"`
+}
+
+func (r *CoinRequest) IsPending() bool {
+	return r.Status == CoinRequestStatusPending
+}
+
+func (r *CoinRequest) IsExpired(now time.Time) bool {
+	return !now.Before(r.ExpiresAt)
+}
@@ -9,4 +9,115 @@ var (
 	ErrUserAlreadyExists = errors.New("user already exists")
 	ErrMerchNotFound     = errors.New("merch not found")
 	ErrTransactionFailed = errors.New("transaction failed")
+	ErrTeamNotFound      = errors.New("team not found")
+	ErrForbidden         = errors.New("forbidden")
+	ErrBudgetNotFound    = errors.New("reward budget not found")
+	ErrBudgetExceeded    = errors.New("reward budget exceeded")
+	ErrRewardCapExceeded = errors.New("reward exceeds the per-award cap")
+	ErrReasonRequired    = errors.New("reason is required")
+	ErrMessageTooLong    = errors.New("message is too long")
+
+	ErrNoRecipients      = errors.New("at least one recipient is required")
+	ErrTooManyRecipients = errors.New("too many recipients")
+	ErrSelfTransfer      = errors.New("cannot transfer coins to yourself")
+
+	ErrTransferLimitExceeded = errors.New("transfer limit exceeded")
+	ErrTransferLimitNotFound = errors.New("transfer limit override not found")
+
+	ErrPendingTransferNotFound   = errors.New("pending transfer not found")
+	ErrPendingTransferNotPending = errors.New("transfer has already been decided")
+	ErrPendingTransferExpired    = errors.New("pending transfer has expired")
+	ErrApprovalRequired          = errors.New("transfer requires approval and must be sent on its own")
+
+	ErrOperationBlocked      = errors.New("operation blocked by fraud checks")
+	ErrFraudFlagNotFound     = errors.New("fraud flag not found")
+	ErrFraudFlagNotOpen      = errors.New("fraud flag has already been reviewed")
+	ErrInvalidFraudFlagState = errors.New("invalid fraud flag status")
+
+	ErrCoinRequestNotFound   = errors.New("coin request not found")
+	ErrCoinRequestNotPending = errors.New("coin request is not pending")
+	ErrCoinRequestExpired    = errors.New("coin request has expired")
+	ErrSelfRequest           = errors.New("cannot request coins from yourself")
+
+	ErrScheduledTransferNotFound = errors.New("scheduled transfer not found")
+	ErrScheduleInPast            = errors.New("scheduled time must be in the future")
+	ErrInvalidRecurrence         = errors.New("invalid recurrence rule")
+
+	ErrCoinLotNotFound = errors.New("coin lot not found")
+
+	ErrInvalidMerchSort  = errors.New("invalid catalog sort order")
+	ErrInvalidPriceRange = errors.New("minimum price is above the maximum")
+
+	ErrVariantNotFound = errors.New("merch variant not found")
+	ErrVariantRequired = errors.New("item comes in several variants, pick one")
+	ErrOutOfStock      = errors.New("merch variant is out of stock")
+
+	ErrInvalidDiscount        = errors.New("invalid discount")
+	ErrInvalidSaleWindow      = errors.New("sale must end after it starts")
+	ErrPromoCodeNotFound      = errors.New("promo code not found")
+	ErrPromoCodeRequired      = errors.New("promo code must not be empty")
+	ErrPromoCodeExists        = errors.New("promo code already exists")
+	ErrPromoCodeInactive      = errors.New("promo code is not active")
+	ErrPromoCodeNotApplicable = errors.New("promo code does not apply to this item")
+	ErrPromoCodeExhausted     = errors.New("promo code has been used up")
+	ErrPromoCodeUsed          = errors.New("promo code has already been used")
+
+	ErrInvalidPrice        = errors.New("price must be positive")
+	ErrPriceChangeInPast   = errors.New("price change must take effect in the future")
+	ErrPriceChangeNotFound = errors.New("scheduled price change not found")
+	ErrPriceChangeConflict = errors.New("a price change already takes effect at that time")
+
+	ErrOrderNotFound          = errors.New("order not found")
+	ErrInvalidOrderStatus     = errors.New("invalid order status")
+	ErrInvalidOrderTransition = errors.New("order cannot move to that status")
+	ErrPickupLocationNotFound = errors.New("pickup location not found")
+	ErrPickupLocationRequired = errors.New("order needs a pickup location first")
+	ErrOrderLocationLocked    = errors.New("pickup location can only be changed before the order is ready")
+
+	ErrInvalidQuantity         = errors.New("quantity must be positive")
+	ErrNotEnoughItems          = errors.New("not enough items in inventory")
+	ErrAddressRequired         = errors.New("delivery address is required")
+	ErrInvalidRedemptionMethod = errors.New("invalid redemption method")
+	ErrRedemptionNotFound      = errors.New("redemption not found")
+	ErrRedemptionNotPending    = errors.New("redemption has already been resolved")
+
+	ErrSelfItemTransfer = errors.New("cannot give items to yourself")
+
+	ErrListingNotFound  = errors.New("listing not found")
+	ErrListingNotActive = errors.New("listing is no longer active")
+	ErrOwnListing       = errors.New("cannot buy your own listing")
+
+	ErrAuctionNotFound      = errors.New("auction not found")
+	ErrAuctionNotOpen       = errors.New("auction is not taking bids")
+	ErrInvalidAuctionWindow = errors.New("auction must end after it starts")
+	ErrBidTooLow            = errors.New("bid is below the minimum")
+
+	ErrRaffleNotFound     = errors.New("raffle not found")
+	ErrRaffleClosed       = errors.New("raffle is no longer selling tickets")
+	ErrTicketLimitReached = errors.New("ticket limit for this raffle reached")
+	ErrInvalidDrawTime    = errors.New("draw must be scheduled in the future")
+
+	ErrWishlistItemNotFound = errors.New("item is not on the wishlist")
+	ErrAlreadyWishlisted    = errors.New("item is already on the wishlist")
+	ErrNotificationNotFound = errors.New("notification not found")
+
+	ErrBundleNotFound      = errors.New("bundle not found")
+	ErrBundleExists        = errors.New("bundle with this name already exists")
+	ErrBundleNameRequired  = errors.New("bundle name is required")
+	ErrEmptyBundle         = errors.New("bundle must contain at least one item")
+	ErrDuplicateBundleItem = errors.New("bundle lists the same item twice")
+	ErrBundlePriceTooLow   = errors.New("bundle price must be at least one coin per unit")
+
+	ErrItemNotReleased      = errors.New("item is not released yet")
+	ErrPreordersClosed      = errors.New("item is not taking pre-orders")
+	ErrItemReleased         = errors.New("item has already been released")
+	ErrPreorderLimitReached = errors.New("pre-order limit for this item reached")
+	ErrPreorderNotFound     = errors.New("pre-order not found")
+	ErrPreorderNotPending   = errors.New("pre-order has already been resolved")
+	ErrInvalidReleaseTime   = errors.New("release must be scheduled in the future")
+
+	ErrInvalidLocale           = errors.New("invalid locale")
+	ErrUnsupportedLocale       = errors.New("locale is not supported")
+	ErrTranslationNotFound     = errors.New("translation not found")
+	ErrTranslationNameRequired = errors.New("translated name is required")
 )
@@ -0,0 +1,76 @@
+package entity
+
+import "time"
+
+// FraudAction is what happens to an operation a fraud rule fired on. Actions
+// are ordered by severity, so the strongest one wins when several rules fire.
+type FraudAction int
+
+const (
+	FraudActionNone FraudAction = iota
+	// FraudActionFlag lets the operation through and records it for review.
+	FraudActionFlag
+	// FraudActionHold parks a transfer until an approver looks at it.
+	FraudActionHold
+	// FraudActionBlock refuses the operation.
+	FraudActionBlock
+)
+
+func (a FraudAction) String() string {
+	switch a {
+	case FraudActionFlag:
+		return "flag"
+	case FraudActionHold:
+		return "hold"
+	case FraudActionBlock:
+		return "block"
+	default:
+		return "none"
+	}
+}
+
+func (a FraudAction) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// FraudOperation is a transfer or purchase about to be executed.
+type FraudOperation struct {
+	Type       TransactionType
+	FromUserID int64
+	ToUserID   int64
+	ItemID     *int64
+	Amount     int64
+	At         time.Time
+}
+
+// FraudVerdict is the outcome of one rule firing on an operation.
+type FraudVerdict struct {
+	Rule   string
+	Action FraudAction
+	Reason string
+}
+
+type FraudFlagStatus string
+
+const (
+	FraudFlagStatusOpen      FraudFlagStatus = "open"
+	FraudFlagStatusDismissed FraudFlagStatus = "dismissed"
+	FraudFlagStatusConfirmed FraudFlagStatus = "confirmed"
+)
+
+// FraudFlag records a rule firing so an admin can review it later.
+type FraudFlag struct {
+	ID            int64           `json:"id" db:"id"`
+	UserID        int64           `json:"user_id" db:"user_id"`
+	CounterpartID *int64          `json:"counterpart_id,omitempty" db:"counterpart_id"`
+	ItemID        *int64          `json:"item_id,omitempty" db:"item_id"`
+	Type          TransactionType `json:"type" db:"type"`
+	Amount        int64           `json:"amount" db:"amount"`
+	Rule          string          `json:"rule" db:"rule"`
+	Action        string          `json:"action" db:"action"`
+	Reason        string          `json:"reason" db:"reason"`
+	Status        FraudFlagStatus `json:"status" db:"status"`
+	ReviewedBy    *int64          `json:"reviewed_by,omitempty" db:"reviewed_by"`
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+	ReviewedAt    *time.Time      `json:"reviewed_at,omitempty" db:"reviewed_at"`
+}
@@ -6,6 +6,19 @@ type UserInventory struct {
 	ID          int64     `json:"id" db:"id"`
 	UserID      int64     `json:"user_id" db:"user_id"`
 	ItemID      int64     `json:"item_id" db:"item_id"`
+	VariantID   *int64    `json:"variant_id,omitempty" db:"variant_id"`
 	Quantity    int64     `json:"quantity" db:"quantity"`
 	PurchasedAt time.Time `json:"purchased_at" db:"purchased_at"`
 }
+
+// Holds reports whether the inventory row is for the given item variant;
+// variantID is nil for items without variants.
+func (i UserInventory) Holds(itemID int64, variantID *int64) bool {
+	if i.ItemID != itemID {
+		return false
+	}
+	if i.VariantID == nil || variantID == nil {
+		return i.VariantID == nil && variantID == nil
+	}
+	return *i.VariantID == *variantID
+}
@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+// Kudos is a transfer with a message that both parties agreed to show in the
+// public feed.
+type Kudos struct {
+	ID           int64     `json:"id" db:"id"`
+	FromUsername string    `json:"from" db:"from_username"`
+	ToUsername   string    `json:"to" db:"to_username"`
+	Amount       int64     `json:"amount" db:"amount"`
+	Message      string    `json:"message" db:"message"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
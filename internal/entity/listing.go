@@ -0,0 +1,89 @@
+package entity
+
+import "time"
+
+type ListingStatus string
+
+const (
+	ListingStatusActive    ListingStatus = "active"
+	ListingStatusSold      ListingStatus = "sold"
+	ListingStatusCancelled ListingStatus = "cancelled"
+	ListingStatusExpired   ListingStatus = "expired"
+)
+
+// MarketPolicy configures the internal marketplace. FeePercent of every sale
+// is kept out of the seller's proceeds and paid to the FeeAccount user or,
+// when that is empty, burned. Listings expire after TTL; a zero TTL keeps
+// them up until sold or cancelled.
+type MarketPolicy struct {
+	FeePercent int64
+	FeeAccount string
+	TTL        time.Duration
+}
+
+// Fee is the part of price the marketplace keeps, rounded down. Percentages
+// outside 0-99 are clamped so that the seller always gets something.
+func (p MarketPolicy) Fee(price int64) int64 {
+	percent := min(max(p.FeePercent, 0), 99)
+	return price * percent / 100
+}
+
+// Burns tells whether fees leave circulation instead of going to an account.
+func (p MarketPolicy) Burns() bool {
+	return p.FeeAccount == ""
+}
+
+// Listing offers items from a user's inventory to other users for coins.
+// While the listing is active the items are held out of the seller's
+// inventory; they go to the buyer on a sale and back to the seller when the
+// listing is cancelled or expires. Price is for the whole quantity.
+type Listing struct {
+	ID        int64         `json:"id" db:"id"`
+	SellerID  int64         `json:"seller_id" db:"seller_id"`
+	ItemID    int64         `json:"item_id" db:"item_id"`
+	VariantID *int64        `json:"variant_id,omitempty" db:"variant_id"`
+	Quantity  int64         `json:"quantity" db:"quantity"`
+	Price     int64         `json:"price" db:"price"`
+	Status    ListingStatus `json:"status" db:"status"`
+	BuyerID   *int64        `json:"buyer_id,omitempty" db:"buyer_id"`
+	Fee       int64         `json:"fee" db:"fee"`
+	ExpiresAt *time.Time    `json:"expires_at,omitempty" db:"expires_at"`
+	ClosedAt  *time.Time    `json:"closed_at,omitempty" db:"closed_at"`
+	CreatedAt time.Time     `json:"created_at" db:"created_at"`
+
+	// Filled in when listing.
+	Seller   string `json:"seller,omitempty" db:"seller"`
+	ItemName string `json:"item_name,omitempty" db:"item_name"`
+	Variant  string `json:"variant,omitempty" db:"variant"`
+}
+
+func (l *Listing) Validate() error {
+	if l.Quantity <= 0 {
+		return ErrInvalidQuantity
+	}
+	if l.Price <= 0 {
+		return ErrInvalidPrice
+	}
+	return nil
+}
+
+func (l *Listing) IsExpired(now time.Time) bool {
+	return l.ExpiresAt != nil && !now.Before(*l.ExpiresAt)
+}
+
+// IsOpen tells whether the listing can still be bought or cancelled.
+func (l *Listing) IsOpen(now time.Time) bool {
+	return l.Status == ListingStatusActive && !l.IsExpired(now)
+}
+
+// ListingQuery selects a page of the active listings. Zero values leave the
+// corresponding filter out.
+type ListingQuery struct {
+	Search        string
+	ItemID        *int64
+	SellerID      *int64
+	MaxPrice      *int64
+	CheapestFirst bool
+	Limit         int
+	Offset        int
+}
@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMarketPolicyFee(t *testing.T) {
+	tests := []struct {
+		name    string
+		percent int64
+		price   int64
+		want    int64
+	}{
+		{"no fee", 0, 100, 0},
+		{"rounded down", 5, 30, 1},
+		{"small price", 5, 10, 0},
+		{"negative", -5, 100, 0},
+		{"capped", 150, 100, 99},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := MarketPolicy{FeePercent: tc.percent}.Fee(tc.price)
+			if got != tc.want {
+				t.Errorf("Fee(%d) at %d%% = %d, want %d", tc.price, tc.percent, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestListingIsOpen(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	later := now.Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		listing Listing
+		want    bool
+	}{
+		{"active", Listing{Status: ListingStatusActive}, true},
+		{"active until later", Listing{Status: ListingStatusActive, ExpiresAt: &later}, true},
+		{"expires now", Listing{Status: ListingStatusActive, ExpiresAt: &now}, false},
+		{"sold", Listing{Status: ListingStatusSold}, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.listing.IsOpen(now); got != tc.want {
+				t.Errorf("IsOpen = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
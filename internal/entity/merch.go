@@ -1,10 +1,107 @@
 package entity
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 type MerchItem struct {
+	ID          int64   `json:"id" db:"id"`
+	Name        string  `json:"name" db:"name"`
+	Price       int64   `json:"price" db:"price"`
+	CategoryID  *int64  `json:"category_id,omitempty" db:"category_id"`
+	Category    string  `json:"category,omitempty" db:"category"`
+	Description string  `json:"description,omitempty" db:"description"`
+	ImageURL    *string `json:"image_url,omitempty" db:"image_url"`
+	// Tags are free-form, lower-cased labels; see NormalizeTags.
+	Tags []string `json:"tags,omitempty" db:"-"`
+	// ReleaseAt is when an upcoming item goes on sale; until then it can
+	// only be pre-ordered, PreorderLimit units at most.
+	ReleaseAt     *time.Time `json:"release_at,omitempty" db:"release_at"`
+	PreorderLimit *int64     `json:"preorder_limit,omitempty" db:"preorder_limit"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+}
+
+// IsReleased tells whether the item is on sale at now. Items without a
+// release date always are.
+func (m MerchItem) IsReleased(now time.Time) bool {
+	return m.ReleaseAt == nil || !now.Before(*m.ReleaseAt)
+}
+
+// TakesPreorders tells whether the item can be pre-ordered at now.
+func (m MerchItem) TakesPreorders(now time.Time) bool {
+	return !m.IsReleased(now) && m.PreorderLimit != nil
+}
+
+type MerchCategory struct {
 	ID        int64     `json:"id" db:"id"`
 	Name      string    `json:"name" db:"name"`
-	Price     int64     `json:"price" db:"price"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 }
+
+type MerchSort string
+
+const (
+	// MerchSortRelevance ranks full-text matches first; without a search
+	// phrase it keeps the catalog order.
+	MerchSortRelevance MerchSort = "relevance"
+	MerchSortPriceAsc  MerchSort = "price_asc"
+	MerchSortPriceDesc MerchSort = "price_desc"
+	MerchSortNewest    MerchSort = "newest"
+	MerchSortName      MerchSort = "name"
+)
+
+func (s MerchSort) IsValid() bool {
+	switch s {
+	case MerchSortRelevance, MerchSortPriceAsc, MerchSortPriceDesc, MerchSortNewest, MerchSortName:
+		return true
+	default:
+		return false
+	}
+}
+
+// MerchQuery selects a page of the catalog. Zero values leave the
+// corresponding filter out.
+type MerchQuery struct {
+	Search     string
+	CategoryID *int64
+	// Tags an item must all carry to match.
+	Tags     []string
+	MinPrice *int64
+	MaxPrice *int64
+	Sort     MerchSort
+	Limit    int
+	Offset   int
+}
+
+// NormalizeTags lower-cases and trims tags, dropping empty ones and
+// duplicates while keeping the original order.
+func NormalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		result = append(result, tag)
+	}
+	return result
+}
+
+// MerchPrice is an entry of an item's price history. The price in effect at
+// a given moment is the one of the latest entry that took effect by then;
+// entries taking effect in the future are scheduled price changes.
+type MerchPrice struct {
+	ID            int64     `json:"id" db:"id"`
+	ItemID        int64     `json:"item_id" db:"item_id"`
+	Price         int64     `json:"price" db:"price"`
+	EffectiveFrom time.Time `json:"effective_from" db:"effective_from"`
+	CreatedBy     *int64    `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+func (p MerchPrice) IsScheduled(now time.Time) bool {
+	return p.EffectiveFrom.After(now)
+}
@@ -0,0 +1,112 @@
+package entity
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TranslationSource tells who wrote a catalog translation.
+type TranslationSource string
+
+const (
+	TranslationSourceHuman   TranslationSource = "human"
+	TranslationSourceMachine TranslationSource = "machine"
+)
+
+// MerchTranslation is an item's name and description in one locale.
+// Machine translations keep the text they were made from, so that an edited
+// item gets translated again; human ones are kept as written.
+type MerchTranslation struct {
+	ItemID            int64             `json:"item_id" db:"item_id"`
+	Locale            string            `json:"locale" db:"locale"`
+	Name              string            `json:"name" db:"name"`
+	Description       string            `json:"description,omitempty" db:"description"`
+	Source            TranslationSource `json:"source" db:"source"`
+	SourceName        string            `json:"-" db:"source_name"`
+	SourceDescription string            `json:"-" db:"source_description"`
+	UpdatedAt         time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+// OriginalTranslation is the item as written, in the catalog's own locale.
+func OriginalTranslation(item MerchItem, locale string) MerchTranslation {
+	return MerchTranslation{
+		ItemID:            item.ID,
+		Locale:            locale,
+		Name:              item.Name,
+		Description:       item.Description,
+		Source:            TranslationSourceHuman,
+		SourceName:        item.Name,
+		SourceDescription: item.Description,
+	}
+}
+
+// IsCurrent tells whether the translation can still be shown for item.
+// Human translations always can.
+func (t MerchTranslation) IsCurrent(item MerchItem) bool {
+	if t.Source == TranslationSourceHuman {
+		return true
+	}
+	return t.SourceName == item.Name && t.SourceDescription == item.Description
+}
+
+// NormalizeLocale reduces a language tag to its lower-cased primary
+// language, so "en-US" becomes "en".
+func NormalizeLocale(tag string) (string, error) {
+	lang, _, _ := strings.Cut(strings.TrimSpace(tag), "-")
+	lang = strings.ToLower(lang)
+	if len(lang) < 2 || len(lang) > 3 {
+		return "", ErrInvalidLocale
+	}
+	for _, r := range lang {
+		if r < 'a' || r > 'z' {
+			return "", ErrInvalidLocale
+		}
+	}
+	return lang, nil
+}
+
+// PreferredLocales parses an Accept-Language header into locales, most
+// preferred first. Wildcards, malformed entries and those with q=0 are
+// skipped.
+func PreferredLocales(acceptLanguage string) []string {
+	type weighted struct {
+		locale string
+		q      float64
+	}
+
+	var ranges []weighted
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		locale, err := NormalizeLocale(tag)
+		if err != nil || seen[locale] {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			q, err = strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+
+		seen[locale] = true
+		ranges = append(ranges, weighted{locale: locale, q: q})
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].q > ranges[j].q
+	})
+
+	locales := make([]string, len(ranges))
+	for i, r := range ranges {
+		locales[i] = r.locale
+	}
+	return locales
+}
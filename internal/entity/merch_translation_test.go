@@ -0,0 +1,57 @@
+package entity
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPreferredLocales(t *testing.T) {
+	tests := []struct {
+		header string
+		want   []string
+	}{
+		{"", []string{}},
+		{"ru", []string{"ru"}},
+		{"ru-RU,ru;q=0.9,en-US;q=0.8,en;q=0.7", []string{"ru", "en"}},
+		{"en;q=0.5, de;q=0.8, fr", []string{"fr", "de", "en"}},
+		{"*, es;q=0, it;q=abc, pt-BR;q=0.3", []string{"pt"}},
+	}
+
+	for _, tc := range tests {
+		got := PreferredLocales(tc.header)
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("PreferredLocales(%q) = %v, want %v", tc.header, got, tc.want)
+		}
+	}
+}
+
+func TestNormalizeLocale(t *testing.T) {
+	if got, err := NormalizeLocale(" EN-gb "); err != nil || got != "en" {
+		t.Errorf("NormalizeLocale = %q, %v; want en", got, err)
+	}
+	for _, tag := range []string{"", "e", "english", "e1", "*"} {
+		if _, err := NormalizeLocale(tag); !errors.Is(err, ErrInvalidLocale) {
+			t.Errorf("NormalizeLocale(%q) error = %v, want ErrInvalidLocale", tag, err)
+		}
+	}
+}
+
+func TestMerchTranslationIsCurrent(t *testing.T) {
+	item := MerchItem{ID: 1, Name: "cup", Description: "a cup"}
+
+	machine := MerchTranslation{Source: TranslationSourceMachine, SourceName: "cup", SourceDescription: "a cup"}
+	if !machine.IsCurrent(item) {
+		t.Errorf("machine translation of the current text should be current")
+	}
+
+	item.Description = "a bigger cup"
+	if machine.IsCurrent(item) {
+		t.Errorf("machine translation of an edited item should be stale")
+	}
+
+	human := MerchTranslation{Source: TranslationSourceHuman, SourceName: "cup", SourceDescription: "a cup"}
+	if !human.IsCurrent(item) {
+		t.Errorf("human translation should never go stale")
+	}
+}
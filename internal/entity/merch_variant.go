@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+// MerchVariant is one purchasable version of an item, such as a size or a
+// color. Unlike items without variants, variants have a limited stock.
+type MerchVariant struct {
+	ID     int64  `json:"id" db:"id"`
+	ItemID int64  `json:"item_id" db:"item_id"`
+	Size   string `json:"size,omitempty" db:"size"`
+	Color  string `json:"color,omitempty" db:"color"`
+	Stock  int64  `json:"stock" db:"stock"`
+	// PriceDelta is added to the item price; it may be negative.
+	PriceDelta int64     `json:"price_delta" db:"price_delta"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// Label is the short form shown next to the item name, e.g. "L" or
+// "L, black".
+func (v MerchVariant) Label() string {
+	parts := make([]string, 0, 2)
+	if v.Size != "" {
+		parts = append(parts, v.Size)
+	}
+	if v.Color != "" {
+		parts = append(parts, v.Color)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Price is what the variant costs given the price of its item.
+func (v MerchVariant) Price(item MerchItem) int64 {
+	return item.Price + v.PriceDelta
+}
+
+func (v MerchVariant) InStock() bool {
+	return v.Stock > 0
+}
@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+// Notification is a message shown to the user inside the app.
+type Notification struct {
+	ID        int64      `json:"id" db:"id"`
+	UserID    int64      `json:"user_id" db:"user_id"`
+	Message   string     `json:"message" db:"message"`
+	ReadAt    *time.Time `json:"read_at,omitempty" db:"read_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+func (n Notification) IsRead() bool {
+	return n.ReadAt != nil
+}
@@ -0,0 +1,93 @@
+package entity
+
+import "time"
+
+type OrderStatus string
+
+const (
+	OrderStatusPlaced         OrderStatus = "placed"
+	OrderStatusPacked         OrderStatus = "packed"
+	OrderStatusReadyForPickup OrderStatus = "ready_for_pickup"
+	OrderStatusDelivered      OrderStatus = "delivered"
+	OrderStatusCancelled      OrderStatus = "cancelled"
+)
+
+// orderTransitions lists the statuses an order may move to from each status.
+// Delivered and cancelled orders are final.
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusPlaced:         {OrderStatusPacked, OrderStatusCancelled},
+	OrderStatusPacked:         {OrderStatusReadyForPickup, OrderStatusCancelled},
+	OrderStatusReadyForPickup: {OrderStatusDelivered, OrderStatusCancelled},
+}
+
+func (s OrderStatus) IsValid() bool {
+	switch s {
+	case OrderStatusPlaced, OrderStatusPacked, OrderStatusReadyForPickup, OrderStatusDelivered, OrderStatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s OrderStatus) IsFinal() bool {
+	return s == OrderStatusDelivered || s == OrderStatusCancelled
+}
+
+func (s OrderStatus) CanBecome(next OrderStatus) bool {
+	for _, allowed := range orderTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// Order tracks the physical hand-over of one purchased item. Price is what
+// the buyer paid, so a cancelled order can be refunded exactly; it is zero
+// for raffle prizes.
+type Order struct {
+	ID         int64       `json:"id" db:"id"`
+	UserID     int64       `json:"user_id" db:"user_id"`
+	ItemID     int64       `json:"item_id" db:"item_id"`
+	VariantID  *int64      `json:"variant_id,omitempty" db:"variant_id"`
+	Price      int64       `json:"price" db:"price"`
+	LocationID *int64      `json:"location_id,omitempty" db:"location_id"`
+	Location   string      `json:"location,omitempty" db:"location"`
+	Status     OrderStatus `json:"status" db:"status"`
+	UpdatedBy  *int64      `json:"updated_by,omitempty" db:"updated_by"`
+	CreatedAt  time.Time   `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time   `json:"updated_at" db:"updated_at"`
+
+	// Lots are the coins the order was paid with, as taken by
+	// LotRepository.Spend. They are stored with the order so a refund gives
+	// the coins back with their original expiry.
+	Lots []CoinLot `json:"-" db:"-"`
+}
+
+// Advance moves the order to the next status on behalf of actorID. Orders
+// are only handed out at a known location, so one has to be set before the
+// order is ready for pickup.
+func (o *Order) Advance(next OrderStatus, actorID int64, now time.Time) error {
+	if !next.IsValid() {
+		return ErrInvalidOrderStatus
+	}
+	if !o.Status.CanBecome(next) {
+		return ErrInvalidOrderTransition
+	}
+	if next == OrderStatusReadyForPickup && o.LocationID == nil {
+		return ErrPickupLocationRequired
+	}
+
+	o.Status = next
+	o.UpdatedBy = &actorID
+	o.UpdatedAt = now
+	return nil
+}
+
+// PickupLocation is an office or desk where orders are handed out.
+type PickupLocation struct {
+	ID        int64     `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	Address   string    `json:"address" db:"address"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
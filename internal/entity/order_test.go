@@ -0,0 +1,49 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestOrderAdvance(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	office := int64(1)
+
+	tests := []struct {
+		name     string
+		from     OrderStatus
+		location *int64
+		next     OrderStatus
+		want     error
+	}{
+		{"pack", OrderStatusPlaced, nil, OrderStatusPacked, nil},
+		{"skip packing", OrderStatusPlaced, &office, OrderStatusReadyForPickup, ErrInvalidOrderTransition},
+		{"ready needs a location", OrderStatusPacked, nil, OrderStatusReadyForPickup, ErrPickupLocationRequired},
+		{"ready", OrderStatusPacked, &office, OrderStatusReadyForPickup, nil},
+		{"deliver", OrderStatusReadyForPickup, &office, OrderStatusDelivered, nil},
+		{"cancel", OrderStatusPacked, nil, OrderStatusCancelled, nil},
+		{"delivered is final", OrderStatusDelivered, &office, OrderStatusCancelled, ErrInvalidOrderTransition},
+		{"unknown status", OrderStatusPlaced, nil, OrderStatus("lost"), ErrInvalidOrderStatus},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			order := Order{Status: tc.from, LocationID: tc.location}
+
+			err := order.Advance(tc.next, 7, now)
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("Advance = %v, want %v", err, tc.want)
+			}
+			if err != nil {
+				if order.Status != tc.from {
+					t.Errorf("status changed to %s on a rejected transition", order.Status)
+				}
+				return
+			}
+			if order.Status != tc.next || *order.UpdatedBy != 7 || !order.UpdatedAt.Equal(now) {
+				t.Errorf("order = %+v, want %s updated by 7 at %s", order, tc.next, now)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package entity
+
+import "time"
+
+type PendingTransferStatus string
+
+const (
+	PendingTransferStatusPending  PendingTransferStatus = "pending"
+	PendingTransferStatusApproved PendingTransferStatus = "approved"
+	PendingTransferStatusRejected PendingTransferStatus = "rejected"
+	PendingTransferStatusExpired  PendingTransferStatus = "expired"
+)
+
+// ApprovalPolicy decides which transfers need a second pair of eyes.
+// Transfers above Threshold wait for approval for at most TTL; a zero
+// Threshold turns approvals off.
+type ApprovalPolicy struct {
+	Threshold int64
+	TTL       time.Duration
+}
+
+func (p ApprovalPolicy) Requires(amount int64) bool {
+	return p.Threshold > 0 && amount > p.Threshold
+}
+
+// PendingTransfer is a large transfer waiting for an approver. The amount is
+// held on the sender's account until the transfer is approved, rejected or
+// expires.
+type PendingTransfer struct {
+	ID         int64                 `json:"id" db:"id"`
+	FromUserID int64                 `json:"from_user_id" db:"from_user_id"`
+	ToUserID   int64                 `json:"to_user_id" db:"to_user_id"`
+	Amount     int64                 `json:"amount" db:"amount"`
+	Message    *string               `json:"message,omitempty" db:"message"`
+	Status     PendingTransferStatus `json:"status" db:"status"`
+	ExpiresAt  time.Time             `json:"expires_at" db:"expires_at"`
+	DecidedBy  *int64                `json:"decided_by,omitempty" db:"decided_by"`
+	CreatedAt  time.Time             `json:"created_at" db:"created_at"`
+	DecidedAt  *time.Time            `json:"decided_at,omitempty" db:"decided_at"`
+}
+
+func (t *PendingTransfer) IsPending() bool {
+	return t.Status == PendingTransferStatusPending
+}
+
+func (t *PendingTransfer) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
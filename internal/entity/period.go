@@ -0,0 +1,11 @@
+package entity
+
+import "time"
+
+// PeriodLayout formats the calendar month that allowances and reward budgets
+// are accounted for.
+const PeriodLayout = "2006-01"
+
+func MonthlyPeriod(t time.Time) string {
+	return t.UTC().Format(PeriodLayout)
+}
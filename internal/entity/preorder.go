@@ -0,0 +1,42 @@
+package entity
+
+import "time"
+
+type PreorderStatus string
+
+const (
+	PreorderStatusPending   PreorderStatus = "pending"
+	PreorderStatusFulfilled PreorderStatus = "fulfilled"
+	PreorderStatusCancelled PreorderStatus = "cancelled"
+)
+
+// Preorder reserves one unit of an upcoming item. Price is held on the
+// user's account until the item is released, when it is captured, or the
+// pre-order is cancelled, when it is released.
+type Preorder struct {
+	ID         int64          `json:"id" db:"id"`
+	UserID     int64          `json:"user_id" db:"user_id"`
+	ItemID     int64          `json:"item_id" db:"item_id"`
+	VariantID  *int64         `json:"variant_id,omitempty" db:"variant_id"`
+	Price      int64          `json:"price" db:"price"`
+	Status     PreorderStatus `json:"status" db:"status"`
+	ResolvedAt *time.Time     `json:"resolved_at,omitempty" db:"resolved_at"`
+	CreatedAt  time.Time      `json:"created_at" db:"created_at"`
+
+	// Filled in when listing.
+	ItemName string `json:"item_name,omitempty" db:"item_name"`
+}
+
+func (p *Preorder) IsPending() bool {
+	return p.Status == PreorderStatusPending
+}
+
+// Resolve moves a pending pre-order to its final status.
+func (p *Preorder) Resolve(status PreorderStatus, now time.Time) error {
+	if !p.IsPending() {
+		return ErrPreorderNotPending
+	}
+	p.Status = status
+	p.ResolvedAt = &now
+	return nil
+}
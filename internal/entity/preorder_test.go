@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMerchItemRelease(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	later := now.Add(time.Hour)
+	limit := int64(10)
+
+	tests := []struct {
+		name      string
+		item      MerchItem
+		released  bool
+		preorders bool
+	}{
+		{"regular item", MerchItem{}, true, false},
+		{"upcoming", MerchItem{ReleaseAt: &later, PreorderLimit: &limit}, false, true},
+		{"upcoming without pre-orders", MerchItem{ReleaseAt: &later}, false, false},
+		{"released", MerchItem{ReleaseAt: &now, PreorderLimit: &limit}, true, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.item.IsReleased(now); got != tc.released {
+				t.Errorf("IsReleased = %v, want %v", got, tc.released)
+			}
+			if got := tc.item.TakesPreorders(now); got != tc.preorders {
+				t.Errorf("TakesPreorders = %v, want %v", got, tc.preorders)
+			}
+		})
+	}
+}
+
+func TestPreorderResolve(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	preorder := Preorder{Status: PreorderStatusPending}
+
+	if err := preorder.Resolve(PreorderStatusFulfilled, now); err != nil {
+		t.Fatalf("Resolve = %v", err)
+	}
+	if preorder.ResolvedAt == nil || !preorder.ResolvedAt.Equal(now) {
+		t.Errorf("ResolvedAt = %v, want %v", preorder.ResolvedAt, now)
+	}
+	if err := preorder.Resolve(PreorderStatusCancelled, now); !errors.Is(err, ErrPreorderNotPending) {
+		t.Errorf("Resolve again = %v, want %v", err, ErrPreorderNotPending)
+	}
+}
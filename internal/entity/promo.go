@@ -0,0 +1,131 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+type DiscountKind string
+
+const (
+	// DiscountKindPercent takes Value percent off the price.
+	DiscountKindPercent DiscountKind = "percent"
+	// DiscountKindFixed takes Value coins off the price.
+	DiscountKindFixed DiscountKind = "fixed"
+)
+
+// Discount lowers a price either by a percentage or by a fixed number of
+// coins. A discounted purchase still costs at least one coin.
+type Discount struct {
+	Kind  DiscountKind `json:"kind" db:"kind"`
+	Value int64        `json:"value" db:"value"`
+}
+
+func (d Discount) Validate() error {
+	switch d.Kind {
+	case DiscountKindPercent:
+		if d.Value <= 0 || d.Value > 100 {
+			return ErrInvalidDiscount
+		}
+	case DiscountKindFixed:
+		if d.Value <= 0 {
+			return ErrInvalidDiscount
+		}
+	default:
+		return ErrInvalidDiscount
+	}
+	return nil
+}
+
+// Apply returns the discounted price. Percentages are rounded in the
+// buyer's favour.
+func (d Discount) Apply(price int64) int64 {
+	var off int64
+	switch d.Kind {
+	case DiscountKindPercent:
+		off = (price*d.Value + 99) / 100
+	case DiscountKindFixed:
+		off = d.Value
+	}
+	return max(price-off, 1)
+}
+
+// PromoCode is a code buyers enter at checkout. A nil ItemID makes it valid
+// for the whole catalog; nil MaxUses leaves the number of uses unlimited.
+type PromoCode struct {
+	ID   int64  `json:"id" db:"id"`
+	Code string `json:"code" db:"code"`
+	Discount
+	ItemID      *int64     `json:"item_id,omitempty" db:"item_id"`
+	MaxUses     *int64     `json:"max_uses,omitempty" db:"max_uses"`
+	Uses        int64      `json:"uses" db:"uses"`
+	OncePerUser bool       `json:"once_per_user" db:"once_per_user"`
+	StartsAt    *time.Time `json:"starts_at,omitempty" db:"starts_at"`
+	EndsAt      *time.Time `json:"ends_at,omitempty" db:"ends_at"`
+	CreatedBy   int64      `json:"created_by" db:"created_by"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+}
+
+// NormalizePromoCode makes codes case-insensitive.
+func NormalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func (p PromoCode) IsActive(now time.Time) bool {
+	if p.StartsAt != nil && now.Before(*p.StartsAt) {
+		return false
+	}
+	return p.EndsAt == nil || now.Before(*p.EndsAt)
+}
+
+func (p PromoCode) AppliesTo(itemID int64) bool {
+	return p.ItemID == nil || *p.ItemID == itemID
+}
+
+func (p PromoCode) Exhausted() bool {
+	return p.MaxUses != nil && p.Uses >= *p.MaxUses
+}
+
+// CheckUsable reports why the code cannot be used on itemID at now, if it
+// cannot. Per-user limits are checked separately.
+func (p PromoCode) CheckUsable(itemID int64, now time.Time) error {
+	if !p.IsActive(now) {
+		return ErrPromoCodeInactive
+	}
+	if !p.AppliesTo(itemID) {
+		return ErrPromoCodeNotApplicable
+	}
+	if p.Exhausted() {
+		return ErrPromoCodeExhausted
+	}
+	return nil
+}
+
+// MerchSale is a discount scheduled for a time window, on one item or, with
+// a nil ItemID, on the whole catalog.
+type MerchSale struct {
+	ID     int64  `json:"id" db:"id"`
+	ItemID *int64 `json:"item_id,omitempty" db:"item_id"`
+	Discount
+	StartsAt  time.Time `json:"starts_at" db:"starts_at"`
+	EndsAt    time.Time `json:"ends_at" db:"ends_at"`
+	CreatedBy int64     `json:"created_by" db:"created_by"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+func (s MerchSale) IsActive(now time.Time) bool {
+	return !now.Before(s.StartsAt) && now.Before(s.EndsAt)
+}
+
+// SalePrice applies the sale that gives the lowest price among those active
+// at now for itemID. Sales do not stack.
+func SalePrice(sales []MerchSale, itemID, price int64, now time.Time) int64 {
+	best := price
+	for _, sale := range sales {
+		if !sale.IsActive(now) || (sale.ItemID != nil && *sale.ItemID != itemID) {
+			continue
+		}
+		best = min(best, sale.Apply(price))
+	}
+	return best
+}
@@ -0,0 +1,79 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDiscountApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		discount Discount
+		price    int64
+		want     int64
+	}{
+		{"percent", Discount{Kind: DiscountKindPercent, Value: 10}, 300, 270},
+		{"percent rounds in the buyer's favour", Discount{Kind: DiscountKindPercent, Value: 15}, 10, 8},
+		{"fixed", Discount{Kind: DiscountKindFixed, Value: 50}, 300, 250},
+		{"fixed above the price", Discount{Kind: DiscountKindFixed, Value: 500}, 300, 1},
+		{"everything off", Discount{Kind: DiscountKindPercent, Value: 100}, 300, 1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.discount.Apply(tc.price); got != tc.want {
+				t.Errorf("Apply(%d) = %d, want %d", tc.price, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPromoCodeCheckUsable(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	later := now.Add(time.Hour)
+	earlier := now.Add(-time.Hour)
+	item := int64(3)
+	one := int64(1)
+
+	tests := []struct {
+		name string
+		code PromoCode
+		want error
+	}{
+		{"unrestricted", PromoCode{}, nil},
+		{"not started", PromoCode{StartsAt: &later}, ErrPromoCodeInactive},
+		{"ended", PromoCode{EndsAt: &earlier}, ErrPromoCodeInactive},
+		{"other item", PromoCode{ItemID: &item}, ErrPromoCodeNotApplicable},
+		{"used up", PromoCode{MaxUses: &one, Uses: 1}, ErrPromoCodeExhausted},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.code.CheckUsable(1, now); !errors.Is(err, tc.want) {
+				t.Errorf("CheckUsable = %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestSalePrice(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	item := int64(3)
+	sales := []MerchSale{
+		{Discount: Discount{Kind: DiscountKindPercent, Value: 10}, StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)},
+		{ItemID: &item, Discount: Discount{Kind: DiscountKindFixed, Value: 100}, StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)},
+		// not started yet
+		{Discount: Discount{Kind: DiscountKindPercent, Value: 90}, StartsAt: now.Add(time.Hour), EndsAt: now.Add(2 * time.Hour)},
+	}
+
+	if got := SalePrice(sales, 3, 300, now); got != 200 {
+		t.Errorf("item on sale costs %d, want the best sale price 200", got)
+	}
+	if got := SalePrice(sales, 4, 300, now); got != 270 {
+		t.Errorf("other item costs %d, want the catalog-wide 270", got)
+	}
+	if got := SalePrice(nil, 4, 300, now); got != 300 {
+		t.Errorf("without sales the item costs %d, want 300", got)
+	}
+}
@@ -0,0 +1,122 @@
+package entity
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+type RaffleStatus string
+
+const (
+	RaffleStatusOpen  RaffleStatus = "open"
+	RaffleStatusDrawn RaffleStatus = "drawn"
+)
+
+// Raffle gives Prizes units of an item away to holders of tickets bought
+// with coins. The draw only depends on the seed and the tickets sold, so it
+// can be repeated by anyone: the seed is fixed when the raffle is created,
+// its SHA-256 is published right away and the seed itself once drawn.
+type Raffle struct {
+	ID                int64        `json:"id" db:"id"`
+	ItemID            int64        `json:"item_id" db:"item_id"`
+	TicketPrice       int64        `json:"ticket_price" db:"ticket_price"`
+	MaxTicketsPerUser int64        `json:"max_tickets_per_user,omitempty" db:"max_tickets_per_user"`
+	Prizes            int64        `json:"prizes" db:"prizes"`
+	DrawAt            time.Time    `json:"draw_at" db:"draw_at"`
+	Status            RaffleStatus `json:"status" db:"status"`
+	Seed              string       `json:"seed,omitempty" db:"seed"`
+	SeedHash          string       `json:"seed_hash" db:"seed_hash"`
+	DrawnAt           *time.Time   `json:"drawn_at,omitempty" db:"drawn_at"`
+	CreatedBy         int64        `json:"created_by" db:"created_by"`
+	CreatedAt         time.Time    `json:"created_at" db:"created_at"`
+
+	// Filled in when listing.
+	ItemName    string `json:"item_name,omitempty" db:"item_name"`
+	TicketsSold int64  `json:"tickets_sold" db:"tickets_sold"`
+}
+
+func (r *Raffle) Validate() error {
+	if r.TicketPrice <= 0 {
+		return ErrInvalidPrice
+	}
+	if r.Prizes <= 0 || r.MaxTicketsPerUser < 0 {
+		return ErrInvalidQuantity
+	}
+	return nil
+}
+
+// IsOpen tells whether tickets are still on sale at now.
+func (r *Raffle) IsOpen(now time.Time) bool {
+	return r.Status == RaffleStatusOpen && now.Before(r.DrawAt)
+}
+
+// IsDue tells whether the raffle is waiting to be drawn.
+func (r *Raffle) IsDue(now time.Time) bool {
+	return r.Status == RaffleStatusOpen && !now.Before(r.DrawAt)
+}
+
+// Public hides the seed until the raffle has been drawn.
+func (r Raffle) Public() Raffle {
+	if r.Status != RaffleStatusDrawn {
+		r.Seed = ""
+	}
+	return r
+}
+
+// RaffleSeedHash is the published commitment to a raffle's seed.
+func RaffleSeedHash(seed string) string {
+	sum := sha256.Sum256([]byte(seed))
+	return hex.EncodeToString(sum[:])
+}
+
+type RaffleTicket struct {
+	ID        int64     `json:"id" db:"id"`
+	RaffleID  int64     `json:"raffle_id" db:"raffle_id"`
+	UserID    int64     `json:"user_id" db:"user_id"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+type RaffleWinner struct {
+	RaffleID int64 `json:"raffle_id" db:"raffle_id"`
+	Position int64 `json:"position" db:"position"`
+	TicketID int64 `json:"ticket_id" db:"ticket_id"`
+	UserID   int64 `json:"user_id" db:"user_id"`
+
+	// Filled in when listing.
+	Username string `json:"username,omitempty" db:"username"`
+}
+
+// DrawWinners picks up to prizes winning tickets. tickets must be in ticket
+// ID order. Round i hashes "<seed>:<i>" with SHA-256 and takes the first
+// eight bytes, big-endian, modulo the number of tickets left in the pool.
+// A user wins at most once: once one of their tickets is drawn the rest
+// leave the pool.
+func DrawWinners(seed string, tickets []RaffleTicket, prizes int64) []RaffleWinner {
+	pool := append([]RaffleTicket(nil), tickets...)
+	var winners []RaffleWinner
+
+	for round := 0; int64(len(winners)) < prizes && len(pool) > 0; round++ {
+		sum := sha256.Sum256([]byte(fmt.Sprintf("%s:%d", seed, round)))
+		drawn := pool[binary.BigEndian.Uint64(sum[:8])%uint64(len(pool))]
+
+		winners = append(winners, RaffleWinner{
+			RaffleID: drawn.RaffleID,
+			Position: int64(len(winners)) + 1,
+			TicketID: drawn.ID,
+			UserID:   drawn.UserID,
+		})
+
+		rest := pool[:0]
+		for _, t := range pool {
+			if t.UserID != drawn.UserID {
+				rest = append(rest, t)
+			}
+		}
+		pool = rest
+	}
+
+	return winners
+}
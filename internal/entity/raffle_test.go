@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"testing"
+)
+
+func TestDrawWinners(t *testing.T) {
+	// Users 1 and 2 hold three tickets each, user 3 holds one.
+	var tickets []RaffleTicket
+	for i, userID := range []int64{1, 1, 1, 2, 2, 2, 3} {
+		tickets = append(tickets, RaffleTicket{ID: int64(i + 1), RaffleID: 5, UserID: userID})
+	}
+
+	first := DrawWinners("seed", tickets, 2)
+	again := DrawWinners("seed", tickets, 2)
+
+	if len(first) != 2 {
+		t.Fatalf("drew %d winners, want 2", len(first))
+	}
+	for i := range first {
+		if first[i] != again[i] {
+			t.Errorf("draw is not repeatable: %+v then %+v", first[i], again[i])
+		}
+		if first[i].Position != int64(i+1) || first[i].RaffleID != 5 {
+			t.Errorf("winner %d = %+v", i, first[i])
+		}
+	}
+	if first[0].UserID == first[1].UserID {
+		t.Errorf("user %d won twice", first[0].UserID)
+	}
+
+	all := DrawWinners("seed", tickets, 10)
+	if len(all) != 3 {
+		t.Errorf("drew %d winners among 3 users, want 3", len(all))
+	}
+
+	if got := DrawWinners("seed", nil, 2); len(got) != 0 {
+		t.Errorf("drew %d winners without tickets", len(got))
+	}
+}
+
+func TestRafflePublic(t *testing.T) {
+	r := Raffle{Status: RaffleStatusOpen, Seed: "secret", SeedHash: RaffleSeedHash("secret")}
+	if r.Public().Seed != "" {
+		t.Error("seed of an open raffle is public")
+	}
+
+	r.Status = RaffleStatusDrawn
+	if r.Public().Seed != "secret" {
+		t.Error("seed of a drawn raffle is hidden")
+	}
+}
@@ -0,0 +1,160 @@
+package entity
+
+import (
+	"sort"
+	"time"
+)
+
+// Weights of the signals a recommendation is scored with. Each signal is
+// scaled to [0, 1] first.
+const (
+	coPurchaseWeight     = 0.5
+	teamPopularityWeight = 0.3
+	affordabilityWeight  = 0.2
+)
+
+type RecommendationReason string
+
+const (
+	ReasonBoughtTogether RecommendationReason = "bought_together"
+	ReasonPopularInTeam  RecommendationReason = "popular_in_team"
+	ReasonAffordable     RecommendationReason = "affordable"
+)
+
+// CoPurchase counts the users who bought both items.
+type CoPurchase struct {
+	ItemID      int64 `db:"item_id"`
+	OtherItemID int64 `db:"other_item_id"`
+	Buyers      int64 `db:"buyers"`
+}
+
+// TeamPurchase counts the members of the team who bought the item.
+type TeamPurchase struct {
+	TeamID int64 `db:"team_id"`
+	ItemID int64 `db:"item_id"`
+	Buyers int64 `db:"buyers"`
+}
+
+// RecommendationStats is a snapshot of the purchase aggregates
+// recommendations are made from.
+type RecommendationStats struct {
+	// coPurchases[a][b] is the number of users who bought both a and b.
+	coPurchases map[int64]map[int64]int64
+	// teamBuyers[team][item] is the number of team members who bought item.
+	teamBuyers  map[int64]map[int64]int64
+	RefreshedAt time.Time
+}
+
+func NewRecommendationStats(coPurchases []CoPurchase, teamPurchases []TeamPurchase, now time.Time) *RecommendationStats {
+	s := &RecommendationStats{
+		coPurchases: make(map[int64]map[int64]int64),
+		teamBuyers:  make(map[int64]map[int64]int64),
+		RefreshedAt: now,
+	}
+	for _, c := range coPurchases {
+		if s.coPurchases[c.ItemID] == nil {
+			s.coPurchases[c.ItemID] = make(map[int64]int64)
+		}
+		s.coPurchases[c.ItemID][c.OtherItemID] = c.Buyers
+	}
+	for _, t := range teamPurchases {
+		if s.teamBuyers[t.TeamID] == nil {
+			s.teamBuyers[t.TeamID] = make(map[int64]int64)
+		}
+		s.teamBuyers[t.TeamID][t.ItemID] = t.Buyers
+	}
+	return s
+}
+
+// Recommendation suggests an item to a user along with why.
+type Recommendation struct {
+	ItemID     int64                  `json:"item_id"`
+	Name       string                 `json:"name"`
+	Price      int64                  `json:"price"`
+	Score      float64                `json:"score"`
+	Affordable bool                   `json:"affordable"`
+	Reasons    []RecommendationReason `json:"reasons"`
+}
+
+// Recommend ranks the items the user has not bought yet. Items bought
+// together with what the user owns score the most, then those popular in
+// their team, then those their balance covers; items the user cannot afford
+// yet score in proportion to how close they are. Upcoming items are left
+// out.
+func (s *RecommendationStats) Recommend(items []MerchItem, bought []int64, teamID *int64, balance int64, now time.Time, limit int) []Recommendation {
+	owned := make(map[int64]bool, len(bought))
+	for _, id := range bought {
+		owned[id] = true
+	}
+
+	var team map[int64]int64
+	if teamID != nil {
+		team = s.teamBuyers[*teamID]
+	}
+
+	type candidate struct {
+		item   MerchItem
+		co     int64
+		team   int64
+		afford float64
+	}
+
+	candidates := make([]candidate, 0, len(items))
+	var maxCo, maxTeam int64
+	for _, item := range items {
+		if owned[item.ID] || !item.IsReleased(now) {
+			continue
+		}
+
+		c := candidate{item: item, team: team[item.ID], afford: 1}
+		for _, id := range bought {
+			c.co += s.coPurchases[id][item.ID]
+		}
+		if item.Price > balance {
+			c.afford = 0
+			if item.Price > 0 && balance > 0 {
+				c.afford = float64(balance) / float64(item.Price)
+			}
+		}
+
+		maxCo = max(maxCo, c.co)
+		maxTeam = max(maxTeam, c.team)
+		candidates = append(candidates, c)
+	}
+
+	result := make([]Recommendation, 0, len(candidates))
+	for _, c := range candidates {
+		r := Recommendation{
+			ItemID:     c.item.ID,
+			Name:       c.item.Name,
+			Price:      c.item.Price,
+			Affordable: c.afford == 1,
+			Reasons:    []RecommendationReason{},
+		}
+		if c.co > 0 {
+			r.Score += coPurchaseWeight * float64(c.co) / float64(maxCo)
+			r.Reasons = append(r.Reasons, ReasonBoughtTogether)
+		}
+		if c.team > 0 {
+			r.Score += teamPopularityWeight * float64(c.team) / float64(maxTeam)
+			r.Reasons = append(r.Reasons, ReasonPopularInTeam)
+		}
+		r.Score += affordabilityWeight * c.afford
+		if r.Affordable {
+			r.Reasons = append(r.Reasons, ReasonAffordable)
+		}
+		result = append(result, r)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Score != result[j].Score {
+			return result[i].Score > result[j].Score
+		}
+		return result[i].Price < result[j].Price
+	})
+
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
@@ -0,0 +1,66 @@
+package entity
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRecommend(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	later := now.Add(time.Hour)
+	teamID := int64(7)
+
+	items := []MerchItem{
+		{ID: 1, Name: "hoody", Price: 300},
+		{ID: 2, Name: "cup", Price: 20},
+		{ID: 3, Name: "pen", Price: 10},
+		{ID: 4, Name: "console", Price: 5000},
+		{ID: 5, Name: "drone", Price: 50, ReleaseAt: &later},
+	}
+
+	stats := NewRecommendationStats(
+		[]CoPurchase{
+			{ItemID: 3, OtherItemID: 2, Buyers: 4},
+			{ItemID: 3, OtherItemID: 1, Buyers: 1},
+			{ItemID: 2, OtherItemID: 3, Buyers: 4},
+		},
+		[]TeamPurchase{
+			{TeamID: 7, ItemID: 1, Buyers: 3},
+			{TeamID: 7, ItemID: 4, Buyers: 1},
+			{TeamID: 8, ItemID: 2, Buyers: 9},
+		},
+		now,
+	)
+
+	got := stats.Recommend(items, []int64{3}, &teamID, 100, now, 0)
+
+	names := make([]string, len(got))
+	for i, r := range got {
+		names[i] = r.Name
+	}
+	if want := []string{"cup", "hoody", "console"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("Recommend = %v, want %v", names, want)
+	}
+
+	if want := []RecommendationReason{ReasonBoughtTogether, ReasonAffordable}; !reflect.DeepEqual(got[0].Reasons, want) {
+		t.Errorf("cup reasons = %v, want %v", got[0].Reasons, want)
+	}
+	if want := []RecommendationReason{ReasonBoughtTogether, ReasonPopularInTeam}; !reflect.DeepEqual(got[1].Reasons, want) {
+		t.Errorf("hoody reasons = %v, want %v", got[1].Reasons, want)
+	}
+	if got[1].Affordable {
+		t.Errorf("hoody is affordable with a balance of 100")
+	}
+
+	limited := stats.Recommend(items, []int64{3}, &teamID, 100, now, 1)
+	if len(limited) != 1 || limited[0].Name != "cup" {
+		t.Errorf("Recommend with limit 1 = %v, want only cup", limited)
+	}
+
+	// without history or a team only affordability is left to rank by
+	fresh := stats.Recommend(items, nil, nil, 100, now, 0)
+	if fresh[0].Name != "pen" || fresh[len(fresh)-1].Name != "console" {
+		t.Errorf("Recommend for a new user = %v", fresh)
+	}
+}
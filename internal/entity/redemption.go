@@ -0,0 +1,79 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+type RedemptionMethod string
+
+const (
+	RedemptionMethodDelivery RedemptionMethod = "delivery"
+	RedemptionMethodPickup   RedemptionMethod = "pickup"
+)
+
+type RedemptionStatus string
+
+const (
+	RedemptionStatusPending   RedemptionStatus = "pending"
+	RedemptionStatusFulfilled RedemptionStatus = "fulfilled"
+	RedemptionStatusCancelled RedemptionStatus = "cancelled"
+)
+
+// Redemption is a claim for items taken out of a user's inventory, to be
+// shipped to an address or handed out at a pickup location.
+type Redemption struct {
+	ID         int64            `json:"id" db:"id"`
+	UserID     int64            `json:"user_id" db:"user_id"`
+	ItemID     int64            `json:"item_id" db:"item_id"`
+	VariantID  *int64           `json:"variant_id,omitempty" db:"variant_id"`
+	Quantity   int64            `json:"quantity" db:"quantity"`
+	Method     RedemptionMethod `json:"method" db:"method"`
+	Address    string           `json:"address,omitempty" db:"address"`
+	LocationID *int64           `json:"location_id,omitempty" db:"location_id"`
+	Status     RedemptionStatus `json:"status" db:"status"`
+	ResolvedBy *int64           `json:"resolved_by,omitempty" db:"resolved_by"`
+	ResolvedAt *time.Time       `json:"resolved_at,omitempty" db:"resolved_at"`
+	CreatedAt  time.Time        `json:"created_at" db:"created_at"`
+
+	// Filled in when listing, for pick lists.
+	Username string `json:"username,omitempty" db:"username"`
+	ItemName string `json:"item_name,omitempty" db:"item_name"`
+	Variant  string `json:"variant,omitempty" db:"variant"`
+	Location string `json:"location,omitempty" db:"location"`
+}
+
+// Validate checks the redemption names where the items should go.
+func (r *Redemption) Validate() error {
+	if r.Quantity <= 0 {
+		return ErrInvalidQuantity
+	}
+
+	r.Address = strings.TrimSpace(r.Address)
+	switch r.Method {
+	case RedemptionMethodDelivery:
+		if r.Address == "" {
+			return ErrAddressRequired
+		}
+		r.LocationID = nil
+	case RedemptionMethodPickup:
+		if r.LocationID == nil {
+			return ErrPickupLocationRequired
+		}
+		r.Address = ""
+	default:
+		return ErrInvalidRedemptionMethod
+	}
+	return nil
+}
+
+// Resolve closes a pending redemption as fulfilled or cancelled.
+func (r *Redemption) Resolve(status RedemptionStatus, actorID int64, now time.Time) error {
+	if r.Status != RedemptionStatusPending {
+		return ErrRedemptionNotPending
+	}
+	r.Status = status
+	r.ResolvedBy = &actorID
+	r.ResolvedAt = &now
+	return nil
+}
@@ -0,0 +1,36 @@
+package entity
+
+import "time"
+
+// RewardBudget is the replenishing pool a manager awards coins from. It is
+// kept apart from the manager's own balance and refilled to Allocation at
+// the start of every period.
+type RewardBudget struct {
+	UserID     int64     `json:"user_id" db:"user_id"`
+	Allocation int64     `json:"allocation" db:"allocation"`
+	Balance    int64     `json:"balance" db:"balance"`
+	Period     string    `json:"period" db:"period"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Available returns what can still be awarded in period. A budget that has
+// not been reset for period yet is treated as already refilled.
+func (b *RewardBudget) Available(period string) int64 {
+	if b.Period != period {
+		return b.Allocation
+	}
+	return b.Balance
+}
+
+// MaxRewardReasonLength is the longest reason a reward can be stored with.
+const MaxRewardReasonLength = 255
+
+type Reward struct {
+	ID        int64     `json:"id" db:"id"`
+	ManagerID int64     `json:"manager_id" db:"manager_id"`
+	UserID    int64     `json:"user_id" db:"user_id"`
+	Amount    int64     `json:"amount" db:"amount"`
+	Reason    string    `json:"reason" db:"reason"`
+	Period    string    `json:"period" db:"period"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
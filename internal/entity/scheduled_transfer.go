@@ -0,0 +1,97 @@
+package entity
+
+import "time"
+
+type Recurrence string
+
+const (
+	RecurrenceNone    Recurrence = ""
+	RecurrenceDaily   Recurrence = "daily"
+	RecurrenceWeekly  Recurrence = "weekly"
+	RecurrenceMonthly Recurrence = "monthly"
+)
+
+func (r Recurrence) IsValid() bool {
+	switch r {
+	case RecurrenceNone, RecurrenceDaily, RecurrenceWeekly, RecurrenceMonthly:
+		return true
+	default:
+		return false
+	}
+}
+
+// Next returns the occurrence following t, or the zero time for a one-off
+// transfer. Monthly occurrences fall on the day of the month of first, or on
+// the last day of shorter months, so a schedule started on the 31st does not
+// drift into the next month.
+func (r Recurrence) Next(first, t time.Time) time.Time {
+	switch r {
+	case RecurrenceDaily:
+		return t.AddDate(0, 0, 1)
+	case RecurrenceWeekly:
+		return t.AddDate(0, 0, 7)
+	case RecurrenceMonthly:
+		year, month, _ := t.Date()
+		lastDay := time.Date(year, month+2, 0, 0, 0, 0, 0, t.Location()).Day()
+		day := min(first.Day(), lastDay)
+		return time.Date(year, month+1, day, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	default:
+		return time.Time{}
+	}
+}
+
+type ScheduledTransferStatus string
+
+const (
+	ScheduledTransferStatusActive    ScheduledTransferStatus = "active"
+	ScheduledTransferStatusCompleted ScheduledTransferStatus = "completed"
+	ScheduledTransferStatusCancelled ScheduledTransferStatus = "cancelled"
+)
+
+type ScheduledTransfer struct {
+	ID         int64                   `json:"id" db:"id"`
+	FromUserID int64                   `json:"from_user_id" db:"from_user_id"`
+	ToUserID   int64                   `json:"to_user_id" db:"to_user_id"`
+	Amount     int64                   `json:"amount" db:"amount"`
+	Message    *string                 `json:"message,omitempty" db:"message"`
+	Recurrence Recurrence              `json:"recurrence" db:"recurrence"`
+	FirstRunAt time.Time               `json:"first_run_at" db:"first_run_at"`
+	NextRunAt  time.Time               `json:"next_run_at" db:"next_run_at"`
+	Status     ScheduledTransferStatus `json:"status" db:"status"`
+	CreatedAt  time.Time               `json:"created_at" db:"created_at"`
+}
+
+// Advance moves the schedule past now. Occurrences missed while the worker
+// was down are skipped rather than paid in a burst; a one-off transfer is
+// completed.
+func (s *ScheduledTransfer) Advance(now time.Time) {
+	next := s.Recurrence.Next(s.FirstRunAt, s.NextRunAt)
+	if next.IsZero() {
+		s.Status = ScheduledTransferStatusCompleted
+		return
+	}
+
+	for !next.After(now) {
+		next = s.Recurrence.Next(s.FirstRunAt, next)
+	}
+	s.NextRunAt = next
+}
+
+type ScheduledTransferRunStatus string
+
+const (
+	ScheduledTransferRunSucceeded ScheduledTransferRunStatus = "succeeded"
+	ScheduledTransferRunFailed    ScheduledTransferRunStatus = "failed"
+)
+
+// ScheduledTransferRun records one occurrence of a scheduled transfer. The
+// pair (ScheduledTransferID, RunAt) is unique, which makes executing an
+// occurrence idempotent.
+type ScheduledTransferRun struct {
+	ID                  int64                      `json:"id" db:"id"`
+	ScheduledTransferID int64                      `json:"scheduled_transfer_id" db:"scheduled_transfer_id"`
+	RunAt               time.Time                  `json:"run_at" db:"run_at"`
+	Status              ScheduledTransferRunStatus `json:"status" db:"status"`
+	FailureReason       *string                    `json:"failure_reason,omitempty" db:"failure_reason"`
+	CreatedAt           time.Time                  `json:"created_at" db:"created_at"`
+}
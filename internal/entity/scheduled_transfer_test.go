@@ -0,0 +1,74 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduledTransferAdvance(t *testing.T) {
+	start := time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		recurrence Recurrence
+		now        time.Time
+		wantNext   time.Time
+		wantStatus ScheduledTransferStatus
+	}{
+		{
+			name:       "one-off completes",
+			recurrence: RecurrenceNone,
+			now:        start,
+			wantNext:   start,
+			wantStatus: ScheduledTransferStatusCompleted,
+		},
+		{
+			name:       "daily moves one day",
+			recurrence: RecurrenceDaily,
+			now:        start,
+			wantNext:   start.AddDate(0, 0, 1),
+			wantStatus: ScheduledTransferStatusActive,
+		},
+		{
+			name:       "missed occurrences are skipped",
+			recurrence: RecurrenceWeekly,
+			now:        start.AddDate(0, 0, 20),
+			wantNext:   start.AddDate(0, 0, 21),
+			wantStatus: ScheduledTransferStatusActive,
+		},
+		{
+			name:       "monthly keeps to the end of the month",
+			recurrence: RecurrenceMonthly,
+			now:        time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+			wantNext:   time.Date(2026, 3, 31, 9, 0, 0, 0, time.UTC),
+			wantStatus: ScheduledTransferStatusActive,
+		},
+		{
+			name:       "monthly is clamped to a short month",
+			recurrence: RecurrenceMonthly,
+			now:        start,
+			wantNext:   time.Date(2026, 2, 28, 9, 0, 0, 0, time.UTC),
+			wantStatus: ScheduledTransferStatusActive,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			st := ScheduledTransfer{
+				Recurrence: tc.recurrence,
+				FirstRunAt: start,
+				NextRunAt:  start,
+				Status:     ScheduledTransferStatusActive,
+			}
+
+			st.Advance(tc.now)
+
+			if !st.NextRunAt.Equal(tc.wantNext) {
+				t.Errorf("NextRunAt mismatch: got %v want %v", st.NextRunAt, tc.wantNext)
+			}
+			if st.Status != tc.wantStatus {
+				t.Errorf("Status mismatch: got %v want %v", st.Status, tc.wantStatus)
+			}
+		})
+	}
+}
@@ -7,8 +7,24 @@ type TransactionType string
 const (
 	TransactionTypeTransfer TransactionType = "transfer"
 	TransactionTypePurchase TransactionType = "purchase"
+	TransactionTypeGrant    TransactionType = "grant"
+	TransactionTypeReward   TransactionType = "reward"
+	TransactionTypeExpiry   TransactionType = "expiry"
+	TransactionTypeRefund   TransactionType = "refund"
+
+	TransactionTypeItemTransfer TransactionType = "item_transfer"
+	TransactionTypeMarketSale   TransactionType = "market_sale"
+	TransactionTypeMarketFee    TransactionType = "market_fee"
+	TransactionTypeRaffleTicket TransactionType = "raffle_ticket"
 )
 
+// MaxTransferMessageLength limits the note a sender can attach to a transfer.
+const MaxTransferMessageLength = 280
+
+// Transaction is a ledger entry. For purchases Amount is what the buyer
+// paid and ListPrice what the item cost before discounts. Item transfers
+// move no coins: Amount is zero and Quantity says how many items changed
+// hands.
 type Transaction struct {
 	ID         int64           `json:"id" db:"id"`
 	FromUserID int64           `json:"from_user_id" db:"from_user_id"`
@@ -16,5 +32,9 @@ type Transaction struct {
 	Amount     int64           `json:"amount" db:"amount"`
 	Type       TransactionType `json:"type" db:"type"`
 	ItemID     *int64          `json:"item_id,omitempty" db:"item_id"`
+	BundleID   *int64          `json:"bundle_id,omitempty" db:"bundle_id"`
+	ListPrice  *int64          `json:"list_price,omitempty" db:"list_price"`
+	Quantity   *int64          `json:"quantity,omitempty" db:"quantity"`
+	Message    *string         `json:"message,omitempty" db:"message"`
 	CreatedAt  time.Time       `json:"created_at" db:"created_at"`
 }
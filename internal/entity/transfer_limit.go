@@ -0,0 +1,38 @@
+package entity
+
+import "time"
+
+// TransferLimitWindow is the rolling window daily and per-recipient limits
+// are counted over.
+const TransferLimitWindow = 24 * time.Hour
+
+// TransferLimits caps outgoing transfers. A zero value means no limit.
+type TransferLimits struct {
+	PerTransfer  int64 `json:"per_transfer"`
+	Daily        int64 `json:"daily"`
+	PerRecipient int64 `json:"per_recipient"`
+}
+
+// TransferLimitOverride replaces the configured limits for a single user.
+// Nil fields fall back to the defaults.
+type TransferLimitOverride struct {
+	UserID       int64     `json:"user_id" db:"user_id"`
+	PerTransfer  *int64    `json:"per_transfer,omitempty" db:"per_transfer"`
+	Daily        *int64    `json:"daily,omitempty" db:"daily"`
+	PerRecipient *int64    `json:"per_recipient,omitempty" db:"per_recipient"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Apply returns the limits with the override's fields taking precedence.
+func (o TransferLimitOverride) Apply(limits TransferLimits) TransferLimits {
+	if o.PerTransfer != nil {
+		limits.PerTransfer = *o.PerTransfer
+	}
+	if o.Daily != nil {
+		limits.Daily = *o.Daily
+	}
+	if o.PerRecipient != nil {
+		limits.PerRecipient = *o.PerRecipient
+	}
+	return limits
+}
@@ -4,6 +4,19 @@ import "time"
 
 const (
 	InitialBalance int64 = 1000
+
+	// SystemUsername is the account coins are minted from. It is created by
+	// migrations and never holds a balance of its own.
+	SystemUsername = "system"
+)
+
+type UserRole string
+
+const (
+	UserRoleUser    UserRole = "user"
+	UserRoleAdmin   UserRole = "admin"
+	UserRoleManager UserRole = "manager"
+	UserRoleSystem  UserRole = "system"
 )
 
 type User struct {
@@ -11,5 +24,45 @@ type User struct {
 	Username     string    `json:"username" db:"username"`
 	PasswordHash string    `json:"-" db:"password_hash"`
 	Coins        int64     `json:"coins" db:"coins"`
+	HeldCoins    int64     `json:"held_coins" db:"held_coins"`
+	Role         UserRole  `json:"role" db:"role"`
+	TeamID       *int64    `json:"team_id,omitempty" db:"team_id"`
+	ManagerID    *int64    `json:"manager_id,omitempty" db:"manager_id"`
+	PublicKudos  bool      `json:"public_kudos" db:"public_kudos"`
 	CreatedAt    time.Time `json:"created_at" db:"created_at"`
 }
+
+func (u *User) IsAdmin() bool {
+	return u.Role == UserRoleAdmin
+}
+
+func (u *User) IsManager() bool {
+	return u.Role == UserRoleManager
+}
+
+// Hold reserves amount of the user's coins. Held coins are no longer
+// spendable but still belong to the user until captured or released.
+func (u *User) Hold(amount int64) error {
+	if u.Coins < amount {
+		return ErrInsufficientFunds
+	}
+	u.Coins -= amount
+	u.HeldCoins += amount
+	return nil
+}
+
+// Release returns previously held coins to the spendable balance.
+func (u *User) Release(amount int64) {
+	u.HeldCoins -= amount
+	u.Coins += amount
+}
+
+// Capture takes previously held coins out of the user's account for good.
+func (u *User) Capture(amount int64) {
+	u.HeldCoins -= amount
+}
+
+// ReportsTo tells whether managerID is the user's direct manager.
+func (u *User) ReportsTo(managerID int64) bool {
+	return u.ManagerID != nil && *u.ManagerID == managerID
+}
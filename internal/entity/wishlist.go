@@ -0,0 +1,52 @@
+package entity
+
+import "time"
+
+type WishlistEvent string
+
+const (
+	WishlistEventPriceDrop  WishlistEvent = "price_drop"
+	WishlistEventRestock    WishlistEvent = "restock"
+	WishlistEventAffordable WishlistEvent = "affordable"
+)
+
+// WishlistState is what a wished item looks like to the user at some
+// moment: the price they would pay, whether it can be bought and whether
+// their balance covers it.
+type WishlistState struct {
+	Price      int64 `json:"price" db:"last_price"`
+	InStock    bool  `json:"in_stock" db:"in_stock"`
+	Affordable bool  `json:"affordable" db:"affordable"`
+}
+
+// WishlistItem is an item the user wants. VariantID narrows it to one
+// variant; without it any variant in stock will do. The embedded state is
+// the one last seen, so only changes are reported.
+type WishlistItem struct {
+	ID        int64  `json:"id" db:"id"`
+	UserID    int64  `json:"user_id" db:"user_id"`
+	ItemID    int64  `json:"item_id" db:"item_id"`
+	VariantID *int64 `json:"variant_id,omitempty" db:"variant_id"`
+	WishlistState
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+
+	// Filled in when listing.
+	ItemName string `json:"item_name,omitempty" db:"item_name"`
+}
+
+// Events compares the last seen state with the current one. A price drop
+// is reported whenever the price goes down; a restock and becoming
+// affordable only when the item was not in stock or affordable before.
+func (w WishlistItem) Events(current WishlistState) []WishlistEvent {
+	var events []WishlistEvent
+	if current.Price < w.Price {
+		events = append(events, WishlistEventPriceDrop)
+	}
+	if current.InStock && !w.InStock {
+		events = append(events, WishlistEventRestock)
+	}
+	if current.Affordable && !w.Affordable {
+		events = append(events, WishlistEventAffordable)
+	}
+	return events
+}
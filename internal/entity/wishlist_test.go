@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWishlistItemEvents(t *testing.T) {
+	seen := WishlistItem{WishlistState: WishlistState{Price: 500, InStock: false, Affordable: false}}
+
+	tests := []struct {
+		name    string
+		current WishlistState
+		want    []WishlistEvent
+	}{
+		{"nothing changed", WishlistState{Price: 500}, nil},
+		{"price went up", WishlistState{Price: 600}, nil},
+		{"price dropped", WishlistState{Price: 400}, []WishlistEvent{WishlistEventPriceDrop}},
+		{"restocked", WishlistState{Price: 500, InStock: true}, []WishlistEvent{WishlistEventRestock}},
+		{
+			"everything at once",
+			WishlistState{Price: 300, InStock: true, Affordable: true},
+			[]WishlistEvent{WishlistEventPriceDrop, WishlistEventRestock, WishlistEventAffordable},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := seen.Events(tc.current)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Events = %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	still := WishlistItem{WishlistState: WishlistState{Price: 500, InStock: true, Affordable: true}}
+	if got := still.Events(WishlistState{Price: 500, InStock: true, Affordable: true}); got != nil {
+		t.Errorf("Events = %v, want none for an unchanged item", got)
+	}
+}
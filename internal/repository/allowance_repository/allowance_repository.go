@@ -0,0 +1,84 @@
+package allowance_repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/repository"
+)
+
+type dbConn interface {
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type AllowanceRepository struct {
+	db dbConn
+}
+
+func NewAllowanceRepository(db *sqlx.DB) *AllowanceRepository {
+	return &AllowanceRepository{
+		db: db,
+	}
+}
+
+func (r *AllowanceRepository) WithTx(tx *sqlx.Tx) *AllowanceRepository {
+	return &AllowanceRepository{
+		db: tx,
+	}
+}
+
+// conn returns the transaction ctx carries, or the repository's own
+// connection outside of one.
+func (r *AllowanceRepository) conn(ctx context.Context) dbConn {
+	if tx, ok := repository.TxFromContext(ctx); ok {
+		return tx
+	}
+	return r.db
+}
+
+// Claim records the payout for its user and period. It reports false without
+// an error when the period has already been paid, which is what keeps a
+// restarted scheduler from paying twice.
+func (r *AllowanceRepository) Claim(ctx context.Context, payout *entity.AllowancePayout) (bool, error) {
+	query := `
+  INSERT INTO allowance_payouts (user_id, period, amount)
+  VALUES ($1, $2, $3)
+  ON CONFLICT ON CONSTRAINT unique_user_period DO NOTHING
+  RETURNING id, created_at`
+
+	err := r.conn(ctx).QueryRowContext(
+		ctx,
+		query,
+		payout.UserID,
+		payout.Period,
+		payout.Amount,
+	).Scan(&payout.ID, &payout.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to claim allowance payout: %w", err)
+	}
+
+	return true, nil
+}
+
+func (r *AllowanceRepository) GetByPeriod(ctx context.Context, period string) ([]entity.AllowancePayout, error) {
+	query := `
+  SELECT id, user_id, period, amount, created_at
+  FROM allowance_payouts
+  WHERE period = $1
+  ORDER BY id`
+
+	var payouts []entity.AllowancePayout
+	err := r.conn(ctx).SelectContext(ctx, &payouts, query, period)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get allowance payouts by period: %w", err)
+	}
+
+	return payouts, nil
+}
@@ -0,0 +1,110 @@
+package allowance_repository
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/testutils"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type AllowanceRepositoryTestSuite struct {
+	suite.Suite
+	db   *sqlx.DB
+	repo *AllowanceRepository
+}
+
+func (s *AllowanceRepositoryTestSuite) SetupSuite() {
+	db, err := testutils.GetTestDB()
+	require.NoError(s.T(), err)
+
+	s.db = db
+	s.repo = NewAllowanceRepository(db)
+
+	s.recreateTables()
+}
+
+func (s *AllowanceRepositoryTestSuite) SetupTest() {
+	_, err := s.db.Exec("TRUNCATE TABLE allowance_payouts, users RESTART IDENTITY CASCADE")
+	require.NoError(s.T(), err)
+
+	_, err = s.db.Exec(`
+  INSERT INTO users (username, password_hash, coins)
+  VALUES
+  ('user1', 'hash1', 1000),
+  ('user2', 'hash2', 1000)`)
+	require.NoError(s.T(), err)
+}
+
+func (s *AllowanceRepositoryTestSuite) TearDownSuite() {
+	s.db.Close()
+}
+
+func (s *AllowanceRepositoryTestSuite) recreateTables() {
+	_, err := s.db.Exec(`
+  DROP TABLE IF EXISTS allowance_payouts;
+  DROP TABLE IF EXISTS users CASCADE;
+
+  CREATE TABLE users (
+   id SERIAL PRIMARY KEY,
+   username VARCHAR(255) UNIQUE NOT NULL,
+   password_hash VARCHAR(255) NOT NULL,
+   coins INTEGER NOT NULL DEFAULT 1000,
+   role VARCHAR(50) NOT NULL DEFAULT 'user',
+   team_id INTEGER,
+   manager_id INTEGER,
+   public_kudos BOOLEAN NOT NULL DEFAULT FALSE,
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+
+  CREATE TABLE allowance_payouts (
+   id SERIAL PRIMARY KEY,
+   user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+   period VARCHAR(7) NOT NULL,
+   amount INTEGER NOT NULL CHECK (amount > 0),
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+   CONSTRAINT unique_user_period UNIQUE(user_id, period)
+  );
+ `)
+	require.NoError(s.T(), err)
+}
+
+func (s *AllowanceRepositoryTestSuite) TestClaim() {
+	ctx := context.Background()
+
+	s.Run("first claim in period", func() {
+		payout := entity.AllowancePayout{UserID: 1, Period: "2026-10", Amount: 100}
+
+		claimed, err := s.repo.Claim(ctx, &payout)
+		s.NoError(err)
+		s.True(claimed)
+		s.NotZero(payout.ID)
+	})
+
+	s.Run("repeated claim in same period", func() {
+		payout := entity.AllowancePayout{UserID: 1, Period: "2026-10", Amount: 100}
+
+		claimed, err := s.repo.Claim(ctx, &payout)
+		s.NoError(err)
+		s.False(claimed)
+	})
+
+	s.Run("next period", func() {
+		payout := entity.AllowancePayout{UserID: 1, Period: "2026-11", Amount: 100}
+
+		claimed, err := s.repo.Claim(ctx, &payout)
+		s.NoError(err)
+		s.True(claimed)
+	})
+
+	payouts, err := s.repo.GetByPeriod(ctx, "2026-10")
+	s.NoError(err)
+	s.Len(payouts, 1)
+}
+
+func TestAllowanceRepository(t *testing.T) {
+	suite.Run(t, new(AllowanceRepositoryTestSuite))
+}
@@ -0,0 +1,180 @@
+package auction_repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/repository"
+	"time"
+)
+
+const selectAuctions = `
+  SELECT a.id, a.item_id, a.start_price, a.min_increment, a.starts_at, a.ends_at, a.status,
+   a.high_bidder_id, a.high_bid, a.created_by, a.closed_at, a.created_at,
+   m.name AS item_name
+  FROM auctions a
+  JOIN merch_items m ON m.id = a.item_id`
+
+type dbConn interface {
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type AuctionRepository struct {
+	db dbConn
+}
+
+func NewAuctionRepository(db *sqlx.DB) *AuctionRepository {
+	return &AuctionRepository{
+		db: db,
+	}
+}
+
+func (r *AuctionRepository) WithTx(tx *sqlx.Tx) *AuctionRepository {
+	return &AuctionRepository{
+		db: tx,
+	}
+}
+
+// conn returns the transaction ctx carries, or the repository's own
+// connection outside of one.
+func (r *AuctionRepository) conn(ctx context.Context) dbConn {
+	if tx, ok := repository.TxFromContext(ctx); ok {
+		return tx
+	}
+	return r.db
+}
+
+func (r *AuctionRepository) Create(ctx context.Context, auction *entity.Auction) error {
+	if auction.Status == "" {
+		auction.Status = entity.AuctionStatusOpen
+	}
+
+	query := `
+  INSERT INTO auctions (item_id, start_price, min_increment, starts_at, ends_at, status, created_by)
+  VALUES ($1, $2, $3, $4, $5, $6, $7)
+  RETURNING id, created_at`
+
+	err := r.conn(ctx).QueryRowContext(
+		ctx,
+		query,
+		auction.ItemID,
+		auction.StartPrice,
+		auction.MinIncrement,
+		auction.StartsAt,
+		auction.EndsAt,
+		auction.Status,
+		auction.CreatedBy,
+	).Scan(&auction.ID, &auction.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create auction: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID locks the auction for the rest of the transaction. Bids and the
+// closer both go through it, so they are applied one at a time.
+func (r *AuctionRepository) GetByID(ctx context.Context, id int64) (entity.Auction, error) {
+	var auction entity.Auction
+	query := selectAuctions + `
+  WHERE a.id = $1
+  FOR UPDATE OF a`
+
+	err := r.conn(ctx).GetContext(ctx, &auction, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Auction{}, entity.ErrAuctionNotFound
+		}
+		return entity.Auction{}, fmt.Errorf("failed to get auction: %w", err)
+	}
+
+	return auction, nil
+}
+
+// ListOpen returns the auctions that have not been closed yet, the ones
+// ending soonest first.
+func (r *AuctionRepository) ListOpen(ctx context.Context) ([]entity.Auction, error) {
+	var auctions []entity.Auction
+	query := selectAuctions + `
+  WHERE a.status = 'open'
+  ORDER BY a.ends_at, a.id`
+
+	err := r.conn(ctx).SelectContext(ctx, &auctions, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list open auctions: %w", err)
+	}
+
+	return auctions, nil
+}
+
+func (r *AuctionRepository) Update(ctx context.Context, auction entity.Auction) error {
+	query := `
+  UPDATE auctions
+  SET status = $2, high_bidder_id = $3, high_bid = $4, closed_at = $5
+  WHERE id = $1`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, auction.ID, auction.Status, auction.HighBidderID, auction.HighBid, auction.ClosedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update auction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AuctionRepository) AddBid(ctx context.Context, bid *entity.AuctionBid) error {
+	query := `
+  INSERT INTO auction_bids (auction_id, user_id, amount)
+  VALUES ($1, $2, $3)
+  RETURNING id, created_at`
+
+	err := r.conn(ctx).QueryRowContext(ctx, query, bid.AuctionID, bid.UserID, bid.Amount).Scan(&bid.ID, &bid.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add auction bid: %w", err)
+	}
+
+	return nil
+}
+
+// ListBids returns the auction's bids, highest first.
+func (r *AuctionRepository) ListBids(ctx context.Context, auctionID int64) ([]entity.AuctionBid, error) {
+	var bids []entity.AuctionBid
+	query := `
+  SELECT b.id, b.auction_id, b.user_id, b.amount, b.created_at, u.username
+  FROM auction_bids b
+  JOIN users u ON u.id = b.user_id
+  WHERE b.auction_id = $1
+  ORDER BY b.amount DESC, b.id`
+
+	err := r.conn(ctx).SelectContext(ctx, &bids, query, auctionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list auction bids: %w", err)
+	}
+
+	return bids, nil
+}
+
+// ListDue returns the IDs of open auctions that ended by now, oldest first.
+func (r *AuctionRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]int64, error) {
+	var ids []int64
+	query := `
+  SELECT id
+  FROM auctions
+  WHERE status = 'open'
+   AND ends_at <= $1
+  ORDER BY ends_at, id
+  LIMIT $2`
+
+	err := r.conn(ctx).SelectContext(ctx, &ids, query, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due auctions: %w", err)
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,136 @@
+package auction_repository
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/testutils"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type AuctionRepositoryTestSuite struct {
+	suite.Suite
+	db   *sqlx.DB
+	repo *AuctionRepository
+}
+
+func (s *AuctionRepositoryTestSuite) SetupSuite() {
+	db, err := testutils.GetTestDB()
+	require.NoError(s.T(), err)
+
+	s.db = db
+	s.repo = NewAuctionRepository(db)
+
+	s.recreateTables()
+}
+
+func (s *AuctionRepositoryTestSuite) SetupTest() {
+	_, err := s.db.Exec("TRUNCATE TABLE auction_bids, auctions, merch_items, users RESTART IDENTITY CASCADE")
+	require.NoError(s.T(), err)
+
+	_, err = s.db.Exec(`
+  INSERT INTO users (username, password_hash, coins) VALUES ('admin', 'hash1', 1000), ('user1', 'hash2', 1000);
+  INSERT INTO merch_items (name, price) VALUES ('golden-hoody', 1000)`)
+	require.NoError(s.T(), err)
+}
+
+func (s *AuctionRepositoryTestSuite) TearDownSuite() {
+	s.db.Close()
+}
+
+func (s *AuctionRepositoryTestSuite) recreateTables() {
+	_, err := s.db.Exec(`
+  DROP TABLE IF EXISTS auction_bids;
+  DROP TABLE IF EXISTS auctions;
+  DROP TABLE IF EXISTS merch_items CASCADE;
+  DROP TABLE IF EXISTS users CASCADE;
+
+  CREATE TABLE users (
+   id SERIAL PRIMARY KEY,
+   username VARCHAR(255) UNIQUE NOT NULL,
+   password_hash VARCHAR(255) NOT NULL,
+   coins INTEGER NOT NULL DEFAULT 1000,
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+
+  CREATE TABLE merch_items (
+   id SERIAL PRIMARY KEY,
+   name VARCHAR(255) UNIQUE NOT NULL,
+   price INTEGER NOT NULL CHECK (price > 0),
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+
+  CREATE TABLE auctions (
+   id SERIAL PRIMARY KEY,
+   item_id INTEGER NOT NULL REFERENCES merch_items(id),
+   start_price INTEGER NOT NULL,
+   min_increment INTEGER NOT NULL DEFAULT 1,
+   starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+   ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+   status VARCHAR(20) NOT NULL DEFAULT 'open',
+   high_bidder_id INTEGER REFERENCES users(id),
+   high_bid INTEGER NOT NULL DEFAULT 0,
+   created_by INTEGER NOT NULL REFERENCES users(id),
+   closed_at TIMESTAMP WITH TIME ZONE,
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+
+  CREATE TABLE auction_bids (
+   id SERIAL PRIMARY KEY,
+   auction_id INTEGER NOT NULL REFERENCES auctions(id) ON DELETE CASCADE,
+   user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+   amount INTEGER NOT NULL,
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+ `)
+	require.NoError(s.T(), err)
+}
+
+func (s *AuctionRepositoryTestSuite) TestLifecycle() {
+	ctx := context.Background()
+	now := time.Now()
+
+	ended := entity.Auction{ItemID: 1, StartPrice: 100, MinIncrement: 10, StartsAt: now.Add(-2 * time.Hour), EndsAt: now.Add(-time.Hour), CreatedBy: 1}
+	running := entity.Auction{ItemID: 1, StartPrice: 100, MinIncrement: 10, StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour), CreatedBy: 1}
+	s.NoError(s.repo.Create(ctx, &ended))
+	s.NoError(s.repo.Create(ctx, &running))
+	s.Equal(entity.AuctionStatusOpen, running.Status)
+
+	auction, err := s.repo.GetByID(ctx, running.ID)
+	s.NoError(err)
+	s.Equal("golden-hoody", auction.ItemName)
+	s.NoError(auction.Bid(2, 120, now))
+	s.NoError(s.repo.Update(ctx, auction))
+	s.NoError(s.repo.AddBid(ctx, &entity.AuctionBid{AuctionID: auction.ID, UserID: 2, Amount: 100}))
+	s.NoError(s.repo.AddBid(ctx, &entity.AuctionBid{AuctionID: auction.ID, UserID: 2, Amount: 120}))
+
+	auction, err = s.repo.GetByID(ctx, running.ID)
+	s.NoError(err)
+	s.Equal(int64(2), *auction.HighBidderID)
+	s.Equal(int64(120), auction.HighBid)
+
+	bids, err := s.repo.ListBids(ctx, running.ID)
+	s.NoError(err)
+	s.Require().Len(bids, 2)
+	s.Equal(int64(120), bids[0].Amount)
+	s.Equal("user1", bids[0].Username)
+
+	due, err := s.repo.ListDue(ctx, now, 10)
+	s.NoError(err)
+	s.Equal([]int64{ended.ID}, due)
+
+	open, err := s.repo.ListOpen(ctx)
+	s.NoError(err)
+	s.Len(open, 2)
+
+	_, err = s.repo.GetByID(ctx, 100)
+	s.ErrorIs(err, entity.ErrAuctionNotFound)
+}
+
+func TestAuctionRepository(t *testing.T) {
+	suite.Run(t, new(AuctionRepositoryTestSuite))
+}
@@ -0,0 +1,201 @@
+package coin_lot_repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/repository"
+	"time"
+)
+
+type dbConn interface {
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type CoinLotRepository struct {
+	db dbConn
+}
+
+func NewCoinLotRepository(db *sqlx.DB) *CoinLotRepository {
+	return &CoinLotRepository{
+		db: db,
+	}
+}
+
+func (r *CoinLotRepository) WithTx(tx *sqlx.Tx) *CoinLotRepository {
+	return &CoinLotRepository{
+		db: tx,
+	}
+}
+
+// conn returns the transaction ctx carries, or the repository's own
+// connection outside of one.
+func (r *CoinLotRepository) conn(ctx context.Context) dbConn {
+	if tx, ok := repository.TxFromContext(ctx); ok {
+		return tx
+	}
+	return r.db
+}
+
+func (r *CoinLotRepository) Create(ctx context.Context, lot *entity.CoinLot) error {
+	query := `
+  INSERT INTO coin_lots (user_id, amount, remaining, granted_at, expires_at)
+  VALUES ($1, $2, $3, $4, $5)
+  RETURNING id, created_at`
+
+	err := r.conn(ctx).QueryRowContext(
+		ctx,
+		query,
+		lot.UserID,
+		lot.Amount,
+		lot.Remaining,
+		lot.GrantedAt,
+		lot.ExpiresAt,
+	).Scan(&lot.ID, &lot.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create coin lot: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID locks the lot row until the surrounding transaction ends.
+func (r *CoinLotRepository) GetByID(ctx context.Context, id int64) (entity.CoinLot, error) {
+	var lot entity.CoinLot
+	query := `
+  SELECT id, user_id, amount, remaining, granted_at, expires_at, created_at
+  FROM coin_lots
+  WHERE id = $1
+  FOR UPDATE`
+
+	err := r.conn(ctx).GetContext(ctx, &lot, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.CoinLot{}, entity.ErrCoinLotNotFound
+		}
+		return entity.CoinLot{}, fmt.Errorf("failed to get coin lot by id: %w", err)
+	}
+
+	return lot, nil
+}
+
+func (r *CoinLotRepository) Update(ctx context.Context, lot entity.CoinLot) error {
+	query := `
+  UPDATE coin_lots
+  SET remaining = $1
+  WHERE id = $2`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, lot.Remaining, lot.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update coin lot: %w", err)
+	}
+
+	return nil
+}
+
+// Spend takes amount coins from the user's lots, oldest first, and returns
+// the portions taken so they can be passed on with Credit. The lots stay
+// locked until the surrounding transaction ends.
+//
+// Coins not covered by any lot never expire; they are taken last and
+// returned as a portion without an ID.
+func (r *CoinLotRepository) Spend(ctx context.Context, userID int64, amount int64) ([]entity.CoinLot, error) {
+	query := `
+  SELECT id, user_id, amount, remaining, granted_at, expires_at, created_at
+  FROM coin_lots
+  WHERE user_id = $1 AND remaining > 0
+  ORDER BY granted_at, id
+  FOR UPDATE`
+
+	var lots []entity.CoinLot
+	err := r.conn(ctx).SelectContext(ctx, &lots, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list open coin lots: %w", err)
+	}
+
+	taken, short := entity.ConsumeLots(lots, amount)
+
+	touched := make(map[int64]bool, len(taken))
+	for _, p := range taken {
+		touched[p.ID] = true
+	}
+	for _, lot := range lots {
+		if !touched[lot.ID] {
+			continue
+		}
+		if err := r.Update(ctx, lot); err != nil {
+			return nil, err
+		}
+	}
+
+	if short > 0 {
+		taken = append(taken, entity.NewCoinLot(userID, short, time.Now(), 0))
+	}
+
+	return taken, nil
+}
+
+// Credit gives the user lots matching portions taken from someone else by
+// Spend, keeping their grant and expiry dates.
+func (r *CoinLotRepository) Credit(ctx context.Context, userID int64, portions []entity.CoinLot) error {
+	for _, p := range portions {
+		lot := entity.CoinLot{
+			UserID:    userID,
+			Amount:    p.Amount,
+			Remaining: p.Amount,
+			GrantedAt: p.GrantedAt,
+			ExpiresAt: p.ExpiresAt,
+		}
+		if err := r.Create(ctx, &lot); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ListExpired returns the IDs of lots that still hold coins past their
+// expiry date, oldest expiry first.
+func (r *CoinLotRepository) ListExpired(ctx context.Context, now time.Time, limit int) ([]int64, error) {
+	query := `
+  SELECT id
+  FROM coin_lots
+  WHERE remaining > 0
+   AND expires_at <= $1
+  ORDER BY expires_at, id
+  LIMIT $2`
+
+	var ids []int64
+	err := r.conn(ctx).SelectContext(ctx, &ids, query, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired coin lots: %w", err)
+	}
+
+	return ids, nil
+}
+
+// ListExpiring returns the user's lots that still hold coins and have an
+// expiry date, soonest first.
+func (r *CoinLotRepository) ListExpiring(ctx context.Context, userID int64) ([]entity.CoinLot, error) {
+	query := `
+  SELECT id, user_id, amount, remaining, granted_at, expires_at, created_at
+  FROM coin_lots
+  WHERE user_id = $1
+   AND remaining > 0
+   AND expires_at IS NOT NULL
+  ORDER BY expires_at, id`
+
+	var lots []entity.CoinLot
+	err := r.conn(ctx).SelectContext(ctx, &lots, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expiring coin lots: %w", err)
+	}
+
+	return lots, nil
+}
@@ -0,0 +1,139 @@
+package coin_lot_repository
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/testutils"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type CoinLotRepositoryTestSuite struct {
+	suite.Suite
+	db   *sqlx.DB
+	repo *CoinLotRepository
+}
+
+func (s *CoinLotRepositoryTestSuite) SetupSuite() {
+	db, err := testutils.GetTestDB()
+	require.NoError(s.T(), err)
+
+	s.db = db
+	s.repo = NewCoinLotRepository(db)
+
+	s.recreateTables()
+}
+
+func (s *CoinLotRepositoryTestSuite) SetupTest() {
+	_, err := s.db.Exec("TRUNCATE TABLE coin_lots, users RESTART IDENTITY CASCADE")
+	require.NoError(s.T(), err)
+
+	_, err = s.db.Exec(`
+  INSERT INTO users (username, password_hash, coins)
+  VALUES
+  ('user1', 'hash1', 1000),
+  ('user2', 'hash2', 1000)`)
+	require.NoError(s.T(), err)
+}
+
+func (s *CoinLotRepositoryTestSuite) TearDownSuite() {
+	s.db.Close()
+}
+
+func (s *CoinLotRepositoryTestSuite) recreateTables() {
+	_, err := s.db.Exec(`
+  DROP TABLE IF EXISTS coin_lots;
+  DROP TABLE IF EXISTS users CASCADE;
+
+  CREATE TABLE users (
+   id SERIAL PRIMARY KEY,
+   username VARCHAR(255) UNIQUE NOT NULL,
+   password_hash VARCHAR(255) NOT NULL,
+   coins INTEGER NOT NULL DEFAULT 1000,
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+
+  CREATE TABLE coin_lots (
+   id SERIAL PRIMARY KEY,
+   user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+   amount INTEGER NOT NULL CHECK (amount > 0),
+   remaining INTEGER NOT NULL CHECK (remaining >= 0 AND remaining <= amount),
+   granted_at TIMESTAMP WITH TIME ZONE NOT NULL,
+   expires_at TIMESTAMP WITH TIME ZONE,
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+ `)
+	require.NoError(s.T(), err)
+}
+
+func (s *CoinLotRepositoryTestSuite) TestSpendAndCredit() {
+	ctx := context.Background()
+	now := time.Now()
+
+	older := entity.NewCoinLot(1, 100, now.AddDate(0, -2, 0), 365*24*time.Hour)
+	newer := entity.NewCoinLot(1, 100, now.AddDate(0, -1, 0), 0)
+	s.NoError(s.repo.Create(ctx, &older))
+	s.NoError(s.repo.Create(ctx, &newer))
+
+	taken, err := s.repo.Spend(ctx, 1, 150)
+	s.NoError(err)
+	s.Len(taken, 2)
+	s.Equal(older.ID, taken[0].ID)
+	s.Equal(int64(100), taken[0].Amount)
+	s.NotNil(taken[0].ExpiresAt)
+	s.Equal(int64(50), taken[1].Amount)
+
+	s.NoError(s.repo.Credit(ctx, 2, taken))
+
+	expiring, err := s.repo.ListExpiring(ctx, 2)
+	s.NoError(err)
+	s.Len(expiring, 1)
+	s.Equal(int64(100), expiring[0].Remaining)
+	s.WithinDuration(*older.ExpiresAt, *expiring[0].ExpiresAt, time.Second)
+
+	lot, err := s.repo.GetByID(ctx, newer.ID)
+	s.NoError(err)
+	s.Equal(int64(50), lot.Remaining)
+
+	// only 50 coins are left in lots, the rest is not backed by any
+	taken, err = s.repo.Spend(ctx, 1, 80)
+	s.NoError(err)
+	s.Len(taken, 2)
+	s.Zero(taken[1].ID)
+	s.Equal(int64(30), taken[1].Amount)
+	s.Nil(taken[1].ExpiresAt)
+
+	_, err = s.repo.GetByID(ctx, 100)
+	s.ErrorIs(err, entity.ErrCoinLotNotFound)
+}
+
+func (s *CoinLotRepositoryTestSuite) TestListExpired() {
+	ctx := context.Background()
+	now := time.Now()
+
+	expired := entity.NewCoinLot(1, 100, now.AddDate(-1, 0, 0), time.Hour)
+	active := entity.NewCoinLot(1, 100, now, time.Hour)
+	forever := entity.NewCoinLot(2, 100, now.AddDate(-2, 0, 0), 0)
+	s.NoError(s.repo.Create(ctx, &expired))
+	s.NoError(s.repo.Create(ctx, &active))
+	s.NoError(s.repo.Create(ctx, &forever))
+
+	ids, err := s.repo.ListExpired(ctx, now, 10)
+	s.NoError(err)
+	s.Equal([]int64{expired.ID}, ids)
+
+	expired.Remaining = 0
+	s.NoError(s.repo.Update(ctx, expired))
+
+	ids, err = s.repo.ListExpired(ctx, now, 10)
+	s.NoError(err)
+	s.Empty(ids)
+}
+
+func TestCoinLotRepository(t *testing.T) {
+	suite.Run(t, new(CoinLotRepositoryTestSuite))
+}
@@ -0,0 +1,162 @@
+package coin_request_repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/repository"
+	"time"
+)
+
+type dbConn interface {
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type CoinRequestRepository struct {
+	db dbConn
+}
+
+func NewCoinRequestRepository(db *sqlx.DB) *CoinRequestRepository {
+	return &CoinRequestRepository{
+		db: db,
+	}
+}
+
+func (r *CoinRequestRepository) WithTx(tx *sqlx.Tx) *CoinRequestRepository {
+	return &CoinRequestRepository{
+		db: tx,
+	}
+}
+
+// conn returns the transaction ctx carries, or the repository's own
+// connection outside of one.
+func (r *CoinRequestRepository) conn(ctx context.Context) dbConn {
+	if tx, ok := repository.TxFromContext(ctx); ok {
+		return tx
+	}
+	return r.db
+}
+
+func (r *CoinRequestRepository) Create(ctx context.Context, req *entity.CoinRequest) error {
+	query := `
+  INSERT INTO coin_requests (requester_id, payer_id, amount, reason, status, expires_at)
+  VALUES ($1, $2, $3, $4, $5, $6)
+  RETURNING id, created_at`
+
+	err := r.conn(ctx).QueryRowContext(
+		ctx,
+		query,
+		req.RequesterID,
+		req.PayerID,
+		req.Amount,
+		req.Reason,
+		req.Status,
+		req.ExpiresAt,
+	).Scan(&req.ID, &req.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create coin request: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID locks the request row until the surrounding transaction ends.
+func (r *CoinRequestRepository) GetByID(ctx context.Context, id int64) (entity.CoinRequest, error) {
+	var req entity.CoinRequest
+	query := `
+  SELECT id, requester_id, payer_id, amount, reason, status, expires_at, created_at, resolved_at
+  FROM coin_requests
+  WHERE id = $1
+  FOR UPDATE`
+
+	err := r.conn(ctx).GetContext(ctx, &req, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.CoinRequest{}, entity.ErrCoinRequestNotFound
+		}
+		return entity.CoinRequest{}, fmt.Errorf("failed to get coin request by id: %w", err)
+	}
+
+	return req, nil
+}
+
+// Resolve moves a pending request to status. It reports false when the
+// request was no longer pending, so a request can be resolved only once.
+func (r *CoinRequestRepository) Resolve(ctx context.Context, id int64, status entity.CoinRequestStatus) (bool, error) {
+	query := `
+  UPDATE coin_requests
+  SET status = $1,
+   resolved_at = CURRENT_TIMESTAMP
+  WHERE id = $2 AND status = $3`
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, status, id, entity.CoinRequestStatusPending)
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve coin request: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// ExpirePending marks every pending request that expired before now.
+func (r *CoinRequestRepository) ExpirePending(ctx context.Context, now time.Time) (int64, error) {
+	query := `
+  UPDATE coin_requests
+  SET status = $1,
+   resolved_at = CURRENT_TIMESTAMP
+  WHERE status = $2 AND expires_at <= $3`
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, entity.CoinRequestStatusExpired, entity.CoinRequestStatusPending, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire coin requests: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+func (r *CoinRequestRepository) ListIncoming(ctx context.Context, payerID int64) ([]entity.CoinRequest, error) {
+	query := `
+  SELECT id, requester_id, payer_id, amount, reason, status, expires_at, created_at, resolved_at
+  FROM coin_requests
+  WHERE payer_id = $1
+  ORDER BY created_at DESC`
+
+	var reqs []entity.CoinRequest
+	err := r.conn(ctx).SelectContext(ctx, &reqs, query, payerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list incoming coin requests: %w", err)
+	}
+
+	return reqs, nil
+}
+
+func (r *CoinRequestRepository) ListOutgoing(ctx context.Context, requesterID int64) ([]entity.CoinRequest, error) {
+	query := `
+  SELECT id, requester_id, payer_id, amount, reason, status, expires_at, created_at, resolved_at
+  FROM coin_requests
+  WHERE requester_id = $1
+  ORDER BY created_at DESC`
+
+	var reqs []entity.CoinRequest
+	err := r.conn(ctx).SelectContext(ctx, &reqs, query, requesterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list outgoing coin requests: %w", err)
+	}
+
+	return reqs, nil
+}
@@ -0,0 +1,147 @@
+package coin_request_repository
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/testutils"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type CoinRequestRepositoryTestSuite struct {
+	suite.Suite
+	db   *sqlx.DB
+	repo *CoinRequestRepository
+}
+
+func (s *CoinRequestRepositoryTestSuite) SetupSuite() {
+	db, err := testutils.GetTestDB()
+	require.NoError(s.T(), err)
+
+	s.db = db
+	s.repo = NewCoinRequestRepository(db)
+
+	s.recreateTables()
+}
+
+func (s *CoinRequestRepositoryTestSuite) SetupTest() {
+	_, err := s.db.Exec("TRUNCATE TABLE coin_requests, users RESTART IDENTITY CASCADE")
+	require.NoError(s.T(), err)
+
+	_, err = s.db.Exec(`
+  INSERT INTO users (username, password_hash, coins)
+  VALUES
+  ('user1', 'hash1', 1000),
+  ('user2', 'hash2', 1000)`)
+	require.NoError(s.T(), err)
+}
+
+func (s *CoinRequestRepositoryTestSuite) TearDownSuite() {
+	s.db.Close()
+}
+
+func (s *CoinRequestRepositoryTestSuite) recreateTables() {
+	_, err := s.db.Exec(`
+  DROP TABLE IF EXISTS coin_requests;
+  DROP TABLE IF EXISTS users CASCADE;
+
+  CREATE TABLE users (
+   id SERIAL PRIMARY KEY,
+   username VARCHAR(255) UNIQUE NOT NULL,
+   password_hash VARCHAR(255) NOT NULL,
+   coins INTEGER NOT NULL DEFAULT 1000,
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+
+  CREATE TABLE coin_requests (
+   id SERIAL PRIMARY KEY,
+   requester_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+   payer_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+   amount INTEGER NOT NULL CHECK (amount > 0),
+   reason VARCHAR(280) NOT NULL DEFAULT '',
+   status VARCHAR(50) NOT NULL DEFAULT 'pending',
+   expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+   resolved_at TIMESTAMP WITH TIME ZONE
+  );
+ `)
+	require.NoError(s.T(), err)
+}
+
+func (s *CoinRequestRepositoryTestSuite) createRequest(expiresAt time.Time) entity.CoinRequest {
+	req := entity.CoinRequest{
+		RequesterID: 1,
+		PayerID:     2,
+		Amount:      100,
+		Reason:      "pizza",
+		Status:      entity.CoinRequestStatusPending,
+		ExpiresAt:   expiresAt,
+	}
+	err := s.repo.Create(context.Background(), &req)
+	s.Require().NoError(err)
+
+	return req
+}
+
+func (s *CoinRequestRepositoryTestSuite) TestCreateAndGet() {
+	ctx := context.Background()
+	req := s.createRequest(time.Now().Add(time.Hour))
+	s.NotZero(req.ID)
+
+	s.Run("existing request", func() {
+		found, err := s.repo.GetByID(ctx, req.ID)
+		s.NoError(err)
+		s.Equal(entity.CoinRequestStatusPending, found.Status)
+		s.Equal("pizza", found.Reason)
+		s.Nil(found.ResolvedAt)
+	})
+
+	s.Run("non-existing request", func() {
+		_, err := s.repo.GetByID(ctx, 99999)
+		s.ErrorIs(err, entity.ErrCoinRequestNotFound)
+	})
+}
+
+func (s *CoinRequestRepositoryTestSuite) TestResolveOnlyOnce() {
+	ctx := context.Background()
+	req := s.createRequest(time.Now().Add(time.Hour))
+
+	resolved, err := s.repo.Resolve(ctx, req.ID, entity.CoinRequestStatusAccepted)
+	s.NoError(err)
+	s.True(resolved)
+
+	resolved, err = s.repo.Resolve(ctx, req.ID, entity.CoinRequestStatusAccepted)
+	s.NoError(err)
+	s.False(resolved)
+
+	found, err := s.repo.GetByID(ctx, req.ID)
+	s.NoError(err)
+	s.Equal(entity.CoinRequestStatusAccepted, found.Status)
+	s.NotNil(found.ResolvedAt)
+}
+
+func (s *CoinRequestRepositoryTestSuite) TestExpirePending() {
+	ctx := context.Background()
+	s.createRequest(time.Now().Add(-time.Minute))
+	s.createRequest(time.Now().Add(time.Hour))
+
+	expired, err := s.repo.ExpirePending(ctx, time.Now())
+	s.NoError(err)
+	s.Equal(int64(1), expired)
+
+	incoming, err := s.repo.ListIncoming(ctx, 2)
+	s.NoError(err)
+	s.Len(incoming, 2)
+
+	outgoing, err := s.repo.ListOutgoing(ctx, 2)
+	s.NoError(err)
+	s.Empty(outgoing)
+}
+
+func TestCoinRequestRepository(t *testing.T) {
+	suite.Run(t, new(CoinRequestRepositoryTestSuite))
+}
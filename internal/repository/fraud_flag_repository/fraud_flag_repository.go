@@ -0,0 +1,130 @@
+package fraud_flag_repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/repository"
+)
+
+type dbConn interface {
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type FraudFlagRepository struct {
+	db dbConn
+}
+
+func NewFraudFlagRepository(db *sqlx.DB) *FraudFlagRepository {
+	return &FraudFlagRepository{
+		db: db,
+	}
+}
+
+func (r *FraudFlagRepository) WithTx(tx *sqlx.Tx) *FraudFlagRepository {
+	return &FraudFlagRepository{
+		db: tx,
+	}
+}
+
+// conn returns the transaction ctx carries, or the repository's own
+// connection outside of one.
+func (r *FraudFlagRepository) conn(ctx context.Context) dbConn {
+	if tx, ok := repository.TxFromContext(ctx); ok {
+		return tx
+	}
+	return r.db
+}
+
+func (r *FraudFlagRepository) Create(ctx context.Context, flag *entity.FraudFlag) error {
+	query := `
+  INSERT INTO fraud_flags (user_id, counterpart_id, item_id, type, amount, rule, action, reason, status)
+  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+  RETURNING id, created_at`
+
+	err := r.conn(ctx).QueryRowContext(
+		ctx,
+		query,
+		flag.UserID,
+		flag.CounterpartID,
+		flag.ItemID,
+		flag.Type,
+		flag.Amount,
+		flag.Rule,
+		flag.Action,
+		flag.Reason,
+		flag.Status,
+	).Scan(&flag.ID, &flag.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create fraud flag: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID locks the flag row until the surrounding transaction ends.
+func (r *FraudFlagRepository) GetByID(ctx context.Context, id int64) (entity.FraudFlag, error) {
+	var flag entity.FraudFlag
+	query := `
+  SELECT id, user_id, counterpart_id, item_id, type, amount, rule, action, reason, status, reviewed_by, created_at, reviewed_at
+  FROM fraud_flags
+  WHERE id = $1
+  FOR UPDATE`
+
+	err := r.conn(ctx).GetContext(ctx, &flag, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.FraudFlag{}, entity.ErrFraudFlagNotFound
+		}
+		return entity.FraudFlag{}, fmt.Errorf("failed to get fraud flag by id: %w", err)
+	}
+
+	return flag, nil
+}
+
+// List returns flags in the given status, newest first. An empty status
+// lists every flag.
+func (r *FraudFlagRepository) List(ctx context.Context, status entity.FraudFlagStatus) ([]entity.FraudFlag, error) {
+	query := `
+  SELECT id, user_id, counterpart_id, item_id, type, amount, rule, action, reason, status, reviewed_by, created_at, reviewed_at
+  FROM fraud_flags
+  WHERE $1 = '' OR status = $1
+  ORDER BY created_at DESC, id DESC`
+
+	var flags []entity.FraudFlag
+	err := r.conn(ctx).SelectContext(ctx, &flags, query, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fraud flags: %w", err)
+	}
+
+	return flags, nil
+}
+
+// Review closes an open flag. It reports false when the flag had already
+// been reviewed.
+func (r *FraudFlagRepository) Review(ctx context.Context, id int64, status entity.FraudFlagStatus, reviewerID int64) (bool, error) {
+	query := `
+  UPDATE fraud_flags
+  SET status = $1,
+   reviewed_by = $2,
+   reviewed_at = CURRENT_TIMESTAMP
+  WHERE id = $3 AND status = $4`
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, status, reviewerID, id, entity.FraudFlagStatusOpen)
+	if err != nil {
+		return false, fmt.Errorf("failed to review fraud flag: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
@@ -0,0 +1,128 @@
+package fraud_flag_repository
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/testutils"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type FraudFlagRepositoryTestSuite struct {
+	suite.Suite
+	db   *sqlx.DB
+	repo *FraudFlagRepository
+}
+
+func (s *FraudFlagRepositoryTestSuite) SetupSuite() {
+	db, err := testutils.GetTestDB()
+	require.NoError(s.T(), err)
+
+	s.db = db
+	s.repo = NewFraudFlagRepository(db)
+
+	s.recreateTables()
+}
+
+func (s *FraudFlagRepositoryTestSuite) SetupTest() {
+	_, err := s.db.Exec("TRUNCATE TABLE fraud_flags, users RESTART IDENTITY CASCADE")
+	require.NoError(s.T(), err)
+
+	_, err = s.db.Exec(`
+  INSERT INTO users (username, password_hash, coins)
+  VALUES
+  ('user1', 'hash1', 1000),
+  ('user2', 'hash2', 1000),
+  ('admin', 'hash3', 1000)`)
+	require.NoError(s.T(), err)
+}
+
+func (s *FraudFlagRepositoryTestSuite) TearDownSuite() {
+	s.db.Close()
+}
+
+func (s *FraudFlagRepositoryTestSuite) recreateTables() {
+	_, err := s.db.Exec(`
+  DROP TABLE IF EXISTS fraud_flags;
+  DROP TABLE IF EXISTS users CASCADE;
+
+  CREATE TABLE users (
+   id SERIAL PRIMARY KEY,
+   username VARCHAR(255) UNIQUE NOT NULL,
+   password_hash VARCHAR(255) NOT NULL,
+   coins INTEGER NOT NULL DEFAULT 1000,
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+
+  CREATE TABLE fraud_flags (
+   id SERIAL PRIMARY KEY,
+   user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+   counterpart_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+   item_id INTEGER,
+   type VARCHAR(50) NOT NULL,
+   amount INTEGER NOT NULL,
+   rule VARCHAR(100) NOT NULL,
+   action VARCHAR(50) NOT NULL,
+   reason TEXT NOT NULL DEFAULT '',
+   status VARCHAR(50) NOT NULL DEFAULT 'open',
+   reviewed_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+   reviewed_at TIMESTAMP WITH TIME ZONE
+  );
+ `)
+	require.NoError(s.T(), err)
+}
+
+func (s *FraudFlagRepositoryTestSuite) TestFlags() {
+	ctx := context.Background()
+
+	counterpartID := int64(2)
+	flag := entity.FraudFlag{
+		UserID:        1,
+		CounterpartID: &counterpartID,
+		Type:          entity.TransactionTypeTransfer,
+		Amount:        900,
+		Rule:          "ring",
+		Action:        entity.FraudActionHold.String(),
+		Reason:        "user1 -> user2 -> user1",
+		Status:        entity.FraudFlagStatusOpen,
+	}
+	s.NoError(s.repo.Create(ctx, &flag))
+	s.NotZero(flag.ID)
+
+	flags, err := s.repo.List(ctx, entity.FraudFlagStatusOpen)
+	s.NoError(err)
+	s.Len(flags, 1)
+	s.Equal("ring", flags[0].Rule)
+
+	reviewed, err := s.repo.Review(ctx, flag.ID, entity.FraudFlagStatusConfirmed, 3)
+	s.NoError(err)
+	s.True(reviewed)
+
+	reviewed, err = s.repo.Review(ctx, flag.ID, entity.FraudFlagStatusDismissed, 3)
+	s.NoError(err)
+	s.False(reviewed)
+
+	flags, err = s.repo.List(ctx, entity.FraudFlagStatusOpen)
+	s.NoError(err)
+	s.Empty(flags)
+
+	flags, err = s.repo.List(ctx, "")
+	s.NoError(err)
+	s.Len(flags, 1)
+
+	found, err := s.repo.GetByID(ctx, flag.ID)
+	s.NoError(err)
+	s.Equal(entity.FraudFlagStatusConfirmed, found.Status)
+	s.Equal(int64(3), *found.ReviewedBy)
+
+	_, err = s.repo.GetByID(ctx, 100)
+	s.ErrorIs(err, entity.ErrFraudFlagNotFound)
+}
+
+func TestFraudFlagRepository(t *testing.T) {
+	suite.Run(t, new(FraudFlagRepositoryTestSuite))
+}
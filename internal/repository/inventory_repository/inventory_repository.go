@@ -0,0 +1,152 @@
+package inventory_repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/repository"
+)
+
+type dbConn interface {
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+type InventoryRepository struct {
+	db dbConn
+}
+
+func NewInventoryRepository(db *sqlx.DB) *InventoryRepository {
+	return &InventoryRepository{
+		db: db,
+	}
+}
+
+func (r *InventoryRepository) WithTx(tx *sqlx.Tx) *InventoryRepository {
+	return &InventoryRepository{
+		db: tx,
+	}
+}
+
+// conn returns the transaction ctx carries, or the repository's own
+// connection outside of one.
+func (r *InventoryRepository) conn(ctx context.Context) dbConn {
+	if tx, ok := repository.TxFromContext(ctx); ok {
+		return tx
+	}
+	return r.db
+}
+
+func (r *InventoryRepository) GetByUserID(ctx context.Context, userID int64) ([]entity.UserInventory, error) {
+	var inventory []entity.UserInventory
+	query := `
+  SELECT id, user_id, item_id, variant_id, quantity, purchased_at
+  FROM user_inventory
+  WHERE user_id = $1
+  ORDER BY id`
+
+	err := r.conn(ctx).SelectContext(ctx, &inventory, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user inventory: %w", err)
+	}
+
+	return inventory, nil
+}
+
+func (r *InventoryRepository) Create(ctx context.Context, inventory entity.UserInventory) error {
+	query := `
+  INSERT INTO user_inventory (user_id, item_id, variant_id, quantity, purchased_at)
+  VALUES ($1, $2, $3, $4, $5)`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, inventory.UserID, inventory.ItemID, inventory.VariantID, inventory.Quantity, inventory.PurchasedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create inventory item: %w", err)
+	}
+
+	return nil
+}
+
+func (r *InventoryRepository) Update(ctx context.Context, inventory entity.UserInventory) error {
+	query := `
+  UPDATE user_inventory
+  SET quantity = $2
+  WHERE id = $1`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, inventory.ID, inventory.Quantity)
+	if err != nil {
+		return fmt.Errorf("failed to update inventory item: %w", err)
+	}
+
+	return nil
+}
+
+func (r *InventoryRepository) Delete(ctx context.Context, id int64) error {
+	query := `
+  DELETE FROM user_inventory
+  WHERE id = $1`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete inventory item: %w", err)
+	}
+
+	return nil
+}
+
+// Take removes quantity units of the item variant from the user's
+// inventory with a single conditional update, dropping the row once it is
+// empty. It reports false, leaving the inventory alone, when the user holds
+// fewer.
+func (r *InventoryRepository) Take(ctx context.Context, userID, itemID int64, variantID *int64, quantity int64) (bool, error) {
+	return r.TakeKeeping(ctx, userID, itemID, variantID, quantity, 0)
+}
+
+// TakeKeeping is Take that also leaves at least keep units behind, for
+// units that are already spoken for.
+func (r *InventoryRepository) TakeKeeping(ctx context.Context, userID, itemID int64, variantID *int64, quantity, keep int64) (bool, error) {
+	var row struct {
+		ID       int64 `db:"id"`
+		Quantity int64 `db:"quantity"`
+	}
+	query := `
+  UPDATE user_inventory
+  SET quantity = quantity - $4
+  WHERE user_id = $1 AND item_id = $2 AND COALESCE(variant_id, 0) = COALESCE($3, 0)
+   AND quantity >= $4 + $5
+  RETURNING id, quantity`
+
+	err := r.conn(ctx).GetContext(ctx, &row, query, userID, itemID, variantID, quantity, keep)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to take inventory items: %w", err)
+	}
+
+	if row.Quantity == 0 {
+		if err := r.Delete(ctx, row.ID); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func (r *InventoryRepository) Add(ctx context.Context, userID, itemID int64, variantID *int64, quantity int64) error {
+	query := `
+  INSERT INTO user_inventory (user_id, item_id, variant_id, quantity)
+  VALUES ($1, $2, $3, $4)
+  ON CONFLICT (user_id, item_id, COALESCE(variant_id, 0)) DO UPDATE
+  SET quantity = user_inventory.quantity + EXCLUDED.quantity`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, userID, itemID, variantID, quantity)
+	if err != nil {
+		return fmt.Errorf("failed to add inventory items: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package inventory_repository
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/testutils"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type InventoryRepositoryTestSuite struct {
+	suite.Suite
+	db   *sqlx.DB
+	repo *InventoryRepository
+}
+
+func (s *InventoryRepositoryTestSuite) SetupSuite() {
+	db, err := testutils.GetTestDB()
+	require.NoError(s.T(), err)
+
+	s.db = db
+	s.repo = NewInventoryRepository(db)
+
+	s.recreateTables()
+}
+
+func (s *InventoryRepositoryTestSuite) SetupTest() {
+	_, err := s.db.Exec("TRUNCATE TABLE user_inventory, merch_items, users RESTART IDENTITY CASCADE")
+	require.NoError(s.T(), err)
+
+	_, err = s.db.Exec(`
+  INSERT INTO users (username, password_hash, coins) VALUES ('user1', 'hash1', 1000);
+  INSERT INTO merch_items (name, price) VALUES ('hoody', 300)`)
+	require.NoError(s.T(), err)
+}
+
+func (s *InventoryRepositoryTestSuite) TearDownSuite() {
+	s.db.Close()
+}
+
+func (s *InventoryRepositoryTestSuite) recreateTables() {
+	_, err := s.db.Exec(`
+  DROP TABLE IF EXISTS user_inventory;
+  DROP TABLE IF EXISTS merch_items CASCADE;
+  DROP TABLE IF EXISTS users CASCADE;
+
+  CREATE TABLE users (
+   id SERIAL PRIMARY KEY,
+   username VARCHAR(255) UNIQUE NOT NULL,
+   password_hash VARCHAR(255) NOT NULL,
+   coins INTEGER NOT NULL DEFAULT 1000,
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+
+  CREATE TABLE merch_items (
+   id SERIAL PRIMARY KEY,
+   name VARCHAR(255) UNIQUE NOT NULL,
+   price INTEGER NOT NULL CHECK (price > 0),
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+
+  CREATE TABLE user_inventory (
+   id SERIAL PRIMARY KEY,
+   user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+   item_id INTEGER REFERENCES merch_items(id) ON DELETE CASCADE,
+   variant_id INTEGER,
+   quantity INTEGER NOT NULL DEFAULT 1,
+   purchased_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+
+  CREATE UNIQUE INDEX unique_user_item_variant ON user_inventory(user_id, item_id, COALESCE(variant_id, 0));
+ `)
+	require.NoError(s.T(), err)
+}
+
+func (s *InventoryRepositoryTestSuite) TestTakeAndAdd() {
+	ctx := context.Background()
+	large := int64(7)
+
+	s.NoError(s.repo.Create(ctx, entity.UserInventory{UserID: 1, ItemID: 1, Quantity: 3, PurchasedAt: time.Now()}))
+	s.NoError(s.repo.Create(ctx, entity.UserInventory{UserID: 1, ItemID: 1, VariantID: &large, Quantity: 1, PurchasedAt: time.Now()}))
+
+	ok, err := s.repo.Take(ctx, 1, 1, nil, 4)
+	s.NoError(err)
+	s.False(ok)
+
+	// two of the three are spoken for
+	ok, err = s.repo.TakeKeeping(ctx, 1, 1, nil, 2, 2)
+	s.NoError(err)
+	s.False(ok)
+
+	ok, err = s.repo.TakeKeeping(ctx, 1, 1, nil, 2, 1)
+	s.NoError(err)
+	s.True(ok)
+
+	// the last unit of a variant empties its row
+	ok, err = s.repo.Take(ctx, 1, 1, &large, 1)
+	s.NoError(err)
+	s.True(ok)
+
+	inventory, err := s.repo.GetByUserID(ctx, 1)
+	s.NoError(err)
+	s.Len(inventory, 1)
+	s.Equal(int64(1), inventory[0].Quantity)
+
+	s.NoError(s.repo.Add(ctx, 1, 1, nil, 2))
+	s.NoError(s.repo.Add(ctx, 1, 1, &large, 1))
+
+	inventory, err = s.repo.GetByUserID(ctx, 1)
+	s.NoError(err)
+	s.Len(inventory, 2)
+	s.Equal(int64(3), inventory[0].Quantity)
+	s.Equal(large, *inventory[1].VariantID)
+}
+
+func TestInventoryRepository(t *testing.T) {
+	suite.Run(t, new(InventoryRepositoryTestSuite))
+}
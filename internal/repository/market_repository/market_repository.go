@@ -0,0 +1,219 @@
+package market_repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/repository"
+	"strings"
+	"time"
+)
+
+// searchConfig matches the configuration merch_items.search_vector is built
+// with.
+const searchConfig = "english"
+
+const selectListings = `
+  SELECT l.id, l.seller_id, l.item_id, l.variant_id, l.quantity, l.price, l.status, l.buyer_id, l.fee,
+   l.expires_at, l.closed_at, l.created_at,
+   u.username AS seller, m.name AS item_name,
+   COALESCE(concat_ws(', ', NULLIF(v.size, ''), NULLIF(v.color, '')), '') AS variant
+  FROM market_listings l
+  JOIN users u ON u.id = l.seller_id
+  JOIN merch_items m ON m.id = l.item_id
+  LEFT JOIN merch_variants v ON v.id = l.variant_id`
+
+type dbConn interface {
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type MarketRepository struct {
+	db dbConn
+}
+
+func NewMarketRepository(db *sqlx.DB) *MarketRepository {
+	return &MarketRepository{
+		db: db,
+	}
+}
+
+func (r *MarketRepository) WithTx(tx *sqlx.Tx) *MarketRepository {
+	return &MarketRepository{
+		db: tx,
+	}
+}
+
+// conn returns the transaction ctx carries, or the repository's own
+// connection outside of one.
+func (r *MarketRepository) conn(ctx context.Context) dbConn {
+	if tx, ok := repository.TxFromContext(ctx); ok {
+		return tx
+	}
+	return r.db
+}
+
+func (r *MarketRepository) Create(ctx context.Context, listing *entity.Listing) error {
+	if listing.Status == "" {
+		listing.Status = entity.ListingStatusActive
+	}
+
+	query := `
+  INSERT INTO market_listings (seller_id, item_id, variant_id, quantity, price, status, expires_at)
+  VALUES ($1, $2, $3, $4, $5, $6, $7)
+  RETURNING id, created_at`
+
+	err := r.conn(ctx).QueryRowContext(
+		ctx,
+		query,
+		listing.SellerID,
+		listing.ItemID,
+		listing.VariantID,
+		listing.Quantity,
+		listing.Price,
+		listing.Status,
+		listing.ExpiresAt,
+	).Scan(&listing.ID, &listing.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create listing: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID locks the listing for the rest of the transaction, so that two
+// buyers cannot both get it.
+func (r *MarketRepository) GetByID(ctx context.Context, id int64) (entity.Listing, error) {
+	var listing entity.Listing
+	query := selectListings + `
+  WHERE l.id = $1
+  FOR UPDATE OF l`
+
+	err := r.conn(ctx).GetContext(ctx, &listing, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Listing{}, entity.ErrListingNotFound
+		}
+		return entity.Listing{}, fmt.Errorf("failed to get listing: %w", err)
+	}
+
+	return listing, nil
+}
+
+// Search returns a page of the listings that can be bought at now, newest
+// first unless the query asks for the cheapest, and how many match in total.
+func (r *MarketRepository) Search(ctx context.Context, q entity.ListingQuery, now time.Time) ([]entity.Listing, int64, error) {
+	var args []interface{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	conds := []string{
+		"l.status = 'active'",
+		"(l.expires_at IS NULL OR l.expires_at > " + arg(now) + ")",
+	}
+	if q.Search != "" {
+		conds = append(conds, fmt.Sprintf("m.search_vector @@ websearch_to_tsquery('%s', %s)", searchConfig, arg(q.Search)))
+	}
+	if q.ItemID != nil {
+		conds = append(conds, "l.item_id = "+arg(*q.ItemID))
+	}
+	if q.SellerID != nil {
+		conds = append(conds, "l.seller_id = "+arg(*q.SellerID))
+	}
+	if q.MaxPrice != nil {
+		conds = append(conds, "l.price <= "+arg(*q.MaxPrice))
+	}
+
+	where := `
+  WHERE ` + strings.Join(conds, " AND ")
+
+	var total int64
+	countQuery := `
+  SELECT COUNT(*)
+  FROM market_listings l
+  JOIN merch_items m ON m.id = l.item_id` + where
+
+	if err := r.conn(ctx).GetContext(ctx, &total, countQuery, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to count listings: %w", err)
+	}
+
+	orderBy := "l.created_at DESC, l.id DESC"
+	if q.CheapestFirst {
+		orderBy = "l.price, l.id"
+	}
+
+	query := selectListings + where + `
+  ORDER BY ` + orderBy + `
+  LIMIT ` + arg(q.Limit) + ` OFFSET ` + arg(q.Offset)
+
+	var listings []entity.Listing
+	if err := r.conn(ctx).SelectContext(ctx, &listings, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to search listings: %w", err)
+	}
+
+	return listings, total, nil
+}
+
+func (r *MarketRepository) ListBySeller(ctx context.Context, sellerID int64) ([]entity.Listing, error) {
+	var listings []entity.Listing
+	query := selectListings + `
+  WHERE l.seller_id = $1
+  ORDER BY l.created_at DESC, l.id DESC`
+
+	err := r.conn(ctx).SelectContext(ctx, &listings, query, sellerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list seller listings: %w", err)
+	}
+
+	return listings, nil
+}
+
+// Close moves an active listing to its final status. It reports false if the
+// listing had already been closed.
+func (r *MarketRepository) Close(ctx context.Context, listing entity.Listing) (bool, error) {
+	query := `
+  UPDATE market_listings
+  SET status = $2, buyer_id = $3, fee = $4, closed_at = $5
+  WHERE id = $1
+   AND status = 'active'`
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, listing.ID, listing.Status, listing.BuyerID, listing.Fee, listing.ClosedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to close listing: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to close listing: %w", err)
+	}
+
+	return n > 0, nil
+}
+
+// ListExpired returns the IDs of active listings whose time ran out by now,
+// oldest first.
+func (r *MarketRepository) ListExpired(ctx context.Context, now time.Time, limit int) ([]int64, error) {
+	var ids []int64
+	query := `
+  SELECT id
+  FROM market_listings
+  WHERE status = 'active'
+   AND expires_at <= $1
+  ORDER BY expires_at, id
+  LIMIT $2`
+
+	err := r.conn(ctx).SelectContext(ctx, &ids, query, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired listings: %w", err)
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,166 @@
+package market_repository
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/testutils"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type MarketRepositoryTestSuite struct {
+	suite.Suite
+	db   *sqlx.DB
+	repo *MarketRepository
+}
+
+func (s *MarketRepositoryTestSuite) SetupSuite() {
+	db, err := testutils.GetTestDB()
+	require.NoError(s.T(), err)
+
+	s.db = db
+	s.repo = NewMarketRepository(db)
+
+	s.recreateTables()
+}
+
+func (s *MarketRepositoryTestSuite) SetupTest() {
+	_, err := s.db.Exec("TRUNCATE TABLE market_listings, merch_variants, merch_items, users RESTART IDENTITY CASCADE")
+	require.NoError(s.T(), err)
+
+	_, err = s.db.Exec(`
+  INSERT INTO users (username, password_hash, coins) VALUES ('user1', 'hash1', 1000), ('user2', 'hash2', 1000);
+  INSERT INTO merch_items (name, price) VALUES ('hoody', 300), ('cup', 20);
+  INSERT INTO merch_variants (item_id, size, color) VALUES (1, 'L', 'black')`)
+	require.NoError(s.T(), err)
+}
+
+func (s *MarketRepositoryTestSuite) TearDownSuite() {
+	s.db.Close()
+}
+
+func (s *MarketRepositoryTestSuite) recreateTables() {
+	_, err := s.db.Exec(`
+  DROP TABLE IF EXISTS market_listings;
+  DROP TABLE IF EXISTS merch_variants;
+  DROP TABLE IF EXISTS merch_items CASCADE;
+  DROP TABLE IF EXISTS users CASCADE;
+
+  CREATE TABLE users (
+   id SERIAL PRIMARY KEY,
+   username VARCHAR(255) UNIQUE NOT NULL,
+   password_hash VARCHAR(255) NOT NULL,
+   coins INTEGER NOT NULL DEFAULT 1000,
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+
+  CREATE TABLE merch_items (
+   id SERIAL PRIMARY KEY,
+   name VARCHAR(255) UNIQUE NOT NULL,
+   price INTEGER NOT NULL CHECK (price > 0),
+   search_vector tsvector GENERATED ALWAYS AS (to_tsvector('english', name)) STORED,
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+
+  CREATE TABLE merch_variants (
+   id SERIAL PRIMARY KEY,
+   item_id INTEGER NOT NULL REFERENCES merch_items(id) ON DELETE CASCADE,
+   size VARCHAR(20) NOT NULL DEFAULT '',
+   color VARCHAR(50) NOT NULL DEFAULT ''
+  );
+
+  CREATE TABLE market_listings (
+   id SERIAL PRIMARY KEY,
+   seller_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+   item_id INTEGER NOT NULL REFERENCES merch_items(id),
+   variant_id INTEGER REFERENCES merch_variants(id) ON DELETE SET NULL,
+   quantity INTEGER NOT NULL CHECK (quantity > 0),
+   price INTEGER NOT NULL CHECK (price > 0),
+   status VARCHAR(20) NOT NULL DEFAULT 'active',
+   buyer_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+   fee INTEGER NOT NULL DEFAULT 0,
+   expires_at TIMESTAMP WITH TIME ZONE,
+   closed_at TIMESTAMP WITH TIME ZONE,
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+ `)
+	require.NoError(s.T(), err)
+}
+
+func (s *MarketRepositoryTestSuite) TestSearch() {
+	ctx := context.Background()
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	large := int64(1)
+
+	hoody := entity.Listing{SellerID: 1, ItemID: 1, VariantID: &large, Quantity: 1, Price: 250}
+	cup := entity.Listing{SellerID: 2, ItemID: 2, Quantity: 2, Price: 30}
+	stale := entity.Listing{SellerID: 2, ItemID: 2, Quantity: 1, Price: 10, ExpiresAt: &past}
+	for _, l := range []*entity.Listing{&hoody, &cup, &stale} {
+		s.NoError(s.repo.Create(ctx, l))
+	}
+	s.Equal(entity.ListingStatusActive, hoody.Status)
+
+	listings, total, err := s.repo.Search(ctx, entity.ListingQuery{CheapestFirst: true, Limit: 10}, now)
+	s.NoError(err)
+	s.Equal(int64(2), total)
+	s.Equal(cup.ID, listings[0].ID)
+	s.Equal("hoody", listings[1].ItemName)
+	s.Equal("L, black", listings[1].Variant)
+	s.Equal("user1", listings[1].Seller)
+
+	listings, total, err = s.repo.Search(ctx, entity.ListingQuery{Search: "hoodies", Limit: 10}, now)
+	s.NoError(err)
+	s.Equal(int64(1), total)
+	s.Equal(hoody.ID, listings[0].ID)
+
+	maxPrice := int64(100)
+	_, total, err = s.repo.Search(ctx, entity.ListingQuery{MaxPrice: &maxPrice, Limit: 10}, now)
+	s.NoError(err)
+	s.Equal(int64(1), total)
+
+	expired, err := s.repo.ListExpired(ctx, now, 10)
+	s.NoError(err)
+	s.Equal([]int64{stale.ID}, expired)
+}
+
+func (s *MarketRepositoryTestSuite) TestClose() {
+	ctx := context.Background()
+
+	listing := entity.Listing{SellerID: 1, ItemID: 2, Quantity: 1, Price: 30}
+	s.NoError(s.repo.Create(ctx, &listing))
+
+	got, err := s.repo.GetByID(ctx, listing.ID)
+	s.NoError(err)
+
+	buyer, now := int64(2), time.Now()
+	got.Status = entity.ListingStatusSold
+	got.BuyerID = &buyer
+	got.Fee = 3
+	got.ClosedAt = &now
+
+	closed, err := s.repo.Close(ctx, got)
+	s.NoError(err)
+	s.True(closed)
+
+	closed, err = s.repo.Close(ctx, got)
+	s.NoError(err)
+	s.False(closed)
+
+	mine, err := s.repo.ListBySeller(ctx, 1)
+	s.NoError(err)
+	s.Require().Len(mine, 1)
+	s.Equal(entity.ListingStatusSold, mine[0].Status)
+	s.Equal(int64(3), mine[0].Fee)
+
+	_, err = s.repo.GetByID(ctx, 100)
+	s.ErrorIs(err, entity.ErrListingNotFound)
+}
+
+func TestMarketRepository(t *testing.T) {
+	suite.Run(t, new(MarketRepositoryTestSuite))
+}
@@ -6,18 +6,60 @@ import (
 	"errors"
 	"fmt"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/repository"
+	"strings"
+	"time"
 )
 
+// searchConfig is the text search configuration merch_items.search_vector
+// is built with; queries have to use the same one.
+const searchConfig = "english"
+
+// currentPrice joins the latest merch_prices entry in effect as p; items
+// without a history keep the price stored on the item.
+const currentPrice = `
+        LEFT JOIN LATERAL (
+            SELECT price
+            FROM merch_prices
+            WHERE item_id = m.id AND effective_from <= now()
+            ORDER BY effective_from DESC
+            LIMIT 1
+        ) p ON TRUE`
+
+const effectivePrice = "COALESCE(p.price, m.price)"
+
+const selectItems = `
+        SELECT m.id, m.name, ` + effectivePrice + ` AS price, m.category_id, COALESCE(c.name, '') AS category,
+               m.description, m.image_url, m.tags, m.release_at, m.preorder_limit, m.created_at
+        FROM merch_items m
+        LEFT JOIN merch_categories c ON c.id = m.category_id` + currentPrice
+
 type dbConn interface {
 	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
 	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
 }
 
 type MerchRepository struct {
 	db dbConn
 }
 
+// merchItemRow scans the tags array, which entity.MerchItem keeps as a
+// plain slice.
+type merchItemRow struct {
+	entity.MerchItem
+	Tags pq.StringArray `db:"tags"`
+}
+
+func (r merchItemRow) item() entity.MerchItem {
+	item := r.MerchItem
+	item.Tags = []string(r.Tags)
+	return item
+}
+
 func NewMerchRepository(db *sqlx.DB) *MerchRepository {
 	return &MerchRepository{
 		db: db,
@@ -30,29 +72,34 @@ func (r *MerchRepository) WithTx(tx *sqlx.Tx) *MerchRepository {
 	}
 }
 
+// conn returns the transaction ctx carries, or the repository's own
+// connection outside of one.
+func (r *MerchRepository) conn(ctx context.Context) dbConn {
+	if tx, ok := repository.TxFromContext(ctx); ok {
+		return tx
+	}
+	return r.db
+}
+
 func (r *MerchRepository) List(ctx context.Context) ([]entity.MerchItem, error) {
-	var items []entity.MerchItem
-	query := `
-        SELECT id, name, price, created_at
-        FROM merch_items
-        ORDER BY id`
+	var rows []merchItemRow
+	query := selectItems + `
+        ORDER BY m.id`
 
-	err := r.db.SelectContext(ctx, &items, query)
+	err := r.conn(ctx).SelectContext(ctx, &rows, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list merch items: %w", err)
 	}
 
-	return items, nil
+	return items(rows), nil
 }
 
 func (r *MerchRepository) GetByID(ctx context.Context, id int64) (entity.MerchItem, error) {
-	var item entity.MerchItem
-	query := `
-        SELECT id, name, price, created_at
-        FROM merch_items
-        WHERE id = $1`
+	var row merchItemRow
+	query := selectItems + `
+        WHERE m.id = $1`
 
-	err := r.db.GetContext(ctx, &item, query, id)
+	err := r.conn(ctx).GetContext(ctx, &row, query, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return entity.MerchItem{}, entity.ErrMerchNotFound
@@ -60,17 +107,15 @@ func (r *MerchRepository) GetByID(ctx context.Context, id int64) (entity.MerchIt
 		return entity.MerchItem{}, fmt.Errorf("failed to get merch item by id: %w", err)
 	}
 
-	return item, nil
+	return row.item(), nil
 }
 
 func (r *MerchRepository) GetByName(ctx context.Context, name string) (entity.MerchItem, error) {
-	var item entity.MerchItem
-	query := `
-        SELECT id, name, price, created_at
-        FROM merch_items
-        WHERE name = $1`
+	var row merchItemRow
+	query := selectItems + `
+        WHERE m.name = $1`
 
-	err := r.db.GetContext(ctx, &item, query, name)
+	err := r.conn(ctx).GetContext(ctx, &row, query, name)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return entity.MerchItem{}, entity.ErrMerchNotFound
@@ -78,5 +123,393 @@ func (r *MerchRepository) GetByName(ctx context.Context, name string) (entity.Me
 		return entity.MerchItem{}, fmt.Errorf("failed to get merch item by name: %w", err)
 	}
 
-	return item, nil
+	return row.item(), nil
+}
+
+func (r *MerchRepository) ListCategories(ctx context.Context) ([]entity.MerchCategory, error) {
+	var categories []entity.MerchCategory
+	query := `
+        SELECT id, name, created_at
+        FROM merch_categories
+        ORDER BY name`
+
+	err := r.conn(ctx).SelectContext(ctx, &categories, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list merch categories: %w", err)
+	}
+
+	return categories, nil
+}
+
+// Search returns the page of the catalog selected by q along with the
+// number of items matching its filters across all pages.
+func (r *MerchRepository) Search(ctx context.Context, q entity.MerchQuery) ([]entity.MerchItem, int64, error) {
+	var (
+		conds []string
+		args  []interface{}
+	)
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	var tsquery string
+	if q.Search != "" {
+		tsquery = fmt.Sprintf("websearch_to_tsquery('%s', %s)", searchConfig, arg(q.Search))
+		conds = append(conds, "m.search_vector @@ "+tsquery)
+	}
+	if q.CategoryID != nil {
+		conds = append(conds, "m.category_id = "+arg(*q.CategoryID))
+	}
+	if len(q.Tags) > 0 {
+		conds = append(conds, "m.tags @> "+arg(pq.StringArray(q.Tags)))
+	}
+	if q.MinPrice != nil {
+		conds = append(conds, effectivePrice+" >= "+arg(*q.MinPrice))
+	}
+	if q.MaxPrice != nil {
+		conds = append(conds, effectivePrice+" <= "+arg(*q.MaxPrice))
+	}
+
+	where := ""
+	if len(conds) > 0 {
+		where = `
+        WHERE ` + strings.Join(conds, " AND ")
+	}
+
+	var total int64
+	countQuery := `
+        SELECT COUNT(*)
+        FROM merch_items m` + currentPrice + where
+
+	if err := r.conn(ctx).GetContext(ctx, &total, countQuery, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to count merch items: %w", err)
+	}
+
+	orderBy := "m.id"
+	switch q.Sort {
+	case entity.MerchSortPriceAsc:
+		orderBy = effectivePrice + ", m.id"
+	case entity.MerchSortPriceDesc:
+		orderBy = effectivePrice + " DESC, m.id"
+	case entity.MerchSortNewest:
+		orderBy = "m.created_at DESC, m.id DESC"
+	case entity.MerchSortName:
+		orderBy = "m.name"
+	case entity.MerchSortRelevance:
+		if tsquery != "" {
+			orderBy = fmt.Sprintf("ts_rank(m.search_vector, %s) DESC, m.id", tsquery)
+		}
+	}
+
+	query := selectItems + where + `
+        ORDER BY ` + orderBy + `
+        LIMIT ` + arg(q.Limit) + ` OFFSET ` + arg(q.Offset)
+
+	var rows []merchItemRow
+	if err := r.conn(ctx).SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to search merch items: %w", err)
+	}
+
+	return items(rows), total, nil
+}
+
+func (r *MerchRepository) ListVariants(ctx context.Context, itemID int64) ([]entity.MerchVariant, error) {
+	var variants []entity.MerchVariant
+	query := `
+        SELECT id, item_id, size, color, stock, price_delta, created_at
+        FROM merch_variants
+        WHERE item_id = $1
+        ORDER BY id`
+
+	err := r.conn(ctx).SelectContext(ctx, &variants, query, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list merch variants: %w", err)
+	}
+
+	return variants, nil
+}
+
+func (r *MerchRepository) GetVariant(ctx context.Context, id int64) (entity.MerchVariant, error) {
+	var variant entity.MerchVariant
+	query := `
+        SELECT id, item_id, size, color, stock, price_delta, created_at
+        FROM merch_variants
+        WHERE id = $1`
+
+	err := r.conn(ctx).GetContext(ctx, &variant, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.MerchVariant{}, entity.ErrVariantNotFound
+		}
+		return entity.MerchVariant{}, fmt.Errorf("failed to get merch variant: %w", err)
+	}
+
+	return variant, nil
+}
+
+// DecrementStock takes one unit of the variant off the shelf. It reports
+// false when the variant is already sold out.
+func (r *MerchRepository) DecrementStock(ctx context.Context, id int64) (bool, error) {
+	query := `
+        UPDATE merch_variants
+        SET stock = stock - 1
+        WHERE id = $1 AND stock > 0`
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to decrement merch variant stock: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return n > 0, nil
+}
+
+// IncrementStock puts one unit of the variant back on the shelf.
+func (r *MerchRepository) IncrementStock(ctx context.Context, id int64) error {
+	query := `
+        UPDATE merch_variants
+        SET stock = stock + 1
+        WHERE id = $1`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to increment merch variant stock: %w", err)
+	}
+
+	return nil
+}
+
+// AddPrice records a price taking effect at price.EffectiveFrom.
+func (r *MerchRepository) AddPrice(ctx context.Context, price *entity.MerchPrice) error {
+	query := `
+        INSERT INTO merch_prices (item_id, price, effective_from, created_by)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id, created_at`
+
+	err := r.conn(ctx).QueryRowContext(
+		ctx,
+		query,
+		price.ItemID,
+		price.Price,
+		price.EffectiveFrom,
+		price.CreatedBy,
+	).Scan(&price.ID, &price.CreatedAt)
+
+	if err != nil {
+		if isUniqueViolation(err) {
+			return entity.ErrPriceChangeConflict
+		}
+		return fmt.Errorf("failed to add merch price: %w", err)
+	}
+
+	return nil
+}
+
+// ListPrices returns the item's price history, scheduled changes included,
+// oldest first.
+func (r *MerchRepository) ListPrices(ctx context.Context, itemID int64) ([]entity.MerchPrice, error) {
+	var prices []entity.MerchPrice
+	query := `
+        SELECT id, item_id, price, effective_from, created_by, created_at
+        FROM merch_prices
+        WHERE item_id = $1
+        ORDER BY effective_from`
+
+	err := r.conn(ctx).SelectContext(ctx, &prices, query, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list merch prices: %w", err)
+	}
+
+	return prices, nil
+}
+
+// DeleteScheduledPrice removes a price change of the item that has not taken
+// effect by now. It reports false when there is no such change.
+func (r *MerchRepository) DeleteScheduledPrice(ctx context.Context, itemID, id int64, now time.Time) (bool, error) {
+	query := `
+        DELETE FROM merch_prices
+        WHERE id = $1 AND item_id = $2 AND effective_from > $3`
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, id, itemID, now)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete scheduled merch price: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return n > 0, nil
+}
+
+// SetRelease schedules the item's release and caps its pre-orders; nil
+// releaseAt puts the item on sale right away.
+func (r *MerchRepository) SetRelease(ctx context.Context, itemID int64, releaseAt *time.Time, preorderLimit *int64) error {
+	query := `
+        UPDATE merch_items
+        SET release_at = $1, preorder_limit = $2
+        WHERE id = $3`
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, releaseAt, preorderLimit, itemID)
+	if err != nil {
+		return fmt.Errorf("failed to set merch item release: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if n == 0 {
+		return entity.ErrMerchNotFound
+	}
+
+	return nil
+}
+
+// CreateBundle stores the bundle with its items. It should run in a
+// transaction so a bundle is never left without some of its items.
+func (r *MerchRepository) CreateBundle(ctx context.Context, bundle *entity.Bundle) error {
+	query := `
+        INSERT INTO merch_bundles (name, price, description, created_by)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id, created_at`
+
+	err := r.conn(ctx).QueryRowContext(
+		ctx,
+		query,
+		bundle.Name,
+		bundle.Price,
+		bundle.Description,
+		bundle.CreatedBy,
+	).Scan(&bundle.ID, &bundle.CreatedAt)
+
+	if err != nil {
+		if isUniqueViolation(err) {
+			return entity.ErrBundleExists
+		}
+		return fmt.Errorf("failed to create merch bundle: %w", err)
+	}
+
+	itemQuery := `
+        INSERT INTO merch_bundle_items (bundle_id, item_id, variant_id, quantity)
+        VALUES ($1, $2, $3, $4)`
+
+	for i := range bundle.Items {
+		bundle.Items[i].BundleID = bundle.ID
+		item := bundle.Items[i]
+		if _, err := r.conn(ctx).ExecContext(ctx, itemQuery, item.BundleID, item.ItemID, item.VariantID, item.Quantity); err != nil {
+			if isUniqueViolation(err) {
+				return entity.ErrDuplicateBundleItem
+			}
+			return fmt.Errorf("failed to add merch bundle item: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (r *MerchRepository) GetBundleByID(ctx context.Context, id int64) (entity.Bundle, error) {
+	return r.getBundle(ctx, "id", id)
+}
+
+func (r *MerchRepository) GetBundleByName(ctx context.Context, name string) (entity.Bundle, error) {
+	return r.getBundle(ctx, "name", name)
+}
+
+func (r *MerchRepository) getBundle(ctx context.Context, column string, value interface{}) (entity.Bundle, error) {
+	var bundle entity.Bundle
+	query := `
+        SELECT id, name, price, description, created_by, created_at
+        FROM merch_bundles
+        WHERE ` + column + ` = $1`
+
+	err := r.conn(ctx).GetContext(ctx, &bundle, query, value)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Bundle{}, entity.ErrBundleNotFound
+		}
+		return entity.Bundle{}, fmt.Errorf("failed to get merch bundle: %w", err)
+	}
+
+	items, err := r.bundleItems(ctx, []int64{bundle.ID})
+	if err != nil {
+		return entity.Bundle{}, err
+	}
+	bundle.Items = items[bundle.ID]
+
+	return bundle, nil
+}
+
+func (r *MerchRepository) ListBundles(ctx context.Context) ([]entity.Bundle, error) {
+	var bundles []entity.Bundle
+	query := `
+        SELECT id, name, price, description, created_by, created_at
+        FROM merch_bundles
+        ORDER BY id`
+
+	err := r.conn(ctx).SelectContext(ctx, &bundles, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list merch bundles: %w", err)
+	}
+	if len(bundles) == 0 {
+		return bundles, nil
+	}
+
+	ids := make([]int64, len(bundles))
+	for i, bundle := range bundles {
+		ids[i] = bundle.ID
+	}
+	items, err := r.bundleItems(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range bundles {
+		bundles[i].Items = items[bundles[i].ID]
+	}
+
+	return bundles, nil
+}
+
+// bundleItems returns the items of the bundles keyed by bundle ID.
+func (r *MerchRepository) bundleItems(ctx context.Context, bundleIDs []int64) (map[int64][]entity.BundleItem, error) {
+	var rows []entity.BundleItem
+	query := `
+        SELECT b.bundle_id, b.item_id, b.variant_id, b.quantity, m.name AS item_name
+        FROM merch_bundle_items b
+        JOIN merch_items m ON m.id = b.item_id
+        WHERE b.bundle_id = ANY($1)
+        ORDER BY b.bundle_id, m.name, b.variant_id`
+
+	err := r.conn(ctx).SelectContext(ctx, &rows, query, pq.Array(bundleIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list merch bundle items: %w", err)
+	}
+
+	result := make(map[int64][]entity.BundleItem, len(bundleIDs))
+	for _, row := range rows {
+		result[row.BundleID] = append(result[row.BundleID], row)
+	}
+	return result, nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == "23505"
+	}
+	return false
+}
+
+func items(rows []merchItemRow) []entity.MerchItem {
+	result := make([]entity.MerchItem, len(rows))
+	for i, row := range rows {
+		result[i] = row.item()
+	}
+	return result
 }
@@ -29,7 +29,7 @@ func (s *MerchRepositoryTestSuite) SetupSuite() {
 }
 
 func (s *MerchRepositoryTestSuite) SetupTest() {
-	_, err := s.db.Exec("TRUNCATE TABLE merch_items RESTART IDENTITY")
+	_, err := s.db.Exec("TRUNCATE TABLE merch_bundle_items, merch_bundles, merch_prices, merch_variants, merch_items, merch_categories RESTART IDENTITY")
 	require.NoError(s.T(), err)
 }
 
@@ -39,13 +39,68 @@ func (s *MerchRepositoryTestSuite) TearDownSuite() {
 
 func (s *MerchRepositoryTestSuite) recreateTable() {
 	_, err := s.db.Exec(`
+        DROP TABLE IF EXISTS merch_bundle_items;
+        DROP TABLE IF EXISTS merch_bundles;
+        DROP TABLE IF EXISTS merch_prices;
+        DROP TABLE IF EXISTS merch_variants;
         DROP TABLE IF EXISTS merch_items;
+        DROP TABLE IF EXISTS merch_categories;
+        CREATE TABLE merch_categories (
+            id SERIAL PRIMARY KEY,
+            name VARCHAR(100) UNIQUE NOT NULL,
+            created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+        );
         CREATE TABLE merch_items (
             id SERIAL PRIMARY KEY,
             name VARCHAR(255) UNIQUE NOT NULL,
             price INTEGER NOT NULL CHECK (price > 0),
+            category_id INTEGER REFERENCES merch_categories(id) ON DELETE SET NULL,
+            description TEXT NOT NULL DEFAULT '',
+            image_url VARCHAR(2048),
+            tags TEXT[] NOT NULL DEFAULT '{}',
+            search_vector tsvector GENERATED ALWAYS AS (
+                setweight(to_tsvector('english', name), 'A') ||
+                setweight(to_tsvector('english', description), 'B')
+            ) STORED,
+            release_at TIMESTAMP WITH TIME ZONE,
+            preorder_limit INTEGER,
+            created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+        );
+        CREATE TABLE merch_variants (
+            id SERIAL PRIMARY KEY,
+            item_id INTEGER NOT NULL REFERENCES merch_items(id) ON DELETE CASCADE,
+            size VARCHAR(20) NOT NULL DEFAULT '',
+            color VARCHAR(50) NOT NULL DEFAULT '',
+            stock INTEGER NOT NULL DEFAULT 0 CHECK (stock >= 0),
+            price_delta INTEGER NOT NULL DEFAULT 0,
+            created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+            CONSTRAINT unique_item_variant UNIQUE(item_id, size, color)
+        );
+        CREATE TABLE merch_prices (
+            id SERIAL PRIMARY KEY,
+            item_id INTEGER NOT NULL REFERENCES merch_items(id) ON DELETE CASCADE,
+            price INTEGER NOT NULL CHECK (price > 0),
+            effective_from TIMESTAMP WITH TIME ZONE NOT NULL,
+            created_by INTEGER,
+            created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+            CONSTRAINT unique_item_price_from UNIQUE(item_id, effective_from)
+        );
+        CREATE TABLE merch_bundles (
+            id SERIAL PRIMARY KEY,
+            name VARCHAR(255) UNIQUE NOT NULL,
+            price INTEGER NOT NULL CHECK (price > 0),
+            description TEXT NOT NULL DEFAULT '',
+            created_by INTEGER,
             created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
-        )
+        );
+        CREATE TABLE merch_bundle_items (
+            bundle_id INTEGER NOT NULL REFERENCES merch_bundles(id) ON DELETE CASCADE,
+            item_id INTEGER NOT NULL REFERENCES merch_items(id),
+            variant_id INTEGER REFERENCES merch_variants(id),
+            quantity INTEGER NOT NULL DEFAULT 1 CHECK (quantity > 0)
+        );
+        CREATE UNIQUE INDEX idx_merch_bundle_items_unique
+            ON merch_bundle_items(bundle_id, item_id, COALESCE(variant_id, 0))
     `)
 	require.NoError(s.T(), err)
 }
@@ -111,6 +166,21 @@ func (s *MerchRepositoryTestSuite) TestGetByID() {
 		_, err := s.repo.GetByID(ctx, 99999)
 		s.ErrorIs(err, entity.ErrMerchNotFound)
 	})
+
+	s.Run("upcoming release", func() {
+		releaseAt := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+		limit := int64(50)
+		s.NoError(s.repo.SetRelease(ctx, itemID, &releaseAt, &limit))
+
+		found, err := s.repo.GetByID(ctx, itemID)
+		s.NoError(err)
+		s.Require().NotNil(found.ReleaseAt)
+		s.True(releaseAt.Equal(*found.ReleaseAt))
+		s.Equal(&limit, found.PreorderLimit)
+		s.False(found.IsReleased(time.Now()))
+
+		s.ErrorIs(s.repo.SetRelease(ctx, 99999, nil, nil), entity.ErrMerchNotFound)
+	})
 }
 
 func (s *MerchRepositoryTestSuite) TestGetByName() {
@@ -147,6 +217,230 @@ func (s *MerchRepositoryTestSuite) TestGetByName() {
 	})
 }
 
+func (s *MerchRepositoryTestSuite) TestSearch() {
+	ctx := context.Background()
+
+	_, err := s.db.Exec(`
+        INSERT INTO merch_categories (name) VALUES ('clothing'), ('office')
+    `)
+	s.NoError(err)
+
+	_, err = s.db.Exec(`
+        INSERT INTO merch_items (name, price, category_id, description, tags, created_at)
+        VALUES
+        ('hoodie', 300, 1, 'Warm cotton hoodie with the company logo', '{winter,logo}', now() - interval '3 days'),
+        ('t-shirt', 80, 1, 'Cotton shirt', '{summer,logo}', now() - interval '2 days'),
+        ('pen', 10, 2, 'Ballpoint pen', '{}', now() - interval '1 day'),
+        ('socks', 10, 1, 'Socks with a hoodie pattern', '{winter}', now())
+    `)
+	s.NoError(err)
+
+	ids := func(items []entity.MerchItem) []int64 {
+		result := make([]int64, len(items))
+		for i, item := range items {
+			result[i] = item.ID
+		}
+		return result
+	}
+	price := func(v int64) *int64 { return &v }
+	clothing := int64(1)
+
+	s.Run("full-text search ranks name matches first", func() {
+		items, total, err := s.repo.Search(ctx, entity.MerchQuery{Search: "hoodies", Sort: entity.MerchSortRelevance, Limit: 10})
+		s.NoError(err)
+		s.Equal(int64(2), total)
+		s.Equal([]int64{1, 4}, ids(items))
+		s.Equal("clothing", items[0].Category)
+		s.Equal([]string{"winter", "logo"}, items[0].Tags)
+	})
+
+	s.Run("filters combine", func() {
+		items, total, err := s.repo.Search(ctx, entity.MerchQuery{
+			CategoryID: &clothing,
+			Tags:       []string{"winter"},
+			MinPrice:   price(50),
+			Limit:      10,
+		})
+		s.NoError(err)
+		s.Equal(int64(1), total)
+		s.Equal([]int64{1}, ids(items))
+	})
+
+	s.Run("sorted and paginated", func() {
+		items, total, err := s.repo.Search(ctx, entity.MerchQuery{
+			MaxPrice: price(100),
+			Sort:     entity.MerchSortPriceDesc,
+			Limit:    2,
+			Offset:   1,
+		})
+		s.NoError(err)
+		s.Equal(int64(3), total)
+		s.Equal([]int64{3, 4}, ids(items))
+	})
+
+	s.Run("categories", func() {
+		categories, err := s.repo.ListCategories(ctx)
+		s.NoError(err)
+		s.Len(categories, 2)
+		s.Equal("clothing", categories[0].Name)
+	})
+}
+
+func (s *MerchRepositoryTestSuite) TestVariants() {
+	ctx := context.Background()
+
+	_, err := s.db.Exec(`
+        INSERT INTO merch_items (name, price) VALUES ('hoody', 300), ('pen', 10)
+    `)
+	s.NoError(err)
+
+	_, err = s.db.Exec(`
+        INSERT INTO merch_variants (item_id, size, color, stock, price_delta)
+        VALUES (1, 'M', '', 2, 0), (1, 'XL', 'black', 1, 20)
+    `)
+	s.NoError(err)
+
+	s.Run("list", func() {
+		variants, err := s.repo.ListVariants(ctx, 1)
+		s.NoError(err)
+		s.Len(variants, 2)
+		s.Equal("XL, black", variants[1].Label())
+		s.Equal(int64(20), variants[1].PriceDelta)
+
+		variants, err = s.repo.ListVariants(ctx, 2)
+		s.NoError(err)
+		s.Empty(variants)
+	})
+
+	s.Run("get", func() {
+		variant, err := s.repo.GetVariant(ctx, 1)
+		s.NoError(err)
+		s.Equal(int64(1), variant.ItemID)
+		s.Equal("M", variant.Size)
+
+		_, err = s.repo.GetVariant(ctx, 999)
+		s.ErrorIs(err, entity.ErrVariantNotFound)
+	})
+
+	s.Run("stock runs out", func() {
+		ok, err := s.repo.DecrementStock(ctx, 2)
+		s.NoError(err)
+		s.True(ok)
+
+		ok, err = s.repo.DecrementStock(ctx, 2)
+		s.NoError(err)
+		s.False(ok)
+
+		variant, err := s.repo.GetVariant(ctx, 2)
+		s.NoError(err)
+		s.Zero(variant.Stock)
+	})
+
+	s.Run("stock is given back", func() {
+		s.NoError(s.repo.IncrementStock(ctx, 2))
+
+		variant, err := s.repo.GetVariant(ctx, 2)
+		s.NoError(err)
+		s.Equal(int64(1), variant.Stock)
+	})
+}
+
+func (s *MerchRepositoryTestSuite) TestBundles() {
+	ctx := context.Background()
+
+	_, err := s.db.Exec(`
+        INSERT INTO merch_items (name, price) VALUES ('t-shirt', 80), ('cup', 20), ('pen', 10);
+        INSERT INTO merch_variants (item_id, size, stock) VALUES (1, 'M', 5)
+    `)
+	s.NoError(err)
+
+	variantID := int64(1)
+	bundle := entity.Bundle{
+		Name:  "welcome pack",
+		Price: 90,
+		Items: []entity.BundleItem{
+			{ItemID: 1, VariantID: &variantID, Quantity: 1},
+			{ItemID: 2, Quantity: 1},
+			{ItemID: 3, Quantity: 2},
+		},
+	}
+	s.NoError(s.repo.CreateBundle(ctx, &bundle))
+	s.NotZero(bundle.ID)
+
+	dup := entity.Bundle{Name: "welcome pack", Price: 10, Items: []entity.BundleItem{{ItemID: 3, Quantity: 1}}}
+	s.ErrorIs(s.repo.CreateBundle(ctx, &dup), entity.ErrBundleExists)
+
+	found, err := s.repo.GetBundleByName(ctx, "welcome pack")
+	s.NoError(err)
+	s.Equal(int64(90), found.Price)
+	s.Require().Len(found.Items, 3)
+	s.Equal("cup", found.Items[0].ItemName)
+	s.Equal(int64(2), found.Items[1].Quantity)
+
+	byID, err := s.repo.GetBundleByID(ctx, bundle.ID)
+	s.NoError(err)
+	s.Equal(found, byID)
+
+	bundles, err := s.repo.ListBundles(ctx)
+	s.NoError(err)
+	s.Require().Len(bundles, 1)
+	s.Len(bundles[0].Items, 3)
+
+	_, err = s.repo.GetBundleByName(ctx, "starter pack")
+	s.ErrorIs(err, entity.ErrBundleNotFound)
+}
+
+func (s *MerchRepositoryTestSuite) TestPriceHistory() {
+	ctx := context.Background()
+	now := time.Now()
+
+	_, err := s.db.Exec(`
+        INSERT INTO merch_items (name, price) VALUES ('hoody', 300), ('pen', 10)
+    `)
+	s.NoError(err)
+
+	past := entity.MerchPrice{ItemID: 1, Price: 250, EffectiveFrom: now.Add(-time.Hour)}
+	future := entity.MerchPrice{ItemID: 1, Price: 400, EffectiveFrom: now.Add(24 * time.Hour)}
+	s.NoError(s.repo.AddPrice(ctx, &past))
+	s.NoError(s.repo.AddPrice(ctx, &future))
+
+	clash := entity.MerchPrice{ItemID: 1, Price: 500, EffectiveFrom: future.EffectiveFrom}
+	s.ErrorIs(s.repo.AddPrice(ctx, &clash), entity.ErrPriceChangeConflict)
+
+	s.Run("current price comes from the history", func() {
+		item, err := s.repo.GetByName(ctx, "hoody")
+		s.NoError(err)
+		s.Equal(int64(250), item.Price)
+
+		// no history, the item's own price stands
+		item, err = s.repo.GetByName(ctx, "pen")
+		s.NoError(err)
+		s.Equal(int64(10), item.Price)
+
+		items, total, err := s.repo.Search(ctx, entity.MerchQuery{MaxPrice: func(v int64) *int64 { return &v }(260), Limit: 10})
+		s.NoError(err)
+		s.Equal(int64(2), total)
+		s.Len(items, 2)
+	})
+
+	s.Run("history", func() {
+		prices, err := s.repo.ListPrices(ctx, 1)
+		s.NoError(err)
+		s.Len(prices, 2)
+		s.Equal(int64(400), prices[1].Price)
+	})
+
+	s.Run("only scheduled changes can be deleted", func() {
+		ok, err := s.repo.DeleteScheduledPrice(ctx, 1, past.ID, now)
+		s.NoError(err)
+		s.False(ok)
+
+		ok, err = s.repo.DeleteScheduledPrice(ctx, 1, future.ID, now)
+		s.NoError(err)
+		s.True(ok)
+	})
+}
+
 func (s *MerchRepositoryTestSuite) TestTransaction() {
 	ctx := context.Background()
 	s.Run("successful transaction", func() {
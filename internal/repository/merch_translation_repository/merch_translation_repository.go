@@ -0,0 +1,123 @@
+package merch_translation_repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/repository"
+)
+
+type dbConn interface {
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// MerchTranslationRepository stores catalog translations, machine-made and
+// human-written alike.
+type MerchTranslationRepository struct {
+	db dbConn
+}
+
+func NewMerchTranslationRepository(db *sqlx.DB) *MerchTranslationRepository {
+	return &MerchTranslationRepository{
+		db: db,
+	}
+}
+
+func (r *MerchTranslationRepository) WithTx(tx *sqlx.Tx) *MerchTranslationRepository {
+	return &MerchTranslationRepository{
+		db: tx,
+	}
+}
+
+// conn returns the transaction ctx carries, or the repository's own
+// connection outside of one.
+func (r *MerchTranslationRepository) conn(ctx context.Context) dbConn {
+	if tx, ok := repository.TxFromContext(ctx); ok {
+		return tx
+	}
+	return r.db
+}
+
+// ListByLocale returns the translations into locale of the given items.
+// Items without one are left out.
+func (r *MerchTranslationRepository) ListByLocale(ctx context.Context, locale string, itemIDs []int64) ([]entity.MerchTranslation, error) {
+	var translations []entity.MerchTranslation
+	query := `
+  SELECT item_id, locale, name, description, source, source_name, source_description, updated_at
+  FROM merch_item_translations
+  WHERE locale = $1
+   AND item_id = ANY($2)
+  ORDER BY item_id`
+
+	err := r.conn(ctx).SelectContext(ctx, &translations, query, locale, pq.Array(itemIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list merch translations: %w", err)
+	}
+
+	return translations, nil
+}
+
+// Save inserts or replaces the item's translation into its locale. A
+// machine translation never replaces a human one; Save reports whether the
+// translation was stored.
+func (r *MerchTranslationRepository) Save(ctx context.Context, t *entity.MerchTranslation) (bool, error) {
+	query := `
+  INSERT INTO merch_item_translations (item_id, locale, name, description, source, source_name, source_description)
+  VALUES ($1, $2, $3, $4, $5, $6, $7)
+  ON CONFLICT (item_id, locale) DO UPDATE
+  SET name = EXCLUDED.name,
+   description = EXCLUDED.description,
+   source = EXCLUDED.source,
+   source_name = EXCLUDED.source_name,
+   source_description = EXCLUDED.source_description,
+   updated_at = CURRENT_TIMESTAMP
+  WHERE EXCLUDED.source = 'human'
+   OR merch_item_translations.source = 'machine'
+  RETURNING updated_at`
+
+	err := r.conn(ctx).QueryRowContext(
+		ctx,
+		query,
+		t.ItemID,
+		t.Locale,
+		t.Name,
+		t.Description,
+		t.Source,
+		t.SourceName,
+		t.SourceDescription,
+	).Scan(&t.UpdatedAt)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to save merch translation: %w", err)
+	}
+
+	return true, nil
+}
+
+func (r *MerchTranslationRepository) Delete(ctx context.Context, itemID int64, locale string) (bool, error) {
+	query := `
+  DELETE FROM merch_item_translations
+  WHERE item_id = $1
+   AND locale = $2`
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, itemID, locale)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete merch translation: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete merch translation: %w", err)
+	}
+
+	return n > 0, nil
+}
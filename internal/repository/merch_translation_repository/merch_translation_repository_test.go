@@ -0,0 +1,120 @@
+package merch_translation_repository
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/testutils"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type MerchTranslationRepositoryTestSuite struct {
+	suite.Suite
+	db   *sqlx.DB
+	repo *MerchTranslationRepository
+}
+
+func (s *MerchTranslationRepositoryTestSuite) SetupSuite() {
+	db, err := testutils.GetTestDB()
+	require.NoError(s.T(), err)
+
+	s.db = db
+	s.repo = NewMerchTranslationRepository(db)
+
+	s.recreateTables()
+}
+
+func (s *MerchTranslationRepositoryTestSuite) SetupTest() {
+	_, err := s.db.Exec("TRUNCATE TABLE merch_item_translations, merch_items RESTART IDENTITY CASCADE")
+	require.NoError(s.T(), err)
+
+	_, err = s.db.Exec(`INSERT INTO merch_items (name, price, description) VALUES ('cup', 20, 'a cup'), ('pen', 10, '')`)
+	require.NoError(s.T(), err)
+}
+
+func (s *MerchTranslationRepositoryTestSuite) TearDownSuite() {
+	s.db.Close()
+}
+
+func (s *MerchTranslationRepositoryTestSuite) recreateTables() {
+	_, err := s.db.Exec(`
+  DROP TABLE IF EXISTS merch_item_translations;
+  DROP TABLE IF EXISTS merch_items CASCADE;
+
+  CREATE TABLE merch_items (
+   id SERIAL PRIMARY KEY,
+   name VARCHAR(255) UNIQUE NOT NULL,
+   price INTEGER NOT NULL CHECK (price > 0),
+   description TEXT NOT NULL DEFAULT '',
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+
+  CREATE TABLE merch_item_translations (
+   item_id INTEGER NOT NULL REFERENCES merch_items(id) ON DELETE CASCADE,
+   locale VARCHAR(8) NOT NULL,
+   name VARCHAR(255) NOT NULL,
+   description TEXT NOT NULL DEFAULT '',
+   source VARCHAR(20) NOT NULL,
+   source_name VARCHAR(255) NOT NULL,
+   source_description TEXT NOT NULL DEFAULT '',
+   updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+   PRIMARY KEY (item_id, locale)
+  );
+ `)
+	require.NoError(s.T(), err)
+}
+
+func (s *MerchTranslationRepositoryTestSuite) TestHumanOverridesMachine() {
+	ctx := context.Background()
+
+	machine := entity.MerchTranslation{
+		ItemID:            1,
+		Locale:            "ru",
+		Name:              "чашка",
+		Description:       "чашка",
+		Source:            entity.TranslationSourceMachine,
+		SourceName:        "cup",
+		SourceDescription: "a cup",
+	}
+	saved, err := s.repo.Save(ctx, &machine)
+	s.NoError(err)
+	s.True(saved)
+	s.False(machine.UpdatedAt.IsZero())
+
+	human := machine
+	human.Name = "кружка"
+	human.Source = entity.TranslationSourceHuman
+	saved, err = s.repo.Save(ctx, &human)
+	s.NoError(err)
+	s.True(saved)
+
+	// the machine translation made concurrently does not win
+	saved, err = s.repo.Save(ctx, &machine)
+	s.NoError(err)
+	s.False(saved)
+
+	translations, err := s.repo.ListByLocale(ctx, "ru", []int64{1, 2})
+	s.NoError(err)
+	s.Require().Len(translations, 1)
+	s.Equal("кружка", translations[0].Name)
+	s.Equal(entity.TranslationSourceHuman, translations[0].Source)
+
+	translations, err = s.repo.ListByLocale(ctx, "de", []int64{1, 2})
+	s.NoError(err)
+	s.Empty(translations)
+
+	deleted, err := s.repo.Delete(ctx, 1, "ru")
+	s.NoError(err)
+	s.True(deleted)
+
+	deleted, err = s.repo.Delete(ctx, 1, "ru")
+	s.NoError(err)
+	s.False(deleted)
+}
+
+func TestMerchTranslationRepository(t *testing.T) {
+	suite.Run(t, new(MerchTranslationRepositoryTestSuite))
+}
@@ -0,0 +1,117 @@
+package notification_repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/repository"
+)
+
+type dbConn interface {
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// NotificationRepository keeps the in-app notifications list. It is the
+// default Notifier of the use cases.
+type NotificationRepository struct {
+	db dbConn
+}
+
+func NewNotificationRepository(db *sqlx.DB) *NotificationRepository {
+	return &NotificationRepository{
+		db: db,
+	}
+}
+
+func (r *NotificationRepository) WithTx(tx *sqlx.Tx) *NotificationRepository {
+	return &NotificationRepository{
+		db: tx,
+	}
+}
+
+// conn returns the transaction ctx carries, or the repository's own
+// connection outside of one.
+func (r *NotificationRepository) conn(ctx context.Context) dbConn {
+	if tx, ok := repository.TxFromContext(ctx); ok {
+		return tx
+	}
+	return r.db
+}
+
+// Notify adds the message to the user's notifications.
+func (r *NotificationRepository) Notify(ctx context.Context, userID int64, message string) error {
+	query := `
+  INSERT INTO notifications (user_id, message)
+  VALUES ($1, $2)`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, userID, message)
+	if err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	return nil
+}
+
+// List returns the user's latest notifications, newest first.
+func (r *NotificationRepository) List(ctx context.Context, userID int64, unreadOnly bool, limit int) ([]entity.Notification, error) {
+	var notifications []entity.Notification
+	query := `
+  SELECT id, user_id, message, read_at, created_at
+  FROM notifications
+  WHERE user_id = $1
+   AND (NOT $2 OR read_at IS NULL)
+  ORDER BY id DESC
+  LIMIT $3`
+
+	err := r.conn(ctx).SelectContext(ctx, &notifications, query, userID, unreadOnly, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+
+	return notifications, nil
+}
+
+// MarkRead marks the user's notification as read. It reports false when
+// the user has no such notification.
+func (r *NotificationRepository) MarkRead(ctx context.Context, userID, id int64) (bool, error) {
+	query := `
+  UPDATE notifications
+  SET read_at = COALESCE(read_at, now())
+  WHERE id = $1 AND user_id = $2`
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, id, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark notification as read: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+// MarkAllRead marks every unread notification of the user as read and
+// returns how many there were.
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, userID int64) (int64, error) {
+	query := `
+  UPDATE notifications
+  SET read_at = now()
+  WHERE user_id = $1 AND read_at IS NULL`
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications as read: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,107 @@
+package notification_repository
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/smthjapanese/avito-merch/internal/testutils"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type NotificationRepositoryTestSuite struct {
+	suite.Suite
+	db   *sqlx.DB
+	repo *NotificationRepository
+}
+
+func (s *NotificationRepositoryTestSuite) SetupSuite() {
+	db, err := testutils.GetTestDB()
+	require.NoError(s.T(), err)
+
+	s.db = db
+	s.repo = NewNotificationRepository(db)
+
+	s.recreateTables()
+}
+
+func (s *NotificationRepositoryTestSuite) SetupTest() {
+	_, err := s.db.Exec("TRUNCATE TABLE notifications, users RESTART IDENTITY CASCADE")
+	require.NoError(s.T(), err)
+
+	_, err = s.db.Exec(`
+  INSERT INTO users (username, password_hash, coins) VALUES ('user1', 'hash1', 1000), ('user2', 'hash2', 1000)`)
+	require.NoError(s.T(), err)
+}
+
+func (s *NotificationRepositoryTestSuite) TearDownSuite() {
+	s.db.Close()
+}
+
+func (s *NotificationRepositoryTestSuite) recreateTables() {
+	_, err := s.db.Exec(`
+  DROP TABLE IF EXISTS notifications;
+  DROP TABLE IF EXISTS users CASCADE;
+
+  CREATE TABLE users (
+   id SERIAL PRIMARY KEY,
+   username VARCHAR(255) UNIQUE NOT NULL,
+   password_hash VARCHAR(255) NOT NULL,
+   coins INTEGER NOT NULL DEFAULT 1000,
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+
+  CREATE TABLE notifications (
+   id SERIAL PRIMARY KEY,
+   user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+   message TEXT NOT NULL,
+   read_at TIMESTAMP WITH TIME ZONE,
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+ `)
+	require.NoError(s.T(), err)
+}
+
+func (s *NotificationRepositoryTestSuite) TestNotifyAndRead() {
+	ctx := context.Background()
+
+	s.NoError(s.repo.Notify(ctx, 1, "first"))
+	s.NoError(s.repo.Notify(ctx, 1, "second"))
+	s.NoError(s.repo.Notify(ctx, 2, "other"))
+
+	all, err := s.repo.List(ctx, 1, false, 10)
+	s.NoError(err)
+	s.Require().Len(all, 2)
+	s.Equal("second", all[0].Message)
+	s.False(all[0].IsRead())
+
+	read, err := s.repo.MarkRead(ctx, 1, all[0].ID)
+	s.NoError(err)
+	s.True(read)
+
+	read, err = s.repo.MarkRead(ctx, 2, all[1].ID)
+	s.NoError(err)
+	s.False(read)
+
+	unread, err := s.repo.List(ctx, 1, true, 10)
+	s.NoError(err)
+	s.Require().Len(unread, 1)
+	s.Equal("first", unread[0].Message)
+
+	marked, err := s.repo.MarkAllRead(ctx, 1)
+	s.NoError(err)
+	s.Equal(int64(1), marked)
+
+	unread, err = s.repo.List(ctx, 1, true, 10)
+	s.NoError(err)
+	s.Empty(unread)
+
+	other, err := s.repo.List(ctx, 2, true, 10)
+	s.NoError(err)
+	s.Len(other, 1)
+}
+
+func TestNotificationRepository(t *testing.T) {
+	suite.Run(t, new(NotificationRepositoryTestSuite))
+}
@@ -0,0 +1,229 @@
+package order_repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/repository"
+)
+
+const selectOrders = `
+  SELECT o.id, o.user_id, o.item_id, o.variant_id, o.price, o.location_id,
+   COALESCE(l.name, '') AS location, o.status, o.updated_by, o.created_at, o.updated_at
+  FROM orders o
+  LEFT JOIN pickup_locations l ON l.id = o.location_id`
+
+type dbConn interface {
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type OrderRepository struct {
+	db dbConn
+}
+
+func NewOrderRepository(db *sqlx.DB) *OrderRepository {
+	return &OrderRepository{
+		db: db,
+	}
+}
+
+func (r *OrderRepository) WithTx(tx *sqlx.Tx) *OrderRepository {
+	return &OrderRepository{
+		db: tx,
+	}
+}
+
+// conn returns the transaction ctx carries, or the repository's own
+// connection outside of one.
+func (r *OrderRepository) conn(ctx context.Context) dbConn {
+	if tx, ok := repository.TxFromContext(ctx); ok {
+		return tx
+	}
+	return r.db
+}
+
+func (r *OrderRepository) Create(ctx context.Context, order *entity.Order) error {
+	if order.Status == "" {
+		order.Status = entity.OrderStatusPlaced
+	}
+
+	query := `
+  INSERT INTO orders (user_id, item_id, variant_id, price, location_id, status)
+  VALUES ($1, $2, $3, $4, $5, $6)
+  RETURNING id, created_at, updated_at`
+
+	err := r.conn(ctx).QueryRowContext(
+		ctx,
+		query,
+		order.UserID,
+		order.ItemID,
+		order.VariantID,
+		order.Price,
+		order.LocationID,
+		order.Status,
+	).Scan(&order.ID, &order.CreatedAt, &order.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create order: %w", err)
+	}
+
+	for _, lot := range order.Lots {
+		query := `
+  INSERT INTO order_coin_lots (order_id, amount, granted_at, expires_at)
+  VALUES ($1, $2, $3, $4)`
+
+		_, err := r.conn(ctx).ExecContext(ctx, query, order.ID, lot.Amount, lot.GrantedAt, lot.ExpiresAt)
+		if err != nil {
+			return fmt.Errorf("failed to store order coin lot: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ListLots returns the coins the order was paid with, oldest first. Orders
+// placed before they were recorded, and free ones, have none.
+func (r *OrderRepository) ListLots(ctx context.Context, orderID int64) ([]entity.CoinLot, error) {
+	query := `
+  SELECT amount, amount AS remaining, granted_at, expires_at
+  FROM order_coin_lots
+  WHERE order_id = $1
+  ORDER BY granted_at, id`
+
+	var lots []entity.CoinLot
+	err := r.conn(ctx).SelectContext(ctx, &lots, query, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list order coin lots: %w", err)
+	}
+
+	return lots, nil
+}
+
+// GetByID locks the order for the rest of the transaction.
+func (r *OrderRepository) GetByID(ctx context.Context, id int64) (entity.Order, error) {
+	var order entity.Order
+	query := selectOrders + `
+  WHERE o.id = $1
+  FOR UPDATE OF o`
+
+	err := r.conn(ctx).GetContext(ctx, &order, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Order{}, entity.ErrOrderNotFound
+		}
+		return entity.Order{}, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	return order, nil
+}
+
+func (r *OrderRepository) ListByUser(ctx context.Context, userID int64) ([]entity.Order, error) {
+	var orders []entity.Order
+	query := selectOrders + `
+  WHERE o.user_id = $1
+  ORDER BY o.created_at DESC, o.id DESC`
+
+	err := r.conn(ctx).SelectContext(ctx, &orders, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user orders: %w", err)
+	}
+
+	return orders, nil
+}
+
+// List returns the orders in the given status, oldest first, so they are
+// worked through in the order they were placed. An empty status lists the
+// orders that are not delivered or cancelled yet.
+func (r *OrderRepository) List(ctx context.Context, status entity.OrderStatus) ([]entity.Order, error) {
+	var orders []entity.Order
+	var err error
+
+	if status == "" {
+		query := selectOrders + `
+  WHERE o.status NOT IN ('delivered', 'cancelled')
+  ORDER BY o.created_at, o.id`
+		err = r.conn(ctx).SelectContext(ctx, &orders, query)
+	} else {
+		query := selectOrders + `
+  WHERE o.status = $1
+  ORDER BY o.created_at, o.id`
+		err = r.conn(ctx).SelectContext(ctx, &orders, query, status)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orders: %w", err)
+	}
+
+	return orders, nil
+}
+
+// CountOpen returns how many of the user's orders for the item are not
+// delivered or cancelled yet. The units behind them are still waiting to be
+// handed over.
+func (r *OrderRepository) CountOpen(ctx context.Context, userID, itemID int64, variantID *int64) (int64, error) {
+	var count int64
+	query := `
+  SELECT COUNT(*)
+  FROM orders
+  WHERE user_id = $1 AND item_id = $2 AND COALESCE(variant_id, 0) = COALESCE($3, 0)
+   AND status NOT IN ('delivered', 'cancelled')`
+
+	err := r.conn(ctx).GetContext(ctx, &count, query, userID, itemID, variantID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count open orders: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *OrderRepository) Update(ctx context.Context, order entity.Order) error {
+	query := `
+  UPDATE orders
+  SET status = $2, location_id = $3, updated_by = $4, updated_at = $5
+  WHERE id = $1`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, order.ID, order.Status, order.LocationID, order.UpdatedBy, order.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update order: %w", err)
+	}
+
+	return nil
+}
+
+func (r *OrderRepository) ListLocations(ctx context.Context) ([]entity.PickupLocation, error) {
+	var locations []entity.PickupLocation
+	query := `
+  SELECT id, name, address, created_at
+  FROM pickup_locations
+  ORDER BY name`
+
+	err := r.conn(ctx).SelectContext(ctx, &locations, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pickup locations: %w", err)
+	}
+
+	return locations, nil
+}
+
+func (r *OrderRepository) GetLocation(ctx context.Context, id int64) (entity.PickupLocation, error) {
+	var location entity.PickupLocation
+	query := `
+  SELECT id, name, address, created_at
+  FROM pickup_locations
+  WHERE id = $1`
+
+	err := r.conn(ctx).GetContext(ctx, &location, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.PickupLocation{}, entity.ErrPickupLocationNotFound
+		}
+		return entity.PickupLocation{}, fmt.Errorf("failed to get pickup location: %w", err)
+	}
+
+	return location, nil
+}
@@ -0,0 +1,212 @@
+package order_repository
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/testutils"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type OrderRepositoryTestSuite struct {
+	suite.Suite
+	db   *sqlx.DB
+	repo *OrderRepository
+}
+
+func (s *OrderRepositoryTestSuite) SetupSuite() {
+	db, err := testutils.GetTestDB()
+	require.NoError(s.T(), err)
+
+	s.db = db
+	s.repo = NewOrderRepository(db)
+
+	s.recreateTables()
+}
+
+func (s *OrderRepositoryTestSuite) SetupTest() {
+	_, err := s.db.Exec("TRUNCATE TABLE orders, pickup_locations, merch_items, users RESTART IDENTITY CASCADE")
+	require.NoError(s.T(), err)
+
+	_, err = s.db.Exec(`
+  INSERT INTO users (username, password_hash, coins)
+  VALUES
+  ('user1', 'hash1', 1000),
+  ('admin', 'hash2', 1000);
+
+  INSERT INTO merch_items (name, price) VALUES ('cup', 20);
+
+  INSERT INTO pickup_locations (name, address) VALUES ('Moscow', 'Lva Tolstogo 16')`)
+	require.NoError(s.T(), err)
+}
+
+func (s *OrderRepositoryTestSuite) TearDownSuite() {
+	s.db.Close()
+}
+
+func (s *OrderRepositoryTestSuite) recreateTables() {
+	_, err := s.db.Exec(`
+  DROP TABLE IF EXISTS order_coin_lots;
+  DROP TABLE IF EXISTS orders;
+  DROP TABLE IF EXISTS pickup_locations;
+  DROP TABLE IF EXISTS merch_items CASCADE;
+  DROP TABLE IF EXISTS users CASCADE;
+
+  CREATE TABLE users (
+   id SERIAL PRIMARY KEY,
+   username VARCHAR(255) UNIQUE NOT NULL,
+   password_hash VARCHAR(255) NOT NULL,
+   coins INTEGER NOT NULL DEFAULT 1000,
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+
+  CREATE TABLE merch_items (
+   id SERIAL PRIMARY KEY,
+   name VARCHAR(255) UNIQUE NOT NULL,
+   price INTEGER NOT NULL CHECK (price > 0),
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+
+  CREATE TABLE pickup_locations (
+   id SERIAL PRIMARY KEY,
+   name VARCHAR(100) UNIQUE NOT NULL,
+   address VARCHAR(255) NOT NULL DEFAULT '',
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+
+  CREATE TABLE orders (
+   id SERIAL PRIMARY KEY,
+   user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+   item_id INTEGER NOT NULL REFERENCES merch_items(id),
+   variant_id INTEGER,
+   price INTEGER NOT NULL CHECK (price >= 0),
+   location_id INTEGER REFERENCES pickup_locations(id),
+   status VARCHAR(20) NOT NULL DEFAULT 'placed',
+   updated_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+   updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+
+  CREATE TABLE order_coin_lots (
+   id SERIAL PRIMARY KEY,
+   order_id INTEGER NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+   amount INTEGER NOT NULL CHECK (amount > 0),
+   granted_at TIMESTAMP WITH TIME ZONE NOT NULL,
+   expires_at TIMESTAMP WITH TIME ZONE
+  );
+ `)
+	require.NoError(s.T(), err)
+}
+
+func (s *OrderRepositoryTestSuite) TestLifecycle() {
+	ctx := context.Background()
+
+	first := entity.Order{UserID: 1, ItemID: 1, Price: 20}
+	second := entity.Order{UserID: 1, ItemID: 1, Price: 15}
+	s.NoError(s.repo.Create(ctx, &first))
+	s.NoError(s.repo.Create(ctx, &second))
+	s.Equal(entity.OrderStatusPlaced, first.Status)
+
+	order, err := s.repo.GetByID(ctx, first.ID)
+	s.NoError(err)
+	s.Empty(order.Location)
+
+	office := int64(1)
+	order.LocationID = &office
+	s.NoError(order.Advance(entity.OrderStatusPacked, 2, time.Now()))
+	s.NoError(s.repo.Update(ctx, order))
+
+	order, err = s.repo.GetByID(ctx, first.ID)
+	s.NoError(err)
+	s.Equal(entity.OrderStatusPacked, order.Status)
+	s.Equal("Moscow", order.Location)
+	s.Equal(int64(2), *order.UpdatedBy)
+
+	packed, err := s.repo.List(ctx, entity.OrderStatusPacked)
+	s.NoError(err)
+	s.Len(packed, 1)
+
+	open, err := s.repo.List(ctx, "")
+	s.NoError(err)
+	s.Len(open, 2)
+	s.Equal(first.ID, open[0].ID)
+
+	mine, err := s.repo.ListByUser(ctx, 1)
+	s.NoError(err)
+	s.Len(mine, 2)
+
+	order.Status = entity.OrderStatusCancelled
+	s.NoError(s.repo.Update(ctx, order))
+
+	count, err := s.repo.CountOpen(ctx, 1, 1, nil)
+	s.NoError(err)
+	s.Equal(int64(1), count)
+
+	_, err = s.repo.GetByID(ctx, 100)
+	s.ErrorIs(err, entity.ErrOrderNotFound)
+}
+
+func (s *OrderRepositoryTestSuite) TestPrizeOrder() {
+	ctx := context.Background()
+
+	// a raffle prize is handed out through an order that cost nothing
+	prize := entity.Order{UserID: 1, ItemID: 1, Status: entity.OrderStatusPlaced}
+	s.NoError(s.repo.Create(ctx, &prize))
+
+	order, err := s.repo.GetByID(ctx, prize.ID)
+	s.NoError(err)
+	s.Equal(int64(0), order.Price)
+
+	s.Error(s.repo.Create(ctx, &entity.Order{UserID: 1, ItemID: 1, Price: -1}))
+}
+
+func (s *OrderRepositoryTestSuite) TestLots() {
+	ctx := context.Background()
+
+	granted := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	expires := granted.AddDate(0, 3, 0)
+	order := entity.Order{
+		UserID: 1,
+		ItemID: 1,
+		Price:  20,
+		Lots: []entity.CoinLot{
+			{Amount: 15, Remaining: 15, GrantedAt: granted, ExpiresAt: &expires},
+			{Amount: 5, Remaining: 5, GrantedAt: granted.AddDate(0, 1, 0)},
+		},
+	}
+	s.NoError(s.repo.Create(ctx, &order))
+
+	lots, err := s.repo.ListLots(ctx, order.ID)
+	s.NoError(err)
+	s.Len(lots, 2)
+	s.Equal(int64(15), lots[0].Amount)
+	s.True(expires.Equal(*lots[0].ExpiresAt))
+	s.Nil(lots[1].ExpiresAt)
+
+	lots, err = s.repo.ListLots(ctx, 100)
+	s.NoError(err)
+	s.Empty(lots)
+}
+
+func (s *OrderRepositoryTestSuite) TestLocations() {
+	ctx := context.Background()
+
+	locations, err := s.repo.ListLocations(ctx)
+	s.NoError(err)
+	s.Len(locations, 1)
+
+	location, err := s.repo.GetLocation(ctx, 1)
+	s.NoError(err)
+	s.Equal("Lva Tolstogo 16", location.Address)
+
+	_, err = s.repo.GetLocation(ctx, 2)
+	s.ErrorIs(err, entity.ErrPickupLocationNotFound)
+}
+
+func TestOrderRepository(t *testing.T) {
+	suite.Run(t, new(OrderRepositoryTestSuite))
+}
@@ -0,0 +1,197 @@
+package pending_transfer_repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/repository"
+	"time"
+)
+
+type dbConn interface {
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type PendingTransferRepository struct {
+	db dbConn
+}
+
+func NewPendingTransferRepository(db *sqlx.DB) *PendingTransferRepository {
+	return &PendingTransferRepository{
+		db: db,
+	}
+}
+
+func (r *PendingTransferRepository) WithTx(tx *sqlx.Tx) *PendingTransferRepository {
+	return &PendingTransferRepository{
+		db: tx,
+	}
+}
+
+// conn returns the transaction ctx carries, or the repository's own
+// connection outside of one.
+func (r *PendingTransferRepository) conn(ctx context.Context) dbConn {
+	if tx, ok := repository.TxFromContext(ctx); ok {
+		return tx
+	}
+	return r.db
+}
+
+func (r *PendingTransferRepository) Create(ctx context.Context, t *entity.PendingTransfer) error {
+	query := `
+  INSERT INTO pending_transfers (from_user_id, to_user_id, amount, message, status, expires_at)
+  VALUES ($1, $2, $3, $4, $5, $6)
+  RETURNING id, created_at`
+
+	err := r.conn(ctx).QueryRowContext(
+		ctx,
+		query,
+		t.FromUserID,
+		t.ToUserID,
+		t.Amount,
+		t.Message,
+		t.Status,
+		t.ExpiresAt,
+	).Scan(&t.ID, &t.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create pending transfer: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID locks the transfer row until the surrounding transaction ends.
+func (r *PendingTransferRepository) GetByID(ctx context.Context, id int64) (entity.PendingTransfer, error) {
+	var t entity.PendingTransfer
+	query := `
+  SELECT id, from_user_id, to_user_id, amount, message, status, expires_at, decided_by, created_at, decided_at
+  FROM pending_transfers
+  WHERE id = $1
+  FOR UPDATE`
+
+	err := r.conn(ctx).GetContext(ctx, &t, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.PendingTransfer{}, entity.ErrPendingTransferNotFound
+		}
+		return entity.PendingTransfer{}, fmt.Errorf("failed to get pending transfer by id: %w", err)
+	}
+
+	return t, nil
+}
+
+// Resolve moves a pending transfer to status. It reports false when the
+// transfer was no longer pending, so a transfer can be decided only once.
+// decidedBy is nil when the transfer expires on its own.
+func (r *PendingTransferRepository) Resolve(ctx context.Context, id int64, status entity.PendingTransferStatus, decidedBy *int64) (bool, error) {
+	query := `
+  UPDATE pending_transfers
+  SET status = $1,
+   decided_by = $2,
+   decided_at = CURRENT_TIMESTAMP
+  WHERE id = $3 AND status = $4`
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, status, decidedBy, id, entity.PendingTransferStatusPending)
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve pending transfer: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// SumPending returns the total of the user's transfers still waiting for a
+// decision. They turn into transfers once approved, so the sender's limits
+// count them for as long as they wait, however long ago they were parked.
+func (r *PendingTransferRepository) SumPending(ctx context.Context, fromUserID int64) (int64, error) {
+	query := `
+  SELECT COALESCE(SUM(amount), 0)
+  FROM pending_transfers
+  WHERE from_user_id = $1 AND status = $2`
+
+	var total int64
+	err := r.conn(ctx).GetContext(ctx, &total, query, fromUserID, entity.PendingTransferStatusPending)
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum pending transfers: %w", err)
+	}
+
+	return total, nil
+}
+
+// SumPendingTo is SumPending for the transfers to one recipient.
+func (r *PendingTransferRepository) SumPendingTo(ctx context.Context, fromUserID, toUserID int64) (int64, error) {
+	query := `
+  SELECT COALESCE(SUM(amount), 0)
+  FROM pending_transfers
+  WHERE from_user_id = $1 AND to_user_id = $2 AND status = $3`
+
+	var total int64
+	err := r.conn(ctx).GetContext(ctx, &total, query, fromUserID, toUserID, entity.PendingTransferStatusPending)
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum pending transfers to user: %w", err)
+	}
+
+	return total, nil
+}
+
+// ListPending returns every transfer still waiting for a decision, oldest
+// first.
+func (r *PendingTransferRepository) ListPending(ctx context.Context) ([]entity.PendingTransfer, error) {
+	query := `
+  SELECT id, from_user_id, to_user_id, amount, message, status, expires_at, decided_by, created_at, decided_at
+  FROM pending_transfers
+  WHERE status = $1
+  ORDER BY created_at`
+
+	var transfers []entity.PendingTransfer
+	err := r.conn(ctx).SelectContext(ctx, &transfers, query, entity.PendingTransferStatusPending)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending transfers: %w", err)
+	}
+
+	return transfers, nil
+}
+
+func (r *PendingTransferRepository) ListByUser(ctx context.Context, fromUserID int64) ([]entity.PendingTransfer, error) {
+	query := `
+  SELECT id, from_user_id, to_user_id, amount, message, status, expires_at, decided_by, created_at, decided_at
+  FROM pending_transfers
+  WHERE from_user_id = $1
+  ORDER BY created_at DESC`
+
+	var transfers []entity.PendingTransfer
+	err := r.conn(ctx).SelectContext(ctx, &transfers, query, fromUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending transfers by user: %w", err)
+	}
+
+	return transfers, nil
+}
+
+// ListExpired returns the IDs of pending transfers that expired before now.
+func (r *PendingTransferRepository) ListExpired(ctx context.Context, now time.Time, limit int) ([]int64, error) {
+	query := `
+  SELECT id
+  FROM pending_transfers
+  WHERE status = $1 AND expires_at <= $2
+  ORDER BY expires_at
+  LIMIT $3`
+
+	var ids []int64
+	err := r.conn(ctx).SelectContext(ctx, &ids, query, entity.PendingTransferStatusPending, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired pending transfers: %w", err)
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,170 @@
+package pending_transfer_repository
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/testutils"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type PendingTransferRepositoryTestSuite struct {
+	suite.Suite
+	db   *sqlx.DB
+	repo *PendingTransferRepository
+}
+
+func (s *PendingTransferRepositoryTestSuite) SetupSuite() {
+	db, err := testutils.GetTestDB()
+	require.NoError(s.T(), err)
+
+	s.db = db
+	s.repo = NewPendingTransferRepository(db)
+
+	s.recreateTables()
+}
+
+func (s *PendingTransferRepositoryTestSuite) SetupTest() {
+	_, err := s.db.Exec("TRUNCATE TABLE pending_transfers, users RESTART IDENTITY CASCADE")
+	require.NoError(s.T(), err)
+
+	_, err = s.db.Exec(`
+  INSERT INTO users (username, password_hash, coins)
+  VALUES
+  ('user1', 'hash1', 1000),
+  ('user2', 'hash2', 1000),
+  ('admin', 'hash3', 1000)`)
+	require.NoError(s.T(), err)
+}
+
+func (s *PendingTransferRepositoryTestSuite) TearDownSuite() {
+	s.db.Close()
+}
+
+func (s *PendingTransferRepositoryTestSuite) recreateTables() {
+	_, err := s.db.Exec(`
+  DROP TABLE IF EXISTS pending_transfers;
+  DROP TABLE IF EXISTS users CASCADE;
+
+  CREATE TABLE users (
+   id SERIAL PRIMARY KEY,
+   username VARCHAR(255) UNIQUE NOT NULL,
+   password_hash VARCHAR(255) NOT NULL,
+   coins INTEGER NOT NULL DEFAULT 1000,
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+
+  CREATE TABLE pending_transfers (
+   id SERIAL PRIMARY KEY,
+   from_user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+   to_user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+   amount INTEGER NOT NULL CHECK (amount > 0),
+   message VARCHAR(280),
+   status VARCHAR(50) NOT NULL DEFAULT 'pending',
+   expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+   decided_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+   decided_at TIMESTAMP WITH TIME ZONE
+  );
+ `)
+	require.NoError(s.T(), err)
+}
+
+func (s *PendingTransferRepositoryTestSuite) TestLifecycle() {
+	ctx := context.Background()
+
+	message := "conference tickets"
+	t := entity.PendingTransfer{
+		FromUserID: 1,
+		ToUserID:   2,
+		Amount:     800,
+		Message:    &message,
+		Status:     entity.PendingTransferStatusPending,
+		ExpiresAt:  time.Now().Add(time.Hour),
+	}
+	s.NoError(s.repo.Create(ctx, &t))
+	s.NotZero(t.ID)
+
+	pending, err := s.repo.ListPending(ctx)
+	s.NoError(err)
+	s.Len(pending, 1)
+	s.Equal(message, *pending[0].Message)
+
+	adminID := int64(3)
+	resolved, err := s.repo.Resolve(ctx, t.ID, entity.PendingTransferStatusApproved, &adminID)
+	s.NoError(err)
+	s.True(resolved)
+
+	resolved, err = s.repo.Resolve(ctx, t.ID, entity.PendingTransferStatusRejected, &adminID)
+	s.NoError(err)
+	s.False(resolved)
+
+	found, err := s.repo.GetByID(ctx, t.ID)
+	s.NoError(err)
+	s.Equal(entity.PendingTransferStatusApproved, found.Status)
+	s.Equal(adminID, *found.DecidedBy)
+	s.NotNil(found.DecidedAt)
+
+	_, err = s.repo.GetByID(ctx, 100)
+	s.ErrorIs(err, entity.ErrPendingTransferNotFound)
+}
+
+func (s *PendingTransferRepositoryTestSuite) TestListExpired() {
+	ctx := context.Background()
+	now := time.Now()
+
+	for _, expiresAt := range []time.Time{now.Add(-time.Hour), now.Add(time.Hour)} {
+		t := entity.PendingTransfer{
+			FromUserID: 1,
+			ToUserID:   2,
+			Amount:     800,
+			Status:     entity.PendingTransferStatusPending,
+			ExpiresAt:  expiresAt,
+		}
+		s.NoError(s.repo.Create(ctx, &t))
+	}
+
+	ids, err := s.repo.ListExpired(ctx, now, 10)
+	s.NoError(err)
+	s.Equal([]int64{1}, ids)
+
+	transfers, err := s.repo.ListByUser(ctx, 1)
+	s.NoError(err)
+	s.Len(transfers, 2)
+}
+
+func (s *PendingTransferRepositoryTestSuite) TestSumPending() {
+	ctx := context.Background()
+
+	for _, toUserID := range []int64{2, 2, 3} {
+		t := entity.PendingTransfer{
+			FromUserID: 1,
+			ToUserID:   toUserID,
+			Amount:     100,
+			Status:     entity.PendingTransferStatusPending,
+			ExpiresAt:  time.Now().Add(time.Hour),
+		}
+		s.NoError(s.repo.Create(ctx, &t))
+	}
+
+	// decided transfers no longer hold anything
+	adminID := int64(3)
+	_, err := s.repo.Resolve(ctx, 1, entity.PendingTransferStatusRejected, &adminID)
+	s.NoError(err)
+
+	total, err := s.repo.SumPending(ctx, 1)
+	s.NoError(err)
+	s.Equal(int64(200), total)
+
+	total, err = s.repo.SumPendingTo(ctx, 1, 2)
+	s.NoError(err)
+	s.Equal(int64(100), total)
+}
+
+func TestPendingTransferRepository(t *testing.T) {
+	suite.Run(t, new(PendingTransferRepositoryTestSuite))
+}
@@ -0,0 +1,189 @@
+package preorder_repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/repository"
+	"time"
+)
+
+const selectPreorders = `
+  SELECT p.id, p.user_id, p.item_id, p.variant_id, p.price, p.status, p.resolved_at, p.created_at,
+   m.name AS item_name
+  FROM preorders p
+  JOIN merch_items m ON m.id = p.item_id`
+
+type dbConn interface {
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type PreorderRepository struct {
+	db dbConn
+}
+
+func NewPreorderRepository(db *sqlx.DB) *PreorderRepository {
+	return &PreorderRepository{
+		db: db,
+	}
+}
+
+func (r *PreorderRepository) WithTx(tx *sqlx.Tx) *PreorderRepository {
+	return &PreorderRepository{
+		db: tx,
+	}
+}
+
+// conn returns the transaction ctx carries, or the repository's own
+// connection outside of one.
+func (r *PreorderRepository) conn(ctx context.Context) dbConn {
+	if tx, ok := repository.TxFromContext(ctx); ok {
+		return tx
+	}
+	return r.db
+}
+
+func (r *PreorderRepository) Create(ctx context.Context, preorder *entity.Preorder) error {
+	if preorder.Status == "" {
+		preorder.Status = entity.PreorderStatusPending
+	}
+
+	query := `
+  INSERT INTO preorders (user_id, item_id, variant_id, price, status)
+  VALUES ($1, $2, $3, $4, $5)
+  RETURNING id, created_at`
+
+	err := r.conn(ctx).QueryRowContext(
+		ctx,
+		query,
+		preorder.UserID,
+		preorder.ItemID,
+		preorder.VariantID,
+		preorder.Price,
+		preorder.Status,
+	).Scan(&preorder.ID, &preorder.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create preorder: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID locks the pre-order for the rest of the transaction.
+func (r *PreorderRepository) GetByID(ctx context.Context, id int64) (entity.Preorder, error) {
+	var preorder entity.Preorder
+	query := selectPreorders + `
+  WHERE p.id = $1
+  FOR UPDATE OF p`
+
+	err := r.conn(ctx).GetContext(ctx, &preorder, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Preorder{}, entity.ErrPreorderNotFound
+		}
+		return entity.Preorder{}, fmt.Errorf("failed to get preorder by id: %w", err)
+	}
+
+	return preorder, nil
+}
+
+// CountActive returns how many pre-orders of the item have not been
+// cancelled. It locks the item for the rest of the transaction, so
+// concurrent pre-orders are counted one at a time and cannot overrun the
+// limit together.
+func (r *PreorderRepository) CountActive(ctx context.Context, itemID int64) (int64, error) {
+	var count int64
+	query := `
+  WITH item AS (
+   SELECT id
+   FROM merch_items
+   WHERE id = $1
+   FOR UPDATE
+  )
+  SELECT COUNT(p.id)
+  FROM item
+  LEFT JOIN preorders p ON p.item_id = item.id AND p.status <> 'cancelled'`
+
+	err := r.conn(ctx).GetContext(ctx, &count, query, itemID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count preorders: %w", err)
+	}
+
+	return count, nil
+}
+
+// ListPending locks and returns the item's pending pre-orders in the order
+// they were placed.
+func (r *PreorderRepository) ListPending(ctx context.Context, itemID int64) ([]entity.Preorder, error) {
+	var preorders []entity.Preorder
+	query := selectPreorders + `
+  WHERE p.item_id = $1
+   AND p.status = 'pending'
+  ORDER BY p.id
+  FOR UPDATE OF p`
+
+	err := r.conn(ctx).SelectContext(ctx, &preorders, query, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending preorders: %w", err)
+	}
+
+	return preorders, nil
+}
+
+func (r *PreorderRepository) ListByUser(ctx context.Context, userID int64) ([]entity.Preorder, error) {
+	var preorders []entity.Preorder
+	query := selectPreorders + `
+  WHERE p.user_id = $1
+  ORDER BY p.id DESC`
+
+	err := r.conn(ctx).SelectContext(ctx, &preorders, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list preorders by user: %w", err)
+	}
+
+	return preorders, nil
+}
+
+func (r *PreorderRepository) Update(ctx context.Context, preorder entity.Preorder) error {
+	query := `
+  UPDATE preorders
+  SET status = $1, resolved_at = $2
+  WHERE id = $3`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, preorder.Status, preorder.ResolvedAt, preorder.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update preorder: %w", err)
+	}
+
+	return nil
+}
+
+// ListDue returns the IDs of items released by now that still have pending
+// pre-orders, earliest release first.
+func (r *PreorderRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]int64, error) {
+	var ids []int64
+	query := `
+  SELECT m.id
+  FROM merch_items m
+  WHERE m.release_at <= $1
+   AND EXISTS (
+    SELECT 1 FROM preorders p
+    WHERE p.item_id = m.id AND p.status = 'pending'
+   )
+  ORDER BY m.release_at, m.id
+  LIMIT $2`
+
+	err := r.conn(ctx).SelectContext(ctx, &ids, query, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list released items with preorders: %w", err)
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,130 @@
+package preorder_repository
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/testutils"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type PreorderRepositoryTestSuite struct {
+	suite.Suite
+	db   *sqlx.DB
+	repo *PreorderRepository
+}
+
+func (s *PreorderRepositoryTestSuite) SetupSuite() {
+	db, err := testutils.GetTestDB()
+	require.NoError(s.T(), err)
+
+	s.db = db
+	s.repo = NewPreorderRepository(db)
+
+	s.recreateTables()
+}
+
+func (s *PreorderRepositoryTestSuite) SetupTest() {
+	_, err := s.db.Exec("TRUNCATE TABLE preorders, merch_items, users RESTART IDENTITY CASCADE")
+	require.NoError(s.T(), err)
+
+	_, err = s.db.Exec(`
+  INSERT INTO users (username, password_hash, coins) VALUES ('user1', 'hash1', 1000), ('user2', 'hash2', 1000);
+  INSERT INTO merch_items (name, price, release_at, preorder_limit) VALUES
+   ('console', 500, now() - interval '1 hour', 10),
+   ('drone', 800, now() + interval '1 day', 5)`)
+	require.NoError(s.T(), err)
+}
+
+func (s *PreorderRepositoryTestSuite) TearDownSuite() {
+	s.db.Close()
+}
+
+func (s *PreorderRepositoryTestSuite) recreateTables() {
+	_, err := s.db.Exec(`
+  DROP TABLE IF EXISTS preorders;
+  DROP TABLE IF EXISTS merch_items CASCADE;
+  DROP TABLE IF EXISTS users CASCADE;
+
+  CREATE TABLE users (
+   id SERIAL PRIMARY KEY,
+   username VARCHAR(255) UNIQUE NOT NULL,
+   password_hash VARCHAR(255) NOT NULL,
+   coins INTEGER NOT NULL DEFAULT 1000,
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+
+  CREATE TABLE merch_items (
+   id SERIAL PRIMARY KEY,
+   name VARCHAR(255) UNIQUE NOT NULL,
+   price INTEGER NOT NULL CHECK (price > 0),
+   release_at TIMESTAMP WITH TIME ZONE,
+   preorder_limit INTEGER,
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+
+  CREATE TABLE preorders (
+   id SERIAL PRIMARY KEY,
+   user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+   item_id INTEGER NOT NULL REFERENCES merch_items(id),
+   variant_id INTEGER,
+   price INTEGER NOT NULL,
+   status VARCHAR(20) NOT NULL DEFAULT 'pending',
+   resolved_at TIMESTAMP WITH TIME ZONE,
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+ `)
+	require.NoError(s.T(), err)
+}
+
+func (s *PreorderRepositoryTestSuite) TestLifecycle() {
+	ctx := context.Background()
+	now := time.Now()
+
+	first := entity.Preorder{UserID: 2, ItemID: 1, Price: 500}
+	second := entity.Preorder{UserID: 1, ItemID: 1, Price: 500}
+	upcoming := entity.Preorder{UserID: 1, ItemID: 2, Price: 800}
+	s.NoError(s.repo.Create(ctx, &first))
+	s.NoError(s.repo.Create(ctx, &second))
+	s.NoError(s.repo.Create(ctx, &upcoming))
+	s.Equal(entity.PreorderStatusPending, first.Status)
+
+	count, err := s.repo.CountActive(ctx, 1)
+	s.NoError(err)
+	s.Equal(int64(2), count)
+
+	preorder, err := s.repo.GetByID(ctx, second.ID)
+	s.NoError(err)
+	s.Equal("console", preorder.ItemName)
+	s.NoError(preorder.Resolve(entity.PreorderStatusCancelled, now))
+	s.NoError(s.repo.Update(ctx, preorder))
+
+	count, err = s.repo.CountActive(ctx, 1)
+	s.NoError(err)
+	s.Equal(int64(1), count)
+
+	due, err := s.repo.ListDue(ctx, now, 10)
+	s.NoError(err)
+	s.Equal([]int64{1}, due)
+
+	pending, err := s.repo.ListPending(ctx, 1)
+	s.NoError(err)
+	s.Require().Len(pending, 1)
+	s.Equal(first.ID, pending[0].ID)
+
+	mine, err := s.repo.ListByUser(ctx, 1)
+	s.NoError(err)
+	s.Require().Len(mine, 2)
+	s.Equal(upcoming.ID, mine[0].ID)
+
+	_, err = s.repo.GetByID(ctx, 100)
+	s.ErrorIs(err, entity.ErrPreorderNotFound)
+}
+
+func TestPreorderRepository(t *testing.T) {
+	suite.Run(t, new(PreorderRepositoryTestSuite))
+}
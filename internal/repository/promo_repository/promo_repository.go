@@ -0,0 +1,213 @@
+package promo_repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/repository"
+	"time"
+)
+
+type dbConn interface {
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// PromoRepository stores promo codes, their redemptions and scheduled
+// sales.
+type PromoRepository struct {
+	db dbConn
+}
+
+func NewPromoRepository(db *sqlx.DB) *PromoRepository {
+	return &PromoRepository{
+		db: db,
+	}
+}
+
+func (r *PromoRepository) WithTx(tx *sqlx.Tx) *PromoRepository {
+	return &PromoRepository{
+		db: tx,
+	}
+}
+
+// conn returns the transaction ctx carries, or the repository's own
+// connection outside of one.
+func (r *PromoRepository) conn(ctx context.Context) dbConn {
+	if tx, ok := repository.TxFromContext(ctx); ok {
+		return tx
+	}
+	return r.db
+}
+
+func (r *PromoRepository) CreateCode(ctx context.Context, code *entity.PromoCode) error {
+	query := `
+  INSERT INTO promo_codes (code, kind, value, item_id, max_uses, once_per_user, starts_at, ends_at, created_by)
+  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+  RETURNING id, created_at`
+
+	err := r.conn(ctx).QueryRowContext(
+		ctx,
+		query,
+		code.Code,
+		code.Kind,
+		code.Value,
+		code.ItemID,
+		code.MaxUses,
+		code.OncePerUser,
+		code.StartsAt,
+		code.EndsAt,
+		code.CreatedBy,
+	).Scan(&code.ID, &code.CreatedAt)
+
+	if err != nil {
+		if isUniqueViolation(err) {
+			return entity.ErrPromoCodeExists
+		}
+		return fmt.Errorf("failed to create promo code: %w", err)
+	}
+
+	return nil
+}
+
+// GetCode locks the code so concurrent purchases cannot both take its last
+// use.
+func (r *PromoRepository) GetCode(ctx context.Context, code string) (entity.PromoCode, error) {
+	var promo entity.PromoCode
+	query := `
+  SELECT id, code, kind, value, item_id, max_uses, uses, once_per_user, starts_at, ends_at, created_by, created_at
+  FROM promo_codes
+  WHERE code = $1
+  FOR UPDATE`
+
+	err := r.conn(ctx).GetContext(ctx, &promo, query, code)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.PromoCode{}, entity.ErrPromoCodeNotFound
+		}
+		return entity.PromoCode{}, fmt.Errorf("failed to get promo code: %w", err)
+	}
+
+	return promo, nil
+}
+
+func (r *PromoRepository) ListCodes(ctx context.Context) ([]entity.PromoCode, error) {
+	var codes []entity.PromoCode
+	query := `
+  SELECT id, code, kind, value, item_id, max_uses, uses, once_per_user, starts_at, ends_at, created_by, created_at
+  FROM promo_codes
+  ORDER BY created_at DESC, id DESC`
+
+	err := r.conn(ctx).SelectContext(ctx, &codes, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list promo codes: %w", err)
+	}
+
+	return codes, nil
+}
+
+func (r *PromoRepository) HasRedeemed(ctx context.Context, codeID, userID int64) (bool, error) {
+	var exists bool
+	query := `
+  SELECT EXISTS (
+   SELECT 1 FROM promo_redemptions WHERE code_id = $1 AND user_id = $2
+  )`
+
+	err := r.conn(ctx).GetContext(ctx, &exists, query, codeID, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check promo redemption: %w", err)
+	}
+
+	return exists, nil
+}
+
+// Redeem records a use of the code by the user.
+func (r *PromoRepository) Redeem(ctx context.Context, codeID, userID int64) error {
+	query := `
+  WITH used AS (
+   UPDATE promo_codes SET uses = uses + 1 WHERE id = $1
+  )
+  INSERT INTO promo_redemptions (code_id, user_id)
+  VALUES ($1, $2)`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, codeID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to redeem promo code: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PromoRepository) CreateSale(ctx context.Context, sale *entity.MerchSale) error {
+	query := `
+  INSERT INTO merch_sales (item_id, kind, value, starts_at, ends_at, created_by)
+  VALUES ($1, $2, $3, $4, $5, $6)
+  RETURNING id, created_at`
+
+	err := r.conn(ctx).QueryRowContext(
+		ctx,
+		query,
+		sale.ItemID,
+		sale.Kind,
+		sale.Value,
+		sale.StartsAt,
+		sale.EndsAt,
+		sale.CreatedBy,
+	).Scan(&sale.ID, &sale.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create merch sale: %w", err)
+	}
+
+	return nil
+}
+
+// ListSales returns the sales that have not ended by now, soonest first.
+func (r *PromoRepository) ListSales(ctx context.Context, now time.Time) ([]entity.MerchSale, error) {
+	var sales []entity.MerchSale
+	query := `
+  SELECT id, item_id, kind, value, starts_at, ends_at, created_by, created_at
+  FROM merch_sales
+  WHERE ends_at > $1
+  ORDER BY starts_at, id`
+
+	err := r.conn(ctx).SelectContext(ctx, &sales, query, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list merch sales: %w", err)
+	}
+
+	return sales, nil
+}
+
+// ActiveSales returns the sales running at now that cover the item, either
+// directly or catalog-wide.
+func (r *PromoRepository) ActiveSales(ctx context.Context, itemID int64, now time.Time) ([]entity.MerchSale, error) {
+	var sales []entity.MerchSale
+	query := `
+  SELECT id, item_id, kind, value, starts_at, ends_at, created_by, created_at
+  FROM merch_sales
+  WHERE starts_at <= $2 AND ends_at > $2
+   AND (item_id IS NULL OR item_id = $1)
+  ORDER BY id`
+
+	err := r.conn(ctx).SelectContext(ctx, &sales, query, itemID, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list active merch sales: %w", err)
+	}
+
+	return sales, nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == "23505"
+	}
+	return false
+}
@@ -0,0 +1,178 @@
+package promo_repository
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/testutils"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type PromoRepositoryTestSuite struct {
+	suite.Suite
+	db   *sqlx.DB
+	repo *PromoRepository
+}
+
+func (s *PromoRepositoryTestSuite) SetupSuite() {
+	db, err := testutils.GetTestDB()
+	require.NoError(s.T(), err)
+
+	s.db = db
+	s.repo = NewPromoRepository(db)
+
+	s.recreateTables()
+}
+
+func (s *PromoRepositoryTestSuite) SetupTest() {
+	_, err := s.db.Exec("TRUNCATE TABLE merch_sales, promo_redemptions, promo_codes, merch_items, users RESTART IDENTITY CASCADE")
+	require.NoError(s.T(), err)
+
+	_, err = s.db.Exec(`
+  INSERT INTO users (username, password_hash, coins)
+  VALUES
+  ('admin', 'hash1', 1000),
+  ('user2', 'hash2', 1000);
+
+  INSERT INTO merch_items (name, price)
+  VALUES
+  ('hoody', 300),
+  ('pen', 10)`)
+	require.NoError(s.T(), err)
+}
+
+func (s *PromoRepositoryTestSuite) TearDownSuite() {
+	s.db.Close()
+}
+
+func (s *PromoRepositoryTestSuite) recreateTables() {
+	_, err := s.db.Exec(`
+  DROP TABLE IF EXISTS merch_sales;
+  DROP TABLE IF EXISTS promo_redemptions;
+  DROP TABLE IF EXISTS promo_codes;
+  DROP TABLE IF EXISTS merch_items CASCADE;
+  DROP TABLE IF EXISTS users CASCADE;
+
+  CREATE TABLE users (
+   id SERIAL PRIMARY KEY,
+   username VARCHAR(255) UNIQUE NOT NULL,
+   password_hash VARCHAR(255) NOT NULL,
+   coins INTEGER NOT NULL DEFAULT 1000,
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+
+  CREATE TABLE merch_items (
+   id SERIAL PRIMARY KEY,
+   name VARCHAR(255) UNIQUE NOT NULL,
+   price INTEGER NOT NULL CHECK (price > 0),
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+
+  CREATE TABLE promo_codes (
+   id SERIAL PRIMARY KEY,
+   code VARCHAR(50) UNIQUE NOT NULL,
+   kind VARCHAR(20) NOT NULL CHECK (kind IN ('percent', 'fixed')),
+   value INTEGER NOT NULL CHECK (value > 0),
+   item_id INTEGER REFERENCES merch_items(id) ON DELETE CASCADE,
+   max_uses INTEGER CHECK (max_uses > 0),
+   uses INTEGER NOT NULL DEFAULT 0 CHECK (uses >= 0),
+   once_per_user BOOLEAN NOT NULL DEFAULT FALSE,
+   starts_at TIMESTAMP WITH TIME ZONE,
+   ends_at TIMESTAMP WITH TIME ZONE,
+   created_by INTEGER NOT NULL REFERENCES users(id),
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+
+  CREATE TABLE promo_redemptions (
+   id SERIAL PRIMARY KEY,
+   code_id INTEGER NOT NULL REFERENCES promo_codes(id) ON DELETE CASCADE,
+   user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+
+  CREATE TABLE merch_sales (
+   id SERIAL PRIMARY KEY,
+   item_id INTEGER REFERENCES merch_items(id) ON DELETE CASCADE,
+   kind VARCHAR(20) NOT NULL CHECK (kind IN ('percent', 'fixed')),
+   value INTEGER NOT NULL CHECK (value > 0),
+   starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+   ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+   created_by INTEGER NOT NULL REFERENCES users(id),
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+ `)
+	require.NoError(s.T(), err)
+}
+
+func (s *PromoRepositoryTestSuite) TestCodes() {
+	ctx := context.Background()
+	maxUses := int64(5)
+
+	code := entity.PromoCode{
+		Code:        "WELCOME",
+		Discount:    entity.Discount{Kind: entity.DiscountKindPercent, Value: 10},
+		MaxUses:     &maxUses,
+		OncePerUser: true,
+		CreatedBy:   1,
+	}
+	s.NoError(s.repo.CreateCode(ctx, &code))
+	s.NotZero(code.ID)
+
+	dup := code
+	s.ErrorIs(s.repo.CreateCode(ctx, &dup), entity.ErrPromoCodeExists)
+
+	redeemed, err := s.repo.HasRedeemed(ctx, code.ID, 2)
+	s.NoError(err)
+	s.False(redeemed)
+
+	s.NoError(s.repo.Redeem(ctx, code.ID, 2))
+
+	redeemed, err = s.repo.HasRedeemed(ctx, code.ID, 2)
+	s.NoError(err)
+	s.True(redeemed)
+
+	got, err := s.repo.GetCode(ctx, "WELCOME")
+	s.NoError(err)
+	s.Equal(int64(1), got.Uses)
+	s.Equal(entity.DiscountKindPercent, got.Kind)
+	s.True(got.OncePerUser)
+
+	_, err = s.repo.GetCode(ctx, "MISSING")
+	s.ErrorIs(err, entity.ErrPromoCodeNotFound)
+
+	codes, err := s.repo.ListCodes(ctx)
+	s.NoError(err)
+	s.Len(codes, 1)
+}
+
+func (s *PromoRepositoryTestSuite) TestSales() {
+	ctx := context.Background()
+	now := time.Now()
+	hoody := int64(1)
+
+	sales := []entity.MerchSale{
+		{Discount: entity.Discount{Kind: entity.DiscountKindPercent, Value: 10}, StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour), CreatedBy: 1},
+		{ItemID: &hoody, Discount: entity.Discount{Kind: entity.DiscountKindFixed, Value: 50}, StartsAt: now.Add(time.Hour), EndsAt: now.Add(2 * time.Hour), CreatedBy: 1},
+		{ItemID: &hoody, Discount: entity.Discount{Kind: entity.DiscountKindFixed, Value: 50}, StartsAt: now.Add(-2 * time.Hour), EndsAt: now.Add(-time.Hour), CreatedBy: 1},
+	}
+	for i := range sales {
+		s.NoError(s.repo.CreateSale(ctx, &sales[i]))
+	}
+
+	active, err := s.repo.ActiveSales(ctx, hoody, now)
+	s.NoError(err)
+	s.Len(active, 1)
+	s.Equal(sales[0].ID, active[0].ID)
+
+	upcoming, err := s.repo.ListSales(ctx, now)
+	s.NoError(err)
+	s.Len(upcoming, 2)
+}
+
+func TestPromoRepository(t *testing.T) {
+	suite.Run(t, new(PromoRepositoryTestSuite))
+}
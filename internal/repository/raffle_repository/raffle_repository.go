@@ -0,0 +1,228 @@
+package raffle_repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/repository"
+	"time"
+)
+
+const selectRaffles = `
+  SELECT r.id, r.item_id, r.ticket_price, r.max_tickets_per_user, r.prizes, r.draw_at, r.status,
+   r.seed, r.seed_hash, r.drawn_at, r.created_by, r.created_at,
+   m.name AS item_name,
+   (SELECT COUNT(*) FROM raffle_tickets t WHERE t.raffle_id = r.id) AS tickets_sold
+  FROM raffles r
+  JOIN merch_items m ON m.id = r.item_id`
+
+type dbConn interface {
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type RaffleRepository struct {
+	db dbConn
+}
+
+func NewRaffleRepository(db *sqlx.DB) *RaffleRepository {
+	return &RaffleRepository{
+		db: db,
+	}
+}
+
+func (r *RaffleRepository) WithTx(tx *sqlx.Tx) *RaffleRepository {
+	return &RaffleRepository{
+		db: tx,
+	}
+}
+
+// conn returns the transaction ctx carries, or the repository's own
+// connection outside of one.
+func (r *RaffleRepository) conn(ctx context.Context) dbConn {
+	if tx, ok := repository.TxFromContext(ctx); ok {
+		return tx
+	}
+	return r.db
+}
+
+func (r *RaffleRepository) Create(ctx context.Context, raffle *entity.Raffle) error {
+	if raffle.Status == "" {
+		raffle.Status = entity.RaffleStatusOpen
+	}
+
+	query := `
+  INSERT INTO raffles (item_id, ticket_price, max_tickets_per_user, prizes, draw_at, status, seed, seed_hash, created_by)
+  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+  RETURNING id, created_at`
+
+	err := r.conn(ctx).QueryRowContext(
+		ctx,
+		query,
+		raffle.ItemID,
+		raffle.TicketPrice,
+		raffle.MaxTicketsPerUser,
+		raffle.Prizes,
+		raffle.DrawAt,
+		raffle.Status,
+		raffle.Seed,
+		raffle.SeedHash,
+		raffle.CreatedBy,
+	).Scan(&raffle.ID, &raffle.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create raffle: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID locks the raffle for the rest of the transaction, so that tickets
+// cannot be sold while it is being drawn.
+func (r *RaffleRepository) GetByID(ctx context.Context, id int64) (entity.Raffle, error) {
+	var raffle entity.Raffle
+	query := selectRaffles + `
+  WHERE r.id = $1
+  FOR UPDATE OF r`
+
+	err := r.conn(ctx).GetContext(ctx, &raffle, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Raffle{}, entity.ErrRaffleNotFound
+		}
+		return entity.Raffle{}, fmt.Errorf("failed to get raffle: %w", err)
+	}
+
+	return raffle, nil
+}
+
+// List returns every raffle, the latest draws first.
+func (r *RaffleRepository) List(ctx context.Context) ([]entity.Raffle, error) {
+	var raffles []entity.Raffle
+	query := selectRaffles + `
+  ORDER BY r.draw_at DESC, r.id DESC`
+
+	err := r.conn(ctx).SelectContext(ctx, &raffles, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list raffles: %w", err)
+	}
+
+	return raffles, nil
+}
+
+func (r *RaffleRepository) Update(ctx context.Context, raffle entity.Raffle) error {
+	query := `
+  UPDATE raffles
+  SET status = $2, drawn_at = $3
+  WHERE id = $1`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, raffle.ID, raffle.Status, raffle.DrawnAt)
+	if err != nil {
+		return fmt.Errorf("failed to update raffle: %w", err)
+	}
+
+	return nil
+}
+
+// AddTickets issues count tickets to the user.
+func (r *RaffleRepository) AddTickets(ctx context.Context, raffleID, userID, count int64) error {
+	query := `
+  INSERT INTO raffle_tickets (raffle_id, user_id)
+  SELECT $1, $2
+  FROM generate_series(1, $3)`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, raffleID, userID, count)
+	if err != nil {
+		return fmt.Errorf("failed to add raffle tickets: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RaffleRepository) CountTickets(ctx context.Context, raffleID, userID int64) (int64, error) {
+	var count int64
+	query := `
+  SELECT COUNT(*)
+  FROM raffle_tickets
+  WHERE raffle_id = $1 AND user_id = $2`
+
+	err := r.conn(ctx).GetContext(ctx, &count, query, raffleID, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count raffle tickets: %w", err)
+	}
+
+	return count, nil
+}
+
+// ListTickets returns the raffle's tickets in ID order, the order the draw
+// works on.
+func (r *RaffleRepository) ListTickets(ctx context.Context, raffleID int64) ([]entity.RaffleTicket, error) {
+	var tickets []entity.RaffleTicket
+	query := `
+  SELECT id, raffle_id, user_id, created_at
+  FROM raffle_tickets
+  WHERE raffle_id = $1
+  ORDER BY id`
+
+	err := r.conn(ctx).SelectContext(ctx, &tickets, query, raffleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list raffle tickets: %w", err)
+	}
+
+	return tickets, nil
+}
+
+func (r *RaffleRepository) AddWinner(ctx context.Context, winner entity.RaffleWinner) error {
+	query := `
+  INSERT INTO raffle_winners (raffle_id, position, ticket_id, user_id)
+  VALUES ($1, $2, $3, $4)`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, winner.RaffleID, winner.Position, winner.TicketID, winner.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to add raffle winner: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RaffleRepository) ListWinners(ctx context.Context, raffleID int64) ([]entity.RaffleWinner, error) {
+	var winners []entity.RaffleWinner
+	query := `
+  SELECT w.raffle_id, w.position, w.ticket_id, w.user_id, u.username
+  FROM raffle_winners w
+  JOIN users u ON u.id = w.user_id
+  WHERE w.raffle_id = $1
+  ORDER BY w.position`
+
+	err := r.conn(ctx).SelectContext(ctx, &winners, query, raffleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list raffle winners: %w", err)
+	}
+
+	return winners, nil
+}
+
+// ListDue returns the IDs of open raffles whose draw time has come, oldest
+// first.
+func (r *RaffleRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]int64, error) {
+	var ids []int64
+	query := `
+  SELECT id
+  FROM raffles
+  WHERE status = 'open'
+   AND draw_at <= $1
+  ORDER BY draw_at, id
+  LIMIT $2`
+
+	err := r.conn(ctx).SelectContext(ctx, &ids, query, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due raffles: %w", err)
+	}
+
+	return ids, nil
+}
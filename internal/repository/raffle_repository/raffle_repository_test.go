@@ -0,0 +1,150 @@
+package raffle_repository
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/testutils"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type RaffleRepositoryTestSuite struct {
+	suite.Suite
+	db   *sqlx.DB
+	repo *RaffleRepository
+}
+
+func (s *RaffleRepositoryTestSuite) SetupSuite() {
+	db, err := testutils.GetTestDB()
+	require.NoError(s.T(), err)
+
+	s.db = db
+	s.repo = NewRaffleRepository(db)
+
+	s.recreateTables()
+}
+
+func (s *RaffleRepositoryTestSuite) SetupTest() {
+	_, err := s.db.Exec("TRUNCATE TABLE raffle_winners, raffle_tickets, raffles, merch_items, users RESTART IDENTITY CASCADE")
+	require.NoError(s.T(), err)
+
+	_, err = s.db.Exec(`
+  INSERT INTO users (username, password_hash, coins) VALUES ('admin', 'hash1', 1000), ('user1', 'hash2', 1000);
+  INSERT INTO merch_items (name, price) VALUES ('console', 5000)`)
+	require.NoError(s.T(), err)
+}
+
+func (s *RaffleRepositoryTestSuite) TearDownSuite() {
+	s.db.Close()
+}
+
+func (s *RaffleRepositoryTestSuite) recreateTables() {
+	_, err := s.db.Exec(`
+  DROP TABLE IF EXISTS raffle_winners;
+  DROP TABLE IF EXISTS raffle_tickets;
+  DROP TABLE IF EXISTS raffles;
+  DROP TABLE IF EXISTS merch_items CASCADE;
+  DROP TABLE IF EXISTS users CASCADE;
+
+  CREATE TABLE users (
+   id SERIAL PRIMARY KEY,
+   username VARCHAR(255) UNIQUE NOT NULL,
+   password_hash VARCHAR(255) NOT NULL,
+   coins INTEGER NOT NULL DEFAULT 1000,
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+
+  CREATE TABLE merch_items (
+   id SERIAL PRIMARY KEY,
+   name VARCHAR(255) UNIQUE NOT NULL,
+   price INTEGER NOT NULL CHECK (price > 0),
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+
+  CREATE TABLE raffles (
+   id SERIAL PRIMARY KEY,
+   item_id INTEGER NOT NULL REFERENCES merch_items(id),
+   ticket_price INTEGER NOT NULL,
+   max_tickets_per_user INTEGER NOT NULL DEFAULT 0,
+   prizes INTEGER NOT NULL DEFAULT 1,
+   draw_at TIMESTAMP WITH TIME ZONE NOT NULL,
+   status VARCHAR(20) NOT NULL DEFAULT 'open',
+   seed VARCHAR(64) NOT NULL,
+   seed_hash VARCHAR(64) NOT NULL,
+   drawn_at TIMESTAMP WITH TIME ZONE,
+   created_by INTEGER NOT NULL REFERENCES users(id),
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+
+  CREATE TABLE raffle_tickets (
+   id SERIAL PRIMARY KEY,
+   raffle_id INTEGER NOT NULL REFERENCES raffles(id) ON DELETE CASCADE,
+   user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+
+  CREATE TABLE raffle_winners (
+   raffle_id INTEGER NOT NULL REFERENCES raffles(id) ON DELETE CASCADE,
+   position INTEGER NOT NULL,
+   ticket_id INTEGER NOT NULL REFERENCES raffle_tickets(id),
+   user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+   PRIMARY KEY (raffle_id, position)
+  );
+ `)
+	require.NoError(s.T(), err)
+}
+
+func (s *RaffleRepositoryTestSuite) TestLifecycle() {
+	ctx := context.Background()
+	now := time.Now()
+
+	raffle := entity.Raffle{ItemID: 1, TicketPrice: 10, Prizes: 1, DrawAt: now.Add(-time.Minute), Seed: "seed", SeedHash: entity.RaffleSeedHash("seed"), CreatedBy: 1}
+	s.NoError(s.repo.Create(ctx, &raffle))
+	s.Equal(entity.RaffleStatusOpen, raffle.Status)
+
+	s.NoError(s.repo.AddTickets(ctx, raffle.ID, 2, 3))
+
+	count, err := s.repo.CountTickets(ctx, raffle.ID, 2)
+	s.NoError(err)
+	s.Equal(int64(3), count)
+
+	got, err := s.repo.GetByID(ctx, raffle.ID)
+	s.NoError(err)
+	s.Equal("console", got.ItemName)
+	s.Equal(int64(3), got.TicketsSold)
+
+	due, err := s.repo.ListDue(ctx, now, 10)
+	s.NoError(err)
+	s.Equal([]int64{raffle.ID}, due)
+
+	tickets, err := s.repo.ListTickets(ctx, raffle.ID)
+	s.NoError(err)
+	s.Require().Len(tickets, 3)
+
+	for _, w := range entity.DrawWinners(got.Seed, tickets, got.Prizes) {
+		s.NoError(s.repo.AddWinner(ctx, w))
+	}
+	got.Status = entity.RaffleStatusDrawn
+	got.DrawnAt = &now
+	s.NoError(s.repo.Update(ctx, got))
+
+	winners, err := s.repo.ListWinners(ctx, raffle.ID)
+	s.NoError(err)
+	s.Require().Len(winners, 1)
+	s.Equal("user1", winners[0].Username)
+
+	due, err = s.repo.ListDue(ctx, now, 10)
+	s.NoError(err)
+	s.Empty(due)
+
+	_, err = s.repo.GetByID(ctx, 100)
+	s.ErrorIs(err, entity.ErrRaffleNotFound)
+}
+
+func TestRaffleRepository(t *testing.T) {
+	suite.Run(t, new(RaffleRepositoryTestSuite))
+}
@@ -0,0 +1,104 @@
+package recommendation_repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/repository"
+)
+
+// purchased lists every item each user has bought at least once. Bundle
+// purchases carry no item and are left out.
+const purchased = `
+  WITH bought AS (
+   SELECT DISTINCT from_user_id AS user_id, item_id
+   FROM transactions
+   WHERE type = 'purchase'
+    AND item_id IS NOT NULL
+  )`
+
+type dbConn interface {
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+}
+
+// RecommendationRepository aggregates purchases for recommendations.
+type RecommendationRepository struct {
+	db dbConn
+}
+
+func NewRecommendationRepository(db *sqlx.DB) *RecommendationRepository {
+	return &RecommendationRepository{
+		db: db,
+	}
+}
+
+func (r *RecommendationRepository) WithTx(tx *sqlx.Tx) *RecommendationRepository {
+	return &RecommendationRepository{
+		db: tx,
+	}
+}
+
+// conn returns the transaction ctx carries, or the repository's own
+// connection outside of one.
+func (r *RecommendationRepository) conn(ctx context.Context) dbConn {
+	if tx, ok := repository.TxFromContext(ctx); ok {
+		return tx
+	}
+	return r.db
+}
+
+// CoPurchases counts, for every pair of different items, the users who
+// bought both. Each pair is returned in both orders.
+func (r *RecommendationRepository) CoPurchases(ctx context.Context) ([]entity.CoPurchase, error) {
+	var pairs []entity.CoPurchase
+	query := purchased + `
+  SELECT a.item_id, b.item_id AS other_item_id, COUNT(*) AS buyers
+  FROM bought a
+  JOIN bought b ON b.user_id = a.user_id AND b.item_id <> a.item_id
+  GROUP BY a.item_id, b.item_id`
+
+	err := r.conn(ctx).SelectContext(ctx, &pairs, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count co-purchases: %w", err)
+	}
+
+	return pairs, nil
+}
+
+// TeamPurchases counts, for every team, the members who bought each item.
+func (r *RecommendationRepository) TeamPurchases(ctx context.Context) ([]entity.TeamPurchase, error) {
+	var purchases []entity.TeamPurchase
+	query := purchased + `
+  SELECT u.team_id, b.item_id, COUNT(*) AS buyers
+  FROM bought b
+  JOIN users u ON u.id = b.user_id
+  WHERE u.team_id IS NOT NULL
+  GROUP BY u.team_id, b.item_id`
+
+	err := r.conn(ctx).SelectContext(ctx, &purchases, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count team purchases: %w", err)
+	}
+
+	return purchases, nil
+}
+
+// PurchasedItems returns the IDs of the items the user has bought.
+func (r *RecommendationRepository) PurchasedItems(ctx context.Context, userID int64) ([]int64, error) {
+	var ids []int64
+	query := `
+  SELECT DISTINCT item_id
+  FROM transactions
+  WHERE type = 'purchase'
+   AND from_user_id = $1
+   AND item_id IS NOT NULL
+  ORDER BY item_id`
+
+	err := r.conn(ctx).SelectContext(ctx, &ids, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list purchased items: %w", err)
+	}
+
+	return ids, nil
+}
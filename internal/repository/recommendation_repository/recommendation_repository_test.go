@@ -0,0 +1,113 @@
+package recommendation_repository
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/testutils"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type RecommendationRepositoryTestSuite struct {
+	suite.Suite
+	db   *sqlx.DB
+	repo *RecommendationRepository
+}
+
+func (s *RecommendationRepositoryTestSuite) SetupSuite() {
+	db, err := testutils.GetTestDB()
+	require.NoError(s.T(), err)
+
+	s.db = db
+	s.repo = NewRecommendationRepository(db)
+
+	s.recreateTables()
+}
+
+func (s *RecommendationRepositoryTestSuite) SetupTest() {
+	_, err := s.db.Exec("TRUNCATE TABLE transactions, users, teams RESTART IDENTITY CASCADE")
+	require.NoError(s.T(), err)
+
+	// user1 and user2 are on the same team; user3 has none
+	_, err = s.db.Exec(`
+  INSERT INTO teams (name) VALUES ('backend');
+  INSERT INTO users (username, password_hash, coins, team_id) VALUES
+   ('user1', 'hash1', 1000, 1), ('user2', 'hash2', 1000, 1), ('user3', 'hash3', 1000, NULL);
+  INSERT INTO transactions (from_user_id, to_user_id, amount, type, item_id) VALUES
+   (1, 1, 10, 'purchase', 1),
+   (1, 1, 10, 'purchase', 1),
+   (1, 1, 20, 'purchase', 2),
+   (2, 2, 10, 'purchase', 1),
+   (3, 3, 10, 'purchase', 1),
+   (3, 3, 20, 'purchase', 2),
+   (3, 3, 90, 'purchase', NULL),
+   (1, 2, 50, 'transfer', NULL)`)
+	require.NoError(s.T(), err)
+}
+
+func (s *RecommendationRepositoryTestSuite) TearDownSuite() {
+	s.db.Close()
+}
+
+func (s *RecommendationRepositoryTestSuite) recreateTables() {
+	_, err := s.db.Exec(`
+  DROP TABLE IF EXISTS transactions;
+  DROP TABLE IF EXISTS users CASCADE;
+  DROP TABLE IF EXISTS teams CASCADE;
+
+  CREATE TABLE teams (
+   id SERIAL PRIMARY KEY,
+   name VARCHAR(100) UNIQUE NOT NULL,
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+
+  CREATE TABLE users (
+   id SERIAL PRIMARY KEY,
+   username VARCHAR(255) UNIQUE NOT NULL,
+   password_hash VARCHAR(255) NOT NULL,
+   coins INTEGER NOT NULL DEFAULT 1000,
+   team_id INTEGER REFERENCES teams(id) ON DELETE SET NULL,
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+
+  CREATE TABLE transactions (
+   id SERIAL PRIMARY KEY,
+   from_user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+   to_user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+   amount INTEGER NOT NULL,
+   type VARCHAR(50) NOT NULL,
+   item_id INTEGER,
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+ `)
+	require.NoError(s.T(), err)
+}
+
+func (s *RecommendationRepositoryTestSuite) TestAggregates() {
+	ctx := context.Background()
+
+	pairs, err := s.repo.CoPurchases(ctx)
+	s.NoError(err)
+	s.ElementsMatch([]entity.CoPurchase{
+		{ItemID: 1, OtherItemID: 2, Buyers: 2},
+		{ItemID: 2, OtherItemID: 1, Buyers: 2},
+	}, pairs)
+
+	team, err := s.repo.TeamPurchases(ctx)
+	s.NoError(err)
+	s.ElementsMatch([]entity.TeamPurchase{
+		{TeamID: 1, ItemID: 1, Buyers: 2},
+		{TeamID: 1, ItemID: 2, Buyers: 1},
+	}, team)
+
+	bought, err := s.repo.PurchasedItems(ctx, 1)
+	s.NoError(err)
+	s.Equal([]int64{1, 2}, bought)
+}
+
+func TestRecommendationRepository(t *testing.T) {
+	suite.Run(t, new(RecommendationRepositoryTestSuite))
+}
@@ -0,0 +1,147 @@
+package redemption_repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/repository"
+)
+
+const selectRedemptions = `
+  SELECT r.id, r.user_id, r.item_id, r.variant_id, r.quantity, r.method, r.address, r.location_id,
+   r.status, r.resolved_by, r.resolved_at, r.created_at,
+   u.username, m.name AS item_name,
+   COALESCE(concat_ws(', ', NULLIF(v.size, ''), NULLIF(v.color, '')), '') AS variant,
+   COALESCE(l.name, '') AS location
+  FROM redemptions r
+  JOIN users u ON u.id = r.user_id
+  JOIN merch_items m ON m.id = r.item_id
+  LEFT JOIN merch_variants v ON v.id = r.variant_id
+  LEFT JOIN pickup_locations l ON l.id = r.location_id`
+
+type dbConn interface {
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type RedemptionRepository struct {
+	db dbConn
+}
+
+func NewRedemptionRepository(db *sqlx.DB) *RedemptionRepository {
+	return &RedemptionRepository{
+		db: db,
+	}
+}
+
+func (r *RedemptionRepository) WithTx(tx *sqlx.Tx) *RedemptionRepository {
+	return &RedemptionRepository{
+		db: tx,
+	}
+}
+
+// conn returns the transaction ctx carries, or the repository's own
+// connection outside of one.
+func (r *RedemptionRepository) conn(ctx context.Context) dbConn {
+	if tx, ok := repository.TxFromContext(ctx); ok {
+		return tx
+	}
+	return r.db
+}
+
+func (r *RedemptionRepository) Create(ctx context.Context, redemption *entity.Redemption) error {
+	if redemption.Status == "" {
+		redemption.Status = entity.RedemptionStatusPending
+	}
+
+	query := `
+  INSERT INTO redemptions (user_id, item_id, variant_id, quantity, method, address, location_id, status)
+  VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+  RETURNING id, created_at`
+
+	err := r.conn(ctx).QueryRowContext(
+		ctx,
+		query,
+		redemption.UserID,
+		redemption.ItemID,
+		redemption.VariantID,
+		redemption.Quantity,
+		redemption.Method,
+		redemption.Address,
+		redemption.LocationID,
+		redemption.Status,
+	).Scan(&redemption.ID, &redemption.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create redemption: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID locks the redemption for the rest of the transaction.
+func (r *RedemptionRepository) GetByID(ctx context.Context, id int64) (entity.Redemption, error) {
+	var redemption entity.Redemption
+	query := selectRedemptions + `
+  WHERE r.id = $1
+  FOR UPDATE OF r`
+
+	err := r.conn(ctx).GetContext(ctx, &redemption, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Redemption{}, entity.ErrRedemptionNotFound
+		}
+		return entity.Redemption{}, fmt.Errorf("failed to get redemption: %w", err)
+	}
+
+	return redemption, nil
+}
+
+func (r *RedemptionRepository) ListByUser(ctx context.Context, userID int64) ([]entity.Redemption, error) {
+	var redemptions []entity.Redemption
+	query := selectRedemptions + `
+  WHERE r.user_id = $1
+  ORDER BY r.created_at DESC, r.id DESC`
+
+	err := r.conn(ctx).SelectContext(ctx, &redemptions, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user redemptions: %w", err)
+	}
+
+	return redemptions, nil
+}
+
+// ListPending returns the redemptions still to be handed out, grouped by
+// destination so a pick list can be worked through location by location.
+func (r *RedemptionRepository) ListPending(ctx context.Context) ([]entity.Redemption, error) {
+	var redemptions []entity.Redemption
+	query := selectRedemptions + `
+  WHERE r.status = 'pending'
+  ORDER BY r.method, l.name NULLS LAST, r.created_at, r.id`
+
+	err := r.conn(ctx).SelectContext(ctx, &redemptions, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending redemptions: %w", err)
+	}
+
+	return redemptions, nil
+}
+
+func (r *RedemptionRepository) Update(ctx context.Context, redemption entity.Redemption) error {
+	query := `
+  UPDATE redemptions
+  SET status = $2, resolved_by = $3, resolved_at = $4
+  WHERE id = $1`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, redemption.ID, redemption.Status, redemption.ResolvedBy, redemption.ResolvedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update redemption: %w", err)
+	}
+
+	return nil
+}
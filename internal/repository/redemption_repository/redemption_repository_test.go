@@ -0,0 +1,141 @@
+package redemption_repository
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/testutils"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type RedemptionRepositoryTestSuite struct {
+	suite.Suite
+	db   *sqlx.DB
+	repo *RedemptionRepository
+}
+
+func (s *RedemptionRepositoryTestSuite) SetupSuite() {
+	db, err := testutils.GetTestDB()
+	require.NoError(s.T(), err)
+
+	s.db = db
+	s.repo = NewRedemptionRepository(db)
+
+	s.recreateTables()
+}
+
+func (s *RedemptionRepositoryTestSuite) SetupTest() {
+	_, err := s.db.Exec("TRUNCATE TABLE redemptions, pickup_locations, merch_variants, merch_items, users RESTART IDENTITY CASCADE")
+	require.NoError(s.T(), err)
+
+	_, err = s.db.Exec(`
+  INSERT INTO users (username, password_hash, coins) VALUES ('user1', 'hash1', 1000);
+  INSERT INTO merch_items (name, price) VALUES ('hoody', 300), ('cup', 20);
+  INSERT INTO merch_variants (item_id, size, color) VALUES (1, 'L', 'black');
+  INSERT INTO pickup_locations (name) VALUES ('Moscow')`)
+	require.NoError(s.T(), err)
+}
+
+func (s *RedemptionRepositoryTestSuite) TearDownSuite() {
+	s.db.Close()
+}
+
+func (s *RedemptionRepositoryTestSuite) recreateTables() {
+	_, err := s.db.Exec(`
+  DROP TABLE IF EXISTS redemptions;
+  DROP TABLE IF EXISTS pickup_locations;
+  DROP TABLE IF EXISTS merch_variants;
+  DROP TABLE IF EXISTS merch_items CASCADE;
+  DROP TABLE IF EXISTS users CASCADE;
+
+  CREATE TABLE users (
+   id SERIAL PRIMARY KEY,
+   username VARCHAR(255) UNIQUE NOT NULL,
+   password_hash VARCHAR(255) NOT NULL,
+   coins INTEGER NOT NULL DEFAULT 1000,
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+
+  CREATE TABLE merch_items (
+   id SERIAL PRIMARY KEY,
+   name VARCHAR(255) UNIQUE NOT NULL,
+   price INTEGER NOT NULL CHECK (price > 0),
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+
+  CREATE TABLE merch_variants (
+   id SERIAL PRIMARY KEY,
+   item_id INTEGER NOT NULL REFERENCES merch_items(id) ON DELETE CASCADE,
+   size VARCHAR(20) NOT NULL DEFAULT '',
+   color VARCHAR(50) NOT NULL DEFAULT ''
+  );
+
+  CREATE TABLE pickup_locations (
+   id SERIAL PRIMARY KEY,
+   name VARCHAR(100) UNIQUE NOT NULL,
+   address VARCHAR(255) NOT NULL DEFAULT '',
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+
+  CREATE TABLE redemptions (
+   id SERIAL PRIMARY KEY,
+   user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+   item_id INTEGER NOT NULL REFERENCES merch_items(id),
+   variant_id INTEGER REFERENCES merch_variants(id) ON DELETE SET NULL,
+   quantity INTEGER NOT NULL CHECK (quantity > 0),
+   method VARCHAR(20) NOT NULL,
+   address VARCHAR(500) NOT NULL DEFAULT '',
+   location_id INTEGER REFERENCES pickup_locations(id),
+   status VARCHAR(20) NOT NULL DEFAULT 'pending',
+   resolved_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+   resolved_at TIMESTAMP WITH TIME ZONE,
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+ `)
+	require.NoError(s.T(), err)
+}
+
+func (s *RedemptionRepositoryTestSuite) TestLifecycle() {
+	ctx := context.Background()
+	office := int64(1)
+	large := int64(1)
+
+	delivery := entity.Redemption{UserID: 1, ItemID: 2, Quantity: 2, Method: entity.RedemptionMethodDelivery, Address: "Nevsky 1"}
+	pickup := entity.Redemption{UserID: 1, ItemID: 1, VariantID: &large, Quantity: 1, Method: entity.RedemptionMethodPickup, LocationID: &office}
+	s.NoError(s.repo.Create(ctx, &delivery))
+	s.NoError(s.repo.Create(ctx, &pickup))
+	s.Equal(entity.RedemptionStatusPending, delivery.Status)
+
+	pending, err := s.repo.ListPending(ctx)
+	s.NoError(err)
+	s.Len(pending, 2)
+	s.Equal(entity.RedemptionMethodDelivery, pending[0].Method)
+	s.Equal("user1", pending[1].Username)
+	s.Equal("hoody", pending[1].ItemName)
+	s.Equal("L, black", pending[1].Variant)
+	s.Equal("Moscow", pending[1].Location)
+
+	redemption, err := s.repo.GetByID(ctx, delivery.ID)
+	s.NoError(err)
+	s.NoError(redemption.Resolve(entity.RedemptionStatusFulfilled, 1, time.Now()))
+	s.NoError(s.repo.Update(ctx, redemption))
+
+	pending, err = s.repo.ListPending(ctx)
+	s.NoError(err)
+	s.Len(pending, 1)
+
+	mine, err := s.repo.ListByUser(ctx, 1)
+	s.NoError(err)
+	s.Len(mine, 2)
+
+	_, err = s.repo.GetByID(ctx, 100)
+	s.ErrorIs(err, entity.ErrRedemptionNotFound)
+}
+
+func TestRedemptionRepository(t *testing.T) {
+	suite.Run(t, new(RedemptionRepositoryTestSuite))
+}
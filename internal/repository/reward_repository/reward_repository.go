@@ -0,0 +1,158 @@
+package reward_repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/repository"
+)
+
+type dbConn interface {
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type RewardRepository struct {
+	db dbConn
+}
+
+func NewRewardRepository(db *sqlx.DB) *RewardRepository {
+	return &RewardRepository{
+		db: db,
+	}
+}
+
+func (r *RewardRepository) WithTx(tx *sqlx.Tx) *RewardRepository {
+	return &RewardRepository{
+		db: tx,
+	}
+}
+
+// conn returns the transaction ctx carries, or the repository's own
+// connection outside of one.
+func (r *RewardRepository) conn(ctx context.Context) dbConn {
+	if tx, ok := repository.TxFromContext(ctx); ok {
+		return tx
+	}
+	return r.db
+}
+
+func (r *RewardRepository) GetBudget(ctx context.Context, managerID int64) (entity.RewardBudget, error) {
+	var budget entity.RewardBudget
+	query := `
+  SELECT user_id, allocation, balance, period, updated_at
+  FROM reward_budgets
+  WHERE user_id = $1
+  FOR UPDATE`
+
+	err := r.conn(ctx).GetContext(ctx, &budget, query, managerID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.RewardBudget{}, entity.ErrBudgetNotFound
+		}
+		return entity.RewardBudget{}, fmt.Errorf("failed to get reward budget: %w", err)
+	}
+
+	return budget, nil
+}
+
+func (r *RewardRepository) ListBudgets(ctx context.Context) ([]entity.RewardBudget, error) {
+	query := `
+  SELECT user_id, allocation, balance, period, updated_at
+  FROM reward_budgets
+  ORDER BY user_id`
+
+	var budgets []entity.RewardBudget
+	err := r.conn(ctx).SelectContext(ctx, &budgets, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reward budgets: %w", err)
+	}
+
+	return budgets, nil
+}
+
+// SaveBudget creates the manager's budget or overwrites the existing one.
+func (r *RewardRepository) SaveBudget(ctx context.Context, budget entity.RewardBudget) error {
+	query := `
+  INSERT INTO reward_budgets (user_id, allocation, balance, period, updated_at)
+  VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP)
+  ON CONFLICT (user_id) DO UPDATE
+  SET allocation = EXCLUDED.allocation,
+   balance = EXCLUDED.balance,
+   period = EXCLUDED.period,
+   updated_at = EXCLUDED.updated_at`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, budget.UserID, budget.Allocation, budget.Balance, budget.Period)
+	if err != nil {
+		return fmt.Errorf("failed to save reward budget: %w", err)
+	}
+
+	return nil
+}
+
+// ResetBudgets refills every budget that has not been reset for period yet
+// and returns how many were refilled. Running it again in the same period is
+// a no-op.
+func (r *RewardRepository) ResetBudgets(ctx context.Context, period string) (int64, error) {
+	query := `
+  UPDATE reward_budgets
+  SET balance = allocation,
+   period = $1,
+   updated_at = CURRENT_TIMESTAMP
+  WHERE period <> $1`
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, period)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reset reward budgets: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+func (r *RewardRepository) CreateReward(ctx context.Context, reward *entity.Reward) error {
+	query := `
+  INSERT INTO rewards (manager_id, user_id, amount, reason, period)
+  VALUES ($1, $2, $3, $4, $5)
+  RETURNING id, created_at`
+
+	err := r.conn(ctx).QueryRowContext(
+		ctx,
+		query,
+		reward.ManagerID,
+		reward.UserID,
+		reward.Amount,
+		reward.Reason,
+		reward.Period,
+	).Scan(&reward.ID, &reward.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create reward: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RewardRepository) ListRewards(ctx context.Context, managerID int64, period string) ([]entity.Reward, error) {
+	query := `
+  SELECT id, manager_id, user_id, amount, reason, period, created_at
+  FROM rewards
+  WHERE manager_id = $1 AND period = $2
+  ORDER BY created_at DESC`
+
+	var rewards []entity.Reward
+	err := r.conn(ctx).SelectContext(ctx, &rewards, query, managerID, period)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rewards: %w", err)
+	}
+
+	return rewards, nil
+}
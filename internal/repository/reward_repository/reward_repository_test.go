@@ -0,0 +1,142 @@
+package reward_repository
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/testutils"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type RewardRepositoryTestSuite struct {
+	suite.Suite
+	db   *sqlx.DB
+	repo *RewardRepository
+}
+
+func (s *RewardRepositoryTestSuite) SetupSuite() {
+	db, err := testutils.GetTestDB()
+	require.NoError(s.T(), err)
+
+	s.db = db
+	s.repo = NewRewardRepository(db)
+
+	s.recreateTables()
+}
+
+func (s *RewardRepositoryTestSuite) SetupTest() {
+	_, err := s.db.Exec("TRUNCATE TABLE rewards, reward_budgets, users RESTART IDENTITY CASCADE")
+	require.NoError(s.T(), err)
+
+	_, err = s.db.Exec(`
+  INSERT INTO users (username, password_hash, coins, role)
+  VALUES
+  ('manager', 'hash1', 1000, 'manager'),
+  ('report', 'hash2', 1000, 'user')`)
+	require.NoError(s.T(), err)
+}
+
+func (s *RewardRepositoryTestSuite) TearDownSuite() {
+	s.db.Close()
+}
+
+func (s *RewardRepositoryTestSuite) recreateTables() {
+	_, err := s.db.Exec(`
+  DROP TABLE IF EXISTS rewards;
+  DROP TABLE IF EXISTS reward_budgets;
+  DROP TABLE IF EXISTS users CASCADE;
+
+  CREATE TABLE users (
+   id SERIAL PRIMARY KEY,
+   username VARCHAR(255) UNIQUE NOT NULL,
+   password_hash VARCHAR(255) NOT NULL,
+   coins INTEGER NOT NULL DEFAULT 1000,
+   role VARCHAR(50) NOT NULL DEFAULT 'user',
+   team_id INTEGER,
+   manager_id INTEGER,
+   public_kudos BOOLEAN NOT NULL DEFAULT FALSE,
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+
+  CREATE TABLE reward_budgets (
+   user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+   allocation INTEGER NOT NULL CHECK (allocation >= 0),
+   balance INTEGER NOT NULL CHECK (balance >= 0),
+   period VARCHAR(7) NOT NULL,
+   updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+
+  CREATE TABLE rewards (
+   id SERIAL PRIMARY KEY,
+   manager_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+   user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+   amount INTEGER NOT NULL CHECK (amount > 0),
+   reason VARCHAR(255) NOT NULL,
+   period VARCHAR(7) NOT NULL,
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+ `)
+	require.NoError(s.T(), err)
+}
+
+func (s *RewardRepositoryTestSuite) TestBudget() {
+	ctx := context.Background()
+
+	s.Run("missing budget", func() {
+		_, err := s.repo.GetBudget(ctx, 1)
+		s.ErrorIs(err, entity.ErrBudgetNotFound)
+	})
+
+	s.Run("save and update", func() {
+		err := s.repo.SaveBudget(ctx, entity.RewardBudget{UserID: 1, Allocation: 500, Balance: 500, Period: "2026-10"})
+		s.NoError(err)
+
+		err = s.repo.SaveBudget(ctx, entity.RewardBudget{UserID: 1, Allocation: 500, Balance: 200, Period: "2026-10"})
+		s.NoError(err)
+
+		budget, err := s.repo.GetBudget(ctx, 1)
+		s.NoError(err)
+		s.Equal(int64(200), budget.Balance)
+		s.Equal(int64(500), budget.Allocation)
+	})
+
+	s.Run("reset is idempotent per period", func() {
+		reset, err := s.repo.ResetBudgets(ctx, "2026-11")
+		s.NoError(err)
+		s.Equal(int64(1), reset)
+
+		reset, err = s.repo.ResetBudgets(ctx, "2026-11")
+		s.NoError(err)
+		s.Zero(reset)
+
+		budget, err := s.repo.GetBudget(ctx, 1)
+		s.NoError(err)
+		s.Equal(int64(500), budget.Balance)
+		s.Equal("2026-11", budget.Period)
+	})
+}
+
+func (s *RewardRepositoryTestSuite) TestRewards() {
+	ctx := context.Background()
+
+	reward := entity.Reward{ManagerID: 1, UserID: 2, Amount: 50, Reason: "release", Period: "2026-10"}
+	err := s.repo.CreateReward(ctx, &reward)
+	s.NoError(err)
+	s.NotZero(reward.ID)
+
+	rewards, err := s.repo.ListRewards(ctx, 1, "2026-10")
+	s.NoError(err)
+	s.Len(rewards, 1)
+	s.Equal("release", rewards[0].Reason)
+
+	rewards, err = s.repo.ListRewards(ctx, 1, "2026-11")
+	s.NoError(err)
+	s.Empty(rewards)
+}
+
+func TestRewardRepository(t *testing.T) {
+	suite.Run(t, new(RewardRepositoryTestSuite))
+}
@@ -0,0 +1,190 @@
+package scheduled_transfer_repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/repository"
+	"time"
+)
+
+type dbConn interface {
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type ScheduledTransferRepository struct {
+	db dbConn
+}
+
+func NewScheduledTransferRepository(db *sqlx.DB) *ScheduledTransferRepository {
+	return &ScheduledTransferRepository{
+		db: db,
+	}
+}
+
+func (r *ScheduledTransferRepository) WithTx(tx *sqlx.Tx) *ScheduledTransferRepository {
+	return &ScheduledTransferRepository{
+		db: tx,
+	}
+}
+
+// conn returns the transaction ctx carries, or the repository's own
+// connection outside of one.
+func (r *ScheduledTransferRepository) conn(ctx context.Context) dbConn {
+	if tx, ok := repository.TxFromContext(ctx); ok {
+		return tx
+	}
+	return r.db
+}
+
+func (r *ScheduledTransferRepository) Create(ctx context.Context, st *entity.ScheduledTransfer) error {
+	query := `
+  INSERT INTO scheduled_transfers (from_user_id, to_user_id, amount, message, recurrence, first_run_at, next_run_at, status)
+  VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+  RETURNING id, created_at`
+
+	err := r.conn(ctx).QueryRowContext(
+		ctx,
+		query,
+		st.FromUserID,
+		st.ToUserID,
+		st.Amount,
+		st.Message,
+		st.Recurrence,
+		st.FirstRunAt,
+		st.NextRunAt,
+		st.Status,
+	).Scan(&st.ID, &st.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create scheduled transfer: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID locks the schedule row until the surrounding transaction ends.
+func (r *ScheduledTransferRepository) GetByID(ctx context.Context, id int64) (entity.ScheduledTransfer, error) {
+	var st entity.ScheduledTransfer
+	query := `
+  SELECT id, from_user_id, to_user_id, amount, message, recurrence, first_run_at, next_run_at, status, created_at
+  FROM scheduled_transfers
+  WHERE id = $1
+  FOR UPDATE`
+
+	err := r.conn(ctx).GetContext(ctx, &st, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.ScheduledTransfer{}, entity.ErrScheduledTransferNotFound
+		}
+		return entity.ScheduledTransfer{}, fmt.Errorf("failed to get scheduled transfer by id: %w", err)
+	}
+
+	return st, nil
+}
+
+// ListDue returns the IDs of active schedules whose next run is not after now.
+func (r *ScheduledTransferRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]int64, error) {
+	query := `
+  SELECT id
+  FROM scheduled_transfers
+  WHERE status = $1 AND next_run_at <= $2
+  ORDER BY next_run_at
+  LIMIT $3`
+
+	var ids []int64
+	err := r.conn(ctx).SelectContext(ctx, &ids, query, entity.ScheduledTransferStatusActive, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due scheduled transfers: %w", err)
+	}
+
+	return ids, nil
+}
+
+func (r *ScheduledTransferRepository) ListByUser(ctx context.Context, userID int64) ([]entity.ScheduledTransfer, error) {
+	query := `
+  SELECT id, from_user_id, to_user_id, amount, message, recurrence, first_run_at, next_run_at, status, created_at
+  FROM scheduled_transfers
+  WHERE from_user_id = $1
+  ORDER BY next_run_at`
+
+	var transfers []entity.ScheduledTransfer
+	err := r.conn(ctx).SelectContext(ctx, &transfers, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scheduled transfers by user: %w", err)
+	}
+
+	return transfers, nil
+}
+
+func (r *ScheduledTransferRepository) Update(ctx context.Context, st entity.ScheduledTransfer) error {
+	query := `
+  UPDATE scheduled_transfers
+  SET next_run_at = $1,
+   status = $2
+  WHERE id = $3`
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, st.NextRunAt, st.Status, st.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update scheduled transfer: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return entity.ErrScheduledTransferNotFound
+	}
+
+	return nil
+}
+
+// CreateRun records an occurrence. It reports false when that occurrence has
+// already been recorded.
+func (r *ScheduledTransferRepository) CreateRun(ctx context.Context, run *entity.ScheduledTransferRun) (bool, error) {
+	query := `
+  INSERT INTO scheduled_transfer_runs (scheduled_transfer_id, run_at, status, failure_reason)
+  VALUES ($1, $2, $3, $4)
+  ON CONFLICT ON CONSTRAINT unique_scheduled_transfer_run DO NOTHING
+  RETURNING id, created_at`
+
+	err := r.conn(ctx).QueryRowContext(
+		ctx,
+		query,
+		run.ScheduledTransferID,
+		run.RunAt,
+		run.Status,
+		run.FailureReason,
+	).Scan(&run.ID, &run.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to create scheduled transfer run: %w", err)
+	}
+
+	return true, nil
+}
+
+func (r *ScheduledTransferRepository) ListRuns(ctx context.Context, scheduledTransferID int64) ([]entity.ScheduledTransferRun, error) {
+	query := `
+  SELECT id, scheduled_transfer_id, run_at, status, failure_reason, created_at
+  FROM scheduled_transfer_runs
+  WHERE scheduled_transfer_id = $1
+  ORDER BY run_at DESC`
+
+	var runs []entity.ScheduledTransferRun
+	err := r.conn(ctx).SelectContext(ctx, &runs, query, scheduledTransferID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scheduled transfer runs: %w", err)
+	}
+
+	return runs, nil
+}
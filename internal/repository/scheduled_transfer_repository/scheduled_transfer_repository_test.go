@@ -0,0 +1,156 @@
+package scheduled_transfer_repository
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/testutils"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type ScheduledTransferRepositoryTestSuite struct {
+	suite.Suite
+	db   *sqlx.DB
+	repo *ScheduledTransferRepository
+}
+
+func (s *ScheduledTransferRepositoryTestSuite) SetupSuite() {
+	db, err := testutils.GetTestDB()
+	require.NoError(s.T(), err)
+
+	s.db = db
+	s.repo = NewScheduledTransferRepository(db)
+
+	s.recreateTables()
+}
+
+func (s *ScheduledTransferRepositoryTestSuite) SetupTest() {
+	_, err := s.db.Exec("TRUNCATE TABLE scheduled_transfer_runs, scheduled_transfers, users RESTART IDENTITY CASCADE")
+	require.NoError(s.T(), err)
+
+	_, err = s.db.Exec(`
+  INSERT INTO users (username, password_hash, coins)
+  VALUES
+  ('user1', 'hash1', 1000),
+  ('user2', 'hash2', 1000)`)
+	require.NoError(s.T(), err)
+}
+
+func (s *ScheduledTransferRepositoryTestSuite) TearDownSuite() {
+	s.db.Close()
+}
+
+func (s *ScheduledTransferRepositoryTestSuite) recreateTables() {
+	_, err := s.db.Exec(`
+  DROP TABLE IF EXISTS scheduled_transfer_runs;
+  DROP TABLE IF EXISTS scheduled_transfers;
+  DROP TABLE IF EXISTS users CASCADE;
+
+  CREATE TABLE users (
+   id SERIAL PRIMARY KEY,
+   username VARCHAR(255) UNIQUE NOT NULL,
+   password_hash VARCHAR(255) NOT NULL,
+   coins INTEGER NOT NULL DEFAULT 1000,
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+
+  CREATE TABLE scheduled_transfers (
+   id SERIAL PRIMARY KEY,
+   from_user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+   to_user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+   amount INTEGER NOT NULL CHECK (amount > 0),
+   message VARCHAR(280),
+   recurrence VARCHAR(50) NOT NULL DEFAULT '',
+   first_run_at TIMESTAMP WITH TIME ZONE NOT NULL,
+   next_run_at TIMESTAMP WITH TIME ZONE NOT NULL,
+   status VARCHAR(50) NOT NULL DEFAULT 'active',
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+
+  CREATE TABLE scheduled_transfer_runs (
+   id SERIAL PRIMARY KEY,
+   scheduled_transfer_id INTEGER NOT NULL REFERENCES scheduled_transfers(id) ON DELETE CASCADE,
+   run_at TIMESTAMP WITH TIME ZONE NOT NULL,
+   status VARCHAR(50) NOT NULL,
+   failure_reason VARCHAR(255),
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+   CONSTRAINT unique_scheduled_transfer_run UNIQUE(scheduled_transfer_id, run_at)
+  );
+ `)
+	require.NoError(s.T(), err)
+}
+
+func (s *ScheduledTransferRepositoryTestSuite) createTransfer(nextRunAt time.Time) entity.ScheduledTransfer {
+	st := entity.ScheduledTransfer{
+		FromUserID: 1,
+		ToUserID:   2,
+		Amount:     50,
+		Recurrence: entity.RecurrenceWeekly,
+		FirstRunAt: nextRunAt,
+		NextRunAt:  nextRunAt,
+		Status:     entity.ScheduledTransferStatusActive,
+	}
+	err := s.repo.Create(context.Background(), &st)
+	s.Require().NoError(err)
+
+	return st
+}
+
+func (s *ScheduledTransferRepositoryTestSuite) TestListDue() {
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	due := s.createTransfer(now.Add(-time.Minute))
+	s.createTransfer(now.Add(time.Hour))
+
+	ids, err := s.repo.ListDue(ctx, now, 10)
+	s.NoError(err)
+	s.Equal([]int64{due.ID}, ids)
+
+	due.Status = entity.ScheduledTransferStatusCancelled
+	s.NoError(s.repo.Update(ctx, due))
+
+	ids, err = s.repo.ListDue(ctx, now, 10)
+	s.NoError(err)
+	s.Empty(ids)
+
+	transfers, err := s.repo.ListByUser(ctx, 1)
+	s.NoError(err)
+	s.Len(transfers, 2)
+}
+
+func (s *ScheduledTransferRepositoryTestSuite) TestCreateRunIsIdempotent() {
+	ctx := context.Background()
+	st := s.createTransfer(time.Now().UTC())
+
+	run := entity.ScheduledTransferRun{
+		ScheduledTransferID: st.ID,
+		RunAt:               st.NextRunAt,
+		Status:              entity.ScheduledTransferRunSucceeded,
+	}
+
+	created, err := s.repo.CreateRun(ctx, &run)
+	s.NoError(err)
+	s.True(created)
+
+	created, err = s.repo.CreateRun(ctx, &run)
+	s.NoError(err)
+	s.False(created)
+
+	runs, err := s.repo.ListRuns(ctx, st.ID)
+	s.NoError(err)
+	s.Len(runs, 1)
+}
+
+func (s *ScheduledTransferRepositoryTestSuite) TestGetByID() {
+	_, err := s.repo.GetByID(context.Background(), 99999)
+	s.ErrorIs(err, entity.ErrScheduledTransferNotFound)
+}
+
+func TestScheduledTransferRepository(t *testing.T) {
+	suite.Run(t, new(ScheduledTransferRepositoryTestSuite))
+}
@@ -0,0 +1,74 @@
+package team_repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/repository"
+)
+
+type dbConn interface {
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+}
+
+type TeamRepository struct {
+	db dbConn
+}
+
+func NewTeamRepository(db *sqlx.DB) *TeamRepository {
+	return &TeamRepository{
+		db: db,
+	}
+}
+
+func (r *TeamRepository) WithTx(tx *sqlx.Tx) *TeamRepository {
+	return &TeamRepository{
+		db: tx,
+	}
+}
+
+// conn returns the transaction ctx carries, or the repository's own
+// connection outside of one.
+func (r *TeamRepository) conn(ctx context.Context) dbConn {
+	if tx, ok := repository.TxFromContext(ctx); ok {
+		return tx
+	}
+	return r.db
+}
+
+func (r *TeamRepository) List(ctx context.Context) ([]entity.Team, error) {
+	var teams []entity.Team
+	query := `
+        SELECT id, name, created_at
+        FROM teams
+        ORDER BY id`
+
+	err := r.conn(ctx).SelectContext(ctx, &teams, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list teams: %w", err)
+	}
+
+	return teams, nil
+}
+
+func (r *TeamRepository) GetByID(ctx context.Context, id int64) (entity.Team, error) {
+	var team entity.Team
+	query := `
+        SELECT id, name, created_at
+        FROM teams
+        WHERE id = $1`
+
+	err := r.conn(ctx).GetContext(ctx, &team, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Team{}, entity.ErrTeamNotFound
+		}
+		return entity.Team{}, fmt.Errorf("failed to get team by id: %w", err)
+	}
+
+	return team, nil
+}
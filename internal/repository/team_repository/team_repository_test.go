@@ -0,0 +1,84 @@
+package team_repository
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/testutils"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type TeamRepositoryTestSuite struct {
+	suite.Suite
+	db   *sqlx.DB
+	repo *TeamRepository
+}
+
+func (s *TeamRepositoryTestSuite) SetupSuite() {
+	db, err := testutils.GetTestDB()
+	require.NoError(s.T(), err)
+
+	s.db = db
+	s.repo = NewTeamRepository(db)
+
+	s.recreateTable()
+}
+
+func (s *TeamRepositoryTestSuite) SetupTest() {
+	_, err := s.db.Exec("TRUNCATE TABLE teams RESTART IDENTITY")
+	require.NoError(s.T(), err)
+}
+
+func (s *TeamRepositoryTestSuite) TearDownSuite() {
+	s.db.Close()
+}
+
+func (s *TeamRepositoryTestSuite) recreateTable() {
+	_, err := s.db.Exec(`
+        DROP TABLE IF EXISTS teams;
+        CREATE TABLE teams (
+            id SERIAL PRIMARY KEY,
+            name VARCHAR(255) UNIQUE NOT NULL,
+            created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+        )
+    `)
+	require.NoError(s.T(), err)
+}
+
+func (s *TeamRepositoryTestSuite) TestList() {
+	ctx := context.Background()
+
+	_, err := s.db.Exec(`INSERT INTO teams (name) VALUES ('backend'), ('frontend')`)
+	s.NoError(err)
+
+	teams, err := s.repo.List(ctx)
+	s.NoError(err)
+	s.Len(teams, 2)
+	s.Equal("backend", teams[0].Name)
+}
+
+func (s *TeamRepositoryTestSuite) TestGetByID() {
+	ctx := context.Background()
+
+	var teamID int64
+	err := s.db.QueryRow(`INSERT INTO teams (name) VALUES ('backend') RETURNING id`).Scan(&teamID)
+	s.NoError(err)
+
+	s.Run("existing team", func() {
+		team, err := s.repo.GetByID(ctx, teamID)
+		s.NoError(err)
+		s.Equal("backend", team.Name)
+	})
+
+	s.Run("non-existing team", func() {
+		_, err := s.repo.GetByID(ctx, 99999)
+		s.ErrorIs(err, entity.ErrTeamNotFound)
+	})
+}
+
+func TestTeamRepository(t *testing.T) {
+	suite.Run(t, new(TeamRepositoryTestSuite))
+}
@@ -7,11 +7,18 @@ import (
 	"fmt"
 	"github.com/jmoiron/sqlx"
 	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/repository"
+	"time"
 )
 
 type Repository interface {
 	Create(ctx context.Context, tr *entity.Transaction) error
 	GetByUserID(ctx context.Context, userID int64) ([]entity.Transaction, error)
+	ListKudos(ctx context.Context, limit int) ([]entity.Kudos, error)
+	SumOutgoing(ctx context.Context, fromUserID int64, since time.Time) (int64, error)
+	SumOutgoingTo(ctx context.Context, fromUserID, toUserID int64, since time.Time) (int64, error)
+	ListSince(ctx context.Context, since time.Time) ([]entity.Transaction, error)
+	ListOutgoingSince(ctx context.Context, fromUserID int64, since time.Time) ([]entity.Transaction, error)
 }
 
 type dbConn interface {
@@ -37,13 +44,22 @@ func (r *TransactionRepository) WithTx(tx *sqlx.Tx) *TransactionRepository {
 	}
 }
 
+// conn returns the transaction ctx carries, or the repository's own
+// connection outside of one.
+func (r *TransactionRepository) conn(ctx context.Context) dbConn {
+	if tx, ok := repository.TxFromContext(ctx); ok {
+		return tx
+	}
+	return r.db
+}
+
 func (r *TransactionRepository) Create(ctx context.Context, tr *entity.Transaction) error {
 	query := `
-  INSERT INTO transactions (from_user_id, to_user_id, amount, type, item_id)
-  VALUES ($1, $2, $3, $4, $5)
+  INSERT INTO transactions (from_user_id, to_user_id, amount, type, item_id, bundle_id, list_price, quantity, message)
+  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
   RETURNING id, created_at`
 
-	err := r.db.QueryRowContext(
+	err := r.conn(ctx).QueryRowContext(
 		ctx,
 		query,
 		tr.FromUserID,
@@ -51,6 +67,10 @@ func (r *TransactionRepository) Create(ctx context.Context, tr *entity.Transacti
 		tr.Amount,
 		tr.Type,
 		tr.ItemID,
+		tr.BundleID,
+		tr.ListPrice,
+		tr.Quantity,
+		tr.Message,
 	).Scan(&tr.ID, &tr.CreatedAt)
 
 	if err != nil {
@@ -62,13 +82,13 @@ func (r *TransactionRepository) Create(ctx context.Context, tr *entity.Transacti
 
 func (r *TransactionRepository) GetByUserID(ctx context.Context, userID int64) ([]entity.Transaction, error) {
 	query := `
-  SELECT id, from_user_id, to_user_id, amount, type, item_id, created_at
+  SELECT id, from_user_id, to_user_id, amount, type, item_id, bundle_id, list_price, quantity, message, created_at
   FROM transactions
   WHERE from_user_id = $1 OR to_user_id = $1
   ORDER BY created_at DESC`
 
 	var transactions []entity.Transaction
-	err := r.db.SelectContext(ctx, &transactions, query, userID)
+	err := r.conn(ctx).SelectContext(ctx, &transactions, query, userID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return []entity.Transaction{}, nil
@@ -78,3 +98,135 @@ func (r *TransactionRepository) GetByUserID(ctx context.Context, userID int64) (
 
 	return transactions, nil
 }
+
+// ListKudos returns the latest transfers with a message between users who
+// both opted in to the public feed.
+func (r *TransactionRepository) ListKudos(ctx context.Context, limit int) ([]entity.Kudos, error) {
+	query := `
+  SELECT t.id, f.username AS from_username, u.username AS to_username, t.amount, t.message, t.created_at
+  FROM transactions t
+  JOIN users f ON f.id = t.from_user_id
+  JOIN users u ON u.id = t.to_user_id
+  WHERE t.type = $1
+   AND t.message IS NOT NULL
+   AND f.public_kudos
+   AND u.public_kudos
+  ORDER BY t.created_at DESC
+  LIMIT $2`
+
+	var kudos []entity.Kudos
+	err := r.conn(ctx).SelectContext(ctx, &kudos, query, entity.TransactionTypeTransfer, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list kudos: %w", err)
+	}
+
+	return kudos, nil
+}
+
+// SumOutgoing returns the total the user has transferred or paid for
+// marketplace purchases to other users since the given time.
+func (r *TransactionRepository) SumOutgoing(ctx context.Context, fromUserID int64, since time.Time) (int64, error) {
+	query := `
+  SELECT COALESCE(SUM(amount), 0)
+  FROM transactions
+  WHERE from_user_id = $1
+   AND type IN ($2, $3)
+   AND created_at >= $4`
+
+	var total int64
+	err := r.conn(ctx).GetContext(ctx, &total, query, fromUserID, entity.TransactionTypeTransfer, entity.TransactionTypeMarketSale, since)
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum outgoing transfers: %w", err)
+	}
+
+	return total, nil
+}
+
+// SumOutgoingTo returns the total the user has transferred or paid for
+// marketplace purchases to one recipient since the given time.
+func (r *TransactionRepository) SumOutgoingTo(ctx context.Context, fromUserID, toUserID int64, since time.Time) (int64, error) {
+	query := `
+  SELECT COALESCE(SUM(amount), 0)
+  FROM transactions
+  WHERE from_user_id = $1
+   AND to_user_id = $2
+   AND type IN ($3, $4)
+   AND created_at >= $5`
+
+	var total int64
+	err := r.conn(ctx).GetContext(ctx, &total, query, fromUserID, toUserID, entity.TransactionTypeTransfer, entity.TransactionTypeMarketSale, since)
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum outgoing transfers to user: %w", err)
+	}
+
+	return total, nil
+}
+
+// ListSince returns every transaction created since the given time, oldest
+// first.
+func (r *TransactionRepository) ListSince(ctx context.Context, since time.Time) ([]entity.Transaction, error) {
+	query := `
+  SELECT id, from_user_id, to_user_id, amount, type, item_id, bundle_id, list_price, quantity, message, created_at
+  FROM transactions
+  WHERE created_at >= $1
+  ORDER BY created_at, id`
+
+	var transactions []entity.Transaction
+	err := r.conn(ctx).SelectContext(ctx, &transactions, query, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transactions since: %w", err)
+	}
+
+	return transactions, nil
+}
+
+// ListTransfersReachable returns the transfers created since the given time
+// that start at a user reachable from userID through at most maxHops-1 such
+// transfers, oldest first. These are all the edges a chain of up to maxHops
+// transfers starting at userID can use.
+func (r *TransactionRepository) ListTransfersReachable(ctx context.Context, userID int64, since time.Time, maxHops int) ([]entity.Transaction, error) {
+	query := `
+  WITH RECURSIVE reach (user_id, depth) AS (
+   SELECT $1::INTEGER, 1
+   UNION
+   SELECT t.to_user_id, reach.depth + 1
+   FROM reach
+   JOIN transactions t ON t.from_user_id = reach.user_id
+   WHERE t.type = $2
+    AND t.created_at >= $3
+    AND reach.depth < $4
+  )
+  SELECT id, from_user_id, to_user_id, amount, type, item_id, bundle_id, list_price, quantity, message, created_at
+  FROM transactions
+  WHERE type = $2
+   AND created_at >= $3
+   AND from_user_id IN (SELECT user_id FROM reach)
+  ORDER BY created_at, id`
+
+	var transactions []entity.Transaction
+	err := r.conn(ctx).SelectContext(ctx, &transactions, query, userID, entity.TransactionTypeTransfer, since, maxHops)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reachable transfers: %w", err)
+	}
+
+	return transactions, nil
+}
+
+// ListOutgoingSince returns the user's outgoing transactions, purchases
+// included, created since the given time, oldest first.
+func (r *TransactionRepository) ListOutgoingSince(ctx context.Context, fromUserID int64, since time.Time) ([]entity.Transaction, error) {
+	query := `
+  SELECT id, from_user_id, to_user_id, amount, type, item_id, bundle_id, list_price, quantity, message, created_at
+  FROM transactions
+  WHERE from_user_id = $1
+   AND created_at >= $2
+  ORDER BY created_at, id`
+
+	var transactions []entity.Transaction
+	err := r.conn(ctx).SelectContext(ctx, &transactions, query, fromUserID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list outgoing transactions since: %w", err)
+	}
+
+	return transactions, nil
+}
@@ -64,6 +64,10 @@ func (s *TransactionRepositoryTestSuite) recreateTables() {
    username VARCHAR(255) UNIQUE NOT NULL,
    password_hash VARCHAR(255) NOT NULL,
    coins INTEGER NOT NULL DEFAULT 1000,
+   role VARCHAR(50) NOT NULL DEFAULT 'user',
+   team_id INTEGER,
+   manager_id INTEGER,
+   public_kudos BOOLEAN NOT NULL DEFAULT FALSE,
    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
   );
   
@@ -71,9 +75,13 @@ func (s *TransactionRepositoryTestSuite) recreateTables() {
    id SERIAL PRIMARY KEY,
    from_user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
    to_user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
-   amount INTEGER NOT NULL CHECK (amount > 0),
-   type VARCHAR(50) NOT NULL CHECK (type IN ('transfer', 'purchase')),
+   amount INTEGER NOT NULL CHECK (amount > 0 OR (type = 'item_transfer' AND amount = 0)),
+   type VARCHAR(50) NOT NULL CHECK (type IN ('transfer', 'purchase', 'grant', 'reward', 'item_transfer')),
    item_id INTEGER,
+   bundle_id INTEGER,
+   list_price INTEGER,
+   quantity INTEGER,
+   message VARCHAR(280),
    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
   );
   
@@ -117,6 +125,58 @@ func (s *TransactionRepositoryTestSuite) TestCreateTransaction() {
 		s.NotZero(tx.CreatedAt)
 	})
 
+	s.Run("successful item transfer creation", func() {
+		itemID, quantity := int64(1), int64(2)
+		tx := entity.Transaction{
+			FromUserID: 1,
+			ToUserID:   2,
+			Type:       entity.TransactionTypeItemTransfer,
+			ItemID:     &itemID,
+			Quantity:   &quantity,
+		}
+
+		err := s.repo.Create(ctx, &tx)
+		s.NoError(err)
+
+		transactions, err := s.repo.GetByUserID(ctx, 2)
+		s.NoError(err)
+		s.Require().NotEmpty(transactions)
+		s.Equal(entity.TransactionTypeItemTransfer, transactions[0].Type)
+		s.Zero(transactions[0].Amount)
+		s.Equal(&quantity, transactions[0].Quantity)
+	})
+
+	s.Run("bundle purchase", func() {
+		bundleID := int64(3)
+		tx := entity.Transaction{
+			FromUserID: 1,
+			ToUserID:   1,
+			Amount:     40,
+			Type:       entity.TransactionTypePurchase,
+			BundleID:   &bundleID,
+		}
+
+		err := s.repo.Create(ctx, &tx)
+		s.NoError(err)
+
+		transactions, err := s.repo.GetByUserID(ctx, 1)
+		s.NoError(err)
+		s.Require().NotEmpty(transactions)
+		s.Equal(&bundleID, transactions[0].BundleID)
+		s.Nil(transactions[0].ItemID)
+	})
+
+	s.Run("zero amount only for item transfers", func() {
+		tx := entity.Transaction{
+			FromUserID: 1,
+			ToUserID:   2,
+			Type:       entity.TransactionTypeTransfer,
+		}
+
+		err := s.repo.Create(ctx, &tx)
+		s.Error(err)
+	})
+
 	s.Run("fail on non-existent user", func() {
 		tx := entity.Transaction{
 			FromUserID: 999,
@@ -174,6 +234,125 @@ func (s *TransactionRepositoryTestSuite) TestGetByUserID() {
 	})
 }
 
+func (s *TransactionRepositoryTestSuite) TestListKudos() {
+	ctx := context.Background()
+
+	_, err := s.db.Exec(`
+  INSERT INTO users (username, password_hash, coins, public_kudos)
+  VALUES ('user3', 'hash3', 1000, TRUE), ('user4', 'hash4', 1000, TRUE)`)
+	s.NoError(err)
+
+	message := "thanks for the review"
+	txs := []entity.Transaction{
+		{FromUserID: 3, ToUserID: 4, Amount: 10, Type: entity.TransactionTypeTransfer, Message: &message},
+		{FromUserID: 3, ToUserID: 4, Amount: 20, Type: entity.TransactionTypeTransfer},
+		{FromUserID: 1, ToUserID: 4, Amount: 30, Type: entity.TransactionTypeTransfer, Message: &message},
+	}
+	for _, tx := range txs {
+		err := s.repo.Create(ctx, &tx)
+		s.NoError(err)
+	}
+
+	kudos, err := s.repo.ListKudos(ctx, 10)
+	s.NoError(err)
+	s.Len(kudos, 1)
+	s.Equal("user3", kudos[0].FromUsername)
+	s.Equal("user4", kudos[0].ToUsername)
+	s.Equal(message, kudos[0].Message)
+
+	transactions, err := s.repo.GetByUserID(ctx, 3)
+	s.NoError(err)
+	s.Len(transactions, 2)
+}
+
+func (s *TransactionRepositoryTestSuite) TestSumOutgoing() {
+	ctx := context.Background()
+
+	txs := []entity.Transaction{
+		{FromUserID: 1, ToUserID: 2, Amount: 10, Type: entity.TransactionTypeTransfer},
+		{FromUserID: 1, ToUserID: 2, Amount: 20, Type: entity.TransactionTypeTransfer},
+		{FromUserID: 2, ToUserID: 1, Amount: 40, Type: entity.TransactionTypeTransfer},
+		{FromUserID: 1, ToUserID: 1, Amount: 80, Type: entity.TransactionTypePurchase},
+		{FromUserID: 1, ToUserID: 3, Amount: 5, Type: entity.TransactionTypeMarketSale},
+	}
+	for _, tx := range txs {
+		err := s.repo.Create(ctx, &tx)
+		s.NoError(err)
+	}
+
+	_, err := s.db.Exec(`
+  INSERT INTO transactions (from_user_id, to_user_id, amount, type, created_at)
+  VALUES (1, 2, 160, 'transfer', NOW() - INTERVAL '2 days')`)
+	s.NoError(err)
+
+	since := time.Now().Add(-entity.TransferLimitWindow)
+
+	total, err := s.repo.SumOutgoing(ctx, 1, since)
+	s.NoError(err)
+	s.Equal(int64(35), total)
+
+	total, err = s.repo.SumOutgoingTo(ctx, 1, 2, since)
+	s.NoError(err)
+	s.Equal(int64(30), total)
+
+	total, err = s.repo.SumOutgoingTo(ctx, 2, 2, since)
+	s.NoError(err)
+	s.Zero(total)
+}
+
+func (s *TransactionRepositoryTestSuite) TestListSince() {
+	ctx := context.Background()
+
+	_, err := s.db.Exec(`
+  INSERT INTO transactions (from_user_id, to_user_id, amount, type, created_at)
+  VALUES
+  (1, 2, 10, 'transfer', NOW() - INTERVAL '2 days'),
+  (1, 2, 20, 'transfer', NOW() - INTERVAL '1 hour'),
+  (2, 1, 30, 'transfer', NOW() - INTERVAL '30 minutes'),
+  (1, 1, 40, 'purchase', NOW() - INTERVAL '10 minutes')`)
+	s.NoError(err)
+
+	since := time.Now().Add(-24 * time.Hour)
+
+	transactions, err := s.repo.ListSince(ctx, since)
+	s.NoError(err)
+	s.Len(transactions, 3)
+	s.Equal(int64(20), transactions[0].Amount)
+
+	transactions, err = s.repo.ListOutgoingSince(ctx, 1, since)
+	s.NoError(err)
+	s.Len(transactions, 2)
+	s.Equal(entity.TransactionTypePurchase, transactions[1].Type)
+}
+
+func (s *TransactionRepositoryTestSuite) TestListTransfersReachable() {
+	ctx := context.Background()
+
+	_, err := s.db.Exec(`
+  INSERT INTO transactions (from_user_id, to_user_id, amount, type, created_at)
+  VALUES
+  (2, 3, 10, 'transfer', NOW() - INTERVAL '1 hour'),
+  (3, 1, 20, 'transfer', NOW() - INTERVAL '50 minutes'),
+  (1, 4, 30, 'transfer', NOW() - INTERVAL '40 minutes'),
+  (4, 5, 40, 'transfer', NOW() - INTERVAL '30 minutes'),
+  (5, 2, 50, 'transfer', NOW() - INTERVAL '2 days'),
+  (3, 3, 60, 'purchase', NOW() - INTERVAL '10 minutes')`)
+	s.NoError(err)
+
+	since := time.Now().Add(-24 * time.Hour)
+
+	// 2 -> 3 -> 1 -> 4: two hops out of 2 reach 1 but not 4.
+	transactions, err := s.repo.ListTransfersReachable(ctx, 2, since, 2)
+	s.NoError(err)
+	s.Len(transactions, 2)
+	s.Equal(int64(10), transactions[0].Amount)
+	s.Equal(int64(20), transactions[1].Amount)
+
+	transactions, err = s.repo.ListTransfersReachable(ctx, 2, since, 4)
+	s.NoError(err)
+	s.Len(transactions, 4)
+}
+
 func (s *TransactionRepositoryTestSuite) TestTransaction() {
 	ctx := context.Background()
 
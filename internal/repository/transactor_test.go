@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/smthjapanese/avito-merch/internal/testutils"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type TransactorTestSuite struct {
+	suite.Suite
+	db         *sqlx.DB
+	transactor *Transactor
+}
+
+func (s *TransactorTestSuite) SetupSuite() {
+	db, err := testutils.GetTestDB()
+	require.NoError(s.T(), err)
+
+	s.db = db
+	s.transactor = NewTransactor(db)
+
+	_, err = s.db.Exec(`
+  DROP TABLE IF EXISTS transactor_test;
+  CREATE TABLE transactor_test (id SERIAL PRIMARY KEY)`)
+	require.NoError(s.T(), err)
+}
+
+func (s *TransactorTestSuite) SetupTest() {
+	_, err := s.db.Exec("TRUNCATE TABLE transactor_test RESTART IDENTITY")
+	require.NoError(s.T(), err)
+}
+
+func (s *TransactorTestSuite) TearDownSuite() {
+	_, _ = s.db.Exec("DROP TABLE IF EXISTS transactor_test")
+	s.db.Close()
+}
+
+func (s *TransactorTestSuite) insert(ctx context.Context) error {
+	tx, ok := TxFromContext(ctx)
+	s.Require().True(ok)
+
+	_, err := tx.ExecContext(ctx, "INSERT INTO transactor_test DEFAULT VALUES")
+	return err
+}
+
+func (s *TransactorTestSuite) count() int {
+	var n int
+	s.Require().NoError(s.db.Get(&n, "SELECT COUNT(*) FROM transactor_test"))
+	return n
+}
+
+func (s *TransactorTestSuite) TestCommit() {
+	err := s.transactor.WithinTransaction(context.Background(), s.insert)
+	s.Require().NoError(err)
+	s.Equal(1, s.count())
+}
+
+func (s *TransactorTestSuite) TestRollback() {
+	errFailed := errors.New("failed")
+
+	err := s.transactor.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		s.Require().NoError(s.insert(ctx))
+		return errFailed
+	})
+	s.ErrorIs(err, errFailed)
+	s.Zero(s.count())
+}
+
+func (s *TransactorTestSuite) TestNestedCallJoins() {
+	errFailed := errors.New("failed")
+
+	err := s.transactor.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		outer, _ := TxFromContext(ctx)
+
+		s.Require().NoError(s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			inner, _ := TxFromContext(ctx)
+			s.Same(outer, inner)
+			return s.insert(ctx)
+		}))
+
+		return errFailed
+	})
+	s.ErrorIs(err, errFailed)
+	s.Zero(s.count())
+}
+
+func TestTransactorSuite(t *testing.T) {
+	suite.Run(t, new(TransactorTestSuite))
+}
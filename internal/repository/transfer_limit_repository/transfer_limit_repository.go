@@ -0,0 +1,121 @@
+package transfer_limit_repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/repository"
+)
+
+type dbConn interface {
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+type TransferLimitRepository struct {
+	db dbConn
+}
+
+func NewTransferLimitRepository(db *sqlx.DB) *TransferLimitRepository {
+	return &TransferLimitRepository{
+		db: db,
+	}
+}
+
+func (r *TransferLimitRepository) WithTx(tx *sqlx.Tx) *TransferLimitRepository {
+	return &TransferLimitRepository{
+		db: tx,
+	}
+}
+
+// conn returns the transaction ctx carries, or the repository's own
+// connection outside of one.
+func (r *TransferLimitRepository) conn(ctx context.Context) dbConn {
+	if tx, ok := repository.TxFromContext(ctx); ok {
+		return tx
+	}
+	return r.db
+}
+
+func (r *TransferLimitRepository) GetOverride(ctx context.Context, userID int64) (entity.TransferLimitOverride, error) {
+	var override entity.TransferLimitOverride
+	query := `
+  SELECT user_id, per_transfer, daily, per_recipient, updated_at
+  FROM transfer_limit_overrides
+  WHERE user_id = $1`
+
+	err := r.conn(ctx).GetContext(ctx, &override, query, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.TransferLimitOverride{}, entity.ErrTransferLimitNotFound
+		}
+		return entity.TransferLimitOverride{}, fmt.Errorf("failed to get transfer limit override: %w", err)
+	}
+
+	return override, nil
+}
+
+func (r *TransferLimitRepository) ListOverrides(ctx context.Context) ([]entity.TransferLimitOverride, error) {
+	query := `
+  SELECT user_id, per_transfer, daily, per_recipient, updated_at
+  FROM transfer_limit_overrides
+  ORDER BY user_id`
+
+	var overrides []entity.TransferLimitOverride
+	err := r.conn(ctx).SelectContext(ctx, &overrides, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transfer limit overrides: %w", err)
+	}
+
+	return overrides, nil
+}
+
+// SaveOverride creates the user's override or replaces the existing one.
+func (r *TransferLimitRepository) SaveOverride(ctx context.Context, override entity.TransferLimitOverride) error {
+	query := `
+  INSERT INTO transfer_limit_overrides (user_id, per_transfer, daily, per_recipient, updated_at)
+  VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP)
+  ON CONFLICT (user_id) DO UPDATE
+  SET per_transfer = EXCLUDED.per_transfer,
+   daily = EXCLUDED.daily,
+   per_recipient = EXCLUDED.per_recipient,
+   updated_at = EXCLUDED.updated_at`
+
+	_, err := r.conn(ctx).ExecContext(
+		ctx,
+		query,
+		override.UserID,
+		override.PerTransfer,
+		override.Daily,
+		override.PerRecipient,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save transfer limit override: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TransferLimitRepository) DeleteOverride(ctx context.Context, userID int64) error {
+	query := `DELETE FROM transfer_limit_overrides WHERE user_id = $1`
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete transfer limit override: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return entity.ErrTransferLimitNotFound
+	}
+
+	return nil
+}
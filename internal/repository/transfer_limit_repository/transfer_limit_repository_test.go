@@ -0,0 +1,105 @@
+package transfer_limit_repository
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/testutils"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type TransferLimitRepositoryTestSuite struct {
+	suite.Suite
+	db   *sqlx.DB
+	repo *TransferLimitRepository
+}
+
+func (s *TransferLimitRepositoryTestSuite) SetupSuite() {
+	db, err := testutils.GetTestDB()
+	require.NoError(s.T(), err)
+
+	s.db = db
+	s.repo = NewTransferLimitRepository(db)
+
+	s.recreateTables()
+}
+
+func (s *TransferLimitRepositoryTestSuite) SetupTest() {
+	_, err := s.db.Exec("TRUNCATE TABLE transfer_limit_overrides, users RESTART IDENTITY CASCADE")
+	require.NoError(s.T(), err)
+
+	_, err = s.db.Exec(`
+  INSERT INTO users (username, password_hash, coins)
+  VALUES
+  ('user1', 'hash1', 1000),
+  ('user2', 'hash2', 1000)`)
+	require.NoError(s.T(), err)
+}
+
+func (s *TransferLimitRepositoryTestSuite) TearDownSuite() {
+	s.db.Close()
+}
+
+func (s *TransferLimitRepositoryTestSuite) recreateTables() {
+	_, err := s.db.Exec(`
+  DROP TABLE IF EXISTS transfer_limit_overrides;
+  DROP TABLE IF EXISTS users CASCADE;
+
+  CREATE TABLE users (
+   id SERIAL PRIMARY KEY,
+   username VARCHAR(255) UNIQUE NOT NULL,
+   password_hash VARCHAR(255) NOT NULL,
+   coins INTEGER NOT NULL DEFAULT 1000,
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+
+  CREATE TABLE transfer_limit_overrides (
+   user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+   per_transfer INTEGER CHECK (per_transfer >= 0),
+   daily INTEGER CHECK (daily >= 0),
+   per_recipient INTEGER CHECK (per_recipient >= 0),
+   updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+ `)
+	require.NoError(s.T(), err)
+}
+
+func (s *TransferLimitRepositoryTestSuite) TestOverride() {
+	ctx := context.Background()
+
+	s.Run("missing override", func() {
+		_, err := s.repo.GetOverride(ctx, 1)
+		s.ErrorIs(err, entity.ErrTransferLimitNotFound)
+	})
+
+	s.Run("save and replace", func() {
+		daily := int64(500)
+		err := s.repo.SaveOverride(ctx, entity.TransferLimitOverride{UserID: 1, Daily: &daily})
+		s.NoError(err)
+
+		perTransfer := int64(100)
+		err = s.repo.SaveOverride(ctx, entity.TransferLimitOverride{UserID: 1, PerTransfer: &perTransfer})
+		s.NoError(err)
+
+		override, err := s.repo.GetOverride(ctx, 1)
+		s.NoError(err)
+		s.Nil(override.Daily)
+		s.Equal(int64(100), *override.PerTransfer)
+
+		overrides, err := s.repo.ListOverrides(ctx)
+		s.NoError(err)
+		s.Len(overrides, 1)
+	})
+
+	s.Run("delete", func() {
+		s.NoError(s.repo.DeleteOverride(ctx, 1))
+		s.ErrorIs(s.repo.DeleteOverride(ctx, 1), entity.ErrTransferLimitNotFound)
+	})
+}
+
+func TestTransferLimitRepository(t *testing.T) {
+	suite.Run(t, new(TransferLimitRepositoryTestSuite))
+}
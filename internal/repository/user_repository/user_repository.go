@@ -8,9 +8,11 @@ import (
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
 	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/repository"
 )
 
 type dbConn interface {
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
 	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
 	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
 	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
@@ -32,18 +34,36 @@ func (r *UserRepository) WithTx(tx *sqlx.Tx) *UserRepository {
 	}
 }
 
+// conn returns the transaction ctx carries, or the repository's own
+// connection outside of one.
+func (r *UserRepository) conn(ctx context.Context) dbConn {
+	if tx, ok := repository.TxFromContext(ctx); ok {
+		return tx
+	}
+	return r.db
+}
+
 func (r *UserRepository) Create(ctx context.Context, user *entity.User) error {
+	if user.Role == "" {
+		user.Role = entity.UserRoleUser
+	}
+
 	query := `
-  INSERT INTO users (username, password_hash, coins, created_at)
-  VALUES ($1, $2, $3, $4)
+  INSERT INTO users (username, password_hash, coins, held_coins, role, team_id, manager_id, public_kudos, created_at)
+  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
   RETURNING id`
 
-	err := r.db.QueryRowContext(
+	err := r.conn(ctx).QueryRowContext(
 		ctx,
 		query,
 		user.Username,
 		user.PasswordHash,
 		user.Coins,
+		user.HeldCoins,
+		user.Role,
+		user.TeamID,
+		user.ManagerID,
+		user.PublicKudos,
 		user.CreatedAt,
 	).Scan(&user.ID)
 
@@ -57,14 +77,18 @@ func (r *UserRepository) Create(ctx context.Context, user *entity.User) error {
 	return nil
 }
 
+// GetByID locks the user row until the surrounding transaction ends, so a
+// balance read here and written back with Update cannot lose a change made
+// by a concurrent transaction in between.
 func (r *UserRepository) GetByID(ctx context.Context, id int64) (*entity.User, error) {
 	var user entity.User
 	query := `
-  SELECT id, username, password_hash, coins, created_at
+  SELECT id, username, password_hash, coins, held_coins, role, team_id, manager_id, public_kudos, created_at
   FROM users
-  WHERE id = $1`
+  WHERE id = $1
+  FOR UPDATE`
 
-	err := r.db.GetContext(ctx, &user, query, id)
+	err := r.conn(ctx).GetContext(ctx, &user, query, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, entity.ErrUserNotFound
@@ -75,14 +99,16 @@ func (r *UserRepository) GetByID(ctx context.Context, id int64) (*entity.User, e
 	return &user, nil
 }
 
+// GetByUsername locks the user row like GetByID.
 func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*entity.User, error) {
 	var user entity.User
 	query := `
-  SELECT id, username, password_hash, coins, created_at
+  SELECT id, username, password_hash, coins, held_coins, role, team_id, manager_id, public_kudos, created_at
   FROM users
-  WHERE username = $1`
+  WHERE username = $1
+  FOR UPDATE`
 
-	err := r.db.GetContext(ctx, &user, query, username)
+	err := r.conn(ctx).GetContext(ctx, &user, query, username)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, entity.ErrUserNotFound
@@ -98,15 +124,25 @@ func (r *UserRepository) Update(ctx context.Context, user *entity.User) error {
   UPDATE users
   SET username = $1,
    password_hash = $2,
-   coins = $3
-  WHERE id = $4`
-
-	result, err := r.db.ExecContext(
+   coins = $3,
+   held_coins = $4,
+   role = $5,
+   team_id = $6,
+   manager_id = $7,
+   public_kudos = $8
+  WHERE id = $9`
+
+	result, err := r.conn(ctx).ExecContext(
 		ctx,
 		query,
 		user.Username,
 		user.PasswordHash,
 		user.Coins,
+		user.HeldCoins,
+		user.Role,
+		user.TeamID,
+		user.ManagerID,
+		user.PublicKudos,
 		user.ID,
 	)
 	if err != nil {
@@ -128,6 +164,80 @@ func (r *UserRepository) Update(ctx context.Context, user *entity.User) error {
 	return nil
 }
 
+// SetPublicKudos changes only the user's kudos visibility, so it cannot
+// overwrite a balance changed at the same time.
+func (r *UserRepository) SetPublicKudos(ctx context.Context, id int64, public bool) error {
+	query := `
+  UPDATE users
+  SET public_kudos = $1
+  WHERE id = $2`
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, public, id)
+	if err != nil {
+		return fmt.Errorf("failed to update public kudos: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return entity.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// List returns every regular user, leaving out the system account.
+func (r *UserRepository) List(ctx context.Context) ([]entity.User, error) {
+	query := `
+  SELECT id, username, password_hash, coins, held_coins, role, team_id, manager_id, public_kudos, created_at
+  FROM users
+  WHERE role <> $1
+  ORDER BY id`
+
+	var users []entity.User
+	err := r.conn(ctx).SelectContext(ctx, &users, query, entity.UserRoleSystem)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	return users, nil
+}
+
+func (r *UserRepository) ListByTeam(ctx context.Context, teamID int64) ([]entity.User, error) {
+	query := `
+  SELECT id, username, password_hash, coins, held_coins, role, team_id, manager_id, public_kudos, created_at
+  FROM users
+  WHERE team_id = $1 AND role <> $2
+  ORDER BY id`
+
+	var users []entity.User
+	err := r.conn(ctx).SelectContext(ctx, &users, query, teamID, entity.UserRoleSystem)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users by team: %w", err)
+	}
+
+	return users, nil
+}
+
+func (r *UserRepository) ListByRole(ctx context.Context, role entity.UserRole) ([]entity.User, error) {
+	query := `
+  SELECT id, username, password_hash, coins, held_coins, role, team_id, manager_id, public_kudos, created_at
+  FROM users
+  WHERE role = $1
+  ORDER BY id`
+
+	var users []entity.User
+	err := r.conn(ctx).SelectContext(ctx, &users, query, role)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users by role: %w", err)
+	}
+
+	return users, nil
+}
+
 func isUniqueViolation(err error) bool {
 	var pqErr *pq.Error
 	if errors.As(err, &pqErr) {
@@ -56,6 +56,11 @@ func (s *UserRepositoryTestSuite) recreateTable() {
    username VARCHAR(255) UNIQUE NOT NULL,
    password_hash VARCHAR(255) NOT NULL,
    coins INTEGER NOT NULL DEFAULT 1000,
+   held_coins INTEGER NOT NULL DEFAULT 0,
+   role VARCHAR(50) NOT NULL DEFAULT 'user',
+   team_id INTEGER,
+   manager_id INTEGER,
+   public_kudos BOOLEAN NOT NULL DEFAULT FALSE,
    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
   )
  `)
@@ -118,6 +123,74 @@ func (s *UserRepositoryTestSuite) TestGetByID() {
 	})
 }
 
+func (s *UserRepositoryTestSuite) TestSetPublicKudos() {
+	ctx := context.Background()
+
+	user := &entity.User{
+		Username:     "testuser",
+		PasswordHash: "hashed_password",
+		Coins:        1000,
+		CreatedAt:    time.Now().UTC(),
+	}
+	err := s.repo.Create(ctx, user)
+	s.NoError(err)
+
+	s.Run("leaves the balance alone", func() {
+		_, err := s.db.Exec(`UPDATE users SET coins = 700 WHERE id = $1`, user.ID)
+		s.NoError(err)
+
+		s.NoError(s.repo.SetPublicKudos(ctx, user.ID, true))
+
+		found, err := s.repo.GetByID(ctx, user.ID)
+		s.NoError(err)
+		s.True(found.PublicKudos)
+		s.Equal(int64(700), found.Coins)
+	})
+
+	s.Run("non-existing user", func() {
+		s.ErrorIs(s.repo.SetPublicKudos(ctx, 999999, true), entity.ErrUserNotFound)
+	})
+}
+
+func (s *UserRepositoryTestSuite) TestList() {
+	ctx := context.Background()
+
+	teamID := int64(7)
+	users := []*entity.User{
+		{Username: "system", Role: entity.UserRoleSystem, CreatedAt: time.Now().UTC()},
+		{Username: "alice", TeamID: &teamID, CreatedAt: time.Now().UTC()},
+		{Username: "bob", CreatedAt: time.Now().UTC()},
+	}
+	for _, u := range users {
+		u.PasswordHash = "hashed_password"
+		u.Coins = 1000
+		s.NoError(s.repo.Create(ctx, u))
+	}
+
+	s.Run("system account is excluded", func() {
+		found, err := s.repo.List(ctx)
+		s.NoError(err)
+		s.Len(found, 2)
+		s.Equal("alice", found[0].Username)
+		s.Equal(entity.UserRoleUser, found[0].Role)
+	})
+
+	s.Run("by team", func() {
+		found, err := s.repo.ListByTeam(ctx, teamID)
+		s.NoError(err)
+		s.Len(found, 1)
+		s.Equal("alice", found[0].Username)
+		s.Equal(teamID, *found[0].TeamID)
+	})
+
+	s.Run("by role", func() {
+		found, err := s.repo.ListByRole(ctx, entity.UserRoleSystem)
+		s.NoError(err)
+		s.Len(found, 1)
+		s.Equal("system", found[0].Username)
+	})
+}
+
 func (s *UserRepositoryTestSuite) TestTransaction() {
 	ctx := context.Background()
 
@@ -170,6 +243,32 @@ func (s *UserRepositoryTestSuite) TestTransaction() {
 	})
 }
 
+func (s *UserRepositoryTestSuite) TestGetByIDLocksRow() {
+	ctx := context.Background()
+
+	user := &entity.User{
+		Username:     "lockeduser",
+		PasswordHash: "hashed_password",
+		Coins:        1000,
+		CreatedAt:    time.Now().UTC(),
+	}
+	s.Require().NoError(s.repo.Create(ctx, user))
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	s.Require().NoError(err)
+
+	_, err = s.repo.WithTx(tx).GetByID(ctx, user.ID)
+	s.Require().NoError(err)
+
+	_, err = s.db.ExecContext(ctx, "SELECT id FROM users WHERE id = $1 FOR UPDATE NOWAIT", user.ID)
+	s.Error(err)
+
+	s.Require().NoError(tx.Rollback())
+
+	_, err = s.db.ExecContext(ctx, "SELECT id FROM users WHERE id = $1 FOR UPDATE NOWAIT", user.ID)
+	s.NoError(err)
+}
+
 func TestUserRepository(t *testing.T) {
 	suite.Run(t, new(UserRepositoryTestSuite))
 }
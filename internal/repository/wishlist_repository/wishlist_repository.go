@@ -0,0 +1,172 @@
+package wishlist_repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/repository"
+)
+
+const selectWishlist = `
+  SELECT w.id, w.user_id, w.item_id, w.variant_id, w.last_price, w.in_stock, w.affordable, w.created_at,
+   m.name AS item_name
+  FROM wishlist_items w
+  JOIN merch_items m ON m.id = w.item_id`
+
+type dbConn interface {
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type WishlistRepository struct {
+	db dbConn
+}
+
+func NewWishlistRepository(db *sqlx.DB) *WishlistRepository {
+	return &WishlistRepository{
+		db: db,
+	}
+}
+
+func (r *WishlistRepository) WithTx(tx *sqlx.Tx) *WishlistRepository {
+	return &WishlistRepository{
+		db: tx,
+	}
+}
+
+// conn returns the transaction ctx carries, or the repository's own
+// connection outside of one.
+func (r *WishlistRepository) conn(ctx context.Context) dbConn {
+	if tx, ok := repository.TxFromContext(ctx); ok {
+		return tx
+	}
+	return r.db
+}
+
+func (r *WishlistRepository) Create(ctx context.Context, item *entity.WishlistItem) error {
+	query := `
+  INSERT INTO wishlist_items (user_id, item_id, variant_id, last_price, in_stock, affordable)
+  VALUES ($1, $2, $3, $4, $5, $6)
+  RETURNING id, created_at`
+
+	err := r.conn(ctx).QueryRowContext(
+		ctx,
+		query,
+		item.UserID,
+		item.ItemID,
+		item.VariantID,
+		item.Price,
+		item.InStock,
+		item.Affordable,
+	).Scan(&item.ID, &item.CreatedAt)
+
+	if err != nil {
+		if isUniqueViolation(err) {
+			return entity.ErrAlreadyWishlisted
+		}
+		return fmt.Errorf("failed to create wishlist item: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID locks the entry for the rest of the transaction so concurrent
+// checks do not report the same change twice.
+func (r *WishlistRepository) GetByID(ctx context.Context, id int64) (entity.WishlistItem, error) {
+	var item entity.WishlistItem
+	query := selectWishlist + `
+  WHERE w.id = $1
+  FOR UPDATE OF w`
+
+	err := r.conn(ctx).GetContext(ctx, &item, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.WishlistItem{}, entity.ErrWishlistItemNotFound
+		}
+		return entity.WishlistItem{}, fmt.Errorf("failed to get wishlist item by id: %w", err)
+	}
+
+	return item, nil
+}
+
+func (r *WishlistRepository) ListByUser(ctx context.Context, userID int64) ([]entity.WishlistItem, error) {
+	var items []entity.WishlistItem
+	query := selectWishlist + `
+  WHERE w.user_id = $1
+  ORDER BY w.id`
+
+	err := r.conn(ctx).SelectContext(ctx, &items, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list wishlist: %w", err)
+	}
+
+	return items, nil
+}
+
+// ListIDs returns up to limit entry IDs greater than afterID in order, so
+// the whole wishlist table can be walked in pages.
+func (r *WishlistRepository) ListIDs(ctx context.Context, afterID int64, limit int) ([]int64, error) {
+	var ids []int64
+	query := `
+  SELECT id
+  FROM wishlist_items
+  WHERE id > $1
+  ORDER BY id
+  LIMIT $2`
+
+	err := r.conn(ctx).SelectContext(ctx, &ids, query, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list wishlist items: %w", err)
+	}
+
+	return ids, nil
+}
+
+// UpdateState stores the state the entry was last seen in.
+func (r *WishlistRepository) UpdateState(ctx context.Context, id int64, state entity.WishlistState) error {
+	query := `
+  UPDATE wishlist_items
+  SET last_price = $1, in_stock = $2, affordable = $3
+  WHERE id = $4`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, state.Price, state.InStock, state.Affordable, id)
+	if err != nil {
+		return fmt.Errorf("failed to update wishlist item: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes the user's entry. It reports false when the user has no
+// such entry.
+func (r *WishlistRepository) Delete(ctx context.Context, userID, id int64) (bool, error) {
+	query := `
+  DELETE FROM wishlist_items
+  WHERE id = $1 AND user_id = $2`
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, id, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete wishlist item: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == "23505"
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+package wishlist_repository
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/testutils"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type WishlistRepositoryTestSuite struct {
+	suite.Suite
+	db   *sqlx.DB
+	repo *WishlistRepository
+}
+
+func (s *WishlistRepositoryTestSuite) SetupSuite() {
+	db, err := testutils.GetTestDB()
+	require.NoError(s.T(), err)
+
+	s.db = db
+	s.repo = NewWishlistRepository(db)
+
+	s.recreateTables()
+}
+
+func (s *WishlistRepositoryTestSuite) SetupTest() {
+	_, err := s.db.Exec("TRUNCATE TABLE wishlist_items, merch_variants, merch_items, users RESTART IDENTITY CASCADE")
+	require.NoError(s.T(), err)
+
+	_, err = s.db.Exec(`
+  INSERT INTO users (username, password_hash, coins) VALUES ('user1', 'hash1', 1000);
+  INSERT INTO merch_items (name, price) VALUES ('hoody', 300), ('pen', 10);
+  INSERT INTO merch_variants (item_id, size, stock) VALUES (1, 'L', 0)`)
+	require.NoError(s.T(), err)
+}
+
+func (s *WishlistRepositoryTestSuite) TearDownSuite() {
+	s.db.Close()
+}
+
+func (s *WishlistRepositoryTestSuite) recreateTables() {
+	_, err := s.db.Exec(`
+  DROP TABLE IF EXISTS wishlist_items;
+  DROP TABLE IF EXISTS merch_variants CASCADE;
+  DROP TABLE IF EXISTS merch_items CASCADE;
+  DROP TABLE IF EXISTS users CASCADE;
+
+  CREATE TABLE users (
+   id SERIAL PRIMARY KEY,
+   username VARCHAR(255) UNIQUE NOT NULL,
+   password_hash VARCHAR(255) NOT NULL,
+   coins INTEGER NOT NULL DEFAULT 1000,
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+
+  CREATE TABLE merch_items (
+   id SERIAL PRIMARY KEY,
+   name VARCHAR(255) UNIQUE NOT NULL,
+   price INTEGER NOT NULL CHECK (price > 0),
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+
+  CREATE TABLE merch_variants (
+   id SERIAL PRIMARY KEY,
+   item_id INTEGER NOT NULL REFERENCES merch_items(id) ON DELETE CASCADE,
+   size VARCHAR(20) NOT NULL DEFAULT '',
+   color VARCHAR(50) NOT NULL DEFAULT '',
+   stock INTEGER NOT NULL DEFAULT 0,
+   price_delta INTEGER NOT NULL DEFAULT 0,
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+
+  CREATE TABLE wishlist_items (
+   id SERIAL PRIMARY KEY,
+   user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+   item_id INTEGER NOT NULL REFERENCES merch_items(id) ON DELETE CASCADE,
+   variant_id INTEGER REFERENCES merch_variants(id) ON DELETE CASCADE,
+   last_price INTEGER NOT NULL,
+   in_stock BOOLEAN NOT NULL,
+   affordable BOOLEAN NOT NULL,
+   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+  );
+
+  CREATE UNIQUE INDEX idx_wishlist_items_unique ON wishlist_items(user_id, item_id, COALESCE(variant_id, 0));
+ `)
+	require.NoError(s.T(), err)
+}
+
+func (s *WishlistRepositoryTestSuite) TestLifecycle() {
+	ctx := context.Background()
+	variantID := int64(1)
+
+	hoody := entity.WishlistItem{UserID: 1, ItemID: 1, VariantID: &variantID, WishlistState: entity.WishlistState{Price: 300, Affordable: true}}
+	pen := entity.WishlistItem{UserID: 1, ItemID: 2, WishlistState: entity.WishlistState{Price: 10, InStock: true, Affordable: true}}
+	s.NoError(s.repo.Create(ctx, &hoody))
+	s.NoError(s.repo.Create(ctx, &pen))
+
+	dup := entity.WishlistItem{UserID: 1, ItemID: 2, WishlistState: entity.WishlistState{Price: 10}}
+	s.ErrorIs(s.repo.Create(ctx, &dup), entity.ErrAlreadyWishlisted)
+
+	s.NoError(s.repo.UpdateState(ctx, hoody.ID, entity.WishlistState{Price: 250, InStock: true, Affordable: true}))
+
+	item, err := s.repo.GetByID(ctx, hoody.ID)
+	s.NoError(err)
+	s.Equal("hoody", item.ItemName)
+	s.Equal(int64(250), item.Price)
+	s.True(item.InStock)
+
+	ids, err := s.repo.ListIDs(ctx, hoody.ID, 10)
+	s.NoError(err)
+	s.Equal([]int64{pen.ID}, ids)
+
+	deleted, err := s.repo.Delete(ctx, 1, pen.ID)
+	s.NoError(err)
+	s.True(deleted)
+
+	deleted, err = s.repo.Delete(ctx, 1, pen.ID)
+	s.NoError(err)
+	s.False(deleted)
+
+	items, err := s.repo.ListByUser(ctx, 1)
+	s.NoError(err)
+	s.Len(items, 1)
+
+	_, err = s.repo.GetByID(ctx, 100)
+	s.ErrorIs(err, entity.ErrWishlistItemNotFound)
+}
+
+func TestWishlistRepository(t *testing.T) {
+	suite.Run(t, new(WishlistRepositoryTestSuite))
+}
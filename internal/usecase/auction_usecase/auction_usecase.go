@@ -0,0 +1,332 @@
+package auction_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"time"
+)
+
+const _closeBatchSize = 100
+
+// AuctionUC runs timed auctions for limited-edition merch. Every bid and
+// every close locks the auction row first, so they are applied one at a
+// time, and all of the state, holds included, lives in the database: an
+// auction that ends while the service is down is closed by the next run of
+// CloseDue.
+type AuctionUC struct {
+	auctionRepo AuctionRepository
+	userRepo    UserRepository
+	merchRepo   MerchRepository
+	invRepo     InventoryRepository
+	txRepo      TransactionRepository
+	lotRepo     LotRepository
+	orderRepo   OrderRepository
+	notifier    Notifier
+	dbTx        DBTransactor
+}
+
+func NewAuctionUC(
+	auctionRepo AuctionRepository,
+	userRepo UserRepository,
+	merchRepo MerchRepository,
+	invRepo InventoryRepository,
+	txRepo TransactionRepository,
+	lotRepo LotRepository,
+	orderRepo OrderRepository,
+	notifier Notifier,
+	dbTx DBTransactor,
+) *AuctionUC {
+	return &AuctionUC{
+		auctionRepo: auctionRepo,
+		userRepo:    userRepo,
+		merchRepo:   merchRepo,
+		invRepo:     invRepo,
+		txRepo:      txRepo,
+		lotRepo:     lotRepo,
+		orderRepo:   orderRepo,
+		notifier:    notifier,
+		dbTx:        dbTx,
+	}
+}
+
+func (uc *AuctionUC) CreateAuction(ctx context.Context, adminID int64, req CreateAuctionRequest) (entity.Auction, error) {
+	if err := uc.requireAdmin(ctx, adminID); err != nil {
+		return entity.Auction{}, err
+	}
+
+	item, err := uc.merchRepo.GetByName(ctx, req.ItemName)
+	if err != nil {
+		return entity.Auction{}, entity.ErrMerchNotFound
+	}
+
+	now := time.Now()
+	auction := entity.Auction{
+		ItemID:       item.ID,
+		StartPrice:   req.StartPrice,
+		MinIncrement: req.MinIncrement,
+		StartsAt:     req.StartsAt,
+		EndsAt:       req.EndsAt,
+		Status:       entity.AuctionStatusOpen,
+		CreatedBy:    adminID,
+	}
+	if auction.StartsAt.IsZero() {
+		auction.StartsAt = now
+	}
+	if auction.MinIncrement == 0 {
+		auction.MinIncrement = 1
+	}
+	if err := auction.Validate(); err != nil {
+		return entity.Auction{}, err
+	}
+	if !auction.EndsAt.After(now) {
+		return entity.Auction{}, entity.ErrInvalidAuctionWindow
+	}
+
+	if err := uc.auctionRepo.Create(ctx, &auction); err != nil {
+		return entity.Auction{}, err
+	}
+
+	auction.ItemName = item.Name
+	return auction, nil
+}
+
+// PlaceBid makes amount the leading bid. The amount is held on the bidder's
+// account and the hold of the bidder they outbid is released; a bidder
+// raising their own bid only has the difference held.
+func (uc *AuctionUC) PlaceBid(ctx context.Context, userID, auctionID, amount int64) (entity.Auction, error) {
+	var (
+		result   entity.Auction
+		outbidID *int64
+	)
+
+	err := uc.dbTx.WithinTransaction(ctx, func(ctx context.Context) error {
+		auction, err := uc.auctionRepo.GetByID(ctx, auctionID)
+		if err != nil {
+			return err
+		}
+
+		prevBidderID, prevBid := auction.HighBidderID, auction.HighBid
+		if err := auction.Bid(userID, amount, time.Now()); err != nil {
+			return err
+		}
+
+		bidder, err := uc.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if bidder == nil {
+			return entity.ErrUserNotFound
+		}
+
+		raising := prevBidderID != nil && *prevBidderID == userID
+		if raising {
+			bidder.Release(prevBid)
+		}
+		if err := bidder.Hold(amount); err != nil {
+			return err
+		}
+		if err := uc.userRepo.Update(ctx, bidder); err != nil {
+			return err
+		}
+
+		if prevBidderID != nil && !raising {
+			prev, err := uc.userRepo.GetByID(ctx, *prevBidderID)
+			if err != nil {
+				return err
+			}
+			if prev == nil {
+				return entity.ErrUserNotFound
+			}
+
+			prev.Release(prevBid)
+			if err := uc.userRepo.Update(ctx, prev); err != nil {
+				return err
+			}
+			outbidID = prevBidderID
+		}
+
+		if err := uc.auctionRepo.Update(ctx, auction); err != nil {
+			return err
+		}
+		if err := uc.auctionRepo.AddBid(ctx, &entity.AuctionBid{
+			AuctionID: auction.ID,
+			UserID:    userID,
+			Amount:    amount,
+		}); err != nil {
+			return err
+		}
+
+		result = auction
+		return nil
+	})
+	if err != nil {
+		return entity.Auction{}, err
+	}
+
+	if outbidID != nil {
+		uc.notify(ctx, *outbidID, fmt.Sprintf("You were outbid on %s: the leading bid is now %d coins", result.ItemName, result.HighBid))
+	}
+
+	return result, nil
+}
+
+// CloseDue closes every auction that has ended: the winner's hold is
+// captured and the item goes to their inventory with an order to hand it
+// out. Each auction is closed in its own transaction. It is meant to be run
+// by the scheduler.
+func (uc *AuctionUC) CloseDue(ctx context.Context, now time.Time) (int, error) {
+	ids, err := uc.auctionRepo.ListDue(ctx, now, _closeBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	closed := 0
+	var errs []error
+
+	for _, id := range ids {
+		var won *entity.Auction
+
+		err := uc.dbTx.WithinTransaction(ctx, func(ctx context.Context) error {
+			auction, err := uc.auctionRepo.GetByID(ctx, id)
+			if err != nil {
+				return err
+			}
+			// Closed or cancelled since it was listed.
+			if !auction.IsDue(now) {
+				return nil
+			}
+
+			if auction.HighBidderID != nil {
+				if err := uc.award(ctx, auction); err != nil {
+					return err
+				}
+				won = &auction
+			}
+
+			auction.Status = entity.AuctionStatusClosed
+			auction.ClosedAt = &now
+			if err := uc.auctionRepo.Update(ctx, auction); err != nil {
+				return err
+			}
+
+			closed++
+			return nil
+		})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if won != nil {
+			uc.notify(ctx, *won.HighBidderID, fmt.Sprintf("You won the auction for %s with %d coins", won.ItemName, won.HighBid))
+		}
+	}
+
+	return closed, errors.Join(errs...)
+}
+
+// award captures the winning bid and gives the item to the winner.
+func (uc *AuctionUC) award(ctx context.Context, auction entity.Auction) error {
+	winner, err := uc.userRepo.GetByID(ctx, *auction.HighBidderID)
+	if err != nil {
+		return err
+	}
+	if winner == nil {
+		return entity.ErrUserNotFound
+	}
+
+	winner.Capture(auction.HighBid)
+	if err := uc.userRepo.Update(ctx, winner); err != nil {
+		return err
+	}
+	paid, err := uc.lotRepo.Spend(ctx, winner.ID, auction.HighBid)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.invRepo.Add(ctx, winner.ID, auction.ItemID, nil, 1); err != nil {
+		return err
+	}
+
+	if err := uc.txRepo.Create(ctx, &entity.Transaction{
+		FromUserID: winner.ID,
+		ToUserID:   winner.ID,
+		Amount:     auction.HighBid,
+		Type:       entity.TransactionTypePurchase,
+		ItemID:     &auction.ItemID,
+	}); err != nil {
+		return err
+	}
+
+	return uc.orderRepo.Create(ctx, &entity.Order{
+		UserID: winner.ID,
+		ItemID: auction.ItemID,
+		Price:  auction.HighBid,
+		Status: entity.OrderStatusPlaced,
+		Lots:   paid,
+	})
+}
+
+// CancelAuction calls off an open auction and releases the leading bid.
+func (uc *AuctionUC) CancelAuction(ctx context.Context, adminID, auctionID int64) error {
+	if err := uc.requireAdmin(ctx, adminID); err != nil {
+		return err
+	}
+
+	return uc.dbTx.WithinTransaction(ctx, func(ctx context.Context) error {
+		auction, err := uc.auctionRepo.GetByID(ctx, auctionID)
+		if err != nil {
+			return err
+		}
+		if auction.Status != entity.AuctionStatusOpen {
+			return entity.ErrAuctionNotOpen
+		}
+
+		if auction.HighBidderID != nil {
+			bidder, err := uc.userRepo.GetByID(ctx, *auction.HighBidderID)
+			if err != nil {
+				return err
+			}
+			if bidder == nil {
+				return entity.ErrUserNotFound
+			}
+
+			bidder.Release(auction.HighBid)
+			if err := uc.userRepo.Update(ctx, bidder); err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		auction.Status = entity.AuctionStatusCancelled
+		auction.ClosedAt = &now
+		return uc.auctionRepo.Update(ctx, auction)
+	})
+}
+
+func (uc *AuctionUC) ListAuctions(ctx context.Context) ([]entity.Auction, error) {
+	return uc.auctionRepo.ListOpen(ctx)
+}
+
+func (uc *AuctionUC) ListBids(ctx context.Context, auctionID int64) ([]entity.AuctionBid, error) {
+	return uc.auctionRepo.ListBids(ctx, auctionID)
+}
+
+// notify is best-effort: the bid or the close has already been committed.
+func (uc *AuctionUC) notify(ctx context.Context, userID int64, message string) {
+	_ = uc.notifier.Notify(ctx, userID, message)
+}
+
+func (uc *AuctionUC) requireAdmin(ctx context.Context, adminID int64) error {
+	admin, err := uc.userRepo.GetByID(ctx, adminID)
+	if err != nil {
+		return err
+	}
+	if admin == nil || !admin.IsAdmin() {
+		return entity.ErrForbidden
+	}
+
+	return nil
+}
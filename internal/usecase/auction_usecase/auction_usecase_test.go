@@ -0,0 +1,352 @@
+package auction_usecase
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/usecase/auction_usecase/mocks"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type test struct {
+	name string
+	mock func()
+	res  interface{}
+	err  error
+}
+
+const adminID = int64(9)
+
+func openAuction() entity.Auction {
+	now := time.Now()
+	return entity.Auction{
+		ID:           1,
+		ItemID:       3,
+		ItemName:     "golden-hoody",
+		StartPrice:   100,
+		MinIncrement: 10,
+		StartsAt:     now.Add(-time.Hour),
+		EndsAt:       now.Add(time.Hour),
+		Status:       entity.AuctionStatusOpen,
+	}
+}
+
+func TestPlaceBid(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	auctionRepo := mocks.NewMockAuctionRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	notifier := mocks.NewMockNotifier(ctrl)
+	dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+	uc := NewAuctionUC(
+		auctionRepo,
+		userRepo,
+		mocks.NewMockMerchRepository(ctrl),
+		mocks.NewMockInventoryRepository(ctrl),
+		mocks.NewMockTransactionRepository(ctrl),
+		mocks.NewMockLotRepository(ctrl),
+		mocks.NewMockOrderRepository(ctrl),
+		notifier,
+		dbTransactor,
+	)
+
+	type bid struct {
+		amount  int64
+		bidder  *entity.User
+		prev    *entity.User
+		coins   int64
+		held    int64
+		prevEnd int64
+	}
+
+	ended := openAuction()
+	ended.EndsAt = time.Now().Add(-time.Minute)
+
+	outbid := bid{amount: 130, bidder: &entity.User{ID: 1, Coins: 200}, prev: &entity.User{ID: 2, Coins: 30, HeldCoins: 120}, coins: 70, held: 130, prevEnd: 150}
+	raise := bid{amount: 140, bidder: &entity.User{ID: 1, Coins: 20, HeldCoins: 120}, coins: 0, held: 140}
+
+	tests := []test{
+		{
+			name: "outbids and releases the previous hold",
+			mock: func() {
+				leader := int64(2)
+				auction := openAuction()
+				auction.HighBidderID = &leader
+				auction.HighBid = 120
+
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				auctionRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(auction, nil)
+				userRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(outbid.bidder, nil)
+				userRepo.EXPECT().Update(gomock.Any(), outbid.bidder).Return(nil)
+				userRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(outbid.prev, nil)
+				userRepo.EXPECT().Update(gomock.Any(), outbid.prev).Return(nil)
+				auctionRepo.EXPECT().
+					Update(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, a entity.Auction) error {
+						require.Equal(t, int64(1), *a.HighBidderID)
+						require.Equal(t, int64(130), a.HighBid)
+						return nil
+					})
+				auctionRepo.EXPECT().AddBid(gomock.Any(), gomock.Any()).Return(nil)
+				notifier.EXPECT().Notify(gomock.Any(), int64(2), "You were outbid on golden-hoody: the leading bid is now 130 coins").Return(nil)
+			},
+			res: outbid,
+		},
+		{
+			name: "raising your own bid holds the difference",
+			mock: func() {
+				leader := int64(1)
+				auction := openAuction()
+				auction.HighBidderID = &leader
+				auction.HighBid = 120
+
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				auctionRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(auction, nil)
+				userRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(raise.bidder, nil)
+				userRepo.EXPECT().Update(gomock.Any(), raise.bidder).Return(nil)
+				auctionRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+				auctionRepo.EXPECT().AddBid(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			res: raise,
+		},
+		{
+			name: "below the minimum",
+			mock: func() {
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				auctionRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(openAuction(), nil)
+			},
+			res: bid{amount: 90},
+			err: entity.ErrBidTooLow,
+		},
+		{
+			name: "not enough coins",
+			mock: func() {
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				auctionRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(openAuction(), nil)
+				userRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&entity.User{ID: 1, Coins: 50}, nil)
+			},
+			res: bid{amount: 100},
+			err: entity.ErrInsufficientFunds,
+		},
+		{
+			name: "auction has ended",
+			mock: func() {
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				auctionRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(ended, nil)
+			},
+			res: bid{amount: 100},
+			err: entity.ErrAuctionNotOpen,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			b := tc.res.(bid)
+			_, err := uc.PlaceBid(context.Background(), 1, 1, b.amount)
+
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, b.coins, b.bidder.Coins)
+				require.Equal(t, b.held, b.bidder.HeldCoins)
+				if b.prev != nil {
+					require.Equal(t, b.prevEnd, b.prev.Coins)
+					require.Zero(t, b.prev.HeldCoins)
+				}
+			}
+		})
+	}
+}
+
+func TestCloseDue(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	auctionRepo := mocks.NewMockAuctionRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	invRepo := mocks.NewMockInventoryRepository(ctrl)
+	txRepo := mocks.NewMockTransactionRepository(ctrl)
+	lotRepo := mocks.NewMockLotRepository(ctrl)
+	orderRepo := mocks.NewMockOrderRepository(ctrl)
+	notifier := mocks.NewMockNotifier(ctrl)
+	dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+	uc := NewAuctionUC(
+		auctionRepo,
+		userRepo,
+		mocks.NewMockMerchRepository(ctrl),
+		invRepo,
+		txRepo,
+		lotRepo,
+		orderRepo,
+		notifier,
+		dbTransactor,
+	)
+
+	now := time.Now()
+	winnerID := int64(2)
+
+	won := openAuction()
+	won.EndsAt = now.Add(-time.Minute)
+	won.HighBidderID = &winnerID
+	won.HighBid = 150
+
+	unsold := openAuction()
+	unsold.ID = 4
+	unsold.EndsAt = now.Add(-time.Minute)
+
+	winner := &entity.User{ID: 2, Coins: 10, HeldCoins: 150}
+
+	auctionRepo.EXPECT().ListDue(gomock.Any(), now, _closeBatchSize).Return([]int64{1, 4}, nil)
+	dbTransactor.EXPECT().
+		WithinTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		}).
+		Times(2)
+	auctionRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(won, nil)
+	auctionRepo.EXPECT().GetByID(gomock.Any(), int64(4)).Return(unsold, nil)
+	userRepo.EXPECT().GetByID(gomock.Any(), winnerID).Return(winner, nil)
+	userRepo.EXPECT().Update(gomock.Any(), winner).Return(nil)
+	lotRepo.EXPECT().Spend(gomock.Any(), winnerID, int64(150)).Return(nil, nil)
+	invRepo.EXPECT().Add(gomock.Any(), winnerID, int64(3), nil, int64(1)).Return(nil)
+	txRepo.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, tr *entity.Transaction) error {
+			require.Equal(t, entity.TransactionTypePurchase, tr.Type)
+			require.Equal(t, int64(150), tr.Amount)
+			return nil
+		})
+	orderRepo.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, o *entity.Order) error {
+			require.Equal(t, int64(150), o.Price)
+			return nil
+		})
+	auctionRepo.EXPECT().
+		Update(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, a entity.Auction) error {
+			require.Equal(t, entity.AuctionStatusClosed, a.Status)
+			return nil
+		}).
+		Times(2)
+	notifier.EXPECT().Notify(gomock.Any(), winnerID, "You won the auction for golden-hoody with 150 coins").Return(nil)
+
+	closed, err := uc.CloseDue(context.Background(), now)
+	require.NoError(t, err)
+	require.Equal(t, 2, closed)
+	require.Zero(t, winner.HeldCoins)
+	require.Equal(t, int64(10), winner.Coins)
+}
+
+func TestCancelAuction(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	auctionRepo := mocks.NewMockAuctionRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+	uc := NewAuctionUC(
+		auctionRepo,
+		userRepo,
+		mocks.NewMockMerchRepository(ctrl),
+		mocks.NewMockInventoryRepository(ctrl),
+		mocks.NewMockTransactionRepository(ctrl),
+		mocks.NewMockLotRepository(ctrl),
+		mocks.NewMockOrderRepository(ctrl),
+		mocks.NewMockNotifier(ctrl),
+		dbTransactor,
+	)
+
+	bidder := &entity.User{ID: 2, HeldCoins: 120}
+
+	tests := []test{
+		{
+			name: "refunds the leading bid",
+			mock: func() {
+				leader := int64(2)
+				auction := openAuction()
+				auction.HighBidderID = &leader
+				auction.HighBid = 120
+
+				userRepo.EXPECT().
+					GetByID(gomock.Any(), adminID).
+					Return(&entity.User{ID: adminID, Role: entity.UserRoleAdmin}, nil)
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				auctionRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(auction, nil)
+				userRepo.EXPECT().GetByID(gomock.Any(), leader).Return(bidder, nil)
+				userRepo.EXPECT().Update(gomock.Any(), bidder).Return(nil)
+				auctionRepo.EXPECT().
+					Update(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, a entity.Auction) error {
+						require.Equal(t, entity.AuctionStatusCancelled, a.Status)
+						return nil
+					})
+			},
+			res: adminID,
+		},
+		{
+			name: "not an admin",
+			mock: func() {
+				userRepo.EXPECT().
+					GetByID(gomock.Any(), int64(1)).
+					Return(&entity.User{ID: 1, Role: entity.UserRoleUser}, nil)
+			},
+			res: int64(1),
+			err: entity.ErrForbidden,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			err := uc.CancelAuction(context.Background(), tc.res.(int64), 1)
+
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, int64(120), bidder.Coins)
+			}
+		})
+	}
+}
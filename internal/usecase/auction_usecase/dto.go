@@ -0,0 +1,13 @@
+package auction_usecase
+
+import "time"
+
+// CreateAuctionRequest puts one unit of an item up for auction. A zero
+// StartsAt opens it right away; MinIncrement defaults to one coin.
+type CreateAuctionRequest struct {
+	ItemName     string    `json:"item" validate:"required"`
+	StartPrice   int64     `json:"start_price" validate:"required,min=1"`
+	MinIncrement int64     `json:"min_increment,omitempty" validate:"omitempty,min=1"`
+	StartsAt     time.Time `json:"starts_at,omitempty"`
+	EndsAt       time.Time `json:"ends_at" validate:"required"`
+}
@@ -0,0 +1,56 @@
+package auction_usecase
+
+import (
+	"context"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"time"
+)
+
+//go:generate mockgen -source=interfaces.go -destination=./mocks/mocks.go -package=mocks
+
+type AuctionRepository interface {
+	Create(ctx context.Context, auction *entity.Auction) error
+	GetByID(ctx context.Context, id int64) (entity.Auction, error)
+	ListOpen(ctx context.Context) ([]entity.Auction, error)
+	Update(ctx context.Context, auction entity.Auction) error
+	AddBid(ctx context.Context, bid *entity.AuctionBid) error
+	ListBids(ctx context.Context, auctionID int64) ([]entity.AuctionBid, error)
+	ListDue(ctx context.Context, now time.Time, limit int) ([]int64, error)
+}
+
+type UserRepository interface {
+	GetByID(ctx context.Context, id int64) (*entity.User, error)
+	Update(ctx context.Context, user *entity.User) error
+}
+
+type MerchRepository interface {
+	GetByName(ctx context.Context, name string) (entity.MerchItem, error)
+}
+
+// InventoryRepository hands the item to the winner.
+type InventoryRepository interface {
+	Add(ctx context.Context, userID, itemID int64, variantID *int64, quantity int64) error
+}
+
+type TransactionRepository interface {
+	Create(ctx context.Context, tr *entity.Transaction) error
+}
+
+// LotRepository takes the captured coins out of the winner's oldest lots.
+type LotRepository interface {
+	Spend(ctx context.Context, userID int64, amount int64) ([]entity.CoinLot, error)
+}
+
+// OrderRepository opens an order for the won item, like for any purchase.
+type OrderRepository interface {
+	Create(ctx context.Context, order *entity.Order) error
+}
+
+// Notifier delivers a short message to a user.
+type Notifier interface {
+	Notify(ctx context.Context, userID int64, message string) error
+}
+
+type DBTransactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
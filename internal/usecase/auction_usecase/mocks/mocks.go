@@ -0,0 +1,452 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: interfaces.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	entity "github.com/smthjapanese/avito-merch/internal/entity"
+)
+
+// MockAuctionRepository is a mock of AuctionRepository interface.
+type MockAuctionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuctionRepositoryMockRecorder
+}
+
+// MockAuctionRepositoryMockRecorder is the mock recorder for MockAuctionRepository.
+type MockAuctionRepositoryMockRecorder struct {
+	mock *MockAuctionRepository
+}
+
+// NewMockAuctionRepository creates a new mock instance.
+func NewMockAuctionRepository(ctrl *gomock.Controller) *MockAuctionRepository {
+	mock := &MockAuctionRepository{ctrl: ctrl}
+	mock.recorder = &MockAuctionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuctionRepository) EXPECT() *MockAuctionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddBid mocks base method.
+func (m *MockAuctionRepository) AddBid(ctx context.Context, bid *entity.AuctionBid) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddBid", ctx, bid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddBid indicates an expected call of AddBid.
+func (mr *MockAuctionRepositoryMockRecorder) AddBid(ctx, bid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddBid", reflect.TypeOf((*MockAuctionRepository)(nil).AddBid), ctx, bid)
+}
+
+// Create mocks base method.
+func (m *MockAuctionRepository) Create(ctx context.Context, auction *entity.Auction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, auction)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAuctionRepositoryMockRecorder) Create(ctx, auction interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAuctionRepository)(nil).Create), ctx, auction)
+}
+
+// GetByID mocks base method.
+func (m *MockAuctionRepository) GetByID(ctx context.Context, id int64) (entity.Auction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(entity.Auction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockAuctionRepositoryMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockAuctionRepository)(nil).GetByID), ctx, id)
+}
+
+// ListBids mocks base method.
+func (m *MockAuctionRepository) ListBids(ctx context.Context, auctionID int64) ([]entity.AuctionBid, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBids", ctx, auctionID)
+	ret0, _ := ret[0].([]entity.AuctionBid)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBids indicates an expected call of ListBids.
+func (mr *MockAuctionRepositoryMockRecorder) ListBids(ctx, auctionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBids", reflect.TypeOf((*MockAuctionRepository)(nil).ListBids), ctx, auctionID)
+}
+
+// ListDue mocks base method.
+func (m *MockAuctionRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDue", ctx, now, limit)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDue indicates an expected call of ListDue.
+func (mr *MockAuctionRepositoryMockRecorder) ListDue(ctx, now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDue", reflect.TypeOf((*MockAuctionRepository)(nil).ListDue), ctx, now, limit)
+}
+
+// ListOpen mocks base method.
+func (m *MockAuctionRepository) ListOpen(ctx context.Context) ([]entity.Auction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOpen", ctx)
+	ret0, _ := ret[0].([]entity.Auction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOpen indicates an expected call of ListOpen.
+func (mr *MockAuctionRepositoryMockRecorder) ListOpen(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOpen", reflect.TypeOf((*MockAuctionRepository)(nil).ListOpen), ctx)
+}
+
+// Update mocks base method.
+func (m *MockAuctionRepository) Update(ctx context.Context, auction entity.Auction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, auction)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockAuctionRepositoryMockRecorder) Update(ctx, auction interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAuctionRepository)(nil).Update), ctx, auction)
+}
+
+// MockUserRepository is a mock of UserRepository interface.
+type MockUserRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserRepositoryMockRecorder
+}
+
+// MockUserRepositoryMockRecorder is the mock recorder for MockUserRepository.
+type MockUserRepositoryMockRecorder struct {
+	mock *MockUserRepository
+}
+
+// NewMockUserRepository creates a new mock instance.
+func NewMockUserRepository(ctrl *gomock.Controller) *MockUserRepository {
+	mock := &MockUserRepository{ctrl: ctrl}
+	mock.recorder = &MockUserRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserRepository) EXPECT() *MockUserRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetByID mocks base method.
+func (m *MockUserRepository) GetByID(ctx context.Context, id int64) (*entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockUserRepositoryMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockUserRepository)(nil).GetByID), ctx, id)
+}
+
+// Update mocks base method.
+func (m *MockUserRepository) Update(ctx context.Context, user *entity.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockUserRepositoryMockRecorder) Update(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUserRepository)(nil).Update), ctx, user)
+}
+
+// MockMerchRepository is a mock of MerchRepository interface.
+type MockMerchRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockMerchRepositoryMockRecorder
+}
+
+// MockMerchRepositoryMockRecorder is the mock recorder for MockMerchRepository.
+type MockMerchRepositoryMockRecorder struct {
+	mock *MockMerchRepository
+}
+
+// NewMockMerchRepository creates a new mock instance.
+func NewMockMerchRepository(ctrl *gomock.Controller) *MockMerchRepository {
+	mock := &MockMerchRepository{ctrl: ctrl}
+	mock.recorder = &MockMerchRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMerchRepository) EXPECT() *MockMerchRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetByName mocks base method.
+func (m *MockMerchRepository) GetByName(ctx context.Context, name string) (entity.MerchItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByName", ctx, name)
+	ret0, _ := ret[0].(entity.MerchItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByName indicates an expected call of GetByName.
+func (mr *MockMerchRepositoryMockRecorder) GetByName(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByName", reflect.TypeOf((*MockMerchRepository)(nil).GetByName), ctx, name)
+}
+
+// MockInventoryRepository is a mock of InventoryRepository interface.
+type MockInventoryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockInventoryRepositoryMockRecorder
+}
+
+// MockInventoryRepositoryMockRecorder is the mock recorder for MockInventoryRepository.
+type MockInventoryRepositoryMockRecorder struct {
+	mock *MockInventoryRepository
+}
+
+// NewMockInventoryRepository creates a new mock instance.
+func NewMockInventoryRepository(ctrl *gomock.Controller) *MockInventoryRepository {
+	mock := &MockInventoryRepository{ctrl: ctrl}
+	mock.recorder = &MockInventoryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInventoryRepository) EXPECT() *MockInventoryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockInventoryRepository) Add(ctx context.Context, userID, itemID int64, variantID *int64, quantity int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, userID, itemID, variantID, quantity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockInventoryRepositoryMockRecorder) Add(ctx, userID, itemID, variantID, quantity interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockInventoryRepository)(nil).Add), ctx, userID, itemID, variantID, quantity)
+}
+
+// MockTransactionRepository is a mock of TransactionRepository interface.
+type MockTransactionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactionRepositoryMockRecorder
+}
+
+// MockTransactionRepositoryMockRecorder is the mock recorder for MockTransactionRepository.
+type MockTransactionRepositoryMockRecorder struct {
+	mock *MockTransactionRepository
+}
+
+// NewMockTransactionRepository creates a new mock instance.
+func NewMockTransactionRepository(ctrl *gomock.Controller) *MockTransactionRepository {
+	mock := &MockTransactionRepository{ctrl: ctrl}
+	mock.recorder = &MockTransactionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactionRepository) EXPECT() *MockTransactionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockTransactionRepository) Create(ctx context.Context, tr *entity.Transaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, tr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTransactionRepositoryMockRecorder) Create(ctx, tr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTransactionRepository)(nil).Create), ctx, tr)
+}
+
+// MockLotRepository is a mock of LotRepository interface.
+type MockLotRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLotRepositoryMockRecorder
+}
+
+// MockLotRepositoryMockRecorder is the mock recorder for MockLotRepository.
+type MockLotRepositoryMockRecorder struct {
+	mock *MockLotRepository
+}
+
+// NewMockLotRepository creates a new mock instance.
+func NewMockLotRepository(ctrl *gomock.Controller) *MockLotRepository {
+	mock := &MockLotRepository{ctrl: ctrl}
+	mock.recorder = &MockLotRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLotRepository) EXPECT() *MockLotRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Spend mocks base method.
+func (m *MockLotRepository) Spend(ctx context.Context, userID, amount int64) ([]entity.CoinLot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Spend", ctx, userID, amount)
+	ret0, _ := ret[0].([]entity.CoinLot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Spend indicates an expected call of Spend.
+func (mr *MockLotRepositoryMockRecorder) Spend(ctx, userID, amount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Spend", reflect.TypeOf((*MockLotRepository)(nil).Spend), ctx, userID, amount)
+}
+
+// MockOrderRepository is a mock of OrderRepository interface.
+type MockOrderRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrderRepositoryMockRecorder
+}
+
+// MockOrderRepositoryMockRecorder is the mock recorder for MockOrderRepository.
+type MockOrderRepositoryMockRecorder struct {
+	mock *MockOrderRepository
+}
+
+// NewMockOrderRepository creates a new mock instance.
+func NewMockOrderRepository(ctrl *gomock.Controller) *MockOrderRepository {
+	mock := &MockOrderRepository{ctrl: ctrl}
+	mock.recorder = &MockOrderRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrderRepository) EXPECT() *MockOrderRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockOrderRepository) Create(ctx context.Context, order *entity.Order) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, order)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockOrderRepositoryMockRecorder) Create(ctx, order interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockOrderRepository)(nil).Create), ctx, order)
+}
+
+// MockNotifier is a mock of Notifier interface.
+type MockNotifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotifierMockRecorder
+}
+
+// MockNotifierMockRecorder is the mock recorder for MockNotifier.
+type MockNotifierMockRecorder struct {
+	mock *MockNotifier
+}
+
+// NewMockNotifier creates a new mock instance.
+func NewMockNotifier(ctrl *gomock.Controller) *MockNotifier {
+	mock := &MockNotifier{ctrl: ctrl}
+	mock.recorder = &MockNotifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotifier) EXPECT() *MockNotifierMockRecorder {
+	return m.recorder
+}
+
+// Notify mocks base method.
+func (m *MockNotifier) Notify(ctx context.Context, userID int64, message string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Notify", ctx, userID, message)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Notify indicates an expected call of Notify.
+func (mr *MockNotifierMockRecorder) Notify(ctx, userID, message interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notify", reflect.TypeOf((*MockNotifier)(nil).Notify), ctx, userID, message)
+}
+
+// MockDBTransactor is a mock of DBTransactor interface.
+type MockDBTransactor struct {
+	ctrl     *gomock.Controller
+	recorder *MockDBTransactorMockRecorder
+}
+
+// MockDBTransactorMockRecorder is the mock recorder for MockDBTransactor.
+type MockDBTransactorMockRecorder struct {
+	mock *MockDBTransactor
+}
+
+// NewMockDBTransactor creates a new mock instance.
+func NewMockDBTransactor(ctrl *gomock.Controller) *MockDBTransactor {
+	mock := &MockDBTransactor{ctrl: ctrl}
+	mock.recorder = &MockDBTransactorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDBTransactor) EXPECT() *MockDBTransactorMockRecorder {
+	return m.recorder
+}
+
+// WithinTransaction mocks base method.
+func (m *MockDBTransactor) WithinTransaction(ctx context.Context, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithinTransaction", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithinTransaction indicates an expected call of WithinTransaction.
+func (mr *MockDBTransactorMockRecorder) WithinTransaction(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithinTransaction", reflect.TypeOf((*MockDBTransactor)(nil).WithinTransaction), ctx, fn)
+}
@@ -0,0 +1,287 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"strings"
+	"time"
+)
+
+const (
+	// _maxMachineTranslations bounds the items one request waits on the web
+	// API for; the rest of the missing translations are made on later
+	// requests.
+	_maxMachineTranslations  = 5
+	_machineTranslateTimeout = 3 * time.Second
+)
+
+// CatalogTranslationUseCase serves merch names and descriptions in the
+// client's language. Missing translations are made through the web API and
+// cached; translations written by admins take precedence over them.
+type CatalogTranslationUseCase struct {
+	repo          MerchTranslationRepo
+	merchRepo     MerchRepository
+	userRepo      UserRepository
+	webAPI        TranslationWebAPI
+	catalogLocale string
+	locales       map[string]bool
+}
+
+// NewCatalogTranslation -. The catalog is written in catalogLocale and
+// translated into any of locales.
+func NewCatalogTranslation(
+	r MerchTranslationRepo,
+	m MerchRepository,
+	u UserRepository,
+	w TranslationWebAPI,
+	catalogLocale string,
+	locales []string,
+) *CatalogTranslationUseCase {
+	supported := make(map[string]bool, len(locales))
+	for _, locale := range locales {
+		if locale, err := entity.NormalizeLocale(locale); err == nil {
+			supported[locale] = true
+		}
+	}
+
+	return &CatalogTranslationUseCase{
+		repo:          r,
+		merchRepo:     m,
+		userRepo:      u,
+		webAPI:        w,
+		catalogLocale: catalogLocale,
+		locales:       supported,
+	}
+}
+
+// Localize returns the items' names and descriptions in the most preferred
+// supported locale of acceptLanguage. At most _maxMachineTranslations
+// missing translations are made per call, within _machineTranslateTimeout;
+// items left over, or that the web API cannot translate, are returned as
+// written, so the catalog stays available while it is slow or down.
+func (uc *CatalogTranslationUseCase) Localize(ctx context.Context, items []entity.MerchItem, acceptLanguage string) ([]entity.MerchTranslation, error) {
+	result := make([]entity.MerchTranslation, len(items))
+	for i, item := range items {
+		result[i] = entity.OriginalTranslation(item, uc.catalogLocale)
+	}
+
+	locale := uc.pick(acceptLanguage)
+	if locale == uc.catalogLocale || len(items) == 0 {
+		return result, nil
+	}
+
+	ids := make([]int64, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+
+	cached, err := uc.repo.ListByLocale(ctx, locale, ids)
+	if err != nil {
+		return nil, fmt.Errorf("CatalogTranslationUseCase - Localize - uc.repo.ListByLocale: %w", err)
+	}
+
+	byItem := make(map[int64]entity.MerchTranslation, len(cached))
+	for _, t := range cached {
+		byItem[t.ItemID] = t
+	}
+
+	translateCtx, cancel := context.WithTimeout(ctx, _machineTranslateTimeout)
+	defer cancel()
+
+	budget := _maxMachineTranslations
+	for i, item := range items {
+		if t, ok := byItem[item.ID]; ok && t.IsCurrent(item) {
+			result[i] = t
+			continue
+		}
+		if budget == 0 {
+			continue
+		}
+		budget--
+
+		t, err := uc.machineTranslate(translateCtx, item, locale)
+		if err != nil {
+			// no point asking again for the rest of the items
+			budget = 0
+			continue
+		}
+
+		saved, err := uc.repo.Save(ctx, &t)
+		if err != nil {
+			return nil, fmt.Errorf("CatalogTranslationUseCase - Localize - uc.repo.Save: %w", err)
+		}
+		if !saved {
+			// an admin translated the item in the meantime
+			stored, err := uc.repo.ListByLocale(ctx, locale, []int64{item.ID})
+			if err != nil {
+				return nil, fmt.Errorf("CatalogTranslationUseCase - Localize - uc.repo.ListByLocale: %w", err)
+			}
+			if len(stored) == 0 {
+				continue
+			}
+			t = stored[0]
+		}
+		result[i] = t
+	}
+
+	return result, nil
+}
+
+// SetTranslation stores an admin's translation of an item. It replaces any
+// machine translation and is never replaced by one.
+func (uc *CatalogTranslationUseCase) SetTranslation(ctx context.Context, adminID int64, req MerchTranslationRequest) (entity.MerchTranslation, error) {
+	if err := uc.requireAdmin(ctx, adminID); err != nil {
+		return entity.MerchTranslation{}, err
+	}
+
+	locale, err := uc.supported(req.Locale)
+	if err != nil {
+		return entity.MerchTranslation{}, err
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return entity.MerchTranslation{}, entity.ErrTranslationNameRequired
+	}
+
+	item, err := uc.merchRepo.GetByName(ctx, req.ItemName)
+	if err != nil {
+		return entity.MerchTranslation{}, err
+	}
+
+	t := entity.MerchTranslation{
+		ItemID:            item.ID,
+		Locale:            locale,
+		Name:              name,
+		Description:       strings.TrimSpace(req.Description),
+		Source:            entity.TranslationSourceHuman,
+		SourceName:        item.Name,
+		SourceDescription: item.Description,
+	}
+	if _, err := uc.repo.Save(ctx, &t); err != nil {
+		return entity.MerchTranslation{}, fmt.Errorf("CatalogTranslationUseCase - SetTranslation - uc.repo.Save: %w", err)
+	}
+
+	return t, nil
+}
+
+// DeleteTranslation drops an item's translation into locale. The next time
+// it is asked for, a machine translation takes its place.
+func (uc *CatalogTranslationUseCase) DeleteTranslation(ctx context.Context, adminID int64, itemName, locale string) error {
+	if err := uc.requireAdmin(ctx, adminID); err != nil {
+		return err
+	}
+
+	locale, err := uc.supported(locale)
+	if err != nil {
+		return err
+	}
+
+	item, err := uc.merchRepo.GetByName(ctx, itemName)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := uc.repo.Delete(ctx, item.ID, locale)
+	if err != nil {
+		return fmt.Errorf("CatalogTranslationUseCase - DeleteTranslation - uc.repo.Delete: %w", err)
+	}
+	if !deleted {
+		return entity.ErrTranslationNotFound
+	}
+
+	return nil
+}
+
+// pick returns the first locale of acceptLanguage the catalog is available
+// in, falling back to the catalog's own.
+func (uc *CatalogTranslationUseCase) pick(acceptLanguage string) string {
+	for _, locale := range entity.PreferredLocales(acceptLanguage) {
+		if locale == uc.catalogLocale || uc.locales[locale] {
+			return locale
+		}
+	}
+
+	return uc.catalogLocale
+}
+
+func (uc *CatalogTranslationUseCase) supported(locale string) (string, error) {
+	locale, err := entity.NormalizeLocale(locale)
+	if err != nil {
+		return "", err
+	}
+	if locale == uc.catalogLocale || !uc.locales[locale] {
+		return "", entity.ErrUnsupportedLocale
+	}
+
+	return locale, nil
+}
+
+// machineTranslate translates the item through the web API. The web API
+// takes no context, so a call still running when ctx is done is left to
+// finish on its own.
+func (uc *CatalogTranslationUseCase) machineTranslate(ctx context.Context, item entity.MerchItem, locale string) (entity.MerchTranslation, error) {
+	type reply struct {
+		res entity.Translation
+		err error
+	}
+
+	translate := func(text string) (string, error) {
+		if text == "" {
+			return "", nil
+		}
+
+		done := make(chan reply, 1)
+		go func() {
+			res, err := uc.webAPI.Translate(entity.Translation{
+				Source:      uc.catalogLocale,
+				Destination: locale,
+				Original:    text,
+			})
+			done <- reply{res: res, err: err}
+		}()
+
+		select {
+		case r := <-done:
+			if r.err != nil {
+				return "", fmt.Errorf("CatalogTranslationUseCase - machineTranslate - uc.webAPI.Translate: %w", r.err)
+			}
+			return r.res.Translation, nil
+		case <-ctx.Done():
+			return "", fmt.Errorf("CatalogTranslationUseCase - machineTranslate - uc.webAPI.Translate: %w", ctx.Err())
+		}
+	}
+
+	name, err := translate(item.Name)
+	if err != nil {
+		return entity.MerchTranslation{}, err
+	}
+
+	description, err := translate(item.Description)
+	if err != nil {
+		return entity.MerchTranslation{}, err
+	}
+
+	return entity.MerchTranslation{
+		ItemID:            item.ID,
+		Locale:            locale,
+		Name:              name,
+		Description:       description,
+		Source:            entity.TranslationSourceMachine,
+		SourceName:        item.Name,
+		SourceDescription: item.Description,
+	}, nil
+}
+
+func (uc *CatalogTranslationUseCase) requireAdmin(ctx context.Context, adminID int64) error {
+	admin, err := uc.userRepo.GetByID(ctx, adminID)
+	if err != nil {
+		return err
+	}
+	if admin == nil || !admin.IsAdmin() {
+		return entity.ErrForbidden
+	}
+
+	return nil
+}
@@ -0,0 +1,275 @@
+package usecase_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/usecase"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+type catalogTranslationMocks struct {
+	repo      *MockMerchTranslationRepo
+	merchRepo *MockMerchRepository
+	userRepo  *MockUserRepository
+	webAPI    *MockTranslationWebAPI
+}
+
+func catalogTranslation(t *testing.T) (*usecase.CatalogTranslationUseCase, catalogTranslationMocks) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+
+	m := catalogTranslationMocks{
+		repo:      NewMockMerchTranslationRepo(mockCtl),
+		merchRepo: NewMockMerchRepository(mockCtl),
+		userRepo:  NewMockUserRepository(mockCtl),
+		webAPI:    NewMockTranslationWebAPI(mockCtl),
+	}
+
+	uc := usecase.NewCatalogTranslation(m.repo, m.merchRepo, m.userRepo, m.webAPI, "en", []string{"ru", "de"})
+
+	return uc, m
+}
+
+// machine translates text the way the web API would.
+func machine(m catalogTranslationMocks, original, translated string) *gomock.Call {
+	return m.webAPI.EXPECT().
+		Translate(entity.Translation{Source: "en", Destination: "ru", Original: original}).
+		Return(entity.Translation{Source: "en", Destination: "ru", Original: original, Translation: translated}, nil)
+}
+
+// admin expects the lookup of the admin making the change.
+func admin(m catalogTranslationMocks) *gomock.Call {
+	return m.userRepo.EXPECT().GetByID(gomock.Any(), int64(9)).Return(&entity.User{ID: 9, Role: entity.UserRoleAdmin}, nil)
+}
+
+func TestLocalize(t *testing.T) {
+	t.Parallel()
+
+	uc, m := catalogTranslation(t)
+
+	items := []entity.MerchItem{
+		{ID: 1, Name: "cup", Description: "a cup"},
+		{ID: 2, Name: "pen"},
+		{ID: 3, Name: "hoody"},
+	}
+	human := entity.MerchTranslation{
+		ItemID: 1, Locale: "ru", Name: "кружка", Description: "просто кружка",
+		Source: entity.TranslationSourceHuman, SourceName: "cup", SourceDescription: "an old cup",
+	}
+	stale := entity.MerchTranslation{
+		ItemID: 2, Locale: "ru", Name: "ручкa", Source: entity.TranslationSourceMachine, SourceName: "old pen",
+	}
+
+	tests := []struct {
+		test
+		acceptLanguage string
+	}{
+		{
+			test: test{
+				name: "catalog locale",
+				mock: func() {},
+				res:  []string{"cup", "pen", "hoody"},
+			},
+			acceptLanguage: "fr-FR,fr;q=0.9,en;q=0.8",
+		},
+		{
+			test: test{
+				name: "human override kept, stale and missing translated",
+				mock: func() {
+					m.repo.EXPECT().ListByLocale(gomock.Any(), "ru", []int64{1, 2, 3}).Return([]entity.MerchTranslation{human, stale}, nil)
+					machine(m, "pen", "ручка")
+					machine(m, "hoody", "худи")
+					m.repo.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(
+						func(_ context.Context, tr *entity.MerchTranslation) (bool, error) {
+							require.Equal(t, entity.TranslationSourceMachine, tr.Source)
+							return true, nil
+						}).Times(2)
+				},
+				res: []string{"кружка", "ручка", "худи"},
+			},
+			acceptLanguage: "fr, ru-RU;q=0.9",
+		},
+		{
+			test: test{
+				name: "web API down",
+				mock: func() {
+					m.repo.EXPECT().ListByLocale(gomock.Any(), "ru", []int64{1, 2, 3}).Return([]entity.MerchTranslation{human}, nil)
+					m.webAPI.EXPECT().Translate(gomock.Any()).Return(entity.Translation{}, errInternalServErr)
+				},
+				res: []string{"кружка", "pen", "hoody"},
+			},
+			acceptLanguage: "ru",
+		},
+		{
+			test: test{
+				name: "admin translation saved meanwhile",
+				mock: func() {
+					m.repo.EXPECT().ListByLocale(gomock.Any(), "ru", []int64{1, 2, 3}).Return([]entity.MerchTranslation{human, stale}, nil)
+					machine(m, "pen", "ручка")
+					m.repo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(false, nil)
+					m.repo.EXPECT().ListByLocale(gomock.Any(), "ru", []int64{2}).Return([]entity.MerchTranslation{
+						{ItemID: 2, Locale: "ru", Name: "ручка (ред.)", Source: entity.TranslationSourceHuman, SourceName: "pen"},
+					}, nil)
+					machine(m, "hoody", "худи")
+					m.repo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(true, nil)
+				},
+				res: []string{"кружка", "ручка (ред.)", "худи"},
+			},
+			acceptLanguage: "ru",
+		},
+		{
+			test: test{
+				name: "repo error",
+				mock: func() {
+					m.repo.EXPECT().ListByLocale(gomock.Any(), "ru", gomock.Any()).Return(nil, errInternalServErr)
+				},
+				err: errInternalServErr,
+			},
+			acceptLanguage: "ru",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			res, err := uc.Localize(context.Background(), items, tc.acceptLanguage)
+
+			require.ErrorIs(t, err, tc.err)
+			if tc.err != nil {
+				return
+			}
+			names := make([]string, len(res))
+			for i, tr := range res {
+				names[i] = tr.Name
+			}
+			require.Equal(t, tc.res, names)
+		})
+	}
+}
+
+func TestLocalize_Budget(t *testing.T) {
+	t.Parallel()
+
+	uc, m := catalogTranslation(t)
+
+	items := make([]entity.MerchItem, 8)
+	ids := make([]int64, len(items))
+	for i := range items {
+		items[i] = entity.MerchItem{ID: int64(i + 1), Name: fmt.Sprintf("item %d", i+1)}
+		ids[i] = items[i].ID
+	}
+
+	m.repo.EXPECT().ListByLocale(gomock.Any(), "ru", ids).Return(nil, nil)
+	m.webAPI.EXPECT().
+		Translate(gomock.Any()).
+		DoAndReturn(func(tr entity.Translation) (entity.Translation, error) {
+			tr.Translation = "ru: " + tr.Original
+			return tr, nil
+		}).
+		Times(5)
+	m.repo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(true, nil).Times(5)
+
+	res, err := uc.Localize(context.Background(), items, "ru")
+	require.NoError(t, err)
+	require.Equal(t, "ru: item 5", res[4].Name)
+	require.Equal(t, "item 6", res[5].Name)
+	require.Equal(t, "en", res[5].Locale)
+}
+
+func TestSetTranslation(t *testing.T) {
+	t.Parallel()
+
+	uc, m := catalogTranslation(t)
+
+	cup := entity.MerchItem{ID: 1, Name: "cup", Description: "a cup"}
+	req := usecase.MerchTranslationRequest{ItemName: "cup", Locale: "ru-RU", Name: " кружка ", Description: "просто кружка"}
+
+	tests := []struct {
+		test
+		adminID int64
+		req     usecase.MerchTranslationRequest
+	}{
+		{
+			test: test{
+				name: "not an admin",
+				mock: func() {
+					m.userRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(&entity.User{ID: 2}, nil)
+				},
+				err: entity.ErrForbidden,
+			},
+			adminID: 2,
+			req:     req,
+		},
+		{
+			test: test{
+				name: "unsupported locale",
+				mock: func() {
+					admin(m)
+				},
+				err: entity.ErrUnsupportedLocale,
+			},
+			adminID: 9,
+			req:     usecase.MerchTranslationRequest{ItemName: "cup", Locale: "en", Name: "mug"},
+		},
+		{
+			test: test{
+				name: "empty name",
+				mock: func() {
+					admin(m)
+				},
+				err: entity.ErrTranslationNameRequired,
+			},
+			adminID: 9,
+			req:     usecase.MerchTranslationRequest{ItemName: "cup", Locale: "ru", Name: " "},
+		},
+		{
+			test: test{
+				name: "success",
+				mock: func() {
+					admin(m)
+					m.merchRepo.EXPECT().GetByName(gomock.Any(), "cup").Return(cup, nil)
+					m.repo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(true, nil)
+				},
+				res: entity.MerchTranslation{
+					ItemID: 1, Locale: "ru", Name: "кружка", Description: "просто кружка",
+					Source: entity.TranslationSourceHuman, SourceName: "cup", SourceDescription: "a cup",
+				},
+			},
+			adminID: 9,
+			req:     req,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			res, err := uc.SetTranslation(context.Background(), tc.adminID, tc.req)
+
+			require.ErrorIs(t, err, tc.err)
+			if tc.err == nil {
+				require.Equal(t, tc.res, res)
+			}
+		})
+	}
+}
+
+func TestDeleteTranslation(t *testing.T) {
+	t.Parallel()
+
+	uc, m := catalogTranslation(t)
+
+	admin(m).Times(2)
+	m.merchRepo.EXPECT().GetByName(gomock.Any(), "cup").Return(entity.MerchItem{ID: 1, Name: "cup"}, nil).Times(2)
+	m.repo.EXPECT().Delete(gomock.Any(), int64(1), "ru").Return(true, nil)
+	m.repo.EXPECT().Delete(gomock.Any(), int64(1), "ru").Return(false, nil)
+
+	require.NoError(t, uc.DeleteTranslation(context.Background(), 9, "cup", "ru"))
+	require.ErrorIs(t, uc.DeleteTranslation(context.Background(), 9, "cup", "ru"), entity.ErrTranslationNotFound)
+}
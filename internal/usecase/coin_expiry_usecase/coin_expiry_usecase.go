@@ -0,0 +1,126 @@
+package coin_expiry_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"time"
+)
+
+const _defaultBatchSize = 100
+
+// CoinExpiryUC sweeps the coins left in lots whose expiry date has passed.
+// Swept coins go back to the system account and are recorded as expiry
+// transactions.
+type CoinExpiryUC struct {
+	lotRepo   LotRepository
+	userRepo  UserRepository
+	txRepo    TransactionRepository
+	dbTx      DBTransactor
+	batchSize int
+}
+
+func NewCoinExpiryUC(
+	lotRepo LotRepository,
+	userRepo UserRepository,
+	txRepo TransactionRepository,
+	dbTx DBTransactor,
+) *CoinExpiryUC {
+	return &CoinExpiryUC{
+		lotRepo:   lotRepo,
+		userRepo:  userRepo,
+		txRepo:    txRepo,
+		dbTx:      dbTx,
+		batchSize: _defaultBatchSize,
+	}
+}
+
+// Sweep expires the lots that are past their expiry date at now and returns
+// how many coins were taken. Each lot is handled in its own transaction.
+//
+// Coins held for a pending operation are left in their lot: they are still
+// spent when the operation goes through, and swept on a later run if it is
+// cancelled instead.
+func (uc *CoinExpiryUC) Sweep(ctx context.Context, now time.Time) (int64, error) {
+	ids, err := uc.lotRepo.ListExpired(ctx, now, uc.batchSize)
+	if err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	system, err := uc.userRepo.GetByUsername(ctx, entity.SystemUsername)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		swept int64
+		errs  []error
+	)
+
+	for _, id := range ids {
+		err := uc.dbTx.WithinTransaction(ctx, func(ctx context.Context) error {
+			amount, err := uc.expire(ctx, id, system.ID, now)
+			if err != nil {
+				return err
+			}
+
+			swept += amount
+			return nil
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("coin lot %d: %w", id, err))
+		}
+	}
+
+	return swept, errors.Join(errs...)
+}
+
+func (uc *CoinExpiryUC) expire(ctx context.Context, id, systemID int64, now time.Time) (int64, error) {
+	lot, err := uc.lotRepo.GetByID(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	// Spent or swept since it was listed.
+	if lot.Remaining == 0 || !lot.IsExpired(now) {
+		return 0, nil
+	}
+
+	user, err := uc.userRepo.GetByID(ctx, lot.UserID)
+	if err != nil {
+		return 0, err
+	}
+	if user == nil {
+		return 0, entity.ErrUserNotFound
+	}
+
+	amount := min(lot.Remaining, user.Coins)
+	if amount == 0 {
+		return 0, nil
+	}
+
+	lot.Remaining -= amount
+	if err := uc.lotRepo.Update(ctx, lot); err != nil {
+		return 0, err
+	}
+
+	user.Coins -= amount
+	if err := uc.userRepo.Update(ctx, user); err != nil {
+		return 0, err
+	}
+
+	tx := entity.Transaction{
+		FromUserID: user.ID,
+		ToUserID:   systemID,
+		Amount:     amount,
+		Type:       entity.TransactionTypeExpiry,
+	}
+	if err := uc.txRepo.Create(ctx, &tx); err != nil {
+		return 0, err
+	}
+
+	return amount, nil
+}
@@ -0,0 +1,161 @@
+package coin_expiry_usecase
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/usecase/coin_expiry_usecase/mocks"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestSweep(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(-time.Hour)
+
+	expiredLot := func(remaining int64) entity.CoinLot {
+		return entity.CoinLot{
+			ID:        7,
+			UserID:    1,
+			Amount:    500,
+			Remaining: remaining,
+			GrantedAt: expiresAt.AddDate(-1, 0, 0),
+			ExpiresAt: &expiresAt,
+		}
+	}
+
+	t.Run("expired lot is swept", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		lotRepo := mocks.NewMockLotRepository(ctrl)
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		txRepo := mocks.NewMockTransactionRepository(ctrl)
+		dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+		uc := NewCoinExpiryUC(lotRepo, userRepo, txRepo, dbTransactor)
+
+		lotRepo.EXPECT().ListExpired(gomock.Any(), now, _defaultBatchSize).Return([]int64{7}, nil)
+		userRepo.EXPECT().
+			GetByUsername(gomock.Any(), entity.SystemUsername).
+			Return(&entity.User{ID: 100, Username: entity.SystemUsername, Role: entity.UserRoleSystem}, nil)
+		dbTransactor.EXPECT().
+			WithinTransaction(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+		lotRepo.EXPECT().GetByID(gomock.Any(), int64(7)).Return(expiredLot(300), nil)
+		userRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&entity.User{ID: 1, Coins: 800}, nil)
+
+		lotRepo.EXPECT().
+			Update(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, lot entity.CoinLot) error {
+				require.Zero(t, lot.Remaining)
+				return nil
+			})
+		userRepo.EXPECT().
+			Update(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, user *entity.User) error {
+				require.Equal(t, int64(500), user.Coins)
+				return nil
+			})
+		txRepo.EXPECT().
+			Create(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tx *entity.Transaction) error {
+				require.Equal(t, entity.TransactionTypeExpiry, tx.Type)
+				require.Equal(t, int64(1), tx.FromUserID)
+				require.Equal(t, int64(100), tx.ToUserID)
+				require.Equal(t, int64(300), tx.Amount)
+				return nil
+			})
+
+		swept, err := uc.Sweep(context.Background(), now)
+		require.NoError(t, err)
+		require.Equal(t, int64(300), swept)
+	})
+
+	t.Run("held coins stay in the lot", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		lotRepo := mocks.NewMockLotRepository(ctrl)
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		txRepo := mocks.NewMockTransactionRepository(ctrl)
+		dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+		uc := NewCoinExpiryUC(lotRepo, userRepo, txRepo, dbTransactor)
+
+		lotRepo.EXPECT().ListExpired(gomock.Any(), now, _defaultBatchSize).Return([]int64{7}, nil)
+		userRepo.EXPECT().
+			GetByUsername(gomock.Any(), entity.SystemUsername).
+			Return(&entity.User{ID: 100, Username: entity.SystemUsername, Role: entity.UserRoleSystem}, nil)
+		dbTransactor.EXPECT().
+			WithinTransaction(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+		lotRepo.EXPECT().GetByID(gomock.Any(), int64(7)).Return(expiredLot(300), nil)
+		userRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&entity.User{ID: 1, Coins: 100, HeldCoins: 200}, nil)
+
+		lotRepo.EXPECT().
+			Update(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, lot entity.CoinLot) error {
+				require.Equal(t, int64(200), lot.Remaining)
+				return nil
+			})
+		userRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+		txRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+		swept, err := uc.Sweep(context.Background(), now)
+		require.NoError(t, err)
+		require.Equal(t, int64(100), swept)
+	})
+
+	t.Run("lot spent since it was listed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		lotRepo := mocks.NewMockLotRepository(ctrl)
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		txRepo := mocks.NewMockTransactionRepository(ctrl)
+		dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+		uc := NewCoinExpiryUC(lotRepo, userRepo, txRepo, dbTransactor)
+
+		lotRepo.EXPECT().ListExpired(gomock.Any(), now, _defaultBatchSize).Return([]int64{7}, nil)
+		userRepo.EXPECT().
+			GetByUsername(gomock.Any(), entity.SystemUsername).
+			Return(&entity.User{ID: 100, Username: entity.SystemUsername, Role: entity.UserRoleSystem}, nil)
+		dbTransactor.EXPECT().
+			WithinTransaction(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+		lotRepo.EXPECT().GetByID(gomock.Any(), int64(7)).Return(expiredLot(0), nil)
+
+		swept, err := uc.Sweep(context.Background(), now)
+		require.NoError(t, err)
+		require.Zero(t, swept)
+	})
+
+	t.Run("nothing to sweep", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		lotRepo := mocks.NewMockLotRepository(ctrl)
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		txRepo := mocks.NewMockTransactionRepository(ctrl)
+		dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+		uc := NewCoinExpiryUC(lotRepo, userRepo, txRepo, dbTransactor)
+
+		lotRepo.EXPECT().ListExpired(gomock.Any(), now, _defaultBatchSize).Return(nil, nil)
+
+		swept, err := uc.Sweep(context.Background(), now)
+		require.NoError(t, err)
+		require.Zero(t, swept)
+	})
+}
@@ -0,0 +1,29 @@
+package coin_expiry_usecase
+
+import (
+	"context"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"time"
+)
+
+//go:generate mockgen -source=interfaces.go -destination=./mocks/mocks.go -package=mocks
+
+type LotRepository interface {
+	GetByID(ctx context.Context, id int64) (entity.CoinLot, error)
+	Update(ctx context.Context, lot entity.CoinLot) error
+	ListExpired(ctx context.Context, now time.Time, limit int) ([]int64, error)
+}
+
+type UserRepository interface {
+	GetByID(ctx context.Context, id int64) (*entity.User, error)
+	GetByUsername(ctx context.Context, username string) (*entity.User, error)
+	Update(ctx context.Context, user *entity.User) error
+}
+
+type TransactionRepository interface {
+	Create(ctx context.Context, tr *entity.Transaction) error
+}
+
+type DBTransactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
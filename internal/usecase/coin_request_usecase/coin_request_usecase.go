@@ -0,0 +1,209 @@
+package coin_request_usecase
+
+import (
+	"context"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"time"
+	"unicode/utf8"
+)
+
+const _defaultRequestTTL = 7 * 24 * time.Hour
+
+// CoinRequestUC lets a user ask a colleague for coins. The payer accepts or
+// declines, the requester may cancel, and requests left pending past their
+// TTL expire.
+type CoinRequestUC struct {
+	reqRepo    Repository
+	userRepo   UserRepository
+	transferer Transferer
+	dbTx       DBTransactor
+	ttl        time.Duration
+}
+
+func NewCoinRequestUC(
+	reqRepo Repository,
+	userRepo UserRepository,
+	transferer Transferer,
+	dbTx DBTransactor,
+	ttl time.Duration,
+) *CoinRequestUC {
+	if ttl <= 0 {
+		ttl = _defaultRequestTTL
+	}
+
+	return &CoinRequestUC{
+		reqRepo:    reqRepo,
+		userRepo:   userRepo,
+		transferer: transferer,
+		dbTx:       dbTx,
+		ttl:        ttl,
+	}
+}
+
+func (uc *CoinRequestUC) Create(ctx context.Context, requesterID, payerID int64, amount int64, reason string) (entity.CoinRequest, error) {
+	if amount <= 0 {
+		return entity.CoinRequest{}, entity.ErrNegativeAmount
+	}
+	if requesterID == payerID {
+		return entity.CoinRequest{}, entity.ErrSelfRequest
+	}
+	if utf8.RuneCountInString(reason) > entity.MaxTransferMessageLength {
+		return entity.CoinRequest{}, entity.ErrMessageTooLong
+	}
+
+	payer, err := uc.userRepo.GetByID(ctx, payerID)
+	if err != nil {
+		return entity.CoinRequest{}, err
+	}
+	if payer == nil {
+		return entity.CoinRequest{}, entity.ErrUserNotFound
+	}
+
+	req := entity.CoinRequest{
+		RequesterID: requesterID,
+		PayerID:     payerID,
+		Amount:      amount,
+		Reason:      reason,
+		Status:      entity.CoinRequestStatusPending,
+		ExpiresAt:   time.Now().Add(uc.ttl),
+	}
+	if err := uc.reqRepo.Create(ctx, &req); err != nil {
+		return entity.CoinRequest{}, err
+	}
+
+	return req, nil
+}
+
+// Accept pays a pending request. The request row is locked and resolved in
+// the same transaction as the transfer, so accepting twice cannot pay twice.
+// A payment that would have to wait for approval is refused with
+// ErrApprovalRequired and the request stays pending: it is only accepted
+// once the coins have actually moved.
+func (uc *CoinRequestUC) Accept(ctx context.Context, payerID, requestID int64) error {
+	return uc.dbTx.WithinTransaction(ctx, func(ctx context.Context) error {
+		req, err := uc.pendingRequest(ctx, requestID, func(req entity.CoinRequest) bool {
+			return req.PayerID == payerID
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := uc.resolve(ctx, req.ID, entity.CoinRequestStatusAccepted); err != nil {
+			return err
+		}
+
+		return uc.transferer.CreateDirectTransfer(ctx, req.PayerID, req.RequesterID, req.Amount, req.Reason)
+	})
+}
+
+func (uc *CoinRequestUC) Decline(ctx context.Context, payerID, requestID int64) error {
+	return uc.dbTx.WithinTransaction(ctx, func(ctx context.Context) error {
+		req, err := uc.pendingRequest(ctx, requestID, func(req entity.CoinRequest) bool {
+			return req.PayerID == payerID
+		})
+		if err != nil {
+			return err
+		}
+
+		return uc.resolve(ctx, req.ID, entity.CoinRequestStatusDeclined)
+	})
+}
+
+func (uc *CoinRequestUC) Cancel(ctx context.Context, requesterID, requestID int64) error {
+	return uc.dbTx.WithinTransaction(ctx, func(ctx context.Context) error {
+		req, err := uc.pendingRequest(ctx, requestID, func(req entity.CoinRequest) bool {
+			return req.RequesterID == requesterID
+		})
+		if err != nil {
+			return err
+		}
+
+		return uc.resolve(ctx, req.ID, entity.CoinRequestStatusCancelled)
+	})
+}
+
+// ExpireRequests marks requests that outlived their TTL as expired. It is
+// meant to be run by the scheduler.
+func (uc *CoinRequestUC) ExpireRequests(ctx context.Context, now time.Time) (int64, error) {
+	return uc.reqRepo.ExpirePending(ctx, now)
+}
+
+func (uc *CoinRequestUC) ListIncoming(ctx context.Context, userID int64) ([]CoinRequestInfo, error) {
+	reqs, err := uc.reqRepo.ListIncoming(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.toInfo(ctx, reqs, func(req entity.CoinRequest) int64 { return req.RequesterID }), nil
+}
+
+func (uc *CoinRequestUC) ListOutgoing(ctx context.Context, userID int64) ([]CoinRequestInfo, error) {
+	reqs, err := uc.reqRepo.ListOutgoing(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.toInfo(ctx, reqs, func(req entity.CoinRequest) int64 { return req.PayerID }), nil
+}
+
+// pendingRequest loads a request that the caller is allowed to act on and
+// that is still pending. Requests past their expiry are refused even if the
+// scheduler has not marked them expired yet.
+func (uc *CoinRequestUC) pendingRequest(
+	ctx context.Context,
+	requestID int64,
+	allowed func(req entity.CoinRequest) bool,
+) (entity.CoinRequest, error) {
+	req, err := uc.reqRepo.GetByID(ctx, requestID)
+	if err != nil {
+		return entity.CoinRequest{}, err
+	}
+	if !allowed(req) {
+		return entity.CoinRequest{}, entity.ErrCoinRequestNotFound
+	}
+	if !req.IsPending() {
+		return entity.CoinRequest{}, entity.ErrCoinRequestNotPending
+	}
+	if req.IsExpired(time.Now()) {
+		return entity.CoinRequest{}, entity.ErrCoinRequestExpired
+	}
+
+	return req, nil
+}
+
+func (uc *CoinRequestUC) resolve(ctx context.Context, requestID int64, status entity.CoinRequestStatus) error {
+	resolved, err := uc.reqRepo.Resolve(ctx, requestID, status)
+	if err != nil {
+		return err
+	}
+	if !resolved {
+		return entity.ErrCoinRequestNotPending
+	}
+
+	return nil
+}
+
+func (uc *CoinRequestUC) toInfo(
+	ctx context.Context,
+	reqs []entity.CoinRequest,
+	counterpart func(req entity.CoinRequest) int64,
+) []CoinRequestInfo {
+	result := make([]CoinRequestInfo, 0, len(reqs))
+	for _, req := range reqs {
+		user, err := uc.userRepo.GetByID(ctx, counterpart(req))
+		if err != nil {
+			continue
+		}
+		result = append(result, CoinRequestInfo{
+			ID:        req.ID,
+			User:      user.Username,
+			Amount:    req.Amount,
+			Reason:    req.Reason,
+			Status:    req.Status,
+			ExpiresAt: req.ExpiresAt,
+			CreatedAt: req.CreatedAt,
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,287 @@
+package coin_request_usecase
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/usecase/coin_request_usecase/mocks"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type test struct {
+	name string
+	mock func()
+	res  interface{}
+	err  error
+}
+
+func pendingRequest() entity.CoinRequest {
+	return entity.CoinRequest{
+		ID:          10,
+		RequesterID: 1,
+		PayerID:     2,
+		Amount:      100,
+		Reason:      "pizza",
+		Status:      entity.CoinRequestStatusPending,
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+}
+
+func TestCreate(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	reqRepo := mocks.NewMockRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	transferer := mocks.NewMockTransferer(ctrl)
+	dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+	uc := NewCoinRequestUC(reqRepo, userRepo, transferer, dbTransactor, time.Hour)
+
+	userRepo.EXPECT().
+		GetByID(gomock.Any(), int64(2)).
+		Return(&entity.User{ID: 2, Username: "payer"}, nil)
+
+	reqRepo.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, req *entity.CoinRequest) error {
+			require.Equal(t, entity.CoinRequestStatusPending, req.Status)
+			require.WithinDuration(t, time.Now().Add(time.Hour), req.ExpiresAt, time.Minute)
+			req.ID = 10
+			return nil
+		})
+
+	req, err := uc.Create(context.Background(), 1, 2, 100, "pizza")
+	require.NoError(t, err)
+	require.Equal(t, int64(10), req.ID)
+
+	_, err = uc.Create(context.Background(), 1, 1, 100, "pizza")
+	require.ErrorIs(t, err, entity.ErrSelfRequest)
+
+	_, err = uc.Create(context.Background(), 1, 2, 0, "pizza")
+	require.ErrorIs(t, err, entity.ErrNegativeAmount)
+}
+
+func TestAccept(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	reqRepo := mocks.NewMockRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	transferer := mocks.NewMockTransferer(ctrl)
+	dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+	uc := NewCoinRequestUC(reqRepo, userRepo, transferer, dbTransactor, time.Hour)
+
+	tests := []test{
+		{
+			name: "success",
+			mock: func() {
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				reqRepo.EXPECT().GetByID(gomock.Any(), int64(10)).Return(pendingRequest(), nil)
+				reqRepo.EXPECT().
+					Resolve(gomock.Any(), int64(10), entity.CoinRequestStatusAccepted).
+					Return(true, nil)
+				transferer.EXPECT().
+					CreateDirectTransfer(gomock.Any(), int64(2), int64(1), int64(100), "pizza").
+					Return(nil)
+			},
+			err: nil,
+		},
+		{
+			name: "already accepted",
+			mock: func() {
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				req := pendingRequest()
+				req.Status = entity.CoinRequestStatusAccepted
+				reqRepo.EXPECT().GetByID(gomock.Any(), int64(10)).Return(req, nil)
+			},
+			err: entity.ErrCoinRequestNotPending,
+		},
+		{
+			name: "resolved concurrently",
+			mock: func() {
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				reqRepo.EXPECT().GetByID(gomock.Any(), int64(10)).Return(pendingRequest(), nil)
+				reqRepo.EXPECT().
+					Resolve(gomock.Any(), int64(10), entity.CoinRequestStatusAccepted).
+					Return(false, nil)
+			},
+			err: entity.ErrCoinRequestNotPending,
+		},
+		{
+			name: "expired",
+			mock: func() {
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				req := pendingRequest()
+				req.ExpiresAt = time.Now().Add(-time.Minute)
+				reqRepo.EXPECT().GetByID(gomock.Any(), int64(10)).Return(req, nil)
+			},
+			err: entity.ErrCoinRequestExpired,
+		},
+		{
+			name: "insufficient funds",
+			mock: func() {
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				reqRepo.EXPECT().GetByID(gomock.Any(), int64(10)).Return(pendingRequest(), nil)
+				reqRepo.EXPECT().
+					Resolve(gomock.Any(), int64(10), entity.CoinRequestStatusAccepted).
+					Return(true, nil)
+				transferer.EXPECT().
+					CreateDirectTransfer(gomock.Any(), int64(2), int64(1), int64(100), "pizza").
+					Return(entity.ErrInsufficientFunds)
+			},
+			err: entity.ErrInsufficientFunds,
+		},
+		{
+			name: "payment would wait for approval",
+			mock: func() {
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				reqRepo.EXPECT().GetByID(gomock.Any(), int64(10)).Return(pendingRequest(), nil)
+				reqRepo.EXPECT().
+					Resolve(gomock.Any(), int64(10), entity.CoinRequestStatusAccepted).
+					Return(true, nil)
+				// the transaction is rolled back, so the request stays pending
+				transferer.EXPECT().
+					CreateDirectTransfer(gomock.Any(), int64(2), int64(1), int64(100), "pizza").
+					Return(entity.ErrApprovalRequired)
+			},
+			err: entity.ErrApprovalRequired,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			err := uc.Accept(context.Background(), 2, 10)
+
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("only the payer can accept", func(t *testing.T) {
+		dbTransactor.EXPECT().
+			WithinTransaction(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+		reqRepo.EXPECT().GetByID(gomock.Any(), int64(10)).Return(pendingRequest(), nil)
+
+		err := uc.Accept(context.Background(), 1, 10)
+		require.ErrorIs(t, err, entity.ErrCoinRequestNotFound)
+	})
+}
+
+func TestDeclineAndCancel(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	reqRepo := mocks.NewMockRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	transferer := mocks.NewMockTransferer(ctrl)
+	dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+	uc := NewCoinRequestUC(reqRepo, userRepo, transferer, dbTransactor, time.Hour)
+
+	dbTransactor.EXPECT().
+		WithinTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		})
+	reqRepo.EXPECT().GetByID(gomock.Any(), int64(10)).Return(pendingRequest(), nil)
+	reqRepo.EXPECT().
+		Resolve(gomock.Any(), int64(10), entity.CoinRequestStatusDeclined).
+		Return(true, nil)
+
+	require.NoError(t, uc.Decline(context.Background(), 2, 10))
+
+	dbTransactor.EXPECT().
+		WithinTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		})
+	reqRepo.EXPECT().GetByID(gomock.Any(), int64(10)).Return(pendingRequest(), nil)
+	reqRepo.EXPECT().
+		Resolve(gomock.Any(), int64(10), entity.CoinRequestStatusCancelled).
+		Return(true, nil)
+
+	require.NoError(t, uc.Cancel(context.Background(), 1, 10))
+
+	dbTransactor.EXPECT().
+		WithinTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		})
+	reqRepo.EXPECT().GetByID(gomock.Any(), int64(10)).Return(pendingRequest(), nil)
+
+	err := uc.Cancel(context.Background(), 2, 10)
+	require.ErrorIs(t, err, entity.ErrCoinRequestNotFound)
+}
+
+func TestListIncoming(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	reqRepo := mocks.NewMockRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	transferer := mocks.NewMockTransferer(ctrl)
+	dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+	uc := NewCoinRequestUC(reqRepo, userRepo, transferer, dbTransactor, time.Hour)
+	req := pendingRequest()
+
+	reqRepo.EXPECT().ListIncoming(gomock.Any(), int64(2)).Return([]entity.CoinRequest{req}, nil)
+	userRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&entity.User{ID: 1, Username: "requester"}, nil)
+
+	list, err := uc.ListIncoming(context.Background(), 2)
+	require.NoError(t, err)
+	require.Equal(t, []CoinRequestInfo{
+		{
+			ID:        req.ID,
+			User:      "requester",
+			Amount:    req.Amount,
+			Reason:    req.Reason,
+			Status:    entity.CoinRequestStatusPending,
+			ExpiresAt: req.ExpiresAt,
+		},
+	}, list)
+}
@@ -0,0 +1,16 @@
+package coin_request_usecase
+
+import (
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"time"
+)
+
+type CoinRequestInfo struct {
+	ID        int64                    `json:"id"`
+	User      string                   `json:"user"`
+	Amount    int64                    `json:"amount"`
+	Reason    string                   `json:"reason"`
+	Status    entity.CoinRequestStatus `json:"status"`
+	ExpiresAt time.Time                `json:"expires_at"`
+	CreatedAt time.Time                `json:"created_at"`
+}
@@ -0,0 +1,32 @@
+package coin_request_usecase
+
+import (
+	"context"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"time"
+)
+
+//go:generate mockgen -source=interfaces.go -destination=./mocks/mocks.go -package=mocks
+
+type Repository interface {
+	Create(ctx context.Context, req *entity.CoinRequest) error
+	GetByID(ctx context.Context, id int64) (entity.CoinRequest, error)
+	Resolve(ctx context.Context, id int64, status entity.CoinRequestStatus) (bool, error)
+	ExpirePending(ctx context.Context, now time.Time) (int64, error)
+	ListIncoming(ctx context.Context, payerID int64) ([]entity.CoinRequest, error)
+	ListOutgoing(ctx context.Context, requesterID int64) ([]entity.CoinRequest, error)
+}
+
+type UserRepository interface {
+	GetByID(ctx context.Context, id int64) (*entity.User, error)
+}
+
+// Transferer executes the transfer once a request is accepted. It refuses a
+// transfer that would have to wait for approval.
+type Transferer interface {
+	CreateDirectTransfer(ctx context.Context, fromUserID, toUserID int64, amount int64, message string) error
+}
+
+type DBTransactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,238 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: interfaces.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	entity "github.com/smthjapanese/avito-merch/internal/entity"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockRepository) Create(ctx context.Context, req *entity.CoinRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRepositoryMockRecorder) Create(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository)(nil).Create), ctx, req)
+}
+
+// ExpirePending mocks base method.
+func (m *MockRepository) ExpirePending(ctx context.Context, now time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpirePending", ctx, now)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpirePending indicates an expected call of ExpirePending.
+func (mr *MockRepositoryMockRecorder) ExpirePending(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpirePending", reflect.TypeOf((*MockRepository)(nil).ExpirePending), ctx, now)
+}
+
+// GetByID mocks base method.
+func (m *MockRepository) GetByID(ctx context.Context, id int64) (entity.CoinRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(entity.CoinRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockRepositoryMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockRepository)(nil).GetByID), ctx, id)
+}
+
+// ListIncoming mocks base method.
+func (m *MockRepository) ListIncoming(ctx context.Context, payerID int64) ([]entity.CoinRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIncoming", ctx, payerID)
+	ret0, _ := ret[0].([]entity.CoinRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListIncoming indicates an expected call of ListIncoming.
+func (mr *MockRepositoryMockRecorder) ListIncoming(ctx, payerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIncoming", reflect.TypeOf((*MockRepository)(nil).ListIncoming), ctx, payerID)
+}
+
+// ListOutgoing mocks base method.
+func (m *MockRepository) ListOutgoing(ctx context.Context, requesterID int64) ([]entity.CoinRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOutgoing", ctx, requesterID)
+	ret0, _ := ret[0].([]entity.CoinRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOutgoing indicates an expected call of ListOutgoing.
+func (mr *MockRepositoryMockRecorder) ListOutgoing(ctx, requesterID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOutgoing", reflect.TypeOf((*MockRepository)(nil).ListOutgoing), ctx, requesterID)
+}
+
+// Resolve mocks base method.
+func (m *MockRepository) Resolve(ctx context.Context, id int64, status entity.CoinRequestStatus) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resolve", ctx, id, status)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Resolve indicates an expected call of Resolve.
+func (mr *MockRepositoryMockRecorder) Resolve(ctx, id, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockRepository)(nil).Resolve), ctx, id, status)
+}
+
+// MockUserRepository is a mock of UserRepository interface.
+type MockUserRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserRepositoryMockRecorder
+}
+
+// MockUserRepositoryMockRecorder is the mock recorder for MockUserRepository.
+type MockUserRepositoryMockRecorder struct {
+	mock *MockUserRepository
+}
+
+// NewMockUserRepository creates a new mock instance.
+func NewMockUserRepository(ctrl *gomock.Controller) *MockUserRepository {
+	mock := &MockUserRepository{ctrl: ctrl}
+	mock.recorder = &MockUserRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserRepository) EXPECT() *MockUserRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetByID mocks base method.
+func (m *MockUserRepository) GetByID(ctx context.Context, id int64) (*entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockUserRepositoryMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockUserRepository)(nil).GetByID), ctx, id)
+}
+
+// MockTransferer is a mock of Transferer interface.
+type MockTransferer struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransfererMockRecorder
+}
+
+// MockTransfererMockRecorder is the mock recorder for MockTransferer.
+type MockTransfererMockRecorder struct {
+	mock *MockTransferer
+}
+
+// NewMockTransferer creates a new mock instance.
+func NewMockTransferer(ctrl *gomock.Controller) *MockTransferer {
+	mock := &MockTransferer{ctrl: ctrl}
+	mock.recorder = &MockTransfererMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransferer) EXPECT() *MockTransfererMockRecorder {
+	return m.recorder
+}
+
+// CreateDirectTransfer mocks base method.
+func (m *MockTransferer) CreateDirectTransfer(ctx context.Context, fromUserID, toUserID, amount int64, message string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDirectTransfer", ctx, fromUserID, toUserID, amount, message)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateDirectTransfer indicates an expected call of CreateDirectTransfer.
+func (mr *MockTransfererMockRecorder) CreateDirectTransfer(ctx, fromUserID, toUserID, amount, message interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDirectTransfer", reflect.TypeOf((*MockTransferer)(nil).CreateDirectTransfer), ctx, fromUserID, toUserID, amount, message)
+}
+
+// MockDBTransactor is a mock of DBTransactor interface.
+type MockDBTransactor struct {
+	ctrl     *gomock.Controller
+	recorder *MockDBTransactorMockRecorder
+}
+
+// MockDBTransactorMockRecorder is the mock recorder for MockDBTransactor.
+type MockDBTransactorMockRecorder struct {
+	mock *MockDBTransactor
+}
+
+// NewMockDBTransactor creates a new mock instance.
+func NewMockDBTransactor(ctrl *gomock.Controller) *MockDBTransactor {
+	mock := &MockDBTransactor{ctrl: ctrl}
+	mock.recorder = &MockDBTransactorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDBTransactor) EXPECT() *MockDBTransactorMockRecorder {
+	return m.recorder
+}
+
+// WithinTransaction mocks base method.
+func (m *MockDBTransactor) WithinTransaction(ctx context.Context, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithinTransaction", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithinTransaction indicates an expected call of WithinTransaction.
+func (mr *MockDBTransactorMockRecorder) WithinTransaction(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithinTransaction", reflect.TypeOf((*MockDBTransactor)(nil).WithinTransaction), ctx, fn)
+}
@@ -6,22 +6,110 @@ import (
 )
 
 type MerchItemDTO struct {
-	ID    int64  `json:"id"`
-	Name  string `json:"name"`
-	Price int64  `json:"price"`
+	ID          int64    `json:"id"`
+	Name        string   `json:"name"`
+	Price       int64    `json:"price"`
+	Category    string   `json:"category,omitempty"`
+	Description string   `json:"description,omitempty"`
+	ImageURL    *string  `json:"image_url,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	// DisplayName and Description are in Locale when the catalog was
+	// localized; Name is left as is.
+	DisplayName string `json:"display_name,omitempty"`
+	Locale      string `json:"locale,omitempty"`
+}
+
+// CatalogRequest filters, orders and pages the catalog. Empty fields leave
+// the corresponding filter out; pages are numbered from 1.
+type CatalogRequest struct {
+	Query      string           `json:"query"`
+	CategoryID *int64           `json:"category_id,omitempty"`
+	Tags       []string         `json:"tags,omitempty"`
+	MinPrice   *int64           `json:"min_price,omitempty" validate:"omitempty,min=0"`
+	MaxPrice   *int64           `json:"max_price,omitempty" validate:"omitempty,min=0"`
+	Sort       entity.MerchSort `json:"sort,omitempty"`
+	Page       int              `json:"page,omitempty" validate:"omitempty,min=1"`
+	PageSize   int              `json:"page_size,omitempty" validate:"omitempty,min=1,max=100"`
+}
+
+type CatalogPage struct {
+	Items    []MerchItemDTO `json:"items"`
+	Total    int64          `json:"total"`
+	Page     int            `json:"page"`
+	PageSize int            `json:"page_size"`
+}
+
+// PriceHistory lists an item's prices, oldest first, including the changes
+// scheduled for the future.
+type PriceHistory struct {
+	ItemID  int64           `json:"item_id"`
+	Name    string          `json:"name"`
+	Current int64           `json:"current_price"`
+	Prices  []PriceEntryDTO `json:"prices"`
+}
+
+type PriceEntryDTO struct {
+	ID            int64     `json:"id"`
+	Price         int64     `json:"price"`
+	EffectiveFrom time.Time `json:"effective_from"`
+	Scheduled     bool      `json:"scheduled"`
+}
+
+// CreateBundleRequest defines a bundle sold at Price. Components of items
+// that come in variants may leave VariantID out to let the buyer pick one.
+type CreateBundleRequest struct {
+	Name        string              `json:"name" validate:"required"`
+	Price       int64               `json:"price" validate:"required,min=1"`
+	Description string              `json:"description,omitempty"`
+	Items       []BundleItemRequest `json:"items" validate:"required,min=1,dive"`
+}
+
+type BundleItemRequest struct {
+	ItemName  string `json:"item" validate:"required"`
+	VariantID *int64 `json:"variant_id,omitempty"`
+	Quantity  int64  `json:"quantity" validate:"required,min=1"`
+}
+
+// BuyBundleRequest buys a bundle. Variants picks, by item name, the variant
+// of every component the bundle leaves open.
+type BuyBundleRequest struct {
+	Bundle   string           `json:"bundle" validate:"required"`
+	Variants map[string]int64 `json:"variants,omitempty"`
 }
 
 type UserDTO struct {
-	ID        int64     `json:"id"`
-	Username  string    `json:"username"`
-	Coins     int64     `json:"coins"`
-	CreatedAt time.Time `json:"created_at"`
+	ID          int64     `json:"id"`
+	Username    string    `json:"username"`
+	Coins       int64     `json:"coins"`
+	HeldCoins   int64     `json:"held_coins"`
+	PublicKudos bool      `json:"public_kudos"`
+	CreatedAt   time.Time `json:"created_at"`
 }
 
 type UserProfileDTO struct {
 	User      UserDTO            `json:"user"`
 	Inventory []InventoryItemDTO `json:"inventory"`
 	History   TransactionHistory `json:"history"`
+	Expiring  []ExpiringCoinsDTO `json:"expiring_coins"`
+	Orders    []OrderDTO         `json:"orders"`
+}
+
+// OrderDTO is the fulfilment status of a purchase as shown in the profile.
+type OrderDTO struct {
+	ID        int64              `json:"id"`
+	ItemName  string             `json:"name"`
+	Price     int64              `json:"price"`
+	Status    entity.OrderStatus `json:"status"`
+	Location  string             `json:"location,omitempty"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+// ExpiringCoinsDTO is an amount of coins that will be swept at ExpiresAt
+// unless spent first.
+type ExpiringCoinsDTO struct {
+	Amount    int64     `json:"amount"`
+	ExpiresAt time.Time `json:"expires_at"`
 }
 
 type UserRegisterRequest struct {
@@ -34,8 +122,13 @@ type UserUpdateDTO struct {
 }
 
 type InventoryItemDTO struct {
-	ItemID      int64     `json:"item_id"`
-	ItemName    string    `json:"name"`
+	ItemID    int64  `json:"item_id"`
+	ItemName  string `json:"name"`
+	VariantID *int64 `json:"variant_id,omitempty"`
+	// Variant is the variant label, e.g. "L" or "L, black".
+	Variant string `json:"variant,omitempty"`
+	// Title is how the row reads in the profile, e.g. "hoody (L) x2".
+	Title       string    `json:"title"`
 	Quantity    int64     `json:"quantity"`
 	PurchasedAt time.Time `json:"purchased_at"`
 }
@@ -50,5 +143,16 @@ type TransactionInfo struct {
 	Amount    int64                  `json:"amount"`
 	Type      entity.TransactionType `json:"type"`
 	ItemName  *string                `json:"item_name,omitempty"`
+	Quantity  *int64                 `json:"quantity,omitempty"`
+	Message   *string                `json:"message,omitempty"`
 	CreatedAt time.Time              `json:"created_at"`
 }
+
+// MerchTranslationRequest is an admin's translation of an item into one
+// locale.
+type MerchTranslationRequest struct {
+	ItemName    string `json:"item_name" validate:"required"`
+	Locale      string `json:"locale" validate:"required"`
+	Name        string `json:"name" validate:"required"`
+	Description string `json:"description,omitempty"`
+}
@@ -0,0 +1,12 @@
+package fraud_usecase
+
+import (
+	"github.com/smthjapanese/avito-merch/internal/entity"
+)
+
+// ReplayResult is a recorded transaction the rules would have fired on.
+type ReplayResult struct {
+	Transaction entity.Transaction    `json:"transaction"`
+	Action      entity.FraudAction    `json:"action"`
+	Verdicts    []entity.FraudVerdict `json:"verdicts"`
+}
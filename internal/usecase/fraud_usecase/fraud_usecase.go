@@ -0,0 +1,269 @@
+package fraud_usecase
+
+import (
+	"context"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"time"
+)
+
+// FraudUC runs the fraud rules on transfers and purchases before they are
+// executed and keeps the resulting flags for admins to review.
+type FraudUC struct {
+	flagRepo FlagRepository
+	txRepo   TransactionRepository
+	userRepo UserRepository
+	dbTx     DBTransactor
+	rules    []Rule
+	cfg      Config
+}
+
+func NewFraudUC(
+	flagRepo FlagRepository,
+	txRepo TransactionRepository,
+	userRepo UserRepository,
+	dbTx DBTransactor,
+	cfg Config,
+) *FraudUC {
+	return &FraudUC{
+		flagRepo: flagRepo,
+		txRepo:   txRepo,
+		userRepo: userRepo,
+		dbTx:     dbTx,
+		rules:    NewRules(cfg),
+		cfg:      cfg,
+	}
+}
+
+// Check evaluates the rules on an operation about to be executed, stores a
+// flag for every rule that fired and returns the action to take.
+func (uc *FraudUC) Check(ctx context.Context, op entity.FraudOperation) (entity.FraudAction, error) {
+	if op.At.IsZero() {
+		op.At = time.Now()
+	}
+
+	sender, err := uc.userRepo.GetByID(ctx, op.FromUserID)
+	if err != nil {
+		return entity.FraudActionNone, err
+	}
+	if sender == nil {
+		return entity.FraudActionNone, entity.ErrUserNotFound
+	}
+
+	outgoing, err := uc.txRepo.ListOutgoingSince(ctx, op.FromUserID, op.At.Add(-uc.cfg.Lookback))
+	if err != nil {
+		return entity.FraudActionNone, err
+	}
+
+	// A ring closed by this transfer leads from the recipient back to the
+	// sender, so only transfers reachable from the recipient matter.
+	var recent []entity.Transaction
+	if op.Type == entity.TransactionTypeTransfer && uc.cfg.MaxRingSize >= 2 {
+		recent, err = uc.txRepo.ListTransfersReachable(ctx, op.ToUserID, op.At.Add(-uc.cfg.RingWindow), uc.cfg.MaxRingSize-1)
+		if err != nil {
+			return entity.FraudActionNone, err
+		}
+	}
+
+	verdicts, action := Evaluate(uc.rules, Input{
+		Op:       op,
+		Sender:   *sender,
+		Outgoing: outgoing,
+		Recent:   recent,
+	})
+	if len(verdicts) == 0 {
+		return entity.FraudActionNone, nil
+	}
+
+	err = uc.dbTx.WithinTransaction(ctx, func(ctx context.Context) error {
+		for _, verdict := range verdicts {
+			flag := &entity.FraudFlag{
+				UserID: op.FromUserID,
+				ItemID: op.ItemID,
+				Type:   op.Type,
+				Amount: op.Amount,
+				Rule:   verdict.Rule,
+				Action: verdict.Action.String(),
+				Reason: verdict.Reason,
+				Status: entity.FraudFlagStatusOpen,
+			}
+			if op.Type == entity.TransactionTypeTransfer {
+				counterpartID := op.ToUserID
+				flag.CounterpartID = &counterpartID
+			}
+
+			if err := uc.flagRepo.Create(ctx, flag); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return entity.FraudActionNone, err
+	}
+
+	return action, nil
+}
+
+func (uc *FraudUC) ListFlags(ctx context.Context, adminID int64, status entity.FraudFlagStatus) ([]entity.FraudFlag, error) {
+	if err := uc.requireAdmin(ctx, adminID); err != nil {
+		return nil, err
+	}
+
+	return uc.flagRepo.List(ctx, status)
+}
+
+// ReviewFlag closes an open flag as confirmed or dismissed.
+func (uc *FraudUC) ReviewFlag(ctx context.Context, adminID, id int64, status entity.FraudFlagStatus) error {
+	if status != entity.FraudFlagStatusConfirmed && status != entity.FraudFlagStatusDismissed {
+		return entity.ErrInvalidFraudFlagState
+	}
+
+	return uc.dbTx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := uc.requireAdmin(ctx, adminID); err != nil {
+			return err
+		}
+
+		flag, err := uc.flagRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if flag.Status != entity.FraudFlagStatusOpen {
+			return entity.ErrFraudFlagNotOpen
+		}
+
+		reviewed, err := uc.flagRepo.Review(ctx, id, status, adminID)
+		if err != nil {
+			return err
+		}
+		if !reviewed {
+			return entity.ErrFraudFlagNotOpen
+		}
+
+		return nil
+	})
+}
+
+// Replay runs the rules against the transfers and purchases recorded since
+// the given time, as if each were about to be executed, and returns the ones
+// the rules fire on. Nothing is stored, so it is safe for trying out rule
+// settings on real data.
+func (uc *FraudUC) Replay(ctx context.Context, adminID int64, since time.Time) ([]ReplayResult, error) {
+	if err := uc.requireAdmin(ctx, adminID); err != nil {
+		return nil, err
+	}
+
+	history := uc.cfg.Lookback
+	if uc.cfg.RingWindow > history {
+		history = uc.cfg.RingWindow
+	}
+
+	txs, err := uc.txRepo.ListSince(ctx, since.Add(-history))
+	if err != nil {
+		return nil, err
+	}
+
+	// Balances are rebuilt by undoing transactions from the newest one
+	// back, starting from what users hold now.
+	users := make(map[int64]*entity.User)
+	user := func(id int64) (*entity.User, error) {
+		if u, ok := users[id]; ok {
+			return u, nil
+		}
+		u, err := uc.userRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if u == nil {
+			return nil, entity.ErrUserNotFound
+		}
+		users[id] = u
+		return u, nil
+	}
+
+	var results []ReplayResult
+	for i := len(txs) - 1; i >= 0; i-- {
+		tx := txs[i]
+
+		sender, err := user(tx.FromUserID)
+		if err != nil {
+			return nil, err
+		}
+		sender.Coins += tx.Amount
+		if tx.Type != entity.TransactionTypePurchase {
+			recipient, err := user(tx.ToUserID)
+			if err != nil {
+				return nil, err
+			}
+			recipient.Coins -= tx.Amount
+		}
+
+		if tx.CreatedAt.Before(since) {
+			continue
+		}
+		if tx.Type != entity.TransactionTypeTransfer && tx.Type != entity.TransactionTypePurchase {
+			continue
+		}
+
+		verdicts, action := Evaluate(uc.rules, replayInput(txs[:i], tx, *sender, uc.cfg))
+		if len(verdicts) == 0 {
+			continue
+		}
+
+		results = append(results, ReplayResult{
+			Transaction: tx,
+			Action:      action,
+			Verdicts:    verdicts,
+		})
+	}
+
+	// Oldest first, like the transactions themselves.
+	for i, j := 0, len(results)-1; i < j; i, j = i+1, j-1 {
+		results[i], results[j] = results[j], results[i]
+	}
+
+	return results, nil
+}
+
+// replayInput builds the rule input for a recorded transaction from the
+// transactions recorded before it.
+func replayInput(earlier []entity.Transaction, tx entity.Transaction, sender entity.User, cfg Config) Input {
+	in := Input{
+		Op: entity.FraudOperation{
+			Type:       tx.Type,
+			FromUserID: tx.FromUserID,
+			ToUserID:   tx.ToUserID,
+			ItemID:     tx.ItemID,
+			Amount:     tx.Amount,
+			At:         tx.CreatedAt,
+		},
+		Sender: sender,
+	}
+
+	lookback := tx.CreatedAt.Add(-cfg.Lookback)
+	ringWindow := tx.CreatedAt.Add(-cfg.RingWindow)
+
+	for _, prev := range earlier {
+		if prev.FromUserID == tx.FromUserID && !prev.CreatedAt.Before(lookback) {
+			in.Outgoing = append(in.Outgoing, prev)
+		}
+		if tx.Type == entity.TransactionTypeTransfer &&
+			prev.Type == entity.TransactionTypeTransfer &&
+			!prev.CreatedAt.Before(ringWindow) {
+			in.Recent = append(in.Recent, prev)
+		}
+	}
+
+	return in
+}
+
+func (uc *FraudUC) requireAdmin(ctx context.Context, adminID int64) error {
+	admin, err := uc.userRepo.GetByID(ctx, adminID)
+	if err != nil {
+		return err
+	}
+	if admin == nil || !admin.IsAdmin() {
+		return entity.ErrForbidden
+	}
+
+	return nil
+}
@@ -0,0 +1,214 @@
+package fraud_usecase
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/usecase/fraud_usecase/mocks"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type test struct {
+	name string
+	mock func()
+	res  interface{}
+	err  error
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	flagRepo := mocks.NewMockFlagRepository(ctrl)
+	txRepo := mocks.NewMockTransactionRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+	uc := NewFraudUC(flagRepo, txRepo, userRepo, dbTransactor, testConfig)
+
+	tests := []test{
+		{
+			name: "clean transfer",
+			mock: func() {
+				user := veteran()
+				userRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&user, nil)
+				txRepo.EXPECT().ListOutgoingSince(gomock.Any(), int64(1), now.Add(-testConfig.Lookback)).Return(nil, nil)
+				txRepo.EXPECT().
+					ListTransfersReachable(gomock.Any(), int64(2), now.Add(-testConfig.RingWindow), testConfig.MaxRingSize-1).
+					Return([]entity.Transaction{transfer(3, 1, 100, time.Hour)}, nil)
+			},
+			res: entity.FraudActionNone,
+		},
+		{
+			name: "ring is held and flagged",
+			mock: func() {
+				user := veteran()
+				userRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&user, nil)
+				txRepo.EXPECT().ListOutgoingSince(gomock.Any(), int64(1), gomock.Any()).Return(nil, nil)
+				txRepo.EXPECT().
+					ListTransfersReachable(gomock.Any(), int64(2), gomock.Any(), gomock.Any()).
+					Return([]entity.Transaction{transfer(2, 1, 100, time.Hour)}, nil)
+
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				flagRepo.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, flag *entity.FraudFlag) error {
+						require.Equal(t, "ring", flag.Rule)
+						require.Equal(t, "hold", flag.Action)
+						require.Equal(t, entity.FraudFlagStatusOpen, flag.Status)
+						require.Equal(t, int64(2), *flag.CounterpartID)
+						return nil
+					})
+			},
+			res: entity.FraudActionHold,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			action, err := uc.Check(context.Background(), transferOp(1, 2, 100))
+
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.res, action)
+			}
+		})
+	}
+}
+
+func TestReplay(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	flagRepo := mocks.NewMockFlagRepository(ctrl)
+	txRepo := mocks.NewMockTransactionRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+	uc := NewFraudUC(flagRepo, txRepo, userRepo, dbTransactor, testConfig)
+	since := now.Add(-24 * time.Hour)
+
+	// A farmed account was created an hour ago with the default balance,
+	// received nothing and sent almost everything to user 2, who then sent
+	// some of it back to user 3.
+	recorded := []entity.Transaction{
+		transfer(3, 2, 900, 40*time.Minute),
+		transfer(2, 3, 100, 20*time.Minute),
+	}
+	recorded[0].ID, recorded[1].ID = 1, 2
+
+	userRepo.EXPECT().
+		GetByID(gomock.Any(), int64(10)).
+		Return(&entity.User{ID: 10, Role: entity.UserRoleAdmin}, nil)
+	txRepo.EXPECT().ListSince(gomock.Any(), since.Add(-testConfig.Lookback)).Return(recorded, nil)
+	userRepo.EXPECT().
+		GetByID(gomock.Any(), int64(2)).
+		Return(&entity.User{ID: 2, Coins: 1800, CreatedAt: now.AddDate(-1, 0, 0)}, nil)
+	userRepo.EXPECT().
+		GetByID(gomock.Any(), int64(3)).
+		Return(&entity.User{ID: 3, Coins: 200, CreatedAt: now.Add(-time.Hour)}, nil)
+
+	results, err := uc.Replay(context.Background(), 10, since)
+	require.NoError(t, err)
+
+	require.Len(t, results, 2)
+
+	require.Equal(t, int64(1), results[0].Transaction.ID)
+	require.Equal(t, entity.FraudActionHold, results[0].Action)
+	require.Equal(t, "new_account_drain", results[0].Verdicts[0].Rule)
+
+	require.Equal(t, int64(2), results[1].Transaction.ID)
+	require.Equal(t, "ring", results[1].Verdicts[0].Rule)
+}
+
+func TestReviewFlag(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	flagRepo := mocks.NewMockFlagRepository(ctrl)
+	txRepo := mocks.NewMockTransactionRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+	uc := NewFraudUC(flagRepo, txRepo, userRepo, dbTransactor, testConfig)
+
+	tests := []test{
+		{
+			name: "confirm",
+			mock: func() {
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				userRepo.EXPECT().GetByID(gomock.Any(), int64(10)).Return(&entity.User{ID: 10, Role: entity.UserRoleAdmin}, nil)
+				flagRepo.EXPECT().GetByID(gomock.Any(), int64(5)).Return(entity.FraudFlag{ID: 5, Status: entity.FraudFlagStatusOpen}, nil)
+				flagRepo.EXPECT().Review(gomock.Any(), int64(5), entity.FraudFlagStatusConfirmed, int64(10)).Return(true, nil)
+			},
+			res: entity.FraudFlagStatusConfirmed,
+		},
+		{
+			name: "already reviewed",
+			mock: func() {
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				userRepo.EXPECT().GetByID(gomock.Any(), int64(10)).Return(&entity.User{ID: 10, Role: entity.UserRoleAdmin}, nil)
+				flagRepo.EXPECT().GetByID(gomock.Any(), int64(5)).Return(entity.FraudFlag{ID: 5, Status: entity.FraudFlagStatusDismissed}, nil)
+			},
+			res: entity.FraudFlagStatusConfirmed,
+			err: entity.ErrFraudFlagNotOpen,
+		},
+		{
+			name: "back to open",
+			mock: func() {},
+			res:  entity.FraudFlagStatusOpen,
+			err:  entity.ErrInvalidFraudFlagState,
+		},
+		{
+			name: "not an admin",
+			mock: func() {
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				userRepo.EXPECT().GetByID(gomock.Any(), int64(10)).Return(&entity.User{ID: 10, Role: entity.UserRoleManager}, nil)
+			},
+			res: entity.FraudFlagStatusDismissed,
+			err: entity.ErrForbidden,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			err := uc.ReviewFlag(context.Background(), 10, 5, tc.res.(entity.FraudFlagStatus))
+
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
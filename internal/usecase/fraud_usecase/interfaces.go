@@ -0,0 +1,30 @@
+package fraud_usecase
+
+import (
+	"context"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"time"
+)
+
+//go:generate mockgen -source=interfaces.go -destination=./mocks/mocks.go -package=mocks
+
+type FlagRepository interface {
+	Create(ctx context.Context, flag *entity.FraudFlag) error
+	GetByID(ctx context.Context, id int64) (entity.FraudFlag, error)
+	List(ctx context.Context, status entity.FraudFlagStatus) ([]entity.FraudFlag, error)
+	Review(ctx context.Context, id int64, status entity.FraudFlagStatus, reviewerID int64) (bool, error)
+}
+
+type TransactionRepository interface {
+	ListSince(ctx context.Context, since time.Time) ([]entity.Transaction, error)
+	ListOutgoingSince(ctx context.Context, fromUserID int64, since time.Time) ([]entity.Transaction, error)
+	ListTransfersReachable(ctx context.Context, userID int64, since time.Time, maxHops int) ([]entity.Transaction, error)
+}
+
+type UserRepository interface {
+	GetByID(ctx context.Context, id int64) (*entity.User, error)
+}
+
+type DBTransactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,239 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: interfaces.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	entity "github.com/smthjapanese/avito-merch/internal/entity"
+)
+
+// MockFlagRepository is a mock of FlagRepository interface.
+type MockFlagRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFlagRepositoryMockRecorder
+}
+
+// MockFlagRepositoryMockRecorder is the mock recorder for MockFlagRepository.
+type MockFlagRepositoryMockRecorder struct {
+	mock *MockFlagRepository
+}
+
+// NewMockFlagRepository creates a new mock instance.
+func NewMockFlagRepository(ctrl *gomock.Controller) *MockFlagRepository {
+	mock := &MockFlagRepository{ctrl: ctrl}
+	mock.recorder = &MockFlagRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFlagRepository) EXPECT() *MockFlagRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockFlagRepository) Create(ctx context.Context, flag *entity.FraudFlag) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, flag)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockFlagRepositoryMockRecorder) Create(ctx, flag interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockFlagRepository)(nil).Create), ctx, flag)
+}
+
+// GetByID mocks base method.
+func (m *MockFlagRepository) GetByID(ctx context.Context, id int64) (entity.FraudFlag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(entity.FraudFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockFlagRepositoryMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockFlagRepository)(nil).GetByID), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockFlagRepository) List(ctx context.Context, status entity.FraudFlagStatus) ([]entity.FraudFlag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, status)
+	ret0, _ := ret[0].([]entity.FraudFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockFlagRepositoryMockRecorder) List(ctx, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockFlagRepository)(nil).List), ctx, status)
+}
+
+// Review mocks base method.
+func (m *MockFlagRepository) Review(ctx context.Context, id int64, status entity.FraudFlagStatus, reviewerID int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Review", ctx, id, status, reviewerID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Review indicates an expected call of Review.
+func (mr *MockFlagRepositoryMockRecorder) Review(ctx, id, status, reviewerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Review", reflect.TypeOf((*MockFlagRepository)(nil).Review), ctx, id, status, reviewerID)
+}
+
+// MockTransactionRepository is a mock of TransactionRepository interface.
+type MockTransactionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactionRepositoryMockRecorder
+}
+
+// MockTransactionRepositoryMockRecorder is the mock recorder for MockTransactionRepository.
+type MockTransactionRepositoryMockRecorder struct {
+	mock *MockTransactionRepository
+}
+
+// NewMockTransactionRepository creates a new mock instance.
+func NewMockTransactionRepository(ctrl *gomock.Controller) *MockTransactionRepository {
+	mock := &MockTransactionRepository{ctrl: ctrl}
+	mock.recorder = &MockTransactionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactionRepository) EXPECT() *MockTransactionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ListOutgoingSince mocks base method.
+func (m *MockTransactionRepository) ListOutgoingSince(ctx context.Context, fromUserID int64, since time.Time) ([]entity.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOutgoingSince", ctx, fromUserID, since)
+	ret0, _ := ret[0].([]entity.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOutgoingSince indicates an expected call of ListOutgoingSince.
+func (mr *MockTransactionRepositoryMockRecorder) ListOutgoingSince(ctx, fromUserID, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOutgoingSince", reflect.TypeOf((*MockTransactionRepository)(nil).ListOutgoingSince), ctx, fromUserID, since)
+}
+
+// ListSince mocks base method.
+func (m *MockTransactionRepository) ListSince(ctx context.Context, since time.Time) ([]entity.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSince", ctx, since)
+	ret0, _ := ret[0].([]entity.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSince indicates an expected call of ListSince.
+func (mr *MockTransactionRepositoryMockRecorder) ListSince(ctx, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSince", reflect.TypeOf((*MockTransactionRepository)(nil).ListSince), ctx, since)
+}
+
+// ListTransfersReachable mocks base method.
+func (m *MockTransactionRepository) ListTransfersReachable(ctx context.Context, userID int64, since time.Time, maxHops int) ([]entity.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransfersReachable", ctx, userID, since, maxHops)
+	ret0, _ := ret[0].([]entity.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransfersReachable indicates an expected call of ListTransfersReachable.
+func (mr *MockTransactionRepositoryMockRecorder) ListTransfersReachable(ctx, userID, since, maxHops interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransfersReachable", reflect.TypeOf((*MockTransactionRepository)(nil).ListTransfersReachable), ctx, userID, since, maxHops)
+}
+
+// MockUserRepository is a mock of UserRepository interface.
+type MockUserRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserRepositoryMockRecorder
+}
+
+// MockUserRepositoryMockRecorder is the mock recorder for MockUserRepository.
+type MockUserRepositoryMockRecorder struct {
+	mock *MockUserRepository
+}
+
+// NewMockUserRepository creates a new mock instance.
+func NewMockUserRepository(ctrl *gomock.Controller) *MockUserRepository {
+	mock := &MockUserRepository{ctrl: ctrl}
+	mock.recorder = &MockUserRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserRepository) EXPECT() *MockUserRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetByID mocks base method.
+func (m *MockUserRepository) GetByID(ctx context.Context, id int64) (*entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockUserRepositoryMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockUserRepository)(nil).GetByID), ctx, id)
+}
+
+// MockDBTransactor is a mock of DBTransactor interface.
+type MockDBTransactor struct {
+	ctrl     *gomock.Controller
+	recorder *MockDBTransactorMockRecorder
+}
+
+// MockDBTransactorMockRecorder is the mock recorder for MockDBTransactor.
+type MockDBTransactorMockRecorder struct {
+	mock *MockDBTransactor
+}
+
+// NewMockDBTransactor creates a new mock instance.
+func NewMockDBTransactor(ctrl *gomock.Controller) *MockDBTransactor {
+	mock := &MockDBTransactor{ctrl: ctrl}
+	mock.recorder = &MockDBTransactorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDBTransactor) EXPECT() *MockDBTransactorMockRecorder {
+	return m.recorder
+}
+
+// WithinTransaction mocks base method.
+func (m *MockDBTransactor) WithinTransaction(ctx context.Context, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithinTransaction", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithinTransaction indicates an expected call of WithinTransaction.
+func (mr *MockDBTransactorMockRecorder) WithinTransaction(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithinTransaction", reflect.TypeOf((*MockDBTransactor)(nil).WithinTransaction), ctx, fn)
+}
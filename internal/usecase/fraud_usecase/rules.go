@@ -0,0 +1,283 @@
+package fraud_usecase
+
+import (
+	"fmt"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"strings"
+	"time"
+)
+
+// Input is everything a rule gets to look at. Rules never query the database
+// themselves, so they can be run the same way against live operations and
+// against recorded transactions.
+type Input struct {
+	Op entity.FraudOperation
+	// Sender is the user behind the operation, with their balance as it was
+	// right before it.
+	Sender entity.User
+	// Outgoing holds the sender's earlier transactions within the lookback
+	// window, oldest first.
+	Outgoing []entity.Transaction
+	// Recent holds transfers within the ring window that a ring closed by the
+	// operation could be made of.
+	Recent []entity.Transaction
+}
+
+// Rule inspects one operation and returns a verdict when it fires.
+type Rule interface {
+	Name() string
+	Evaluate(in Input) (entity.FraudVerdict, bool)
+}
+
+type Config struct {
+	// Lookback is how far back the sender's own history goes.
+	Lookback time.Duration
+
+	VelocityWindow time.Duration
+	VelocityFlagAt int
+	VelocityHoldAt int
+
+	RingWindow  time.Duration
+	MaxRingSize int
+
+	NewAccountAge time.Duration
+	DrainPercent  int64
+
+	UnusualFactor     int64
+	UnusualMinSamples int
+}
+
+// NewRules builds the default rule set.
+func NewRules(cfg Config) []Rule {
+	return []Rule{
+		VelocityRule{Window: cfg.VelocityWindow, FlagAt: cfg.VelocityFlagAt, HoldAt: cfg.VelocityHoldAt},
+		RingRule{Window: cfg.RingWindow, MaxSize: cfg.MaxRingSize},
+		NewAccountDrainRule{MaxAge: cfg.NewAccountAge, Percent: cfg.DrainPercent},
+		UnusualAmountRule{Factor: cfg.UnusualFactor, MinSamples: cfg.UnusualMinSamples},
+	}
+}
+
+// Evaluate runs every rule and returns the verdicts of those that fired
+// along with the strongest action among them.
+func Evaluate(rules []Rule, in Input) ([]entity.FraudVerdict, entity.FraudAction) {
+	var verdicts []entity.FraudVerdict
+	action := entity.FraudActionNone
+
+	for _, rule := range rules {
+		verdict, fired := rule.Evaluate(in)
+		if !fired {
+			continue
+		}
+		verdict.Rule = rule.Name()
+		verdicts = append(verdicts, verdict)
+		if verdict.Action > action {
+			action = verdict.Action
+		}
+	}
+
+	// A purchase cannot wait for an approver the way a transfer does.
+	if in.Op.Type == entity.TransactionTypePurchase && action == entity.FraudActionHold {
+		action = entity.FraudActionBlock
+	}
+
+	return verdicts, action
+}
+
+// VelocityRule fires when a user makes too many operations in a short
+// window.
+type VelocityRule struct {
+	Window time.Duration
+	FlagAt int
+	HoldAt int
+}
+
+func (r VelocityRule) Name() string {
+	return "velocity"
+}
+
+func (r VelocityRule) Evaluate(in Input) (entity.FraudVerdict, bool) {
+	if r.FlagAt <= 0 {
+		return entity.FraudVerdict{}, false
+	}
+
+	since := in.Op.At.Add(-r.Window)
+	count := 1 // the operation itself
+	for _, tx := range in.Outgoing {
+		if !tx.CreatedAt.Before(since) {
+			count++
+		}
+	}
+
+	reason := fmt.Sprintf("%d operations within %s", count, r.Window)
+	switch {
+	case r.HoldAt > 0 && count >= r.HoldAt:
+		return entity.FraudVerdict{Action: entity.FraudActionHold, Reason: reason}, true
+	case count >= r.FlagAt:
+		return entity.FraudVerdict{Action: entity.FraudActionFlag, Reason: reason}, true
+	default:
+		return entity.FraudVerdict{}, false
+	}
+}
+
+// RingRule fires when a transfer closes a cycle of recent transfers among a
+// small group, which is how coins get pumped around to inflate leaderboards.
+type RingRule struct {
+	Window  time.Duration
+	MaxSize int
+}
+
+func (r RingRule) Name() string {
+	return "ring"
+}
+
+func (r RingRule) Evaluate(in Input) (entity.FraudVerdict, bool) {
+	if in.Op.Type != entity.TransactionTypeTransfer || r.MaxSize < 2 {
+		return entity.FraudVerdict{}, false
+	}
+
+	since := in.Op.At.Add(-r.Window)
+	edges := make(map[int64][]int64)
+	for _, tx := range in.Recent {
+		if tx.Type != entity.TransactionTypeTransfer || tx.CreatedAt.Before(since) {
+			continue
+		}
+		edges[tx.FromUserID] = append(edges[tx.FromUserID], tx.ToUserID)
+	}
+
+	// Look for a way back from the recipient to the sender that, together
+	// with this transfer, involves at most MaxSize users.
+	path := findPath(edges, in.Op.ToUserID, in.Op.FromUserID, r.MaxSize-1)
+	if path == nil {
+		return entity.FraudVerdict{}, false
+	}
+
+	ring := make([]string, 0, len(path)+1)
+	ring = append(ring, fmt.Sprint(in.Op.FromUserID))
+	for _, id := range path {
+		ring = append(ring, fmt.Sprint(id))
+	}
+
+	return entity.FraudVerdict{
+		Action: entity.FraudActionHold,
+		Reason: "transfer ring between users " + strings.Join(ring, " -> "),
+	}, true
+}
+
+// findPath does a breadth-first search from one user to another using at
+// most maxEdges transfers and returns the users visited, both ends included.
+func findPath(edges map[int64][]int64, from, to int64, maxEdges int) []int64 {
+	prev := map[int64]int64{from: from}
+	frontier := []int64{from}
+
+	for depth := 0; depth < maxEdges && len(frontier) > 0; depth++ {
+		var next []int64
+		for _, node := range frontier {
+			for _, neighbour := range edges[node] {
+				if _, seen := prev[neighbour]; seen {
+					continue
+				}
+				prev[neighbour] = node
+				if neighbour == to {
+					return walkBack(prev, from, to)
+				}
+				next = append(next, neighbour)
+			}
+		}
+		frontier = next
+	}
+
+	return nil
+}
+
+func walkBack(prev map[int64]int64, from, to int64) []int64 {
+	path := []int64{to}
+	for node := to; node != from; {
+		node = prev[node]
+		path = append([]int64{node}, path...)
+	}
+	return path
+}
+
+// NewAccountDrainRule fires when a freshly created account tries to send
+// most of its balance away.
+type NewAccountDrainRule struct {
+	MaxAge  time.Duration
+	Percent int64
+}
+
+func (r NewAccountDrainRule) Name() string {
+	return "new_account_drain"
+}
+
+func (r NewAccountDrainRule) Evaluate(in Input) (entity.FraudVerdict, bool) {
+	if in.Op.Type != entity.TransactionTypeTransfer || r.Percent <= 0 || in.Sender.Coins <= 0 {
+		return entity.FraudVerdict{}, false
+	}
+	if in.Op.At.Sub(in.Sender.CreatedAt) >= r.MaxAge {
+		return entity.FraudVerdict{}, false
+	}
+	if in.Op.Amount*100 < in.Sender.Coins*r.Percent {
+		return entity.FraudVerdict{}, false
+	}
+
+	return entity.FraudVerdict{
+		Action: entity.FraudActionHold,
+		Reason: fmt.Sprintf("account younger than %s sends %d of %d coins", r.MaxAge, in.Op.Amount, in.Sender.Coins),
+	}, true
+}
+
+// UnusualAmountRule fires when an operation is far larger than what the
+// user usually spends.
+type UnusualAmountRule struct {
+	Factor     int64
+	MinSamples int
+}
+
+func (r UnusualAmountRule) Name() string {
+	return "unusual_amount"
+}
+
+func (r UnusualAmountRule) Evaluate(in Input) (entity.FraudVerdict, bool) {
+	if r.Factor <= 0 {
+		return entity.FraudVerdict{}, false
+	}
+
+	var total, samples int64
+	for _, tx := range in.Outgoing {
+		if !spendsCoins(tx) {
+			continue
+		}
+		total += tx.Amount
+		samples++
+	}
+	if samples == 0 || samples < int64(r.MinSamples) {
+		return entity.FraudVerdict{}, false
+	}
+	average := total / samples
+
+	if in.Op.Amount <= average*r.Factor {
+		return entity.FraudVerdict{}, false
+	}
+
+	return entity.FraudVerdict{
+		Action: entity.FraudActionFlag,
+		Reason: fmt.Sprintf("%d coins is more than %d times the average of %d", in.Op.Amount, r.Factor, average),
+	}, true
+}
+
+// spendsCoins tells the transactions that are the user spending coins from
+// item transfers, which move none, and from coins taken away by expiry.
+func spendsCoins(tx entity.Transaction) bool {
+	if tx.Amount <= 0 {
+		return false
+	}
+
+	switch tx.Type {
+	case entity.TransactionTypeTransfer, entity.TransactionTypePurchase,
+		entity.TransactionTypeMarketSale, entity.TransactionTypeMarketFee,
+		entity.TransactionTypeRaffleTicket:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,199 @@
+package fraud_usecase
+
+import (
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+var testConfig = Config{
+	Lookback:          30 * 24 * time.Hour,
+	VelocityWindow:    10 * time.Minute,
+	VelocityFlagAt:    5,
+	VelocityHoldAt:    10,
+	RingWindow:        24 * time.Hour,
+	MaxRingSize:       4,
+	NewAccountAge:     7 * 24 * time.Hour,
+	DrainPercent:      80,
+	UnusualFactor:     5,
+	UnusualMinSamples: 3,
+}
+
+var now = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+func transfer(from, to, amount int64, ago time.Duration) entity.Transaction {
+	return entity.Transaction{
+		FromUserID: from,
+		ToUserID:   to,
+		Amount:     amount,
+		Type:       entity.TransactionTypeTransfer,
+		CreatedAt:  now.Add(-ago),
+	}
+}
+
+func transferOp(from, to, amount int64) entity.FraudOperation {
+	return entity.FraudOperation{
+		Type:       entity.TransactionTypeTransfer,
+		FromUserID: from,
+		ToUserID:   to,
+		Amount:     amount,
+		At:         now,
+	}
+}
+
+func veteran() entity.User {
+	return entity.User{ID: 1, Coins: 1000, CreatedAt: now.AddDate(-1, 0, 0)}
+}
+
+func TestEvaluate(t *testing.T) {
+	t.Parallel()
+
+	rules := NewRules(testConfig)
+
+	tests := []struct {
+		name   string
+		in     Input
+		rules  []string
+		action entity.FraudAction
+	}{
+		{
+			name: "ordinary transfer",
+			in: Input{
+				Op:       transferOp(1, 2, 50),
+				Sender:   veteran(),
+				Outgoing: []entity.Transaction{transfer(1, 2, 40, 48*time.Hour), transfer(1, 3, 60, 24*time.Hour)},
+				Recent:   []entity.Transaction{transfer(2, 3, 10, time.Hour)},
+			},
+			action: entity.FraudActionNone,
+		},
+		{
+			name: "three-user ring",
+			in: Input{
+				Op:     transferOp(1, 2, 100),
+				Sender: veteran(),
+				Recent: []entity.Transaction{
+					transfer(2, 3, 100, 2*time.Hour),
+					transfer(3, 1, 100, time.Hour),
+				},
+			},
+			rules:  []string{"ring"},
+			action: entity.FraudActionHold,
+		},
+		{
+			name: "ring larger than the limit",
+			in: Input{
+				Op:     transferOp(1, 2, 100),
+				Sender: veteran(),
+				Recent: []entity.Transaction{
+					transfer(2, 3, 100, 5*time.Hour),
+					transfer(3, 4, 100, 4*time.Hour),
+					transfer(4, 5, 100, 3*time.Hour),
+					transfer(5, 1, 100, 2*time.Hour),
+				},
+			},
+			action: entity.FraudActionNone,
+		},
+		{
+			name: "ring outside the window",
+			in: Input{
+				Op:     transferOp(1, 2, 100),
+				Sender: veteran(),
+				Recent: []entity.Transaction{
+					transfer(2, 1, 100, 48*time.Hour),
+				},
+			},
+			action: entity.FraudActionNone,
+		},
+		{
+			name: "new account drains its balance",
+			in: Input{
+				Op:     transferOp(1, 2, 900),
+				Sender: entity.User{ID: 1, Coins: 1000, CreatedAt: now.Add(-time.Hour)},
+			},
+			rules:  []string{"new_account_drain"},
+			action: entity.FraudActionHold,
+		},
+		{
+			name: "burst of transfers",
+			in: Input{
+				Op:     transferOp(1, 2, 10),
+				Sender: veteran(),
+				Outgoing: []entity.Transaction{
+					transfer(1, 2, 10, 5*time.Minute),
+					transfer(1, 3, 10, 4*time.Minute),
+					transfer(1, 4, 10, 3*time.Minute),
+					transfer(1, 5, 10, 2*time.Minute),
+				},
+			},
+			rules:  []string{"velocity"},
+			action: entity.FraudActionFlag,
+		},
+		{
+			name: "amount far above the usual",
+			in: Input{
+				Op:     transferOp(1, 2, 600),
+				Sender: veteran(),
+				Outgoing: []entity.Transaction{
+					transfer(1, 2, 20, 72*time.Hour),
+					transfer(1, 3, 20, 48*time.Hour),
+					transfer(1, 4, 20, 24*time.Hour),
+				},
+			},
+			rules:  []string{"unusual_amount"},
+			action: entity.FraudActionFlag,
+		},
+		{
+			name: "item transfers and expiry do not count as spending",
+			in: Input{
+				Op:     transferOp(1, 2, 600),
+				Sender: veteran(),
+				Outgoing: []entity.Transaction{
+					transfer(1, 2, 200, 72*time.Hour),
+					transfer(1, 3, 200, 48*time.Hour),
+					transfer(1, 4, 200, 24*time.Hour),
+					{FromUserID: 1, ToUserID: 2, Type: entity.TransactionTypeItemTransfer, CreatedAt: now.Add(-3 * time.Hour)},
+					{FromUserID: 1, ToUserID: 3, Type: entity.TransactionTypeItemTransfer, CreatedAt: now.Add(-2 * time.Hour)},
+					{FromUserID: 1, ToUserID: 9, Amount: 5, Type: entity.TransactionTypeExpiry, CreatedAt: now.Add(-time.Hour)},
+				},
+			},
+			action: entity.FraudActionNone,
+		},
+		{
+			name: "held purchase is blocked",
+			in: Input{
+				Op: entity.FraudOperation{
+					Type:       entity.TransactionTypePurchase,
+					FromUserID: 1,
+					ToUserID:   1,
+					Amount:     10,
+					At:         now,
+				},
+				Sender: veteran(),
+				Outgoing: func() []entity.Transaction {
+					var txs []entity.Transaction
+					for i := 0; i < 9; i++ {
+						txs = append(txs, transfer(1, 2, 10, time.Minute))
+					}
+					return txs
+				}(),
+			},
+			rules:  []string{"velocity"},
+			action: entity.FraudActionBlock,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			verdicts, action := Evaluate(rules, tc.in)
+
+			fired := make([]string, 0, len(verdicts))
+			for _, v := range verdicts {
+				fired = append(fired, v.Rule)
+			}
+
+			require.ElementsMatch(t, tc.rules, fired)
+			require.Equal(t, tc.action, action)
+		})
+	}
+}
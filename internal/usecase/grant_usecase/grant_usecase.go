@@ -0,0 +1,197 @@
+package grant_usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"time"
+)
+
+// GrantUC mints new coins from the system account, either on an admin's
+// request or as the monthly allowance paid to every user. Granted coins
+// expire after the configured time; zero means they never do.
+type GrantUC struct {
+	userRepo      UserRepository
+	teamRepo      TeamRepository
+	txRepo        TransactionRepository
+	lotRepo       LotRepository
+	allowanceRepo AllowanceRepository
+	dbTx          DBTransactor
+	allowance     int64
+	expiry        time.Duration
+}
+
+func NewGrantUC(
+	userRepo UserRepository,
+	teamRepo TeamRepository,
+	txRepo TransactionRepository,
+	lotRepo LotRepository,
+	allowanceRepo AllowanceRepository,
+	dbTx DBTransactor,
+	allowance int64,
+	expiry time.Duration,
+) *GrantUC {
+	return &GrantUC{
+		userRepo:      userRepo,
+		teamRepo:      teamRepo,
+		txRepo:        txRepo,
+		lotRepo:       lotRepo,
+		allowanceRepo: allowanceRepo,
+		dbTx:          dbTx,
+		allowance:     allowance,
+		expiry:        expiry,
+	}
+}
+
+func (uc *GrantUC) GrantToUser(ctx context.Context, adminID, toUserID int64, amount int64) error {
+	_, err := uc.grant(ctx, adminID, amount, func(ctx context.Context) ([]entity.User, error) {
+		user, err := uc.userRepo.GetByID(ctx, toUserID)
+		if err != nil {
+			return nil, err
+		}
+		if user == nil || user.Role == entity.UserRoleSystem {
+			return nil, entity.ErrUserNotFound
+		}
+		return []entity.User{*user}, nil
+	})
+
+	return err
+}
+
+func (uc *GrantUC) GrantToTeam(ctx context.Context, adminID, teamID int64, amount int64) (int, error) {
+	return uc.grant(ctx, adminID, amount, func(ctx context.Context) ([]entity.User, error) {
+		if _, err := uc.teamRepo.GetByID(ctx, teamID); err != nil {
+			return nil, err
+		}
+		return uc.userRepo.ListByTeam(ctx, teamID)
+	})
+}
+
+func (uc *GrantUC) GrantToAll(ctx context.Context, adminID int64, amount int64) (int, error) {
+	return uc.grant(ctx, adminID, amount, uc.userRepo.List)
+}
+
+// PayAllowance pays the configured allowance for the month containing now to
+// every user who has not received it yet. Each user is paid in a separate
+// transaction, so one failure does not hold back the rest; the run can simply
+// be repeated.
+func (uc *GrantUC) PayAllowance(ctx context.Context, now time.Time) (int, error) {
+	if uc.allowance <= 0 {
+		return 0, nil
+	}
+
+	system, err := uc.userRepo.GetByUsername(ctx, entity.SystemUsername)
+	if err != nil {
+		return 0, err
+	}
+
+	users, err := uc.userRepo.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	period := entity.MonthlyPeriod(now)
+	paid := 0
+	var errs []error
+
+	for i := range users {
+		user := users[i]
+		err := uc.dbTx.WithinTransaction(ctx, func(ctx context.Context) error {
+			payout := entity.AllowancePayout{
+				UserID: user.ID,
+				Period: period,
+				Amount: uc.allowance,
+			}
+
+			claimed, err := uc.allowanceRepo.Claim(ctx, &payout)
+			if err != nil || !claimed {
+				return err
+			}
+
+			if err := uc.credit(ctx, system.ID, user.ID, uc.allowance); err != nil {
+				return err
+			}
+
+			paid++
+			return nil
+		})
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return paid, errors.Join(errs...)
+}
+
+func (uc *GrantUC) grant(
+	ctx context.Context,
+	adminID int64,
+	amount int64,
+	recipients func(ctx context.Context) ([]entity.User, error),
+) (int, error) {
+	if amount <= 0 {
+		return 0, entity.ErrNegativeAmount
+	}
+
+	granted := 0
+	err := uc.dbTx.WithinTransaction(ctx, func(ctx context.Context) error {
+		admin, err := uc.userRepo.GetByID(ctx, adminID)
+		if err != nil {
+			return err
+		}
+		if admin == nil || !admin.IsAdmin() {
+			return entity.ErrForbidden
+		}
+
+		system, err := uc.userRepo.GetByUsername(ctx, entity.SystemUsername)
+		if err != nil {
+			return err
+		}
+
+		users, err := recipients(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, user := range users {
+			if err := uc.credit(ctx, system.ID, user.ID, amount); err != nil {
+				return err
+			}
+		}
+
+		granted = len(users)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return granted, nil
+}
+
+// credit reads the user again inside the transaction: the copy the caller
+// listed may be stale by now, and writing it back would undo whatever changed
+// the balance in the meantime.
+func (uc *GrantUC) credit(ctx context.Context, systemID, userID int64, amount int64) error {
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	user.Coins += amount
+	if err := uc.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	lot := entity.NewCoinLot(user.ID, amount, time.Now(), uc.expiry)
+	if err := uc.lotRepo.Create(ctx, &lot); err != nil {
+		return err
+	}
+
+	return uc.txRepo.Create(ctx, &entity.Transaction{
+		FromUserID: systemID,
+		ToUserID:   user.ID,
+		Amount:     amount,
+		Type:       entity.TransactionTypeGrant,
+	})
+}
@@ -0,0 +1,406 @@
+package grant_usecase
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/usecase/grant_usecase/mocks"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type test struct {
+	name string
+	mock func()
+	res  interface{}
+	err  error
+}
+
+const (
+	testAllowance = int64(100)
+	testExpiry    = 365 * 24 * time.Hour
+)
+
+func systemAccount() *entity.User {
+	return &entity.User{ID: 100, Username: entity.SystemUsername, Role: entity.UserRoleSystem}
+}
+
+func TestGrantToUser(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	teamRepo := mocks.NewMockTeamRepository(ctrl)
+	txRepo := mocks.NewMockTransactionRepository(ctrl)
+	lotRepo := mocks.NewMockLotRepository(ctrl)
+	allowanceRepo := mocks.NewMockAllowanceRepository(ctrl)
+	dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+	uc := NewGrantUC(userRepo, teamRepo, txRepo, lotRepo, allowanceRepo, dbTransactor, testAllowance, testExpiry)
+
+	tests := []test{
+		{
+			name: "success",
+			mock: func() {
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				userRepo.EXPECT().
+					GetByID(gomock.Any(), int64(1)).
+					Return(&entity.User{ID: 1, Username: "admin", Role: entity.UserRoleAdmin}, nil)
+				userRepo.EXPECT().
+					GetByUsername(gomock.Any(), entity.SystemUsername).
+					Return(systemAccount(), nil)
+
+				// once to check the recipient, once more to credit it
+				userRepo.EXPECT().
+					GetByID(gomock.Any(), int64(2)).
+					Return(&entity.User{ID: 2, Username: "receiver", Coins: 500, Role: entity.UserRoleUser}, nil).
+					Times(2)
+
+				userRepo.EXPECT().
+					Update(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, user *entity.User) error {
+						require.Equal(t, int64(800), user.Coins)
+						return nil
+					})
+
+				lotRepo.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, lot *entity.CoinLot) error {
+						require.Equal(t, int64(2), lot.UserID)
+						require.Equal(t, int64(300), lot.Remaining)
+						require.WithinDuration(t, time.Now().Add(testExpiry), *lot.ExpiresAt, time.Minute)
+						return nil
+					})
+
+				txRepo.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, tx *entity.Transaction) error {
+						require.Equal(t, int64(100), tx.FromUserID)
+						require.Equal(t, int64(2), tx.ToUserID)
+						require.Equal(t, int64(300), tx.Amount)
+						require.Equal(t, entity.TransactionTypeGrant, tx.Type)
+						return nil
+					})
+			},
+			err: nil,
+		},
+		{
+			name: "not an admin",
+			mock: func() {
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				userRepo.EXPECT().
+					GetByID(gomock.Any(), int64(1)).
+					Return(&entity.User{ID: 1, Username: "admin", Role: entity.UserRoleUser}, nil)
+			},
+			err: entity.ErrForbidden,
+		},
+		{
+			name: "grant to system account",
+			mock: func() {
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				userRepo.EXPECT().
+					GetByID(gomock.Any(), int64(1)).
+					Return(&entity.User{ID: 1, Username: "admin", Role: entity.UserRoleAdmin}, nil)
+				userRepo.EXPECT().
+					GetByUsername(gomock.Any(), entity.SystemUsername).
+					Return(systemAccount(), nil)
+
+				userRepo.EXPECT().
+					GetByID(gomock.Any(), int64(2)).
+					Return(&entity.User{ID: 2, Username: entity.SystemUsername, Role: entity.UserRoleSystem}, nil)
+			},
+			err: entity.ErrUserNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			err := uc.GrantToUser(context.Background(), 1, 2, 300)
+
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("negative amount", func(t *testing.T) {
+		err := uc.GrantToUser(context.Background(), 1, 2, -1)
+		require.ErrorIs(t, err, entity.ErrNegativeAmount)
+	})
+}
+
+func TestGrantToTeam(t *testing.T) {
+	t.Parallel()
+
+	teamID := int64(5)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	teamRepo := mocks.NewMockTeamRepository(ctrl)
+	txRepo := mocks.NewMockTransactionRepository(ctrl)
+	lotRepo := mocks.NewMockLotRepository(ctrl)
+	allowanceRepo := mocks.NewMockAllowanceRepository(ctrl)
+	dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+	uc := NewGrantUC(userRepo, teamRepo, txRepo, lotRepo, allowanceRepo, dbTransactor, testAllowance, testExpiry)
+
+	tests := []test{
+		{
+			name: "success",
+			mock: func() {
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				userRepo.EXPECT().
+					GetByID(gomock.Any(), int64(1)).
+					Return(&entity.User{ID: 1, Username: "admin", Role: entity.UserRoleAdmin}, nil)
+				userRepo.EXPECT().
+					GetByUsername(gomock.Any(), entity.SystemUsername).
+					Return(systemAccount(), nil)
+
+				teamRepo.EXPECT().
+					GetByID(gomock.Any(), teamID).
+					Return(entity.Team{ID: teamID, Name: "backend"}, nil)
+
+				userRepo.EXPECT().
+					ListByTeam(gomock.Any(), teamID).
+					Return([]entity.User{
+						{ID: 2, Username: "alice", Coins: 0, TeamID: &teamID},
+						{ID: 3, Username: "bob", Coins: 10, TeamID: &teamID},
+					}, nil)
+
+				// balances changed after the team was listed
+				userRepo.EXPECT().
+					GetByID(gomock.Any(), int64(2)).
+					Return(&entity.User{ID: 2, Username: "alice", Coins: 40, TeamID: &teamID}, nil)
+				userRepo.EXPECT().
+					GetByID(gomock.Any(), int64(3)).
+					Return(&entity.User{ID: 3, Username: "bob", Coins: 0, TeamID: &teamID}, nil)
+
+				userRepo.EXPECT().
+					Update(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, user *entity.User) error {
+						require.Equal(t, map[int64]int64{2: 90, 3: 50}[user.ID], user.Coins)
+						return nil
+					}).
+					Times(2)
+				lotRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+				txRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+			},
+			res: 2,
+			err: nil,
+		},
+		{
+			name: "team not found",
+			mock: func() {
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				userRepo.EXPECT().
+					GetByID(gomock.Any(), int64(1)).
+					Return(&entity.User{ID: 1, Username: "admin", Role: entity.UserRoleAdmin}, nil)
+				userRepo.EXPECT().
+					GetByUsername(gomock.Any(), entity.SystemUsername).
+					Return(systemAccount(), nil)
+
+				teamRepo.EXPECT().
+					GetByID(gomock.Any(), teamID).
+					Return(entity.Team{}, entity.ErrTeamNotFound)
+			},
+			res: 0,
+			err: entity.ErrTeamNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			granted, err := uc.GrantToTeam(context.Background(), 1, teamID, 50)
+
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tc.res, granted)
+		})
+	}
+}
+
+func TestGrantToAll(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	teamRepo := mocks.NewMockTeamRepository(ctrl)
+	txRepo := mocks.NewMockTransactionRepository(ctrl)
+	lotRepo := mocks.NewMockLotRepository(ctrl)
+	allowanceRepo := mocks.NewMockAllowanceRepository(ctrl)
+	dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+	uc := NewGrantUC(userRepo, teamRepo, txRepo, lotRepo, allowanceRepo, dbTransactor, testAllowance, testExpiry)
+
+	dbTransactor.EXPECT().
+		WithinTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		})
+	userRepo.EXPECT().
+		GetByID(gomock.Any(), int64(1)).
+		Return(&entity.User{ID: 1, Username: "admin", Role: entity.UserRoleAdmin}, nil)
+	userRepo.EXPECT().
+		GetByUsername(gomock.Any(), entity.SystemUsername).
+		Return(systemAccount(), nil)
+
+	userRepo.EXPECT().
+		List(gomock.Any()).
+		Return([]entity.User{{ID: 2}, {ID: 3}, {ID: 4}}, nil)
+
+	for _, id := range []int64{2, 3, 4} {
+		userRepo.EXPECT().
+			GetByID(gomock.Any(), id).
+			Return(&entity.User{ID: id, Coins: 5}, nil)
+	}
+
+	userRepo.EXPECT().
+		Update(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, user *entity.User) error {
+			require.Equal(t, int64(15), user.Coins)
+			return nil
+		}).
+		Times(3)
+	lotRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil).Times(3)
+	txRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil).Times(3)
+
+	granted, err := uc.GrantToAll(context.Background(), 1, 10)
+	require.NoError(t, err)
+	require.Equal(t, 3, granted)
+}
+
+func TestPayAllowance(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	teamRepo := mocks.NewMockTeamRepository(ctrl)
+	txRepo := mocks.NewMockTransactionRepository(ctrl)
+	lotRepo := mocks.NewMockLotRepository(ctrl)
+	allowanceRepo := mocks.NewMockAllowanceRepository(ctrl)
+	dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+	tests := []test{
+		{
+			name: "pays whoever has not been paid this month",
+			mock: func() {
+				userRepo.EXPECT().
+					GetByUsername(gomock.Any(), entity.SystemUsername).
+					Return(systemAccount(), nil)
+				userRepo.EXPECT().
+					List(gomock.Any()).
+					Return([]entity.User{
+						{ID: 1, Username: "paid", Coins: 0},
+						{ID: 2, Username: "unpaid", Coins: 0},
+					}, nil)
+
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					}).
+					Times(2)
+
+				allowanceRepo.EXPECT().
+					Claim(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, payout *entity.AllowancePayout) (bool, error) {
+						require.Equal(t, "2026-10", payout.Period)
+						require.Equal(t, testAllowance, payout.Amount)
+						// user 1 has already been paid this month
+						return payout.UserID != 1, nil
+					}).
+					Times(2)
+
+				// the balance changed after the users were listed
+				userRepo.EXPECT().
+					GetByID(gomock.Any(), int64(2)).
+					Return(&entity.User{ID: 2, Username: "unpaid", Coins: 30}, nil)
+
+				userRepo.EXPECT().
+					Update(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, user *entity.User) error {
+						require.Equal(t, int64(2), user.ID)
+						require.Equal(t, 30+testAllowance, user.Coins)
+						return nil
+					})
+
+				lotRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+				txRepo.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, tx *entity.Transaction) error {
+						require.Equal(t, entity.TransactionTypeGrant, tx.Type)
+						require.Equal(t, int64(2), tx.ToUserID)
+						return nil
+					})
+			},
+			res: 1,
+			err: nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			uc := NewGrantUC(userRepo, teamRepo, txRepo, lotRepo, allowanceRepo, dbTransactor, testAllowance, testExpiry)
+
+			paid, err := uc.PayAllowance(context.Background(), now)
+
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tc.res, paid)
+		})
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		uc := NewGrantUC(userRepo, teamRepo, txRepo, lotRepo, allowanceRepo, dbTransactor, 0, 0)
+
+		paid, err := uc.PayAllowance(context.Background(), time.Now())
+		require.NoError(t, err)
+		require.Zero(t, paid)
+	})
+}
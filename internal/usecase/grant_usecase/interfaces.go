@@ -0,0 +1,36 @@
+package grant_usecase
+
+import (
+	"context"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+)
+
+//go:generate mockgen -source=interfaces.go -destination=./mocks/mocks.go -package=mocks
+
+type TransactionRepository interface {
+	Create(ctx context.Context, tr *entity.Transaction) error
+}
+
+type LotRepository interface {
+	Create(ctx context.Context, lot *entity.CoinLot) error
+}
+
+type UserRepository interface {
+	GetByID(ctx context.Context, id int64) (*entity.User, error)
+	GetByUsername(ctx context.Context, username string) (*entity.User, error)
+	Update(ctx context.Context, user *entity.User) error
+	List(ctx context.Context) ([]entity.User, error)
+	ListByTeam(ctx context.Context, teamID int64) ([]entity.User, error)
+}
+
+type TeamRepository interface {
+	GetByID(ctx context.Context, id int64) (entity.Team, error)
+}
+
+type AllowanceRepository interface {
+	Claim(ctx context.Context, payout *entity.AllowancePayout) (bool, error)
+}
+
+type DBTransactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
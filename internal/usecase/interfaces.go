@@ -25,6 +25,20 @@ type (
 	TranslationWebAPI interface {
 		Translate(entity.Translation) (entity.Translation, error)
 	}
+
+	// CatalogTranslation -.
+	CatalogTranslation interface {
+		Localize(ctx context.Context, items []entity.MerchItem, acceptLanguage string) ([]entity.MerchTranslation, error)
+		SetTranslation(ctx context.Context, adminID int64, req MerchTranslationRequest) (entity.MerchTranslation, error)
+		DeleteTranslation(ctx context.Context, adminID int64, itemName, locale string) error
+	}
+
+	// MerchTranslationRepo -.
+	MerchTranslationRepo interface {
+		ListByLocale(ctx context.Context, locale string, itemIDs []int64) ([]entity.MerchTranslation, error)
+		Save(ctx context.Context, t *entity.MerchTranslation) (bool, error)
+		Delete(ctx context.Context, itemID int64, locale string) (bool, error)
+	}
 )
 type User interface {
 	Register(ctx context.Context, username, password string) error
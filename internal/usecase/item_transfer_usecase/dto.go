@@ -0,0 +1,11 @@
+package item_transfer_usecase
+
+// TransferItemRequest gives Quantity of an item from the sender's inventory
+// to another user. VariantID picks the size or colour when the item has any.
+type TransferItemRequest struct {
+	ToUser    string `json:"to_user" validate:"required"`
+	ItemName  string `json:"item" validate:"required"`
+	VariantID *int64 `json:"variant_id,omitempty"`
+	Quantity  int64  `json:"quantity" validate:"required,min=1"`
+	Message   string `json:"message,omitempty"`
+}
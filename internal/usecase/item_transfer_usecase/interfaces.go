@@ -0,0 +1,44 @@
+package item_transfer_usecase
+
+import (
+	"context"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+)
+
+//go:generate mockgen -source=interfaces.go -destination=./mocks/mocks.go -package=mocks
+
+// InventoryRepository moves items out of one inventory and into another in
+// a single statement each, so concurrent transfers cannot overdraw it.
+type InventoryRepository interface {
+	TakeKeeping(ctx context.Context, userID, itemID int64, variantID *int64, quantity, keep int64) (bool, error)
+	Add(ctx context.Context, userID, itemID int64, variantID *int64, quantity int64) error
+}
+
+// OrderRepository tells which units are still waiting to be handed over
+// through an order.
+type OrderRepository interface {
+	CountOpen(ctx context.Context, userID, itemID int64, variantID *int64) (int64, error)
+}
+
+type MerchRepository interface {
+	GetByName(ctx context.Context, name string) (entity.MerchItem, error)
+	GetVariant(ctx context.Context, id int64) (entity.MerchVariant, error)
+}
+
+type UserRepository interface {
+	GetByID(ctx context.Context, id int64) (*entity.User, error)
+	GetByUsername(ctx context.Context, username string) (*entity.User, error)
+}
+
+type TransactionRepository interface {
+	Create(ctx context.Context, tr *entity.Transaction) error
+}
+
+// Notifier delivers a short message to a user.
+type Notifier interface {
+	Notify(ctx context.Context, userID int64, message string) error
+}
+
+type DBTransactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,130 @@
+package item_transfer_usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"unicode/utf8"
+)
+
+// ItemTransferUC lets users give merch they own to a colleague.
+type ItemTransferUC struct {
+	invRepo   InventoryRepository
+	merchRepo MerchRepository
+	userRepo  UserRepository
+	txRepo    TransactionRepository
+	orderRepo OrderRepository
+	notifier  Notifier
+	dbTx      DBTransactor
+}
+
+func NewItemTransferUC(
+	invRepo InventoryRepository,
+	merchRepo MerchRepository,
+	userRepo UserRepository,
+	txRepo TransactionRepository,
+	orderRepo OrderRepository,
+	notifier Notifier,
+	dbTx DBTransactor,
+) *ItemTransferUC {
+	return &ItemTransferUC{
+		invRepo:   invRepo,
+		merchRepo: merchRepo,
+		userRepo:  userRepo,
+		txRepo:    txRepo,
+		orderRepo: orderRepo,
+		notifier:  notifier,
+		dbTx:      dbTx,
+	}
+}
+
+// TransferItem moves the requested quantity from the sender's inventory to
+// the recipient's and records it as an item_transfer with zero coins, so it
+// shows up in both users' histories. Either all of it happens or none.
+// Units still waiting on an open order cannot be given away: cancelling
+// that order takes them back.
+func (uc *ItemTransferUC) TransferItem(ctx context.Context, fromUserID int64, req TransferItemRequest) (entity.Transaction, error) {
+	if req.Quantity <= 0 {
+		return entity.Transaction{}, entity.ErrInvalidQuantity
+	}
+	if utf8.RuneCountInString(req.Message) > entity.MaxTransferMessageLength {
+		return entity.Transaction{}, entity.ErrMessageTooLong
+	}
+
+	toUser, err := uc.userRepo.GetByUsername(ctx, req.ToUser)
+	if err != nil {
+		return entity.Transaction{}, err
+	}
+	if toUser == nil {
+		return entity.Transaction{}, entity.ErrUserNotFound
+	}
+	if toUser.ID == fromUserID {
+		return entity.Transaction{}, entity.ErrSelfItemTransfer
+	}
+
+	item, err := uc.merchRepo.GetByName(ctx, req.ItemName)
+	if err != nil {
+		return entity.Transaction{}, entity.ErrMerchNotFound
+	}
+	if req.VariantID != nil {
+		variant, err := uc.merchRepo.GetVariant(ctx, *req.VariantID)
+		if err != nil {
+			return entity.Transaction{}, err
+		}
+		if variant.ItemID != item.ID {
+			return entity.Transaction{}, entity.ErrVariantNotFound
+		}
+	}
+
+	quantity := req.Quantity
+	tx := entity.Transaction{
+		FromUserID: fromUserID,
+		ToUserID:   toUser.ID,
+		Type:       entity.TransactionTypeItemTransfer,
+		ItemID:     &item.ID,
+		Quantity:   &quantity,
+	}
+	if req.Message != "" {
+		tx.Message = &req.Message
+	}
+
+	err = uc.dbTx.WithinTransaction(ctx, func(ctx context.Context) error {
+		ordered, err := uc.orderRepo.CountOpen(ctx, fromUserID, item.ID, req.VariantID)
+		if err != nil {
+			return err
+		}
+
+		taken, err := uc.invRepo.TakeKeeping(ctx, fromUserID, item.ID, req.VariantID, quantity, ordered)
+		if err != nil {
+			return err
+		}
+		if !taken {
+			return entity.ErrNotEnoughItems
+		}
+
+		if err := uc.invRepo.Add(ctx, toUser.ID, item.ID, req.VariantID, quantity); err != nil {
+			return err
+		}
+
+		return uc.txRepo.Create(ctx, &tx)
+	})
+	if err != nil {
+		return entity.Transaction{}, err
+	}
+
+	uc.notifyRecipient(ctx, fromUserID, toUser.ID, item.Name, quantity)
+
+	return tx, nil
+}
+
+// notifyRecipient tells the recipient about the gift. It is best effort: the
+// items have already moved, so a failed notification is not an error.
+func (uc *ItemTransferUC) notifyRecipient(ctx context.Context, fromUserID, toUserID int64, itemName string, quantity int64) {
+	from := "someone"
+	if sender, err := uc.userRepo.GetByID(ctx, fromUserID); err == nil && sender != nil {
+		from = sender.Username
+	}
+
+	message := fmt.Sprintf("%s gave you %s x%d", from, itemName, quantity)
+	_ = uc.notifier.Notify(ctx, toUserID, message)
+}
@@ -0,0 +1,144 @@
+package item_transfer_usecase
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/usecase/item_transfer_usecase/mocks"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type test struct {
+	name string
+	mock func()
+	res  interface{}
+	err  error
+}
+
+func TestTransferItem(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	invRepo := mocks.NewMockInventoryRepository(ctrl)
+	merchRepo := mocks.NewMockMerchRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	txRepo := mocks.NewMockTransactionRepository(ctrl)
+	orderRepo := mocks.NewMockOrderRepository(ctrl)
+	notifier := mocks.NewMockNotifier(ctrl)
+	dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+	uc := NewItemTransferUC(invRepo, merchRepo, userRepo, txRepo, orderRepo, notifier, dbTransactor)
+	size := int64(4)
+
+	tests := []test{
+		{
+			name: "gives a duplicate away",
+			mock: func() {
+				userRepo.EXPECT().
+					GetByUsername(gomock.Any(), "ivan").
+					Return(&entity.User{ID: 2, Username: "ivan"}, nil)
+				merchRepo.EXPECT().
+					GetByName(gomock.Any(), "t-shirt").
+					Return(entity.MerchItem{ID: 3, Name: "t-shirt", Price: 80}, nil)
+				merchRepo.EXPECT().GetVariant(gomock.Any(), size).Return(entity.MerchVariant{ID: size, ItemID: 3, Size: "M"}, nil)
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				orderRepo.EXPECT().CountOpen(gomock.Any(), int64(1), int64(3), &size).Return(int64(0), nil)
+				invRepo.EXPECT().TakeKeeping(gomock.Any(), int64(1), int64(3), &size, int64(1), int64(0)).Return(true, nil)
+				invRepo.EXPECT().Add(gomock.Any(), int64(2), int64(3), &size, int64(1)).Return(nil)
+				txRepo.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, tr *entity.Transaction) error {
+						require.Equal(t, entity.TransactionTypeItemTransfer, tr.Type)
+						require.Zero(t, tr.Amount)
+						require.Equal(t, int64(3), *tr.ItemID)
+						require.Equal(t, int64(1), *tr.Quantity)
+						tr.ID = 21
+						return nil
+					})
+				userRepo.EXPECT().
+					GetByID(gomock.Any(), int64(1)).
+					Return(&entity.User{ID: 1, Username: "anna"}, nil)
+				notifier.EXPECT().Notify(gomock.Any(), int64(2), "anna gave you t-shirt x1").Return(nil)
+			},
+			res: TransferItemRequest{ToUser: "ivan", ItemName: "t-shirt", VariantID: &size, Quantity: 1},
+		},
+		{
+			name: "more than the inventory holds",
+			mock: func() {
+				userRepo.EXPECT().
+					GetByUsername(gomock.Any(), "ivan").
+					Return(&entity.User{ID: 2, Username: "ivan"}, nil)
+				merchRepo.EXPECT().
+					GetByName(gomock.Any(), "t-shirt").
+					Return(entity.MerchItem{ID: 3, Name: "t-shirt", Price: 80}, nil)
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				orderRepo.EXPECT().CountOpen(gomock.Any(), int64(1), int64(3), nil).Return(int64(0), nil)
+				invRepo.EXPECT().TakeKeeping(gomock.Any(), int64(1), int64(3), nil, int64(3), int64(0)).Return(false, nil)
+			},
+			res: TransferItemRequest{ToUser: "ivan", ItemName: "t-shirt", Quantity: 3},
+			err: entity.ErrNotEnoughItems,
+		},
+		{
+			name: "unit still waiting on an order",
+			mock: func() {
+				userRepo.EXPECT().
+					GetByUsername(gomock.Any(), "ivan").
+					Return(&entity.User{ID: 2, Username: "ivan"}, nil)
+				merchRepo.EXPECT().
+					GetByName(gomock.Any(), "t-shirt").
+					Return(entity.MerchItem{ID: 3, Name: "t-shirt", Price: 80}, nil)
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				orderRepo.EXPECT().CountOpen(gomock.Any(), int64(1), int64(3), nil).Return(int64(1), nil)
+				invRepo.EXPECT().TakeKeeping(gomock.Any(), int64(1), int64(3), nil, int64(1), int64(1)).Return(false, nil)
+			},
+			res: TransferItemRequest{ToUser: "ivan", ItemName: "t-shirt", Quantity: 1},
+			err: entity.ErrNotEnoughItems,
+		},
+		{
+			name: "to yourself",
+			mock: func() {
+				userRepo.EXPECT().
+					GetByUsername(gomock.Any(), "anna").
+					Return(&entity.User{ID: 1, Username: "anna"}, nil)
+			},
+			res: TransferItemRequest{ToUser: "anna", ItemName: "t-shirt", Quantity: 1},
+			err: entity.ErrSelfItemTransfer,
+		},
+		{
+			name: "zero quantity",
+			mock: func() {},
+			res:  TransferItemRequest{ToUser: "ivan", ItemName: "t-shirt"},
+			err:  entity.ErrInvalidQuantity,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			tx, err := uc.TransferItem(context.Background(), 1, tc.res.(TransferItemRequest))
+
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, int64(21), tx.ID)
+			}
+		})
+	}
+}
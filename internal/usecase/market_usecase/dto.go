@@ -0,0 +1,29 @@
+package market_usecase
+
+import (
+	"github.com/smthjapanese/avito-merch/internal/entity"
+)
+
+// CreateListingRequest puts Quantity of an item from the seller's inventory
+// up for sale. Price is for the whole quantity.
+type CreateListingRequest struct {
+	ItemName  string `json:"item" validate:"required"`
+	VariantID *int64 `json:"variant_id,omitempty"`
+	Quantity  int64  `json:"quantity" validate:"required,min=1"`
+	Price     int64  `json:"price" validate:"required,min=1"`
+}
+
+type SearchListingsRequest struct {
+	Query         string `json:"query,omitempty"`
+	MaxPrice      *int64 `json:"max_price,omitempty" validate:"omitempty,min=1"`
+	CheapestFirst bool   `json:"cheapest_first,omitempty"`
+	Page          int    `json:"page,omitempty" validate:"omitempty,min=1"`
+	PageSize      int    `json:"page_size,omitempty" validate:"omitempty,min=1,max=100"`
+}
+
+type ListingPage struct {
+	Listings []entity.Listing `json:"listings"`
+	Total    int64            `json:"total"`
+	Page     int              `json:"page"`
+	PageSize int              `json:"page_size"`
+}
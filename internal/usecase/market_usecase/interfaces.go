@@ -0,0 +1,63 @@
+package market_usecase
+
+import (
+	"context"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"time"
+)
+
+//go:generate mockgen -source=interfaces.go -destination=./mocks/mocks.go -package=mocks
+
+type ListingRepository interface {
+	Create(ctx context.Context, listing *entity.Listing) error
+	GetByID(ctx context.Context, id int64) (entity.Listing, error)
+	Search(ctx context.Context, q entity.ListingQuery, now time.Time) ([]entity.Listing, int64, error)
+	ListBySeller(ctx context.Context, sellerID int64) ([]entity.Listing, error)
+	Close(ctx context.Context, listing entity.Listing) (bool, error)
+	ListExpired(ctx context.Context, now time.Time, limit int) ([]int64, error)
+}
+
+// InventoryRepository takes listed items out of the seller's inventory and
+// puts them into the buyer's, or back, in a single statement each.
+type InventoryRepository interface {
+	TakeKeeping(ctx context.Context, userID, itemID int64, variantID *int64, quantity, keep int64) (bool, error)
+	Add(ctx context.Context, userID, itemID int64, variantID *int64, quantity int64) error
+}
+
+// OrderRepository tells which units are still waiting to be handed over
+// through an order.
+type OrderRepository interface {
+	CountOpen(ctx context.Context, userID, itemID int64, variantID *int64) (int64, error)
+}
+
+type MerchRepository interface {
+	GetByName(ctx context.Context, name string) (entity.MerchItem, error)
+	GetVariant(ctx context.Context, id int64) (entity.MerchVariant, error)
+}
+
+type UserRepository interface {
+	GetByID(ctx context.Context, id int64) (*entity.User, error)
+	GetByUsername(ctx context.Context, username string) (*entity.User, error)
+	Update(ctx context.Context, user *entity.User) error
+}
+
+type TransactionRepository interface {
+	Create(ctx context.Context, tr *entity.Transaction) error
+}
+
+// LotRepository passes the buyer's coins on to the seller keeping their
+// grant and expiry dates.
+type LotRepository interface {
+	Spend(ctx context.Context, userID int64, amount int64) ([]entity.CoinLot, error)
+	Credit(ctx context.Context, userID int64, portions []entity.CoinLot) error
+}
+
+// TransferChecker vets the buyer's payment to the seller like a direct
+// transfer: fraud rules, transfer limits and the approval threshold.
+type TransferChecker interface {
+	CheckTransfer(ctx context.Context, op entity.FraudOperation) error
+}
+
+type DBTransactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,450 @@
+package market_usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"strings"
+	"time"
+)
+
+const (
+	_defaultPageSize = 20
+	_maxPageSize     = 100
+	_expireBatchSize = 100
+)
+
+// MarketUC runs the internal marketplace where users resell the merch in
+// their inventory to each other for coins.
+type MarketUC struct {
+	listingRepo ListingRepository
+	invRepo     InventoryRepository
+	merchRepo   MerchRepository
+	userRepo    UserRepository
+	txRepo      TransactionRepository
+	lotRepo     LotRepository
+	orderRepo   OrderRepository
+	transfers   TransferChecker
+	dbTx        DBTransactor
+	policy      entity.MarketPolicy
+}
+
+func NewMarketUC(
+	listingRepo ListingRepository,
+	invRepo InventoryRepository,
+	merchRepo MerchRepository,
+	userRepo UserRepository,
+	txRepo TransactionRepository,
+	lotRepo LotRepository,
+	orderRepo OrderRepository,
+	transfers TransferChecker,
+	dbTx DBTransactor,
+	policy entity.MarketPolicy,
+) *MarketUC {
+	return &MarketUC{
+		listingRepo: listingRepo,
+		invRepo:     invRepo,
+		merchRepo:   merchRepo,
+		userRepo:    userRepo,
+		txRepo:      txRepo,
+		lotRepo:     lotRepo,
+		orderRepo:   orderRepo,
+		transfers:   transfers,
+		dbTx:        dbTx,
+		policy:      policy,
+	}
+}
+
+// CreateListing takes the items out of the seller's inventory and puts them
+// up for sale until they are bought, the listing is cancelled or it expires.
+// Units still waiting on an open order cannot be listed.
+func (uc *MarketUC) CreateListing(ctx context.Context, sellerID int64, req CreateListingRequest) (entity.Listing, error) {
+	listing := entity.Listing{
+		SellerID:  sellerID,
+		VariantID: req.VariantID,
+		Quantity:  req.Quantity,
+		Price:     req.Price,
+		Status:    entity.ListingStatusActive,
+	}
+	if err := listing.Validate(); err != nil {
+		return entity.Listing{}, err
+	}
+
+	item, err := uc.merchRepo.GetByName(ctx, req.ItemName)
+	if err != nil {
+		return entity.Listing{}, entity.ErrMerchNotFound
+	}
+	listing.ItemID = item.ID
+
+	if listing.VariantID != nil {
+		variant, err := uc.merchRepo.GetVariant(ctx, *listing.VariantID)
+		if err != nil {
+			return entity.Listing{}, err
+		}
+		if variant.ItemID != item.ID {
+			return entity.Listing{}, entity.ErrVariantNotFound
+		}
+	}
+
+	if uc.policy.TTL > 0 {
+		expiresAt := time.Now().Add(uc.policy.TTL)
+		listing.ExpiresAt = &expiresAt
+	}
+
+	err = uc.dbTx.WithinTransaction(ctx, func(ctx context.Context) error {
+		ordered, err := uc.orderRepo.CountOpen(ctx, sellerID, item.ID, listing.VariantID)
+		if err != nil {
+			return err
+		}
+
+		taken, err := uc.invRepo.TakeKeeping(ctx, sellerID, item.ID, listing.VariantID, listing.Quantity, ordered)
+		if err != nil {
+			return err
+		}
+		if !taken {
+			return entity.ErrNotEnoughItems
+		}
+
+		return uc.listingRepo.Create(ctx, &listing)
+	})
+	if err != nil {
+		return entity.Listing{}, err
+	}
+
+	listing.ItemName = item.Name
+	return listing, nil
+}
+
+// Buy sells the listing to the buyer. The items go to the buyer's inventory
+// and the price leaves their account in the same transaction: the seller
+// gets the price less the marketplace fee, and the fee goes to the fee
+// account or is burned. Each leg is recorded in the ledger. The payment goes
+// through the same checks as a direct transfer to the seller first.
+func (uc *MarketUC) Buy(ctx context.Context, buyerID, listingID int64) (entity.Listing, error) {
+	listing, err := uc.listingRepo.GetByID(ctx, listingID)
+	if err != nil {
+		return entity.Listing{}, err
+	}
+	if err := checkOpen(listing, buyerID, time.Now()); err != nil {
+		return entity.Listing{}, err
+	}
+
+	err = uc.transfers.CheckTransfer(ctx, entity.FraudOperation{
+		Type:       entity.TransactionTypeMarketSale,
+		FromUserID: buyerID,
+		ToUserID:   listing.SellerID,
+		ItemID:     &listing.ItemID,
+		Amount:     listing.Price,
+		At:         time.Now(),
+	})
+	if err != nil {
+		return entity.Listing{}, err
+	}
+
+	var sold entity.Listing
+
+	err = uc.dbTx.WithinTransaction(ctx, func(ctx context.Context) error {
+		now := time.Now()
+
+		listing, err := uc.listingRepo.GetByID(ctx, listingID)
+		if err != nil {
+			return err
+		}
+		if err := checkOpen(listing, buyerID, now); err != nil {
+			return err
+		}
+
+		buyer, err := uc.userRepo.GetByID(ctx, buyerID)
+		if err != nil {
+			return err
+		}
+		if buyer == nil {
+			return entity.ErrUserNotFound
+		}
+		if buyer.Coins < listing.Price {
+			return entity.ErrInsufficientFunds
+		}
+
+		seller, err := uc.userRepo.GetByID(ctx, listing.SellerID)
+		if err != nil {
+			return err
+		}
+		if seller == nil {
+			return entity.ErrUserNotFound
+		}
+
+		feeAccount, err := uc.feeAccount(ctx)
+		if err != nil {
+			return err
+		}
+		// The fee account may be on either side of the sale; its balance
+		// has to change on the copy that gets saved.
+		switch feeAccount.ID {
+		case buyer.ID:
+			feeAccount = buyer
+		case seller.ID:
+			feeAccount = seller
+		}
+
+		fee := uc.policy.Fee(listing.Price)
+		proceeds := listing.Price - fee
+		collectsFee := !uc.policy.Burns() && fee > 0
+
+		listing.Status = entity.ListingStatusSold
+		listing.BuyerID = &buyerID
+		listing.Fee = fee
+		listing.ClosedAt = &now
+
+		closed, err := uc.listingRepo.Close(ctx, listing)
+		if err != nil {
+			return err
+		}
+		if !closed {
+			return entity.ErrListingNotActive
+		}
+
+		buyer.Coins -= listing.Price
+		seller.Coins += proceeds
+		if collectsFee {
+			feeAccount.Coins += fee
+		}
+		if err := uc.userRepo.Update(ctx, buyer); err != nil {
+			return err
+		}
+		if err := uc.userRepo.Update(ctx, seller); err != nil {
+			return err
+		}
+		if collectsFee && feeAccount != buyer && feeAccount != seller {
+			if err := uc.userRepo.Update(ctx, feeAccount); err != nil {
+				return err
+			}
+		}
+
+		portions, err := uc.lotRepo.Spend(ctx, buyerID, listing.Price)
+		if err != nil {
+			return err
+		}
+		sellerPortions, _ := entity.ConsumeLots(portions, proceeds)
+		if err := uc.lotRepo.Credit(ctx, seller.ID, sellerPortions); err != nil {
+			return err
+		}
+		if collectsFee {
+			feePortions, _ := entity.ConsumeLots(portions, fee)
+			if err := uc.lotRepo.Credit(ctx, feeAccount.ID, feePortions); err != nil {
+				return err
+			}
+		}
+
+		if err := uc.invRepo.Add(ctx, buyerID, listing.ItemID, listing.VariantID, listing.Quantity); err != nil {
+			return err
+		}
+
+		if err := uc.record(ctx, listing, seller.ID, feeAccount.ID, proceeds); err != nil {
+			return err
+		}
+
+		sold = listing
+		return nil
+	})
+	if err != nil {
+		return entity.Listing{}, err
+	}
+
+	return sold, nil
+}
+
+// checkOpen makes sure the listing can be bought by buyerID right now.
+func checkOpen(listing entity.Listing, buyerID int64, now time.Time) error {
+	if !listing.IsOpen(now) {
+		return entity.ErrListingNotActive
+	}
+	if listing.SellerID == buyerID {
+		return entity.ErrOwnListing
+	}
+
+	return nil
+}
+
+// record writes the legs of a sale: the items going to the buyer, the
+// seller's proceeds and, if there is one, the marketplace fee.
+func (uc *MarketUC) record(ctx context.Context, listing entity.Listing, sellerID, feeAccountID, proceeds int64) error {
+	buyerID := *listing.BuyerID
+
+	items := entity.Transaction{
+		FromUserID: sellerID,
+		ToUserID:   buyerID,
+		Type:       entity.TransactionTypeItemTransfer,
+		ItemID:     &listing.ItemID,
+		Quantity:   &listing.Quantity,
+	}
+	if err := uc.txRepo.Create(ctx, &items); err != nil {
+		return err
+	}
+
+	coins := entity.Transaction{
+		FromUserID: buyerID,
+		ToUserID:   sellerID,
+		Amount:     proceeds,
+		Type:       entity.TransactionTypeMarketSale,
+		ItemID:     &listing.ItemID,
+		ListPrice:  &listing.Price,
+	}
+	if err := uc.txRepo.Create(ctx, &coins); err != nil {
+		return err
+	}
+
+	if listing.Fee == 0 {
+		return nil
+	}
+
+	fee := entity.Transaction{
+		FromUserID: buyerID,
+		ToUserID:   feeAccountID,
+		Amount:     listing.Fee,
+		Type:       entity.TransactionTypeMarketFee,
+		ItemID:     &listing.ItemID,
+	}
+	return uc.txRepo.Create(ctx, &fee)
+}
+
+// feeAccount returns the user fees are paid to. Burned fees are recorded as
+// going back to the system account, like swept coins.
+func (uc *MarketUC) feeAccount(ctx context.Context) (*entity.User, error) {
+	username := uc.policy.FeeAccount
+	if uc.policy.Burns() {
+		username = entity.SystemUsername
+	}
+
+	account, err := uc.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, entity.ErrUserNotFound
+	}
+
+	return account, nil
+}
+
+// CancelListing takes the listing down and gives the items back to the
+// seller. Sellers cancel their own listings; admins can cancel any.
+func (uc *MarketUC) CancelListing(ctx context.Context, userID, listingID int64) error {
+	return uc.dbTx.WithinTransaction(ctx, func(ctx context.Context) error {
+		listing, err := uc.listingRepo.GetByID(ctx, listingID)
+		if err != nil {
+			return err
+		}
+
+		if listing.SellerID != userID {
+			if err := uc.requireAdmin(ctx, userID); err != nil {
+				return err
+			}
+		}
+
+		return uc.close(ctx, listing, entity.ListingStatusCancelled, time.Now())
+	})
+}
+
+// ExpireListings takes down every listing whose time ran out and gives the
+// items back to their sellers. Each listing is handled in its own
+// transaction.
+func (uc *MarketUC) ExpireListings(ctx context.Context, now time.Time) (int, error) {
+	ids, err := uc.listingRepo.ListExpired(ctx, now, _expireBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	var errs []error
+
+	for _, id := range ids {
+		err := uc.dbTx.WithinTransaction(ctx, func(ctx context.Context) error {
+			listing, err := uc.listingRepo.GetByID(ctx, id)
+			if err != nil {
+				return err
+			}
+			// Sold or cancelled since it was listed.
+			if listing.Status != entity.ListingStatusActive || !listing.IsExpired(now) {
+				return nil
+			}
+
+			if err := uc.close(ctx, listing, entity.ListingStatusExpired, now); err != nil {
+				return err
+			}
+			expired++
+			return nil
+		})
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return expired, errors.Join(errs...)
+}
+
+// close ends an active listing without a sale and returns the items.
+func (uc *MarketUC) close(ctx context.Context, listing entity.Listing, status entity.ListingStatus, now time.Time) error {
+	if listing.Status != entity.ListingStatusActive {
+		return entity.ErrListingNotActive
+	}
+
+	listing.Status = status
+	listing.ClosedAt = &now
+
+	closed, err := uc.listingRepo.Close(ctx, listing)
+	if err != nil {
+		return err
+	}
+	if !closed {
+		return entity.ErrListingNotActive
+	}
+
+	return uc.invRepo.Add(ctx, listing.SellerID, listing.ItemID, listing.VariantID, listing.Quantity)
+}
+
+// Search returns a page of the listings that can be bought right now.
+func (uc *MarketUC) Search(ctx context.Context, req SearchListingsRequest) (ListingPage, error) {
+	page := max(req.Page, 1)
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = _defaultPageSize
+	}
+	pageSize = min(pageSize, _maxPageSize)
+
+	listings, total, err := uc.listingRepo.Search(ctx, entity.ListingQuery{
+		Search:        strings.TrimSpace(req.Query),
+		MaxPrice:      req.MaxPrice,
+		CheapestFirst: req.CheapestFirst,
+		Limit:         pageSize,
+		Offset:        (page - 1) * pageSize,
+	}, time.Now())
+	if err != nil {
+		return ListingPage{}, err
+	}
+
+	if listings == nil {
+		listings = []entity.Listing{}
+	}
+	return ListingPage{
+		Listings: listings,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+func (uc *MarketUC) ListMyListings(ctx context.Context, sellerID int64) ([]entity.Listing, error) {
+	return uc.listingRepo.ListBySeller(ctx, sellerID)
+}
+
+func (uc *MarketUC) requireAdmin(ctx context.Context, adminID int64) error {
+	admin, err := uc.userRepo.GetByID(ctx, adminID)
+	if err != nil {
+		return err
+	}
+	if admin == nil || !admin.IsAdmin() {
+		return entity.ErrForbidden
+	}
+
+	return nil
+}
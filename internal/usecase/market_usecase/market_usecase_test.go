@@ -0,0 +1,525 @@
+package market_usecase
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/usecase/market_usecase/mocks"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type test struct {
+	name string
+	mock func()
+	res  interface{}
+	err  error
+}
+
+func TestCreateListing(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	listingRepo := mocks.NewMockListingRepository(ctrl)
+	invRepo := mocks.NewMockInventoryRepository(ctrl)
+	merchRepo := mocks.NewMockMerchRepository(ctrl)
+	orderRepo := mocks.NewMockOrderRepository(ctrl)
+	dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+	uc := NewMarketUC(
+		listingRepo,
+		invRepo,
+		merchRepo,
+		mocks.NewMockUserRepository(ctrl),
+		mocks.NewMockTransactionRepository(ctrl),
+		mocks.NewMockLotRepository(ctrl),
+		orderRepo,
+		mocks.NewMockTransferChecker(ctrl),
+		dbTransactor,
+		entity.MarketPolicy{TTL: 24 * time.Hour},
+	)
+
+	tests := []test{
+		{
+			name: "lists a duplicate",
+			mock: func() {
+				merchRepo.EXPECT().
+					GetByName(gomock.Any(), "t-shirt").
+					Return(entity.MerchItem{ID: 3, Name: "t-shirt", Price: 80}, nil)
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				orderRepo.EXPECT().CountOpen(gomock.Any(), int64(1), int64(3), nil).Return(int64(0), nil)
+				invRepo.EXPECT().TakeKeeping(gomock.Any(), int64(1), int64(3), nil, int64(1), int64(0)).Return(true, nil)
+				listingRepo.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, l *entity.Listing) error {
+						require.Equal(t, entity.ListingStatusActive, l.Status)
+						require.NotNil(t, l.ExpiresAt)
+						l.ID = 5
+						return nil
+					})
+			},
+			res: CreateListingRequest{ItemName: "t-shirt", Quantity: 1, Price: 60},
+		},
+		{
+			name: "not in the inventory",
+			mock: func() {
+				merchRepo.EXPECT().
+					GetByName(gomock.Any(), "t-shirt").
+					Return(entity.MerchItem{ID: 3, Name: "t-shirt", Price: 80}, nil)
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				orderRepo.EXPECT().CountOpen(gomock.Any(), int64(1), int64(3), nil).Return(int64(0), nil)
+				invRepo.EXPECT().TakeKeeping(gomock.Any(), int64(1), int64(3), nil, int64(2), int64(0)).Return(false, nil)
+			},
+			res: CreateListingRequest{ItemName: "t-shirt", Quantity: 2, Price: 60},
+			err: entity.ErrNotEnoughItems,
+		},
+		{
+			name: "unit still waiting on an order",
+			mock: func() {
+				merchRepo.EXPECT().
+					GetByName(gomock.Any(), "t-shirt").
+					Return(entity.MerchItem{ID: 3, Name: "t-shirt", Price: 80}, nil)
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				orderRepo.EXPECT().CountOpen(gomock.Any(), int64(1), int64(3), nil).Return(int64(1), nil)
+				invRepo.EXPECT().TakeKeeping(gomock.Any(), int64(1), int64(3), nil, int64(1), int64(1)).Return(false, nil)
+			},
+			res: CreateListingRequest{ItemName: "t-shirt", Quantity: 1, Price: 60},
+			err: entity.ErrNotEnoughItems,
+		},
+		{
+			name: "free",
+			mock: func() {},
+			res:  CreateListingRequest{ItemName: "t-shirt", Quantity: 1},
+			err:  entity.ErrInvalidPrice,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			listing, err := uc.CreateListing(context.Background(), 1, tc.res.(CreateListingRequest))
+
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, int64(5), listing.ID)
+			}
+		})
+	}
+}
+
+func TestBuy(t *testing.T) {
+	t.Parallel()
+
+	listing := entity.Listing{ID: 5, SellerID: 1, ItemID: 3, Quantity: 1, Price: 60, Status: entity.ListingStatusActive}
+	portions := []entity.CoinLot{{ID: 40, Amount: 60, Remaining: 60}}
+
+	t.Run("pays the seller and the fee account", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		listingRepo := mocks.NewMockListingRepository(ctrl)
+		invRepo := mocks.NewMockInventoryRepository(ctrl)
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		txRepo := mocks.NewMockTransactionRepository(ctrl)
+		lotRepo := mocks.NewMockLotRepository(ctrl)
+		transfers := mocks.NewMockTransferChecker(ctrl)
+		dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+		uc := NewMarketUC(
+			listingRepo,
+			invRepo,
+			mocks.NewMockMerchRepository(ctrl),
+			userRepo,
+			txRepo,
+			lotRepo,
+			mocks.NewMockOrderRepository(ctrl),
+			transfers,
+			dbTransactor,
+			entity.MarketPolicy{FeePercent: 5, FeeAccount: "market"},
+		)
+
+		seller := &entity.User{ID: 1, Username: "anna", Coins: 100}
+		buyer := &entity.User{ID: 2, Username: "ivan", Coins: 100}
+		market := &entity.User{ID: 7, Username: "market"}
+
+		listingRepo.EXPECT().GetByID(gomock.Any(), int64(5)).Return(listing, nil).Times(2)
+		transfers.EXPECT().
+			CheckTransfer(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, op entity.FraudOperation) error {
+				require.Equal(t, entity.TransactionTypeMarketSale, op.Type)
+				require.Equal(t, int64(2), op.FromUserID)
+				require.Equal(t, int64(1), op.ToUserID)
+				require.Equal(t, int64(60), op.Amount)
+				return nil
+			})
+		dbTransactor.EXPECT().
+			WithinTransaction(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+		userRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(buyer, nil)
+		userRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(seller, nil)
+		userRepo.EXPECT().GetByUsername(gomock.Any(), "market").Return(market, nil)
+		listingRepo.EXPECT().
+			Close(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, l entity.Listing) (bool, error) {
+				require.Equal(t, entity.ListingStatusSold, l.Status)
+				require.Equal(t, int64(2), *l.BuyerID)
+				require.Equal(t, int64(3), l.Fee)
+				return true, nil
+			})
+		userRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil).Times(3)
+		lotRepo.EXPECT().Spend(gomock.Any(), int64(2), int64(60)).Return(portions, nil)
+		lotRepo.EXPECT().Credit(gomock.Any(), int64(1), []entity.CoinLot{{ID: 40, Amount: 57, Remaining: 57}}).Return(nil)
+		lotRepo.EXPECT().Credit(gomock.Any(), int64(7), []entity.CoinLot{{ID: 40, Amount: 3, Remaining: 3}}).Return(nil)
+		invRepo.EXPECT().Add(gomock.Any(), int64(2), int64(3), nil, int64(1)).Return(nil)
+
+		var legs []entity.TransactionType
+		txRepo.EXPECT().
+			Create(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tr *entity.Transaction) error {
+				legs = append(legs, tr.Type)
+				return nil
+			}).
+			Times(3)
+
+		sold, err := uc.Buy(context.Background(), 2, 5)
+		require.NoError(t, err)
+		require.Equal(t, entity.ListingStatusSold, sold.Status)
+		require.Equal(t, int64(40), buyer.Coins)
+		require.Equal(t, int64(157), seller.Coins)
+		require.Equal(t, int64(3), market.Coins)
+		require.Equal(t, []entity.TransactionType{
+			entity.TransactionTypeItemTransfer,
+			entity.TransactionTypeMarketSale,
+			entity.TransactionTypeMarketFee,
+		}, legs)
+	})
+
+	t.Run("burns the fee", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		listingRepo := mocks.NewMockListingRepository(ctrl)
+		invRepo := mocks.NewMockInventoryRepository(ctrl)
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		txRepo := mocks.NewMockTransactionRepository(ctrl)
+		lotRepo := mocks.NewMockLotRepository(ctrl)
+		transfers := mocks.NewMockTransferChecker(ctrl)
+		dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+		uc := NewMarketUC(
+			listingRepo,
+			invRepo,
+			mocks.NewMockMerchRepository(ctrl),
+			userRepo,
+			txRepo,
+			lotRepo,
+			mocks.NewMockOrderRepository(ctrl),
+			transfers,
+			dbTransactor,
+			entity.MarketPolicy{FeePercent: 10},
+		)
+
+		seller := &entity.User{ID: 1, Username: "anna", Coins: 100}
+		buyer := &entity.User{ID: 2, Username: "ivan", Coins: 100}
+		system := &entity.User{ID: 8, Username: entity.SystemUsername}
+
+		listingRepo.EXPECT().GetByID(gomock.Any(), int64(5)).Return(listing, nil).Times(2)
+		transfers.EXPECT().
+			CheckTransfer(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, op entity.FraudOperation) error {
+				require.Equal(t, entity.TransactionTypeMarketSale, op.Type)
+				require.Equal(t, int64(2), op.FromUserID)
+				require.Equal(t, int64(1), op.ToUserID)
+				require.Equal(t, int64(60), op.Amount)
+				return nil
+			})
+		dbTransactor.EXPECT().
+			WithinTransaction(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+		userRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(buyer, nil)
+		userRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(seller, nil)
+		userRepo.EXPECT().GetByUsername(gomock.Any(), entity.SystemUsername).Return(system, nil)
+		listingRepo.EXPECT().Close(gomock.Any(), gomock.Any()).Return(true, nil)
+		userRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+		lotRepo.EXPECT().Spend(gomock.Any(), int64(2), int64(60)).Return(portions, nil)
+		lotRepo.EXPECT().Credit(gomock.Any(), int64(1), gomock.Any()).Return(nil)
+		invRepo.EXPECT().Add(gomock.Any(), int64(2), int64(3), nil, int64(1)).Return(nil)
+		txRepo.EXPECT().
+			Create(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tr *entity.Transaction) error {
+				if tr.Type == entity.TransactionTypeMarketFee {
+					require.Equal(t, system.ID, tr.ToUserID)
+					require.Equal(t, int64(6), tr.Amount)
+				}
+				return nil
+			}).
+			Times(3)
+
+		_, err := uc.Buy(context.Background(), 2, 5)
+		require.NoError(t, err)
+		require.Equal(t, int64(154), seller.Coins)
+		require.Zero(t, system.Coins)
+	})
+
+	t.Run("the seller runs the fee account", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		listingRepo := mocks.NewMockListingRepository(ctrl)
+		invRepo := mocks.NewMockInventoryRepository(ctrl)
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		txRepo := mocks.NewMockTransactionRepository(ctrl)
+		lotRepo := mocks.NewMockLotRepository(ctrl)
+		transfers := mocks.NewMockTransferChecker(ctrl)
+		dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+		uc := NewMarketUC(
+			listingRepo,
+			invRepo,
+			mocks.NewMockMerchRepository(ctrl),
+			userRepo,
+			txRepo,
+			lotRepo,
+			mocks.NewMockOrderRepository(ctrl),
+			transfers,
+			dbTransactor,
+			entity.MarketPolicy{FeePercent: 5, FeeAccount: "anna"},
+		)
+
+		seller := &entity.User{ID: 1, Username: "anna", Coins: 100}
+		buyer := &entity.User{ID: 2, Username: "ivan", Coins: 100}
+
+		listingRepo.EXPECT().GetByID(gomock.Any(), int64(5)).Return(listing, nil).Times(2)
+		transfers.EXPECT().CheckTransfer(gomock.Any(), gomock.Any()).Return(nil)
+		dbTransactor.EXPECT().
+			WithinTransaction(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+		userRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(buyer, nil)
+		userRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(seller, nil)
+		userRepo.EXPECT().GetByUsername(gomock.Any(), "anna").Return(&entity.User{ID: 1, Username: "anna", Coins: 100}, nil)
+		listingRepo.EXPECT().Close(gomock.Any(), gomock.Any()).Return(true, nil)
+		userRepo.EXPECT().Update(gomock.Any(), buyer).Return(nil)
+		userRepo.EXPECT().
+			Update(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, user *entity.User) error {
+				require.Equal(t, int64(160), user.Coins)
+				return nil
+			})
+		lotRepo.EXPECT().Spend(gomock.Any(), int64(2), int64(60)).Return(portions, nil)
+		lotRepo.EXPECT().Credit(gomock.Any(), int64(1), gomock.Any()).Return(nil).Times(2)
+		invRepo.EXPECT().Add(gomock.Any(), int64(2), int64(3), nil, int64(1)).Return(nil)
+		txRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil).Times(3)
+
+		_, err := uc.Buy(context.Background(), 2, 5)
+		require.NoError(t, err)
+		require.Equal(t, int64(160), seller.Coins)
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		listingRepo := mocks.NewMockListingRepository(ctrl)
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		transfers := mocks.NewMockTransferChecker(ctrl)
+		dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+		uc := NewMarketUC(
+			listingRepo,
+			mocks.NewMockInventoryRepository(ctrl),
+			mocks.NewMockMerchRepository(ctrl),
+			userRepo,
+			mocks.NewMockTransactionRepository(ctrl),
+			mocks.NewMockLotRepository(ctrl),
+			mocks.NewMockOrderRepository(ctrl),
+			transfers,
+			dbTransactor,
+			entity.MarketPolicy{},
+		)
+
+		sold := listing
+		sold.Status = entity.ListingStatusSold
+
+		tests := []test{
+			{
+				name: "own listing",
+				mock: func() {
+					listingRepo.EXPECT().GetByID(gomock.Any(), int64(5)).Return(listing, nil)
+				},
+				res: int64(1),
+				err: entity.ErrOwnListing,
+			},
+			{
+				name: "already sold",
+				mock: func() {
+					listingRepo.EXPECT().GetByID(gomock.Any(), int64(5)).Return(sold, nil)
+				},
+				res: int64(2),
+				err: entity.ErrListingNotActive,
+			},
+			{
+				name: "not enough coins",
+				mock: func() {
+					listingRepo.EXPECT().GetByID(gomock.Any(), int64(5)).Return(listing, nil)
+					transfers.EXPECT().CheckTransfer(gomock.Any(), gomock.Any()).Return(nil)
+					dbTransactor.EXPECT().
+						WithinTransaction(gomock.Any(), gomock.Any()).
+						DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+							return fn(ctx)
+						})
+					listingRepo.EXPECT().GetByID(gomock.Any(), int64(5)).Return(listing, nil)
+					userRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(&entity.User{ID: 2, Coins: 59}, nil)
+				},
+				res: int64(2),
+				err: entity.ErrInsufficientFunds,
+			},
+			{
+				name: "over the transfer limit",
+				mock: func() {
+					listingRepo.EXPECT().GetByID(gomock.Any(), int64(5)).Return(listing, nil)
+					transfers.EXPECT().CheckTransfer(gomock.Any(), gomock.Any()).Return(entity.ErrTransferLimitExceeded)
+				},
+				res: int64(2),
+				err: entity.ErrTransferLimitExceeded,
+			},
+			{
+				name: "blocked by fraud checks",
+				mock: func() {
+					listingRepo.EXPECT().GetByID(gomock.Any(), int64(5)).Return(listing, nil)
+					transfers.EXPECT().CheckTransfer(gomock.Any(), gomock.Any()).Return(entity.ErrOperationBlocked)
+				},
+				res: int64(2),
+				err: entity.ErrOperationBlocked,
+			},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				tc.mock()
+
+				_, err := uc.Buy(context.Background(), tc.res.(int64), 5)
+				require.ErrorIs(t, err, tc.err)
+			})
+		}
+	})
+}
+
+func TestCancelListing(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	listingRepo := mocks.NewMockListingRepository(ctrl)
+	invRepo := mocks.NewMockInventoryRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+	uc := NewMarketUC(
+		listingRepo,
+		invRepo,
+		mocks.NewMockMerchRepository(ctrl),
+		userRepo,
+		mocks.NewMockTransactionRepository(ctrl),
+		mocks.NewMockLotRepository(ctrl),
+		mocks.NewMockOrderRepository(ctrl),
+		mocks.NewMockTransferChecker(ctrl),
+		dbTransactor,
+		entity.MarketPolicy{},
+	)
+
+	listing := entity.Listing{ID: 5, SellerID: 1, ItemID: 3, Quantity: 2, Price: 60, Status: entity.ListingStatusActive}
+
+	dbTransactor.EXPECT().
+		WithinTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		}).
+		Times(2)
+
+	listingRepo.EXPECT().GetByID(gomock.Any(), int64(5)).Return(listing, nil)
+	userRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(&entity.User{ID: 2, Role: entity.UserRoleUser}, nil)
+	require.ErrorIs(t, uc.CancelListing(context.Background(), 2, 5), entity.ErrForbidden)
+
+	listingRepo.EXPECT().GetByID(gomock.Any(), int64(5)).Return(listing, nil)
+	listingRepo.EXPECT().
+		Close(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, l entity.Listing) (bool, error) {
+			require.Equal(t, entity.ListingStatusCancelled, l.Status)
+			return true, nil
+		})
+	invRepo.EXPECT().Add(gomock.Any(), int64(1), int64(3), nil, int64(2)).Return(nil)
+	require.NoError(t, uc.CancelListing(context.Background(), 1, 5))
+}
+
+func TestExpireListings(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	listingRepo := mocks.NewMockListingRepository(ctrl)
+	invRepo := mocks.NewMockInventoryRepository(ctrl)
+	dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+	uc := NewMarketUC(
+		listingRepo,
+		invRepo,
+		mocks.NewMockMerchRepository(ctrl),
+		mocks.NewMockUserRepository(ctrl),
+		mocks.NewMockTransactionRepository(ctrl),
+		mocks.NewMockLotRepository(ctrl),
+		mocks.NewMockOrderRepository(ctrl),
+		mocks.NewMockTransferChecker(ctrl),
+		dbTransactor,
+		entity.MarketPolicy{},
+	)
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Minute)
+
+	dbTransactor.EXPECT().
+		WithinTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		}).
+		Times(2)
+
+	listingRepo.EXPECT().ListExpired(gomock.Any(), now, _expireBatchSize).Return([]int64{5, 6}, nil)
+	listingRepo.EXPECT().
+		GetByID(gomock.Any(), int64(5)).
+		Return(entity.Listing{ID: 5, SellerID: 1, ItemID: 3, Quantity: 1, Status: entity.ListingStatusActive, ExpiresAt: &past}, nil)
+	listingRepo.EXPECT().
+		GetByID(gomock.Any(), int64(6)).
+		Return(entity.Listing{ID: 6, SellerID: 1, ItemID: 3, Quantity: 1, Status: entity.ListingStatusSold, ExpiresAt: &past}, nil)
+	listingRepo.EXPECT().Close(gomock.Any(), gomock.Any()).Return(true, nil)
+	invRepo.EXPECT().Add(gomock.Any(), int64(1), int64(3), nil, int64(1)).Return(nil)
+
+	expired, err := uc.ExpireListings(context.Background(), now)
+	require.NoError(t, err)
+	require.Equal(t, 1, expired)
+}
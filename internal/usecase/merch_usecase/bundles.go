@@ -0,0 +1,231 @@
+package usecase
+
+import (
+	"context"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"time"
+)
+
+// bundleComponent is an item of a bundle being bought, resolved to the
+// variant the buyer gets.
+type bundleComponent struct {
+	item      entity.MerchItem
+	variant   *entity.MerchVariant
+	listPrice int64
+	quantity  int64
+}
+
+func (uc *MerchUseCase) CreateBundle(ctx context.Context, adminID int64, req CreateBundleRequest) (entity.Bundle, error) {
+	if err := uc.requireAdmin(ctx, adminID); err != nil {
+		return entity.Bundle{}, err
+	}
+
+	bundle := entity.Bundle{
+		Name:        req.Name,
+		Price:       req.Price,
+		Description: req.Description,
+		CreatedBy:   &adminID,
+		Items:       make([]entity.BundleItem, 0, len(req.Items)),
+	}
+	for _, component := range req.Items {
+		item, err := uc.merchRepo.GetByName(ctx, component.ItemName)
+		if err != nil {
+			return entity.Bundle{}, entity.ErrMerchNotFound
+		}
+		if component.VariantID != nil {
+			variant, err := uc.merchRepo.GetVariant(ctx, *component.VariantID)
+			if err != nil {
+				return entity.Bundle{}, err
+			}
+			if variant.ItemID != item.ID {
+				return entity.Bundle{}, entity.ErrVariantNotFound
+			}
+		}
+
+		bundle.Items = append(bundle.Items, entity.BundleItem{
+			ItemID:    item.ID,
+			VariantID: component.VariantID,
+			Quantity:  component.Quantity,
+			ItemName:  item.Name,
+		})
+	}
+	if err := bundle.Validate(); err != nil {
+		return entity.Bundle{}, err
+	}
+
+	err := uc.dbTransactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		return uc.merchRepo.CreateBundle(ctx, &bundle)
+	})
+	if err != nil {
+		return entity.Bundle{}, err
+	}
+
+	return bundle, nil
+}
+
+func (uc *MerchUseCase) ListBundles(ctx context.Context) ([]entity.Bundle, error) {
+	return uc.merchRepo.ListBundles(ctx)
+}
+
+// BuyBundle buys every item of the bundle for the bundle price in one go.
+// It is charged once and recorded as a single purchase linked to the
+// bundle; each unit still gets its own order, priced at its share of the
+// bundle price so cancelling it refunds a fair part. If any component with
+// a limited stock has sold out, nothing is bought.
+func (uc *MerchUseCase) BuyBundle(ctx context.Context, userID int64, req BuyBundleRequest) error {
+	bundle, err := uc.merchRepo.GetBundleByName(ctx, req.Bundle)
+	if err != nil {
+		return err
+	}
+
+	components, err := uc.bundleComponents(ctx, bundle, req.Variants)
+	if err != nil {
+		return err
+	}
+
+	// one list price per unit, in the order the units get their orders
+	var listPrices []int64
+	var listPrice int64
+	for _, c := range components {
+		for i := int64(0); i < c.quantity; i++ {
+			listPrices = append(listPrices, c.listPrice)
+		}
+		listPrice += c.listPrice * c.quantity
+	}
+	shares := entity.SplitPrice(bundle.Price, listPrices)
+
+	action, err := uc.fraud.Check(ctx, entity.FraudOperation{
+		Type:       entity.TransactionTypePurchase,
+		FromUserID: userID,
+		ToUserID:   userID,
+		Amount:     bundle.Price,
+		At:         time.Now(),
+	})
+	if err != nil {
+		return entity.ErrTransactionFailed
+	}
+	if action == entity.FraudActionBlock {
+		return entity.ErrOperationBlocked
+	}
+
+	return uc.dbTransactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		user, err := uc.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return entity.ErrUserNotFound
+		}
+		if user.Coins < bundle.Price {
+			return entity.ErrInsufficientFunds
+		}
+
+		for _, c := range components {
+			if c.variant == nil {
+				continue
+			}
+			for i := int64(0); i < c.quantity; i++ {
+				ok, err := uc.merchRepo.DecrementStock(ctx, c.variant.ID)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					return entity.ErrOutOfStock
+				}
+			}
+		}
+
+		user.Coins -= bundle.Price
+		if err := uc.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+		paid, err := uc.lotRepo.Spend(ctx, userID, bundle.Price)
+		if err != nil {
+			return err
+		}
+
+		for _, c := range components {
+			if err := uc.addToInventory(ctx, userID, c.item.ID, c.variantID(), c.quantity); err != nil {
+				return err
+			}
+		}
+
+		transaction := entity.Transaction{
+			FromUserID: userID,
+			ToUserID:   userID, // self-transaction for purchase
+			Amount:     bundle.Price,
+			Type:       entity.TransactionTypePurchase,
+			BundleID:   &bundle.ID,
+			ListPrice:  &listPrice,
+			CreatedAt:  time.Now(),
+		}
+		if err := uc.txRepo.Create(ctx, transaction); err != nil {
+			return err
+		}
+
+		// Each order keeps its share of the coins paid, so cancelling one
+		// refunds exactly those.
+		unit := 0
+		for _, c := range components {
+			for i := int64(0); i < c.quantity; i++ {
+				lots, _ := entity.ConsumeLots(paid, shares[unit])
+				order := entity.Order{
+					UserID:    userID,
+					ItemID:    c.item.ID,
+					VariantID: c.variantID(),
+					Price:     shares[unit],
+					Status:    entity.OrderStatusPlaced,
+					Lots:      lots,
+				}
+				if err := uc.orderRepo.Create(ctx, &order); err != nil {
+					return err
+				}
+				unit++
+			}
+		}
+
+		return nil
+	})
+}
+
+// bundleComponents resolves the items of the bundle, with the variants
+// picked by the buyer filled in for the components that leave them open.
+func (uc *MerchUseCase) bundleComponents(ctx context.Context, bundle entity.Bundle, picked map[string]int64) ([]bundleComponent, error) {
+	components := make([]bundleComponent, 0, len(bundle.Items))
+	for _, component := range bundle.Items {
+		item, err := uc.merchRepo.GetByID(ctx, component.ItemID)
+		if err != nil {
+			return nil, err
+		}
+		if !item.IsReleased(time.Now()) {
+			return nil, entity.ErrItemNotReleased
+		}
+
+		variantID := component.VariantID
+		if variantID == nil {
+			if id, ok := picked[item.Name]; ok {
+				variantID = &id
+			}
+		}
+		variant, err := uc.variant(ctx, item, variantID)
+		if err != nil {
+			return nil, err
+		}
+
+		listPrice := item.Price
+		if variant != nil {
+			listPrice = variant.Price(item)
+		}
+		components = append(components, bundleComponent{
+			item:      item,
+			variant:   variant,
+			listPrice: listPrice,
+			quantity:  component.Quantity,
+		})
+	}
+	return components, nil
+}
+
+func (c bundleComponent) variantID() *int64 {
+	if c.variant == nil {
+		return nil
+	}
+	return &c.variant.ID
+}
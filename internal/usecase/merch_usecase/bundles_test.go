@@ -0,0 +1,292 @@
+package usecase
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/usecase/merch_usecase/mocks"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestMerchUseCase_BuyBundle(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	merchRepo := mocks.NewMockMerchRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	invRepo := mocks.NewMockInventoryRepository(ctrl)
+	txRepo := mocks.NewMockTransactionRepository(ctrl)
+	lotRepo := mocks.NewMockLotRepository(ctrl)
+	orderRepo := mocks.NewMockOrderRepository(ctrl)
+	dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+	fraud := mocks.NewMockFraudChecker(ctrl)
+
+	uc := NewMerchUseCase(merchRepo, userRepo, invRepo, txRepo, lotRepo, mocks.NewMockPromoRepository(ctrl), orderRepo, dbTransactor, fraud, nil)
+
+	userID := int64(1)
+	tshirt := entity.MerchItem{ID: 1, Name: "t-shirt", Price: 80}
+	cup := entity.MerchItem{ID: 2, Name: "cup", Price: 20}
+	pen := entity.MerchItem{ID: 3, Name: "pen", Price: 10}
+	medium := entity.MerchVariant{ID: 7, ItemID: 1, Size: "M", Stock: 2}
+	pack := entity.Bundle{
+		ID:    5,
+		Name:  "welcome pack",
+		Price: 90,
+		Items: []entity.BundleItem{
+			{BundleID: 5, ItemID: 1, Quantity: 1},
+			{BundleID: 5, ItemID: 2, Quantity: 1},
+			{BundleID: 5, ItemID: 3, Quantity: 2},
+		},
+	}
+
+	// the lookups made before the purchase, with the M t-shirt picked
+	resolved := func() {
+		merchRepo.EXPECT().GetBundleByName(gomock.Any(), "welcome pack").Return(pack, nil)
+		merchRepo.EXPECT().GetByID(gomock.Any(), tshirt.ID).Return(tshirt, nil)
+		merchRepo.EXPECT().GetVariant(gomock.Any(), medium.ID).Return(medium, nil)
+		merchRepo.EXPECT().GetByID(gomock.Any(), cup.ID).Return(cup, nil)
+		merchRepo.EXPECT().ListVariants(gomock.Any(), cup.ID).Return(nil, nil)
+		merchRepo.EXPECT().GetByID(gomock.Any(), pen.ID).Return(pen, nil)
+		merchRepo.EXPECT().ListVariants(gomock.Any(), pen.ID).Return(nil, nil)
+		fraud.EXPECT().Check(gomock.Any(), gomock.Any()).Return(entity.FraudActionNone, nil)
+		dbTransactor.EXPECT().
+			WithinTransaction(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+	}
+
+	picked := map[string]int64{"t-shirt": medium.ID}
+
+	tests := []struct {
+		test
+		req BuyBundleRequest
+	}{
+		{
+			req: BuyBundleRequest{Bundle: "welcome pack", Variants: picked},
+			test: test{
+				name: "success",
+				mock: func() {
+					resolved()
+					userRepo.EXPECT().
+						GetByID(gomock.Any(), userID).
+						Return(&entity.User{ID: userID, Coins: 100}, nil)
+					merchRepo.EXPECT().DecrementStock(gomock.Any(), medium.ID).Return(true, nil)
+					userRepo.EXPECT().
+						Update(gomock.Any(), gomock.Any()).
+						DoAndReturn(func(ctx context.Context, user *entity.User) error {
+							require.Equal(t, int64(10), user.Coins)
+							return nil
+						})
+					expires := time.Now().Add(24 * time.Hour)
+					lotRepo.EXPECT().
+						Spend(gomock.Any(), userID, int64(90)).
+						Return([]entity.CoinLot{
+							{ID: 1, Amount: 60, Remaining: 60, ExpiresAt: &expires},
+							{Amount: 30, Remaining: 30},
+						}, nil)
+
+					// the user already has a pen
+					invRepo.EXPECT().
+						GetByUserID(gomock.Any(), userID).
+						Return([]entity.UserInventory{{ID: 1, UserID: userID, ItemID: pen.ID, Quantity: 1}}, nil).
+						Times(3)
+					invRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+					invRepo.EXPECT().
+						Update(gomock.Any(), gomock.Any()).
+						DoAndReturn(func(ctx context.Context, inventory entity.UserInventory) error {
+							require.Equal(t, int64(3), inventory.Quantity)
+							return nil
+						})
+
+					txRepo.EXPECT().
+						Create(gomock.Any(), gomock.Any()).
+						DoAndReturn(func(ctx context.Context, tr entity.Transaction) error {
+							require.Equal(t, entity.TransactionTypePurchase, tr.Type)
+							require.Equal(t, int64(90), tr.Amount)
+							require.Equal(t, pack.ID, *tr.BundleID)
+							require.Nil(t, tr.ItemID)
+							require.Equal(t, int64(120), *tr.ListPrice)
+							return nil
+						})
+
+					var prices []int64
+					orderRepo.EXPECT().
+						Create(gomock.Any(), gomock.Any()).
+						DoAndReturn(func(ctx context.Context, order *entity.Order) error {
+							prices = append(prices, order.Price)
+
+							// each order carries its share of the coins paid
+							var paid int64
+							for _, lot := range order.Lots {
+								paid += lot.Amount
+							}
+							require.Equal(t, order.Price, paid)
+							if len(prices) == 1 {
+								require.Len(t, order.Lots, 1)
+								require.NotNil(t, order.Lots[0].ExpiresAt)
+							}
+
+							if len(prices) == 4 {
+								require.Equal(t, []int64{59, 15, 8, 8}, prices)
+							}
+							return nil
+						}).
+						Times(4)
+				},
+			},
+		},
+		{
+			req: BuyBundleRequest{Bundle: "welcome pack", Variants: picked},
+			test: test{
+				name: "component_out_of_stock",
+				mock: func() {
+					resolved()
+					userRepo.EXPECT().
+						GetByID(gomock.Any(), userID).
+						Return(&entity.User{ID: userID, Coins: 100}, nil)
+					merchRepo.EXPECT().DecrementStock(gomock.Any(), medium.ID).Return(false, nil)
+				},
+				err: entity.ErrOutOfStock,
+			},
+		},
+		{
+			req: BuyBundleRequest{Bundle: "welcome pack", Variants: picked},
+			test: test{
+				name: "insufficient_funds",
+				mock: func() {
+					resolved()
+					userRepo.EXPECT().
+						GetByID(gomock.Any(), userID).
+						Return(&entity.User{ID: userID, Coins: 89}, nil)
+				},
+				err: entity.ErrInsufficientFunds,
+			},
+		},
+		{
+			req: BuyBundleRequest{Bundle: "welcome pack"},
+			test: test{
+				name: "variant_required",
+				mock: func() {
+					merchRepo.EXPECT().GetBundleByName(gomock.Any(), "welcome pack").Return(pack, nil)
+					merchRepo.EXPECT().GetByID(gomock.Any(), tshirt.ID).Return(tshirt, nil)
+					merchRepo.EXPECT().ListVariants(gomock.Any(), tshirt.ID).Return([]entity.MerchVariant{medium}, nil)
+				},
+				err: entity.ErrVariantRequired,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+			err := uc.BuyBundle(context.Background(), userID, tc.req)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestMerchUseCase_CreateBundle(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	merchRepo := mocks.NewMockMerchRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+	uc := NewMerchUseCase(merchRepo, userRepo, nil, nil, nil, nil, nil, dbTransactor, nil, nil)
+
+	adminID, userID := int64(9), int64(1)
+	deskPack := []BundleItemRequest{{ItemName: "cup", Quantity: 1}, {ItemName: "pen", Quantity: 1}}
+
+	tests := []struct {
+		test
+		userID int64
+		items  []BundleItemRequest
+	}{
+		{
+			userID: adminID,
+			items:  deskPack,
+			test: test{
+				name: "success",
+				mock: func() {
+					userRepo.EXPECT().
+						GetByID(gomock.Any(), adminID).
+						Return(&entity.User{ID: adminID, Role: entity.UserRoleAdmin}, nil)
+					merchRepo.EXPECT().GetByName(gomock.Any(), "cup").Return(entity.MerchItem{ID: 2, Name: "cup", Price: 20}, nil)
+					merchRepo.EXPECT().GetByName(gomock.Any(), "pen").Return(entity.MerchItem{ID: 3, Name: "pen", Price: 10}, nil)
+					dbTransactor.EXPECT().
+						WithinTransaction(gomock.Any(), gomock.Any()).
+						DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+							return fn(ctx)
+						})
+					merchRepo.EXPECT().
+						CreateBundle(gomock.Any(), gomock.Any()).
+						DoAndReturn(func(ctx context.Context, bundle *entity.Bundle) error {
+							bundle.ID = 1
+							return nil
+						})
+				},
+			},
+		},
+		{
+			userID: userID,
+			items:  deskPack,
+			test: test{
+				name: "not_an_admin",
+				mock: func() {
+					userRepo.EXPECT().
+						GetByID(gomock.Any(), userID).
+						Return(&entity.User{ID: userID}, nil)
+				},
+				err: entity.ErrForbidden,
+			},
+		},
+		{
+			userID: adminID,
+			items:  append(append([]BundleItemRequest{}, deskPack...), BundleItemRequest{ItemName: "pen", Quantity: 2}),
+			test: test{
+				name: "duplicate_item",
+				mock: func() {
+					userRepo.EXPECT().
+						GetByID(gomock.Any(), adminID).
+						Return(&entity.User{ID: adminID, Role: entity.UserRoleAdmin}, nil)
+					merchRepo.EXPECT().GetByName(gomock.Any(), "cup").Return(entity.MerchItem{ID: 2, Name: "cup", Price: 20}, nil)
+					merchRepo.EXPECT().GetByName(gomock.Any(), "pen").Return(entity.MerchItem{ID: 3, Name: "pen", Price: 10}, nil).Times(2)
+				},
+				err: entity.ErrDuplicateBundleItem,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+			bundle, err := uc.CreateBundle(context.Background(), tc.userID, CreateBundleRequest{
+				Name:  "desk pack",
+				Price: 25,
+				Items: tc.items,
+			})
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, int64(1), bundle.ID)
+				require.Len(t, bundle.Items, 2)
+			}
+		})
+	}
+}
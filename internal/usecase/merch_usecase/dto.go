@@ -6,9 +6,53 @@ import (
 )
 
 type MerchItemDTO struct {
-	ID    int64  `json:"id"`
-	Name  string `json:"name"`
-	Price int64  `json:"price"`
+	ID          int64    `json:"id"`
+	Name        string   `json:"name"`
+	Price       int64    `json:"price"`
+	Category    string   `json:"category,omitempty"`
+	Description string   `json:"description,omitempty"`
+	ImageURL    *string  `json:"image_url,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	// DisplayName and Description are in Locale when the catalog was
+	// localized; Name is left as is.
+	DisplayName string `json:"display_name,omitempty"`
+	Locale      string `json:"locale,omitempty"`
+}
+
+// CatalogRequest filters, orders and pages the catalog. Empty fields leave
+// the corresponding filter out; pages are numbered from 1.
+type CatalogRequest struct {
+	Query      string           `json:"query"`
+	CategoryID *int64           `json:"category_id,omitempty"`
+	Tags       []string         `json:"tags,omitempty"`
+	MinPrice   *int64           `json:"min_price,omitempty" validate:"omitempty,min=0"`
+	MaxPrice   *int64           `json:"max_price,omitempty" validate:"omitempty,min=0"`
+	Sort       entity.MerchSort `json:"sort,omitempty"`
+	Page       int              `json:"page,omitempty" validate:"omitempty,min=1"`
+	PageSize   int              `json:"page_size,omitempty" validate:"omitempty,min=1,max=100"`
+}
+
+type CatalogPage struct {
+	Items    []MerchItemDTO `json:"items"`
+	Total    int64          `json:"total"`
+	Page     int            `json:"page"`
+	PageSize int            `json:"page_size"`
+}
+
+// PriceHistory lists an item's prices, oldest first, including the changes
+// scheduled for the future.
+type PriceHistory struct {
+	ItemID  int64           `json:"item_id"`
+	Name    string          `json:"name"`
+	Current int64           `json:"current_price"`
+	Prices  []PriceEntryDTO `json:"prices"`
+}
+
+type PriceEntryDTO struct {
+	ID            int64     `json:"id"`
+	Price         int64     `json:"price"`
+	EffectiveFrom time.Time `json:"effective_from"`
+	Scheduled     bool      `json:"scheduled"`
 }
 
 type UserDTO struct {
@@ -52,3 +96,25 @@ type TransactionInfo struct {
 	ItemName  *string                `json:"item_name,omitempty"`
 	CreatedAt time.Time              `json:"created_at"`
 }
+
+// CreateBundleRequest defines a bundle sold at Price. Components of items
+// that come in variants may leave VariantID out to let the buyer pick one.
+type CreateBundleRequest struct {
+	Name        string              `json:"name" validate:"required"`
+	Price       int64               `json:"price" validate:"required,min=1"`
+	Description string              `json:"description,omitempty"`
+	Items       []BundleItemRequest `json:"items" validate:"required,min=1,dive"`
+}
+
+type BundleItemRequest struct {
+	ItemName  string `json:"item" validate:"required"`
+	VariantID *int64 `json:"variant_id,omitempty"`
+	Quantity  int64  `json:"quantity" validate:"required,min=1"`
+}
+
+// BuyBundleRequest buys a bundle. Variants picks, by item name, the variant
+// of every component the bundle leaves open.
+type BuyBundleRequest struct {
+	Bundle   string           `json:"bundle" validate:"required"`
+	Variants map[string]int64 `json:"variants,omitempty"`
+}
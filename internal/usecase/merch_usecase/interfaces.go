@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 	"github.com/smthjapanese/avito-merch/internal/entity"
+	"time"
 )
 
 //go:generate mockgen -source=interfaces.go -destination=./mocks/mocks.go -package=mocks
@@ -20,6 +21,17 @@ type MerchRepository interface {
 	List(ctx context.Context) ([]entity.MerchItem, error)
 	GetByID(ctx context.Context, id int64) (entity.MerchItem, error)
 	GetByName(ctx context.Context, name string) (entity.MerchItem, error)
+	ListCategories(ctx context.Context) ([]entity.MerchCategory, error)
+	Search(ctx context.Context, q entity.MerchQuery) ([]entity.MerchItem, int64, error)
+	ListVariants(ctx context.Context, itemID int64) ([]entity.MerchVariant, error)
+	GetVariant(ctx context.Context, id int64) (entity.MerchVariant, error)
+	DecrementStock(ctx context.Context, id int64) (bool, error)
+	AddPrice(ctx context.Context, price *entity.MerchPrice) error
+	ListPrices(ctx context.Context, itemID int64) ([]entity.MerchPrice, error)
+	DeleteScheduledPrice(ctx context.Context, itemID, id int64, now time.Time) (bool, error)
+	CreateBundle(ctx context.Context, bundle *entity.Bundle) error
+	GetBundleByName(ctx context.Context, name string) (entity.Bundle, error)
+	ListBundles(ctx context.Context) ([]entity.Bundle, error)
 }
 
 type InventoryRepository interface {
@@ -28,6 +40,36 @@ type InventoryRepository interface {
 	Create(ctx context.Context, inventory entity.UserInventory) error
 }
 
+// LotRepository takes spent coins out of the buyer's oldest lots first.
+type LotRepository interface {
+	Spend(ctx context.Context, userID int64, amount int64) ([]entity.CoinLot, error)
+}
+
+// PromoRepository prices purchases with promo codes and scheduled sales.
+type PromoRepository interface {
+	GetCode(ctx context.Context, code string) (entity.PromoCode, error)
+	HasRedeemed(ctx context.Context, codeID, userID int64) (bool, error)
+	Redeem(ctx context.Context, codeID, userID int64) error
+	ActiveSales(ctx context.Context, itemID int64, now time.Time) ([]entity.MerchSale, error)
+}
+
+// OrderRepository opens an order for every purchase so the item can be
+// tracked until it is handed over.
+type OrderRepository interface {
+	Create(ctx context.Context, order *entity.Order) error
+}
+
+// FraudChecker screens a purchase before it is made.
+type FraudChecker interface {
+	Check(ctx context.Context, op entity.FraudOperation) (entity.FraudAction, error)
+}
+
+// Localizer translates item names and descriptions into the language the
+// client asked for.
+type Localizer interface {
+	Localize(ctx context.Context, items []entity.MerchItem, acceptLanguage string) ([]entity.MerchTranslation, error)
+}
+
 type UserRepository interface {
 	Create(ctx context.Context, user *entity.User) error
 	GetByID(ctx context.Context, id int64) (*entity.User, error)
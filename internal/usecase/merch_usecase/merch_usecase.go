@@ -3,15 +3,26 @@ package usecase
 import (
 	"context"
 	"github.com/smthjapanese/avito-merch/internal/entity"
+	"strings"
 	"time"
 )
 
+const (
+	_defaultPageSize = 20
+	_maxPageSize     = 100
+)
+
 type MerchUseCase struct {
 	merchRepo    MerchRepository
 	userRepo     UserRepository
 	invRepo      InventoryRepository
 	txRepo       TransactionRepository
+	lotRepo      LotRepository
+	promoRepo    PromoRepository
+	orderRepo    OrderRepository
 	dbTransactor DBTransactor
+	fraud        FraudChecker
+	localizer    Localizer
 }
 
 func NewMerchUseCase(
@@ -19,86 +30,292 @@ func NewMerchUseCase(
 	userRepo UserRepository,
 	invRepo InventoryRepository,
 	txRepo TransactionRepository,
+	lotRepo LotRepository,
+	promoRepo PromoRepository,
+	orderRepo OrderRepository,
 	dbTransactor DBTransactor,
+	fraud FraudChecker,
+	localizer Localizer,
 ) MerchUseCase {
 	return MerchUseCase{
 		merchRepo:    merchRepo,
 		userRepo:     userRepo,
 		invRepo:      invRepo,
 		txRepo:       txRepo,
+		lotRepo:      lotRepo,
+		promoRepo:    promoRepo,
+		orderRepo:    orderRepo,
 		dbTransactor: dbTransactor,
+		fraud:        fraud,
+		localizer:    localizer,
 	}
 }
 
-func (uc *MerchUseCase) ListAvailable(ctx context.Context) ([]MerchItemDTO, error) {
+// ListAvailable lists the catalog in the language preferred by the
+// Accept-Language header. Items keep their name, which is what they are
+// bought by; the translated one is the display name.
+func (uc *MerchUseCase) ListAvailable(ctx context.Context, acceptLanguage string) ([]MerchItemDTO, error) {
 	items, err := uc.merchRepo.List(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	translations, err := uc.localizer.Localize(ctx, items, acceptLanguage)
+	if err != nil {
+		return nil, err
+	}
+
 	result := make([]MerchItemDTO, len(items))
 	for i, item := range items {
-		result[i] = MerchItemDTO{
-			ID:    item.ID,
-			Name:  item.Name,
-			Price: item.Price,
-		}
+		result[i] = newMerchItemDTO(item)
+		result[i].DisplayName = translations[i].Name
+		result[i].Description = translations[i].Description
+		result[i].Locale = translations[i].Locale
 	}
 	return result, nil
 }
 
-func (uc *MerchUseCase) BuyItem(ctx context.Context, userID int64, itemName string) error {
-	return uc.dbTransactor.WithinTransaction(ctx, func(ctx context.Context) error {
-		item, err := uc.merchRepo.GetByName(ctx, itemName)
-		if err != nil {
-			return entity.ErrMerchNotFound
+// Catalog returns the page of the catalog selected by req. Without an
+// explicit sort, search results come most relevant first and everything
+// else in catalog order.
+func (uc *MerchUseCase) Catalog(ctx context.Context, req CatalogRequest) (CatalogPage, error) {
+	sort := req.Sort
+	if sort == "" {
+		sort = entity.MerchSortRelevance
+	}
+	if !sort.IsValid() {
+		return CatalogPage{}, entity.ErrInvalidMerchSort
+	}
+	if req.MinPrice != nil && req.MaxPrice != nil && *req.MinPrice > *req.MaxPrice {
+		return CatalogPage{}, entity.ErrInvalidPriceRange
+	}
+
+	page := max(req.Page, 1)
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = _defaultPageSize
+	}
+	pageSize = min(pageSize, _maxPageSize)
+
+	items, total, err := uc.merchRepo.Search(ctx, entity.MerchQuery{
+		Search:     strings.TrimSpace(req.Query),
+		CategoryID: req.CategoryID,
+		Tags:       entity.NormalizeTags(req.Tags),
+		MinPrice:   req.MinPrice,
+		MaxPrice:   req.MaxPrice,
+		Sort:       sort,
+		Limit:      pageSize,
+		Offset:     (page - 1) * pageSize,
+	})
+	if err != nil {
+		return CatalogPage{}, err
+	}
+
+	result := CatalogPage{
+		Items:    make([]MerchItemDTO, len(items)),
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}
+	for i, item := range items {
+		result.Items[i] = newMerchItemDTO(item)
+	}
+	return result, nil
+}
+
+func (uc *MerchUseCase) ListCategories(ctx context.Context) ([]entity.MerchCategory, error) {
+	return uc.merchRepo.ListCategories(ctx)
+}
+
+func (uc *MerchUseCase) ListVariants(ctx context.Context, itemName string) ([]entity.MerchVariant, error) {
+	item, err := uc.merchRepo.GetByName(ctx, itemName)
+	if err != nil {
+		return nil, entity.ErrMerchNotFound
+	}
+
+	return uc.merchRepo.ListVariants(ctx, item.ID)
+}
+
+func (uc *MerchUseCase) PriceHistory(ctx context.Context, itemName string) (PriceHistory, error) {
+	item, err := uc.merchRepo.GetByName(ctx, itemName)
+	if err != nil {
+		return PriceHistory{}, entity.ErrMerchNotFound
+	}
+
+	prices, err := uc.merchRepo.ListPrices(ctx, item.ID)
+	if err != nil {
+		return PriceHistory{}, err
+	}
+
+	now := time.Now()
+	history := PriceHistory{
+		ItemID:  item.ID,
+		Name:    item.Name,
+		Current: item.Price,
+		Prices:  make([]PriceEntryDTO, len(prices)),
+	}
+	for i, price := range prices {
+		history.Prices[i] = PriceEntryDTO{
+			ID:            price.ID,
+			Price:         price.Price,
+			EffectiveFrom: price.EffectiveFrom,
+			Scheduled:     price.IsScheduled(now),
 		}
+	}
+	return history, nil
+}
+
+// SchedulePriceChange sets a new price for the item from effectiveFrom on.
+// A zero effectiveFrom makes the change immediate.
+func (uc *MerchUseCase) SchedulePriceChange(ctx context.Context, adminID int64, itemName string, price int64, effectiveFrom time.Time) (entity.MerchPrice, error) {
+	if price <= 0 {
+		return entity.MerchPrice{}, entity.ErrInvalidPrice
+	}
+
+	now := time.Now()
+	if effectiveFrom.IsZero() {
+		effectiveFrom = now
+	} else if effectiveFrom.Before(now) {
+		return entity.MerchPrice{}, entity.ErrPriceChangeInPast
+	}
+
+	if err := uc.requireAdmin(ctx, adminID); err != nil {
+		return entity.MerchPrice{}, err
+	}
+
+	item, err := uc.merchRepo.GetByName(ctx, itemName)
+	if err != nil {
+		return entity.MerchPrice{}, entity.ErrMerchNotFound
+	}
+
+	change := entity.MerchPrice{
+		ItemID:        item.ID,
+		Price:         price,
+		EffectiveFrom: effectiveFrom,
+		CreatedBy:     &adminID,
+	}
+	if err := uc.merchRepo.AddPrice(ctx, &change); err != nil {
+		return entity.MerchPrice{}, err
+	}
+
+	return change, nil
+}
+
+// CancelPriceChange drops a price change that has not taken effect yet.
+func (uc *MerchUseCase) CancelPriceChange(ctx context.Context, adminID int64, itemName string, id int64) error {
+	if err := uc.requireAdmin(ctx, adminID); err != nil {
+		return err
+	}
+
+	item, err := uc.merchRepo.GetByName(ctx, itemName)
+	if err != nil {
+		return entity.ErrMerchNotFound
+	}
+
+	deleted, err := uc.merchRepo.DeleteScheduledPrice(ctx, item.ID, id, time.Now())
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return entity.ErrPriceChangeNotFound
+	}
+
+	return nil
+}
+
+// BuyItem buys one unit of the item at the price in effect now. Items that
+// come in variants need variantID to name one of them; it is ignored for
+// items without variants. Upcoming items cannot be bought before their
+// release, only pre-ordered.
+// The best running sale is applied to the price, then promoCode if one is
+// given.
+func (uc *MerchUseCase) BuyItem(ctx context.Context, userID int64, itemName string, variantID *int64, promoCode string) error {
+	item, err := uc.merchRepo.GetByName(ctx, itemName)
+	if err != nil {
+		return entity.ErrMerchNotFound
+	}
+	if !item.IsReleased(time.Now()) {
+		return entity.ErrItemNotReleased
+	}
+
+	variant, err := uc.variant(ctx, item, variantID)
+	if err != nil {
+		return err
+	}
+
+	listPrice := item.Price
+	if variant != nil {
+		listPrice = variant.Price(item)
+	}
+
+	// screened at the list price so discounts cannot slip a purchase under
+	// a rule
+	action, err := uc.fraud.Check(ctx, entity.FraudOperation{
+		Type:       entity.TransactionTypePurchase,
+		FromUserID: userID,
+		ToUserID:   userID,
+		ItemID:     &item.ID,
+		Amount:     listPrice,
+		At:         time.Now(),
+	})
+	if err != nil {
+		return entity.ErrTransactionFailed
+	}
+	if action == entity.FraudActionBlock {
+		return entity.ErrOperationBlocked
+	}
 
+	return uc.dbTransactor.WithinTransaction(ctx, func(ctx context.Context) error {
 		user, err := uc.userRepo.GetByID(ctx, userID)
 		if err != nil {
 			return entity.ErrUserNotFound
 		}
 
-		if user.Coins < item.Price {
-			return entity.ErrInsufficientFunds
+		price, promo, err := uc.price(ctx, userID, item.ID, listPrice, promoCode)
+		if err != nil {
+			return err
 		}
 
-		user.Coins -= item.Price
-		if err := uc.userRepo.Update(ctx, user); err != nil {
-			return err
+		if user.Coins < price {
+			return entity.ErrInsufficientFunds
 		}
 
-		inventory, err := uc.invRepo.GetByUserID(ctx, userID)
-		var found bool
-		for i := range inventory {
-			if inventory[i].ItemID == item.ID {
-				inventory[i].Quantity++
-				if err := uc.invRepo.Update(ctx, inventory[i]); err != nil {
-					return err
-				}
-				found = true
-				break
+		if variant != nil {
+			ok, err := uc.merchRepo.DecrementStock(ctx, variant.ID)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return entity.ErrOutOfStock
 			}
+			variantID = &variant.ID
 		}
 
-		if !found {
-			newInventory := entity.UserInventory{
-				UserID:      userID,
-				ItemID:      item.ID,
-				Quantity:    1,
-				PurchasedAt: time.Now(),
-			}
-			if err := uc.invRepo.Create(ctx, newInventory); err != nil {
+		user.Coins -= price
+		if err := uc.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+		paid, err := uc.lotRepo.Spend(ctx, userID, price)
+		if err != nil {
+			return err
+		}
+		if promo != nil {
+			if err := uc.promoRepo.Redeem(ctx, promo.ID, userID); err != nil {
 				return err
 			}
 		}
 
+		if err := uc.addToInventory(ctx, userID, item.ID, variantID, 1); err != nil {
+			return err
+		}
+
 		transaction := entity.Transaction{
 			FromUserID: userID,
 			ToUserID:   userID, // self-transaction for purchase
-			Amount:     item.Price,
+			Amount:     price,
 			Type:       entity.TransactionTypePurchase,
 			ItemID:     &item.ID,
+			ListPrice:  &listPrice,
 			CreatedAt:  time.Now(),
 		}
 
@@ -106,6 +323,127 @@ func (uc *MerchUseCase) BuyItem(ctx context.Context, userID int64, itemName stri
 			return err
 		}
 
+		order := entity.Order{
+			UserID:    userID,
+			ItemID:    item.ID,
+			VariantID: variantID,
+			Price:     price,
+			Status:    entity.OrderStatusPlaced,
+			Lots:      paid,
+		}
+		if err := uc.orderRepo.Create(ctx, &order); err != nil {
+			return err
+		}
+
 		return nil
 	})
 }
+
+// addToInventory puts quantity units of the item into the user's inventory,
+// stacking them onto the entry already holding that item and variant.
+func (uc *MerchUseCase) addToInventory(ctx context.Context, userID, itemID int64, variantID *int64, quantity int64) error {
+	inventory, err := uc.invRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for i := range inventory {
+		if inventory[i].Holds(itemID, variantID) {
+			inventory[i].Quantity += quantity
+			return uc.invRepo.Update(ctx, inventory[i])
+		}
+	}
+
+	return uc.invRepo.Create(ctx, entity.UserInventory{
+		UserID:      userID,
+		ItemID:      itemID,
+		VariantID:   variantID,
+		Quantity:    quantity,
+		PurchasedAt: time.Now(),
+	})
+}
+
+// price applies the running sales and the promo code, if any, to the list
+// price. The code is returned so its use can be recorded once the purchase
+// goes through.
+func (uc *MerchUseCase) price(ctx context.Context, userID, itemID, listPrice int64, code string) (int64, *entity.PromoCode, error) {
+	now := time.Now()
+
+	sales, err := uc.promoRepo.ActiveSales(ctx, itemID, now)
+	if err != nil {
+		return 0, nil, err
+	}
+	price := entity.SalePrice(sales, itemID, listPrice, now)
+
+	code = entity.NormalizePromoCode(code)
+	if code == "" {
+		return price, nil, nil
+	}
+
+	promo, err := uc.promoRepo.GetCode(ctx, code)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := promo.CheckUsable(itemID, now); err != nil {
+		return 0, nil, err
+	}
+	if promo.OncePerUser {
+		used, err := uc.promoRepo.HasRedeemed(ctx, promo.ID, userID)
+		if err != nil {
+			return 0, nil, err
+		}
+		if used {
+			return 0, nil, entity.ErrPromoCodeUsed
+		}
+	}
+
+	return promo.Apply(price), &promo, nil
+}
+
+// variant resolves the variant a purchase is for, or nil when the item has
+// no variants.
+func (uc *MerchUseCase) variant(ctx context.Context, item entity.MerchItem, variantID *int64) (*entity.MerchVariant, error) {
+	if variantID != nil {
+		variant, err := uc.merchRepo.GetVariant(ctx, *variantID)
+		if err != nil {
+			return nil, err
+		}
+		if variant.ItemID != item.ID {
+			return nil, entity.ErrVariantNotFound
+		}
+		return &variant, nil
+	}
+
+	variants, err := uc.merchRepo.ListVariants(ctx, item.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(variants) > 0 {
+		return nil, entity.ErrVariantRequired
+	}
+	return nil, nil
+}
+
+func newMerchItemDTO(item entity.MerchItem) MerchItemDTO {
+	return MerchItemDTO{
+		ID:          item.ID,
+		Name:        item.Name,
+		Price:       item.Price,
+		Category:    item.Category,
+		Description: item.Description,
+		ImageURL:    item.ImageURL,
+		Tags:        item.Tags,
+	}
+}
+
+func (uc *MerchUseCase) requireAdmin(ctx context.Context, adminID int64) error {
+	admin, err := uc.userRepo.GetByID(ctx, adminID)
+	if err != nil {
+		return err
+	}
+	if admin == nil || !admin.IsAdmin() {
+		return entity.ErrForbidden
+	}
+
+	return nil
+}
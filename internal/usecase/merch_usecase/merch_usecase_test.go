@@ -17,6 +17,26 @@ type test struct {
 	err  error
 }
 
+// anyOrders accepts the orders opened by purchases.
+func anyOrders(ctrl *gomock.Controller) *mocks.MockOrderRepository {
+	orderRepo := mocks.NewMockOrderRepository(ctrl)
+	orderRepo.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		Return(nil).
+		AnyTimes()
+	return orderRepo
+}
+
+// noPromos stubs a promo repository with no sales running.
+func noPromos(ctrl *gomock.Controller) *mocks.MockPromoRepository {
+	promoRepo := mocks.NewMockPromoRepository(ctrl)
+	promoRepo.EXPECT().
+		ActiveSales(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, nil).
+		AnyTimes()
+	return promoRepo
+}
+
 func TestMerchUseCase_ListAvailable(t *testing.T) {
 	t.Parallel()
 
@@ -27,9 +47,11 @@ func TestMerchUseCase_ListAvailable(t *testing.T) {
 	userRepo := mocks.NewMockUserRepository(ctrl)
 	invRepo := mocks.NewMockInventoryRepository(ctrl)
 	txRepo := mocks.NewMockTransactionRepository(ctrl)
+	lotRepo := mocks.NewMockLotRepository(ctrl)
 	dbTransactor := mocks.NewMockDBTransactor(ctrl)
+	localizer := mocks.NewMockLocalizer(ctrl)
 
-	uc := NewMerchUseCase(merchRepo, userRepo, invRepo, txRepo, dbTransactor)
+	uc := NewMerchUseCase(merchRepo, userRepo, invRepo, txRepo, lotRepo, noPromos(ctrl), anyOrders(ctrl), dbTransactor, mocks.NewMockFraudChecker(ctrl), localizer)
 
 	testTime := time.Date(2024, 2, 15, 12, 0, 0, 0, time.UTC)
 	testItems := []entity.MerchItem{
@@ -54,17 +76,28 @@ func TestMerchUseCase_ListAvailable(t *testing.T) {
 				merchRepo.EXPECT().
 					List(gomock.Any()).
 					Return(testItems, nil)
+				localizer.EXPECT().
+					Localize(gomock.Any(), testItems, "ru-RU,ru;q=0.9").
+					Return([]entity.MerchTranslation{
+						{ItemID: 1, Locale: "ru", Name: "Тестовый товар 1", Description: "Описание"},
+						entity.OriginalTranslation(testItems[1], "en"),
+					}, nil)
 			},
 			res: []MerchItemDTO{
 				{
-					ID:    1,
-					Name:  "Test Item 1",
-					Price: 100,
+					ID:          1,
+					Name:        "Test Item 1",
+					Price:       100,
+					Description: "Описание",
+					DisplayName: "Тестовый товар 1",
+					Locale:      "ru",
 				},
 				{
-					ID:    2,
-					Name:  "Test Item 2",
-					Price: 200,
+					ID:          2,
+					Name:        "Test Item 2",
+					Price:       200,
+					DisplayName: "Test Item 2",
+					Locale:      "en",
 				},
 			},
 			err: nil,
@@ -86,7 +119,7 @@ func TestMerchUseCase_ListAvailable(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.mock()
 
-			items, err := uc.ListAvailable(context.Background())
+			items, err := uc.ListAvailable(context.Background(), "ru-RU,ru;q=0.9")
 
 			if tc.err != nil {
 				require.ErrorIs(t, err, tc.err)
@@ -98,6 +131,120 @@ func TestMerchUseCase_ListAvailable(t *testing.T) {
 	}
 }
 
+func TestMerchUseCase_Catalog(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	merchRepo := mocks.NewMockMerchRepository(ctrl)
+
+	uc := NewMerchUseCase(
+		merchRepo,
+		mocks.NewMockUserRepository(ctrl),
+		mocks.NewMockInventoryRepository(ctrl),
+		mocks.NewMockTransactionRepository(ctrl),
+		mocks.NewMockLotRepository(ctrl),
+		mocks.NewMockPromoRepository(ctrl),
+		mocks.NewMockOrderRepository(ctrl),
+		mocks.NewMockDBTransactor(ctrl),
+		mocks.NewMockFraudChecker(ctrl),
+		nil,
+	)
+
+	price := func(v int64) *int64 { return &v }
+	categoryID := int64(2)
+	hoodie := entity.MerchItem{
+		ID:          1,
+		Name:        "hoodie",
+		Price:       300,
+		CategoryID:  &categoryID,
+		Category:    "clothing",
+		Description: "Warm hoodie",
+		Tags:        []string{"winter"},
+	}
+
+	tests := []struct {
+		test
+		req CatalogRequest
+	}{
+		{
+			test: test{
+				name: "defaults",
+				mock: func() {
+					merchRepo.EXPECT().
+						Search(gomock.Any(), entity.MerchQuery{
+							Search: "hoodie",
+							Tags:   []string{"winter"},
+							Sort:   entity.MerchSortRelevance,
+							Limit:  _defaultPageSize,
+							Offset: 0,
+						}).
+						Return([]entity.MerchItem{hoodie}, int64(1), nil)
+				},
+				res: CatalogPage{
+					Items: []MerchItemDTO{
+						{
+							ID:          1,
+							Name:        "hoodie",
+							Price:       300,
+							Category:    "clothing",
+							Description: "Warm hoodie",
+							Tags:        []string{"winter"},
+						},
+					},
+					Total:    1,
+					Page:     1,
+					PageSize: _defaultPageSize,
+				},
+			},
+			req: CatalogRequest{Query: "  hoodie ", Tags: []string{" Winter", "winter"}},
+		},
+		{
+			test: test{
+				name: "page past the first",
+				mock: func() {
+					merchRepo.EXPECT().
+						Search(gomock.Any(), entity.MerchQuery{
+							CategoryID: &categoryID,
+							Tags:       []string{},
+							MinPrice:   price(100),
+							Sort:       entity.MerchSortPriceAsc,
+							Limit:      _maxPageSize,
+							Offset:     2 * _maxPageSize,
+						}).
+						Return(nil, int64(250), nil)
+				},
+				res: CatalogPage{Items: []MerchItemDTO{}, Total: 250, Page: 3, PageSize: _maxPageSize},
+			},
+			req: CatalogRequest{CategoryID: &categoryID, MinPrice: price(100), Sort: entity.MerchSortPriceAsc, Page: 3, PageSize: 500},
+		},
+		{
+			test: test{name: "unknown sort", mock: func() {}, err: entity.ErrInvalidMerchSort},
+			req:  CatalogRequest{Sort: "popularity"},
+		},
+		{
+			test: test{name: "inverted price range", mock: func() {}, err: entity.ErrInvalidPriceRange},
+			req:  CatalogRequest{MinPrice: price(500), MaxPrice: price(100)},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			page, err := uc.Catalog(context.Background(), tc.req)
+
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.res, page)
+			}
+		})
+	}
+}
+
 func TestMerchUseCase_BuyItem(t *testing.T) {
 	t.Parallel()
 
@@ -108,9 +255,16 @@ func TestMerchUseCase_BuyItem(t *testing.T) {
 	userRepo := mocks.NewMockUserRepository(ctrl)
 	invRepo := mocks.NewMockInventoryRepository(ctrl)
 	txRepo := mocks.NewMockTransactionRepository(ctrl)
+	lotRepo := mocks.NewMockLotRepository(ctrl)
 	dbTransactor := mocks.NewMockDBTransactor(ctrl)
 
-	uc := NewMerchUseCase(merchRepo, userRepo, invRepo, txRepo, dbTransactor)
+	fraud := mocks.NewMockFraudChecker(ctrl)
+	fraud.EXPECT().
+		Check(gomock.Any(), gomock.Any()).
+		Return(entity.FraudActionNone, nil).
+		AnyTimes()
+
+	uc := NewMerchUseCase(merchRepo, userRepo, invRepo, txRepo, lotRepo, noPromos(ctrl), anyOrders(ctrl), dbTransactor, fraud, nil)
 
 	testTime := time.Date(2024, 2, 15, 12, 0, 0, 0, time.UTC)
 	userID := int64(1)
@@ -140,6 +294,12 @@ func TestMerchUseCase_BuyItem(t *testing.T) {
 		},
 	}
 
+	// the test item comes in a single version
+	merchRepo.EXPECT().
+		ListVariants(gomock.Any(), testItem.ID).
+		Return(nil, nil).
+		AnyTimes()
+
 	tests := []test{
 		{
 			name: "success_first_purchase",
@@ -157,9 +317,11 @@ func TestMerchUseCase_BuyItem(t *testing.T) {
 					GetByName(gomock.Any(), itemName).
 					Return(testItem, nil)
 
+				// a copy, as BuyItem charges the user it is given
+				buyer := *testUser
 				userRepo.EXPECT().
 					GetByID(gomock.Any(), userID).
-					Return(testUser, nil)
+					Return(&buyer, nil)
 
 				userRepo.EXPECT().
 					Update(gomock.Any(), gomock.Any()).
@@ -168,6 +330,10 @@ func TestMerchUseCase_BuyItem(t *testing.T) {
 						return nil
 					})
 
+				lotRepo.EXPECT().
+					Spend(gomock.Any(), userID, testItem.Price).
+					Return(nil, nil)
+
 				invRepo.EXPECT().
 					GetByUserID(gomock.Any(), userID).
 					Return(nil, nil)
@@ -211,9 +377,10 @@ func TestMerchUseCase_BuyItem(t *testing.T) {
 					GetByName(gomock.Any(), itemName).
 					Return(testItem, nil)
 
+				buyer := *testUser
 				userRepo.EXPECT().
 					GetByID(gomock.Any(), userID).
-					Return(testUser, nil)
+					Return(&buyer, nil)
 
 				userRepo.EXPECT().
 					Update(gomock.Any(), gomock.Any()).
@@ -222,6 +389,10 @@ func TestMerchUseCase_BuyItem(t *testing.T) {
 						return nil
 					})
 
+				lotRepo.EXPECT().
+					Spend(gomock.Any(), userID, testItem.Price).
+					Return(nil, nil)
+
 				invRepo.EXPECT().
 					GetByUserID(gomock.Any(), userID).
 					Return(testInventory, nil)
@@ -248,12 +419,6 @@ func TestMerchUseCase_BuyItem(t *testing.T) {
 		{
 			name: "item_not_found",
 			mock: func() {
-				dbTransactor.EXPECT().
-					WithinTransaction(gomock.Any(), gomock.Any()).
-					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
-						return fn(ctx)
-					})
-
 				merchRepo.EXPECT().
 					GetByName(gomock.Any(), itemName).
 					Return(entity.MerchItem{}, entity.ErrMerchNotFound)
@@ -297,9 +462,10 @@ func TestMerchUseCase_BuyItem(t *testing.T) {
 					GetByName(gomock.Any(), itemName).
 					Return(testItem, nil)
 
+				buyer := *testUser
 				userRepo.EXPECT().
 					GetByID(gomock.Any(), userID).
-					Return(testUser, nil)
+					Return(&buyer, nil)
 
 				userRepo.EXPECT().
 					Update(gomock.Any(), gomock.Any()).
@@ -320,14 +486,19 @@ func TestMerchUseCase_BuyItem(t *testing.T) {
 					GetByName(gomock.Any(), itemName).
 					Return(testItem, nil)
 
+				buyer := *testUser
 				userRepo.EXPECT().
 					GetByID(gomock.Any(), userID).
-					Return(testUser, nil)
+					Return(&buyer, nil)
 
 				userRepo.EXPECT().
 					Update(gomock.Any(), gomock.Any()).
 					Return(nil)
 
+				lotRepo.EXPECT().
+					Spend(gomock.Any(), userID, testItem.Price).
+					Return(nil, nil)
+
 				invRepo.EXPECT().
 					GetByUserID(gomock.Any(), userID).
 					Return(testInventory, nil)
@@ -344,7 +515,7 @@ func TestMerchUseCase_BuyItem(t *testing.T) {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
 			tc.mock()
-			err := uc.BuyItem(context.Background(), userID, itemName)
+			err := uc.BuyItem(context.Background(), userID, itemName, nil, "")
 			if tc.err != nil {
 				require.ErrorIs(t, err, tc.err)
 			} else {
@@ -352,4 +523,439 @@ func TestMerchUseCase_BuyItem(t *testing.T) {
 			}
 		})
 	}
+
+	t.Run("blocked_by_fraud_rules", func(t *testing.T) {
+		blocking := mocks.NewMockFraudChecker(ctrl)
+		uc := NewMerchUseCase(merchRepo, userRepo, invRepo, txRepo, lotRepo, noPromos(ctrl), anyOrders(ctrl), dbTransactor, blocking, nil)
+
+		merchRepo.EXPECT().
+			GetByName(gomock.Any(), itemName).
+			Return(testItem, nil)
+
+		blocking.EXPECT().
+			Check(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, op entity.FraudOperation) (entity.FraudAction, error) {
+				require.Equal(t, entity.TransactionTypePurchase, op.Type)
+				require.Equal(t, testItem.ID, *op.ItemID)
+				require.Equal(t, testItem.Price, op.Amount)
+				return entity.FraudActionBlock, nil
+			})
+
+		err := uc.BuyItem(context.Background(), userID, itemName, nil, "")
+		require.ErrorIs(t, err, entity.ErrOperationBlocked)
+	})
+}
+
+func TestMerchUseCase_BuyVariant(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	merchRepo := mocks.NewMockMerchRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	invRepo := mocks.NewMockInventoryRepository(ctrl)
+	txRepo := mocks.NewMockTransactionRepository(ctrl)
+	lotRepo := mocks.NewMockLotRepository(ctrl)
+	dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+	fraud := mocks.NewMockFraudChecker(ctrl)
+	fraud.EXPECT().
+		Check(gomock.Any(), gomock.Any()).
+		Return(entity.FraudActionNone, nil).
+		AnyTimes()
+
+	dbTransactor.EXPECT().
+		WithinTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		}).
+		AnyTimes()
+
+	uc := NewMerchUseCase(merchRepo, userRepo, invRepo, txRepo, lotRepo, noPromos(ctrl), anyOrders(ctrl), dbTransactor, fraud, nil)
+
+	userID := int64(1)
+	hoody := entity.MerchItem{ID: 1, Name: "hoody", Price: 300}
+	large := entity.MerchVariant{ID: 7, ItemID: 1, Size: "L", Stock: 3, PriceDelta: 20}
+	medium := int64(6)
+
+	merchRepo.EXPECT().
+		GetByName(gomock.Any(), "hoody").
+		Return(hoody, nil).
+		AnyTimes()
+
+	variantID := func(id int64) *int64 { return &id }
+
+	tests := []test{
+		{
+			name: "success_separate_row_per_variant",
+			mock: func() {
+				merchRepo.EXPECT().GetVariant(gomock.Any(), large.ID).Return(large, nil)
+				userRepo.EXPECT().
+					GetByID(gomock.Any(), userID).
+					Return(&entity.User{ID: userID, Coins: 1000}, nil)
+				merchRepo.EXPECT().DecrementStock(gomock.Any(), large.ID).Return(true, nil)
+
+				userRepo.EXPECT().
+					Update(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, user *entity.User) error {
+						require.Equal(t, int64(680), user.Coins)
+						return nil
+					})
+				lotRepo.EXPECT().Spend(gomock.Any(), userID, int64(320)).Return(nil, nil)
+
+				// the user already owns the hoody in another size
+				invRepo.EXPECT().
+					GetByUserID(gomock.Any(), userID).
+					Return([]entity.UserInventory{{ID: 1, UserID: userID, ItemID: 1, VariantID: &medium, Quantity: 1}}, nil)
+				invRepo.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, inventory entity.UserInventory) error {
+						require.Equal(t, large.ID, *inventory.VariantID)
+						require.Equal(t, int64(1), inventory.Quantity)
+						return nil
+					})
+
+				txRepo.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, tr entity.Transaction) error {
+						require.Equal(t, int64(320), tr.Amount)
+						return nil
+					})
+			},
+			res: variantID(large.ID),
+		},
+		{
+			name: "out_of_stock",
+			mock: func() {
+				merchRepo.EXPECT().GetVariant(gomock.Any(), large.ID).Return(large, nil)
+				userRepo.EXPECT().
+					GetByID(gomock.Any(), userID).
+					Return(&entity.User{ID: userID, Coins: 1000}, nil)
+				merchRepo.EXPECT().DecrementStock(gomock.Any(), large.ID).Return(false, nil)
+			},
+			res: variantID(large.ID),
+			err: entity.ErrOutOfStock,
+		},
+		{
+			name: "variant_of_another_item",
+			mock: func() {
+				merchRepo.EXPECT().
+					GetVariant(gomock.Any(), int64(9)).
+					Return(entity.MerchVariant{ID: 9, ItemID: 2}, nil)
+			},
+			res: variantID(9),
+			err: entity.ErrVariantNotFound,
+		},
+		{
+			name: "variant_required",
+			mock: func() {
+				merchRepo.EXPECT().
+					ListVariants(gomock.Any(), hoody.ID).
+					Return([]entity.MerchVariant{large}, nil)
+			},
+			res: (*int64)(nil),
+			err: entity.ErrVariantRequired,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+			err := uc.BuyItem(context.Background(), userID, "hoody", tc.res.(*int64), "")
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestMerchUseCase_BuyUnreleased(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	merchRepo := mocks.NewMockMerchRepository(ctrl)
+	uc := NewMerchUseCase(merchRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	releaseAt := time.Now().Add(time.Hour)
+	limit := int64(10)
+	merchRepo.EXPECT().
+		GetByName(gomock.Any(), "console").
+		Return(entity.MerchItem{ID: 4, Name: "console", Price: 5000, ReleaseAt: &releaseAt, PreorderLimit: &limit}, nil)
+
+	err := uc.BuyItem(context.Background(), 1, "console", nil, "")
+	require.ErrorIs(t, err, entity.ErrItemNotReleased)
+}
+
+func TestMerchUseCase_BuyWithDiscounts(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	merchRepo := mocks.NewMockMerchRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	invRepo := mocks.NewMockInventoryRepository(ctrl)
+	txRepo := mocks.NewMockTransactionRepository(ctrl)
+	lotRepo := mocks.NewMockLotRepository(ctrl)
+	promoRepo := mocks.NewMockPromoRepository(ctrl)
+	orderRepo := mocks.NewMockOrderRepository(ctrl)
+	dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+	fraud := mocks.NewMockFraudChecker(ctrl)
+	fraud.EXPECT().
+		Check(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, op entity.FraudOperation) (entity.FraudAction, error) {
+			require.Equal(t, int64(300), op.Amount)
+			return entity.FraudActionNone, nil
+		}).
+		AnyTimes()
+
+	dbTransactor.EXPECT().
+		WithinTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		}).
+		AnyTimes()
+
+	uc := NewMerchUseCase(merchRepo, userRepo, invRepo, txRepo, lotRepo, promoRepo, orderRepo, dbTransactor, fraud, nil)
+
+	userID := int64(1)
+	hoody := entity.MerchItem{ID: 1, Name: "hoody", Price: 300}
+	sale := entity.MerchSale{
+		Discount: entity.Discount{Kind: entity.DiscountKindPercent, Value: 10},
+		StartsAt: time.Now().Add(-time.Hour),
+		EndsAt:   time.Now().Add(time.Hour),
+	}
+	welcome := entity.PromoCode{
+		ID:          4,
+		Code:        "WELCOME",
+		Discount:    entity.Discount{Kind: entity.DiscountKindFixed, Value: 70},
+		OncePerUser: true,
+	}
+
+	merchRepo.EXPECT().GetByName(gomock.Any(), "hoody").Return(hoody, nil).AnyTimes()
+	merchRepo.EXPECT().ListVariants(gomock.Any(), hoody.ID).Return(nil, nil).AnyTimes()
+	promoRepo.EXPECT().
+		ActiveSales(gomock.Any(), hoody.ID, gomock.Any()).
+		Return([]entity.MerchSale{sale}, nil).
+		AnyTimes()
+	userRepo.EXPECT().
+		GetByID(gomock.Any(), userID).
+		DoAndReturn(func(ctx context.Context, id int64) (*entity.User, error) {
+			return &entity.User{ID: userID, Coins: 1000}, nil
+		}).
+		AnyTimes()
+
+	tests := []test{
+		{
+			name: "sale_then_code",
+			mock: func() {
+				promoRepo.EXPECT().GetCode(gomock.Any(), "WELCOME").Return(welcome, nil)
+				promoRepo.EXPECT().HasRedeemed(gomock.Any(), welcome.ID, userID).Return(false, nil)
+
+				userRepo.EXPECT().
+					Update(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, user *entity.User) error {
+						require.Equal(t, int64(800), user.Coins)
+						return nil
+					})
+				lotRepo.EXPECT().Spend(gomock.Any(), userID, int64(200)).Return(nil, nil)
+				promoRepo.EXPECT().Redeem(gomock.Any(), welcome.ID, userID).Return(nil)
+				invRepo.EXPECT().GetByUserID(gomock.Any(), userID).Return(nil, nil)
+				invRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+				txRepo.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, tr entity.Transaction) error {
+						require.Equal(t, int64(200), tr.Amount)
+						require.Equal(t, int64(300), *tr.ListPrice)
+						return nil
+					})
+
+				// the order keeps the paid price for refunds
+				orderRepo.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, order *entity.Order) error {
+						require.Equal(t, entity.OrderStatusPlaced, order.Status)
+						require.Equal(t, int64(200), order.Price)
+						return nil
+					})
+			},
+			res: " welcome ",
+		},
+		{
+			name: "code_already_used",
+			mock: func() {
+				promoRepo.EXPECT().GetCode(gomock.Any(), "WELCOME").Return(welcome, nil)
+				promoRepo.EXPECT().HasRedeemed(gomock.Any(), welcome.ID, userID).Return(true, nil)
+			},
+			res: "WELCOME",
+			err: entity.ErrPromoCodeUsed,
+		},
+		{
+			name: "code_for_another_item",
+			mock: func() {
+				pen := int64(2)
+				promo := welcome
+				promo.ItemID = &pen
+				promoRepo.EXPECT().GetCode(gomock.Any(), "PEN").Return(promo, nil)
+			},
+			res: "PEN",
+			err: entity.ErrPromoCodeNotApplicable,
+		},
+		{
+			name: "unknown_code",
+			mock: func() {
+				promoRepo.EXPECT().GetCode(gomock.Any(), "NOPE").Return(entity.PromoCode{}, entity.ErrPromoCodeNotFound)
+			},
+			res: "NOPE",
+			err: entity.ErrPromoCodeNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+			err := uc.BuyItem(context.Background(), userID, "hoody", nil, tc.res.(string))
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestMerchUseCase_PriceHistory(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	merchRepo := mocks.NewMockMerchRepository(ctrl)
+	uc := NewMerchUseCase(
+		merchRepo,
+		mocks.NewMockUserRepository(ctrl),
+		mocks.NewMockInventoryRepository(ctrl),
+		mocks.NewMockTransactionRepository(ctrl),
+		mocks.NewMockLotRepository(ctrl),
+		mocks.NewMockPromoRepository(ctrl),
+		mocks.NewMockOrderRepository(ctrl),
+		mocks.NewMockDBTransactor(ctrl),
+		mocks.NewMockFraudChecker(ctrl),
+		nil,
+	)
+
+	now := time.Now()
+	merchRepo.EXPECT().
+		GetByName(gomock.Any(), "hoody").
+		Return(entity.MerchItem{ID: 1, Name: "hoody", Price: 250}, nil)
+	merchRepo.EXPECT().
+		ListPrices(gomock.Any(), int64(1)).
+		Return([]entity.MerchPrice{
+			{ID: 1, ItemID: 1, Price: 300, EffectiveFrom: now.AddDate(0, -1, 0)},
+			{ID: 2, ItemID: 1, Price: 250, EffectiveFrom: now.AddDate(0, 0, -1)},
+			{ID: 3, ItemID: 1, Price: 350, EffectiveFrom: now.AddDate(0, 0, 7)},
+		}, nil)
+
+	history, err := uc.PriceHistory(context.Background(), "hoody")
+	require.NoError(t, err)
+	require.Equal(t, int64(250), history.Current)
+	require.Len(t, history.Prices, 3)
+	require.False(t, history.Prices[1].Scheduled)
+	require.True(t, history.Prices[2].Scheduled)
+}
+
+func TestMerchUseCase_SchedulePriceChange(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	merchRepo := mocks.NewMockMerchRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	uc := NewMerchUseCase(
+		merchRepo,
+		userRepo,
+		mocks.NewMockInventoryRepository(ctrl),
+		mocks.NewMockTransactionRepository(ctrl),
+		mocks.NewMockLotRepository(ctrl),
+		mocks.NewMockPromoRepository(ctrl),
+		mocks.NewMockOrderRepository(ctrl),
+		mocks.NewMockDBTransactor(ctrl),
+		mocks.NewMockFraudChecker(ctrl),
+		nil,
+	)
+
+	adminID := int64(1)
+	userRepo.EXPECT().
+		GetByID(gomock.Any(), adminID).
+		Return(&entity.User{ID: adminID, Role: entity.UserRoleAdmin}, nil).
+		AnyTimes()
+	userRepo.EXPECT().
+		GetByID(gomock.Any(), int64(2)).
+		Return(&entity.User{ID: 2, Role: entity.UserRoleUser}, nil).
+		AnyTimes()
+	merchRepo.EXPECT().
+		GetByName(gomock.Any(), "hoody").
+		Return(entity.MerchItem{ID: 1, Name: "hoody", Price: 300}, nil).
+		AnyTimes()
+
+	from := time.Now().Add(24 * time.Hour)
+
+	tests := []test{
+		{
+			name: "scheduled",
+			mock: func() {
+				merchRepo.EXPECT().
+					AddPrice(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, price *entity.MerchPrice) error {
+						require.Equal(t, int64(1), price.ItemID)
+						require.Equal(t, int64(350), price.Price)
+						require.Equal(t, from, price.EffectiveFrom)
+						price.ID = 5
+						return nil
+					})
+			},
+			res: adminID,
+		},
+		{
+			name: "not_an_admin",
+			mock: func() {},
+			res:  int64(2),
+			err:  entity.ErrForbidden,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+			_, err := uc.SchedulePriceChange(context.Background(), tc.res.(int64), "hoody", 350, from)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("in_the_past", func(t *testing.T) {
+		_, err := uc.SchedulePriceChange(context.Background(), adminID, "hoody", 350, time.Now().Add(-time.Hour))
+		require.ErrorIs(t, err, entity.ErrPriceChangeInPast)
+	})
+
+	t.Run("cancel_applied_change", func(t *testing.T) {
+		merchRepo.EXPECT().DeleteScheduledPrice(gomock.Any(), int64(1), int64(2), gomock.Any()).Return(false, nil)
+
+		err := uc.CancelPriceChange(context.Background(), adminID, "hoody", 2)
+		require.ErrorIs(t, err, entity.ErrPriceChangeNotFound)
+	})
 }
@@ -7,6 +7,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	entity "github.com/smthjapanese/avito-merch/internal/entity"
@@ -124,6 +125,79 @@ func (m *MockMerchRepository) EXPECT() *MockMerchRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AddPrice mocks base method.
+func (m *MockMerchRepository) AddPrice(ctx context.Context, price *entity.MerchPrice) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddPrice", ctx, price)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddPrice indicates an expected call of AddPrice.
+func (mr *MockMerchRepositoryMockRecorder) AddPrice(ctx, price interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPrice", reflect.TypeOf((*MockMerchRepository)(nil).AddPrice), ctx, price)
+}
+
+// CreateBundle mocks base method.
+func (m *MockMerchRepository) CreateBundle(ctx context.Context, bundle *entity.Bundle) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBundle", ctx, bundle)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateBundle indicates an expected call of CreateBundle.
+func (mr *MockMerchRepositoryMockRecorder) CreateBundle(ctx, bundle interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBundle", reflect.TypeOf((*MockMerchRepository)(nil).CreateBundle), ctx, bundle)
+}
+
+// DecrementStock mocks base method.
+func (m *MockMerchRepository) DecrementStock(ctx context.Context, id int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecrementStock", ctx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecrementStock indicates an expected call of DecrementStock.
+func (mr *MockMerchRepositoryMockRecorder) DecrementStock(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecrementStock", reflect.TypeOf((*MockMerchRepository)(nil).DecrementStock), ctx, id)
+}
+
+// DeleteScheduledPrice mocks base method.
+func (m *MockMerchRepository) DeleteScheduledPrice(ctx context.Context, itemID, id int64, now time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteScheduledPrice", ctx, itemID, id, now)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteScheduledPrice indicates an expected call of DeleteScheduledPrice.
+func (mr *MockMerchRepositoryMockRecorder) DeleteScheduledPrice(ctx, itemID, id, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteScheduledPrice", reflect.TypeOf((*MockMerchRepository)(nil).DeleteScheduledPrice), ctx, itemID, id, now)
+}
+
+// GetBundleByName mocks base method.
+func (m *MockMerchRepository) GetBundleByName(ctx context.Context, name string) (entity.Bundle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBundleByName", ctx, name)
+	ret0, _ := ret[0].(entity.Bundle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBundleByName indicates an expected call of GetBundleByName.
+func (mr *MockMerchRepositoryMockRecorder) GetBundleByName(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBundleByName", reflect.TypeOf((*MockMerchRepository)(nil).GetBundleByName), ctx, name)
+}
+
 // GetByID mocks base method.
 func (m *MockMerchRepository) GetByID(ctx context.Context, id int64) (entity.MerchItem, error) {
 	m.ctrl.T.Helper()
@@ -154,6 +228,21 @@ func (mr *MockMerchRepositoryMockRecorder) GetByName(ctx, name interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByName", reflect.TypeOf((*MockMerchRepository)(nil).GetByName), ctx, name)
 }
 
+// GetVariant mocks base method.
+func (m *MockMerchRepository) GetVariant(ctx context.Context, id int64) (entity.MerchVariant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVariant", ctx, id)
+	ret0, _ := ret[0].(entity.MerchVariant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVariant indicates an expected call of GetVariant.
+func (mr *MockMerchRepositoryMockRecorder) GetVariant(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVariant", reflect.TypeOf((*MockMerchRepository)(nil).GetVariant), ctx, id)
+}
+
 // List mocks base method.
 func (m *MockMerchRepository) List(ctx context.Context) ([]entity.MerchItem, error) {
 	m.ctrl.T.Helper()
@@ -169,6 +258,82 @@ func (mr *MockMerchRepositoryMockRecorder) List(ctx interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockMerchRepository)(nil).List), ctx)
 }
 
+// ListBundles mocks base method.
+func (m *MockMerchRepository) ListBundles(ctx context.Context) ([]entity.Bundle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBundles", ctx)
+	ret0, _ := ret[0].([]entity.Bundle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBundles indicates an expected call of ListBundles.
+func (mr *MockMerchRepositoryMockRecorder) ListBundles(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBundles", reflect.TypeOf((*MockMerchRepository)(nil).ListBundles), ctx)
+}
+
+// ListCategories mocks base method.
+func (m *MockMerchRepository) ListCategories(ctx context.Context) ([]entity.MerchCategory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCategories", ctx)
+	ret0, _ := ret[0].([]entity.MerchCategory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCategories indicates an expected call of ListCategories.
+func (mr *MockMerchRepositoryMockRecorder) ListCategories(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCategories", reflect.TypeOf((*MockMerchRepository)(nil).ListCategories), ctx)
+}
+
+// ListPrices mocks base method.
+func (m *MockMerchRepository) ListPrices(ctx context.Context, itemID int64) ([]entity.MerchPrice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPrices", ctx, itemID)
+	ret0, _ := ret[0].([]entity.MerchPrice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPrices indicates an expected call of ListPrices.
+func (mr *MockMerchRepositoryMockRecorder) ListPrices(ctx, itemID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPrices", reflect.TypeOf((*MockMerchRepository)(nil).ListPrices), ctx, itemID)
+}
+
+// ListVariants mocks base method.
+func (m *MockMerchRepository) ListVariants(ctx context.Context, itemID int64) ([]entity.MerchVariant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVariants", ctx, itemID)
+	ret0, _ := ret[0].([]entity.MerchVariant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVariants indicates an expected call of ListVariants.
+func (mr *MockMerchRepositoryMockRecorder) ListVariants(ctx, itemID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVariants", reflect.TypeOf((*MockMerchRepository)(nil).ListVariants), ctx, itemID)
+}
+
+// Search mocks base method.
+func (m *MockMerchRepository) Search(ctx context.Context, q entity.MerchQuery) ([]entity.MerchItem, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, q)
+	ret0, _ := ret[0].([]entity.MerchItem)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockMerchRepositoryMockRecorder) Search(ctx, q interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockMerchRepository)(nil).Search), ctx, q)
+}
+
 // MockInventoryRepository is a mock of InventoryRepository interface.
 type MockInventoryRepository struct {
 	ctrl     *gomock.Controller
@@ -235,6 +400,239 @@ func (mr *MockInventoryRepositoryMockRecorder) Update(ctx, inventory interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockInventoryRepository)(nil).Update), ctx, inventory)
 }
 
+// MockLotRepository is a mock of LotRepository interface.
+type MockLotRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLotRepositoryMockRecorder
+}
+
+// MockLotRepositoryMockRecorder is the mock recorder for MockLotRepository.
+type MockLotRepositoryMockRecorder struct {
+	mock *MockLotRepository
+}
+
+// NewMockLotRepository creates a new mock instance.
+func NewMockLotRepository(ctrl *gomock.Controller) *MockLotRepository {
+	mock := &MockLotRepository{ctrl: ctrl}
+	mock.recorder = &MockLotRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLotRepository) EXPECT() *MockLotRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Spend mocks base method.
+func (m *MockLotRepository) Spend(ctx context.Context, userID, amount int64) ([]entity.CoinLot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Spend", ctx, userID, amount)
+	ret0, _ := ret[0].([]entity.CoinLot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Spend indicates an expected call of Spend.
+func (mr *MockLotRepositoryMockRecorder) Spend(ctx, userID, amount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Spend", reflect.TypeOf((*MockLotRepository)(nil).Spend), ctx, userID, amount)
+}
+
+// MockPromoRepository is a mock of PromoRepository interface.
+type MockPromoRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPromoRepositoryMockRecorder
+}
+
+// MockPromoRepositoryMockRecorder is the mock recorder for MockPromoRepository.
+type MockPromoRepositoryMockRecorder struct {
+	mock *MockPromoRepository
+}
+
+// NewMockPromoRepository creates a new mock instance.
+func NewMockPromoRepository(ctrl *gomock.Controller) *MockPromoRepository {
+	mock := &MockPromoRepository{ctrl: ctrl}
+	mock.recorder = &MockPromoRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPromoRepository) EXPECT() *MockPromoRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ActiveSales mocks base method.
+func (m *MockPromoRepository) ActiveSales(ctx context.Context, itemID int64, now time.Time) ([]entity.MerchSale, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ActiveSales", ctx, itemID, now)
+	ret0, _ := ret[0].([]entity.MerchSale)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ActiveSales indicates an expected call of ActiveSales.
+func (mr *MockPromoRepositoryMockRecorder) ActiveSales(ctx, itemID, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActiveSales", reflect.TypeOf((*MockPromoRepository)(nil).ActiveSales), ctx, itemID, now)
+}
+
+// GetCode mocks base method.
+func (m *MockPromoRepository) GetCode(ctx context.Context, code string) (entity.PromoCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCode", ctx, code)
+	ret0, _ := ret[0].(entity.PromoCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCode indicates an expected call of GetCode.
+func (mr *MockPromoRepositoryMockRecorder) GetCode(ctx, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCode", reflect.TypeOf((*MockPromoRepository)(nil).GetCode), ctx, code)
+}
+
+// HasRedeemed mocks base method.
+func (m *MockPromoRepository) HasRedeemed(ctx context.Context, codeID, userID int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasRedeemed", ctx, codeID, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasRedeemed indicates an expected call of HasRedeemed.
+func (mr *MockPromoRepositoryMockRecorder) HasRedeemed(ctx, codeID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasRedeemed", reflect.TypeOf((*MockPromoRepository)(nil).HasRedeemed), ctx, codeID, userID)
+}
+
+// Redeem mocks base method.
+func (m *MockPromoRepository) Redeem(ctx context.Context, codeID, userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Redeem", ctx, codeID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Redeem indicates an expected call of Redeem.
+func (mr *MockPromoRepositoryMockRecorder) Redeem(ctx, codeID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Redeem", reflect.TypeOf((*MockPromoRepository)(nil).Redeem), ctx, codeID, userID)
+}
+
+// MockOrderRepository is a mock of OrderRepository interface.
+type MockOrderRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrderRepositoryMockRecorder
+}
+
+// MockOrderRepositoryMockRecorder is the mock recorder for MockOrderRepository.
+type MockOrderRepositoryMockRecorder struct {
+	mock *MockOrderRepository
+}
+
+// NewMockOrderRepository creates a new mock instance.
+func NewMockOrderRepository(ctrl *gomock.Controller) *MockOrderRepository {
+	mock := &MockOrderRepository{ctrl: ctrl}
+	mock.recorder = &MockOrderRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrderRepository) EXPECT() *MockOrderRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockOrderRepository) Create(ctx context.Context, order *entity.Order) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, order)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockOrderRepositoryMockRecorder) Create(ctx, order interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockOrderRepository)(nil).Create), ctx, order)
+}
+
+// MockFraudChecker is a mock of FraudChecker interface.
+type MockFraudChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockFraudCheckerMockRecorder
+}
+
+// MockFraudCheckerMockRecorder is the mock recorder for MockFraudChecker.
+type MockFraudCheckerMockRecorder struct {
+	mock *MockFraudChecker
+}
+
+// NewMockFraudChecker creates a new mock instance.
+func NewMockFraudChecker(ctrl *gomock.Controller) *MockFraudChecker {
+	mock := &MockFraudChecker{ctrl: ctrl}
+	mock.recorder = &MockFraudCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFraudChecker) EXPECT() *MockFraudCheckerMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockFraudChecker) Check(ctx context.Context, op entity.FraudOperation) (entity.FraudAction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ctx, op)
+	ret0, _ := ret[0].(entity.FraudAction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockFraudCheckerMockRecorder) Check(ctx, op interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockFraudChecker)(nil).Check), ctx, op)
+}
+
+// MockLocalizer is a mock of Localizer interface.
+type MockLocalizer struct {
+	ctrl     *gomock.Controller
+	recorder *MockLocalizerMockRecorder
+}
+
+// MockLocalizerMockRecorder is the mock recorder for MockLocalizer.
+type MockLocalizerMockRecorder struct {
+	mock *MockLocalizer
+}
+
+// NewMockLocalizer creates a new mock instance.
+func NewMockLocalizer(ctrl *gomock.Controller) *MockLocalizer {
+	mock := &MockLocalizer{ctrl: ctrl}
+	mock.recorder = &MockLocalizerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLocalizer) EXPECT() *MockLocalizerMockRecorder {
+	return m.recorder
+}
+
+// Localize mocks base method.
+func (m *MockLocalizer) Localize(ctx context.Context, items []entity.MerchItem, acceptLanguage string) ([]entity.MerchTranslation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Localize", ctx, items, acceptLanguage)
+	ret0, _ := ret[0].([]entity.MerchTranslation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Localize indicates an expected call of Localize.
+func (mr *MockLocalizerMockRecorder) Localize(ctx, items, acceptLanguage interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Localize", reflect.TypeOf((*MockLocalizer)(nil).Localize), ctx, items, acceptLanguage)
+}
+
 // MockUserRepository is a mock of UserRepository interface.
 type MockUserRepository struct {
 	ctrl     *gomock.Controller
@@ -0,0 +1,14 @@
+package notification_usecase
+
+import (
+	"context"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+)
+
+//go:generate mockgen -source=interfaces.go -destination=./mocks/mocks.go -package=mocks
+
+type NotificationRepository interface {
+	List(ctx context.Context, userID int64, unreadOnly bool, limit int) ([]entity.Notification, error)
+	MarkRead(ctx context.Context, userID, id int64) (bool, error)
+	MarkAllRead(ctx context.Context, userID int64) (int64, error)
+}
@@ -0,0 +1,42 @@
+package notification_usecase
+
+import (
+	"context"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+)
+
+const _listLimit = 50
+
+// NotificationUC lets users read their in-app notifications, the default
+// sink for everything the service notifies about.
+type NotificationUC struct {
+	repo NotificationRepository
+}
+
+func NewNotificationUC(repo NotificationRepository) *NotificationUC {
+	return &NotificationUC{
+		repo: repo,
+	}
+}
+
+// ListNotifications returns the user's latest notifications, newest first.
+func (uc *NotificationUC) ListNotifications(ctx context.Context, userID int64, unreadOnly bool) ([]entity.Notification, error) {
+	return uc.repo.List(ctx, userID, unreadOnly, _listLimit)
+}
+
+func (uc *NotificationUC) MarkRead(ctx context.Context, userID, id int64) error {
+	marked, err := uc.repo.MarkRead(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if !marked {
+		return entity.ErrNotificationNotFound
+	}
+
+	return nil
+}
+
+// MarkAllRead returns how many notifications were unread.
+func (uc *NotificationUC) MarkAllRead(ctx context.Context, userID int64) (int64, error) {
+	return uc.repo.MarkAllRead(ctx, userID)
+}
@@ -0,0 +1,70 @@
+package notification_usecase
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/usecase/notification_usecase/mocks"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type test struct {
+	name string
+	mock func()
+	res  interface{}
+	err  error
+}
+
+func TestMarkRead(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockNotificationRepository(ctrl)
+	uc := NewNotificationUC(repo)
+
+	tests := []test{
+		{
+			name: "own notification",
+			mock: func() {
+				repo.EXPECT().MarkRead(gomock.Any(), int64(2), int64(1)).Return(true, nil)
+			},
+		},
+		{
+			name: "someone else's notification",
+			mock: func() {
+				repo.EXPECT().MarkRead(gomock.Any(), int64(2), int64(1)).Return(false, nil)
+			},
+			err: entity.ErrNotificationNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			err := uc.MarkRead(context.Background(), 2, 1)
+
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestListNotifications(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockNotificationRepository(ctrl)
+	uc := NewNotificationUC(repo)
+
+	want := []entity.Notification{{ID: 1, UserID: 2, Message: "hoody from your wishlist is back in stock"}}
+	repo.EXPECT().List(gomock.Any(), int64(2), true, _listLimit).Return(want, nil)
+
+	got, err := uc.ListNotifications(context.Background(), 2, true)
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+}
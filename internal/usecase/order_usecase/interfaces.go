@@ -0,0 +1,47 @@
+package order_usecase
+
+import (
+	"context"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+)
+
+//go:generate mockgen -source=interfaces.go -destination=./mocks/mocks.go -package=mocks
+
+type OrderRepository interface {
+	GetByID(ctx context.Context, id int64) (entity.Order, error)
+	List(ctx context.Context, status entity.OrderStatus) ([]entity.Order, error)
+	Update(ctx context.Context, order entity.Order) error
+	ListLots(ctx context.Context, orderID int64) ([]entity.CoinLot, error)
+	ListLocations(ctx context.Context) ([]entity.PickupLocation, error)
+	GetLocation(ctx context.Context, id int64) (entity.PickupLocation, error)
+}
+
+type UserRepository interface {
+	GetByID(ctx context.Context, id int64) (*entity.User, error)
+	GetByUsername(ctx context.Context, username string) (*entity.User, error)
+	Update(ctx context.Context, user *entity.User) error
+}
+
+// MerchRepository puts a cancelled order's variant back on the shelf.
+type MerchRepository interface {
+	IncrementStock(ctx context.Context, id int64) error
+}
+
+// InventoryRepository takes a cancelled order's item back from the buyer.
+type InventoryRepository interface {
+	Take(ctx context.Context, userID, itemID int64, variantID *int64, quantity int64) (bool, error)
+}
+
+type TransactionRepository interface {
+	Create(ctx context.Context, tr *entity.Transaction) error
+}
+
+// LotRepository gives refunded coins back with the dates of the lots they
+// were paid from.
+type LotRepository interface {
+	Credit(ctx context.Context, userID int64, portions []entity.CoinLot) error
+}
+
+type DBTransactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
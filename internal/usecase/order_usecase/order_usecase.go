@@ -0,0 +1,204 @@
+package order_usecase
+
+import (
+	"context"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"time"
+)
+
+// OrderUC moves the orders opened by purchases through fulfilment. Admins
+// advance orders; buyers pick where they collect them.
+type OrderUC struct {
+	orderRepo OrderRepository
+	userRepo  UserRepository
+	merchRepo MerchRepository
+	invRepo   InventoryRepository
+	txRepo    TransactionRepository
+	lotRepo   LotRepository
+	dbTx      DBTransactor
+}
+
+func NewOrderUC(
+	orderRepo OrderRepository,
+	userRepo UserRepository,
+	merchRepo MerchRepository,
+	invRepo InventoryRepository,
+	txRepo TransactionRepository,
+	lotRepo LotRepository,
+	dbTx DBTransactor,
+) *OrderUC {
+	return &OrderUC{
+		orderRepo: orderRepo,
+		userRepo:  userRepo,
+		merchRepo: merchRepo,
+		invRepo:   invRepo,
+		txRepo:    txRepo,
+		lotRepo:   lotRepo,
+		dbTx:      dbTx,
+	}
+}
+
+// ListOrders returns the orders in the given status; an empty status lists
+// all open orders.
+func (uc *OrderUC) ListOrders(ctx context.Context, adminID int64, status entity.OrderStatus) ([]entity.Order, error) {
+	if status != "" && !status.IsValid() {
+		return nil, entity.ErrInvalidOrderStatus
+	}
+	if err := uc.requireAdmin(ctx, adminID); err != nil {
+		return nil, err
+	}
+
+	return uc.orderRepo.List(ctx, status)
+}
+
+// AdvanceOrder moves the order to the next status. Cancelling takes the
+// item back from the buyer, returns it to stock and refunds the price they
+// paid; it fails with ErrNotEnoughItems once the buyer no longer has it.
+func (uc *OrderUC) AdvanceOrder(ctx context.Context, adminID, id int64, status entity.OrderStatus) (entity.Order, error) {
+	var order entity.Order
+
+	err := uc.dbTx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := uc.requireAdmin(ctx, adminID); err != nil {
+			return err
+		}
+
+		var err error
+		order, err = uc.orderRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := order.Advance(status, adminID, time.Now()); err != nil {
+			return err
+		}
+		if err := uc.orderRepo.Update(ctx, order); err != nil {
+			return err
+		}
+
+		if status == entity.OrderStatusCancelled {
+			return uc.refund(ctx, order)
+		}
+		return nil
+	})
+	if err != nil {
+		return entity.Order{}, err
+	}
+
+	return order, nil
+}
+
+// SetPickupLocation chooses where the order is collected. The buyer and
+// admins may change it until the order is ready for pickup.
+func (uc *OrderUC) SetPickupLocation(ctx context.Context, userID, id, locationID int64) error {
+	return uc.dbTx.WithinTransaction(ctx, func(ctx context.Context) error {
+		order, err := uc.orderRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if order.UserID != userID {
+			if err := uc.requireAdmin(ctx, userID); err != nil {
+				return err
+			}
+		}
+		if order.Status != entity.OrderStatusPlaced && order.Status != entity.OrderStatusPacked {
+			return entity.ErrOrderLocationLocked
+		}
+
+		if _, err := uc.orderRepo.GetLocation(ctx, locationID); err != nil {
+			return err
+		}
+
+		order.LocationID = &locationID
+		order.UpdatedBy = &userID
+		order.UpdatedAt = time.Now()
+		return uc.orderRepo.Update(ctx, order)
+	})
+}
+
+func (uc *OrderUC) ListPickupLocations(ctx context.Context) ([]entity.PickupLocation, error) {
+	return uc.orderRepo.ListLocations(ctx)
+}
+
+func (uc *OrderUC) refund(ctx context.Context, order entity.Order) error {
+	// The item may have been given away, sold or redeemed since; refunding
+	// it anyway would hand out coins for an item the buyer keeps elsewhere.
+	taken, err := uc.invRepo.Take(ctx, order.UserID, order.ItemID, order.VariantID, 1)
+	if err != nil {
+		return err
+	}
+	if !taken {
+		return entity.ErrNotEnoughItems
+	}
+
+	if order.VariantID != nil {
+		if err := uc.merchRepo.IncrementStock(ctx, *order.VariantID); err != nil {
+			return err
+		}
+	}
+
+	// Prizes are handed out for free, so there is nothing to pay back.
+	if order.Price == 0 {
+		return nil
+	}
+
+	buyer, err := uc.userRepo.GetByID(ctx, order.UserID)
+	if err != nil {
+		return err
+	}
+	if buyer == nil {
+		return entity.ErrUserNotFound
+	}
+
+	system, err := uc.userRepo.GetByUsername(ctx, entity.SystemUsername)
+	if err != nil {
+		return err
+	}
+	if system == nil {
+		return entity.ErrUserNotFound
+	}
+
+	buyer.Coins += order.Price
+	if err := uc.userRepo.Update(ctx, buyer); err != nil {
+		return err
+	}
+
+	// The coins come back with the dates of the lots they were paid from,
+	// so a refund cannot extend their life. Orders placed before those were
+	// recorded are refunded in coins that never expire.
+	lots, err := uc.orderRepo.ListLots(ctx, order.ID)
+	if err != nil {
+		return err
+	}
+	var covered int64
+	for _, lot := range lots {
+		covered += lot.Amount
+	}
+	if covered < order.Price {
+		lots = append(lots, entity.NewCoinLot(buyer.ID, order.Price-covered, time.Now(), 0))
+	}
+	if err := uc.lotRepo.Credit(ctx, buyer.ID, lots); err != nil {
+		return err
+	}
+
+	return uc.txRepo.Create(ctx, &entity.Transaction{
+		FromUserID: system.ID,
+		ToUserID:   buyer.ID,
+		Amount:     order.Price,
+		Type:       entity.TransactionTypeRefund,
+		ItemID:     &order.ItemID,
+		CreatedAt:  time.Now(),
+	})
+}
+
+func (uc *OrderUC) requireAdmin(ctx context.Context, adminID int64) error {
+	admin, err := uc.userRepo.GetByID(ctx, adminID)
+	if err != nil {
+		return err
+	}
+	if admin == nil || !admin.IsAdmin() {
+		return entity.ErrForbidden
+	}
+
+	return nil
+}
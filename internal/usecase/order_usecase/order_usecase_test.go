@@ -0,0 +1,338 @@
+package order_usecase
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/usecase/order_usecase/mocks"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type test struct {
+	name string
+	mock func()
+	res  interface{}
+	err  error
+}
+
+const adminID = int64(9)
+
+func placedOrder() entity.Order {
+	return entity.Order{ID: 5, UserID: 1, ItemID: 3, Price: 80, Status: entity.OrderStatusPlaced}
+}
+
+func TestAdvanceOrder(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	orderRepo := mocks.NewMockOrderRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	merchRepo := mocks.NewMockMerchRepository(ctrl)
+	invRepo := mocks.NewMockInventoryRepository(ctrl)
+	txRepo := mocks.NewMockTransactionRepository(ctrl)
+	lotRepo := mocks.NewMockLotRepository(ctrl)
+	dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+	uc := NewOrderUC(orderRepo, userRepo, merchRepo, invRepo, txRepo, lotRepo, dbTransactor)
+
+	tests := []test{
+		{
+			name: "packed",
+			mock: func() {
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				userRepo.EXPECT().
+					GetByID(gomock.Any(), adminID).
+					Return(&entity.User{ID: adminID, Role: entity.UserRoleAdmin}, nil)
+				orderRepo.EXPECT().GetByID(gomock.Any(), int64(5)).Return(placedOrder(), nil)
+				orderRepo.EXPECT().
+					Update(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, order entity.Order) error {
+						require.Equal(t, entity.OrderStatusPacked, order.Status)
+						require.Equal(t, adminID, *order.UpdatedBy)
+						return nil
+					})
+			},
+			res: entity.OrderStatusPacked,
+		},
+		{
+			name: "cancelled and refunded",
+			mock: func() {
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				userRepo.EXPECT().
+					GetByID(gomock.Any(), adminID).
+					Return(&entity.User{ID: adminID, Role: entity.UserRoleAdmin}, nil)
+				orderRepo.EXPECT().GetByID(gomock.Any(), int64(5)).Return(placedOrder(), nil)
+				orderRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+				invRepo.EXPECT().Take(gomock.Any(), int64(1), int64(3), nil, int64(1)).Return(true, nil)
+
+				userRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&entity.User{ID: 1, Coins: 20}, nil)
+				userRepo.EXPECT().
+					GetByUsername(gomock.Any(), entity.SystemUsername).
+					Return(&entity.User{ID: 100, Role: entity.UserRoleSystem}, nil)
+				userRepo.EXPECT().
+					Update(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, user *entity.User) error {
+						require.Equal(t, int64(100), user.Coins)
+						return nil
+					})
+				expires := time.Now().Add(24 * time.Hour)
+				paid := []entity.CoinLot{
+					{Amount: 50, Remaining: 50, ExpiresAt: &expires},
+					{Amount: 30, Remaining: 30},
+				}
+				orderRepo.EXPECT().ListLots(gomock.Any(), int64(5)).Return(paid, nil)
+				lotRepo.EXPECT().Credit(gomock.Any(), int64(1), paid).Return(nil)
+				txRepo.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, tr *entity.Transaction) error {
+						require.Equal(t, entity.TransactionTypeRefund, tr.Type)
+						require.Equal(t, int64(100), tr.FromUserID)
+						require.Equal(t, int64(80), tr.Amount)
+						return nil
+					})
+			},
+			res: entity.OrderStatusCancelled,
+		},
+		{
+			name: "cancelled variant goes back on the shelf",
+			mock: func() {
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				userRepo.EXPECT().
+					GetByID(gomock.Any(), adminID).
+					Return(&entity.User{ID: adminID, Role: entity.UserRoleAdmin}, nil)
+
+				variantID := int64(4)
+				order := placedOrder()
+				order.VariantID = &variantID
+				orderRepo.EXPECT().GetByID(gomock.Any(), int64(5)).Return(order, nil)
+				orderRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+				invRepo.EXPECT().Take(gomock.Any(), int64(1), int64(3), &variantID, int64(1)).Return(true, nil)
+				merchRepo.EXPECT().IncrementStock(gomock.Any(), variantID).Return(nil)
+
+				userRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&entity.User{ID: 1, Coins: 20}, nil)
+				userRepo.EXPECT().
+					GetByUsername(gomock.Any(), entity.SystemUsername).
+					Return(&entity.User{ID: 100, Role: entity.UserRoleSystem}, nil)
+				userRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+				// placed before the coins paid were recorded
+				orderRepo.EXPECT().ListLots(gomock.Any(), int64(5)).Return(nil, nil)
+				lotRepo.EXPECT().
+					Credit(gomock.Any(), int64(1), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, userID int64, lots []entity.CoinLot) error {
+						require.Len(t, lots, 1)
+						require.Equal(t, int64(80), lots[0].Amount)
+						require.Nil(t, lots[0].ExpiresAt)
+						return nil
+					})
+				txRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			res: entity.OrderStatusCancelled,
+		},
+		{
+			name: "cancelled prize is taken back without a refund",
+			mock: func() {
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				userRepo.EXPECT().
+					GetByID(gomock.Any(), adminID).
+					Return(&entity.User{ID: adminID, Role: entity.UserRoleAdmin}, nil)
+
+				order := placedOrder()
+				order.Price = 0
+				orderRepo.EXPECT().GetByID(gomock.Any(), int64(5)).Return(order, nil)
+				orderRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+				invRepo.EXPECT().Take(gomock.Any(), int64(1), int64(3), nil, int64(1)).Return(true, nil)
+			},
+			res: entity.OrderStatusCancelled,
+		},
+		{
+			name: "item no longer held",
+			mock: func() {
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				userRepo.EXPECT().
+					GetByID(gomock.Any(), adminID).
+					Return(&entity.User{ID: adminID, Role: entity.UserRoleAdmin}, nil)
+				orderRepo.EXPECT().GetByID(gomock.Any(), int64(5)).Return(placedOrder(), nil)
+				orderRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+				// given away since; nothing is refunded
+				invRepo.EXPECT().Take(gomock.Any(), int64(1), int64(3), nil, int64(1)).Return(false, nil)
+			},
+			res: entity.OrderStatusCancelled,
+			err: entity.ErrNotEnoughItems,
+		},
+		{
+			name: "ready without a location",
+			mock: func() {
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				userRepo.EXPECT().
+					GetByID(gomock.Any(), adminID).
+					Return(&entity.User{ID: adminID, Role: entity.UserRoleAdmin}, nil)
+				order := placedOrder()
+				order.Status = entity.OrderStatusPacked
+				orderRepo.EXPECT().GetByID(gomock.Any(), int64(5)).Return(order, nil)
+			},
+			res: entity.OrderStatusReadyForPickup,
+			err: entity.ErrPickupLocationRequired,
+		},
+		{
+			name: "delivered orders are final",
+			mock: func() {
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				userRepo.EXPECT().
+					GetByID(gomock.Any(), adminID).
+					Return(&entity.User{ID: adminID, Role: entity.UserRoleAdmin}, nil)
+				order := placedOrder()
+				order.Status = entity.OrderStatusDelivered
+				orderRepo.EXPECT().GetByID(gomock.Any(), int64(5)).Return(order, nil)
+			},
+			res: entity.OrderStatusCancelled,
+			err: entity.ErrInvalidOrderTransition,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			order, err := uc.AdvanceOrder(context.Background(), adminID, 5, tc.res.(entity.OrderStatus))
+
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.res, order.Status)
+			}
+		})
+	}
+
+	t.Run("not an admin", func(t *testing.T) {
+		dbTransactor.EXPECT().
+			WithinTransaction(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+		userRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&entity.User{ID: 1, Role: entity.UserRoleUser}, nil)
+
+		_, err := uc.AdvanceOrder(context.Background(), 1, 5, entity.OrderStatusPacked)
+		require.ErrorIs(t, err, entity.ErrForbidden)
+	})
+}
+
+func TestSetPickupLocation(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	orderRepo := mocks.NewMockOrderRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	merchRepo := mocks.NewMockMerchRepository(ctrl)
+	invRepo := mocks.NewMockInventoryRepository(ctrl)
+	txRepo := mocks.NewMockTransactionRepository(ctrl)
+	lotRepo := mocks.NewMockLotRepository(ctrl)
+	dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+	uc := NewOrderUC(orderRepo, userRepo, merchRepo, invRepo, txRepo, lotRepo, dbTransactor)
+
+	tests := []test{
+		{
+			name: "buyer picks an office",
+			mock: func() {
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				orderRepo.EXPECT().GetByID(gomock.Any(), int64(5)).Return(placedOrder(), nil)
+				orderRepo.EXPECT().GetLocation(gomock.Any(), int64(2)).Return(entity.PickupLocation{ID: 2}, nil)
+				orderRepo.EXPECT().
+					Update(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, order entity.Order) error {
+						require.Equal(t, int64(2), *order.LocationID)
+						require.Equal(t, entity.OrderStatusPlaced, order.Status)
+						return nil
+					})
+			},
+			res: int64(1),
+		},
+		{
+			name: "someone else's order",
+			mock: func() {
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				orderRepo.EXPECT().GetByID(gomock.Any(), int64(5)).Return(placedOrder(), nil)
+				userRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(&entity.User{ID: 2, Role: entity.UserRoleUser}, nil)
+			},
+			res: int64(2),
+			err: entity.ErrForbidden,
+		},
+		{
+			name: "already ready for pickup",
+			mock: func() {
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				order := placedOrder()
+				order.Status = entity.OrderStatusReadyForPickup
+				orderRepo.EXPECT().GetByID(gomock.Any(), int64(5)).Return(order, nil)
+			},
+			res: int64(1),
+			err: entity.ErrOrderLocationLocked,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			err := uc.SetPickupLocation(context.Background(), tc.res.(int64), 5, 2)
+
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package preorder_usecase
+
+import "time"
+
+// ScheduleReleaseRequest makes an item upcoming: it goes on sale at
+// ReleaseAt and takes at most PreorderLimit pre-orders until then.
+type ScheduleReleaseRequest struct {
+	ItemName      string    `json:"item" validate:"required"`
+	ReleaseAt     time.Time `json:"release_at" validate:"required"`
+	PreorderLimit int64     `json:"preorder_limit" validate:"required,min=1"`
+}
+
+// PreorderRequest pre-orders one unit of an upcoming item. Items that come
+// in variants need VariantID to name one of them.
+type PreorderRequest struct {
+	ItemName  string `json:"item" validate:"required"`
+	VariantID *int64 `json:"variant_id,omitempty"`
+}
@@ -0,0 +1,62 @@
+package preorder_usecase
+
+import (
+	"context"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"time"
+)
+
+//go:generate mockgen -source=interfaces.go -destination=./mocks/mocks.go -package=mocks
+
+type PreorderRepository interface {
+	Create(ctx context.Context, preorder *entity.Preorder) error
+	GetByID(ctx context.Context, id int64) (entity.Preorder, error)
+	CountActive(ctx context.Context, itemID int64) (int64, error)
+	ListPending(ctx context.Context, itemID int64) ([]entity.Preorder, error)
+	ListByUser(ctx context.Context, userID int64) ([]entity.Preorder, error)
+	Update(ctx context.Context, preorder entity.Preorder) error
+	ListDue(ctx context.Context, now time.Time, limit int) ([]int64, error)
+}
+
+type UserRepository interface {
+	GetByID(ctx context.Context, id int64) (*entity.User, error)
+	Update(ctx context.Context, user *entity.User) error
+}
+
+type MerchRepository interface {
+	GetByID(ctx context.Context, id int64) (entity.MerchItem, error)
+	GetByName(ctx context.Context, name string) (entity.MerchItem, error)
+	ListVariants(ctx context.Context, itemID int64) ([]entity.MerchVariant, error)
+	GetVariant(ctx context.Context, id int64) (entity.MerchVariant, error)
+	SetRelease(ctx context.Context, itemID int64, releaseAt *time.Time, preorderLimit *int64) error
+	DecrementStock(ctx context.Context, id int64) (bool, error)
+}
+
+// InventoryRepository hands the released item to the buyer.
+type InventoryRepository interface {
+	Add(ctx context.Context, userID, itemID int64, variantID *int64, quantity int64) error
+}
+
+type TransactionRepository interface {
+	Create(ctx context.Context, tr *entity.Transaction) error
+}
+
+// LotRepository takes the captured coins out of the buyer's oldest lots.
+type LotRepository interface {
+	Spend(ctx context.Context, userID int64, amount int64) ([]entity.CoinLot, error)
+}
+
+// OrderRepository opens an order for every fulfilled pre-order, like for
+// any purchase.
+type OrderRepository interface {
+	Create(ctx context.Context, order *entity.Order) error
+}
+
+// Notifier delivers a short message to a user.
+type Notifier interface {
+	Notify(ctx context.Context, userID int64, message string) error
+}
+
+type DBTransactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
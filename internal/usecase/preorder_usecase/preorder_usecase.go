@@ -0,0 +1,362 @@
+package preorder_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"time"
+)
+
+const _releaseBatchSize = 100
+
+// PreorderUC takes pre-orders for upcoming items. The price is held on the
+// buyer's account when the pre-order is placed; once the item is released
+// ReleaseDue turns pending pre-orders into purchases in the order they were
+// placed, and cancelling before the release gives the coins back.
+type PreorderUC struct {
+	preorderRepo PreorderRepository
+	userRepo     UserRepository
+	merchRepo    MerchRepository
+	invRepo      InventoryRepository
+	txRepo       TransactionRepository
+	lotRepo      LotRepository
+	orderRepo    OrderRepository
+	notifier     Notifier
+	dbTx         DBTransactor
+}
+
+func NewPreorderUC(
+	preorderRepo PreorderRepository,
+	userRepo UserRepository,
+	merchRepo MerchRepository,
+	invRepo InventoryRepository,
+	txRepo TransactionRepository,
+	lotRepo LotRepository,
+	orderRepo OrderRepository,
+	notifier Notifier,
+	dbTx DBTransactor,
+) *PreorderUC {
+	return &PreorderUC{
+		preorderRepo: preorderRepo,
+		userRepo:     userRepo,
+		merchRepo:    merchRepo,
+		invRepo:      invRepo,
+		txRepo:       txRepo,
+		lotRepo:      lotRepo,
+		orderRepo:    orderRepo,
+		notifier:     notifier,
+		dbTx:         dbTx,
+	}
+}
+
+// ScheduleRelease makes the item upcoming. Pre-orders already placed are
+// kept; lowering the limit below their number only stops new ones.
+func (uc *PreorderUC) ScheduleRelease(ctx context.Context, adminID int64, req ScheduleReleaseRequest) (entity.MerchItem, error) {
+	if err := uc.requireAdmin(ctx, adminID); err != nil {
+		return entity.MerchItem{}, err
+	}
+	if !req.ReleaseAt.After(time.Now()) {
+		return entity.MerchItem{}, entity.ErrInvalidReleaseTime
+	}
+	if req.PreorderLimit <= 0 {
+		return entity.MerchItem{}, entity.ErrInvalidQuantity
+	}
+
+	item, err := uc.merchRepo.GetByName(ctx, req.ItemName)
+	if err != nil {
+		return entity.MerchItem{}, entity.ErrMerchNotFound
+	}
+
+	if err := uc.merchRepo.SetRelease(ctx, item.ID, &req.ReleaseAt, &req.PreorderLimit); err != nil {
+		return entity.MerchItem{}, err
+	}
+
+	item.ReleaseAt = &req.ReleaseAt
+	item.PreorderLimit = &req.PreorderLimit
+	return item, nil
+}
+
+// PlacePreorder reserves one unit of an upcoming item at its current price
+// and holds the price on the user's account.
+func (uc *PreorderUC) PlacePreorder(ctx context.Context, userID int64, req PreorderRequest) (entity.Preorder, error) {
+	item, err := uc.merchRepo.GetByName(ctx, req.ItemName)
+	if err != nil {
+		return entity.Preorder{}, entity.ErrMerchNotFound
+	}
+	if !item.TakesPreorders(time.Now()) {
+		return entity.Preorder{}, entity.ErrPreordersClosed
+	}
+
+	variant, err := uc.variant(ctx, item, req.VariantID)
+	if err != nil {
+		return entity.Preorder{}, err
+	}
+
+	preorder := entity.Preorder{
+		UserID:   userID,
+		ItemID:   item.ID,
+		Price:    item.Price,
+		ItemName: item.Name,
+	}
+	if variant != nil {
+		preorder.VariantID = &variant.ID
+		preorder.Price = variant.Price(item)
+	}
+
+	err = uc.dbTx.WithinTransaction(ctx, func(ctx context.Context) error {
+		placed, err := uc.preorderRepo.CountActive(ctx, item.ID)
+		if err != nil {
+			return err
+		}
+		if placed >= *item.PreorderLimit {
+			return entity.ErrPreorderLimitReached
+		}
+
+		user, err := uc.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return entity.ErrUserNotFound
+		}
+		if err := user.Hold(preorder.Price); err != nil {
+			return err
+		}
+		if err := uc.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+
+		return uc.preorderRepo.Create(ctx, &preorder)
+	})
+	if err != nil {
+		return entity.Preorder{}, err
+	}
+
+	return preorder, nil
+}
+
+// CancelPreorder gives the held coins back. It is only possible before the
+// item is released.
+func (uc *PreorderUC) CancelPreorder(ctx context.Context, userID, id int64) error {
+	return uc.dbTx.WithinTransaction(ctx, func(ctx context.Context) error {
+		preorder, err := uc.preorderRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if preorder.UserID != userID {
+			return entity.ErrPreorderNotFound
+		}
+
+		item, err := uc.merchRepo.GetByID(ctx, preorder.ItemID)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		if item.IsReleased(now) {
+			return entity.ErrItemReleased
+		}
+
+		if err := preorder.Resolve(entity.PreorderStatusCancelled, now); err != nil {
+			return err
+		}
+
+		user, err := uc.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return entity.ErrUserNotFound
+		}
+		user.Release(preorder.Price)
+		if err := uc.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+
+		return uc.preorderRepo.Update(ctx, preorder)
+	})
+}
+
+func (uc *PreorderUC) ListPreorders(ctx context.Context, userID int64) ([]entity.Preorder, error) {
+	return uc.preorderRepo.ListByUser(ctx, userID)
+}
+
+// ReleaseDue fulfils the pending pre-orders of every item released by now,
+// in the order they were placed: the hold is captured and the item goes to
+// the buyer's inventory with an order to hand it out. Pre-orders for a
+// variant that has sold out by the time their turn comes are cancelled. Each item is handled
+// in its own transaction. It returns the number of pre-orders fulfilled and
+// is meant to be run by the scheduler.
+func (uc *PreorderUC) ReleaseDue(ctx context.Context, now time.Time) (int, error) {
+	ids, err := uc.preorderRepo.ListDue(ctx, now, _releaseBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	fulfilled := 0
+	var errs []error
+
+	for _, itemID := range ids {
+		var done, soldOut []entity.Preorder
+
+		err := uc.dbTx.WithinTransaction(ctx, func(ctx context.Context) error {
+			preorders, err := uc.preorderRepo.ListPending(ctx, itemID)
+			if err != nil {
+				return err
+			}
+
+			for _, preorder := range preorders {
+				ok, err := uc.fulfil(ctx, preorder, now)
+				if err != nil {
+					return err
+				}
+				if ok {
+					done = append(done, preorder)
+				} else {
+					soldOut = append(soldOut, preorder)
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		fulfilled += len(done)
+		for _, preorder := range done {
+			uc.notify(ctx, preorder.UserID, fmt.Sprintf("%s is out: your pre-order is ready", preorder.ItemName))
+		}
+		for _, preorder := range soldOut {
+			uc.notify(ctx, preorder.UserID, fmt.Sprintf("%s is out but your variant sold out: your pre-order was cancelled and the coins are back", preorder.ItemName))
+		}
+	}
+
+	return fulfilled, errors.Join(errs...)
+}
+
+// fulfil turns a pending pre-order into a purchase. A pre-order for a
+// variant takes a unit of its stock; when the variant has sold out the
+// pre-order is cancelled instead, the hold is released and fulfil reports
+// false.
+func (uc *PreorderUC) fulfil(ctx context.Context, preorder entity.Preorder, now time.Time) (bool, error) {
+	buyer, err := uc.userRepo.GetByID(ctx, preorder.UserID)
+	if err != nil {
+		return false, err
+	}
+	if buyer == nil {
+		return false, entity.ErrUserNotFound
+	}
+
+	if preorder.VariantID != nil {
+		ok, err := uc.merchRepo.DecrementStock(ctx, *preorder.VariantID)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			return false, uc.cancel(ctx, buyer, preorder, now)
+		}
+	}
+
+	if err := preorder.Resolve(entity.PreorderStatusFulfilled, now); err != nil {
+		return false, err
+	}
+
+	buyer.Capture(preorder.Price)
+	if err := uc.userRepo.Update(ctx, buyer); err != nil {
+		return false, err
+	}
+	paid, err := uc.lotRepo.Spend(ctx, buyer.ID, preorder.Price)
+	if err != nil {
+		return false, err
+	}
+
+	if err := uc.invRepo.Add(ctx, buyer.ID, preorder.ItemID, preorder.VariantID, 1); err != nil {
+		return false, err
+	}
+
+	if err := uc.txRepo.Create(ctx, &entity.Transaction{
+		FromUserID: buyer.ID,
+		ToUserID:   buyer.ID, // self-transaction for purchase
+		Amount:     preorder.Price,
+		Type:       entity.TransactionTypePurchase,
+		ItemID:     &preorder.ItemID,
+		ListPrice:  &preorder.Price,
+	}); err != nil {
+		return false, err
+	}
+
+	if err := uc.orderRepo.Create(ctx, &entity.Order{
+		UserID:    buyer.ID,
+		ItemID:    preorder.ItemID,
+		VariantID: preorder.VariantID,
+		Price:     preorder.Price,
+		Status:    entity.OrderStatusPlaced,
+		Lots:      paid,
+	}); err != nil {
+		return false, err
+	}
+
+	if err := uc.preorderRepo.Update(ctx, preorder); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// cancel resolves a pre-order that cannot be fulfilled and gives the held
+// coins back.
+func (uc *PreorderUC) cancel(ctx context.Context, buyer *entity.User, preorder entity.Preorder, now time.Time) error {
+	if err := preorder.Resolve(entity.PreorderStatusCancelled, now); err != nil {
+		return err
+	}
+
+	buyer.Release(preorder.Price)
+	if err := uc.userRepo.Update(ctx, buyer); err != nil {
+		return err
+	}
+
+	return uc.preorderRepo.Update(ctx, preorder)
+}
+
+// variant resolves the variant a pre-order is for, or nil when the item
+// has no variants.
+func (uc *PreorderUC) variant(ctx context.Context, item entity.MerchItem, variantID *int64) (*entity.MerchVariant, error) {
+	if variantID != nil {
+		variant, err := uc.merchRepo.GetVariant(ctx, *variantID)
+		if err != nil {
+			return nil, err
+		}
+		if variant.ItemID != item.ID {
+			return nil, entity.ErrVariantNotFound
+		}
+		return &variant, nil
+	}
+
+	variants, err := uc.merchRepo.ListVariants(ctx, item.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(variants) > 0 {
+		return nil, entity.ErrVariantRequired
+	}
+	return nil, nil
+}
+
+// notify is best-effort: the pre-orders have already been fulfilled.
+func (uc *PreorderUC) notify(ctx context.Context, userID int64, message string) {
+	_ = uc.notifier.Notify(ctx, userID, message)
+}
+
+func (uc *PreorderUC) requireAdmin(ctx context.Context, adminID int64) error {
+	admin, err := uc.userRepo.GetByID(ctx, adminID)
+	if err != nil {
+		return err
+	}
+	if admin == nil || !admin.IsAdmin() {
+		return entity.ErrForbidden
+	}
+
+	return nil
+}
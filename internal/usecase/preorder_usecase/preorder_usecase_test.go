@@ -0,0 +1,374 @@
+package preorder_usecase
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/usecase/preorder_usecase/mocks"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type test struct {
+	name string
+	mock func()
+	res  interface{}
+	err  error
+}
+
+const adminID = int64(9)
+
+func upcoming(releaseAt time.Time, limit int64) entity.MerchItem {
+	return entity.MerchItem{ID: 4, Name: "console", Price: 500, ReleaseAt: &releaseAt, PreorderLimit: &limit}
+}
+
+func TestScheduleRelease(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	merchRepo := mocks.NewMockMerchRepository(ctrl)
+
+	uc := NewPreorderUC(
+		mocks.NewMockPreorderRepository(ctrl),
+		userRepo,
+		merchRepo,
+		mocks.NewMockInventoryRepository(ctrl),
+		mocks.NewMockTransactionRepository(ctrl),
+		mocks.NewMockLotRepository(ctrl),
+		mocks.NewMockOrderRepository(ctrl),
+		mocks.NewMockNotifier(ctrl),
+		mocks.NewMockDBTransactor(ctrl),
+	)
+
+	releaseAt := time.Now().Add(24 * time.Hour)
+
+	tests := []test{
+		{
+			name: "takes pre-orders until the release",
+			mock: func() {
+				userRepo.EXPECT().
+					GetByID(gomock.Any(), adminID).
+					Return(&entity.User{ID: adminID, Role: entity.UserRoleAdmin}, nil)
+				merchRepo.EXPECT().GetByName(gomock.Any(), "console").Return(entity.MerchItem{ID: 4, Name: "console", Price: 500}, nil)
+				merchRepo.EXPECT().SetRelease(gomock.Any(), int64(4), &releaseAt, gomock.Any()).Return(nil)
+			},
+			res: releaseAt,
+		},
+		{
+			name: "release time in the past",
+			mock: func() {
+				userRepo.EXPECT().
+					GetByID(gomock.Any(), adminID).
+					Return(&entity.User{ID: adminID, Role: entity.UserRoleAdmin}, nil)
+			},
+			res: time.Now().Add(-time.Hour),
+			err: entity.ErrInvalidReleaseTime,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			item, err := uc.ScheduleRelease(context.Background(), adminID, ScheduleReleaseRequest{
+				ItemName:      "console",
+				ReleaseAt:     tc.res.(time.Time),
+				PreorderLimit: 10,
+			})
+
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+				require.True(t, item.TakesPreorders(time.Now()))
+			}
+		})
+	}
+}
+
+func TestPlacePreorder(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	preorderRepo := mocks.NewMockPreorderRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	merchRepo := mocks.NewMockMerchRepository(ctrl)
+	dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+	uc := NewPreorderUC(
+		preorderRepo,
+		userRepo,
+		merchRepo,
+		mocks.NewMockInventoryRepository(ctrl),
+		mocks.NewMockTransactionRepository(ctrl),
+		mocks.NewMockLotRepository(ctrl),
+		mocks.NewMockOrderRepository(ctrl),
+		mocks.NewMockNotifier(ctrl),
+		dbTransactor,
+	)
+
+	item := upcoming(time.Now().Add(24*time.Hour), 2)
+
+	tests := []test{
+		{
+			name: "holds the price",
+			mock: func() {
+				merchRepo.EXPECT().GetByName(gomock.Any(), "console").Return(item, nil)
+				merchRepo.EXPECT().ListVariants(gomock.Any(), item.ID).Return(nil, nil)
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				preorderRepo.EXPECT().CountActive(gomock.Any(), item.ID).Return(int64(1), nil)
+				userRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(&entity.User{ID: 2, Coins: 600}, nil)
+				userRepo.EXPECT().
+					Update(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, u *entity.User) error {
+						require.Equal(t, int64(100), u.Coins)
+						require.Equal(t, int64(500), u.HeldCoins)
+						return nil
+					})
+				preorderRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "limit reached",
+			mock: func() {
+				merchRepo.EXPECT().GetByName(gomock.Any(), "console").Return(item, nil)
+				merchRepo.EXPECT().ListVariants(gomock.Any(), item.ID).Return(nil, nil)
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				preorderRepo.EXPECT().CountActive(gomock.Any(), item.ID).Return(int64(2), nil)
+			},
+			err: entity.ErrPreorderLimitReached,
+		},
+		{
+			name: "not enough coins",
+			mock: func() {
+				merchRepo.EXPECT().GetByName(gomock.Any(), "console").Return(item, nil)
+				merchRepo.EXPECT().ListVariants(gomock.Any(), item.ID).Return(nil, nil)
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				preorderRepo.EXPECT().CountActive(gomock.Any(), item.ID).Return(int64(0), nil)
+				userRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(&entity.User{ID: 2, Coins: 499}, nil)
+			},
+			err: entity.ErrInsufficientFunds,
+		},
+		{
+			name: "already released",
+			mock: func() {
+				merchRepo.EXPECT().GetByName(gomock.Any(), "console").Return(upcoming(time.Now().Add(-time.Hour), 2), nil)
+			},
+			err: entity.ErrPreordersClosed,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			_, err := uc.PlacePreorder(context.Background(), 2, PreorderRequest{ItemName: "console"})
+
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCancelPreorder(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	preorderRepo := mocks.NewMockPreorderRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	merchRepo := mocks.NewMockMerchRepository(ctrl)
+	dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+	uc := NewPreorderUC(
+		preorderRepo,
+		userRepo,
+		merchRepo,
+		mocks.NewMockInventoryRepository(ctrl),
+		mocks.NewMockTransactionRepository(ctrl),
+		mocks.NewMockLotRepository(ctrl),
+		mocks.NewMockOrderRepository(ctrl),
+		mocks.NewMockNotifier(ctrl),
+		dbTransactor,
+	)
+
+	pending := entity.Preorder{ID: 1, UserID: 2, ItemID: 4, Price: 500, Status: entity.PreorderStatusPending}
+
+	tests := []test{
+		{
+			name: "releases the hold",
+			mock: func() {
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				preorderRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(pending, nil)
+				merchRepo.EXPECT().GetByID(gomock.Any(), int64(4)).Return(upcoming(time.Now().Add(time.Hour), 2), nil)
+				userRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(&entity.User{ID: 2, Coins: 100, HeldCoins: 500}, nil)
+				userRepo.EXPECT().
+					Update(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, u *entity.User) error {
+						require.Equal(t, int64(600), u.Coins)
+						require.Zero(t, u.HeldCoins)
+						return nil
+					})
+				preorderRepo.EXPECT().
+					Update(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, p entity.Preorder) error {
+						require.Equal(t, entity.PreorderStatusCancelled, p.Status)
+						return nil
+					})
+			},
+		},
+		{
+			name: "after the release",
+			mock: func() {
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				preorderRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(pending, nil)
+				merchRepo.EXPECT().GetByID(gomock.Any(), int64(4)).Return(upcoming(time.Now().Add(-time.Hour), 2), nil)
+			},
+			err: entity.ErrItemReleased,
+		},
+		{
+			name: "someone else's pre-order",
+			mock: func() {
+				other := pending
+				other.UserID = 3
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				preorderRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(other, nil)
+			},
+			err: entity.ErrPreorderNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			err := uc.CancelPreorder(context.Background(), 2, 1)
+
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestReleaseDue(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	preorderRepo := mocks.NewMockPreorderRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	merchRepo := mocks.NewMockMerchRepository(ctrl)
+	invRepo := mocks.NewMockInventoryRepository(ctrl)
+	txRepo := mocks.NewMockTransactionRepository(ctrl)
+	lotRepo := mocks.NewMockLotRepository(ctrl)
+	orderRepo := mocks.NewMockOrderRepository(ctrl)
+	notifier := mocks.NewMockNotifier(ctrl)
+	dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+	uc := NewPreorderUC(
+		preorderRepo,
+		userRepo,
+		merchRepo,
+		invRepo,
+		txRepo,
+		lotRepo,
+		orderRepo,
+		notifier,
+		dbTransactor,
+	)
+
+	now := time.Now()
+	large := int64(7)
+
+	preorders := []entity.Preorder{
+		{ID: 1, UserID: 2, ItemID: 4, VariantID: &large, Price: 500, Status: entity.PreorderStatusPending, ItemName: "console"},
+		{ID: 2, UserID: 3, ItemID: 4, VariantID: &large, Price: 500, Status: entity.PreorderStatusPending, ItemName: "console"},
+	}
+	first := &entity.User{ID: 2, HeldCoins: 500}
+	second := &entity.User{ID: 3, HeldCoins: 500}
+
+	preorderRepo.EXPECT().ListDue(gomock.Any(), now, _releaseBatchSize).Return([]int64{4}, nil)
+	dbTransactor.EXPECT().
+		WithinTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		})
+	preorderRepo.EXPECT().ListPending(gomock.Any(), int64(4)).Return(preorders, nil)
+
+	// the first in line gets the last unit
+	userRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(first, nil)
+	merchRepo.EXPECT().DecrementStock(gomock.Any(), large).Return(true, nil)
+	userRepo.EXPECT().Update(gomock.Any(), first).Return(nil)
+	lotRepo.EXPECT().Spend(gomock.Any(), int64(2), int64(500)).Return(nil, nil)
+	invRepo.EXPECT().Add(gomock.Any(), int64(2), int64(4), &large, int64(1)).Return(nil)
+	txRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+	orderRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+	// the second one finds it sold out and gets the coins back
+	userRepo.EXPECT().GetByID(gomock.Any(), int64(3)).Return(second, nil)
+	merchRepo.EXPECT().DecrementStock(gomock.Any(), large).Return(false, nil)
+	userRepo.EXPECT().Update(gomock.Any(), second).Return(nil)
+
+	statuses := map[int64]entity.PreorderStatus{}
+	preorderRepo.EXPECT().
+		Update(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, p entity.Preorder) error {
+			statuses[p.ID] = p.Status
+			return nil
+		}).
+		Times(2)
+
+	notifier.EXPECT().Notify(gomock.Any(), int64(2), "console is out: your pre-order is ready").Return(nil)
+	notifier.EXPECT().
+		Notify(gomock.Any(), int64(3), "console is out but your variant sold out: your pre-order was cancelled and the coins are back").
+		Return(nil)
+
+	n, err := uc.ReleaseDue(context.Background(), now)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Equal(t, map[int64]entity.PreorderStatus{
+		1: entity.PreorderStatusFulfilled,
+		2: entity.PreorderStatusCancelled,
+	}, statuses)
+	require.Zero(t, first.HeldCoins)
+	require.Zero(t, second.HeldCoins)
+	require.Equal(t, int64(500), second.Coins)
+}
@@ -0,0 +1,30 @@
+package promo_usecase
+
+import (
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"time"
+)
+
+// CreateCodeRequest describes a new promo code. Leaving ItemID out makes
+// the code valid on the whole catalog, and leaving MaxUses out makes it
+// unlimited.
+type CreateCodeRequest struct {
+	Code        string              `json:"code" validate:"required,max=50"`
+	Kind        entity.DiscountKind `json:"kind" validate:"required"`
+	Value       int64               `json:"value" validate:"required,min=1"`
+	ItemID      *int64              `json:"item_id,omitempty"`
+	MaxUses     *int64              `json:"max_uses,omitempty" validate:"omitempty,min=1"`
+	OncePerUser bool                `json:"once_per_user"`
+	StartsAt    *time.Time          `json:"starts_at,omitempty"`
+	EndsAt      *time.Time          `json:"ends_at,omitempty"`
+}
+
+// CreateSaleRequest schedules a discount for a time window. Leaving ItemID
+// out puts the whole catalog on sale.
+type CreateSaleRequest struct {
+	ItemID   *int64              `json:"item_id,omitempty"`
+	Kind     entity.DiscountKind `json:"kind" validate:"required"`
+	Value    int64               `json:"value" validate:"required,min=1"`
+	StartsAt time.Time           `json:"starts_at" validate:"required"`
+	EndsAt   time.Time           `json:"ends_at" validate:"required"`
+}
@@ -0,0 +1,24 @@
+package promo_usecase
+
+import (
+	"context"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"time"
+)
+
+//go:generate mockgen -source=interfaces.go -destination=./mocks/mocks.go -package=mocks
+
+type PromoRepository interface {
+	CreateCode(ctx context.Context, code *entity.PromoCode) error
+	ListCodes(ctx context.Context) ([]entity.PromoCode, error)
+	CreateSale(ctx context.Context, sale *entity.MerchSale) error
+	ListSales(ctx context.Context, now time.Time) ([]entity.MerchSale, error)
+}
+
+type MerchRepository interface {
+	GetByID(ctx context.Context, id int64) (entity.MerchItem, error)
+}
+
+type UserRepository interface {
+	GetByID(ctx context.Context, id int64) (*entity.User, error)
+}
@@ -0,0 +1,171 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: interfaces.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	entity "github.com/smthjapanese/avito-merch/internal/entity"
+)
+
+// MockPromoRepository is a mock of PromoRepository interface.
+type MockPromoRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPromoRepositoryMockRecorder
+}
+
+// MockPromoRepositoryMockRecorder is the mock recorder for MockPromoRepository.
+type MockPromoRepositoryMockRecorder struct {
+	mock *MockPromoRepository
+}
+
+// NewMockPromoRepository creates a new mock instance.
+func NewMockPromoRepository(ctrl *gomock.Controller) *MockPromoRepository {
+	mock := &MockPromoRepository{ctrl: ctrl}
+	mock.recorder = &MockPromoRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPromoRepository) EXPECT() *MockPromoRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateCode mocks base method.
+func (m *MockPromoRepository) CreateCode(ctx context.Context, code *entity.PromoCode) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCode", ctx, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateCode indicates an expected call of CreateCode.
+func (mr *MockPromoRepositoryMockRecorder) CreateCode(ctx, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCode", reflect.TypeOf((*MockPromoRepository)(nil).CreateCode), ctx, code)
+}
+
+// CreateSale mocks base method.
+func (m *MockPromoRepository) CreateSale(ctx context.Context, sale *entity.MerchSale) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSale", ctx, sale)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateSale indicates an expected call of CreateSale.
+func (mr *MockPromoRepositoryMockRecorder) CreateSale(ctx, sale interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSale", reflect.TypeOf((*MockPromoRepository)(nil).CreateSale), ctx, sale)
+}
+
+// ListCodes mocks base method.
+func (m *MockPromoRepository) ListCodes(ctx context.Context) ([]entity.PromoCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCodes", ctx)
+	ret0, _ := ret[0].([]entity.PromoCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCodes indicates an expected call of ListCodes.
+func (mr *MockPromoRepositoryMockRecorder) ListCodes(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCodes", reflect.TypeOf((*MockPromoRepository)(nil).ListCodes), ctx)
+}
+
+// ListSales mocks base method.
+func (m *MockPromoRepository) ListSales(ctx context.Context, now time.Time) ([]entity.MerchSale, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSales", ctx, now)
+	ret0, _ := ret[0].([]entity.MerchSale)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSales indicates an expected call of ListSales.
+func (mr *MockPromoRepositoryMockRecorder) ListSales(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSales", reflect.TypeOf((*MockPromoRepository)(nil).ListSales), ctx, now)
+}
+
+// MockMerchRepository is a mock of MerchRepository interface.
+type MockMerchRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockMerchRepositoryMockRecorder
+}
+
+// MockMerchRepositoryMockRecorder is the mock recorder for MockMerchRepository.
+type MockMerchRepositoryMockRecorder struct {
+	mock *MockMerchRepository
+}
+
+// NewMockMerchRepository creates a new mock instance.
+func NewMockMerchRepository(ctrl *gomock.Controller) *MockMerchRepository {
+	mock := &MockMerchRepository{ctrl: ctrl}
+	mock.recorder = &MockMerchRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMerchRepository) EXPECT() *MockMerchRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetByID mocks base method.
+func (m *MockMerchRepository) GetByID(ctx context.Context, id int64) (entity.MerchItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(entity.MerchItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockMerchRepositoryMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockMerchRepository)(nil).GetByID), ctx, id)
+}
+
+// MockUserRepository is a mock of UserRepository interface.
+type MockUserRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserRepositoryMockRecorder
+}
+
+// MockUserRepositoryMockRecorder is the mock recorder for MockUserRepository.
+type MockUserRepositoryMockRecorder struct {
+	mock *MockUserRepository
+}
+
+// NewMockUserRepository creates a new mock instance.
+func NewMockUserRepository(ctrl *gomock.Controller) *MockUserRepository {
+	mock := &MockUserRepository{ctrl: ctrl}
+	mock.recorder = &MockUserRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserRepository) EXPECT() *MockUserRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetByID mocks base method.
+func (m *MockUserRepository) GetByID(ctx context.Context, id int64) (*entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockUserRepositoryMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockUserRepository)(nil).GetByID), ctx, id)
+}
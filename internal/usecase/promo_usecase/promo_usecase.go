@@ -0,0 +1,124 @@
+package promo_usecase
+
+import (
+	"context"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"time"
+)
+
+// PromoUC lets admins hand out promo codes and schedule sales. The discounts
+// are applied at checkout by the merch use case.
+type PromoUC struct {
+	promoRepo PromoRepository
+	merchRepo MerchRepository
+	userRepo  UserRepository
+}
+
+func NewPromoUC(
+	promoRepo PromoRepository,
+	merchRepo MerchRepository,
+	userRepo UserRepository,
+) *PromoUC {
+	return &PromoUC{
+		promoRepo: promoRepo,
+		merchRepo: merchRepo,
+		userRepo:  userRepo,
+	}
+}
+
+func (uc *PromoUC) CreateCode(ctx context.Context, adminID int64, req CreateCodeRequest) (entity.PromoCode, error) {
+	if err := uc.requireAdmin(ctx, adminID); err != nil {
+		return entity.PromoCode{}, err
+	}
+
+	code := entity.PromoCode{
+		Code:        entity.NormalizePromoCode(req.Code),
+		Discount:    entity.Discount{Kind: req.Kind, Value: req.Value},
+		ItemID:      req.ItemID,
+		MaxUses:     req.MaxUses,
+		OncePerUser: req.OncePerUser,
+		StartsAt:    req.StartsAt,
+		EndsAt:      req.EndsAt,
+		CreatedBy:   adminID,
+	}
+	if code.Code == "" {
+		return entity.PromoCode{}, entity.ErrPromoCodeRequired
+	}
+	if err := code.Validate(); err != nil {
+		return entity.PromoCode{}, err
+	}
+	if code.StartsAt != nil && code.EndsAt != nil && !code.EndsAt.After(*code.StartsAt) {
+		return entity.PromoCode{}, entity.ErrInvalidSaleWindow
+	}
+	if err := uc.checkItem(ctx, code.ItemID); err != nil {
+		return entity.PromoCode{}, err
+	}
+
+	if err := uc.promoRepo.CreateCode(ctx, &code); err != nil {
+		return entity.PromoCode{}, err
+	}
+
+	return code, nil
+}
+
+func (uc *PromoUC) ListCodes(ctx context.Context, adminID int64) ([]entity.PromoCode, error) {
+	if err := uc.requireAdmin(ctx, adminID); err != nil {
+		return nil, err
+	}
+
+	return uc.promoRepo.ListCodes(ctx)
+}
+
+func (uc *PromoUC) CreateSale(ctx context.Context, adminID int64, req CreateSaleRequest) (entity.MerchSale, error) {
+	if err := uc.requireAdmin(ctx, adminID); err != nil {
+		return entity.MerchSale{}, err
+	}
+
+	sale := entity.MerchSale{
+		ItemID:    req.ItemID,
+		Discount:  entity.Discount{Kind: req.Kind, Value: req.Value},
+		StartsAt:  req.StartsAt,
+		EndsAt:    req.EndsAt,
+		CreatedBy: adminID,
+	}
+	if err := sale.Validate(); err != nil {
+		return entity.MerchSale{}, err
+	}
+	if !sale.EndsAt.After(sale.StartsAt) {
+		return entity.MerchSale{}, entity.ErrInvalidSaleWindow
+	}
+	if err := uc.checkItem(ctx, sale.ItemID); err != nil {
+		return entity.MerchSale{}, err
+	}
+
+	if err := uc.promoRepo.CreateSale(ctx, &sale); err != nil {
+		return entity.MerchSale{}, err
+	}
+
+	return sale, nil
+}
+
+// ListSales returns the running and upcoming sales. Anyone may see them.
+func (uc *PromoUC) ListSales(ctx context.Context, now time.Time) ([]entity.MerchSale, error) {
+	return uc.promoRepo.ListSales(ctx, now)
+}
+
+func (uc *PromoUC) checkItem(ctx context.Context, itemID *int64) error {
+	if itemID == nil {
+		return nil
+	}
+	_, err := uc.merchRepo.GetByID(ctx, *itemID)
+	return err
+}
+
+func (uc *PromoUC) requireAdmin(ctx context.Context, adminID int64) error {
+	admin, err := uc.userRepo.GetByID(ctx, adminID)
+	if err != nil {
+		return err
+	}
+	if admin == nil || !admin.IsAdmin() {
+		return entity.ErrForbidden
+	}
+
+	return nil
+}
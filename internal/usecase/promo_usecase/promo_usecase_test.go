@@ -0,0 +1,154 @@
+package promo_usecase
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/usecase/promo_usecase/mocks"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type test struct {
+	name string
+	mock func()
+	res  interface{}
+	err  error
+}
+
+func TestCreateCode(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	promoRepo := mocks.NewMockPromoRepository(ctrl)
+	merchRepo := mocks.NewMockMerchRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+
+	uc := NewPromoUC(promoRepo, merchRepo, userRepo)
+	item := int64(5)
+	now := time.Now()
+	later := now.Add(time.Hour)
+
+	tests := []test{
+		{
+			name: "success",
+			mock: func() {
+				userRepo.EXPECT().
+					GetByID(gomock.Any(), int64(1)).
+					Return(&entity.User{ID: 1, Role: entity.UserRoleAdmin}, nil)
+				merchRepo.EXPECT().GetByID(gomock.Any(), item).Return(entity.MerchItem{ID: item}, nil)
+				promoRepo.EXPECT().
+					CreateCode(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, code *entity.PromoCode) error {
+						require.Equal(t, "SUMMER", code.Code)
+						require.Equal(t, int64(1), code.CreatedBy)
+						code.ID = 3
+						return nil
+					})
+			},
+			res: CreateCodeRequest{Code: " summer ", Kind: entity.DiscountKindPercent, Value: 20, ItemID: &item},
+		},
+		{
+			name: "percent above 100",
+			mock: func() {
+				userRepo.EXPECT().
+					GetByID(gomock.Any(), int64(1)).
+					Return(&entity.User{ID: 1, Role: entity.UserRoleAdmin}, nil)
+			},
+			res: CreateCodeRequest{Code: "ALL", Kind: entity.DiscountKindPercent, Value: 120},
+			err: entity.ErrInvalidDiscount,
+		},
+		{
+			name: "window ends before it starts",
+			mock: func() {
+				userRepo.EXPECT().
+					GetByID(gomock.Any(), int64(1)).
+					Return(&entity.User{ID: 1, Role: entity.UserRoleAdmin}, nil)
+			},
+			res: CreateCodeRequest{Code: "LATE", Kind: entity.DiscountKindFixed, Value: 10, StartsAt: &later, EndsAt: &now},
+			err: entity.ErrInvalidSaleWindow,
+		},
+		{
+			name: "unknown item",
+			mock: func() {
+				userRepo.EXPECT().
+					GetByID(gomock.Any(), int64(1)).
+					Return(&entity.User{ID: 1, Role: entity.UserRoleAdmin}, nil)
+				merchRepo.EXPECT().GetByID(gomock.Any(), item).Return(entity.MerchItem{}, entity.ErrMerchNotFound)
+			},
+			res: CreateCodeRequest{Code: "GONE", Kind: entity.DiscountKindFixed, Value: 10, ItemID: &item},
+			err: entity.ErrMerchNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			code, err := uc.CreateCode(context.Background(), 1, tc.res.(CreateCodeRequest))
+
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+				require.NotZero(t, code.ID)
+			}
+		})
+	}
+
+	t.Run("not an admin", func(t *testing.T) {
+		userRepo.EXPECT().
+			GetByID(gomock.Any(), int64(2)).
+			Return(&entity.User{ID: 2, Role: entity.UserRoleUser}, nil)
+
+		_, err := uc.CreateCode(context.Background(), 2, CreateCodeRequest{Code: "X", Kind: entity.DiscountKindFixed, Value: 1})
+		require.ErrorIs(t, err, entity.ErrForbidden)
+	})
+}
+
+func TestCreateSale(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	promoRepo := mocks.NewMockPromoRepository(ctrl)
+	merchRepo := mocks.NewMockMerchRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+
+	uc := NewPromoUC(promoRepo, merchRepo, userRepo)
+	start := time.Date(2026, 11, 27, 0, 0, 0, 0, time.UTC)
+
+	userRepo.EXPECT().
+		GetByID(gomock.Any(), int64(1)).
+		Return(&entity.User{ID: 1, Role: entity.UserRoleAdmin}, nil).
+		Times(2)
+
+	promoRepo.EXPECT().
+		CreateSale(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, sale *entity.MerchSale) error {
+			require.Nil(t, sale.ItemID)
+			sale.ID = 1
+			return nil
+		})
+
+	sale, err := uc.CreateSale(context.Background(), 1, CreateSaleRequest{
+		Kind:     entity.DiscountKindPercent,
+		Value:    30,
+		StartsAt: start,
+		EndsAt:   start.Add(72 * time.Hour),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), sale.ID)
+
+	_, err = uc.CreateSale(context.Background(), 1, CreateSaleRequest{
+		Kind:     entity.DiscountKindPercent,
+		Value:    30,
+		StartsAt: start,
+		EndsAt:   start,
+	})
+	require.ErrorIs(t, err, entity.ErrInvalidSaleWindow)
+}
@@ -0,0 +1,25 @@
+package raffle_usecase
+
+import (
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"time"
+)
+
+// CreateRaffleRequest raffles Prizes units of an item off at DrawAt. A zero
+// MaxTicketsPerUser lets users buy as many tickets as they like.
+type CreateRaffleRequest struct {
+	ItemName          string    `json:"item" validate:"required"`
+	TicketPrice       int64     `json:"ticket_price" validate:"required,min=1"`
+	MaxTicketsPerUser int64     `json:"max_tickets_per_user,omitempty" validate:"omitempty,min=1"`
+	Prizes            int64     `json:"prizes" validate:"required,min=1"`
+	DrawAt            time.Time `json:"draw_at" validate:"required"`
+}
+
+// RaffleResults is the audit trail of a raffle: every ticket sold and, once
+// drawn, the seed and the winners, enough to repeat the draw with
+// entity.DrawWinners.
+type RaffleResults struct {
+	Raffle  entity.Raffle         `json:"raffle"`
+	Tickets []entity.RaffleTicket `json:"tickets"`
+	Winners []entity.RaffleWinner `json:"winners"`
+}
@@ -0,0 +1,59 @@
+package raffle_usecase
+
+import (
+	"context"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"time"
+)
+
+//go:generate mockgen -source=interfaces.go -destination=./mocks/mocks.go -package=mocks
+
+type RaffleRepository interface {
+	Create(ctx context.Context, raffle *entity.Raffle) error
+	GetByID(ctx context.Context, id int64) (entity.Raffle, error)
+	List(ctx context.Context) ([]entity.Raffle, error)
+	Update(ctx context.Context, raffle entity.Raffle) error
+	AddTickets(ctx context.Context, raffleID, userID, count int64) error
+	CountTickets(ctx context.Context, raffleID, userID int64) (int64, error)
+	ListTickets(ctx context.Context, raffleID int64) ([]entity.RaffleTicket, error)
+	AddWinner(ctx context.Context, winner entity.RaffleWinner) error
+	ListWinners(ctx context.Context, raffleID int64) ([]entity.RaffleWinner, error)
+	ListDue(ctx context.Context, now time.Time, limit int) ([]int64, error)
+}
+
+type UserRepository interface {
+	GetByID(ctx context.Context, id int64) (*entity.User, error)
+	Update(ctx context.Context, user *entity.User) error
+}
+
+type MerchRepository interface {
+	GetByName(ctx context.Context, name string) (entity.MerchItem, error)
+}
+
+// InventoryRepository hands the prizes to the winners.
+type InventoryRepository interface {
+	Add(ctx context.Context, userID, itemID int64, variantID *int64, quantity int64) error
+}
+
+type TransactionRepository interface {
+	Create(ctx context.Context, tr *entity.Transaction) error
+}
+
+// LotRepository takes the ticket price out of the buyer's oldest lots first.
+type LotRepository interface {
+	Spend(ctx context.Context, userID int64, amount int64) ([]entity.CoinLot, error)
+}
+
+// OrderRepository opens an order to hand each prize out.
+type OrderRepository interface {
+	Create(ctx context.Context, order *entity.Order) error
+}
+
+// Notifier delivers a short message to a user.
+type Notifier interface {
+	Notify(ctx context.Context, userID int64, message string) error
+}
+
+type DBTransactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
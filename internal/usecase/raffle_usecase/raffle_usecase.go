@@ -0,0 +1,306 @@
+package raffle_usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"math"
+	"time"
+)
+
+const (
+	_drawBatchSize         = 100
+	_seedBytes             = 32
+	_maxTicketsPerPurchase = 1000
+)
+
+// RaffleUC runs raffles: users buy tickets with coins and, at the scheduled
+// time, winners are drawn from the tickets with the raffle's seed and get
+// the item.
+type RaffleUC struct {
+	raffleRepo RaffleRepository
+	userRepo   UserRepository
+	merchRepo  MerchRepository
+	invRepo    InventoryRepository
+	txRepo     TransactionRepository
+	lotRepo    LotRepository
+	orderRepo  OrderRepository
+	notifier   Notifier
+	dbTx       DBTransactor
+}
+
+func NewRaffleUC(
+	raffleRepo RaffleRepository,
+	userRepo UserRepository,
+	merchRepo MerchRepository,
+	invRepo InventoryRepository,
+	txRepo TransactionRepository,
+	lotRepo LotRepository,
+	orderRepo OrderRepository,
+	notifier Notifier,
+	dbTx DBTransactor,
+) *RaffleUC {
+	return &RaffleUC{
+		raffleRepo: raffleRepo,
+		userRepo:   userRepo,
+		merchRepo:  merchRepo,
+		invRepo:    invRepo,
+		txRepo:     txRepo,
+		lotRepo:    lotRepo,
+		orderRepo:  orderRepo,
+		notifier:   notifier,
+		dbTx:       dbTx,
+	}
+}
+
+// CreateRaffle schedules a raffle. Its seed is generated now and only its
+// hash is shown until the draw, so the result cannot be steered by picking
+// the seed after the tickets are sold.
+func (uc *RaffleUC) CreateRaffle(ctx context.Context, adminID int64, req CreateRaffleRequest) (entity.Raffle, error) {
+	if err := uc.requireAdmin(ctx, adminID); err != nil {
+		return entity.Raffle{}, err
+	}
+
+	item, err := uc.merchRepo.GetByName(ctx, req.ItemName)
+	if err != nil {
+		return entity.Raffle{}, entity.ErrMerchNotFound
+	}
+
+	raffle := entity.Raffle{
+		ItemID:            item.ID,
+		TicketPrice:       req.TicketPrice,
+		MaxTicketsPerUser: req.MaxTicketsPerUser,
+		Prizes:            req.Prizes,
+		DrawAt:            req.DrawAt,
+		Status:            entity.RaffleStatusOpen,
+		CreatedBy:         adminID,
+	}
+	if err := raffle.Validate(); err != nil {
+		return entity.Raffle{}, err
+	}
+	if !raffle.DrawAt.After(time.Now()) {
+		return entity.Raffle{}, entity.ErrInvalidDrawTime
+	}
+
+	seed := make([]byte, _seedBytes)
+	if _, err := rand.Read(seed); err != nil {
+		return entity.Raffle{}, fmt.Errorf("failed to generate raffle seed: %w", err)
+	}
+	raffle.Seed = hex.EncodeToString(seed)
+	raffle.SeedHash = entity.RaffleSeedHash(raffle.Seed)
+
+	if err := uc.raffleRepo.Create(ctx, &raffle); err != nil {
+		return entity.Raffle{}, err
+	}
+
+	raffle.ItemName = item.Name
+	return raffle.Public(), nil
+}
+
+// BuyTickets sells count tickets to the user. The coins are taken the same
+// way as for a purchase and the sale is recorded in the ledger. At most
+// _maxTicketsPerPurchase tickets are sold at once.
+func (uc *RaffleUC) BuyTickets(ctx context.Context, userID, raffleID, count int64) error {
+	if count <= 0 {
+		return entity.ErrInvalidQuantity
+	}
+	if count > _maxTicketsPerPurchase {
+		return entity.ErrTicketLimitReached
+	}
+
+	return uc.dbTx.WithinTransaction(ctx, func(ctx context.Context) error {
+		raffle, err := uc.raffleRepo.GetByID(ctx, raffleID)
+		if err != nil {
+			return err
+		}
+		if !raffle.IsOpen(time.Now()) {
+			return entity.ErrRaffleClosed
+		}
+
+		if raffle.MaxTicketsPerUser > 0 {
+			owned, err := uc.raffleRepo.CountTickets(ctx, raffleID, userID)
+			if err != nil {
+				return err
+			}
+			if owned+count > raffle.MaxTicketsPerUser {
+				return entity.ErrTicketLimitReached
+			}
+		}
+
+		user, err := uc.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return entity.ErrUserNotFound
+		}
+
+		// No balance can cover a cost that does not fit in an int64.
+		if count > math.MaxInt64/raffle.TicketPrice {
+			return entity.ErrInsufficientFunds
+		}
+		cost := raffle.TicketPrice * count
+		if user.Coins < cost {
+			return entity.ErrInsufficientFunds
+		}
+
+		user.Coins -= cost
+		if err := uc.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+		if _, err := uc.lotRepo.Spend(ctx, userID, cost); err != nil {
+			return err
+		}
+
+		if err := uc.raffleRepo.AddTickets(ctx, raffleID, userID, count); err != nil {
+			return err
+		}
+
+		return uc.txRepo.Create(ctx, &entity.Transaction{
+			FromUserID: userID,
+			ToUserID:   userID,
+			Amount:     cost,
+			Type:       entity.TransactionTypeRaffleTicket,
+			ItemID:     &raffle.ItemID,
+			Quantity:   &count,
+		})
+	})
+}
+
+// DrawDue draws every raffle whose time has come and gives the prizes to
+// the winners. Each raffle is drawn in its own transaction. It is meant to
+// be run by the scheduler.
+func (uc *RaffleUC) DrawDue(ctx context.Context, now time.Time) (int, error) {
+	ids, err := uc.raffleRepo.ListDue(ctx, now, _drawBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	drawn := 0
+	var errs []error
+
+	for _, id := range ids {
+		var (
+			raffle  entity.Raffle
+			winners []entity.RaffleWinner
+		)
+
+		err := uc.dbTx.WithinTransaction(ctx, func(ctx context.Context) error {
+			var err error
+			raffle, err = uc.raffleRepo.GetByID(ctx, id)
+			if err != nil {
+				return err
+			}
+			// Drawn since it was listed.
+			if !raffle.IsDue(now) {
+				return nil
+			}
+
+			tickets, err := uc.raffleRepo.ListTickets(ctx, id)
+			if err != nil {
+				return err
+			}
+
+			winners = entity.DrawWinners(raffle.Seed, tickets, raffle.Prizes)
+			for _, w := range winners {
+				if err := uc.award(ctx, raffle, w); err != nil {
+					return err
+				}
+			}
+
+			raffle.Status = entity.RaffleStatusDrawn
+			raffle.DrawnAt = &now
+			if err := uc.raffleRepo.Update(ctx, raffle); err != nil {
+				return err
+			}
+
+			drawn++
+			return nil
+		})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		for _, w := range winners {
+			uc.notify(ctx, w.UserID, fmt.Sprintf("Your ticket #%d won %s in the raffle", w.TicketID, raffle.ItemName))
+		}
+	}
+
+	return drawn, errors.Join(errs...)
+}
+
+// award records the winner and puts the prize in their inventory, with an
+// order to hand it out. The order is free: the winner paid for tickets, not
+// for the item.
+func (uc *RaffleUC) award(ctx context.Context, raffle entity.Raffle, winner entity.RaffleWinner) error {
+	if err := uc.raffleRepo.AddWinner(ctx, winner); err != nil {
+		return err
+	}
+	if err := uc.invRepo.Add(ctx, winner.UserID, raffle.ItemID, nil, 1); err != nil {
+		return err
+	}
+
+	return uc.orderRepo.Create(ctx, &entity.Order{
+		UserID: winner.UserID,
+		ItemID: raffle.ItemID,
+		Status: entity.OrderStatusPlaced,
+	})
+}
+
+func (uc *RaffleUC) ListRaffles(ctx context.Context) ([]entity.Raffle, error) {
+	raffles, err := uc.raffleRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range raffles {
+		raffles[i] = raffles[i].Public()
+	}
+	return raffles, nil
+}
+
+// Results returns the raffle's audit trail. The seed is only included once
+// the raffle has been drawn.
+func (uc *RaffleUC) Results(ctx context.Context, raffleID int64) (RaffleResults, error) {
+	raffle, err := uc.raffleRepo.GetByID(ctx, raffleID)
+	if err != nil {
+		return RaffleResults{}, err
+	}
+
+	tickets, err := uc.raffleRepo.ListTickets(ctx, raffleID)
+	if err != nil {
+		return RaffleResults{}, err
+	}
+
+	winners, err := uc.raffleRepo.ListWinners(ctx, raffleID)
+	if err != nil {
+		return RaffleResults{}, err
+	}
+
+	return RaffleResults{
+		Raffle:  raffle.Public(),
+		Tickets: tickets,
+		Winners: winners,
+	}, nil
+}
+
+// notify is best-effort: the draw has already been committed.
+func (uc *RaffleUC) notify(ctx context.Context, userID int64, message string) {
+	_ = uc.notifier.Notify(ctx, userID, message)
+}
+
+func (uc *RaffleUC) requireAdmin(ctx context.Context, adminID int64) error {
+	admin, err := uc.userRepo.GetByID(ctx, adminID)
+	if err != nil {
+		return err
+	}
+	if admin == nil || !admin.IsAdmin() {
+		return entity.ErrForbidden
+	}
+
+	return nil
+}
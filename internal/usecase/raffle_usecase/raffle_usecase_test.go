@@ -0,0 +1,302 @@
+package raffle_usecase
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/usecase/raffle_usecase/mocks"
+	"github.com/stretchr/testify/require"
+	"math"
+	"testing"
+	"time"
+)
+
+type test struct {
+	name string
+	mock func()
+	res  interface{}
+	err  error
+}
+
+const adminID = int64(9)
+
+func TestCreateRaffle(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	raffleRepo := mocks.NewMockRaffleRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	merchRepo := mocks.NewMockMerchRepository(ctrl)
+
+	uc := NewRaffleUC(
+		raffleRepo,
+		userRepo,
+		merchRepo,
+		mocks.NewMockInventoryRepository(ctrl),
+		mocks.NewMockTransactionRepository(ctrl),
+		mocks.NewMockLotRepository(ctrl),
+		mocks.NewMockOrderRepository(ctrl),
+		mocks.NewMockNotifier(ctrl),
+		mocks.NewMockDBTransactor(ctrl),
+	)
+
+	var stored entity.Raffle
+
+	tests := []test{
+		{
+			name: "commits to a seed",
+			mock: func() {
+				userRepo.EXPECT().
+					GetByID(gomock.Any(), adminID).
+					Return(&entity.User{ID: adminID, Role: entity.UserRoleAdmin}, nil)
+				merchRepo.EXPECT().
+					GetByName(gomock.Any(), "console").
+					Return(entity.MerchItem{ID: 3, Name: "console", Price: 5000}, nil)
+				raffleRepo.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, r *entity.Raffle) error {
+						r.ID = 1
+						stored = *r
+						return nil
+					})
+			},
+			res: time.Now().Add(24 * time.Hour),
+		},
+		{
+			name: "draw time in the past",
+			mock: func() {
+				userRepo.EXPECT().
+					GetByID(gomock.Any(), adminID).
+					Return(&entity.User{ID: adminID, Role: entity.UserRoleAdmin}, nil)
+				merchRepo.EXPECT().
+					GetByName(gomock.Any(), "console").
+					Return(entity.MerchItem{ID: 3, Name: "console", Price: 5000}, nil)
+			},
+			res: time.Now().Add(-time.Hour),
+			err: entity.ErrInvalidDrawTime,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			raffle, err := uc.CreateRaffle(context.Background(), adminID, CreateRaffleRequest{
+				ItemName:    "console",
+				TicketPrice: 10,
+				Prizes:      1,
+				DrawAt:      tc.res.(time.Time),
+			})
+
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+				require.Len(t, stored.Seed, 2*_seedBytes)
+				require.Equal(t, entity.RaffleSeedHash(stored.Seed), raffle.SeedHash)
+				require.Empty(t, raffle.Seed)
+			}
+		})
+	}
+}
+
+func TestBuyTickets(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	raffleRepo := mocks.NewMockRaffleRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	txRepo := mocks.NewMockTransactionRepository(ctrl)
+	lotRepo := mocks.NewMockLotRepository(ctrl)
+	dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+	uc := NewRaffleUC(
+		raffleRepo,
+		userRepo,
+		mocks.NewMockMerchRepository(ctrl),
+		mocks.NewMockInventoryRepository(ctrl),
+		txRepo,
+		lotRepo,
+		mocks.NewMockOrderRepository(ctrl),
+		mocks.NewMockNotifier(ctrl),
+		dbTransactor,
+	)
+
+	raffle := entity.Raffle{ID: 1, ItemID: 3, TicketPrice: 10, MaxTicketsPerUser: 5, Prizes: 1, DrawAt: time.Now().Add(time.Hour), Status: entity.RaffleStatusOpen}
+
+	tests := []test{
+		{
+			name: "buys tickets",
+			mock: func() {
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				raffleRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(raffle, nil)
+				raffleRepo.EXPECT().CountTickets(gomock.Any(), int64(1), int64(2)).Return(int64(2), nil)
+				userRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(&entity.User{ID: 2, Coins: 100}, nil)
+				userRepo.EXPECT().
+					Update(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, u *entity.User) error {
+						require.Equal(t, int64(70), u.Coins)
+						return nil
+					})
+				lotRepo.EXPECT().Spend(gomock.Any(), int64(2), int64(30)).Return(nil, nil)
+				raffleRepo.EXPECT().AddTickets(gomock.Any(), int64(1), int64(2), int64(3)).Return(nil)
+				txRepo.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, tr *entity.Transaction) error {
+						require.Equal(t, entity.TransactionTypeRaffleTicket, tr.Type)
+						require.Equal(t, int64(30), tr.Amount)
+						require.Equal(t, int64(3), *tr.Quantity)
+						return nil
+					})
+			},
+			res: int64(3),
+		},
+		{
+			name: "over the limit",
+			mock: func() {
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				raffleRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(raffle, nil)
+				raffleRepo.EXPECT().CountTickets(gomock.Any(), int64(1), int64(2)).Return(int64(4), nil)
+			},
+			res: int64(2),
+			err: entity.ErrTicketLimitReached,
+		},
+		{
+			name: "not enough coins",
+			mock: func() {
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				raffleRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(raffle, nil)
+				raffleRepo.EXPECT().CountTickets(gomock.Any(), int64(1), int64(2)).Return(int64(0), nil)
+				userRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(&entity.User{ID: 2, Coins: 40}, nil)
+			},
+			res: int64(5),
+			err: entity.ErrInsufficientFunds,
+		},
+		{
+			name: "cost does not fit in an int64",
+			mock: func() {
+				pricey := raffle
+				pricey.TicketPrice = math.MaxInt64 / 2
+				pricey.MaxTicketsPerUser = 0
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				raffleRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(pricey, nil)
+				userRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(&entity.User{ID: 2, Coins: 100}, nil)
+			},
+			res: int64(3),
+			err: entity.ErrInsufficientFunds,
+		},
+		{
+			name: "too many at once",
+			mock: func() {},
+			res:  int64(_maxTicketsPerPurchase + 1),
+			err:  entity.ErrTicketLimitReached,
+		},
+		{
+			name: "after the draw time",
+			mock: func() {
+				closed := raffle
+				closed.DrawAt = time.Now().Add(-time.Minute)
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				raffleRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(closed, nil)
+			},
+			res: int64(1),
+			err: entity.ErrRaffleClosed,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			err := uc.BuyTickets(context.Background(), 2, 1, tc.res.(int64))
+
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDrawDue(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	raffleRepo := mocks.NewMockRaffleRepository(ctrl)
+	invRepo := mocks.NewMockInventoryRepository(ctrl)
+	orderRepo := mocks.NewMockOrderRepository(ctrl)
+	notifier := mocks.NewMockNotifier(ctrl)
+	dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+	uc := NewRaffleUC(
+		raffleRepo,
+		mocks.NewMockUserRepository(ctrl),
+		mocks.NewMockMerchRepository(ctrl),
+		invRepo,
+		mocks.NewMockTransactionRepository(ctrl),
+		mocks.NewMockLotRepository(ctrl),
+		orderRepo,
+		notifier,
+		dbTransactor,
+	)
+
+	now := time.Now()
+
+	raffle := entity.Raffle{ID: 1, ItemID: 3, ItemName: "console", TicketPrice: 10, Prizes: 1, DrawAt: now.Add(-time.Minute), Status: entity.RaffleStatusOpen, Seed: "seed"}
+	tickets := []entity.RaffleTicket{
+		{ID: 1, RaffleID: 1, UserID: 2},
+		{ID: 2, RaffleID: 1, UserID: 2},
+		{ID: 3, RaffleID: 1, UserID: 4},
+	}
+	want := entity.DrawWinners("seed", tickets, 1)[0]
+
+	raffleRepo.EXPECT().ListDue(gomock.Any(), now, _drawBatchSize).Return([]int64{1}, nil)
+	dbTransactor.EXPECT().
+		WithinTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		})
+	raffleRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(raffle, nil)
+	raffleRepo.EXPECT().ListTickets(gomock.Any(), int64(1)).Return(tickets, nil)
+	raffleRepo.EXPECT().AddWinner(gomock.Any(), want).Return(nil)
+	invRepo.EXPECT().Add(gomock.Any(), want.UserID, int64(3), nil, int64(1)).Return(nil)
+	orderRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+	raffleRepo.EXPECT().
+		Update(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, r entity.Raffle) error {
+			require.Equal(t, entity.RaffleStatusDrawn, r.Status)
+			return nil
+		})
+	notifier.EXPECT().Notify(gomock.Any(), want.UserID, gomock.Any()).Return(nil)
+
+	drawn, err := uc.DrawDue(context.Background(), now)
+	require.NoError(t, err)
+	require.Equal(t, 1, drawn)
+}
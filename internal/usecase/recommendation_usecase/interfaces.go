@@ -0,0 +1,22 @@
+package recommendation_usecase
+
+import (
+	"context"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+)
+
+//go:generate mockgen -source=interfaces.go -destination=./mocks/mocks.go -package=mocks
+
+type RecommendationRepository interface {
+	CoPurchases(ctx context.Context) ([]entity.CoPurchase, error)
+	TeamPurchases(ctx context.Context) ([]entity.TeamPurchase, error)
+	PurchasedItems(ctx context.Context, userID int64) ([]int64, error)
+}
+
+type UserRepository interface {
+	GetByID(ctx context.Context, id int64) (*entity.User, error)
+}
+
+type MerchRepository interface {
+	List(ctx context.Context) ([]entity.MerchItem, error)
+}
@@ -0,0 +1,116 @@
+package recommendation_usecase
+
+import (
+	"context"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"sync"
+	"time"
+)
+
+const (
+	_defaultLimit = 10
+	_maxLimit     = 50
+)
+
+// RecommendationUC suggests merch to users. Purchase statistics are
+// aggregated in the database and kept in memory between refreshes, so
+// serving a recommendation only reads the user and the catalog. Statistics
+// older than maxAge are rebuilt on the next request, in case the scheduled
+// refresh has not run.
+type RecommendationUC struct {
+	repo      RecommendationRepository
+	userRepo  UserRepository
+	merchRepo MerchRepository
+	maxAge    time.Duration
+
+	mu    sync.RWMutex
+	stats *entity.RecommendationStats
+}
+
+func NewRecommendationUC(repo RecommendationRepository, userRepo UserRepository, merchRepo MerchRepository, maxAge time.Duration) *RecommendationUC {
+	return &RecommendationUC{
+		repo:      repo,
+		userRepo:  userRepo,
+		merchRepo: merchRepo,
+		maxAge:    maxAge,
+	}
+}
+
+// Refresh rebuilds the purchase statistics. It is meant to be run by the
+// scheduler; until the first run, or when it falls behind, the statistics
+// are loaded on demand.
+func (uc *RecommendationUC) Refresh(ctx context.Context) error {
+	stats, err := uc.load(ctx)
+	if err != nil {
+		return err
+	}
+
+	uc.mu.Lock()
+	uc.stats = stats
+	uc.mu.Unlock()
+
+	return nil
+}
+
+// Recommend returns up to limit items the user doesn't own yet, best first.
+func (uc *RecommendationUC) Recommend(ctx context.Context, userID int64, limit int) ([]entity.Recommendation, error) {
+	if limit <= 0 {
+		limit = _defaultLimit
+	}
+	limit = min(limit, _maxLimit)
+
+	stats, err := uc.snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	bought, err := uc.repo.PurchasedItems(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := uc.merchRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return stats.Recommend(items, bought, user.TeamID, user.Coins, time.Now(), limit), nil
+}
+
+func (uc *RecommendationUC) snapshot(ctx context.Context) (*entity.RecommendationStats, error) {
+	uc.mu.RLock()
+	stats := uc.stats
+	uc.mu.RUnlock()
+
+	if stats != nil && (uc.maxAge <= 0 || time.Since(stats.RefreshedAt) < uc.maxAge) {
+		return stats, nil
+	}
+
+	if err := uc.Refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	uc.mu.RLock()
+	defer uc.mu.RUnlock()
+
+	return uc.stats, nil
+}
+
+func (uc *RecommendationUC) load(ctx context.Context) (*entity.RecommendationStats, error) {
+	coPurchases, err := uc.repo.CoPurchases(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	teamPurchases, err := uc.repo.TeamPurchases(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return entity.NewRecommendationStats(coPurchases, teamPurchases, time.Now()), nil
+}
@@ -0,0 +1,147 @@
+package recommendation_usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/usecase/recommendation_usecase/mocks"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type test struct {
+	name string
+	mock func()
+	res  interface{}
+	err  error
+}
+
+func TestRecommend(t *testing.T) {
+	t.Parallel()
+
+	teamID := int64(1)
+	items := []entity.MerchItem{
+		{ID: 1, Name: "t-shirt", Price: 80},
+		{ID: 2, Name: "cup", Price: 20},
+		{ID: 3, Name: "hoody", Price: 300},
+	}
+	errDB := errors.New("db is down")
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockRecommendationRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	merchRepo := mocks.NewMockMerchRepository(ctrl)
+
+	uc := NewRecommendationUC(repo, userRepo, merchRepo, time.Hour)
+
+	// statistics that failed to load are retried on the next request
+	tests := []test{
+		{
+			name: "stats unavailable",
+			mock: func() {
+				repo.EXPECT().CoPurchases(gomock.Any()).Return(nil, errDB)
+			},
+			err: errDB,
+		},
+		{
+			name: "ranked",
+			mock: func() {
+				repo.EXPECT().CoPurchases(gomock.Any()).Return([]entity.CoPurchase{
+					{ItemID: 1, OtherItemID: 2, Buyers: 3},
+				}, nil)
+				repo.EXPECT().TeamPurchases(gomock.Any()).Return([]entity.TeamPurchase{
+					{TeamID: 1, ItemID: 2, Buyers: 2},
+				}, nil)
+				userRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(&entity.User{ID: 2, Coins: 150, TeamID: &teamID}, nil)
+				repo.EXPECT().PurchasedItems(gomock.Any(), int64(2)).Return([]int64{1}, nil)
+				merchRepo.EXPECT().List(gomock.Any()).Return(items, nil)
+			},
+			res: []entity.Recommendation{
+				{ItemID: 2, Name: "cup", Price: 20, Score: 1, Affordable: true, Reasons: []entity.RecommendationReason{
+					entity.ReasonBoughtTogether, entity.ReasonPopularInTeam, entity.ReasonAffordable,
+				}},
+				{ItemID: 3, Name: "hoody", Price: 300, Score: 0.1, Reasons: []entity.RecommendationReason{}},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			res, err := uc.Recommend(context.Background(), 2, 0)
+
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, res, len(tc.res.([]entity.Recommendation)))
+			for i, want := range tc.res.([]entity.Recommendation) {
+				require.Equal(t, want.ItemID, res[i].ItemID)
+				require.InDelta(t, want.Score, res[i].Score, 1e-9)
+				require.Equal(t, want.Affordable, res[i].Affordable)
+				require.Equal(t, want.Reasons, res[i].Reasons)
+			}
+		})
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reused until the next refresh", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockRecommendationRepository(ctrl)
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		merchRepo := mocks.NewMockMerchRepository(ctrl)
+
+		uc := NewRecommendationUC(repo, userRepo, merchRepo, time.Hour)
+
+		repo.EXPECT().CoPurchases(gomock.Any()).Return(nil, nil).Times(2)
+		repo.EXPECT().TeamPurchases(gomock.Any()).Return(nil, nil).Times(2)
+		userRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(&entity.User{ID: 2, Coins: 100}, nil).Times(3)
+		repo.EXPECT().PurchasedItems(gomock.Any(), int64(2)).Return(nil, nil).Times(3)
+		merchRepo.EXPECT().List(gomock.Any()).Return([]entity.MerchItem{{ID: 1, Name: "cup", Price: 20}}, nil).Times(3)
+
+		for i := 0; i < 2; i++ {
+			res, err := uc.Recommend(context.Background(), 2, 5)
+			require.NoError(t, err)
+			require.Len(t, res, 1)
+		}
+
+		require.NoError(t, uc.Refresh(context.Background()))
+
+		res, err := uc.Recommend(context.Background(), 2, 5)
+		require.NoError(t, err)
+		require.Equal(t, []entity.RecommendationReason{entity.ReasonAffordable}, res[0].Reasons)
+	})
+
+	t.Run("rebuilt once stale", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockRecommendationRepository(ctrl)
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		merchRepo := mocks.NewMockMerchRepository(ctrl)
+
+		uc := NewRecommendationUC(repo, userRepo, merchRepo, time.Hour)
+		uc.stats = entity.NewRecommendationStats(nil, nil, time.Now().Add(-2*time.Hour))
+
+		repo.EXPECT().CoPurchases(gomock.Any()).Return(nil, nil)
+		repo.EXPECT().TeamPurchases(gomock.Any()).Return(nil, nil)
+		userRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(&entity.User{ID: 2, Coins: 100}, nil)
+		repo.EXPECT().PurchasedItems(gomock.Any(), int64(2)).Return(nil, nil)
+		merchRepo.EXPECT().List(gomock.Any()).Return([]entity.MerchItem{{ID: 1, Name: "cup", Price: 20}}, nil)
+
+		_, err := uc.Recommend(context.Background(), 2, 5)
+		require.NoError(t, err)
+		require.WithinDuration(t, time.Now(), uc.stats.RefreshedAt, time.Minute)
+	})
+}
@@ -0,0 +1,16 @@
+package redemption_usecase
+
+import (
+	"github.com/smthjapanese/avito-merch/internal/entity"
+)
+
+// RedeemRequest claims items from the inventory. Deliveries need Address,
+// pickups need LocationID.
+type RedeemRequest struct {
+	ItemName   string                  `json:"item" validate:"required"`
+	VariantID  *int64                  `json:"variant_id,omitempty"`
+	Quantity   int64                   `json:"quantity" validate:"required,min=1"`
+	Method     entity.RedemptionMethod `json:"method" validate:"required"`
+	Address    string                  `json:"address,omitempty"`
+	LocationID *int64                  `json:"location_id,omitempty"`
+}
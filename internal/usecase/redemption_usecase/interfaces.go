@@ -0,0 +1,46 @@
+package redemption_usecase
+
+import (
+	"context"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+)
+
+//go:generate mockgen -source=interfaces.go -destination=./mocks/mocks.go -package=mocks
+
+type RedemptionRepository interface {
+	Create(ctx context.Context, redemption *entity.Redemption) error
+	GetByID(ctx context.Context, id int64) (entity.Redemption, error)
+	ListByUser(ctx context.Context, userID int64) ([]entity.Redemption, error)
+	ListPending(ctx context.Context) ([]entity.Redemption, error)
+	Update(ctx context.Context, redemption entity.Redemption) error
+}
+
+// InventoryRepository moves items out of the inventory and back in a single
+// statement each, so concurrent redemptions cannot overdraw it.
+type InventoryRepository interface {
+	TakeKeeping(ctx context.Context, userID, itemID int64, variantID *int64, quantity, keep int64) (bool, error)
+	Add(ctx context.Context, userID, itemID int64, variantID *int64, quantity int64) error
+}
+
+// OrderRepository tells which units are still waiting to be handed over
+// through an order.
+type OrderRepository interface {
+	CountOpen(ctx context.Context, userID, itemID int64, variantID *int64) (int64, error)
+}
+
+type MerchRepository interface {
+	GetByName(ctx context.Context, name string) (entity.MerchItem, error)
+	GetVariant(ctx context.Context, id int64) (entity.MerchVariant, error)
+}
+
+type LocationRepository interface {
+	GetLocation(ctx context.Context, id int64) (entity.PickupLocation, error)
+}
+
+type UserRepository interface {
+	GetByID(ctx context.Context, id int64) (*entity.User, error)
+}
+
+type DBTransactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,213 @@
+package redemption_usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"strconv"
+	"time"
+)
+
+var pickListHeader = []string{"id", "created_at", "user", "item", "variant", "quantity", "method", "location", "address"}
+
+// RedemptionUC lets users claim the items in their inventory for delivery or
+// pickup, and admins work through the claims.
+type RedemptionUC struct {
+	redemptionRepo RedemptionRepository
+	invRepo        InventoryRepository
+	orderRepo      OrderRepository
+	merchRepo      MerchRepository
+	locationRepo   LocationRepository
+	userRepo       UserRepository
+	dbTx           DBTransactor
+}
+
+func NewRedemptionUC(
+	redemptionRepo RedemptionRepository,
+	invRepo InventoryRepository,
+	orderRepo OrderRepository,
+	merchRepo MerchRepository,
+	locationRepo LocationRepository,
+	userRepo UserRepository,
+	dbTx DBTransactor,
+) *RedemptionUC {
+	return &RedemptionUC{
+		redemptionRepo: redemptionRepo,
+		invRepo:        invRepo,
+		orderRepo:      orderRepo,
+		merchRepo:      merchRepo,
+		locationRepo:   locationRepo,
+		userRepo:       userRepo,
+		dbTx:           dbTx,
+	}
+}
+
+// Redeem takes the requested quantity out of the user's inventory and opens
+// a redemption for it. Units with an open order are handed over through that
+// order, so only the rest can be redeemed.
+func (uc *RedemptionUC) Redeem(ctx context.Context, userID int64, req RedeemRequest) (entity.Redemption, error) {
+	redemption := entity.Redemption{
+		UserID:     userID,
+		VariantID:  req.VariantID,
+		Quantity:   req.Quantity,
+		Method:     req.Method,
+		Address:    req.Address,
+		LocationID: req.LocationID,
+		Status:     entity.RedemptionStatusPending,
+	}
+	if err := redemption.Validate(); err != nil {
+		return entity.Redemption{}, err
+	}
+
+	item, err := uc.merchRepo.GetByName(ctx, req.ItemName)
+	if err != nil {
+		return entity.Redemption{}, entity.ErrMerchNotFound
+	}
+	redemption.ItemID = item.ID
+
+	if redemption.VariantID != nil {
+		variant, err := uc.merchRepo.GetVariant(ctx, *redemption.VariantID)
+		if err != nil {
+			return entity.Redemption{}, err
+		}
+		if variant.ItemID != item.ID {
+			return entity.Redemption{}, entity.ErrVariantNotFound
+		}
+	}
+	if redemption.LocationID != nil {
+		if _, err := uc.locationRepo.GetLocation(ctx, *redemption.LocationID); err != nil {
+			return entity.Redemption{}, err
+		}
+	}
+
+	err = uc.dbTx.WithinTransaction(ctx, func(ctx context.Context) error {
+		ordered, err := uc.orderRepo.CountOpen(ctx, userID, item.ID, redemption.VariantID)
+		if err != nil {
+			return err
+		}
+
+		taken, err := uc.invRepo.TakeKeeping(ctx, userID, item.ID, redemption.VariantID, redemption.Quantity, ordered)
+		if err != nil {
+			return err
+		}
+		if !taken {
+			return entity.ErrNotEnoughItems
+		}
+
+		return uc.redemptionRepo.Create(ctx, &redemption)
+	})
+	if err != nil {
+		return entity.Redemption{}, err
+	}
+
+	return redemption, nil
+}
+
+func (uc *RedemptionUC) ListRedemptions(ctx context.Context, userID int64) ([]entity.Redemption, error) {
+	return uc.redemptionRepo.ListByUser(ctx, userID)
+}
+
+func (uc *RedemptionUC) ListPending(ctx context.Context, adminID int64) ([]entity.Redemption, error) {
+	if err := uc.requireAdmin(ctx, adminID); err != nil {
+		return nil, err
+	}
+
+	return uc.redemptionRepo.ListPending(ctx)
+}
+
+// PickList renders the pending redemptions as CSV for the people packing
+// them.
+func (uc *RedemptionUC) PickList(ctx context.Context, adminID int64) ([]byte, error) {
+	pending, err := uc.ListPending(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	if err := w.Write(pickListHeader); err != nil {
+		return nil, err
+	}
+	for _, r := range pending {
+		record := []string{
+			strconv.FormatInt(r.ID, 10),
+			r.CreatedAt.UTC().Format(time.RFC3339),
+			r.Username,
+			r.ItemName,
+			r.Variant,
+			strconv.FormatInt(r.Quantity, 10),
+			string(r.Method),
+			r.Location,
+			r.Address,
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Fulfil marks a pending redemption as handed over.
+func (uc *RedemptionUC) Fulfil(ctx context.Context, adminID, id int64) error {
+	return uc.dbTx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := uc.requireAdmin(ctx, adminID); err != nil {
+			return err
+		}
+
+		redemption, err := uc.redemptionRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := redemption.Resolve(entity.RedemptionStatusFulfilled, adminID, time.Now()); err != nil {
+			return err
+		}
+
+		return uc.redemptionRepo.Update(ctx, redemption)
+	})
+}
+
+// Cancel withdraws a pending redemption and puts its items back into the
+// inventory. The user who made it and admins may cancel it.
+func (uc *RedemptionUC) Cancel(ctx context.Context, userID, id int64) error {
+	return uc.dbTx.WithinTransaction(ctx, func(ctx context.Context) error {
+		redemption, err := uc.redemptionRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if redemption.UserID != userID {
+			if err := uc.requireAdmin(ctx, userID); err != nil {
+				return err
+			}
+		}
+
+		if err := redemption.Resolve(entity.RedemptionStatusCancelled, userID, time.Now()); err != nil {
+			return err
+		}
+		if err := uc.redemptionRepo.Update(ctx, redemption); err != nil {
+			return err
+		}
+
+		return uc.invRepo.Add(ctx, redemption.UserID, redemption.ItemID, redemption.VariantID, redemption.Quantity)
+	})
+}
+
+func (uc *RedemptionUC) requireAdmin(ctx context.Context, adminID int64) error {
+	admin, err := uc.userRepo.GetByID(ctx, adminID)
+	if err != nil {
+		return err
+	}
+	if admin == nil || !admin.IsAdmin() {
+		return entity.ErrForbidden
+	}
+
+	return nil
+}
@@ -0,0 +1,208 @@
+package redemption_usecase
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/usecase/redemption_usecase/mocks"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type test struct {
+	name string
+	mock func()
+	res  interface{}
+	err  error
+}
+
+const adminID = int64(9)
+
+func TestRedeem(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	redemptionRepo := mocks.NewMockRedemptionRepository(ctrl)
+	invRepo := mocks.NewMockInventoryRepository(ctrl)
+	orderRepo := mocks.NewMockOrderRepository(ctrl)
+	merchRepo := mocks.NewMockMerchRepository(ctrl)
+	locationRepo := mocks.NewMockLocationRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+	uc := NewRedemptionUC(redemptionRepo, invRepo, orderRepo, merchRepo, locationRepo, userRepo, dbTransactor)
+	office := int64(2)
+
+	tests := []test{
+		{
+			name: "pickup",
+			mock: func() {
+				merchRepo.EXPECT().GetByName(gomock.Any(), "cup").Return(entity.MerchItem{ID: 3, Name: "cup", Price: 20}, nil)
+				locationRepo.EXPECT().GetLocation(gomock.Any(), office).Return(entity.PickupLocation{ID: office}, nil)
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				orderRepo.EXPECT().CountOpen(gomock.Any(), int64(1), int64(3), nil).Return(int64(0), nil)
+				invRepo.EXPECT().TakeKeeping(gomock.Any(), int64(1), int64(3), nil, int64(2), int64(0)).Return(true, nil)
+				redemptionRepo.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, r *entity.Redemption) error {
+						require.Equal(t, entity.RedemptionStatusPending, r.Status)
+						require.Equal(t, office, *r.LocationID)
+						r.ID = 11
+						return nil
+					})
+			},
+			res: RedeemRequest{ItemName: "cup", Quantity: 2, Method: entity.RedemptionMethodPickup, LocationID: &office},
+		},
+		{
+			name: "more than the inventory holds",
+			mock: func() {
+				merchRepo.EXPECT().GetByName(gomock.Any(), "cup").Return(entity.MerchItem{ID: 3, Name: "cup", Price: 20}, nil)
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				orderRepo.EXPECT().CountOpen(gomock.Any(), int64(1), int64(3), nil).Return(int64(0), nil)
+				invRepo.EXPECT().TakeKeeping(gomock.Any(), int64(1), int64(3), nil, int64(5), int64(0)).Return(false, nil)
+			},
+			res: RedeemRequest{ItemName: "cup", Quantity: 5, Method: entity.RedemptionMethodDelivery, Address: "Nevsky 1"},
+			err: entity.ErrNotEnoughItems,
+		},
+		{
+			name: "units still waiting on an order",
+			mock: func() {
+				merchRepo.EXPECT().GetByName(gomock.Any(), "cup").Return(entity.MerchItem{ID: 3, Name: "cup", Price: 20}, nil)
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				orderRepo.EXPECT().CountOpen(gomock.Any(), int64(1), int64(3), nil).Return(int64(1), nil)
+				invRepo.EXPECT().TakeKeeping(gomock.Any(), int64(1), int64(3), nil, int64(1), int64(1)).Return(false, nil)
+			},
+			res: RedeemRequest{ItemName: "cup", Quantity: 1, Method: entity.RedemptionMethodDelivery, Address: "Nevsky 1"},
+			err: entity.ErrNotEnoughItems,
+		},
+		{
+			name: "delivery without an address",
+			mock: func() {},
+			res:  RedeemRequest{ItemName: "cup", Quantity: 1, Method: entity.RedemptionMethodDelivery, Address: "  "},
+			err:  entity.ErrAddressRequired,
+		},
+		{
+			name: "variant of another item",
+			mock: func() {
+				merchRepo.EXPECT().GetByName(gomock.Any(), "cup").Return(entity.MerchItem{ID: 3, Name: "cup", Price: 20}, nil)
+				merchRepo.EXPECT().GetVariant(gomock.Any(), int64(4)).Return(entity.MerchVariant{ID: 4, ItemID: 1}, nil)
+			},
+			res: RedeemRequest{ItemName: "cup", VariantID: func(v int64) *int64 { return &v }(4), Quantity: 1, Method: entity.RedemptionMethodDelivery, Address: "Nevsky 1"},
+			err: entity.ErrVariantNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			redemption, err := uc.Redeem(context.Background(), 1, tc.res.(RedeemRequest))
+
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, int64(11), redemption.ID)
+			}
+		})
+	}
+}
+
+func TestCancel(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	redemptionRepo := mocks.NewMockRedemptionRepository(ctrl)
+	invRepo := mocks.NewMockInventoryRepository(ctrl)
+	orderRepo := mocks.NewMockOrderRepository(ctrl)
+	merchRepo := mocks.NewMockMerchRepository(ctrl)
+	locationRepo := mocks.NewMockLocationRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+	uc := NewRedemptionUC(redemptionRepo, invRepo, orderRepo, merchRepo, locationRepo, userRepo, dbTransactor)
+
+	dbTransactor.EXPECT().
+		WithinTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		}).
+		Times(2)
+
+	pending := entity.Redemption{ID: 11, UserID: 1, ItemID: 3, Quantity: 2, Method: entity.RedemptionMethodDelivery, Status: entity.RedemptionStatusPending}
+
+	redemptionRepo.EXPECT().GetByID(gomock.Any(), int64(11)).Return(pending, nil)
+	redemptionRepo.EXPECT().
+		Update(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, r entity.Redemption) error {
+			require.Equal(t, entity.RedemptionStatusCancelled, r.Status)
+			return nil
+		})
+	invRepo.EXPECT().Add(gomock.Any(), int64(1), int64(3), nil, int64(2)).Return(nil)
+
+	require.NoError(t, uc.Cancel(context.Background(), 1, 11))
+
+	fulfilled := pending
+	fulfilled.Status = entity.RedemptionStatusFulfilled
+	redemptionRepo.EXPECT().GetByID(gomock.Any(), int64(11)).Return(fulfilled, nil)
+
+	require.ErrorIs(t, uc.Cancel(context.Background(), 1, 11), entity.ErrRedemptionNotPending)
+}
+
+func TestPickList(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	redemptionRepo := mocks.NewMockRedemptionRepository(ctrl)
+	invRepo := mocks.NewMockInventoryRepository(ctrl)
+	orderRepo := mocks.NewMockOrderRepository(ctrl)
+	merchRepo := mocks.NewMockMerchRepository(ctrl)
+	locationRepo := mocks.NewMockLocationRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+	uc := NewRedemptionUC(redemptionRepo, invRepo, orderRepo, merchRepo, locationRepo, userRepo, dbTransactor)
+	created := time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC)
+
+	userRepo.EXPECT().
+		GetByID(gomock.Any(), adminID).
+		Return(&entity.User{ID: adminID, Role: entity.UserRoleAdmin}, nil)
+	redemptionRepo.EXPECT().
+		ListPending(gomock.Any()).
+		Return([]entity.Redemption{
+			{ID: 1, CreatedAt: created, Username: "anna", ItemName: "cup", Quantity: 2, Method: entity.RedemptionMethodDelivery, Address: "Nevsky 1, apt. 2"},
+			{ID: 2, CreatedAt: created, Username: "ivan", ItemName: "hoody", Variant: "L, black", Quantity: 1, Method: entity.RedemptionMethodPickup, Location: "Moscow"},
+		}, nil)
+
+	csv, err := uc.PickList(context.Background(), adminID)
+	require.NoError(t, err)
+	require.Equal(t,
+		"id,created_at,user,item,variant,quantity,method,location,address\n"+
+			"1,2026-10-18T09:30:00Z,anna,cup,,2,delivery,,\"Nevsky 1, apt. 2\"\n"+
+			"2,2026-10-18T09:30:00Z,ivan,hoody,\"L, black\",1,pickup,Moscow,\n",
+		string(csv))
+
+	userRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&entity.User{ID: 1, Role: entity.UserRoleUser}, nil)
+
+	_, err = uc.PickList(context.Background(), 1)
+	require.ErrorIs(t, err, entity.ErrForbidden)
+}
@@ -0,0 +1,22 @@
+package reward_usecase
+
+import (
+	"time"
+)
+
+type RewardReport struct {
+	ManagerID  int64        `json:"manager_id"`
+	Period     string       `json:"period"`
+	Allocation int64        `json:"allocation"`
+	Remaining  int64        `json:"remaining"`
+	Awarded    int64        `json:"awarded"`
+	Rewards    []RewardInfo `json:"rewards"`
+}
+
+type RewardInfo struct {
+	ID        int64     `json:"id"`
+	User      string    `json:"user"`
+	Amount    int64     `json:"amount"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
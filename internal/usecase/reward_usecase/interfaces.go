@@ -0,0 +1,34 @@
+package reward_usecase
+
+import (
+	"context"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+)
+
+//go:generate mockgen -source=interfaces.go -destination=./mocks/mocks.go -package=mocks
+
+type TransactionRepository interface {
+	Create(ctx context.Context, tr *entity.Transaction) error
+}
+
+type LotRepository interface {
+	Create(ctx context.Context, lot *entity.CoinLot) error
+}
+
+type UserRepository interface {
+	GetByID(ctx context.Context, id int64) (*entity.User, error)
+	Update(ctx context.Context, user *entity.User) error
+}
+
+type RewardRepository interface {
+	GetBudget(ctx context.Context, managerID int64) (entity.RewardBudget, error)
+	ListBudgets(ctx context.Context) ([]entity.RewardBudget, error)
+	SaveBudget(ctx context.Context, budget entity.RewardBudget) error
+	ResetBudgets(ctx context.Context, period string) (int64, error)
+	CreateReward(ctx context.Context, reward *entity.Reward) error
+	ListRewards(ctx context.Context, managerID int64, period string) ([]entity.Reward, error)
+}
+
+type DBTransactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,224 @@
+package reward_usecase
+
+import (
+	"context"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// RewardUC lets managers recognize their reports with coins drawn from a
+// monthly reward budget instead of their own balance.
+type RewardUC struct {
+	userRepo   UserRepository
+	rewardRepo RewardRepository
+	txRepo     TransactionRepository
+	lotRepo    LotRepository
+	dbTx       DBTransactor
+	maxAward   int64
+}
+
+func NewRewardUC(
+	userRepo UserRepository,
+	rewardRepo RewardRepository,
+	txRepo TransactionRepository,
+	lotRepo LotRepository,
+	dbTx DBTransactor,
+	maxAward int64,
+) *RewardUC {
+	return &RewardUC{
+		userRepo:   userRepo,
+		rewardRepo: rewardRepo,
+		txRepo:     txRepo,
+		lotRepo:    lotRepo,
+		dbTx:       dbTx,
+		maxAward:   maxAward,
+	}
+}
+
+func (uc *RewardUC) Award(ctx context.Context, managerID, toUserID int64, amount int64, reason string) error {
+	if amount <= 0 {
+		return entity.ErrNegativeAmount
+	}
+	if uc.maxAward > 0 && amount > uc.maxAward {
+		return entity.ErrRewardCapExceeded
+	}
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return entity.ErrReasonRequired
+	}
+	if utf8.RuneCountInString(reason) > entity.MaxRewardReasonLength {
+		return entity.ErrMessageTooLong
+	}
+
+	return uc.dbTx.WithinTransaction(ctx, func(ctx context.Context) error {
+		manager, err := uc.userRepo.GetByID(ctx, managerID)
+		if err != nil {
+			return err
+		}
+		if manager == nil || !manager.IsManager() {
+			return entity.ErrForbidden
+		}
+
+		report, err := uc.userRepo.GetByID(ctx, toUserID)
+		if err != nil {
+			return err
+		}
+		if report == nil {
+			return entity.ErrUserNotFound
+		}
+		if !report.ReportsTo(managerID) {
+			return entity.ErrForbidden
+		}
+
+		budget, err := uc.rewardRepo.GetBudget(ctx, managerID)
+		if err != nil {
+			return err
+		}
+
+		period := entity.MonthlyPeriod(time.Now())
+		if budget.Available(period) < amount {
+			return entity.ErrBudgetExceeded
+		}
+
+		budget.Balance = budget.Available(period) - amount
+		budget.Period = period
+		if err := uc.rewardRepo.SaveBudget(ctx, budget); err != nil {
+			return err
+		}
+
+		report.Coins += amount
+		if err := uc.userRepo.Update(ctx, report); err != nil {
+			return err
+		}
+
+		// Unlike grants, rewards are earned for specific work and never expire.
+		lot := entity.NewCoinLot(toUserID, amount, time.Now(), 0)
+		if err := uc.lotRepo.Create(ctx, &lot); err != nil {
+			return err
+		}
+
+		tx := entity.Transaction{
+			FromUserID: managerID,
+			ToUserID:   toUserID,
+			Amount:     amount,
+			Type:       entity.TransactionTypeReward,
+		}
+		if err := uc.txRepo.Create(ctx, &tx); err != nil {
+			return err
+		}
+
+		return uc.rewardRepo.CreateReward(ctx, &entity.Reward{
+			ManagerID: managerID,
+			UserID:    toUserID,
+			Amount:    amount,
+			Reason:    reason,
+			Period:    period,
+		})
+	})
+}
+
+// SetBudget assigns a manager's monthly allocation. The remaining balance for
+// the current period is refilled to the new allocation.
+func (uc *RewardUC) SetBudget(ctx context.Context, adminID, managerID int64, allocation int64) error {
+	if allocation < 0 {
+		return entity.ErrNegativeAmount
+	}
+
+	return uc.dbTx.WithinTransaction(ctx, func(ctx context.Context) error {
+		admin, err := uc.userRepo.GetByID(ctx, adminID)
+		if err != nil {
+			return err
+		}
+		if admin == nil || !admin.IsAdmin() {
+			return entity.ErrForbidden
+		}
+
+		manager, err := uc.userRepo.GetByID(ctx, managerID)
+		if err != nil {
+			return err
+		}
+		if manager == nil || !manager.IsManager() {
+			return entity.ErrUserNotFound
+		}
+
+		return uc.rewardRepo.SaveBudget(ctx, entity.RewardBudget{
+			UserID:     managerID,
+			Allocation: allocation,
+			Balance:    allocation,
+			Period:     entity.MonthlyPeriod(time.Now()),
+		})
+	})
+}
+
+// ResetBudgets refills all budgets for the period containing now. It is meant
+// to be run by the scheduler and is safe to repeat.
+func (uc *RewardUC) ResetBudgets(ctx context.Context, now time.Time) (int64, error) {
+	return uc.rewardRepo.ResetBudgets(ctx, entity.MonthlyPeriod(now))
+}
+
+// Report returns what the manager has awarded in the period containing now.
+// Only the manager and admins may see it.
+func (uc *RewardUC) Report(ctx context.Context, callerID, managerID int64, now time.Time) (RewardReport, error) {
+	if callerID != managerID {
+		caller, err := uc.userRepo.GetByID(ctx, callerID)
+		if err != nil {
+			return RewardReport{}, err
+		}
+		if caller == nil || !caller.IsAdmin() {
+			return RewardReport{}, entity.ErrForbidden
+		}
+	}
+
+	budget, err := uc.rewardRepo.GetBudget(ctx, managerID)
+	if err != nil {
+		return RewardReport{}, err
+	}
+
+	period := entity.MonthlyPeriod(now)
+	rewards, err := uc.rewardRepo.ListRewards(ctx, managerID, period)
+	if err != nil {
+		return RewardReport{}, err
+	}
+
+	report := RewardReport{
+		ManagerID:  managerID,
+		Period:     period,
+		Allocation: budget.Allocation,
+		Remaining:  budget.Available(period),
+		Rewards:    make([]RewardInfo, 0, len(rewards)),
+	}
+
+	for _, reward := range rewards {
+		report.Awarded += reward.Amount
+
+		user, err := uc.userRepo.GetByID(ctx, reward.UserID)
+		if err != nil {
+			continue
+		}
+		report.Rewards = append(report.Rewards, RewardInfo{
+			ID:        reward.ID,
+			User:      user.Username,
+			Amount:    reward.Amount,
+			Reason:    reward.Reason,
+			CreatedAt: reward.CreatedAt,
+		})
+	}
+
+	return report, nil
+}
+
+// ListBudgets returns every manager's budget for admins to review.
+func (uc *RewardUC) ListBudgets(ctx context.Context, adminID int64) ([]entity.RewardBudget, error) {
+	admin, err := uc.userRepo.GetByID(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+	if admin == nil || !admin.IsAdmin() {
+		return nil, entity.ErrForbidden
+	}
+
+	return uc.rewardRepo.ListBudgets(ctx)
+}
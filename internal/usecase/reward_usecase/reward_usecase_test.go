@@ -0,0 +1,317 @@
+package reward_usecase
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/usecase/reward_usecase/mocks"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+type test struct {
+	name   string
+	mock   func()
+	toID   int64
+	amount int64
+	reason string
+	res    interface{}
+	err    error
+}
+
+func TestAward(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	rewardRepo := mocks.NewMockRewardRepository(ctrl)
+	txRepo := mocks.NewMockTransactionRepository(ctrl)
+	dbTx := mocks.NewMockDBTransactor(ctrl)
+
+	lotRepo := mocks.NewMockLotRepository(ctrl)
+
+	uc := NewRewardUC(userRepo, rewardRepo, txRepo, lotRepo, dbTx, 200)
+
+	managerID := int64(1)
+	period := entity.MonthlyPeriod(time.Now())
+
+	newManager := func() *entity.User {
+		return &entity.User{ID: managerID, Username: "manager", Coins: 10, Role: entity.UserRoleManager}
+	}
+	newReport := func() *entity.User {
+		return &entity.User{ID: 2, Username: "report", Coins: 500, Role: entity.UserRoleUser, ManagerID: &managerID}
+	}
+
+	expectTransaction := func() {
+		dbTx.EXPECT().
+			WithinTransaction(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+	}
+
+	tests := []test{
+		{
+			name:   "success",
+			toID:   2,
+			amount: 150,
+			reason: "shipped the release",
+			mock: func() {
+				expectTransaction()
+
+				userRepo.EXPECT().GetByID(gomock.Any(), managerID).Return(newManager(), nil)
+				userRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(newReport(), nil)
+
+				rewardRepo.EXPECT().
+					GetBudget(gomock.Any(), managerID).
+					Return(entity.RewardBudget{UserID: managerID, Allocation: 1000, Balance: 400, Period: period}, nil)
+
+				rewardRepo.EXPECT().
+					SaveBudget(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, budget entity.RewardBudget) error {
+						require.Equal(t, int64(250), budget.Balance)
+						return nil
+					})
+
+				userRepo.EXPECT().
+					Update(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, user *entity.User) error {
+						require.Equal(t, int64(2), user.ID)
+						require.Equal(t, int64(650), user.Coins)
+						return nil
+					})
+
+				lotRepo.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, lot *entity.CoinLot) error {
+						require.Equal(t, int64(150), lot.Remaining)
+						require.Nil(t, lot.ExpiresAt)
+						return nil
+					})
+
+				txRepo.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, tx *entity.Transaction) error {
+						require.Equal(t, entity.TransactionTypeReward, tx.Type)
+						require.Equal(t, managerID, tx.FromUserID)
+						return nil
+					})
+
+				rewardRepo.EXPECT().
+					CreateReward(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, reward *entity.Reward) error {
+						require.Equal(t, "shipped the release", reward.Reason)
+						require.Equal(t, period, reward.Period)
+						return nil
+					})
+			},
+			err: nil,
+		},
+		{
+			name:   "stale budget is refilled before spending",
+			toID:   2,
+			amount: 100,
+			reason: "onboarding",
+			mock: func() {
+				expectTransaction()
+
+				userRepo.EXPECT().GetByID(gomock.Any(), managerID).Return(newManager(), nil)
+				userRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(newReport(), nil)
+
+				rewardRepo.EXPECT().
+					GetBudget(gomock.Any(), managerID).
+					Return(entity.RewardBudget{UserID: managerID, Allocation: 1000, Balance: 0, Period: "2000-01"}, nil)
+
+				rewardRepo.EXPECT().
+					SaveBudget(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, budget entity.RewardBudget) error {
+						require.Equal(t, int64(900), budget.Balance)
+						require.Equal(t, period, budget.Period)
+						return nil
+					})
+
+				userRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+				lotRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				txRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				rewardRepo.EXPECT().CreateReward(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			err: nil,
+		},
+		{
+			name:   "budget exceeded",
+			toID:   2,
+			amount: 150,
+			reason: "great work",
+			mock: func() {
+				expectTransaction()
+
+				userRepo.EXPECT().GetByID(gomock.Any(), managerID).Return(newManager(), nil)
+				userRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(newReport(), nil)
+
+				rewardRepo.EXPECT().
+					GetBudget(gomock.Any(), managerID).
+					Return(entity.RewardBudget{UserID: managerID, Allocation: 1000, Balance: 100, Period: period}, nil)
+			},
+			err: entity.ErrBudgetExceeded,
+		},
+		{
+			name:   "not a direct report",
+			toID:   3,
+			amount: 50,
+			reason: "great work",
+			mock: func() {
+				expectTransaction()
+
+				userRepo.EXPECT().GetByID(gomock.Any(), managerID).Return(newManager(), nil)
+				userRepo.EXPECT().
+					GetByID(gomock.Any(), int64(3)).
+					Return(&entity.User{ID: 3, Username: "stranger"}, nil)
+			},
+			err: entity.ErrForbidden,
+		},
+		{
+			name:   "above per-award cap",
+			toID:   2,
+			amount: 201,
+			reason: "great work",
+			mock:   func() {},
+			err:    entity.ErrRewardCapExceeded,
+		},
+		{
+			name:   "empty reason",
+			toID:   2,
+			amount: 50,
+			reason: "  ",
+			mock:   func() {},
+			err:    entity.ErrReasonRequired,
+		},
+		{
+			name:   "reason too long",
+			toID:   2,
+			amount: 50,
+			reason: strings.Repeat("я", entity.MaxRewardReasonLength+1),
+			mock:   func() {},
+			err:    entity.ErrMessageTooLong,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			err := uc.Award(context.Background(), managerID, tc.toID, tc.amount, tc.reason)
+
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestReport(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	rewardRepo := mocks.NewMockRewardRepository(ctrl)
+
+	uc := NewRewardUC(userRepo, rewardRepo, mocks.NewMockTransactionRepository(ctrl), mocks.NewMockLotRepository(ctrl), mocks.NewMockDBTransactor(ctrl), 0)
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	testTime := time.Date(2026, 10, 2, 12, 0, 0, 0, time.UTC)
+
+	rewardRepo.EXPECT().
+		GetBudget(gomock.Any(), int64(1)).
+		Return(entity.RewardBudget{UserID: 1, Allocation: 1000, Balance: 700, Period: "2026-10"}, nil)
+
+	rewardRepo.EXPECT().
+		ListRewards(gomock.Any(), int64(1), "2026-10").
+		Return([]entity.Reward{
+			{ID: 1, ManagerID: 1, UserID: 2, Amount: 200, Reason: "demo", Period: "2026-10", CreatedAt: testTime},
+			{ID: 2, ManagerID: 1, UserID: 3, Amount: 100, Reason: "review", Period: "2026-10", CreatedAt: testTime},
+		}, nil)
+
+	userRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(&entity.User{ID: 2, Username: "alice"}, nil)
+	userRepo.EXPECT().GetByID(gomock.Any(), int64(3)).Return(&entity.User{ID: 3, Username: "bob"}, nil)
+
+	report, err := uc.Report(context.Background(), 1, 1, now)
+	require.NoError(t, err)
+	require.Equal(t, RewardReport{
+		ManagerID:  1,
+		Period:     "2026-10",
+		Allocation: 1000,
+		Remaining:  700,
+		Awarded:    300,
+		Rewards: []RewardInfo{
+			{ID: 1, User: "alice", Amount: 200, Reason: "demo", CreatedAt: testTime},
+			{ID: 2, User: "bob", Amount: 100, Reason: "review", CreatedAt: testTime},
+		},
+	}, report)
+
+	t.Run("admin", func(t *testing.T) {
+		userRepo.EXPECT().GetByID(gomock.Any(), int64(9)).Return(&entity.User{ID: 9, Role: entity.UserRoleAdmin}, nil)
+		rewardRepo.EXPECT().GetBudget(gomock.Any(), int64(1)).Return(entity.RewardBudget{UserID: 1}, nil)
+		rewardRepo.EXPECT().ListRewards(gomock.Any(), int64(1), "2026-10").Return(nil, nil)
+
+		_, err := uc.Report(context.Background(), 9, 1, now)
+		require.NoError(t, err)
+	})
+
+	t.Run("another manager", func(t *testing.T) {
+		userRepo.EXPECT().GetByID(gomock.Any(), int64(4)).Return(&entity.User{ID: 4, Role: entity.UserRoleManager}, nil)
+
+		_, err := uc.Report(context.Background(), 4, 1, now)
+		require.ErrorIs(t, err, entity.ErrForbidden)
+	})
+}
+
+func TestSetBudget(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	rewardRepo := mocks.NewMockRewardRepository(ctrl)
+	dbTx := mocks.NewMockDBTransactor(ctrl)
+
+	uc := NewRewardUC(userRepo, rewardRepo, mocks.NewMockTransactionRepository(ctrl), mocks.NewMockLotRepository(ctrl), dbTx, 0)
+
+	dbTx.EXPECT().
+		WithinTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		}).
+		Times(2)
+
+	t.Run("success", func(t *testing.T) {
+		userRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&entity.User{ID: 1, Role: entity.UserRoleAdmin}, nil)
+		userRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(&entity.User{ID: 2, Role: entity.UserRoleManager}, nil)
+
+		rewardRepo.EXPECT().
+			SaveBudget(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, budget entity.RewardBudget) error {
+				require.Equal(t, int64(2), budget.UserID)
+				require.Equal(t, int64(500), budget.Allocation)
+				require.Equal(t, int64(500), budget.Balance)
+				return nil
+			})
+
+		require.NoError(t, uc.SetBudget(context.Background(), 1, 2, 500))
+	})
+
+	t.Run("not an admin", func(t *testing.T) {
+		userRepo.EXPECT().GetByID(gomock.Any(), int64(3)).Return(&entity.User{ID: 3, Role: entity.UserRoleManager}, nil)
+
+		err := uc.SetBudget(context.Background(), 3, 2, 500)
+		require.ErrorIs(t, err, entity.ErrForbidden)
+	})
+}
@@ -0,0 +1,14 @@
+package scheduled_transfer_usecase
+
+import (
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"time"
+)
+
+type ScheduleRequest struct {
+	ToUserID   int64             `json:"to_user_id"`
+	Amount     int64             `json:"amount"`
+	Message    string            `json:"message,omitempty"`
+	RunAt      time.Time         `json:"run_at"`
+	Recurrence entity.Recurrence `json:"recurrence,omitempty"`
+}
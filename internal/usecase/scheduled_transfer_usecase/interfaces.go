@@ -0,0 +1,32 @@
+package scheduled_transfer_usecase
+
+import (
+	"context"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"time"
+)
+
+//go:generate mockgen -source=interfaces.go -destination=./mocks/mocks.go -package=mocks
+
+type Repository interface {
+	Create(ctx context.Context, st *entity.ScheduledTransfer) error
+	GetByID(ctx context.Context, id int64) (entity.ScheduledTransfer, error)
+	ListDue(ctx context.Context, now time.Time, limit int) ([]int64, error)
+	ListByUser(ctx context.Context, userID int64) ([]entity.ScheduledTransfer, error)
+	Update(ctx context.Context, st entity.ScheduledTransfer) error
+	CreateRun(ctx context.Context, run *entity.ScheduledTransferRun) (bool, error)
+	ListRuns(ctx context.Context, scheduledTransferID int64) ([]entity.ScheduledTransferRun, error)
+}
+
+type UserRepository interface {
+	GetByID(ctx context.Context, id int64) (*entity.User, error)
+}
+
+// Transferer executes a due occurrence.
+type Transferer interface {
+	CreateTransfer(ctx context.Context, fromUserID, toUserID int64, amount int64, message string) error
+}
+
+type DBTransactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
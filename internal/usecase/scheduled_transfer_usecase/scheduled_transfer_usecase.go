@@ -0,0 +1,219 @@
+package scheduled_transfer_usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"time"
+	"unicode/utf8"
+)
+
+const _defaultBatchSize = 100
+
+var errDuplicateRun = errors.New("scheduled transfer occurrence already executed")
+
+// ScheduledTransferUC stores transfers to be executed later, once or on a
+// recurrence rule, and executes them from a background worker.
+type ScheduledTransferUC struct {
+	repo       Repository
+	userRepo   UserRepository
+	transferer Transferer
+	dbTx       DBTransactor
+	batchSize  int
+}
+
+func NewScheduledTransferUC(
+	repo Repository,
+	userRepo UserRepository,
+	transferer Transferer,
+	dbTx DBTransactor,
+) *ScheduledTransferUC {
+	return &ScheduledTransferUC{
+		repo:       repo,
+		userRepo:   userRepo,
+		transferer: transferer,
+		dbTx:       dbTx,
+		batchSize:  _defaultBatchSize,
+	}
+}
+
+func (uc *ScheduledTransferUC) Schedule(ctx context.Context, fromUserID int64, req ScheduleRequest) (entity.ScheduledTransfer, error) {
+	if req.Amount <= 0 {
+		return entity.ScheduledTransfer{}, entity.ErrNegativeAmount
+	}
+	if req.ToUserID == fromUserID {
+		return entity.ScheduledTransfer{}, entity.ErrSelfTransfer
+	}
+	if utf8.RuneCountInString(req.Message) > entity.MaxTransferMessageLength {
+		return entity.ScheduledTransfer{}, entity.ErrMessageTooLong
+	}
+	if !req.Recurrence.IsValid() {
+		return entity.ScheduledTransfer{}, entity.ErrInvalidRecurrence
+	}
+	if !req.RunAt.After(time.Now()) {
+		return entity.ScheduledTransfer{}, entity.ErrScheduleInPast
+	}
+
+	toUser, err := uc.userRepo.GetByID(ctx, req.ToUserID)
+	if err != nil {
+		return entity.ScheduledTransfer{}, err
+	}
+	if toUser == nil {
+		return entity.ScheduledTransfer{}, entity.ErrUserNotFound
+	}
+
+	st := entity.ScheduledTransfer{
+		FromUserID: fromUserID,
+		ToUserID:   req.ToUserID,
+		Amount:     req.Amount,
+		Recurrence: req.Recurrence,
+		FirstRunAt: req.RunAt,
+		NextRunAt:  req.RunAt,
+		Status:     entity.ScheduledTransferStatusActive,
+	}
+	if req.Message != "" {
+		st.Message = &req.Message
+	}
+
+	if err := uc.repo.Create(ctx, &st); err != nil {
+		return entity.ScheduledTransfer{}, err
+	}
+
+	return st, nil
+}
+
+func (uc *ScheduledTransferUC) Cancel(ctx context.Context, userID, id int64) error {
+	return uc.dbTx.WithinTransaction(ctx, func(ctx context.Context) error {
+		st, err := uc.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if st.FromUserID != userID || st.Status != entity.ScheduledTransferStatusActive {
+			return entity.ErrScheduledTransferNotFound
+		}
+
+		st.Status = entity.ScheduledTransferStatusCancelled
+		return uc.repo.Update(ctx, st)
+	})
+}
+
+func (uc *ScheduledTransferUC) List(ctx context.Context, userID int64) ([]entity.ScheduledTransfer, error) {
+	return uc.repo.ListByUser(ctx, userID)
+}
+
+func (uc *ScheduledTransferUC) Runs(ctx context.Context, userID, id int64) ([]entity.ScheduledTransferRun, error) {
+	st, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if st.FromUserID != userID {
+		return nil, entity.ErrScheduledTransferNotFound
+	}
+
+	return uc.repo.ListRuns(ctx, id)
+}
+
+// RunDue executes every occurrence that is due at now and returns how many
+// were processed. Each occurrence runs in its own transaction together with
+// its run record and the schedule advance, so a crash leaves it either fully
+// done or due again on the next tick.
+func (uc *ScheduledTransferUC) RunDue(ctx context.Context, now time.Time) (int, error) {
+	ids, err := uc.repo.ListDue(ctx, now, uc.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	processed := 0
+	var errs []error
+
+	for _, id := range ids {
+		err := uc.dbTx.WithinTransaction(ctx, func(ctx context.Context) error {
+			ran, err := uc.runOne(ctx, id, now)
+			if ran {
+				processed++
+			}
+			return err
+		})
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return processed, errors.Join(errs...)
+}
+
+func (uc *ScheduledTransferUC) runOne(ctx context.Context, id int64, now time.Time) (bool, error) {
+	st, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	// Another worker may have handled it between listing and locking.
+	if st.Status != entity.ScheduledTransferStatusActive || st.NextRunAt.After(now) {
+		return false, nil
+	}
+
+	run := entity.ScheduledTransferRun{
+		ScheduledTransferID: st.ID,
+		RunAt:               st.NextRunAt,
+		Status:              entity.ScheduledTransferRunSucceeded,
+	}
+
+	refused, err := uc.execute(ctx, st)
+	if err != nil {
+		return false, err
+	}
+	if refused != nil {
+		reason := refused.Error()
+		run.Status = entity.ScheduledTransferRunFailed
+		run.FailureReason = &reason
+	}
+
+	created, err := uc.repo.CreateRun(ctx, &run)
+	if err != nil {
+		return false, err
+	}
+	if !created {
+		return false, errDuplicateRun
+	}
+
+	st.Advance(now)
+	if err := uc.repo.Update(ctx, st); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// execute checks the balance up front so that a skipped occurrence never
+// touches the ledger. An occurrence the rules refuse, such as one over the
+// sender's limits or blocked by the fraud checks, is returned as refused and
+// recorded as a failed run; err is only set for failures worth retrying.
+func (uc *ScheduledTransferUC) execute(ctx context.Context, st entity.ScheduledTransfer) (refused error, err error) {
+	sender, err := uc.userRepo.GetByID(ctx, st.FromUserID)
+	if errors.Is(err, entity.ErrUserNotFound) {
+		return err, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if sender == nil {
+		return entity.ErrUserNotFound, nil
+	}
+	if sender.Coins < st.Amount {
+		return entity.ErrInsufficientFunds, nil
+	}
+
+	var message string
+	if st.Message != nil {
+		message = *st.Message
+	}
+
+	// CreateTransfer reports every failure that is not about the transfer
+	// itself as ErrTransactionFailed.
+	err = uc.transferer.CreateTransfer(ctx, st.FromUserID, st.ToUserID, st.Amount, message)
+	if err == nil || errors.Is(err, entity.ErrTransactionFailed) {
+		return nil, err
+	}
+
+	return err, nil
+}
@@ -0,0 +1,375 @@
+package scheduled_transfer_usecase
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/usecase/scheduled_transfer_usecase/mocks"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type test struct {
+	name string
+	mock func()
+	res  interface{}
+	err  error
+}
+
+func TestSchedule(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	transferer := mocks.NewMockTransferer(ctrl)
+	dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+	uc := NewScheduledTransferUC(repo, userRepo, transferer, dbTransactor)
+	runAt := time.Now().Add(24 * time.Hour)
+
+	tests := []struct {
+		test
+		req ScheduleRequest
+	}{
+		{
+			test: test{
+				name: "success",
+				mock: func() {
+					userRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(&entity.User{ID: 2}, nil)
+					repo.EXPECT().
+						Create(gomock.Any(), gomock.Any()).
+						DoAndReturn(func(ctx context.Context, st *entity.ScheduledTransfer) error {
+							require.Equal(t, entity.ScheduledTransferStatusActive, st.Status)
+							require.Equal(t, runAt, st.NextRunAt)
+							require.Equal(t, runAt, st.FirstRunAt)
+							require.Equal(t, "happy birthday", *st.Message)
+							return nil
+						})
+				},
+			},
+			req: ScheduleRequest{ToUserID: 2, Amount: 10, Message: "happy birthday", RunAt: runAt, Recurrence: entity.RecurrenceMonthly},
+		},
+		{
+			test: test{name: "in the past", mock: func() {}, err: entity.ErrScheduleInPast},
+			req:  ScheduleRequest{ToUserID: 2, Amount: 10, RunAt: time.Now().Add(-time.Hour)},
+		},
+		{
+			test: test{name: "unknown recurrence", mock: func() {}, err: entity.ErrInvalidRecurrence},
+			req:  ScheduleRequest{ToUserID: 2, Amount: 10, RunAt: runAt, Recurrence: "hourly"},
+		},
+		{
+			test: test{name: "negative amount", mock: func() {}, err: entity.ErrNegativeAmount},
+			req:  ScheduleRequest{ToUserID: 2, Amount: -10, RunAt: runAt},
+		},
+		{
+			test: test{name: "to yourself", mock: func() {}, err: entity.ErrSelfTransfer},
+			req:  ScheduleRequest{ToUserID: 1, Amount: 10, RunAt: runAt, Recurrence: entity.RecurrenceMonthly},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			_, err := uc.Schedule(context.Background(), 1, tc.req)
+
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRunDue(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	runAt := now.Add(-time.Minute)
+
+	due := func(recurrence entity.Recurrence) entity.ScheduledTransfer {
+		return entity.ScheduledTransfer{
+			ID:         7,
+			FromUserID: 1,
+			ToUserID:   2,
+			Amount:     100,
+			Recurrence: recurrence,
+			FirstRunAt: runAt,
+			NextRunAt:  runAt,
+			Status:     entity.ScheduledTransferStatusActive,
+		}
+	}
+
+	t.Run("recurring transfer succeeds and advances", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockRepository(ctrl)
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		transferer := mocks.NewMockTransferer(ctrl)
+		dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+		uc := NewScheduledTransferUC(repo, userRepo, transferer, dbTransactor)
+
+		repo.EXPECT().ListDue(gomock.Any(), now, _defaultBatchSize).Return([]int64{7}, nil)
+		dbTransactor.EXPECT().
+			WithinTransaction(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+		repo.EXPECT().GetByID(gomock.Any(), int64(7)).Return(due(entity.RecurrenceWeekly), nil)
+		userRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&entity.User{ID: 1, Coins: 500}, nil)
+		transferer.EXPECT().CreateTransfer(gomock.Any(), int64(1), int64(2), int64(100), "").Return(nil)
+
+		repo.EXPECT().
+			CreateRun(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, run *entity.ScheduledTransferRun) (bool, error) {
+				require.Equal(t, entity.ScheduledTransferRunSucceeded, run.Status)
+				require.Equal(t, runAt, run.RunAt)
+				return true, nil
+			})
+
+		repo.EXPECT().
+			Update(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, st entity.ScheduledTransfer) error {
+				require.Equal(t, entity.ScheduledTransferStatusActive, st.Status)
+				require.Equal(t, runAt.AddDate(0, 0, 7), st.NextRunAt)
+				return nil
+			})
+
+		processed, err := uc.RunDue(context.Background(), now)
+		require.NoError(t, err)
+		require.Equal(t, 1, processed)
+	})
+
+	t.Run("insufficient funds is recorded and skipped", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockRepository(ctrl)
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		transferer := mocks.NewMockTransferer(ctrl)
+		dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+		uc := NewScheduledTransferUC(repo, userRepo, transferer, dbTransactor)
+
+		repo.EXPECT().ListDue(gomock.Any(), now, _defaultBatchSize).Return([]int64{7}, nil)
+		dbTransactor.EXPECT().
+			WithinTransaction(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+		repo.EXPECT().GetByID(gomock.Any(), int64(7)).Return(due(entity.RecurrenceNone), nil)
+		userRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&entity.User{ID: 1, Coins: 50}, nil)
+
+		repo.EXPECT().
+			CreateRun(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, run *entity.ScheduledTransferRun) (bool, error) {
+				require.Equal(t, entity.ScheduledTransferRunFailed, run.Status)
+				require.Equal(t, entity.ErrInsufficientFunds.Error(), *run.FailureReason)
+				return true, nil
+			})
+
+		repo.EXPECT().
+			Update(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, st entity.ScheduledTransfer) error {
+				require.Equal(t, entity.ScheduledTransferStatusCompleted, st.Status)
+				return nil
+			})
+
+		processed, err := uc.RunDue(context.Background(), now)
+		require.NoError(t, err)
+		require.Equal(t, 1, processed)
+	})
+
+	t.Run("refused transfer is recorded and the schedule moves on", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockRepository(ctrl)
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		transferer := mocks.NewMockTransferer(ctrl)
+		dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+		uc := NewScheduledTransferUC(repo, userRepo, transferer, dbTransactor)
+
+		repo.EXPECT().ListDue(gomock.Any(), now, _defaultBatchSize).Return([]int64{7}, nil)
+		dbTransactor.EXPECT().
+			WithinTransaction(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+		repo.EXPECT().GetByID(gomock.Any(), int64(7)).Return(due(entity.RecurrenceDaily), nil)
+		userRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&entity.User{ID: 1, Coins: 500}, nil)
+		transferer.EXPECT().
+			CreateTransfer(gomock.Any(), int64(1), int64(2), int64(100), "").
+			Return(entity.ErrOperationBlocked)
+
+		repo.EXPECT().
+			CreateRun(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, run *entity.ScheduledTransferRun) (bool, error) {
+				require.Equal(t, entity.ScheduledTransferRunFailed, run.Status)
+				require.Equal(t, entity.ErrOperationBlocked.Error(), *run.FailureReason)
+				return true, nil
+			})
+
+		repo.EXPECT().
+			Update(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, st entity.ScheduledTransfer) error {
+				require.True(t, st.NextRunAt.After(now))
+				return nil
+			})
+
+		processed, err := uc.RunDue(context.Background(), now)
+		require.NoError(t, err)
+		require.Equal(t, 1, processed)
+	})
+
+	t.Run("failed transaction is retried", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockRepository(ctrl)
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		transferer := mocks.NewMockTransferer(ctrl)
+		dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+		uc := NewScheduledTransferUC(repo, userRepo, transferer, dbTransactor)
+
+		repo.EXPECT().ListDue(gomock.Any(), now, _defaultBatchSize).Return([]int64{7}, nil)
+		dbTransactor.EXPECT().
+			WithinTransaction(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+		repo.EXPECT().GetByID(gomock.Any(), int64(7)).Return(due(entity.RecurrenceDaily), nil)
+		userRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&entity.User{ID: 1, Coins: 500}, nil)
+		transferer.EXPECT().
+			CreateTransfer(gomock.Any(), int64(1), int64(2), int64(100), "").
+			Return(entity.ErrTransactionFailed)
+
+		processed, err := uc.RunDue(context.Background(), now)
+		require.ErrorIs(t, err, entity.ErrTransactionFailed)
+		require.Zero(t, processed)
+	})
+
+	t.Run("already handled by another worker", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockRepository(ctrl)
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		transferer := mocks.NewMockTransferer(ctrl)
+		dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+		uc := NewScheduledTransferUC(repo, userRepo, transferer, dbTransactor)
+
+		handled := due(entity.RecurrenceWeekly)
+		handled.NextRunAt = now.Add(time.Hour)
+
+		repo.EXPECT().ListDue(gomock.Any(), now, _defaultBatchSize).Return([]int64{7}, nil)
+		dbTransactor.EXPECT().
+			WithinTransaction(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+		repo.EXPECT().GetByID(gomock.Any(), int64(7)).Return(handled, nil)
+
+		processed, err := uc.RunDue(context.Background(), now)
+		require.NoError(t, err)
+		require.Zero(t, processed)
+	})
+
+	t.Run("occurrence already recorded", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockRepository(ctrl)
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		transferer := mocks.NewMockTransferer(ctrl)
+		dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+		uc := NewScheduledTransferUC(repo, userRepo, transferer, dbTransactor)
+
+		repo.EXPECT().ListDue(gomock.Any(), now, _defaultBatchSize).Return([]int64{7}, nil)
+		dbTransactor.EXPECT().
+			WithinTransaction(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+		repo.EXPECT().GetByID(gomock.Any(), int64(7)).Return(due(entity.RecurrenceWeekly), nil)
+		userRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&entity.User{ID: 1, Coins: 500}, nil)
+		transferer.EXPECT().CreateTransfer(gomock.Any(), int64(1), int64(2), int64(100), "").Return(nil)
+		repo.EXPECT().CreateRun(gomock.Any(), gomock.Any()).Return(false, nil)
+
+		processed, err := uc.RunDue(context.Background(), now)
+		require.ErrorIs(t, err, errDuplicateRun)
+		require.Zero(t, processed)
+	})
+}
+
+func TestCancel(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockRepository(ctrl)
+	dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+	uc := NewScheduledTransferUC(repo, mocks.NewMockUserRepository(ctrl), mocks.NewMockTransferer(ctrl), dbTransactor)
+	st := entity.ScheduledTransfer{ID: 7, FromUserID: 1, Status: entity.ScheduledTransferStatusActive}
+
+	tests := []test{
+		{
+			name: "success",
+			mock: func() {
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				repo.EXPECT().GetByID(gomock.Any(), int64(7)).Return(st, nil)
+				repo.EXPECT().
+					Update(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, st entity.ScheduledTransfer) error {
+						require.Equal(t, entity.ScheduledTransferStatusCancelled, st.Status)
+						return nil
+					})
+			},
+			res: int64(1),
+		},
+		{
+			name: "someone else's transfer",
+			mock: func() {
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				repo.EXPECT().GetByID(gomock.Any(), int64(7)).Return(st, nil)
+			},
+			res: int64(2),
+			err: entity.ErrScheduledTransferNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			err := uc.Cancel(context.Background(), tc.res.(int64), 7)
+
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
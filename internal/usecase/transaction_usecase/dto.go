@@ -50,5 +50,22 @@ type TransactionInfo struct {
 	Amount    int64                  `json:"amount"`
 	Type      entity.TransactionType `json:"type"`
 	ItemName  *string                `json:"item_name,omitempty"`
+	Quantity  *int64                 `json:"quantity,omitempty"`
+	Message   *string                `json:"message,omitempty"`
 	CreatedAt time.Time              `json:"created_at"`
 }
+
+type BulkTransferRecipient struct {
+	Username string `json:"username" validate:"required"`
+	Amount   int64  `json:"amount" validate:"required,gt=0"`
+}
+
+type BulkTransferRequest struct {
+	Recipients []BulkTransferRecipient `json:"recipients" validate:"required,min=1,dive"`
+	Message    string                  `json:"message,omitempty"`
+}
+
+type BulkTransferResult struct {
+	Total        int64             `json:"total"`
+	Transactions []TransactionInfo `json:"transactions"`
+}
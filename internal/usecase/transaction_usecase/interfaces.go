@@ -3,20 +3,68 @@ package transaction_usecase
 import (
 	"context"
 	"github.com/smthjapanese/avito-merch/internal/entity"
+	"time"
 )
 
 //go:generate mockgen -source=interfaces.go -destination=./mocks/mocks.go -package=mocks
 
 type Repository interface {
-	Create(ctx context.Context, tr entity.Transaction) error
+	Create(ctx context.Context, tr *entity.Transaction) error
 	GetByUserID(ctx context.Context, userID int64) ([]entity.Transaction, error)
+	ListKudos(ctx context.Context, limit int) ([]entity.Kudos, error)
+	SumOutgoing(ctx context.Context, fromUserID int64, since time.Time) (int64, error)
+	SumOutgoingTo(ctx context.Context, fromUserID, toUserID int64, since time.Time) (int64, error)
+}
+
+type LimitRepository interface {
+	GetOverride(ctx context.Context, userID int64) (entity.TransferLimitOverride, error)
+	ListOverrides(ctx context.Context) ([]entity.TransferLimitOverride, error)
+	SaveOverride(ctx context.Context, override entity.TransferLimitOverride) error
+	DeleteOverride(ctx context.Context, userID int64) error
+}
+
+// MerchRepository resolves the item or bundle a transaction was about, for
+// the history.
+type MerchRepository interface {
+	GetByID(ctx context.Context, id int64) (entity.MerchItem, error)
+	GetBundleByID(ctx context.Context, id int64) (entity.Bundle, error)
+}
+
+// LotRepository tracks the lots a user's coins came in, so that the oldest
+// are spent first and expiring coins keep their expiry when passed on.
+type LotRepository interface {
+	Spend(ctx context.Context, userID int64, amount int64) ([]entity.CoinLot, error)
+	Credit(ctx context.Context, userID int64, portions []entity.CoinLot) error
 }
 
 type UserRepository interface {
 	GetByID(ctx context.Context, id int64) (*entity.User, error)
+	GetByUsername(ctx context.Context, username string) (*entity.User, error)
+	ListByRole(ctx context.Context, role entity.UserRole) ([]entity.User, error)
 	Update(ctx context.Context, user *entity.User) error
 }
 
+type PendingTransferRepository interface {
+	Create(ctx context.Context, t *entity.PendingTransfer) error
+	GetByID(ctx context.Context, id int64) (entity.PendingTransfer, error)
+	Resolve(ctx context.Context, id int64, status entity.PendingTransferStatus, decidedBy *int64) (bool, error)
+	ListPending(ctx context.Context) ([]entity.PendingTransfer, error)
+	ListByUser(ctx context.Context, fromUserID int64) ([]entity.PendingTransfer, error)
+	ListExpired(ctx context.Context, now time.Time, limit int) ([]int64, error)
+	SumPending(ctx context.Context, fromUserID int64) (int64, error)
+	SumPendingTo(ctx context.Context, fromUserID, toUserID int64) (int64, error)
+}
+
+// FraudChecker vets an operation before it is executed.
+type FraudChecker interface {
+	Check(ctx context.Context, op entity.FraudOperation) (entity.FraudAction, error)
+}
+
+// Notifier delivers a short message to a user.
+type Notifier interface {
+	Notify(ctx context.Context, userID int64, message string) error
+}
+
 type DBTransactor interface {
 	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
 }
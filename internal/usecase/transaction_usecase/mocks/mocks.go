@@ -7,6 +7,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	entity "github.com/smthjapanese/avito-merch/internal/entity"
@@ -36,7 +37,7 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockRepository) Create(ctx context.Context, tr entity.Transaction) error {
+func (m *MockRepository) Create(ctx context.Context, tr *entity.Transaction) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Create", ctx, tr)
 	ret0, _ := ret[0].(error)
@@ -64,6 +65,237 @@ func (mr *MockRepositoryMockRecorder) GetByUserID(ctx, userID interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserID", reflect.TypeOf((*MockRepository)(nil).GetByUserID), ctx, userID)
 }
 
+// ListKudos mocks base method.
+func (m *MockRepository) ListKudos(ctx context.Context, limit int) ([]entity.Kudos, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListKudos", ctx, limit)
+	ret0, _ := ret[0].([]entity.Kudos)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListKudos indicates an expected call of ListKudos.
+func (mr *MockRepositoryMockRecorder) ListKudos(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListKudos", reflect.TypeOf((*MockRepository)(nil).ListKudos), ctx, limit)
+}
+
+// SumOutgoing mocks base method.
+func (m *MockRepository) SumOutgoing(ctx context.Context, fromUserID int64, since time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumOutgoing", ctx, fromUserID, since)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumOutgoing indicates an expected call of SumOutgoing.
+func (mr *MockRepositoryMockRecorder) SumOutgoing(ctx, fromUserID, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumOutgoing", reflect.TypeOf((*MockRepository)(nil).SumOutgoing), ctx, fromUserID, since)
+}
+
+// SumOutgoingTo mocks base method.
+func (m *MockRepository) SumOutgoingTo(ctx context.Context, fromUserID, toUserID int64, since time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumOutgoingTo", ctx, fromUserID, toUserID, since)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumOutgoingTo indicates an expected call of SumOutgoingTo.
+func (mr *MockRepositoryMockRecorder) SumOutgoingTo(ctx, fromUserID, toUserID, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumOutgoingTo", reflect.TypeOf((*MockRepository)(nil).SumOutgoingTo), ctx, fromUserID, toUserID, since)
+}
+
+// MockLimitRepository is a mock of LimitRepository interface.
+type MockLimitRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLimitRepositoryMockRecorder
+}
+
+// MockLimitRepositoryMockRecorder is the mock recorder for MockLimitRepository.
+type MockLimitRepositoryMockRecorder struct {
+	mock *MockLimitRepository
+}
+
+// NewMockLimitRepository creates a new mock instance.
+func NewMockLimitRepository(ctrl *gomock.Controller) *MockLimitRepository {
+	mock := &MockLimitRepository{ctrl: ctrl}
+	mock.recorder = &MockLimitRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLimitRepository) EXPECT() *MockLimitRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteOverride mocks base method.
+func (m *MockLimitRepository) DeleteOverride(ctx context.Context, userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOverride", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOverride indicates an expected call of DeleteOverride.
+func (mr *MockLimitRepositoryMockRecorder) DeleteOverride(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOverride", reflect.TypeOf((*MockLimitRepository)(nil).DeleteOverride), ctx, userID)
+}
+
+// GetOverride mocks base method.
+func (m *MockLimitRepository) GetOverride(ctx context.Context, userID int64) (entity.TransferLimitOverride, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOverride", ctx, userID)
+	ret0, _ := ret[0].(entity.TransferLimitOverride)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOverride indicates an expected call of GetOverride.
+func (mr *MockLimitRepositoryMockRecorder) GetOverride(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOverride", reflect.TypeOf((*MockLimitRepository)(nil).GetOverride), ctx, userID)
+}
+
+// ListOverrides mocks base method.
+func (m *MockLimitRepository) ListOverrides(ctx context.Context) ([]entity.TransferLimitOverride, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOverrides", ctx)
+	ret0, _ := ret[0].([]entity.TransferLimitOverride)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOverrides indicates an expected call of ListOverrides.
+func (mr *MockLimitRepositoryMockRecorder) ListOverrides(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOverrides", reflect.TypeOf((*MockLimitRepository)(nil).ListOverrides), ctx)
+}
+
+// SaveOverride mocks base method.
+func (m *MockLimitRepository) SaveOverride(ctx context.Context, override entity.TransferLimitOverride) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveOverride", ctx, override)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveOverride indicates an expected call of SaveOverride.
+func (mr *MockLimitRepositoryMockRecorder) SaveOverride(ctx, override interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveOverride", reflect.TypeOf((*MockLimitRepository)(nil).SaveOverride), ctx, override)
+}
+
+// MockMerchRepository is a mock of MerchRepository interface.
+type MockMerchRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockMerchRepositoryMockRecorder
+}
+
+// MockMerchRepositoryMockRecorder is the mock recorder for MockMerchRepository.
+type MockMerchRepositoryMockRecorder struct {
+	mock *MockMerchRepository
+}
+
+// NewMockMerchRepository creates a new mock instance.
+func NewMockMerchRepository(ctrl *gomock.Controller) *MockMerchRepository {
+	mock := &MockMerchRepository{ctrl: ctrl}
+	mock.recorder = &MockMerchRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMerchRepository) EXPECT() *MockMerchRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetBundleByID mocks base method.
+func (m *MockMerchRepository) GetBundleByID(ctx context.Context, id int64) (entity.Bundle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBundleByID", ctx, id)
+	ret0, _ := ret[0].(entity.Bundle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBundleByID indicates an expected call of GetBundleByID.
+func (mr *MockMerchRepositoryMockRecorder) GetBundleByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBundleByID", reflect.TypeOf((*MockMerchRepository)(nil).GetBundleByID), ctx, id)
+}
+
+// GetByID mocks base method.
+func (m *MockMerchRepository) GetByID(ctx context.Context, id int64) (entity.MerchItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(entity.MerchItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockMerchRepositoryMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockMerchRepository)(nil).GetByID), ctx, id)
+}
+
+// MockLotRepository is a mock of LotRepository interface.
+type MockLotRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLotRepositoryMockRecorder
+}
+
+// MockLotRepositoryMockRecorder is the mock recorder for MockLotRepository.
+type MockLotRepositoryMockRecorder struct {
+	mock *MockLotRepository
+}
+
+// NewMockLotRepository creates a new mock instance.
+func NewMockLotRepository(ctrl *gomock.Controller) *MockLotRepository {
+	mock := &MockLotRepository{ctrl: ctrl}
+	mock.recorder = &MockLotRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLotRepository) EXPECT() *MockLotRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Credit mocks base method.
+func (m *MockLotRepository) Credit(ctx context.Context, userID int64, portions []entity.CoinLot) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Credit", ctx, userID, portions)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Credit indicates an expected call of Credit.
+func (mr *MockLotRepositoryMockRecorder) Credit(ctx, userID, portions interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Credit", reflect.TypeOf((*MockLotRepository)(nil).Credit), ctx, userID, portions)
+}
+
+// Spend mocks base method.
+func (m *MockLotRepository) Spend(ctx context.Context, userID, amount int64) ([]entity.CoinLot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Spend", ctx, userID, amount)
+	ret0, _ := ret[0].([]entity.CoinLot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Spend indicates an expected call of Spend.
+func (mr *MockLotRepositoryMockRecorder) Spend(ctx, userID, amount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Spend", reflect.TypeOf((*MockLotRepository)(nil).Spend), ctx, userID, amount)
+}
+
 // MockUserRepository is a mock of UserRepository interface.
 type MockUserRepository struct {
 	ctrl     *gomock.Controller
@@ -102,6 +334,36 @@ func (mr *MockUserRepositoryMockRecorder) GetByID(ctx, id interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockUserRepository)(nil).GetByID), ctx, id)
 }
 
+// GetByUsername mocks base method.
+func (m *MockUserRepository) GetByUsername(ctx context.Context, username string) (*entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUsername", ctx, username)
+	ret0, _ := ret[0].(*entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUsername indicates an expected call of GetByUsername.
+func (mr *MockUserRepositoryMockRecorder) GetByUsername(ctx, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUsername", reflect.TypeOf((*MockUserRepository)(nil).GetByUsername), ctx, username)
+}
+
+// ListByRole mocks base method.
+func (m *MockUserRepository) ListByRole(ctx context.Context, role entity.UserRole) ([]entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByRole", ctx, role)
+	ret0, _ := ret[0].([]entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByRole indicates an expected call of ListByRole.
+func (mr *MockUserRepositoryMockRecorder) ListByRole(ctx, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByRole", reflect.TypeOf((*MockUserRepository)(nil).ListByRole), ctx, role)
+}
+
 // Update mocks base method.
 func (m *MockUserRepository) Update(ctx context.Context, user *entity.User) error {
 	m.ctrl.T.Helper()
@@ -116,6 +378,223 @@ func (mr *MockUserRepositoryMockRecorder) Update(ctx, user interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUserRepository)(nil).Update), ctx, user)
 }
 
+// MockPendingTransferRepository is a mock of PendingTransferRepository interface.
+type MockPendingTransferRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPendingTransferRepositoryMockRecorder
+}
+
+// MockPendingTransferRepositoryMockRecorder is the mock recorder for MockPendingTransferRepository.
+type MockPendingTransferRepositoryMockRecorder struct {
+	mock *MockPendingTransferRepository
+}
+
+// NewMockPendingTransferRepository creates a new mock instance.
+func NewMockPendingTransferRepository(ctrl *gomock.Controller) *MockPendingTransferRepository {
+	mock := &MockPendingTransferRepository{ctrl: ctrl}
+	mock.recorder = &MockPendingTransferRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPendingTransferRepository) EXPECT() *MockPendingTransferRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockPendingTransferRepository) Create(ctx context.Context, t *entity.PendingTransfer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, t)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPendingTransferRepositoryMockRecorder) Create(ctx, t interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPendingTransferRepository)(nil).Create), ctx, t)
+}
+
+// GetByID mocks base method.
+func (m *MockPendingTransferRepository) GetByID(ctx context.Context, id int64) (entity.PendingTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(entity.PendingTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockPendingTransferRepositoryMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockPendingTransferRepository)(nil).GetByID), ctx, id)
+}
+
+// ListByUser mocks base method.
+func (m *MockPendingTransferRepository) ListByUser(ctx context.Context, fromUserID int64) ([]entity.PendingTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", ctx, fromUserID)
+	ret0, _ := ret[0].([]entity.PendingTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *MockPendingTransferRepositoryMockRecorder) ListByUser(ctx, fromUserID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockPendingTransferRepository)(nil).ListByUser), ctx, fromUserID)
+}
+
+// ListExpired mocks base method.
+func (m *MockPendingTransferRepository) ListExpired(ctx context.Context, now time.Time, limit int) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExpired", ctx, now, limit)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExpired indicates an expected call of ListExpired.
+func (mr *MockPendingTransferRepositoryMockRecorder) ListExpired(ctx, now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpired", reflect.TypeOf((*MockPendingTransferRepository)(nil).ListExpired), ctx, now, limit)
+}
+
+// ListPending mocks base method.
+func (m *MockPendingTransferRepository) ListPending(ctx context.Context) ([]entity.PendingTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPending", ctx)
+	ret0, _ := ret[0].([]entity.PendingTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPending indicates an expected call of ListPending.
+func (mr *MockPendingTransferRepositoryMockRecorder) ListPending(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPending", reflect.TypeOf((*MockPendingTransferRepository)(nil).ListPending), ctx)
+}
+
+// Resolve mocks base method.
+func (m *MockPendingTransferRepository) Resolve(ctx context.Context, id int64, status entity.PendingTransferStatus, decidedBy *int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resolve", ctx, id, status, decidedBy)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Resolve indicates an expected call of Resolve.
+func (mr *MockPendingTransferRepositoryMockRecorder) Resolve(ctx, id, status, decidedBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockPendingTransferRepository)(nil).Resolve), ctx, id, status, decidedBy)
+}
+
+// SumPending mocks base method.
+func (m *MockPendingTransferRepository) SumPending(ctx context.Context, fromUserID int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumPending", ctx, fromUserID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumPending indicates an expected call of SumPending.
+func (mr *MockPendingTransferRepositoryMockRecorder) SumPending(ctx, fromUserID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumPending", reflect.TypeOf((*MockPendingTransferRepository)(nil).SumPending), ctx, fromUserID)
+}
+
+// SumPendingTo mocks base method.
+func (m *MockPendingTransferRepository) SumPendingTo(ctx context.Context, fromUserID, toUserID int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumPendingTo", ctx, fromUserID, toUserID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumPendingTo indicates an expected call of SumPendingTo.
+func (mr *MockPendingTransferRepositoryMockRecorder) SumPendingTo(ctx, fromUserID, toUserID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumPendingTo", reflect.TypeOf((*MockPendingTransferRepository)(nil).SumPendingTo), ctx, fromUserID, toUserID)
+}
+
+// MockFraudChecker is a mock of FraudChecker interface.
+type MockFraudChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockFraudCheckerMockRecorder
+}
+
+// MockFraudCheckerMockRecorder is the mock recorder for MockFraudChecker.
+type MockFraudCheckerMockRecorder struct {
+	mock *MockFraudChecker
+}
+
+// NewMockFraudChecker creates a new mock instance.
+func NewMockFraudChecker(ctrl *gomock.Controller) *MockFraudChecker {
+	mock := &MockFraudChecker{ctrl: ctrl}
+	mock.recorder = &MockFraudCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFraudChecker) EXPECT() *MockFraudCheckerMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockFraudChecker) Check(ctx context.Context, op entity.FraudOperation) (entity.FraudAction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ctx, op)
+	ret0, _ := ret[0].(entity.FraudAction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockFraudCheckerMockRecorder) Check(ctx, op interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockFraudChecker)(nil).Check), ctx, op)
+}
+
+// MockNotifier is a mock of Notifier interface.
+type MockNotifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotifierMockRecorder
+}
+
+// MockNotifierMockRecorder is the mock recorder for MockNotifier.
+type MockNotifierMockRecorder struct {
+	mock *MockNotifier
+}
+
+// NewMockNotifier creates a new mock instance.
+func NewMockNotifier(ctrl *gomock.Controller) *MockNotifier {
+	mock := &MockNotifier{ctrl: ctrl}
+	mock.recorder = &MockNotifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotifier) EXPECT() *MockNotifierMockRecorder {
+	return m.recorder
+}
+
+// Notify mocks base method.
+func (m *MockNotifier) Notify(ctx context.Context, userID int64, message string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Notify", ctx, userID, message)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Notify indicates an expected call of Notify.
+func (mr *MockNotifierMockRecorder) Notify(ctx, userID, message interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notify", reflect.TypeOf((*MockNotifier)(nil).Notify), ctx, userID, message)
+}
+
 // MockDBTransactor is a mock of DBTransactor interface.
 type MockDBTransactor struct {
 	ctrl     *gomock.Controller
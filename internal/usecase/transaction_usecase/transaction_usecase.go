@@ -2,33 +2,107 @@ package transaction_usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/smthjapanese/avito-merch/internal/entity"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	_defaultKudosLimit = 50
+	_maxKudosLimit     = 100
+	_maxBulkRecipients = 100
 )
 
 type TransactionUC struct {
-	userRepo UserRepository
-	txRepo   Repository
-	dbTx     DBTransactor
+	userRepo    UserRepository
+	txRepo      Repository
+	merchRepo   MerchRepository
+	lotRepo     LotRepository
+	limitRepo   LimitRepository
+	pendingRepo PendingTransferRepository
+	dbTx        DBTransactor
+	notifier    Notifier
+	fraud       FraudChecker
+	limits      entity.TransferLimits
+	approvals   entity.ApprovalPolicy
 }
 
 func NewTransactionUC(
 	userRepo UserRepository,
 	txRepo Repository,
+	merchRepo MerchRepository,
+	lotRepo LotRepository,
+	limitRepo LimitRepository,
+	pendingRepo PendingTransferRepository,
 	dbTx DBTransactor,
+	notifier Notifier,
+	fraud FraudChecker,
+	limits entity.TransferLimits,
+	approvals entity.ApprovalPolicy,
 ) *TransactionUC {
 	return &TransactionUC{
-		userRepo: userRepo,
-		txRepo:   txRepo,
-		dbTx:     dbTx,
+		userRepo:    userRepo,
+		txRepo:      txRepo,
+		merchRepo:   merchRepo,
+		lotRepo:     lotRepo,
+		limitRepo:   limitRepo,
+		pendingRepo: pendingRepo,
+		dbTx:        dbTx,
+		notifier:    notifier,
+		fraud:       fraud,
+		limits:      limits,
+		approvals:   approvals,
 	}
 }
 
-func (uc *TransactionUC) CreateTransfer(ctx context.Context, fromUserID, toUserID int64, amount int64) error {
+// CreateTransfer moves coins between users. The message is optional; an
+// empty one is not stored. The sender's transfer limits are checked against
+// what they have already sent in the last 24 hours.
+//
+// Transfers above the approval threshold, or ones the fraud rules want held,
+// are not executed right away: the amount is held on the sender's account
+// and the transfer waits for an approver. Parking a transfer is not an error.
+func (uc *TransactionUC) CreateTransfer(ctx context.Context, fromUserID, toUserID int64, amount int64, message string) error {
+	return uc.transfer(ctx, fromUserID, toUserID, amount, message, true)
+}
+
+// CreateDirectTransfer is CreateTransfer for payments that cannot wait for
+// approval, such as accepting a coin request: a transfer that would be
+// parked fails with ErrApprovalRequired instead and nothing is held.
+func (uc *TransactionUC) CreateDirectTransfer(ctx context.Context, fromUserID, toUserID int64, amount int64, message string) error {
+	return uc.transfer(ctx, fromUserID, toUserID, amount, message, false)
+}
+
+func (uc *TransactionUC) transfer(ctx context.Context, fromUserID, toUserID int64, amount int64, message string, mayPark bool) error {
 	if amount <= 0 {
 		return entity.ErrNegativeAmount
 	}
+	if utf8.RuneCountInString(message) > entity.MaxTransferMessageLength {
+		return entity.ErrMessageTooLong
+	}
 
-	err := uc.dbTx.WithinTransaction(ctx, func(ctx context.Context) error {
+	action, err := uc.fraud.Check(ctx, entity.FraudOperation{
+		Type:       entity.TransactionTypeTransfer,
+		FromUserID: fromUserID,
+		ToUserID:   toUserID,
+		Amount:     amount,
+		At:         time.Now(),
+	})
+	if err != nil {
+		return entity.ErrTransactionFailed
+	}
+	if action == entity.FraudActionBlock {
+		return entity.ErrOperationBlocked
+	}
+
+	var (
+		sender *entity.User
+		parked *entity.PendingTransfer
+	)
+
+	err = uc.dbTx.WithinTransaction(ctx, func(ctx context.Context) error {
 		fromUser, err := uc.userRepo.GetByID(ctx, fromUserID)
 		if err != nil {
 			return err
@@ -49,6 +123,19 @@ func (uc *TransactionUC) CreateTransfer(ctx context.Context, fromUserID, toUserI
 			return entity.ErrInsufficientFunds
 		}
 
+		if err := uc.checkLimits(ctx, fromUserID, map[int64]int64{toUserID: amount}); err != nil {
+			return err
+		}
+
+		if uc.approvals.Requires(amount) || action == entity.FraudActionHold {
+			if !mayPark {
+				return entity.ErrApprovalRequired
+			}
+			sender = fromUser
+			parked, err = uc.park(ctx, fromUser, toUserID, amount, message)
+			return err
+		}
+
 		fromUser.Coins -= amount
 		toUser.Coins += amount
 
@@ -58,6 +145,9 @@ func (uc *TransactionUC) CreateTransfer(ctx context.Context, fromUserID, toUserI
 		if err := uc.userRepo.Update(ctx, toUser); err != nil {
 			return err
 		}
+		if err := uc.moveLots(ctx, fromUserID, toUserID, amount); err != nil {
+			return err
+		}
 
 		tx := entity.Transaction{
 			FromUserID: fromUserID,
@@ -65,8 +155,11 @@ func (uc *TransactionUC) CreateTransfer(ctx context.Context, fromUserID, toUserI
 			Amount:     amount,
 			Type:       entity.TransactionTypeTransfer,
 		}
+		if message != "" {
+			tx.Message = &message
+		}
 
-		if err := uc.txRepo.Create(ctx, tx); err != nil {
+		if err := uc.txRepo.Create(ctx, &tx); err != nil {
 			return err
 		}
 
@@ -74,17 +167,240 @@ func (uc *TransactionUC) CreateTransfer(ctx context.Context, fromUserID, toUserI
 	})
 
 	if err != nil {
-		switch err {
-		case entity.ErrInsufficientFunds, entity.ErrNegativeAmount:
+		switch {
+		case errors.Is(err, entity.ErrInsufficientFunds),
+			errors.Is(err, entity.ErrNegativeAmount),
+			errors.Is(err, entity.ErrTransferLimitExceeded),
+			errors.Is(err, entity.ErrApprovalRequired):
 			return err
 		default:
 			return entity.ErrTransactionFailed
 		}
 	}
 
+	if parked != nil {
+		uc.notifyApprovers(ctx, sender, parked)
+	}
+
+	return nil
+}
+
+// CheckTransfer runs a coin movement made elsewhere, such as a marketplace
+// sale, through the checks a direct transfer of the same amount would face:
+// the fraud rules, the sender's limits and the approval threshold. It cannot
+// wait for approval, so one that would need it is refused.
+func (uc *TransactionUC) CheckTransfer(ctx context.Context, op entity.FraudOperation) error {
+	action, err := uc.fraud.Check(ctx, op)
+	if err != nil {
+		return entity.ErrTransactionFailed
+	}
+	if action == entity.FraudActionBlock {
+		return entity.ErrOperationBlocked
+	}
+
+	if err := uc.checkLimits(ctx, op.FromUserID, map[int64]int64{op.ToUserID: op.Amount}); err != nil {
+		return err
+	}
+
+	if uc.approvals.Requires(op.Amount) || action == entity.FraudActionHold {
+		return entity.ErrApprovalRequired
+	}
+
 	return nil
 }
 
+// CreateBulkTransfer sends coins from one user to many recipients in a
+// single database transaction. The sender's balance is checked once against
+// the total, and either every transfer is recorded or none is.
+func (uc *TransactionUC) CreateBulkTransfer(ctx context.Context, fromUserID int64, req BulkTransferRequest) (*BulkTransferResult, error) {
+	if len(req.Recipients) == 0 {
+		return nil, entity.ErrNoRecipients
+	}
+	if len(req.Recipients) > _maxBulkRecipients {
+		return nil, entity.ErrTooManyRecipients
+	}
+	if utf8.RuneCountInString(req.Message) > entity.MaxTransferMessageLength {
+		return nil, entity.ErrMessageTooLong
+	}
+
+	var total int64
+	perRecipient := make(map[string]int64, len(req.Recipients))
+	for _, r := range req.Recipients {
+		if r.Amount <= 0 {
+			return nil, entity.ErrNegativeAmount
+		}
+		total += r.Amount
+		perRecipient[r.Username] += r.Amount
+	}
+
+	// Recipients are resolved and vetted up front so that fraud flags are
+	// kept even when the transfer itself does not go through. The same
+	// username may appear more than once; all its entries go to one user.
+	recipientIDs := make(map[string]int64, len(perRecipient))
+	for _, r := range req.Recipients {
+		if _, ok := recipientIDs[r.Username]; ok {
+			continue
+		}
+
+		toUser, err := uc.userRepo.GetByUsername(ctx, r.Username)
+		if err != nil {
+			if errors.Is(err, entity.ErrUserNotFound) {
+				return nil, fmt.Errorf("%w: %s", entity.ErrUserNotFound, r.Username)
+			}
+			return nil, entity.ErrTransactionFailed
+		}
+		if toUser == nil {
+			return nil, fmt.Errorf("%w: %s", entity.ErrUserNotFound, r.Username)
+		}
+		if toUser.ID == fromUserID {
+			return nil, entity.ErrSelfTransfer
+		}
+		recipientIDs[r.Username] = toUser.ID
+
+		amount := perRecipient[r.Username]
+		action, err := uc.fraud.Check(ctx, entity.FraudOperation{
+			Type:       entity.TransactionTypeTransfer,
+			FromUserID: fromUserID,
+			ToUserID:   toUser.ID,
+			Amount:     amount,
+			At:         time.Now(),
+		})
+		if err != nil {
+			return nil, entity.ErrTransactionFailed
+		}
+		if action == entity.FraudActionBlock {
+			return nil, entity.ErrOperationBlocked
+		}
+
+		// Bulk transfers are all-or-nothing, so one that would have to wait
+		// for approval cannot be part of them.
+		if uc.approvals.Requires(amount) || action == entity.FraudActionHold {
+			return nil, entity.ErrApprovalRequired
+		}
+	}
+
+	result := &BulkTransferResult{
+		Total:        total,
+		Transactions: make([]TransactionInfo, 0, len(req.Recipients)),
+	}
+
+	err := uc.dbTx.WithinTransaction(ctx, func(ctx context.Context) error {
+		fromUser, err := uc.userRepo.GetByID(ctx, fromUserID)
+		if err != nil {
+			return err
+		}
+		if fromUser == nil {
+			return entity.ErrUserNotFound
+		}
+
+		// Reload recipients inside the transaction so their balances are
+		// current; each is written only once.
+		recipients := make(map[string]*entity.User, len(recipientIDs))
+		for _, r := range req.Recipients {
+			if _, ok := recipients[r.Username]; ok {
+				continue
+			}
+
+			toUser, err := uc.userRepo.GetByID(ctx, recipientIDs[r.Username])
+			if err != nil {
+				return err
+			}
+			if toUser == nil {
+				return fmt.Errorf("%w: %s", entity.ErrUserNotFound, r.Username)
+			}
+
+			recipients[r.Username] = toUser
+		}
+
+		if fromUser.Coins < total {
+			return entity.ErrInsufficientFunds
+		}
+
+		amounts := make(map[int64]int64, len(recipients))
+		for _, r := range req.Recipients {
+			amounts[recipients[r.Username].ID] += r.Amount
+		}
+		if err := uc.checkLimits(ctx, fromUser.ID, amounts); err != nil {
+			return err
+		}
+
+		fromUser.Coins -= total
+		if err := uc.userRepo.Update(ctx, fromUser); err != nil {
+			return err
+		}
+
+		for _, r := range req.Recipients {
+			recipients[r.Username].Coins += r.Amount
+		}
+
+		updated := make(map[int64]bool, len(recipients))
+		for _, r := range req.Recipients {
+			toUser := recipients[r.Username]
+			if updated[toUser.ID] {
+				continue
+			}
+			if err := uc.userRepo.Update(ctx, toUser); err != nil {
+				return err
+			}
+			updated[toUser.ID] = true
+		}
+
+		for _, r := range req.Recipients {
+			if err := uc.moveLots(ctx, fromUser.ID, recipients[r.Username].ID, r.Amount); err != nil {
+				return err
+			}
+
+			tx := entity.Transaction{
+				FromUserID: fromUser.ID,
+				ToUserID:   recipients[r.Username].ID,
+				Amount:     r.Amount,
+				Type:       entity.TransactionTypeTransfer,
+			}
+			if req.Message != "" {
+				tx.Message = &req.Message
+			}
+
+			if err := uc.txRepo.Create(ctx, &tx); err != nil {
+				return err
+			}
+
+			result.Transactions = append(result.Transactions, TransactionInfo{
+				ID:        tx.ID,
+				User:      r.Username,
+				Amount:    tx.Amount,
+				Type:      tx.Type,
+				Message:   tx.Message,
+				CreatedAt: tx.CreatedAt,
+			})
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		switch {
+		case errors.Is(err, entity.ErrInsufficientFunds),
+			errors.Is(err, entity.ErrUserNotFound),
+			errors.Is(err, entity.ErrTransferLimitExceeded):
+			return nil, err
+		default:
+			return nil, entity.ErrTransactionFailed
+		}
+	}
+
+	return result, nil
+}
+
+// moveLots passes amount coins from the sender's oldest lots on to the
+// recipient, keeping their grant and expiry dates.
+func (uc *TransactionUC) moveLots(ctx context.Context, fromUserID, toUserID, amount int64) error {
+	portions, err := uc.lotRepo.Spend(ctx, fromUserID, amount)
+	if err != nil {
+		return err
+	}
+	return uc.lotRepo.Credit(ctx, toUserID, portions)
+}
+
 func (uc *TransactionUC) GetUserHistory(ctx context.Context, userID int64) (*TransactionHistory, error) {
 	user, err := uc.userRepo.GetByID(ctx, userID)
 	if err != nil {
@@ -103,36 +419,46 @@ func (uc *TransactionUC) GetUserHistory(ctx context.Context, userID int64) (*Tra
 	sent := make([]TransactionInfo, 0)
 
 	for _, tx := range transactions {
-		var info TransactionInfo
-		var otherUserName string
-
+		otherUserID := tx.ToUserID
 		if tx.ToUserID == userID {
-			fromUser, err := uc.userRepo.GetByID(ctx, tx.FromUserID)
-			if err != nil {
-				continue
-			}
-			otherUserName = fromUser.Username
-			info = TransactionInfo{
-				ID:        tx.ID,
-				User:      otherUserName,
-				Amount:    tx.Amount,
-				Type:      tx.Type,
-				CreatedAt: tx.CreatedAt,
+			otherUserID = tx.FromUserID
+		}
+		otherUser, err := uc.userRepo.GetByID(ctx, otherUserID)
+		if err != nil {
+			continue
+		}
+
+		info := TransactionInfo{
+			ID:        tx.ID,
+			User:      otherUser.Username,
+			Amount:    tx.Amount,
+			Type:      tx.Type,
+			Quantity:  tx.Quantity,
+			Message:   tx.Message,
+			CreatedAt: tx.CreatedAt,
+		}
+
+		switch tx.Type {
+		case entity.TransactionTypePurchase, entity.TransactionTypeItemTransfer,
+			entity.TransactionTypeMarketSale, entity.TransactionTypeMarketFee,
+			entity.TransactionTypeRaffleTicket:
+			if tx.ItemID != nil {
+				merchItem, err := uc.merchRepo.GetByID(ctx, *tx.ItemID)
+				if err == nil {
+					itemName := merchItem.Name
+					info.ItemName = &itemName
+				}
+			} else if tx.BundleID != nil {
+				bundle, err := uc.merchRepo.GetBundleByID(ctx, *tx.BundleID)
+				if err == nil {
+					info.ItemName = &bundle.Name
+				}
 			}
+		}
+
+		if tx.ToUserID == userID {
 			received = append(received, info)
 		} else {
-			toUser, err := uc.userRepo.GetByID(ctx, tx.ToUserID)
-			if err != nil {
-				continue
-			}
-			otherUserName = toUser.Username
-			info = TransactionInfo{
-				ID:        tx.ID,
-				User:      otherUserName,
-				Amount:    tx.Amount,
-				Type:      tx.Type,
-				CreatedAt: tx.CreatedAt,
-			}
 			sent = append(sent, info)
 		}
 	}
@@ -142,3 +468,15 @@ func (uc *TransactionUC) GetUserHistory(ctx context.Context, userID int64) (*Tra
 		Sent:     sent,
 	}, nil
 }
+
+// KudosFeed lists recent transfers with messages for the public feed.
+func (uc *TransactionUC) KudosFeed(ctx context.Context, limit int) ([]entity.Kudos, error) {
+	if limit <= 0 {
+		limit = _defaultKudosLimit
+	}
+	if limit > _maxKudosLimit {
+		limit = _maxKudosLimit
+	}
+
+	return uc.txRepo.ListKudos(ctx, limit)
+}
@@ -2,22 +2,54 @@ package transaction_usecase
 
 import (
 	"context"
+	"errors"
 	"github.com/golang/mock/gomock"
 	"github.com/smthjapanese/avito-merch/internal/entity"
 	"github.com/smthjapanese/avito-merch/internal/usecase/transaction_usecase/mocks"
 	"github.com/stretchr/testify/require"
+	"strings"
 	"testing"
 	"time"
 )
 
 type test struct {
-	name   string
-	mock   func()
-	fromID int64
-	toID   int64
-	amount int64
-	res    interface{}
-	err    error
+	name    string
+	mock    func()
+	fromID  int64
+	toID    int64
+	amount  int64
+	message string
+	res     interface{}
+	err     error
+}
+
+// noLimitOverrides returns a limit repository where no user has an override.
+func noLimitOverrides(ctrl *gomock.Controller) *mocks.MockLimitRepository {
+	limitRepo := mocks.NewMockLimitRepository(ctrl)
+	limitRepo.EXPECT().
+		GetOverride(gomock.Any(), gomock.Any()).
+		Return(entity.TransferLimitOverride{}, entity.ErrTransferLimitNotFound).
+		AnyTimes()
+	return limitRepo
+}
+
+// noLots returns a lot repository that moves coins between lots without
+// checking them.
+func noLots(ctrl *gomock.Controller) *mocks.MockLotRepository {
+	lotRepo := mocks.NewMockLotRepository(ctrl)
+	lotRepo.EXPECT().Spend(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	lotRepo.EXPECT().Credit(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	return lotRepo
+}
+
+// noFraud returns a fraud checker that lets every operation through.
+func noFraud(ctrl *gomock.Controller) *mocks.MockFraudChecker {
+	fraud := mocks.NewMockFraudChecker(ctrl)
+	fraud.EXPECT().
+		Check(gomock.Any(), gomock.Any()).
+		Return(entity.FraudActionNone, nil).
+		AnyTimes()
+	return fraud
 }
 
 func TestCreateTransfer(t *testing.T) {
@@ -30,7 +62,19 @@ func TestCreateTransfer(t *testing.T) {
 	txRepo := mocks.NewMockRepository(ctrl)
 	dbTx := mocks.NewMockDBTransactor(ctrl)
 
-	uc := NewTransactionUC(userRepo, txRepo, dbTx)
+	uc := NewTransactionUC(
+		userRepo,
+		txRepo,
+		mocks.NewMockMerchRepository(ctrl),
+		noLots(ctrl),
+		noLimitOverrides(ctrl),
+		mocks.NewMockPendingTransferRepository(ctrl),
+		dbTx,
+		mocks.NewMockNotifier(ctrl),
+		noFraud(ctrl),
+		entity.TransferLimits{},
+		entity.ApprovalPolicy{},
+	)
 
 	fromUser := &entity.User{
 		ID:       1,
@@ -81,7 +125,7 @@ func TestCreateTransfer(t *testing.T) {
 
 				txRepo.EXPECT().
 					Create(gomock.Any(), gomock.Any()).
-					DoAndReturn(func(ctx context.Context, tx entity.Transaction) error {
+					DoAndReturn(func(ctx context.Context, tx *entity.Transaction) error {
 						require.Equal(t, int64(1), tx.FromUserID)
 						require.Equal(t, int64(2), tx.ToUserID)
 						require.Equal(t, int64(500), tx.Amount)
@@ -134,6 +178,16 @@ func TestCreateTransfer(t *testing.T) {
 			res: nil,
 			err: entity.ErrTransactionFailed,
 		},
+		{
+			name:    "message too long",
+			fromID:  1,
+			toID:    2,
+			amount:  10,
+			message: strings.Repeat("я", entity.MaxTransferMessageLength+1),
+			mock:    func() {},
+			res:     nil,
+			err:     entity.ErrMessageTooLong,
+		},
 		{
 			name:   "negative amount",
 			fromID: 1,
@@ -153,7 +207,7 @@ func TestCreateTransfer(t *testing.T) {
 
 			tc.mock()
 
-			err := uc.CreateTransfer(context.Background(), tc.fromID, tc.toID, tc.amount)
+			err := uc.CreateTransfer(context.Background(), tc.fromID, tc.toID, tc.amount, tc.message)
 
 			if tc.err != nil {
 				require.ErrorIs(t, err, tc.err)
@@ -172,9 +226,22 @@ func TestGetUserHistory(t *testing.T) {
 
 	userRepo := mocks.NewMockUserRepository(ctrl)
 	txRepo := mocks.NewMockRepository(ctrl)
+	merchRepo := mocks.NewMockMerchRepository(ctrl)
 	dbTx := mocks.NewMockDBTransactor(ctrl)
 
-	uc := NewTransactionUC(userRepo, txRepo, dbTx)
+	uc := NewTransactionUC(
+		userRepo,
+		txRepo,
+		merchRepo,
+		noLots(ctrl),
+		noLimitOverrides(ctrl),
+		mocks.NewMockPendingTransferRepository(ctrl),
+		dbTx,
+		mocks.NewMockNotifier(ctrl),
+		noFraud(ctrl),
+		entity.TransferLimits{},
+		entity.ApprovalPolicy{},
+	)
 	testTime := time.Date(2024, 2, 15, 12, 0, 0, 0, time.UTC)
 	userID := int64(1)
 
@@ -194,6 +261,10 @@ func TestGetUserHistory(t *testing.T) {
 		Username: "receiver",
 	}
 
+	cupID := int64(4)
+	one := int64(1)
+	cupName := "cup"
+
 	testTransactions := []entity.Transaction{
 		{
 			ID:         1,
@@ -211,6 +282,16 @@ func TestGetUserHistory(t *testing.T) {
 			Type:       entity.TransactionTypeTransfer,
 			CreatedAt:  testTime,
 		},
+		{
+			ID:         3,
+			FromUserID: 3,
+			ToUserID:   userID,
+			Amount:     90,
+			Type:       entity.TransactionTypeMarketSale,
+			ItemID:     &cupID,
+			Quantity:   &one,
+			CreatedAt:  testTime,
+		},
 	}
 
 	tests := []test{
@@ -231,7 +312,12 @@ func TestGetUserHistory(t *testing.T) {
 
 				userRepo.EXPECT().
 					GetByID(gomock.Any(), int64(3)). // receiver
-					Return(receiverUser, nil)
+					Return(receiverUser, nil).
+					Times(2)
+
+				merchRepo.EXPECT().
+					GetByID(gomock.Any(), cupID).
+					Return(entity.MerchItem{ID: cupID, Name: cupName, Price: 20}, nil)
 			},
 			res: &TransactionHistory{
 				Received: []TransactionInfo{
@@ -242,6 +328,15 @@ func TestGetUserHistory(t *testing.T) {
 						Type:      entity.TransactionTypeTransfer,
 						CreatedAt: testTime,
 					},
+					{
+						ID:        3,
+						User:      "receiver",
+						Amount:    90,
+						Type:      entity.TransactionTypeMarketSale,
+						ItemName:  &cupName,
+						Quantity:  &one,
+						CreatedAt: testTime,
+					},
 				},
 				Sent: []TransactionInfo{
 					{
@@ -300,3 +395,458 @@ func TestGetUserHistory(t *testing.T) {
 		})
 	}
 }
+
+func TestKudosFeed(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	txRepo := mocks.NewMockRepository(ctrl)
+	uc := NewTransactionUC(
+		mocks.NewMockUserRepository(ctrl),
+		txRepo,
+		mocks.NewMockMerchRepository(ctrl),
+		noLots(ctrl),
+		mocks.NewMockLimitRepository(ctrl),
+		mocks.NewMockPendingTransferRepository(ctrl),
+		mocks.NewMockDBTransactor(ctrl),
+		mocks.NewMockNotifier(ctrl),
+		noFraud(ctrl),
+		entity.TransferLimits{},
+		entity.ApprovalPolicy{},
+	)
+
+	kudos := []entity.Kudos{
+		{ID: 1, FromUsername: "sender", ToUsername: "receiver", Amount: 10, Message: "thanks"},
+	}
+
+	tests := []struct {
+		name  string
+		limit int
+		want  int
+	}{
+		{name: "default limit", limit: 0, want: _defaultKudosLimit},
+		{name: "custom limit", limit: 10, want: 10},
+		{name: "limit is capped", limit: 1000, want: _maxKudosLimit},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			txRepo.EXPECT().
+				ListKudos(gomock.Any(), tc.want).
+				Return(kudos, nil)
+
+			feed, err := uc.KudosFeed(context.Background(), tc.limit)
+			require.NoError(t, err)
+			require.Equal(t, kudos, feed)
+		})
+	}
+}
+
+func TestCreateTransferWithMessage(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	txRepo := mocks.NewMockRepository(ctrl)
+	dbTx := mocks.NewMockDBTransactor(ctrl)
+
+	uc := NewTransactionUC(
+		userRepo,
+		txRepo,
+		mocks.NewMockMerchRepository(ctrl),
+		noLots(ctrl),
+		noLimitOverrides(ctrl),
+		mocks.NewMockPendingTransferRepository(ctrl),
+		dbTx,
+		mocks.NewMockNotifier(ctrl),
+		noFraud(ctrl),
+		entity.TransferLimits{},
+		entity.ApprovalPolicy{},
+	)
+
+	dbTx.EXPECT().
+		WithinTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		})
+
+	userRepo.EXPECT().
+		GetByID(gomock.Any(), int64(1)).
+		Return(&entity.User{ID: 1, Username: "sender", Coins: 100}, nil)
+
+	userRepo.EXPECT().
+		GetByID(gomock.Any(), int64(2)).
+		Return(&entity.User{ID: 2, Username: "receiver", Coins: 100}, nil)
+
+	userRepo.EXPECT().
+		Update(gomock.Any(), gomock.Any()).
+		Return(nil).
+		Times(2)
+
+	txRepo.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, tx *entity.Transaction) error {
+			require.NotNil(t, tx.Message)
+			require.Equal(t, "thanks for the help", *tx.Message)
+			return nil
+		})
+
+	err := uc.CreateTransfer(context.Background(), 1, 2, 10, "thanks for the help")
+	require.NoError(t, err)
+}
+
+func TestCreateBulkTransfer(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	txRepo := mocks.NewMockRepository(ctrl)
+	dbTx := mocks.NewMockDBTransactor(ctrl)
+
+	uc := NewTransactionUC(
+		userRepo,
+		txRepo,
+		mocks.NewMockMerchRepository(ctrl),
+		noLots(ctrl),
+		noLimitOverrides(ctrl),
+		mocks.NewMockPendingTransferRepository(ctrl),
+		dbTx,
+		mocks.NewMockNotifier(ctrl),
+		noFraud(ctrl),
+		entity.TransferLimits{},
+		entity.ApprovalPolicy{},
+	)
+
+	expectTransaction := func() {
+		dbTx.EXPECT().
+			WithinTransaction(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+	}
+	expectSender := func(coins int64) {
+		userRepo.EXPECT().
+			GetByID(gomock.Any(), int64(1)).
+			Return(&entity.User{ID: 1, Username: "sender", Coins: coins}, nil)
+	}
+	expectRecipient := func(id int64, username string) {
+		userRepo.EXPECT().
+			GetByUsername(gomock.Any(), username).
+			Return(&entity.User{ID: id, Username: username, Coins: 100}, nil)
+	}
+	expectReload := func(id int64, username string) {
+		userRepo.EXPECT().
+			GetByID(gomock.Any(), id).
+			Return(&entity.User{ID: id, Username: username, Coins: 100}, nil)
+	}
+
+	tests := []struct {
+		test
+		req BulkTransferRequest
+	}{
+		{
+			test: test{
+				name: "success",
+				mock: func() {
+					expectRecipient(2, "alice")
+					expectRecipient(3, "bob")
+					expectTransaction()
+					expectSender(1000)
+					expectReload(2, "alice")
+					expectReload(3, "bob")
+
+					userRepo.EXPECT().
+						Update(gomock.Any(), gomock.Any()).
+						DoAndReturn(func(ctx context.Context, user *entity.User) error {
+							require.Equal(t, int64(1), user.ID)
+							require.Equal(t, int64(600), user.Coins)
+							return nil
+						})
+					userRepo.EXPECT().
+						Update(gomock.Any(), gomock.Any()).
+						DoAndReturn(func(ctx context.Context, user *entity.User) error {
+							// alice appears twice in the request
+							require.Equal(t, int64(2), user.ID)
+							require.Equal(t, int64(400), user.Coins)
+							return nil
+						})
+					userRepo.EXPECT().
+						Update(gomock.Any(), gomock.Any()).
+						DoAndReturn(func(ctx context.Context, user *entity.User) error {
+							require.Equal(t, int64(3), user.ID)
+							require.Equal(t, int64(200), user.Coins)
+							return nil
+						})
+
+					id := int64(0)
+					txRepo.EXPECT().
+						Create(gomock.Any(), gomock.Any()).
+						DoAndReturn(func(ctx context.Context, tx *entity.Transaction) error {
+							require.Equal(t, entity.TransactionTypeTransfer, tx.Type)
+							require.Equal(t, "team prize", *tx.Message)
+							id++
+							tx.ID = id
+							return nil
+						}).
+						Times(3)
+				},
+				res: []int64{1, 2, 3},
+			},
+			req: BulkTransferRequest{
+				Recipients: []BulkTransferRecipient{
+					{Username: "alice", Amount: 200},
+					{Username: "bob", Amount: 100},
+					{Username: "alice", Amount: 100},
+				},
+				Message: "team prize",
+			},
+		},
+		{
+			test: test{
+				name: "insufficient funds",
+				mock: func() {
+					expectRecipient(2, "alice")
+					expectRecipient(3, "bob")
+					expectTransaction()
+					expectSender(250)
+					expectReload(2, "alice")
+					expectReload(3, "bob")
+				},
+				err: entity.ErrInsufficientFunds,
+			},
+			req: BulkTransferRequest{
+				Recipients: []BulkTransferRecipient{
+					{Username: "alice", Amount: 200},
+					{Username: "bob", Amount: 100},
+				},
+			},
+		},
+		{
+			test: test{
+				name: "unknown recipient",
+				mock: func() {
+					expectRecipient(2, "alice")
+					userRepo.EXPECT().
+						GetByUsername(gomock.Any(), "ghost").
+						Return(nil, entity.ErrUserNotFound)
+				},
+				err: entity.ErrUserNotFound,
+			},
+			req: BulkTransferRequest{
+				Recipients: []BulkTransferRecipient{
+					{Username: "alice", Amount: 200},
+					{Username: "ghost", Amount: 100},
+				},
+			},
+		},
+		{
+			test: test{
+				name: "sender among recipients",
+				mock: func() {
+					expectRecipient(1, "sender")
+				},
+				err: entity.ErrSelfTransfer,
+			},
+			req: BulkTransferRequest{
+				Recipients: []BulkTransferRecipient{{Username: "sender", Amount: 10}},
+			},
+		},
+		{
+			test: test{name: "no recipients", mock: func() {}, err: entity.ErrNoRecipients},
+			req:  BulkTransferRequest{},
+		},
+		{
+			test: test{name: "zero amount", mock: func() {}, err: entity.ErrNegativeAmount},
+			req: BulkTransferRequest{
+				Recipients: []BulkTransferRecipient{{Username: "alice", Amount: 0}},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			result, err := uc.CreateBulkTransfer(context.Background(), 1, tc.req)
+
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				require.Nil(t, result)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, int64(400), result.Total)
+
+			ids := make([]int64, 0, len(result.Transactions))
+			for _, info := range result.Transactions {
+				ids = append(ids, info.ID)
+			}
+			require.Equal(t, tc.res, ids)
+		})
+	}
+}
+
+func TestCreateTransferFraudActions(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	pendingRepo := mocks.NewMockPendingTransferRepository(ctrl)
+	dbTx := mocks.NewMockDBTransactor(ctrl)
+	fraud := mocks.NewMockFraudChecker(ctrl)
+
+	uc := NewTransactionUC(
+		userRepo,
+		mocks.NewMockRepository(ctrl),
+		mocks.NewMockMerchRepository(ctrl),
+		noLots(ctrl),
+		noLimitOverrides(ctrl),
+		pendingRepo,
+		dbTx,
+		mocks.NewMockNotifier(ctrl),
+		fraud,
+		entity.TransferLimits{},
+		entity.ApprovalPolicy{TTL: time.Hour},
+	)
+
+	expectCheck := func(action entity.FraudAction, err error) {
+		fraud.EXPECT().
+			Check(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, op entity.FraudOperation) (entity.FraudAction, error) {
+				require.Equal(t, entity.TransactionTypeTransfer, op.Type)
+				require.Equal(t, int64(1), op.FromUserID)
+				require.Equal(t, int64(2), op.ToUserID)
+				require.Equal(t, int64(100), op.Amount)
+				return action, err
+			})
+	}
+
+	tests := []test{
+		{
+			name: "blocked",
+			mock: func() {
+				expectCheck(entity.FraudActionBlock, nil)
+			},
+			err: entity.ErrOperationBlocked,
+		},
+		{
+			name: "held for review",
+			mock: func() {
+				expectCheck(entity.FraudActionHold, nil)
+
+				dbTx.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+
+				userRepo.EXPECT().
+					GetByID(gomock.Any(), int64(1)).
+					Return(&entity.User{ID: 1, Username: "sender", Coins: 1000}, nil)
+				userRepo.EXPECT().
+					GetByID(gomock.Any(), int64(2)).
+					Return(&entity.User{ID: 2, Username: "receiver"}, nil)
+
+				userRepo.EXPECT().
+					Update(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, user *entity.User) error {
+						require.Equal(t, int64(1), user.ID)
+						require.Equal(t, int64(100), user.HeldCoins)
+						return nil
+					})
+				pendingRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				userRepo.EXPECT().ListByRole(gomock.Any(), entity.UserRoleAdmin).Return(nil, nil)
+			},
+		},
+		{
+			name: "checker unavailable",
+			mock: func() {
+				expectCheck(entity.FraudActionNone, errors.New("db is down"))
+			},
+			err: entity.ErrTransactionFailed,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			err := uc.CreateTransfer(context.Background(), 1, 2, 100, "")
+
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("held bulk transfer is refused", func(t *testing.T) {
+		userRepo.EXPECT().
+			GetByUsername(gomock.Any(), "receiver").
+			Return(&entity.User{ID: 2, Username: "receiver"}, nil)
+		expectCheck(entity.FraudActionHold, nil)
+
+		_, err := uc.CreateBulkTransfer(context.Background(), 1, BulkTransferRequest{
+			Recipients: []BulkTransferRecipient{{Username: "receiver", Amount: 100}},
+		})
+		require.ErrorIs(t, err, entity.ErrApprovalRequired)
+	})
+}
+
+func TestCreateTransferMovesLots(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	txRepo := mocks.NewMockRepository(ctrl)
+	lotRepo := mocks.NewMockLotRepository(ctrl)
+	dbTx := mocks.NewMockDBTransactor(ctrl)
+
+	uc := NewTransactionUC(
+		userRepo,
+		txRepo,
+		mocks.NewMockMerchRepository(ctrl),
+		lotRepo,
+		noLimitOverrides(ctrl),
+		mocks.NewMockPendingTransferRepository(ctrl),
+		dbTx,
+		mocks.NewMockNotifier(ctrl),
+		noFraud(ctrl),
+		entity.TransferLimits{},
+		entity.ApprovalPolicy{},
+	)
+
+	dbTx.EXPECT().
+		WithinTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		})
+
+	userRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&entity.User{ID: 1, Coins: 1000}, nil)
+	userRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(&entity.User{ID: 2}, nil)
+	userRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	txRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+	expiresAt := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+	portions := []entity.CoinLot{
+		{ID: 5, UserID: 1, Amount: 200, Remaining: 200, ExpiresAt: &expiresAt},
+		{ID: 6, UserID: 1, Amount: 100, Remaining: 100},
+	}
+
+	lotRepo.EXPECT().Spend(gomock.Any(), int64(1), int64(300)).Return(portions, nil)
+	lotRepo.EXPECT().Credit(gomock.Any(), int64(2), portions).Return(nil)
+
+	require.NoError(t, uc.CreateTransfer(context.Background(), 1, 2, 300, ""))
+}
@@ -0,0 +1,301 @@
+package transaction_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"time"
+)
+
+const _expireBatchSize = 100
+
+// ApproveTransfer executes a parked transfer on behalf of an approver: the
+// held coins leave the sender's account and are credited to the recipient.
+// The sender's limits are checked again first, since they may have sent
+// more while the transfer waited; a transfer over them stays pending.
+func (uc *TransactionUC) ApproveTransfer(ctx context.Context, approverID, id int64) error {
+	var decided entity.PendingTransfer
+
+	err := uc.dbTx.WithinTransaction(ctx, func(ctx context.Context) error {
+		t, fromUser, err := uc.decide(ctx, approverID, id, entity.PendingTransferStatusApproved)
+		if err != nil {
+			return err
+		}
+
+		toUser, err := uc.userRepo.GetByID(ctx, t.ToUserID)
+		if err != nil {
+			return err
+		}
+		if toUser == nil {
+			return entity.ErrUserNotFound
+		}
+
+		if err := uc.checkLimits(ctx, t.FromUserID, map[int64]int64{t.ToUserID: t.Amount}); err != nil {
+			return err
+		}
+
+		fromUser.Capture(t.Amount)
+		toUser.Coins += t.Amount
+
+		if err := uc.userRepo.Update(ctx, fromUser); err != nil {
+			return err
+		}
+		if err := uc.userRepo.Update(ctx, toUser); err != nil {
+			return err
+		}
+		if err := uc.moveLots(ctx, t.FromUserID, t.ToUserID, t.Amount); err != nil {
+			return err
+		}
+
+		tx := entity.Transaction{
+			FromUserID: t.FromUserID,
+			ToUserID:   t.ToUserID,
+			Amount:     t.Amount,
+			Type:       entity.TransactionTypeTransfer,
+			Message:    t.Message,
+		}
+		if err := uc.txRepo.Create(ctx, &tx); err != nil {
+			return err
+		}
+
+		decided = t
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	uc.notify(ctx, decided.FromUserID, fmt.Sprintf("Your transfer #%d of %d coins was approved", decided.ID, decided.Amount))
+
+	return nil
+}
+
+// RejectTransfer cancels a parked transfer and releases the held coins back
+// to the sender.
+func (uc *TransactionUC) RejectTransfer(ctx context.Context, approverID, id int64) error {
+	var decided entity.PendingTransfer
+
+	err := uc.dbTx.WithinTransaction(ctx, func(ctx context.Context) error {
+		t, fromUser, err := uc.decide(ctx, approverID, id, entity.PendingTransferStatusRejected)
+		if err != nil {
+			return err
+		}
+
+		fromUser.Release(t.Amount)
+		if err := uc.userRepo.Update(ctx, fromUser); err != nil {
+			return err
+		}
+
+		decided = t
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	uc.notify(ctx, decided.FromUserID, fmt.Sprintf("Your transfer #%d of %d coins was rejected", decided.ID, decided.Amount))
+
+	return nil
+}
+
+// ExpirePendingTransfers releases the coins held by every transfer nobody
+// decided on in time. Each transfer is handled in its own transaction.
+func (uc *TransactionUC) ExpirePendingTransfers(ctx context.Context, now time.Time) (int, error) {
+	ids, err := uc.pendingRepo.ListExpired(ctx, now, _expireBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	var errs []error
+
+	for _, id := range ids {
+		err := uc.dbTx.WithinTransaction(ctx, func(ctx context.Context) error {
+			t, err := uc.pendingRepo.GetByID(ctx, id)
+			if err != nil {
+				return err
+			}
+			// Decided or extended since it was listed.
+			if !t.IsPending() || !t.IsExpired(now) {
+				return nil
+			}
+
+			resolved, err := uc.pendingRepo.Resolve(ctx, id, entity.PendingTransferStatusExpired, nil)
+			if err != nil {
+				return err
+			}
+			if !resolved {
+				return nil
+			}
+
+			fromUser, err := uc.userRepo.GetByID(ctx, t.FromUserID)
+			if err != nil {
+				return err
+			}
+			if fromUser == nil {
+				return entity.ErrUserNotFound
+			}
+
+			fromUser.Release(t.Amount)
+			if err := uc.userRepo.Update(ctx, fromUser); err != nil {
+				return err
+			}
+
+			expired++
+			return nil
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("pending transfer %d: %w", id, err))
+		}
+	}
+
+	return expired, errors.Join(errs...)
+}
+
+// ListPendingTransfers returns the transfers the approver may decide on:
+// all of them for an admin, those sent by direct reports for a manager.
+func (uc *TransactionUC) ListPendingTransfers(ctx context.Context, approverID int64) ([]entity.PendingTransfer, error) {
+	approver, err := uc.userRepo.GetByID(ctx, approverID)
+	if err != nil {
+		return nil, err
+	}
+	if approver == nil || (!approver.IsAdmin() && !approver.IsManager()) {
+		return nil, entity.ErrForbidden
+	}
+
+	transfers, err := uc.pendingRepo.ListPending(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if approver.IsAdmin() {
+		return transfers, nil
+	}
+
+	visible := make([]entity.PendingTransfer, 0, len(transfers))
+	for _, t := range transfers {
+		sender, err := uc.userRepo.GetByID(ctx, t.FromUserID)
+		if err != nil {
+			continue
+		}
+		if sender.ReportsTo(approver.ID) {
+			visible = append(visible, t)
+		}
+	}
+
+	return visible, nil
+}
+
+// ListOwnPendingTransfers returns the large transfers the user has sent,
+// whatever their state.
+func (uc *TransactionUC) ListOwnPendingTransfers(ctx context.Context, userID int64) ([]entity.PendingTransfer, error) {
+	return uc.pendingRepo.ListByUser(ctx, userID)
+}
+
+// park holds the amount on the sender's account and records the transfer as
+// waiting for approval.
+func (uc *TransactionUC) park(ctx context.Context, fromUser *entity.User, toUserID, amount int64, message string) (*entity.PendingTransfer, error) {
+	if err := fromUser.Hold(amount); err != nil {
+		return nil, err
+	}
+	if err := uc.userRepo.Update(ctx, fromUser); err != nil {
+		return nil, err
+	}
+
+	t := &entity.PendingTransfer{
+		FromUserID: fromUser.ID,
+		ToUserID:   toUserID,
+		Amount:     amount,
+		Status:     entity.PendingTransferStatusPending,
+		ExpiresAt:  time.Now().Add(uc.approvals.TTL),
+	}
+	if message != "" {
+		t.Message = &message
+	}
+
+	if err := uc.pendingRepo.Create(ctx, t); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// decide locks a pending transfer, checks that the approver may decide on
+// it and moves it to status. It returns the transfer and its sender.
+func (uc *TransactionUC) decide(ctx context.Context, approverID, id int64, status entity.PendingTransferStatus) (entity.PendingTransfer, *entity.User, error) {
+	t, err := uc.pendingRepo.GetByID(ctx, id)
+	if err != nil {
+		return entity.PendingTransfer{}, nil, err
+	}
+	if !t.IsPending() {
+		return entity.PendingTransfer{}, nil, entity.ErrPendingTransferNotPending
+	}
+	if t.IsExpired(time.Now()) {
+		return entity.PendingTransfer{}, nil, entity.ErrPendingTransferExpired
+	}
+
+	fromUser, err := uc.userRepo.GetByID(ctx, t.FromUserID)
+	if err != nil {
+		return entity.PendingTransfer{}, nil, err
+	}
+	if fromUser == nil {
+		return entity.PendingTransfer{}, nil, entity.ErrUserNotFound
+	}
+
+	approver, err := uc.userRepo.GetByID(ctx, approverID)
+	if err != nil {
+		return entity.PendingTransfer{}, nil, err
+	}
+	if approver == nil || !canApprove(approver, fromUser) || approverID == t.ToUserID {
+		return entity.PendingTransfer{}, nil, entity.ErrForbidden
+	}
+
+	resolved, err := uc.pendingRepo.Resolve(ctx, id, status, &approverID)
+	if err != nil {
+		return entity.PendingTransfer{}, nil, err
+	}
+	if !resolved {
+		return entity.PendingTransfer{}, nil, entity.ErrPendingTransferNotPending
+	}
+
+	t.Status = status
+	return t, fromUser, nil
+}
+
+// notifyApprovers tells every admin, and the sender's manager, that a
+// transfer is waiting for them.
+func (uc *TransactionUC) notifyApprovers(ctx context.Context, fromUser *entity.User, t *entity.PendingTransfer) {
+	message := fmt.Sprintf("Transfer #%d of %d coins from %s is waiting for your approval", t.ID, t.Amount, fromUser.Username)
+
+	approvers := make(map[int64]bool)
+	if fromUser.ManagerID != nil {
+		approvers[*fromUser.ManagerID] = true
+	}
+
+	admins, err := uc.userRepo.ListByRole(ctx, entity.UserRoleAdmin)
+	if err == nil {
+		for _, admin := range admins {
+			approvers[admin.ID] = true
+		}
+	}
+
+	delete(approvers, fromUser.ID)
+	delete(approvers, t.ToUserID)
+
+	for approverID := range approvers {
+		uc.notify(ctx, approverID, message)
+	}
+}
+
+// notify is best-effort: the transfer has already been committed and shows
+// up in the pending lists whether or not the notification gets through.
+func (uc *TransactionUC) notify(ctx context.Context, userID int64, message string) {
+	_ = uc.notifier.Notify(ctx, userID, message)
+}
+
+func canApprove(approver, sender *entity.User) bool {
+	if approver.ID == sender.ID {
+		return false
+	}
+	return approver.IsAdmin() || (approver.IsManager() && sender.ReportsTo(approver.ID))
+}
@@ -0,0 +1,409 @@
+package transaction_usecase
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/usecase/transaction_usecase/mocks"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func pendingTransfer() entity.PendingTransfer {
+	return entity.PendingTransfer{
+		ID:         10,
+		FromUserID: 1,
+		ToUserID:   2,
+		Amount:     800,
+		Status:     entity.PendingTransferStatusPending,
+		ExpiresAt:  time.Now().Add(time.Hour),
+	}
+}
+
+func sender() *entity.User {
+	managerID := int64(3)
+	return &entity.User{ID: 1, Username: "sender", Coins: 200, HeldCoins: 800, ManagerID: &managerID}
+}
+
+func TestCreateTransferParksLargeAmounts(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	txRepo := mocks.NewMockRepository(ctrl)
+	pendingRepo := mocks.NewMockPendingTransferRepository(ctrl)
+	notifier := mocks.NewMockNotifier(ctrl)
+	dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+	uc := NewTransactionUC(
+		userRepo,
+		txRepo,
+		mocks.NewMockMerchRepository(ctrl),
+		noLots(ctrl),
+		noLimitOverrides(ctrl),
+		pendingRepo,
+		dbTransactor,
+		notifier,
+		noFraud(ctrl),
+		entity.TransferLimits{},
+		entity.ApprovalPolicy{Threshold: 500, TTL: time.Hour},
+	)
+	managerID := int64(3)
+
+	dbTransactor.EXPECT().
+		WithinTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		})
+	userRepo.EXPECT().
+		GetByID(gomock.Any(), int64(1)).
+		Return(&entity.User{ID: 1, Username: "sender", Coins: 1000, ManagerID: &managerID}, nil)
+	userRepo.EXPECT().
+		GetByID(gomock.Any(), int64(2)).
+		Return(&entity.User{ID: 2, Username: "receiver", Coins: 0}, nil)
+
+	userRepo.EXPECT().
+		Update(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, user *entity.User) error {
+			require.Equal(t, int64(1), user.ID)
+			require.Equal(t, int64(200), user.Coins)
+			require.Equal(t, int64(800), user.HeldCoins)
+			return nil
+		})
+
+	pendingRepo.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, pt *entity.PendingTransfer) error {
+			require.Equal(t, entity.PendingTransferStatusPending, pt.Status)
+			require.Equal(t, int64(800), pt.Amount)
+			pt.ID = 10
+			return nil
+		})
+
+	userRepo.EXPECT().
+		ListByRole(gomock.Any(), entity.UserRoleAdmin).
+		Return([]entity.User{{ID: 4, Role: entity.UserRoleAdmin}}, nil)
+	notifier.EXPECT().Notify(gomock.Any(), int64(3), gomock.Any()).Return(nil)
+	notifier.EXPECT().Notify(gomock.Any(), int64(4), gomock.Any()).Return(nil)
+
+	// the recipient is not credited and no transaction is recorded yet
+	err := uc.CreateTransfer(context.Background(), 1, 2, 800, "")
+	require.NoError(t, err)
+}
+
+func TestCreateDirectTransferRefusesParking(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+	uc := NewTransactionUC(
+		userRepo,
+		mocks.NewMockRepository(ctrl),
+		mocks.NewMockMerchRepository(ctrl),
+		noLots(ctrl),
+		noLimitOverrides(ctrl),
+		mocks.NewMockPendingTransferRepository(ctrl),
+		dbTransactor,
+		mocks.NewMockNotifier(ctrl),
+		noFraud(ctrl),
+		entity.TransferLimits{},
+		entity.ApprovalPolicy{Threshold: 500, TTL: time.Hour},
+	)
+
+	dbTransactor.EXPECT().
+		WithinTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		})
+	userRepo.EXPECT().
+		GetByID(gomock.Any(), int64(1)).
+		Return(&entity.User{ID: 1, Username: "sender", Coins: 1000}, nil)
+	userRepo.EXPECT().
+		GetByID(gomock.Any(), int64(2)).
+		Return(&entity.User{ID: 2, Username: "receiver", Coins: 0}, nil)
+
+	// nothing is held and nobody is asked to approve
+	err := uc.CreateDirectTransfer(context.Background(), 1, 2, 800, "")
+	require.ErrorIs(t, err, entity.ErrApprovalRequired)
+}
+
+func TestApproveTransfer(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	txRepo := mocks.NewMockRepository(ctrl)
+	pendingRepo := mocks.NewMockPendingTransferRepository(ctrl)
+	notifier := mocks.NewMockNotifier(ctrl)
+	dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+	uc := NewTransactionUC(
+		userRepo,
+		txRepo,
+		mocks.NewMockMerchRepository(ctrl),
+		noLots(ctrl),
+		noLimitOverrides(ctrl),
+		pendingRepo,
+		dbTransactor,
+		notifier,
+		noFraud(ctrl),
+		entity.TransferLimits{Daily: 1000},
+		entity.ApprovalPolicy{Threshold: 500, TTL: time.Hour},
+	)
+
+	tests := []test{
+		{
+			name: "manager approves a report's transfer",
+			mock: func() {
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				pendingRepo.EXPECT().GetByID(gomock.Any(), int64(10)).Return(pendingTransfer(), nil)
+				userRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(sender(), nil)
+				userRepo.EXPECT().
+					GetByID(gomock.Any(), int64(3)).
+					Return(&entity.User{ID: 3, Role: entity.UserRoleManager}, nil)
+				pendingRepo.EXPECT().
+					Resolve(gomock.Any(), int64(10), entity.PendingTransferStatusApproved, gomock.Any()).
+					Return(true, nil)
+				userRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(&entity.User{ID: 2, Coins: 0}, nil)
+				txRepo.EXPECT().SumOutgoing(gomock.Any(), int64(1), gomock.Any()).Return(int64(200), nil)
+				pendingRepo.EXPECT().SumPending(gomock.Any(), int64(1)).Return(int64(0), nil)
+
+				userRepo.EXPECT().
+					Update(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, user *entity.User) error {
+						require.Equal(t, int64(200), user.Coins)
+						require.Zero(t, user.HeldCoins)
+						return nil
+					})
+				userRepo.EXPECT().
+					Update(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, user *entity.User) error {
+						require.Equal(t, int64(800), user.Coins)
+						return nil
+					})
+
+				txRepo.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, tx *entity.Transaction) error {
+						require.Equal(t, entity.TransactionTypeTransfer, tx.Type)
+						require.Equal(t, int64(800), tx.Amount)
+						return nil
+					})
+
+				notifier.EXPECT().Notify(gomock.Any(), int64(1), gomock.Any()).Return(nil)
+			},
+			res: int64(3),
+		},
+		{
+			name: "sender reached the daily limit while it waited",
+			mock: func() {
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				pendingRepo.EXPECT().GetByID(gomock.Any(), int64(10)).Return(pendingTransfer(), nil)
+				userRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(sender(), nil)
+				userRepo.EXPECT().
+					GetByID(gomock.Any(), int64(3)).
+					Return(&entity.User{ID: 3, Role: entity.UserRoleManager}, nil)
+				pendingRepo.EXPECT().
+					Resolve(gomock.Any(), int64(10), entity.PendingTransferStatusApproved, gomock.Any()).
+					Return(true, nil)
+				userRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(&entity.User{ID: 2, Coins: 0}, nil)
+				txRepo.EXPECT().SumOutgoing(gomock.Any(), int64(1), gomock.Any()).Return(int64(300), nil)
+				pendingRepo.EXPECT().SumPending(gomock.Any(), int64(1)).Return(int64(0), nil)
+			},
+			res: int64(3),
+			err: entity.ErrTransferLimitExceeded,
+		},
+		{
+			name: "another team's manager",
+			mock: func() {
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				pendingRepo.EXPECT().GetByID(gomock.Any(), int64(10)).Return(pendingTransfer(), nil)
+				userRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(sender(), nil)
+				userRepo.EXPECT().
+					GetByID(gomock.Any(), int64(5)).
+					Return(&entity.User{ID: 5, Role: entity.UserRoleManager}, nil)
+			},
+			res: int64(5),
+			err: entity.ErrForbidden,
+		},
+		{
+			name: "already decided",
+			mock: func() {
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				pt := pendingTransfer()
+				pt.Status = entity.PendingTransferStatusRejected
+				pendingRepo.EXPECT().GetByID(gomock.Any(), int64(10)).Return(pt, nil)
+			},
+			res: int64(3),
+			err: entity.ErrPendingTransferNotPending,
+		},
+		{
+			name: "expired",
+			mock: func() {
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				pt := pendingTransfer()
+				pt.ExpiresAt = time.Now().Add(-time.Minute)
+				pendingRepo.EXPECT().GetByID(gomock.Any(), int64(10)).Return(pt, nil)
+			},
+			res: int64(3),
+			err: entity.ErrPendingTransferExpired,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			err := uc.ApproveTransfer(context.Background(), tc.res.(int64), 10)
+
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRejectTransfer(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	txRepo := mocks.NewMockRepository(ctrl)
+	pendingRepo := mocks.NewMockPendingTransferRepository(ctrl)
+	notifier := mocks.NewMockNotifier(ctrl)
+	dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+	uc := NewTransactionUC(
+		userRepo,
+		txRepo,
+		mocks.NewMockMerchRepository(ctrl),
+		noLots(ctrl),
+		noLimitOverrides(ctrl),
+		pendingRepo,
+		dbTransactor,
+		notifier,
+		noFraud(ctrl),
+		entity.TransferLimits{},
+		entity.ApprovalPolicy{Threshold: 500, TTL: time.Hour},
+	)
+
+	dbTransactor.EXPECT().
+		WithinTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		})
+	pendingRepo.EXPECT().GetByID(gomock.Any(), int64(10)).Return(pendingTransfer(), nil)
+	userRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(sender(), nil)
+	userRepo.EXPECT().
+		GetByID(gomock.Any(), int64(4)).
+		Return(&entity.User{ID: 4, Role: entity.UserRoleAdmin}, nil)
+	pendingRepo.EXPECT().
+		Resolve(gomock.Any(), int64(10), entity.PendingTransferStatusRejected, gomock.Any()).
+		Return(true, nil)
+
+	userRepo.EXPECT().
+		Update(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, user *entity.User) error {
+			require.Equal(t, int64(1000), user.Coins)
+			require.Zero(t, user.HeldCoins)
+			return nil
+		})
+
+	notifier.EXPECT().Notify(gomock.Any(), int64(1), gomock.Any()).Return(nil)
+
+	require.NoError(t, uc.RejectTransfer(context.Background(), 4, 10))
+}
+
+func TestExpirePendingTransfers(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	txRepo := mocks.NewMockRepository(ctrl)
+	pendingRepo := mocks.NewMockPendingTransferRepository(ctrl)
+	notifier := mocks.NewMockNotifier(ctrl)
+	dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+	uc := NewTransactionUC(
+		userRepo,
+		txRepo,
+		mocks.NewMockMerchRepository(ctrl),
+		noLots(ctrl),
+		noLimitOverrides(ctrl),
+		pendingRepo,
+		dbTransactor,
+		notifier,
+		noFraud(ctrl),
+		entity.TransferLimits{},
+		entity.ApprovalPolicy{Threshold: 500, TTL: time.Hour},
+	)
+	now := time.Now()
+
+	expired := pendingTransfer()
+	expired.ExpiresAt = now.Add(-time.Minute)
+
+	decided := pendingTransfer()
+	decided.ID = 11
+	decided.Status = entity.PendingTransferStatusApproved
+
+	pendingRepo.EXPECT().ListExpired(gomock.Any(), now, _expireBatchSize).Return([]int64{10, 11}, nil)
+	dbTransactor.EXPECT().
+		WithinTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		}).
+		Times(2)
+	pendingRepo.EXPECT().GetByID(gomock.Any(), int64(10)).Return(expired, nil)
+	pendingRepo.EXPECT().GetByID(gomock.Any(), int64(11)).Return(decided, nil)
+	pendingRepo.EXPECT().
+		Resolve(gomock.Any(), int64(10), entity.PendingTransferStatusExpired, nil).
+		Return(true, nil)
+	userRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(sender(), nil)
+
+	userRepo.EXPECT().
+		Update(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, user *entity.User) error {
+			require.Equal(t, int64(1000), user.Coins)
+			require.Zero(t, user.HeldCoins)
+			return nil
+		})
+
+	n, err := uc.ExpirePendingTransfers(context.Background(), now)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+}
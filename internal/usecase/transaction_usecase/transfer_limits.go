@@ -0,0 +1,131 @@
+package transaction_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"time"
+)
+
+// TransferLimits returns the limits in effect for the user: the configured
+// defaults with the user's override, if any, applied on top.
+func (uc *TransactionUC) TransferLimits(ctx context.Context, userID int64) (entity.TransferLimits, error) {
+	override, err := uc.limitRepo.GetOverride(ctx, userID)
+	if err != nil {
+		if errors.Is(err, entity.ErrTransferLimitNotFound) {
+			return uc.limits, nil
+		}
+		return entity.TransferLimits{}, err
+	}
+
+	return override.Apply(uc.limits), nil
+}
+
+// SetTransferLimits stores an admin override of the user's transfer limits.
+func (uc *TransactionUC) SetTransferLimits(ctx context.Context, adminID int64, override entity.TransferLimitOverride) error {
+	for _, limit := range []*int64{override.PerTransfer, override.Daily, override.PerRecipient} {
+		if limit != nil && *limit < 0 {
+			return entity.ErrNegativeAmount
+		}
+	}
+
+	return uc.dbTx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := uc.requireAdmin(ctx, adminID); err != nil {
+			return err
+		}
+
+		user, err := uc.userRepo.GetByID(ctx, override.UserID)
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return entity.ErrUserNotFound
+		}
+
+		return uc.limitRepo.SaveOverride(ctx, override)
+	})
+}
+
+// ClearTransferLimits drops the user's override so the defaults apply again.
+func (uc *TransactionUC) ClearTransferLimits(ctx context.Context, adminID, userID int64) error {
+	return uc.dbTx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := uc.requireAdmin(ctx, adminID); err != nil {
+			return err
+		}
+
+		return uc.limitRepo.DeleteOverride(ctx, userID)
+	})
+}
+
+func (uc *TransactionUC) ListTransferLimitOverrides(ctx context.Context, adminID int64) ([]entity.TransferLimitOverride, error) {
+	if err := uc.requireAdmin(ctx, adminID); err != nil {
+		return nil, err
+	}
+
+	return uc.limitRepo.ListOverrides(ctx)
+}
+
+// checkLimits makes sure sending amounts, keyed by recipient ID, keeps the
+// sender within their limits. Amounts sent to the same recipient in one call
+// count as a single transfer. Transfers still waiting for approval count as
+// already sent: otherwise parking a few of them would add up past the limits.
+func (uc *TransactionUC) checkLimits(ctx context.Context, fromUserID int64, amounts map[int64]int64) error {
+	limits, err := uc.TransferLimits(ctx, fromUserID)
+	if err != nil {
+		return err
+	}
+
+	since := time.Now().Add(-entity.TransferLimitWindow)
+
+	var total int64
+	for toUserID, amount := range amounts {
+		if limits.PerTransfer > 0 && amount > limits.PerTransfer {
+			return fmt.Errorf("%w: at most %d coins per transfer", entity.ErrTransferLimitExceeded, limits.PerTransfer)
+		}
+
+		if limits.PerRecipient > 0 {
+			sent, err := uc.txRepo.SumOutgoingTo(ctx, fromUserID, toUserID, since)
+			if err != nil {
+				return err
+			}
+			pending, err := uc.pendingRepo.SumPendingTo(ctx, fromUserID, toUserID)
+			if err != nil {
+				return err
+			}
+			if sent+pending+amount > limits.PerRecipient {
+				return fmt.Errorf("%w: at most %d coins to one user per day", entity.ErrTransferLimitExceeded, limits.PerRecipient)
+			}
+		}
+
+		total += amount
+	}
+
+	if limits.Daily > 0 {
+		sent, err := uc.txRepo.SumOutgoing(ctx, fromUserID, since)
+		if err != nil {
+			return err
+		}
+		pending, err := uc.pendingRepo.SumPending(ctx, fromUserID)
+		if err != nil {
+			return err
+		}
+		if sent+pending+total > limits.Daily {
+			return fmt.Errorf("%w: at most %d coins per day", entity.ErrTransferLimitExceeded, limits.Daily)
+		}
+	}
+
+	return nil
+}
+
+func (uc *TransactionUC) requireAdmin(ctx context.Context, adminID int64) error {
+	admin, err := uc.userRepo.GetByID(ctx, adminID)
+	if err != nil {
+		return err
+	}
+	if admin == nil || !admin.IsAdmin() {
+		return entity.ErrForbidden
+	}
+
+	return nil
+}
@@ -0,0 +1,310 @@
+package transaction_usecase
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/usecase/transaction_usecase/mocks"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestCreateTransferLimits(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	txRepo := mocks.NewMockRepository(ctrl)
+	limitRepo := mocks.NewMockLimitRepository(ctrl)
+	pendingRepo := mocks.NewMockPendingTransferRepository(ctrl)
+	dbTx := mocks.NewMockDBTransactor(ctrl)
+
+	uc := NewTransactionUC(
+		userRepo,
+		txRepo,
+		mocks.NewMockMerchRepository(ctrl),
+		noLots(ctrl),
+		limitRepo,
+		pendingRepo,
+		dbTx,
+		mocks.NewMockNotifier(ctrl),
+		noFraud(ctrl),
+		entity.TransferLimits{PerTransfer: 300, Daily: 500, PerRecipient: 400},
+		entity.ApprovalPolicy{},
+	)
+
+	expectUsers := func() {
+		dbTx.EXPECT().
+			WithinTransaction(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+		userRepo.EXPECT().
+			GetByID(gomock.Any(), int64(1)).
+			Return(&entity.User{ID: 1, Username: "sender", Coins: 1000}, nil)
+		userRepo.EXPECT().
+			GetByID(gomock.Any(), int64(2)).
+			Return(&entity.User{ID: 2, Username: "receiver", Coins: 0}, nil)
+	}
+	noOverride := func() {
+		limitRepo.EXPECT().
+			GetOverride(gomock.Any(), int64(1)).
+			Return(entity.TransferLimitOverride{}, entity.ErrTransferLimitNotFound)
+	}
+	expectTransfer := func() {
+		userRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+		txRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+	}
+
+	tests := []test{
+		{
+			name:   "within limits",
+			amount: 100,
+			mock: func() {
+				expectUsers()
+				noOverride()
+				txRepo.EXPECT().SumOutgoingTo(gomock.Any(), int64(1), int64(2), gomock.Any()).Return(int64(200), nil)
+				pendingRepo.EXPECT().SumPendingTo(gomock.Any(), int64(1), int64(2)).Return(int64(0), nil)
+				txRepo.EXPECT().SumOutgoing(gomock.Any(), int64(1), gomock.Any()).Return(int64(300), nil)
+				pendingRepo.EXPECT().SumPending(gomock.Any(), int64(1)).Return(int64(0), nil)
+				expectTransfer()
+			},
+		},
+		{
+			name:   "above per-transfer limit",
+			amount: 301,
+			mock: func() {
+				expectUsers()
+				noOverride()
+			},
+			err: entity.ErrTransferLimitExceeded,
+		},
+		{
+			name:   "above per-recipient limit",
+			amount: 250,
+			mock: func() {
+				expectUsers()
+				noOverride()
+				txRepo.EXPECT().SumOutgoingTo(gomock.Any(), int64(1), int64(2), gomock.Any()).Return(int64(200), nil)
+				pendingRepo.EXPECT().SumPendingTo(gomock.Any(), int64(1), int64(2)).Return(int64(0), nil)
+			},
+			err: entity.ErrTransferLimitExceeded,
+		},
+		{
+			name:   "above daily limit",
+			amount: 100,
+			mock: func() {
+				expectUsers()
+				noOverride()
+				txRepo.EXPECT().SumOutgoingTo(gomock.Any(), int64(1), int64(2), gomock.Any()).Return(int64(0), nil)
+				pendingRepo.EXPECT().SumPendingTo(gomock.Any(), int64(1), int64(2)).Return(int64(0), nil)
+				txRepo.EXPECT().SumOutgoing(gomock.Any(), int64(1), gomock.Any()).Return(int64(450), nil)
+				pendingRepo.EXPECT().SumPending(gomock.Any(), int64(1)).Return(int64(0), nil)
+			},
+			err: entity.ErrTransferLimitExceeded,
+		},
+		{
+			name:   "pending transfers count against the per-recipient limit",
+			amount: 100,
+			mock: func() {
+				expectUsers()
+				noOverride()
+				txRepo.EXPECT().SumOutgoingTo(gomock.Any(), int64(1), int64(2), gomock.Any()).Return(int64(0), nil)
+				pendingRepo.EXPECT().SumPendingTo(gomock.Any(), int64(1), int64(2)).Return(int64(350), nil)
+			},
+			err: entity.ErrTransferLimitExceeded,
+		},
+		{
+			name:   "pending transfers count against the daily limit",
+			amount: 100,
+			mock: func() {
+				expectUsers()
+				noOverride()
+				txRepo.EXPECT().SumOutgoingTo(gomock.Any(), int64(1), int64(2), gomock.Any()).Return(int64(0), nil)
+				pendingRepo.EXPECT().SumPendingTo(gomock.Any(), int64(1), int64(2)).Return(int64(0), nil)
+				txRepo.EXPECT().SumOutgoing(gomock.Any(), int64(1), gomock.Any()).Return(int64(100), nil)
+				pendingRepo.EXPECT().SumPending(gomock.Any(), int64(1)).Return(int64(350), nil)
+			},
+			err: entity.ErrTransferLimitExceeded,
+		},
+		{
+			name:   "override lifts the daily limit",
+			amount: 100,
+			mock: func() {
+				expectUsers()
+				unlimited := int64(0)
+				limitRepo.EXPECT().
+					GetOverride(gomock.Any(), int64(1)).
+					Return(entity.TransferLimitOverride{UserID: 1, Daily: &unlimited}, nil)
+				txRepo.EXPECT().SumOutgoingTo(gomock.Any(), int64(1), int64(2), gomock.Any()).Return(int64(0), nil)
+				pendingRepo.EXPECT().SumPendingTo(gomock.Any(), int64(1), int64(2)).Return(int64(0), nil)
+				expectTransfer()
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			err := uc.CreateTransfer(context.Background(), 1, 2, tc.amount, "")
+
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSetTransferLimits(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	limitRepo := mocks.NewMockLimitRepository(ctrl)
+	dbTx := mocks.NewMockDBTransactor(ctrl)
+
+	uc := NewTransactionUC(
+		userRepo,
+		mocks.NewMockRepository(ctrl),
+		mocks.NewMockMerchRepository(ctrl),
+		noLots(ctrl),
+		limitRepo,
+		mocks.NewMockPendingTransferRepository(ctrl),
+		dbTx,
+		mocks.NewMockNotifier(ctrl),
+		noFraud(ctrl),
+		entity.TransferLimits{Daily: 500},
+		entity.ApprovalPolicy{},
+	)
+
+	dbTx.EXPECT().
+		WithinTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		}).
+		Times(2)
+
+	daily := int64(2000)
+	override := entity.TransferLimitOverride{UserID: 2, Daily: &daily}
+
+	t.Run("success", func(t *testing.T) {
+		userRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&entity.User{ID: 1, Role: entity.UserRoleAdmin}, nil)
+		userRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(&entity.User{ID: 2}, nil)
+		limitRepo.EXPECT().SaveOverride(gomock.Any(), override).Return(nil)
+
+		require.NoError(t, uc.SetTransferLimits(context.Background(), 1, override))
+	})
+
+	t.Run("not an admin", func(t *testing.T) {
+		userRepo.EXPECT().GetByID(gomock.Any(), int64(3)).Return(&entity.User{ID: 3, Role: entity.UserRoleUser}, nil)
+
+		err := uc.SetTransferLimits(context.Background(), 3, override)
+		require.ErrorIs(t, err, entity.ErrForbidden)
+	})
+
+	t.Run("negative limit", func(t *testing.T) {
+		negative := int64(-1)
+
+		err := uc.SetTransferLimits(context.Background(), 1, entity.TransferLimitOverride{UserID: 2, PerTransfer: &negative})
+		require.ErrorIs(t, err, entity.ErrNegativeAmount)
+	})
+
+	t.Run("effective limits", func(t *testing.T) {
+		limitRepo.EXPECT().GetOverride(gomock.Any(), int64(2)).Return(override, nil)
+
+		limits, err := uc.TransferLimits(context.Background(), 2)
+		require.NoError(t, err)
+		require.Equal(t, entity.TransferLimits{Daily: 2000}, limits)
+	})
+}
+
+func TestCheckTransfer(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	txRepo := mocks.NewMockRepository(ctrl)
+	pendingRepo := mocks.NewMockPendingTransferRepository(ctrl)
+	fraud := mocks.NewMockFraudChecker(ctrl)
+
+	uc := NewTransactionUC(
+		mocks.NewMockUserRepository(ctrl),
+		txRepo,
+		mocks.NewMockMerchRepository(ctrl),
+		noLots(ctrl),
+		noLimitOverrides(ctrl),
+		pendingRepo,
+		mocks.NewMockDBTransactor(ctrl),
+		mocks.NewMockNotifier(ctrl),
+		fraud,
+		entity.TransferLimits{Daily: 500},
+		entity.ApprovalPolicy{Threshold: 200},
+	)
+
+	tests := []test{
+		{
+			name:   "passes",
+			amount: 100,
+			mock: func() {
+				fraud.EXPECT().Check(gomock.Any(), gomock.Any()).Return(entity.FraudActionNone, nil)
+				txRepo.EXPECT().SumOutgoing(gomock.Any(), int64(1), gomock.Any()).Return(int64(300), nil)
+				pendingRepo.EXPECT().SumPending(gomock.Any(), int64(1)).Return(int64(0), nil)
+			},
+		},
+		{
+			name:   "over the daily limit",
+			amount: 150,
+			mock: func() {
+				fraud.EXPECT().Check(gomock.Any(), gomock.Any()).Return(entity.FraudActionNone, nil)
+				txRepo.EXPECT().SumOutgoing(gomock.Any(), int64(1), gomock.Any()).Return(int64(400), nil)
+				pendingRepo.EXPECT().SumPending(gomock.Any(), int64(1)).Return(int64(0), nil)
+			},
+			err: entity.ErrTransferLimitExceeded,
+		},
+		{
+			name:   "needs approval",
+			amount: 250,
+			mock: func() {
+				fraud.EXPECT().Check(gomock.Any(), gomock.Any()).Return(entity.FraudActionNone, nil)
+				txRepo.EXPECT().SumOutgoing(gomock.Any(), int64(1), gomock.Any()).Return(int64(0), nil)
+				pendingRepo.EXPECT().SumPending(gomock.Any(), int64(1)).Return(int64(0), nil)
+			},
+			err: entity.ErrApprovalRequired,
+		},
+		{
+			name:   "blocked",
+			amount: 100,
+			mock: func() {
+				fraud.EXPECT().Check(gomock.Any(), gomock.Any()).Return(entity.FraudActionBlock, nil)
+			},
+			err: entity.ErrOperationBlocked,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			err := uc.CheckTransfer(context.Background(), entity.FraudOperation{
+				Type:       entity.TransactionTypeMarketSale,
+				FromUserID: 1,
+				ToUserID:   2,
+				Amount:     tc.amount,
+			})
+
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
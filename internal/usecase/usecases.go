@@ -2,19 +2,178 @@ package usecase
 
 import (
 	"context"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/usecase/auction_usecase"
+	"github.com/smthjapanese/avito-merch/internal/usecase/coin_request_usecase"
+	"github.com/smthjapanese/avito-merch/internal/usecase/fraud_usecase"
+	"github.com/smthjapanese/avito-merch/internal/usecase/item_transfer_usecase"
+	"github.com/smthjapanese/avito-merch/internal/usecase/market_usecase"
+	"github.com/smthjapanese/avito-merch/internal/usecase/preorder_usecase"
+	"github.com/smthjapanese/avito-merch/internal/usecase/promo_usecase"
+	"github.com/smthjapanese/avito-merch/internal/usecase/raffle_usecase"
+	"github.com/smthjapanese/avito-merch/internal/usecase/redemption_usecase"
+	"github.com/smthjapanese/avito-merch/internal/usecase/reward_usecase"
+	"github.com/smthjapanese/avito-merch/internal/usecase/scheduled_transfer_usecase"
+	"github.com/smthjapanese/avito-merch/internal/usecase/transaction_usecase"
+	"github.com/smthjapanese/avito-merch/internal/usecase/wishlist_usecase"
+	"time"
 )
 
 type TransactionUseCase interface {
-	CreateTransfer(ctx context.Context, fromUserID, toUserID int64, amount int64) error
+	CreateTransfer(ctx context.Context, fromUserID, toUserID int64, amount int64, message string) error
+	CreateBulkTransfer(ctx context.Context, fromUserID int64, req transaction_usecase.BulkTransferRequest) (*transaction_usecase.BulkTransferResult, error)
 	GetUserHistory(ctx context.Context, userID int64) (*TransactionHistory, error)
+	KudosFeed(ctx context.Context, limit int) ([]entity.Kudos, error)
+	TransferLimits(ctx context.Context, userID int64) (entity.TransferLimits, error)
+	SetTransferLimits(ctx context.Context, adminID int64, override entity.TransferLimitOverride) error
+	ClearTransferLimits(ctx context.Context, adminID, userID int64) error
+	ListTransferLimitOverrides(ctx context.Context, adminID int64) ([]entity.TransferLimitOverride, error)
+	ApproveTransfer(ctx context.Context, approverID, id int64) error
+	RejectTransfer(ctx context.Context, approverID, id int64) error
+	ExpirePendingTransfers(ctx context.Context, now time.Time) (int, error)
+	ListPendingTransfers(ctx context.Context, approverID int64) ([]entity.PendingTransfer, error)
+	ListOwnPendingTransfers(ctx context.Context, userID int64) ([]entity.PendingTransfer, error)
 }
 
 type MerchUseCase interface {
-	ListAvailable(ctx context.Context) ([]MerchItemDTO, error)
-	BuyItem(ctx context.Context, userID int64, itemName string) error
+	ListAvailable(ctx context.Context, acceptLanguage string) ([]MerchItemDTO, error)
+	Catalog(ctx context.Context, req CatalogRequest) (CatalogPage, error)
+	ListCategories(ctx context.Context) ([]entity.MerchCategory, error)
+	ListVariants(ctx context.Context, itemName string) ([]entity.MerchVariant, error)
+	PriceHistory(ctx context.Context, itemName string) (PriceHistory, error)
+	SchedulePriceChange(ctx context.Context, adminID int64, itemName string, price int64, effectiveFrom time.Time) (entity.MerchPrice, error)
+	CancelPriceChange(ctx context.Context, adminID int64, itemName string, id int64) error
+	BuyItem(ctx context.Context, userID int64, itemName string, variantID *int64, promoCode string) error
+	CreateBundle(ctx context.Context, adminID int64, req CreateBundleRequest) (entity.Bundle, error)
+	ListBundles(ctx context.Context) ([]entity.Bundle, error)
+	BuyBundle(ctx context.Context, userID int64, req BuyBundleRequest) error
 }
 
 type UserUseCase interface {
 	Register(ctx context.Context, username, password string) (string, error)
 	GetProfile(ctx context.Context, userID int64) (UserProfileDTO, error)
+	SetPublicKudos(ctx context.Context, userID int64, public bool) error
+}
+
+type GrantUseCase interface {
+	GrantToUser(ctx context.Context, adminID, toUserID int64, amount int64) error
+	GrantToTeam(ctx context.Context, adminID, teamID int64, amount int64) (int, error)
+	GrantToAll(ctx context.Context, adminID int64, amount int64) (int, error)
+	PayAllowance(ctx context.Context, now time.Time) (int, error)
+}
+
+type RewardUseCase interface {
+	Award(ctx context.Context, managerID, toUserID int64, amount int64, reason string) error
+	SetBudget(ctx context.Context, adminID, managerID int64, allocation int64) error
+	ResetBudgets(ctx context.Context, now time.Time) (int64, error)
+	Report(ctx context.Context, callerID, managerID int64, now time.Time) (reward_usecase.RewardReport, error)
+	ListBudgets(ctx context.Context, adminID int64) ([]entity.RewardBudget, error)
+}
+
+type CoinRequestUseCase interface {
+	Create(ctx context.Context, requesterID, payerID int64, amount int64, reason string) (entity.CoinRequest, error)
+	Accept(ctx context.Context, payerID, requestID int64) error
+	Decline(ctx context.Context, payerID, requestID int64) error
+	Cancel(ctx context.Context, requesterID, requestID int64) error
+	ExpireRequests(ctx context.Context, now time.Time) (int64, error)
+	ListIncoming(ctx context.Context, userID int64) ([]coin_request_usecase.CoinRequestInfo, error)
+	ListOutgoing(ctx context.Context, userID int64) ([]coin_request_usecase.CoinRequestInfo, error)
+}
+
+type ScheduledTransferUseCase interface {
+	Schedule(ctx context.Context, fromUserID int64, req scheduled_transfer_usecase.ScheduleRequest) (entity.ScheduledTransfer, error)
+	Cancel(ctx context.Context, userID, id int64) error
+	List(ctx context.Context, userID int64) ([]entity.ScheduledTransfer, error)
+	Runs(ctx context.Context, userID, id int64) ([]entity.ScheduledTransferRun, error)
+	RunDue(ctx context.Context, now time.Time) (int, error)
+}
+
+type FraudUseCase interface {
+	Check(ctx context.Context, op entity.FraudOperation) (entity.FraudAction, error)
+	ListFlags(ctx context.Context, adminID int64, status entity.FraudFlagStatus) ([]entity.FraudFlag, error)
+	ReviewFlag(ctx context.Context, adminID, id int64, status entity.FraudFlagStatus) error
+	Replay(ctx context.Context, adminID int64, since time.Time) ([]fraud_usecase.ReplayResult, error)
+}
+
+type PromoUseCase interface {
+	CreateCode(ctx context.Context, adminID int64, req promo_usecase.CreateCodeRequest) (entity.PromoCode, error)
+	ListCodes(ctx context.Context, adminID int64) ([]entity.PromoCode, error)
+	CreateSale(ctx context.Context, adminID int64, req promo_usecase.CreateSaleRequest) (entity.MerchSale, error)
+	ListSales(ctx context.Context, now time.Time) ([]entity.MerchSale, error)
+}
+
+type OrderUseCase interface {
+	ListOrders(ctx context.Context, adminID int64, status entity.OrderStatus) ([]entity.Order, error)
+	AdvanceOrder(ctx context.Context, adminID, id int64, status entity.OrderStatus) (entity.Order, error)
+	SetPickupLocation(ctx context.Context, userID, id, locationID int64) error
+	ListPickupLocations(ctx context.Context) ([]entity.PickupLocation, error)
+}
+
+type ItemTransferUseCase interface {
+	TransferItem(ctx context.Context, fromUserID int64, req item_transfer_usecase.TransferItemRequest) (entity.Transaction, error)
+}
+
+type MarketUseCase interface {
+	CreateListing(ctx context.Context, sellerID int64, req market_usecase.CreateListingRequest) (entity.Listing, error)
+	Buy(ctx context.Context, buyerID, listingID int64) (entity.Listing, error)
+	CancelListing(ctx context.Context, userID, listingID int64) error
+	ExpireListings(ctx context.Context, now time.Time) (int, error)
+	Search(ctx context.Context, req market_usecase.SearchListingsRequest) (market_usecase.ListingPage, error)
+	ListMyListings(ctx context.Context, sellerID int64) ([]entity.Listing, error)
+}
+
+type AuctionUseCase interface {
+	CreateAuction(ctx context.Context, adminID int64, req auction_usecase.CreateAuctionRequest) (entity.Auction, error)
+	PlaceBid(ctx context.Context, userID, auctionID, amount int64) (entity.Auction, error)
+	CloseDue(ctx context.Context, now time.Time) (int, error)
+	CancelAuction(ctx context.Context, adminID, auctionID int64) error
+	ListAuctions(ctx context.Context) ([]entity.Auction, error)
+	ListBids(ctx context.Context, auctionID int64) ([]entity.AuctionBid, error)
+}
+
+type RaffleUseCase interface {
+	CreateRaffle(ctx context.Context, adminID int64, req raffle_usecase.CreateRaffleRequest) (entity.Raffle, error)
+	BuyTickets(ctx context.Context, userID, raffleID, count int64) error
+	DrawDue(ctx context.Context, now time.Time) (int, error)
+	ListRaffles(ctx context.Context) ([]entity.Raffle, error)
+	Results(ctx context.Context, raffleID int64) (raffle_usecase.RaffleResults, error)
+}
+
+type PreorderUseCase interface {
+	ScheduleRelease(ctx context.Context, adminID int64, req preorder_usecase.ScheduleReleaseRequest) (entity.MerchItem, error)
+	PlacePreorder(ctx context.Context, userID int64, req preorder_usecase.PreorderRequest) (entity.Preorder, error)
+	CancelPreorder(ctx context.Context, userID, id int64) error
+	ListPreorders(ctx context.Context, userID int64) ([]entity.Preorder, error)
+	ReleaseDue(ctx context.Context, now time.Time) (int, error)
+}
+
+type WishlistUseCase interface {
+	AddItem(ctx context.Context, userID int64, req wishlist_usecase.AddItemRequest) (entity.WishlistItem, error)
+	RemoveItem(ctx context.Context, userID, id int64) error
+	ListWishlist(ctx context.Context, userID int64) ([]entity.WishlistItem, error)
+	CheckWishlists(ctx context.Context, now time.Time) (int, error)
+}
+
+type NotificationUseCase interface {
+	ListNotifications(ctx context.Context, userID int64, unreadOnly bool) ([]entity.Notification, error)
+	MarkRead(ctx context.Context, userID, id int64) error
+	MarkAllRead(ctx context.Context, userID int64) (int64, error)
+}
+
+type RedemptionUseCase interface {
+	Redeem(ctx context.Context, userID int64, req redemption_usecase.RedeemRequest) (entity.Redemption, error)
+	ListRedemptions(ctx context.Context, userID int64) ([]entity.Redemption, error)
+	ListPending(ctx context.Context, adminID int64) ([]entity.Redemption, error)
+	PickList(ctx context.Context, adminID int64) ([]byte, error)
+	Fulfil(ctx context.Context, adminID, id int64) error
+	Cancel(ctx context.Context, userID, id int64) error
+}
+
+type CoinExpiryUseCase interface {
+	Sweep(ctx context.Context, now time.Time) (int64, error)
+}
+
+type RecommendationUseCase interface {
+	Recommend(ctx context.Context, userID int64, limit int) ([]entity.Recommendation, error)
+	Refresh(ctx context.Context) error
 }
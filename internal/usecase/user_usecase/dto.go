@@ -6,21 +6,48 @@ import (
 )
 
 type UserDTO struct {
-	ID        int64     `json:"id"`
-	Username  string    `json:"username"`
-	Coins     int64     `json:"coins"`
-	CreatedAt time.Time `json:"created_at"`
+	ID          int64     `json:"id"`
+	Username    string    `json:"username"`
+	Coins       int64     `json:"coins"`
+	HeldCoins   int64     `json:"held_coins"`
+	PublicKudos bool      `json:"public_kudos"`
+	CreatedAt   time.Time `json:"created_at"`
 }
 
 type UserProfileDTO struct {
 	User      UserDTO            `json:"user"`
 	Inventory []InventoryItemDTO `json:"inventory"`
 	History   TransactionHistory `json:"history"`
+	Expiring  []ExpiringCoinsDTO `json:"expiring_coins"`
+	Orders    []OrderDTO         `json:"orders"`
+}
+
+// OrderDTO is the fulfilment status of a purchase as shown in the profile.
+type OrderDTO struct {
+	ID        int64              `json:"id"`
+	ItemName  string             `json:"name"`
+	Price     int64              `json:"price"`
+	Status    entity.OrderStatus `json:"status"`
+	Location  string             `json:"location,omitempty"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+// ExpiringCoinsDTO is an amount of coins that will be swept at ExpiresAt
+// unless spent first.
+type ExpiringCoinsDTO struct {
+	Amount    int64     `json:"amount"`
+	ExpiresAt time.Time `json:"expires_at"`
 }
 
 type InventoryItemDTO struct {
-	ItemID      int64     `json:"item_id"`
-	ItemName    string    `json:"name"`
+	ItemID    int64  `json:"item_id"`
+	ItemName  string `json:"name"`
+	VariantID *int64 `json:"variant_id,omitempty"`
+	// Variant is the variant label, e.g. "L" or "L, black".
+	Variant string `json:"variant,omitempty"`
+	// Title is how the row reads in the profile, e.g. "hoody (L) x2".
+	Title       string    `json:"title"`
 	Quantity    int64     `json:"quantity"`
 	PurchasedAt time.Time `json:"purchased_at"`
 }
@@ -34,5 +61,7 @@ type TransactionInfo struct {
 	Amount    int64                  `json:"amount"`
 	Type      entity.TransactionType `json:"type"`
 	ItemName  *string                `json:"item_name,omitempty"`
+	Quantity  *int64                 `json:"quantity,omitempty"`
+	Message   *string                `json:"message,omitempty"`
 	CreatedAt time.Time              `json:"created_at"`
 }
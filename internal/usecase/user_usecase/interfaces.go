@@ -16,6 +16,8 @@ type MerchRepository interface {
 	List(ctx context.Context) ([]entity.MerchItem, error)
 	GetByID(ctx context.Context, id int64) (entity.MerchItem, error)
 	GetByName(ctx context.Context, name string) (entity.MerchItem, error)
+	GetVariant(ctx context.Context, id int64) (entity.MerchVariant, error)
+	GetBundleByID(ctx context.Context, id int64) (entity.Bundle, error)
 }
 
 type InventoryRepository interface {
@@ -24,9 +26,23 @@ type InventoryRepository interface {
 	Create(ctx context.Context, inventory entity.UserInventory) error
 }
 
+type LotRepository interface {
+	Create(ctx context.Context, lot *entity.CoinLot) error
+	ListExpiring(ctx context.Context, userID int64) ([]entity.CoinLot, error)
+}
+
 type UserRepository interface {
 	Create(ctx context.Context, user *entity.User) error
 	GetByID(ctx context.Context, id int64) (*entity.User, error)
 	GetByUsername(ctx context.Context, username string) (*entity.User, error)
 	Update(ctx context.Context, user *entity.User) error
+	SetPublicKudos(ctx context.Context, id int64, public bool) error
+}
+
+type OrderRepository interface {
+	ListByUser(ctx context.Context, userID int64) ([]entity.Order, error)
+}
+
+type DBTransactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
 }
@@ -13,6 +13,9 @@ type UserUseCase struct {
 	txRepo    TransactionRepository
 	invRepo   InventoryRepository
 	merchRepo MerchRepository
+	lotRepo   LotRepository
+	orderRepo OrderRepository
+	dbTx      DBTransactor
 }
 
 func NewUserUseCase(
@@ -20,12 +23,18 @@ func NewUserUseCase(
 	txRepo TransactionRepository,
 	invRepo InventoryRepository,
 	merchRepo MerchRepository,
+	lotRepo LotRepository,
+	orderRepo OrderRepository,
+	dbTx DBTransactor,
 ) UserUseCase {
 	return UserUseCase{
 		userRepo:  userRepo,
 		txRepo:    txRepo,
 		invRepo:   invRepo,
 		merchRepo: merchRepo,
+		lotRepo:   lotRepo,
+		orderRepo: orderRepo,
+		dbTx:      dbTx,
 	}
 }
 
@@ -47,7 +56,17 @@ func (uc *UserUseCase) Register(ctx context.Context, username, password string)
 		CreatedAt:    time.Now(),
 	}
 
-	if err := uc.userRepo.Create(ctx, user); err != nil {
+	// The welcome balance has to come with its lot, or it could never be
+	// spent.
+	err = uc.dbTx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := uc.userRepo.Create(ctx, user); err != nil {
+			return err
+		}
+
+		lot := entity.NewCoinLot(user.ID, user.Coins, user.CreatedAt, 0)
+		return uc.lotRepo.Create(ctx, &lot)
+	})
+	if err != nil {
 		return "", err
 	}
 
@@ -80,11 +99,47 @@ func (uc *UserUseCase) GetProfile(ctx context.Context, userID int64) (UserProfil
 		if err != nil {
 			continue // Skip items that can't be found
 		}
-		inventoryDTO = append(inventoryDTO, InventoryItemDTO{
+		dto := InventoryItemDTO{
 			ItemID:      item.ItemID,
 			ItemName:    merchItem.Name,
+			VariantID:   item.VariantID,
 			Quantity:    item.Quantity,
 			PurchasedAt: item.PurchasedAt,
+		}
+		if item.VariantID != nil {
+			variant, err := uc.merchRepo.GetVariant(ctx, *item.VariantID)
+			if err == nil {
+				dto.Variant = variant.Label()
+			}
+		}
+		dto.Title = inventoryTitle(dto)
+		inventoryDTO = append(inventoryDTO, dto)
+	}
+
+	lots, err := uc.lotRepo.ListExpiring(ctx, userID)
+	if err != nil {
+		return UserProfileDTO{}, err
+	}
+
+	orders, err := uc.orderRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return UserProfileDTO{}, err
+	}
+
+	ordersDTO := make([]OrderDTO, 0, len(orders))
+	for _, order := range orders {
+		merchItem, err := uc.merchRepo.GetByID(ctx, order.ItemID)
+		if err != nil {
+			continue
+		}
+		ordersDTO = append(ordersDTO, OrderDTO{
+			ID:        order.ID,
+			ItemName:  merchItem.Name,
+			Price:     order.Price,
+			Status:    order.Status,
+			Location:  order.Location,
+			CreatedAt: order.CreatedAt,
+			UpdatedAt: order.UpdatedAt,
 		})
 	}
 
@@ -92,16 +147,45 @@ func (uc *UserUseCase) GetProfile(ctx context.Context, userID int64) (UserProfil
 
 	return UserProfileDTO{
 		User: UserDTO{
-			ID:        user.ID,
-			Username:  user.Username,
-			Coins:     user.Coins,
-			CreatedAt: user.CreatedAt,
+			ID:          user.ID,
+			Username:    user.Username,
+			Coins:       user.Coins,
+			HeldCoins:   user.HeldCoins,
+			PublicKudos: user.PublicKudos,
+			CreatedAt:   user.CreatedAt,
 		},
 		Inventory: inventoryDTO,
 		History:   history,
+		Expiring:  upcomingExpiry(lots),
+		Orders:    ordersDTO,
 	}, nil
 }
 
+// SetPublicKudos opts the user in or out of the public kudos feed.
+func (uc *UserUseCase) SetPublicKudos(ctx context.Context, userID int64, public bool) error {
+	return uc.userRepo.SetPublicKudos(ctx, userID, public)
+}
+
+// upcomingExpiry sums the coins left in lots, soonest expiry first, merging
+// lots that expire at the same moment.
+func upcomingExpiry(lots []entity.CoinLot) []ExpiringCoinsDTO {
+	result := make([]ExpiringCoinsDTO, 0, len(lots))
+	for _, lot := range lots {
+		if lot.ExpiresAt == nil {
+			continue
+		}
+		if n := len(result); n > 0 && result[n-1].ExpiresAt.Equal(*lot.ExpiresAt) {
+			result[n-1].Amount += lot.Remaining
+			continue
+		}
+		result = append(result, ExpiringCoinsDTO{
+			Amount:    lot.Remaining,
+			ExpiresAt: *lot.ExpiresAt,
+		})
+	}
+	return result
+}
+
 func generateDummyToken(userID int64, username string) string {
 	return fmt.Sprintf("dummy_token_%d_%s", userID, username)
 }
@@ -111,49 +195,60 @@ func (uc *UserUseCase) processTransactionHistory(ctx context.Context, transactio
 	sent := make([]TransactionInfo, 0)
 
 	for _, tx := range transactions {
-		var info TransactionInfo
-		var otherUserName string
-
+		otherUserID := tx.ToUserID
 		if tx.ToUserID == userID {
-			fromUser, err := uc.userRepo.GetByID(ctx, tx.FromUserID)
-			if err != nil {
-				continue
-			}
-			otherUserName = fromUser.Username
-			info = TransactionInfo{
-				ID:        tx.ID,
-				User:      otherUserName,
-				Amount:    tx.Amount,
-				Type:      tx.Type,
-				CreatedAt: tx.CreatedAt,
+			otherUserID = tx.FromUserID
+		}
+		otherUser, err := uc.userRepo.GetByID(ctx, otherUserID)
+		if err != nil {
+			continue
+		}
+
+		info := TransactionInfo{
+			ID:        tx.ID,
+			User:      otherUser.Username,
+			Amount:    tx.Amount,
+			Type:      tx.Type,
+			Quantity:  tx.Quantity,
+			Message:   tx.Message,
+			CreatedAt: tx.CreatedAt,
+		}
+
+		switch tx.Type {
+		case entity.TransactionTypePurchase, entity.TransactionTypeItemTransfer,
+			entity.TransactionTypeMarketSale, entity.TransactionTypeMarketFee,
+			entity.TransactionTypeRaffleTicket:
+			if tx.ItemID != nil {
+				merchItem, err := uc.merchRepo.GetByID(ctx, *tx.ItemID)
+				if err == nil {
+					itemName := merchItem.Name
+					info.ItemName = &itemName
+				}
+			} else if tx.BundleID != nil {
+				bundle, err := uc.merchRepo.GetBundleByID(ctx, *tx.BundleID)
+				if err == nil {
+					info.ItemName = &bundle.Name
+				}
 			}
+		}
+
+		if tx.ToUserID == userID {
 			received = append(received, info)
 		} else {
-			toUser, err := uc.userRepo.GetByID(ctx, tx.ToUserID)
-			if err != nil {
-				continue
-			}
-			otherUserName = toUser.Username
-			info = TransactionInfo{
-				ID:        tx.ID,
-				User:      otherUserName,
-				Amount:    tx.Amount,
-				Type:      tx.Type,
-				CreatedAt: tx.CreatedAt,
-			}
 			sent = append(sent, info)
 		}
-
-		if tx.Type == entity.TransactionTypePurchase && tx.ItemID != nil {
-			merchItem, err := uc.merchRepo.GetByID(ctx, *tx.ItemID)
-			if err == nil {
-				itemName := merchItem.Name
-				info.ItemName = &itemName
-			}
-		}
 	}
 	return TransactionHistory{
 		Received: received,
 		Sent:     sent,
 	}
 }
+
+// inventoryTitle renders an inventory row as "hoody (L) x2".
+func inventoryTitle(item InventoryItemDTO) string {
+	title := item.ItemName
+	if item.Variant != "" {
+		title += " (" + item.Variant + ")"
+	}
+	return fmt.Sprintf("%s x%d", title, item.Quantity)
+}
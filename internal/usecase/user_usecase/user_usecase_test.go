@@ -27,8 +27,11 @@ func TestRegister(t *testing.T) {
 	txRepo := mocks.NewMockTransactionRepository(ctrl)
 	invRepo := mocks.NewMockInventoryRepository(ctrl)
 	merchRepo := mocks.NewMockMerchRepository(ctrl)
+	lotRepo := mocks.NewMockLotRepository(ctrl)
+	orderRepo := mocks.NewMockOrderRepository(ctrl)
+	dbTransactor := mocks.NewMockDBTransactor(ctrl)
 
-	uc := NewUserUseCase(userRepo, txRepo, invRepo, merchRepo)
+	uc := NewUserUseCase(userRepo, txRepo, invRepo, merchRepo, lotRepo, orderRepo, dbTransactor)
 
 	tests := []test{
 		{
@@ -38,6 +41,12 @@ func TestRegister(t *testing.T) {
 					GetByUsername(gomock.Any(), "testuser").
 					Return(nil, nil)
 
+				dbTransactor.EXPECT().
+					WithinTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+
 				userRepo.EXPECT().
 					Create(gomock.Any(), gomock.Any()).
 					DoAndReturn(func(ctx context.Context, user *entity.User) error {
@@ -46,6 +55,14 @@ func TestRegister(t *testing.T) {
 						require.NotEmpty(t, user.PasswordHash)
 						return nil
 					})
+
+				lotRepo.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, lot *entity.CoinLot) error {
+						require.Equal(t, entity.InitialBalance, lot.Remaining)
+						require.Nil(t, lot.ExpiresAt)
+						return nil
+					})
 			},
 			res: "dummy_token_0_testuser",
 			err: nil,
@@ -92,8 +109,11 @@ func TestGetProfile(t *testing.T) {
 	txRepo := mocks.NewMockTransactionRepository(ctrl)
 	invRepo := mocks.NewMockInventoryRepository(ctrl)
 	merchRepo := mocks.NewMockMerchRepository(ctrl)
+	lotRepo := mocks.NewMockLotRepository(ctrl)
+	orderRepo := mocks.NewMockOrderRepository(ctrl)
+	dbTransactor := mocks.NewMockDBTransactor(ctrl)
 
-	uc := NewUserUseCase(userRepo, txRepo, invRepo, merchRepo)
+	uc := NewUserUseCase(userRepo, txRepo, invRepo, merchRepo, lotRepo, orderRepo, dbTransactor)
 
 	testTime := time.Date(2024, 2, 15, 12, 0, 0, 0, time.UTC)
 	userID := int64(1)
@@ -121,6 +141,9 @@ func TestGetProfile(t *testing.T) {
 		CreatedAt: testTime,
 	}
 
+	bundleID := int64(5)
+	bundleName := "welcome pack"
+
 	testTransactions := []entity.Transaction{
 		{
 			ID:         1,
@@ -138,8 +161,20 @@ func TestGetProfile(t *testing.T) {
 			Type:       entity.TransactionTypeTransfer,
 			CreatedAt:  testTime,
 		},
+		{
+			ID:         3,
+			FromUserID: userID,
+			ToUserID:   userID,
+			Amount:     90,
+			Type:       entity.TransactionTypePurchase,
+			BundleID:   &bundleID,
+			CreatedAt:  testTime,
+		},
 	}
 
+	expiresAt := testTime.AddDate(1, 0, 0)
+	expiresLater := expiresAt.AddDate(0, 1, 0)
+
 	senderUser := &entity.User{
 		ID:       2,
 		Username: "sender",
@@ -156,7 +191,8 @@ func TestGetProfile(t *testing.T) {
 			mock: func() {
 				userRepo.EXPECT().
 					GetByID(gomock.Any(), userID).
-					Return(testUser, nil)
+					Return(testUser, nil).
+					Times(2)
 
 				invRepo.EXPECT().
 					GetByUserID(gomock.Any(), userID).
@@ -164,18 +200,36 @@ func TestGetProfile(t *testing.T) {
 
 				merchRepo.EXPECT().
 					GetByID(gomock.Any(), int64(1)).
-					Return(testMerchItem, nil)
+					Return(testMerchItem, nil).
+					Times(2)
+
+				orderRepo.EXPECT().
+					ListByUser(gomock.Any(), userID).
+					Return([]entity.Order{
+						{ID: 4, UserID: userID, ItemID: 1, Price: 100, Status: entity.OrderStatusReadyForPickup, Location: "Moscow", CreatedAt: testTime, UpdatedAt: testTime},
+					}, nil)
 
 				txRepo.EXPECT().
 					GetByUserID(gomock.Any(), userID).
 					Return(testTransactions, nil)
 
+				lotRepo.EXPECT().
+					ListExpiring(gomock.Any(), userID).
+					Return([]entity.CoinLot{
+						{ID: 1, UserID: userID, Amount: 300, Remaining: 100, ExpiresAt: &expiresAt},
+						{ID: 2, UserID: userID, Amount: 200, Remaining: 200, ExpiresAt: &expiresAt},
+						{ID: 3, UserID: userID, Amount: 100, Remaining: 50, ExpiresAt: &expiresLater},
+					}, nil)
+
 				userRepo.EXPECT().
 					GetByID(gomock.Any(), int64(2)).
 					Return(senderUser, nil)
 				userRepo.EXPECT().
 					GetByID(gomock.Any(), int64(3)).
 					Return(receiverUser, nil)
+				merchRepo.EXPECT().
+					GetBundleByID(gomock.Any(), bundleID).
+					Return(entity.Bundle{ID: bundleID, Name: bundleName, Price: 90}, nil)
 			},
 			res: UserProfileDTO{
 				User: UserDTO{
@@ -188,6 +242,7 @@ func TestGetProfile(t *testing.T) {
 					{
 						ItemID:      1,
 						ItemName:    "Test Item",
+						Title:       "Test Item x2",
 						Quantity:    2,
 						PurchasedAt: testTime,
 					},
@@ -201,6 +256,14 @@ func TestGetProfile(t *testing.T) {
 							Type:      entity.TransactionTypeTransfer,
 							CreatedAt: testTime,
 						},
+						{
+							ID:        3,
+							User:      "testuser",
+							Amount:    90,
+							Type:      entity.TransactionTypePurchase,
+							ItemName:  &bundleName,
+							CreatedAt: testTime,
+						},
 					},
 					Sent: []TransactionInfo{
 						{
@@ -212,6 +275,21 @@ func TestGetProfile(t *testing.T) {
 						},
 					},
 				},
+				Expiring: []ExpiringCoinsDTO{
+					{Amount: 300, ExpiresAt: expiresAt},
+					{Amount: 50, ExpiresAt: expiresLater},
+				},
+				Orders: []OrderDTO{
+					{
+						ID:        4,
+						ItemName:  "Test Item",
+						Price:     100,
+						Status:    entity.OrderStatusReadyForPickup,
+						Location:  "Moscow",
+						CreatedAt: testTime,
+						UpdatedAt: testTime,
+					},
+				},
 			},
 			err: nil,
 		},
@@ -246,3 +324,27 @@ func TestGetProfile(t *testing.T) {
 		})
 	}
 }
+
+func TestSetPublicKudos(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	uc := NewUserUseCase(userRepo, mocks.NewMockTransactionRepository(ctrl), mocks.NewMockInventoryRepository(ctrl), mocks.NewMockMerchRepository(ctrl), mocks.NewMockLotRepository(ctrl), mocks.NewMockOrderRepository(ctrl), mocks.NewMockDBTransactor(ctrl))
+
+	userRepo.EXPECT().
+		SetPublicKudos(gomock.Any(), int64(1), true).
+		Return(nil)
+
+	err := uc.SetPublicKudos(context.Background(), 1, true)
+	require.NoError(t, err)
+
+	userRepo.EXPECT().
+		SetPublicKudos(gomock.Any(), int64(2), true).
+		Return(entity.ErrUserNotFound)
+
+	err = uc.SetPublicKudos(context.Background(), 2, true)
+	require.ErrorIs(t, err, entity.ErrUserNotFound)
+}
@@ -0,0 +1,8 @@
+package wishlist_usecase
+
+// AddItemRequest wishes for an item. Items that come in variants can be
+// narrowed to one with VariantID; otherwise any variant will do.
+type AddItemRequest struct {
+	ItemName  string `json:"item" validate:"required"`
+	VariantID *int64 `json:"variant_id,omitempty"`
+}
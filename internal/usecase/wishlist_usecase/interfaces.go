@@ -0,0 +1,44 @@
+package wishlist_usecase
+
+import (
+	"context"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"time"
+)
+
+//go:generate mockgen -source=interfaces.go -destination=./mocks/mocks.go -package=mocks
+
+type WishlistRepository interface {
+	Create(ctx context.Context, item *entity.WishlistItem) error
+	GetByID(ctx context.Context, id int64) (entity.WishlistItem, error)
+	ListByUser(ctx context.Context, userID int64) ([]entity.WishlistItem, error)
+	ListIDs(ctx context.Context, afterID int64, limit int) ([]int64, error)
+	UpdateState(ctx context.Context, id int64, state entity.WishlistState) error
+	Delete(ctx context.Context, userID, id int64) (bool, error)
+}
+
+type UserRepository interface {
+	GetByID(ctx context.Context, id int64) (*entity.User, error)
+}
+
+type MerchRepository interface {
+	GetByID(ctx context.Context, id int64) (entity.MerchItem, error)
+	GetByName(ctx context.Context, name string) (entity.MerchItem, error)
+	ListVariants(ctx context.Context, itemID int64) ([]entity.MerchVariant, error)
+	GetVariant(ctx context.Context, id int64) (entity.MerchVariant, error)
+}
+
+// PromoRepository prices wished items with the running sales, the same way
+// purchases are priced.
+type PromoRepository interface {
+	ActiveSales(ctx context.Context, itemID int64, now time.Time) ([]entity.MerchSale, error)
+}
+
+// Notifier delivers a short message to a user.
+type Notifier interface {
+	Notify(ctx context.Context, userID int64, message string) error
+}
+
+type DBTransactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
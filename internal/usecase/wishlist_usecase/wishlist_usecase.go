@@ -0,0 +1,262 @@
+package wishlist_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"time"
+)
+
+const _checkBatchSize = 100
+
+// WishlistUC keeps users' wishlists and tells them when a wished item gets
+// cheaper, comes back into stock or becomes affordable. Each entry stores
+// the state it was last seen in, so CheckWishlists only reports changes,
+// however often it runs.
+type WishlistUC struct {
+	wishlistRepo WishlistRepository
+	userRepo     UserRepository
+	merchRepo    MerchRepository
+	promoRepo    PromoRepository
+	notifier     Notifier
+	dbTx         DBTransactor
+}
+
+func NewWishlistUC(
+	wishlistRepo WishlistRepository,
+	userRepo UserRepository,
+	merchRepo MerchRepository,
+	promoRepo PromoRepository,
+	notifier Notifier,
+	dbTx DBTransactor,
+) *WishlistUC {
+	return &WishlistUC{
+		wishlistRepo: wishlistRepo,
+		userRepo:     userRepo,
+		merchRepo:    merchRepo,
+		promoRepo:    promoRepo,
+		notifier:     notifier,
+		dbTx:         dbTx,
+	}
+}
+
+// AddItem puts the item on the user's wishlist as it is now; only later
+// changes are notified.
+func (uc *WishlistUC) AddItem(ctx context.Context, userID int64, req AddItemRequest) (entity.WishlistItem, error) {
+	item, err := uc.merchRepo.GetByName(ctx, req.ItemName)
+	if err != nil {
+		return entity.WishlistItem{}, entity.ErrMerchNotFound
+	}
+
+	if req.VariantID != nil {
+		variant, err := uc.merchRepo.GetVariant(ctx, *req.VariantID)
+		if err != nil {
+			return entity.WishlistItem{}, err
+		}
+		if variant.ItemID != item.ID {
+			return entity.WishlistItem{}, entity.ErrVariantNotFound
+		}
+	}
+
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return entity.WishlistItem{}, err
+	}
+	if user == nil {
+		return entity.WishlistItem{}, entity.ErrUserNotFound
+	}
+
+	wished := entity.WishlistItem{
+		UserID:    userID,
+		ItemID:    item.ID,
+		VariantID: req.VariantID,
+		ItemName:  item.Name,
+	}
+	wished.WishlistState, _, err = uc.state(ctx, wished, user, time.Now())
+	if err != nil {
+		return entity.WishlistItem{}, err
+	}
+
+	if err := uc.wishlistRepo.Create(ctx, &wished); err != nil {
+		return entity.WishlistItem{}, err
+	}
+
+	return wished, nil
+}
+
+func (uc *WishlistUC) RemoveItem(ctx context.Context, userID, id int64) error {
+	deleted, err := uc.wishlistRepo.Delete(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return entity.ErrWishlistItemNotFound
+	}
+
+	return nil
+}
+
+func (uc *WishlistUC) ListWishlist(ctx context.Context, userID int64) ([]entity.WishlistItem, error) {
+	return uc.wishlistRepo.ListByUser(ctx, userID)
+}
+
+// CheckWishlists compares every wishlist entry with the item and the
+// user's balance as they are at now, notifies the user of what changed and
+// stores the new state. Each entry is checked in its own transaction. It
+// returns the number of notifications sent and is meant to be run by the
+// scheduler.
+func (uc *WishlistUC) CheckWishlists(ctx context.Context, now time.Time) (int, error) {
+	sent := 0
+	var errs []error
+
+	var afterID int64
+	for {
+		ids, err := uc.wishlistRepo.ListIDs(ctx, afterID, _checkBatchSize)
+		if err != nil {
+			return sent, errors.Join(append(errs, err)...)
+		}
+
+		for _, id := range ids {
+			var userID int64
+			var messages []string
+
+			err := uc.dbTx.WithinTransaction(ctx, func(ctx context.Context) error {
+				wished, err := uc.wishlistRepo.GetByID(ctx, id)
+				if err != nil {
+					return err
+				}
+
+				user, err := uc.userRepo.GetByID(ctx, wished.UserID)
+				if err != nil {
+					return err
+				}
+				if user == nil {
+					return entity.ErrUserNotFound
+				}
+
+				userID = wished.UserID
+				current, label, err := uc.state(ctx, wished, user, now)
+				if err != nil {
+					return err
+				}
+				if current == wished.WishlistState {
+					return nil
+				}
+
+				for _, event := range wished.Events(current) {
+					messages = append(messages, wishlistMessage(event, label, wished.Price, current.Price))
+				}
+
+				return uc.wishlistRepo.UpdateState(ctx, wished.ID, current)
+			})
+			if err != nil {
+				// removed since it was listed
+				if !errors.Is(err, entity.ErrWishlistItemNotFound) {
+					errs = append(errs, err)
+				}
+				continue
+			}
+
+			for _, message := range messages {
+				uc.notify(ctx, userID, message)
+				sent++
+			}
+		}
+
+		if len(ids) < _checkBatchSize {
+			break
+		}
+		afterID = ids[len(ids)-1]
+	}
+
+	return sent, errors.Join(errs...)
+}
+
+// state prices the wished item for the user at now, with the running sales
+// applied, and returns it with the label to show in notifications. A
+// released item without variants is always in stock; one wished for any
+// variant is in stock while some variant is, at the price of the cheapest
+// of those.
+func (uc *WishlistUC) state(ctx context.Context, wished entity.WishlistItem, user *entity.User, now time.Time) (entity.WishlistState, string, error) {
+	item, err := uc.merchRepo.GetByID(ctx, wished.ItemID)
+	if err != nil {
+		return entity.WishlistState{}, "", err
+	}
+
+	label := item.Name
+	price := item.Price
+	inStock := true
+
+	if wished.VariantID != nil {
+		variant, err := uc.merchRepo.GetVariant(ctx, *wished.VariantID)
+		if err != nil {
+			return entity.WishlistState{}, "", err
+		}
+		if l := variant.Label(); l != "" {
+			label = fmt.Sprintf("%s (%s)", item.Name, l)
+		}
+		price = variant.Price(item)
+		inStock = variant.InStock()
+	} else {
+		variants, err := uc.merchRepo.ListVariants(ctx, item.ID)
+		if err != nil {
+			return entity.WishlistState{}, "", err
+		}
+		if len(variants) > 0 {
+			price, inStock = cheapestVariant(item, variants)
+		}
+	}
+
+	// upcoming items cannot be bought yet, so their release counts as a
+	// restock
+	inStock = inStock && item.IsReleased(now)
+
+	sales, err := uc.promoRepo.ActiveSales(ctx, item.ID, now)
+	if err != nil {
+		return entity.WishlistState{}, "", err
+	}
+	price = entity.SalePrice(sales, item.ID, price, now)
+
+	return entity.WishlistState{
+		Price:      price,
+		InStock:    inStock,
+		Affordable: user.Coins >= price,
+	}, label, nil
+}
+
+// cheapestVariant returns the lowest price among the variants in stock, or
+// among all of them when none is.
+func cheapestVariant(item entity.MerchItem, variants []entity.MerchVariant) (int64, bool) {
+	price := int64(-1)
+	inStock := false
+	for _, v := range variants {
+		if v.InStock() && !inStock {
+			price, inStock = v.Price(item), true
+			continue
+		}
+		if v.InStock() != inStock {
+			continue
+		}
+		if price < 0 || v.Price(item) < price {
+			price = v.Price(item)
+		}
+	}
+	return price, inStock
+}
+
+func wishlistMessage(event entity.WishlistEvent, label string, was, now int64) string {
+	switch event {
+	case entity.WishlistEventPriceDrop:
+		return fmt.Sprintf("%s from your wishlist is now %d coins (was %d)", label, now, was)
+	case entity.WishlistEventRestock:
+		return fmt.Sprintf("%s from your wishlist is back in stock", label)
+	default:
+		return fmt.Sprintf("You can now afford %s from your wishlist for %d coins", label, now)
+	}
+}
+
+// notify is best-effort: the new state has already been committed.
+func (uc *WishlistUC) notify(ctx context.Context, userID int64, message string) {
+	_ = uc.notifier.Notify(ctx, userID, message)
+}
@@ -0,0 +1,192 @@
+package wishlist_usecase
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/smthjapanese/avito-merch/internal/entity"
+	"github.com/smthjapanese/avito-merch/internal/usecase/wishlist_usecase/mocks"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type test struct {
+	name string
+	mock func()
+	res  interface{}
+	err  error
+}
+
+var hoody = entity.MerchItem{ID: 1, Name: "hoody", Price: 300}
+
+func TestAddItem(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	wishlistRepo := mocks.NewMockWishlistRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	merchRepo := mocks.NewMockMerchRepository(ctrl)
+	promoRepo := mocks.NewMockPromoRepository(ctrl)
+
+	uc := NewWishlistUC(
+		wishlistRepo,
+		userRepo,
+		merchRepo,
+		promoRepo,
+		mocks.NewMockNotifier(ctrl),
+		mocks.NewMockDBTransactor(ctrl),
+	)
+
+	other := int64(7)
+
+	tests := []struct {
+		test
+		req AddItemRequest
+	}{
+		{
+			req: AddItemRequest{ItemName: "hoody"},
+			test: test{
+				name: "any variant, cheapest in stock",
+				mock: func() {
+					merchRepo.EXPECT().GetByName(gomock.Any(), "hoody").Return(hoody, nil)
+					userRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(&entity.User{ID: 2, Coins: 300}, nil)
+					merchRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(hoody, nil)
+					merchRepo.EXPECT().ListVariants(gomock.Any(), int64(1)).Return([]entity.MerchVariant{
+						{ID: 1, ItemID: 1, Size: "S", Stock: 0, PriceDelta: -100},
+						{ID: 2, ItemID: 1, Size: "L", Stock: 3, PriceDelta: 50},
+						{ID: 3, ItemID: 1, Size: "XL", Stock: 1, PriceDelta: 20},
+					}, nil)
+					promoRepo.EXPECT().ActiveSales(gomock.Any(), int64(1), gomock.Any()).Return(nil, nil)
+					wishlistRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				},
+				res: entity.WishlistState{Price: 320, InStock: true, Affordable: false},
+			},
+		},
+		{
+			req: AddItemRequest{ItemName: "hoody", VariantID: &other},
+			test: test{
+				name: "variant of another item",
+				mock: func() {
+					merchRepo.EXPECT().GetByName(gomock.Any(), "hoody").Return(hoody, nil)
+					merchRepo.EXPECT().GetVariant(gomock.Any(), other).Return(entity.MerchVariant{ID: other, ItemID: 5}, nil)
+				},
+				err: entity.ErrVariantNotFound,
+			},
+		},
+		{
+			req: AddItemRequest{ItemName: "hoody"},
+			test: test{
+				name: "already wished",
+				mock: func() {
+					merchRepo.EXPECT().GetByName(gomock.Any(), "hoody").Return(hoody, nil)
+					userRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(&entity.User{ID: 2, Coins: 300}, nil)
+					merchRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(hoody, nil)
+					merchRepo.EXPECT().ListVariants(gomock.Any(), int64(1)).Return(nil, nil)
+					promoRepo.EXPECT().ActiveSales(gomock.Any(), int64(1), gomock.Any()).Return(nil, nil)
+					wishlistRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(entity.ErrAlreadyWishlisted)
+				},
+				err: entity.ErrAlreadyWishlisted,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			wished, err := uc.AddItem(context.Background(), 2, tc.req)
+
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.res, wished.WishlistState)
+			}
+		})
+	}
+}
+
+func TestCheckWishlists(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	wishlistRepo := mocks.NewMockWishlistRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	merchRepo := mocks.NewMockMerchRepository(ctrl)
+	promoRepo := mocks.NewMockPromoRepository(ctrl)
+	notifier := mocks.NewMockNotifier(ctrl)
+	dbTransactor := mocks.NewMockDBTransactor(ctrl)
+
+	uc := NewWishlistUC(
+		wishlistRepo,
+		userRepo,
+		merchRepo,
+		promoRepo,
+		notifier,
+		dbTransactor,
+	)
+
+	now := time.Now()
+	variantID := int64(4)
+
+	wishlistRepo.EXPECT().ListIDs(gomock.Any(), int64(0), _checkBatchSize).Return([]int64{1, 2}, nil)
+	dbTransactor.EXPECT().
+		WithinTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		}).
+		Times(2)
+	merchRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(hoody, nil).Times(2)
+	promoRepo.EXPECT().ActiveSales(gomock.Any(), int64(1), now).Return(nil, nil).Times(2)
+
+	// the L hoody got cheaper, came back and the user can pay for it now
+	wishlistRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(entity.WishlistItem{
+		ID: 1, UserID: 2, ItemID: 1, VariantID: &variantID,
+		WishlistState: entity.WishlistState{Price: 400, InStock: false, Affordable: false},
+	}, nil)
+	userRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(&entity.User{ID: 2, Coins: 350}, nil)
+	merchRepo.EXPECT().GetVariant(gomock.Any(), variantID).Return(entity.MerchVariant{ID: 4, ItemID: 1, Size: "L", Stock: 2}, nil)
+	wishlistRepo.EXPECT().UpdateState(gomock.Any(), int64(1), entity.WishlistState{Price: 300, InStock: true, Affordable: true}).Return(nil)
+	notifier.EXPECT().Notify(gomock.Any(), int64(2), "hoody (L) from your wishlist is now 300 coins (was 400)").Return(nil)
+	notifier.EXPECT().Notify(gomock.Any(), int64(2), "hoody (L) from your wishlist is back in stock").Return(nil)
+	notifier.EXPECT().Notify(gomock.Any(), int64(2), "You can now afford hoody (L) from your wishlist for 300 coins").Return(nil)
+
+	// nothing changed for the second entry
+	wishlistRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(entity.WishlistItem{
+		ID: 2, UserID: 3, ItemID: 1,
+		WishlistState: entity.WishlistState{Price: 300, InStock: true, Affordable: false},
+	}, nil)
+	userRepo.EXPECT().GetByID(gomock.Any(), int64(3)).Return(&entity.User{ID: 3, Coins: 10}, nil)
+	merchRepo.EXPECT().ListVariants(gomock.Any(), int64(1)).Return(nil, nil)
+
+	sent, err := uc.CheckWishlists(context.Background(), now)
+	require.NoError(t, err)
+	require.Equal(t, 3, sent)
+}
+
+func TestRemoveItem(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	wishlistRepo := mocks.NewMockWishlistRepository(ctrl)
+
+	uc := NewWishlistUC(
+		wishlistRepo,
+		mocks.NewMockUserRepository(ctrl),
+		mocks.NewMockMerchRepository(ctrl),
+		mocks.NewMockPromoRepository(ctrl),
+		mocks.NewMockNotifier(ctrl),
+		mocks.NewMockDBTransactor(ctrl),
+	)
+
+	wishlistRepo.EXPECT().Delete(gomock.Any(), int64(2), int64(5)).Return(false, nil)
+
+	err := uc.RemoveItem(context.Background(), 2, 5)
+	require.ErrorIs(t, err, entity.ErrWishlistItemNotFound)
+}
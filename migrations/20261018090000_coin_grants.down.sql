@@ -0,0 +1,19 @@
+BEGIN;
+
+DROP TABLE IF EXISTS allowance_payouts;
+
+DELETE FROM transactions WHERE type = 'grant';
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_type_check;
+ALTER TABLE transactions
+    ADD CONSTRAINT transactions_type_check CHECK (type IN ('transfer', 'purchase'));
+
+DELETE FROM users WHERE role = 'system';
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE users
+    DROP COLUMN IF EXISTS role,
+    DROP COLUMN IF EXISTS team_id;
+
+DROP TABLE IF EXISTS teams;
+
+COMMIT;
@@ -0,0 +1,38 @@
+BEGIN;
+
+-- Команды пользователей
+CREATE TABLE IF NOT EXISTS teams (
+                                     id SERIAL PRIMARY KEY,
+                                     name VARCHAR(255) UNIQUE NOT NULL,
+                                     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS team_id INTEGER REFERENCES teams(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS role VARCHAR(50) NOT NULL DEFAULT 'user';
+
+ALTER TABLE users
+    ADD CONSTRAINT users_role_check CHECK (role IN ('user', 'admin', 'system'));
+
+-- Системный аккаунт, от имени которого начисляются монеты
+INSERT INTO users (username, password_hash, coins, role)
+VALUES ('system', '', 0, 'system')
+ON CONFLICT (username) DO NOTHING;
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_type_check;
+ALTER TABLE transactions
+    ADD CONSTRAINT transactions_type_check CHECK (type IN ('transfer', 'purchase', 'grant'));
+
+-- Выплаты ежемесячного пособия, не более одной на пользователя за период
+CREATE TABLE IF NOT EXISTS allowance_payouts (
+                                                 id SERIAL PRIMARY KEY,
+                                                 user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                                 period VARCHAR(7) NOT NULL,
+                                                 amount INTEGER NOT NULL CHECK (amount > 0),
+                                                 created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+                                                 CONSTRAINT unique_user_period UNIQUE(user_id, period)
+);
+
+CREATE INDEX IF NOT EXISTS idx_users_team ON users(team_id);
+
+COMMIT;
@@ -0,0 +1,18 @@
+BEGIN;
+
+DROP TABLE IF EXISTS rewards;
+DROP TABLE IF EXISTS reward_budgets;
+
+DELETE FROM transactions WHERE type = 'reward';
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_type_check;
+ALTER TABLE transactions
+    ADD CONSTRAINT transactions_type_check CHECK (type IN ('transfer', 'purchase', 'grant'));
+
+UPDATE users SET role = 'user' WHERE role = 'manager';
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE users
+    ADD CONSTRAINT users_role_check CHECK (role IN ('user', 'admin', 'system'));
+
+ALTER TABLE users DROP COLUMN IF EXISTS manager_id;
+
+COMMIT;
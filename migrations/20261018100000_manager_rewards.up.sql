@@ -0,0 +1,36 @@
+BEGIN;
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS manager_id INTEGER REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE users
+    ADD CONSTRAINT users_role_check CHECK (role IN ('user', 'admin', 'manager', 'system'));
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_type_check;
+ALTER TABLE transactions
+    ADD CONSTRAINT transactions_type_check CHECK (type IN ('transfer', 'purchase', 'grant', 'reward'));
+
+-- Бюджеты менеджеров на поощрение сотрудников, отдельные от личного баланса
+CREATE TABLE IF NOT EXISTS reward_budgets (
+                                              user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+                                              allocation INTEGER NOT NULL CHECK (allocation >= 0),
+                                              balance INTEGER NOT NULL CHECK (balance >= 0),
+                                              period VARCHAR(7) NOT NULL,
+                                              updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS rewards (
+                                       id SERIAL PRIMARY KEY,
+                                       manager_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+                                       user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+                                       amount INTEGER NOT NULL CHECK (amount > 0),
+                                       reason VARCHAR(255) NOT NULL,
+                                       period VARCHAR(7) NOT NULL,
+                                       created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_users_manager ON users(manager_id);
+CREATE INDEX IF NOT EXISTS idx_rewards_manager_period ON rewards(manager_id, period);
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_transactions_kudos;
+
+ALTER TABLE users DROP COLUMN IF EXISTS public_kudos;
+ALTER TABLE transactions DROP COLUMN IF EXISTS message;
+
+COMMIT;
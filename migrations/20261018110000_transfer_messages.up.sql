@@ -0,0 +1,13 @@
+BEGIN;
+
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS message VARCHAR(280);
+
+-- Согласие пользователя показывать его переводы в публичной ленте благодарностей
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS public_kudos BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_transactions_kudos ON transactions(created_at DESC)
+    WHERE type = 'transfer' AND message IS NOT NULL;
+
+COMMIT;
@@ -0,0 +1,22 @@
+BEGIN;
+
+-- Запросы монет у коллег
+CREATE TABLE IF NOT EXISTS coin_requests (
+                                             id SERIAL PRIMARY KEY,
+                                             requester_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                             payer_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                             amount INTEGER NOT NULL CHECK (amount > 0),
+                                             reason VARCHAR(280) NOT NULL DEFAULT '',
+                                             status VARCHAR(50) NOT NULL DEFAULT 'pending'
+                                                 CHECK (status IN ('pending', 'accepted', 'declined', 'expired', 'cancelled')),
+                                             expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+                                             created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+                                             resolved_at TIMESTAMP WITH TIME ZONE,
+                                             CONSTRAINT coin_requests_distinct_users CHECK (requester_id <> payer_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_coin_requests_payer ON coin_requests(payer_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_coin_requests_requester ON coin_requests(requester_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_coin_requests_pending ON coin_requests(expires_at) WHERE status = 'pending';
+
+COMMIT;
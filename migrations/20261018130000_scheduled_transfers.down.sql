@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS scheduled_transfer_runs;
+DROP TABLE IF EXISTS scheduled_transfers;
+
+COMMIT;
@@ -0,0 +1,33 @@
+BEGIN;
+
+-- Отложенные и регулярные переводы
+CREATE TABLE IF NOT EXISTS scheduled_transfers (
+                                                   id SERIAL PRIMARY KEY,
+                                                   from_user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                                   to_user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                                   amount INTEGER NOT NULL CHECK (amount > 0),
+                                                   message VARCHAR(280),
+                                                   recurrence VARCHAR(50) NOT NULL DEFAULT ''
+                                                       CHECK (recurrence IN ('', 'daily', 'weekly', 'monthly')),
+                                                   first_run_at TIMESTAMP WITH TIME ZONE NOT NULL,
+                                                   next_run_at TIMESTAMP WITH TIME ZONE NOT NULL,
+                                                   status VARCHAR(50) NOT NULL DEFAULT 'active'
+                                                       CHECK (status IN ('active', 'completed', 'cancelled')),
+                                                   created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Журнал запусков; уникальность (перевод, время запуска) делает запуск идемпотентным
+CREATE TABLE IF NOT EXISTS scheduled_transfer_runs (
+                                                       id SERIAL PRIMARY KEY,
+                                                       scheduled_transfer_id INTEGER NOT NULL REFERENCES scheduled_transfers(id) ON DELETE CASCADE,
+                                                       run_at TIMESTAMP WITH TIME ZONE NOT NULL,
+                                                       status VARCHAR(50) NOT NULL CHECK (status IN ('succeeded', 'failed')),
+                                                       failure_reason VARCHAR(255),
+                                                       created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+                                                       CONSTRAINT unique_scheduled_transfer_run UNIQUE(scheduled_transfer_id, run_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_transfers_due ON scheduled_transfers(next_run_at) WHERE status = 'active';
+CREATE INDEX IF NOT EXISTS idx_scheduled_transfers_from_user ON scheduled_transfers(from_user_id);
+
+COMMIT;
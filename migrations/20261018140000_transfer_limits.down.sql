@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_transactions_outgoing;
+DROP TABLE IF EXISTS transfer_limit_overrides;
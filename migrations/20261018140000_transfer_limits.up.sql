@@ -0,0 +1,17 @@
+BEGIN;
+
+-- Индивидуальные лимиты переводов, заданные администратором.
+-- NULL означает лимит по умолчанию из конфигурации, 0 — без ограничений.
+CREATE TABLE IF NOT EXISTS transfer_limit_overrides (
+                                                        user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+                                                        per_transfer INTEGER CHECK (per_transfer >= 0),
+                                                        daily INTEGER CHECK (daily >= 0),
+                                                        per_recipient INTEGER CHECK (per_recipient >= 0),
+                                                        updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Суммы исходящих переводов за скользящие сутки
+CREATE INDEX IF NOT EXISTS idx_transactions_outgoing ON transactions(from_user_id, created_at)
+    WHERE type = 'transfer';
+
+COMMIT;
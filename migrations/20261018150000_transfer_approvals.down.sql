@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS pending_transfers;
+
+ALTER TABLE users DROP COLUMN IF EXISTS held_coins;
+
+COMMIT;
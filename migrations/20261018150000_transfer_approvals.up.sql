@@ -0,0 +1,26 @@
+BEGIN;
+
+-- Монеты, зарезервированные под ожидающие операции: их нельзя потратить,
+-- но они всё ещё принадлежат пользователю
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS held_coins INTEGER NOT NULL DEFAULT 0 CHECK (held_coins >= 0);
+
+-- Крупные переводы, ожидающие одобрения администратора или руководителя
+CREATE TABLE IF NOT EXISTS pending_transfers (
+                                                 id SERIAL PRIMARY KEY,
+                                                 from_user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                                 to_user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                                 amount INTEGER NOT NULL CHECK (amount > 0),
+                                                 message VARCHAR(280),
+                                                 status VARCHAR(50) NOT NULL DEFAULT 'pending'
+                                                     CHECK (status IN ('pending', 'approved', 'rejected', 'expired')),
+                                                 expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+                                                 decided_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+                                                 created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+                                                 decided_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_pending_transfers_from_user ON pending_transfers(from_user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_pending_transfers_pending ON pending_transfers(expires_at) WHERE status = 'pending';
+
+COMMIT;
@@ -0,0 +1,24 @@
+BEGIN;
+
+-- Срабатывания антифрод-правил для разбора администратором
+CREATE TABLE IF NOT EXISTS fraud_flags (
+                                           id SERIAL PRIMARY KEY,
+                                           user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                           counterpart_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+                                           item_id INTEGER REFERENCES merch_items(id) ON DELETE SET NULL,
+                                           type VARCHAR(50) NOT NULL,
+                                           amount INTEGER NOT NULL,
+                                           rule VARCHAR(100) NOT NULL,
+                                           action VARCHAR(50) NOT NULL CHECK (action IN ('flag', 'hold', 'block')),
+                                           reason TEXT NOT NULL DEFAULT '',
+                                           status VARCHAR(50) NOT NULL DEFAULT 'open'
+                                               CHECK (status IN ('open', 'dismissed', 'confirmed')),
+                                           reviewed_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+                                           created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+                                           reviewed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_fraud_flags_status ON fraud_flags(status, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_fraud_flags_user ON fraud_flags(user_id);
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+DROP TABLE IF EXISTS coin_lots;
+
+DELETE FROM transactions WHERE type = 'expiry';
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_type_check;
+ALTER TABLE transactions
+    ADD CONSTRAINT transactions_type_check CHECK (type IN ('transfer', 'purchase', 'grant', 'reward'));
+
+COMMIT;
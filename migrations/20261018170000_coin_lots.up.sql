@@ -0,0 +1,28 @@
+BEGIN;
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_type_check;
+ALTER TABLE transactions
+    ADD CONSTRAINT transactions_type_check CHECK (type IN ('transfer', 'purchase', 'grant', 'reward', 'expiry'));
+
+-- Партии монет: баланс пользователя складывается из остатков партий,
+-- списание идёт с самых старых, а партии с истёкшим сроком сгорают
+CREATE TABLE IF NOT EXISTS coin_lots (
+                                         id SERIAL PRIMARY KEY,
+                                         user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                         amount INTEGER NOT NULL CHECK (amount > 0),
+                                         remaining INTEGER NOT NULL CHECK (remaining >= 0 AND remaining <= amount),
+                                         granted_at TIMESTAMP WITH TIME ZONE NOT NULL,
+                                         expires_at TIMESTAMP WITH TIME ZONE,
+                                         created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_coin_lots_user_open ON coin_lots(user_id, granted_at, id) WHERE remaining > 0;
+CREATE INDEX IF NOT EXISTS idx_coin_lots_expiring ON coin_lots(expires_at) WHERE remaining > 0 AND expires_at IS NOT NULL;
+
+-- Уже начисленные монеты переносятся бессрочной партией
+INSERT INTO coin_lots (user_id, amount, remaining, granted_at)
+SELECT id, coins + held_coins, coins + held_coins, COALESCE(created_at, CURRENT_TIMESTAMP)
+FROM users
+WHERE coins + held_coins > 0;
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+ALTER TABLE merch_items
+    DROP COLUMN IF EXISTS search_vector,
+    DROP COLUMN IF EXISTS tags,
+    DROP COLUMN IF EXISTS image_url,
+    DROP COLUMN IF EXISTS description,
+    DROP COLUMN IF EXISTS category_id;
+
+DROP TABLE IF EXISTS merch_categories;
+
+COMMIT;
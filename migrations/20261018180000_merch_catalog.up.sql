@@ -0,0 +1,28 @@
+BEGIN;
+
+-- Категории каталога мерча
+CREATE TABLE IF NOT EXISTS merch_categories (
+                                                id SERIAL PRIMARY KEY,
+                                                name VARCHAR(100) UNIQUE NOT NULL,
+                                                created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE merch_items
+    ADD COLUMN IF NOT EXISTS category_id INTEGER REFERENCES merch_categories(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS description TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS image_url VARCHAR(2048),
+    ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+
+-- Поисковый вектор по названию и описанию; название весит больше
+ALTER TABLE merch_items
+    ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', name), 'A') ||
+        setweight(to_tsvector('english', description), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_merch_items_search ON merch_items USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_merch_items_tags ON merch_items USING GIN (tags);
+CREATE INDEX IF NOT EXISTS idx_merch_items_category ON merch_items(category_id);
+CREATE INDEX IF NOT EXISTS idx_merch_items_price ON merch_items(price);
+
+COMMIT;
@@ -0,0 +1,21 @@
+BEGIN;
+
+DROP INDEX IF EXISTS unique_user_item_variant;
+
+-- Строки разных вариантов одного товара сворачиваются обратно в одну
+WITH merged AS (
+    DELETE FROM user_inventory
+    WHERE variant_id IS NOT NULL
+    RETURNING user_id, item_id, quantity, purchased_at
+)
+INSERT INTO user_inventory (user_id, item_id, quantity, purchased_at)
+SELECT user_id, item_id, SUM(quantity), MIN(purchased_at)
+FROM merged
+GROUP BY user_id, item_id
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE user_inventory DROP COLUMN IF EXISTS variant_id;
+
+DROP TABLE IF EXISTS merch_variants;
+
+COMMIT;
@@ -0,0 +1,25 @@
+BEGIN;
+
+-- Варианты товара (размер, цвет) со своим остатком и надбавкой к цене
+CREATE TABLE IF NOT EXISTS merch_variants (
+                                              id SERIAL PRIMARY KEY,
+                                              item_id INTEGER NOT NULL REFERENCES merch_items(id) ON DELETE CASCADE,
+                                              size VARCHAR(20) NOT NULL DEFAULT '',
+                                              color VARCHAR(50) NOT NULL DEFAULT '',
+                                              stock INTEGER NOT NULL DEFAULT 0 CHECK (stock >= 0),
+                                              price_delta INTEGER NOT NULL DEFAULT 0,
+                                              created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+                                              CONSTRAINT unique_item_variant UNIQUE(item_id, size, color)
+);
+
+CREATE INDEX IF NOT EXISTS idx_merch_variants_item ON merch_variants(item_id);
+
+-- Инвентарь учитывает вариант: одна строка на товар и вариант
+ALTER TABLE user_inventory
+    ADD COLUMN IF NOT EXISTS variant_id INTEGER REFERENCES merch_variants(id) ON DELETE SET NULL;
+
+ALTER TABLE user_inventory DROP CONSTRAINT IF EXISTS unique_user_item;
+CREATE UNIQUE INDEX IF NOT EXISTS unique_user_item_variant
+    ON user_inventory(user_id, item_id, COALESCE(variant_id, 0));
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP TABLE IF EXISTS merch_sales;
+DROP TABLE IF EXISTS promo_redemptions;
+DROP TABLE IF EXISTS promo_codes;
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS list_price;
+
+COMMIT;
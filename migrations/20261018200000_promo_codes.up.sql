@@ -0,0 +1,50 @@
+BEGIN;
+
+-- Цена покупки до скидок; amount хранит фактически уплаченную сумму
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS list_price INTEGER CHECK (list_price > 0);
+
+-- Промокоды: процент или фиксированная скидка, на товар или на весь каталог
+CREATE TABLE IF NOT EXISTS promo_codes (
+                                           id SERIAL PRIMARY KEY,
+                                           code VARCHAR(50) UNIQUE NOT NULL,
+                                           kind VARCHAR(20) NOT NULL CHECK (kind IN ('percent', 'fixed')),
+                                           value INTEGER NOT NULL CHECK (value > 0),
+                                           item_id INTEGER REFERENCES merch_items(id) ON DELETE CASCADE,
+                                           max_uses INTEGER CHECK (max_uses > 0),
+                                           uses INTEGER NOT NULL DEFAULT 0 CHECK (uses >= 0),
+                                           once_per_user BOOLEAN NOT NULL DEFAULT FALSE,
+                                           starts_at TIMESTAMP WITH TIME ZONE,
+                                           ends_at TIMESTAMP WITH TIME ZONE,
+                                           created_by INTEGER NOT NULL REFERENCES users(id),
+                                           created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+                                           CONSTRAINT promo_codes_percent_check CHECK (kind <> 'percent' OR value <= 100),
+                                           CONSTRAINT promo_codes_window_check CHECK (starts_at IS NULL OR ends_at IS NULL OR ends_at > starts_at)
+);
+
+-- Погашения промокодов, по ним проверяется одноразовость для пользователя
+CREATE TABLE IF NOT EXISTS promo_redemptions (
+                                                 id SERIAL PRIMARY KEY,
+                                                 code_id INTEGER NOT NULL REFERENCES promo_codes(id) ON DELETE CASCADE,
+                                                 user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                                 created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_promo_redemptions_code_user ON promo_redemptions(code_id, user_id);
+
+-- Запланированные распродажи
+CREATE TABLE IF NOT EXISTS merch_sales (
+                                           id SERIAL PRIMARY KEY,
+                                           item_id INTEGER REFERENCES merch_items(id) ON DELETE CASCADE,
+                                           kind VARCHAR(20) NOT NULL CHECK (kind IN ('percent', 'fixed')),
+                                           value INTEGER NOT NULL CHECK (value > 0),
+                                           starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+                                           ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+                                           created_by INTEGER NOT NULL REFERENCES users(id),
+                                           created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+                                           CONSTRAINT merch_sales_percent_check CHECK (kind <> 'percent' OR value <= 100),
+                                           CONSTRAINT merch_sales_window_check CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_merch_sales_window ON merch_sales(starts_at, ends_at);
+
+COMMIT;
@@ -0,0 +1,16 @@
+BEGIN;
+
+-- Возвращаем в merch_items цену, действующую на момент отката
+UPDATE merch_items m
+SET price = p.price
+FROM (
+    SELECT DISTINCT ON (item_id) item_id, price
+    FROM merch_prices
+    WHERE effective_from <= CURRENT_TIMESTAMP
+    ORDER BY item_id, effective_from DESC
+) p
+WHERE p.item_id = m.id;
+
+DROP TABLE IF EXISTS merch_prices;
+
+COMMIT;
@@ -0,0 +1,21 @@
+BEGIN;
+
+-- История цен мерча: цена товара в момент t — последняя запись с effective_from <= t.
+-- Записи с effective_from в будущем — запланированные изменения цены.
+CREATE TABLE IF NOT EXISTS merch_prices (
+                                            id SERIAL PRIMARY KEY,
+                                            item_id INTEGER NOT NULL REFERENCES merch_items(id) ON DELETE CASCADE,
+                                            price INTEGER NOT NULL CHECK (price > 0),
+                                            effective_from TIMESTAMP WITH TIME ZONE NOT NULL,
+                                            created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+                                            created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+                                            CONSTRAINT unique_item_price_from UNIQUE(item_id, effective_from)
+);
+
+-- Текущие цены становятся первой записью истории
+INSERT INTO merch_prices (item_id, price, effective_from)
+SELECT id, price, COALESCE(created_at, CURRENT_TIMESTAMP)
+FROM merch_items
+ON CONFLICT DO NOTHING;
+
+COMMIT;
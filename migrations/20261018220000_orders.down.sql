@@ -0,0 +1,11 @@
+BEGIN;
+
+DROP TABLE IF EXISTS orders;
+DROP TABLE IF EXISTS pickup_locations;
+
+DELETE FROM transactions WHERE type = 'refund';
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_type_check;
+ALTER TABLE transactions
+    ADD CONSTRAINT transactions_type_check CHECK (type IN ('transfer', 'purchase', 'grant', 'reward', 'expiry'));
+
+COMMIT;
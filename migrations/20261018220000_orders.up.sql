@@ -0,0 +1,34 @@
+BEGIN;
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_type_check;
+ALTER TABLE transactions
+    ADD CONSTRAINT transactions_type_check CHECK (type IN ('transfer', 'purchase', 'grant', 'reward', 'expiry', 'refund'));
+
+-- Пункты выдачи мерча (офисы)
+CREATE TABLE IF NOT EXISTS pickup_locations (
+                                                id SERIAL PRIMARY KEY,
+                                                name VARCHAR(100) UNIQUE NOT NULL,
+                                                address VARCHAR(255) NOT NULL DEFAULT '',
+                                                created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Заказы: каждая покупка создаёт заказ, который проходит путь
+-- placed -> packed -> ready_for_pickup -> delivered либо отменяется
+CREATE TABLE IF NOT EXISTS orders (
+                                      id SERIAL PRIMARY KEY,
+                                      user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                      item_id INTEGER NOT NULL REFERENCES merch_items(id),
+                                      variant_id INTEGER REFERENCES merch_variants(id) ON DELETE SET NULL,
+                                      price INTEGER NOT NULL CHECK (price > 0),
+                                      location_id INTEGER REFERENCES pickup_locations(id),
+                                      status VARCHAR(20) NOT NULL DEFAULT 'placed'
+                                          CHECK (status IN ('placed', 'packed', 'ready_for_pickup', 'delivered', 'cancelled')),
+                                      updated_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+                                      created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+                                      updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_orders_user ON orders(user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_orders_open ON orders(status, created_at) WHERE status NOT IN ('delivered', 'cancelled');
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS redemptions;
+
+ALTER TABLE user_inventory DROP CONSTRAINT IF EXISTS user_inventory_quantity_check;
+
+COMMIT;
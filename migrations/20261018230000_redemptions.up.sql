@@ -0,0 +1,30 @@
+BEGIN;
+
+ALTER TABLE user_inventory DROP CONSTRAINT IF EXISTS user_inventory_quantity_check;
+ALTER TABLE user_inventory ADD CONSTRAINT user_inventory_quantity_check CHECK (quantity >= 0);
+
+-- Заявки на получение предметов из инвентаря: доставка по адресу или выдача в офисе
+CREATE TABLE IF NOT EXISTS redemptions (
+                                           id SERIAL PRIMARY KEY,
+                                           user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                           item_id INTEGER NOT NULL REFERENCES merch_items(id),
+                                           variant_id INTEGER REFERENCES merch_variants(id) ON DELETE SET NULL,
+                                           quantity INTEGER NOT NULL CHECK (quantity > 0),
+                                           method VARCHAR(20) NOT NULL CHECK (method IN ('delivery', 'pickup')),
+                                           address VARCHAR(500) NOT NULL DEFAULT '',
+                                           location_id INTEGER REFERENCES pickup_locations(id),
+                                           status VARCHAR(20) NOT NULL DEFAULT 'pending'
+                                               CHECK (status IN ('pending', 'fulfilled', 'cancelled')),
+                                           resolved_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+                                           resolved_at TIMESTAMP WITH TIME ZONE,
+                                           created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+                                           CONSTRAINT redemptions_destination_check CHECK (
+                                               (method = 'delivery' AND address <> '') OR
+                                               (method = 'pickup' AND location_id IS NOT NULL)
+                                           )
+);
+
+CREATE INDEX IF NOT EXISTS idx_redemptions_user ON redemptions(user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_redemptions_pending ON redemptions(created_at) WHERE status = 'pending';
+
+COMMIT;
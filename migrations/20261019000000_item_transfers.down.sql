@@ -0,0 +1,15 @@
+BEGIN;
+
+DELETE FROM transactions WHERE type = 'item_transfer';
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS quantity;
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_amount_check;
+ALTER TABLE transactions
+    ADD CONSTRAINT transactions_amount_check CHECK (amount > 0);
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_type_check;
+ALTER TABLE transactions
+    ADD CONSTRAINT transactions_type_check CHECK (type IN ('transfer', 'purchase', 'grant', 'reward', 'expiry', 'refund'));
+
+COMMIT;
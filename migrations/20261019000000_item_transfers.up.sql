@@ -0,0 +1,17 @@
+BEGIN;
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_type_check;
+ALTER TABLE transactions
+    ADD CONSTRAINT transactions_type_check CHECK (type IN ('transfer', 'purchase', 'grant', 'reward', 'expiry', 'refund', 'item_transfer'));
+
+-- Передача мерча между сотрудниками не двигает монеты, поэтому сумма
+-- у неё нулевая; все остальные операции по-прежнему строго положительные
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_amount_check;
+ALTER TABLE transactions
+    ADD CONSTRAINT transactions_amount_check CHECK (amount > 0 OR (type = 'item_transfer' AND amount = 0));
+
+-- Сколько штук передано (заполняется для item_transfer)
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS quantity INTEGER CHECK (quantity > 0);
+
+COMMIT;
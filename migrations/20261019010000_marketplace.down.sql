@@ -0,0 +1,18 @@
+BEGIN;
+
+-- Возвращаем продавцам предметы из ещё активных объявлений
+INSERT INTO user_inventory (user_id, item_id, variant_id, quantity)
+SELECT seller_id, item_id, variant_id, quantity
+FROM market_listings
+WHERE status = 'active'
+ON CONFLICT (user_id, item_id, COALESCE(variant_id, 0)) DO UPDATE
+    SET quantity = user_inventory.quantity + EXCLUDED.quantity;
+
+DROP TABLE IF EXISTS market_listings;
+
+DELETE FROM transactions WHERE type IN ('market_sale', 'market_fee');
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_type_check;
+ALTER TABLE transactions
+    ADD CONSTRAINT transactions_type_check CHECK (type IN ('transfer', 'purchase', 'grant', 'reward', 'expiry', 'refund', 'item_transfer'));
+
+COMMIT;
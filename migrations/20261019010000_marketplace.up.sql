@@ -0,0 +1,29 @@
+BEGIN;
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_type_check;
+ALTER TABLE transactions
+    ADD CONSTRAINT transactions_type_check CHECK (type IN ('transfer', 'purchase', 'grant', 'reward', 'expiry', 'refund', 'item_transfer', 'market_sale', 'market_fee'));
+
+-- Объявления о перепродаже мерча внутри компании. Пока объявление активно,
+-- предметы изъяты из инвентаря продавца
+CREATE TABLE IF NOT EXISTS market_listings (
+                                               id SERIAL PRIMARY KEY,
+                                               seller_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                               item_id INTEGER NOT NULL REFERENCES merch_items(id),
+                                               variant_id INTEGER REFERENCES merch_variants(id) ON DELETE SET NULL,
+                                               quantity INTEGER NOT NULL CHECK (quantity > 0),
+                                               price INTEGER NOT NULL CHECK (price > 0),
+                                               status VARCHAR(20) NOT NULL DEFAULT 'active'
+                                                   CHECK (status IN ('active', 'sold', 'cancelled', 'expired')),
+                                               buyer_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+                                               fee INTEGER NOT NULL DEFAULT 0 CHECK (fee >= 0),
+                                               expires_at TIMESTAMP WITH TIME ZONE,
+                                               closed_at TIMESTAMP WITH TIME ZONE,
+                                               created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_market_listings_active ON market_listings(item_id, price) WHERE status = 'active';
+CREATE INDEX IF NOT EXISTS idx_market_listings_expires ON market_listings(expires_at) WHERE status = 'active';
+CREATE INDEX IF NOT EXISTS idx_market_listings_seller ON market_listings(seller_id, created_at);
+
+COMMIT;
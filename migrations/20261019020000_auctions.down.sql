@@ -0,0 +1,18 @@
+BEGIN;
+
+-- Снимаем удержание лидирующих ставок открытых аукционов
+UPDATE users u
+SET coins = u.coins + h.held,
+    held_coins = u.held_coins - h.held
+FROM (
+         SELECT high_bidder_id, SUM(high_bid) AS held
+         FROM auctions
+         WHERE status = 'open' AND high_bidder_id IS NOT NULL
+         GROUP BY high_bidder_id
+     ) h
+WHERE h.high_bidder_id = u.id;
+
+DROP TABLE IF EXISTS auction_bids;
+DROP TABLE IF EXISTS auctions;
+
+COMMIT;
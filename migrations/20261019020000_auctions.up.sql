@@ -0,0 +1,34 @@
+BEGIN;
+
+-- Аукционы на лимитированный мерч. Лидирующая ставка удерживается на счёте
+-- участника (users.held_coins) до закрытия аукциона или перебития ставки
+CREATE TABLE IF NOT EXISTS auctions (
+                                        id SERIAL PRIMARY KEY,
+                                        item_id INTEGER NOT NULL REFERENCES merch_items(id),
+                                        start_price INTEGER NOT NULL CHECK (start_price > 0),
+                                        min_increment INTEGER NOT NULL DEFAULT 1 CHECK (min_increment > 0),
+                                        starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+                                        ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+                                        status VARCHAR(20) NOT NULL DEFAULT 'open'
+                                            CHECK (status IN ('open', 'closed', 'cancelled')),
+                                        high_bidder_id INTEGER REFERENCES users(id),
+                                        high_bid INTEGER NOT NULL DEFAULT 0 CHECK (high_bid >= 0),
+                                        created_by INTEGER NOT NULL REFERENCES users(id),
+                                        closed_at TIMESTAMP WITH TIME ZONE,
+                                        created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+                                        CONSTRAINT auctions_window_check CHECK (ends_at > starts_at)
+);
+
+-- История ставок
+CREATE TABLE IF NOT EXISTS auction_bids (
+                                            id SERIAL PRIMARY KEY,
+                                            auction_id INTEGER NOT NULL REFERENCES auctions(id) ON DELETE CASCADE,
+                                            user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                            amount INTEGER NOT NULL CHECK (amount > 0),
+                                            created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_auctions_due ON auctions(ends_at) WHERE status = 'open';
+CREATE INDEX IF NOT EXISTS idx_auction_bids_auction ON auction_bids(auction_id, amount DESC);
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+DROP TABLE IF EXISTS raffle_winners;
+DROP TABLE IF EXISTS raffle_tickets;
+DROP TABLE IF EXISTS raffles;
+
+DELETE FROM transactions WHERE type = 'raffle_ticket';
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_type_check;
+ALTER TABLE transactions
+    ADD CONSTRAINT transactions_type_check CHECK (type IN ('transfer', 'purchase', 'grant', 'reward', 'expiry', 'refund', 'item_transfer', 'market_sale', 'market_fee'));
+
+COMMIT;
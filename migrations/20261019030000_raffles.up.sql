@@ -0,0 +1,44 @@
+BEGIN;
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_type_check;
+ALTER TABLE transactions
+    ADD CONSTRAINT transactions_type_check CHECK (type IN ('transfer', 'purchase', 'grant', 'reward', 'expiry', 'refund', 'item_transfer', 'market_sale', 'market_fee', 'raffle_ticket'));
+
+-- Розыгрыши: seed фиксируется при создании, его хэш публикуется сразу,
+-- а сам seed — после розыгрыша, чтобы результат можно было проверить
+CREATE TABLE IF NOT EXISTS raffles (
+                                       id SERIAL PRIMARY KEY,
+                                       item_id INTEGER NOT NULL REFERENCES merch_items(id),
+                                       ticket_price INTEGER NOT NULL CHECK (ticket_price > 0),
+                                       max_tickets_per_user INTEGER NOT NULL DEFAULT 0 CHECK (max_tickets_per_user >= 0),
+                                       prizes INTEGER NOT NULL DEFAULT 1 CHECK (prizes > 0),
+                                       draw_at TIMESTAMP WITH TIME ZONE NOT NULL,
+                                       status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'drawn')),
+                                       seed VARCHAR(64) NOT NULL,
+                                       seed_hash VARCHAR(64) NOT NULL,
+                                       drawn_at TIMESTAMP WITH TIME ZONE,
+                                       created_by INTEGER NOT NULL REFERENCES users(id),
+                                       created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Билеты: одна строка на билет, порядок id задаёт порядок для розыгрыша
+CREATE TABLE IF NOT EXISTS raffle_tickets (
+                                              id SERIAL PRIMARY KEY,
+                                              raffle_id INTEGER NOT NULL REFERENCES raffles(id) ON DELETE CASCADE,
+                                              user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                              created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Результаты розыгрыша
+CREATE TABLE IF NOT EXISTS raffle_winners (
+                                              raffle_id INTEGER NOT NULL REFERENCES raffles(id) ON DELETE CASCADE,
+                                              position INTEGER NOT NULL CHECK (position > 0),
+                                              ticket_id INTEGER NOT NULL REFERENCES raffle_tickets(id),
+                                              user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                              PRIMARY KEY (raffle_id, position)
+);
+
+CREATE INDEX IF NOT EXISTS idx_raffles_due ON raffles(draw_at) WHERE status = 'open';
+CREATE INDEX IF NOT EXISTS idx_raffle_tickets_raffle_user ON raffle_tickets(raffle_id, user_id);
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS wishlist_items;
+DROP TABLE IF EXISTS notifications;
+
+COMMIT;
@@ -0,0 +1,30 @@
+BEGIN;
+
+-- Уведомления внутри приложения. Используются как получатель по умолчанию
+-- для всех уведомлений сервиса
+CREATE TABLE IF NOT EXISTS notifications (
+                                             id SERIAL PRIMARY KEY,
+                                             user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                             message TEXT NOT NULL,
+                                             read_at TIMESTAMP WITH TIME ZONE,
+                                             created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Список желаний. last_price, in_stock и affordable хранят состояние товара
+-- на момент последней проверки, чтобы уведомлять только об изменениях
+CREATE TABLE IF NOT EXISTS wishlist_items (
+                                              id SERIAL PRIMARY KEY,
+                                              user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                              item_id INTEGER NOT NULL REFERENCES merch_items(id) ON DELETE CASCADE,
+                                              variant_id INTEGER REFERENCES merch_variants(id) ON DELETE CASCADE,
+                                              last_price INTEGER NOT NULL CHECK (last_price >= 0),
+                                              in_stock BOOLEAN NOT NULL,
+                                              affordable BOOLEAN NOT NULL,
+                                              created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user ON notifications(user_id, id DESC);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_wishlist_items_unique
+    ON wishlist_items(user_id, item_id, COALESCE(variant_id, 0));
+
+COMMIT;
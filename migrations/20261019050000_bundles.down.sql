@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS bundle_id;
+
+DROP TABLE IF EXISTS merch_bundle_items;
+DROP TABLE IF EXISTS merch_bundles;
+
+COMMIT;
@@ -0,0 +1,29 @@
+BEGIN;
+
+-- Наборы мерча, которые продаются как один товар по собственной цене
+CREATE TABLE IF NOT EXISTS merch_bundles (
+                                             id SERIAL PRIMARY KEY,
+                                             name VARCHAR(255) UNIQUE NOT NULL,
+                                             price INTEGER NOT NULL CHECK (price > 0),
+                                             description TEXT NOT NULL DEFAULT '',
+                                             created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+                                             created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Состав набора. variant_id фиксирует вариант; если он не задан, а у товара
+-- есть варианты, покупатель выбирает его сам
+CREATE TABLE IF NOT EXISTS merch_bundle_items (
+                                                  bundle_id INTEGER NOT NULL REFERENCES merch_bundles(id) ON DELETE CASCADE,
+                                                  item_id INTEGER NOT NULL REFERENCES merch_items(id),
+                                                  variant_id INTEGER REFERENCES merch_variants(id),
+                                                  quantity INTEGER NOT NULL DEFAULT 1 CHECK (quantity > 0)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_merch_bundle_items_unique
+    ON merch_bundle_items(bundle_id, item_id, COALESCE(variant_id, 0));
+
+-- Покупка набора записывается одной транзакцией со ссылкой на набор
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS bundle_id INTEGER REFERENCES merch_bundles(id);
+
+COMMIT;
@@ -0,0 +1,21 @@
+BEGIN;
+
+-- Возвращаем удержанные за предзаказы монеты
+UPDATE users u
+SET coins = u.coins + h.held,
+    held_coins = u.held_coins - h.held
+FROM (
+         SELECT user_id, SUM(price) AS held
+         FROM preorders
+         WHERE status = 'pending'
+         GROUP BY user_id
+     ) h
+WHERE h.user_id = u.id;
+
+DROP TABLE IF EXISTS preorders;
+
+ALTER TABLE merch_items
+    DROP COLUMN IF EXISTS preorder_limit,
+    DROP COLUMN IF EXISTS release_at;
+
+COMMIT;
@@ -0,0 +1,26 @@
+BEGIN;
+
+-- Дата выхода товара. До неё товар нельзя купить, только предзаказать,
+-- и не больше preorder_limit штук
+ALTER TABLE merch_items
+    ADD COLUMN IF NOT EXISTS release_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS preorder_limit INTEGER CHECK (preorder_limit > 0);
+
+-- Предзаказы. Цена удерживается на счёте (users.held_coins) до выхода
+-- товара или отмены предзаказа
+CREATE TABLE IF NOT EXISTS preorders (
+                                         id SERIAL PRIMARY KEY,
+                                         user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                         item_id INTEGER NOT NULL REFERENCES merch_items(id),
+                                         variant_id INTEGER REFERENCES merch_variants(id),
+                                         price INTEGER NOT NULL CHECK (price > 0),
+                                         status VARCHAR(20) NOT NULL DEFAULT 'pending'
+                                             CHECK (status IN ('pending', 'fulfilled', 'cancelled')),
+                                         resolved_at TIMESTAMP WITH TIME ZONE,
+                                         created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_preorders_pending ON preorders(item_id, id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_preorders_user ON preorders(user_id);
+
+COMMIT;
@@ -0,0 +1,18 @@
+BEGIN;
+
+-- Переводы названий и описаний товаров. Машинные переводы кэшируются здесь
+-- вместе с исходным текстом, чтобы заметить правку товара; переводы,
+-- написанные вручную (source = 'human'), машинными не перезаписываются
+CREATE TABLE IF NOT EXISTS merch_item_translations (
+                                                       item_id INTEGER NOT NULL REFERENCES merch_items(id) ON DELETE CASCADE,
+                                                       locale VARCHAR(8) NOT NULL,
+                                                       name VARCHAR(255) NOT NULL,
+                                                       description TEXT NOT NULL DEFAULT '',
+                                                       source VARCHAR(20) NOT NULL CHECK (source IN ('human', 'machine')),
+                                                       source_name VARCHAR(255) NOT NULL,
+                                                       source_description TEXT NOT NULL DEFAULT '',
+                                                       updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+                                                       PRIMARY KEY (item_id, locale)
+);
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+DELETE FROM orders WHERE price = 0;
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_price_check;
+ALTER TABLE orders
+    ADD CONSTRAINT orders_price_check CHECK (price > 0);
+
+COMMIT;
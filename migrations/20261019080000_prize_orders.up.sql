@@ -0,0 +1,9 @@
+BEGIN;
+
+-- Призы розыгрышей выдаются бесплатно, поэтому заказ на них создаётся
+-- с нулевой ценой; при отмене такого заказа возвращать нечего
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_price_check;
+ALTER TABLE orders
+    ADD CONSTRAINT orders_price_check CHECK (price >= 0);
+
+COMMIT;
@@ -0,0 +1,15 @@
+BEGIN;
+
+-- Монеты, которыми оплачен заказ, с датами лотов, из которых они взяты:
+-- при отмене заказа они возвращаются с тем же сроком сгорания
+CREATE TABLE IF NOT EXISTS order_coin_lots (
+                                               id SERIAL PRIMARY KEY,
+                                               order_id INTEGER NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+                                               amount INTEGER NOT NULL CHECK (amount > 0),
+                                               granted_at TIMESTAMP WITH TIME ZONE NOT NULL,
+                                               expires_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_coin_lots_order ON order_coin_lots(order_id);
+
+COMMIT;
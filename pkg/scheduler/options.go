@@ -0,0 +1,20 @@
+package scheduler
+
+import "time"
+
+// Option -.
+type Option func(*Scheduler)
+
+// Interval -.
+func Interval(interval time.Duration) Option {
+	return func(s *Scheduler) {
+		s.interval = interval
+	}
+}
+
+// OnError -.
+func OnError(fn func(error)) Option {
+	return func(s *Scheduler) {
+		s.onError = fn
+	}
+}
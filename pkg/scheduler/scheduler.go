@@ -0,0 +1,72 @@
+// Package scheduler implements periodic background jobs.
+package scheduler
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	_defaultInterval = time.Minute
+)
+
+// Job -.
+type Job func(ctx context.Context) error
+
+// Scheduler -.
+type Scheduler struct {
+	job      Job
+	interval time.Duration
+	onError  func(error)
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// New -.
+func New(job Job, opts ...Option) *Scheduler {
+	s := &Scheduler{
+		job:      job,
+		interval: _defaultInterval,
+		onError:  func(error) {},
+		done:     make(chan struct{}),
+	}
+
+	// Custom options
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.start()
+
+	return s
+}
+
+func (s *Scheduler) start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	go func() {
+		defer close(s.done)
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			if err := s.job(ctx); err != nil && ctx.Err() == nil {
+				s.onError(err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Shutdown stops the ticker and waits for a running job to return.
+func (s *Scheduler) Shutdown() {
+	s.cancel()
+	<-s.done
+}